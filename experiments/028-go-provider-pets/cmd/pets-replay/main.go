@@ -0,0 +1,74 @@
+// Command pets-replay re-runs an RPC recording made with PETS_RECORD_FILE
+// against this build of the provider and reports every call whose response
+// changed. Record with pets:deterministic=true so generated IDs and
+// timestamps repeat:
+//
+//	PETS_RECORD_FILE=rpc.jsonl pulumi up
+//	pets-replay rpc.jsonl
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/provider"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/rpclog"
+)
+
+func main() {
+	dataDir := flag.String("data-dir", "", "registry directory for the replay (default: a fresh temporary directory)")
+	verbose := flag.Bool("v", false, "print matching calls as well")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: pets-replay [flags] recording.jsonl\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Arg(0), *dataDir, *verbose); err != nil {
+		fmt.Fprintf(os.Stderr, "pets-replay: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(path, dataDir string, verbose bool) error {
+	recording, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer recording.Close()
+
+	if dataDir == "" {
+		if dataDir, err = os.MkdirTemp("", "pets-replay-"); err != nil {
+			return err
+		}
+		defer os.RemoveAll(dataDir)
+	}
+
+	calls, changed := 0, 0
+	err = rpclog.Replay(context.Background(), provider.New(), recording, dataDir, func(r rpclog.Result) {
+		calls++
+		if r.Matches() {
+			if verbose {
+				fmt.Printf("ok      line %d %s\n", r.Line, r.Method)
+			}
+			return
+		}
+		changed++
+		fmt.Printf("differs line %d %s\n  want: %s\n  got:  %s\n", r.Line, r.Method, r.Want, r.Got)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d of %d calls replayed with a different outcome\n", changed, calls)
+	if changed > 0 {
+		return fmt.Errorf("replay differs from %s", path)
+	}
+	return nil
+}
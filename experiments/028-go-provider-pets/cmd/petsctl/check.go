@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+)
+
+// relatedKinds are the records the provider keeps under the ID of a
+// resource of another kind, and removes when that resource is deleted; see
+// crudResource.related.
+var relatedKinds = map[string][]string{
+	"adoption-application": {"adoption"},
+	"approval":             {"dog", "visit"},
+	"dog-care":             {"dog"},
+	"dog-history":          {"dog"},
+	"geofence":             {"collar"},
+	"listing-closure":      {"listing"},
+	"ownership":            {"dog"},
+	"waitlist-match":       {"waitlist"},
+	"wellness-usage":       {"wellness"},
+}
+
+// problem is an inconsistency check found. Droppable ones are leftovers
+// nothing reads any more, which compact deletes.
+type problem struct {
+	Kind      string
+	ID        string
+	Version   int64
+	Reason    string
+	Droppable bool
+}
+
+func (p problem) String() string {
+	return fmt.Sprintf("%s %s: %s", p.Kind, p.ID, p.Reason)
+}
+
+// check looks for payloads that don't decode, records about dogs that
+// aren't registered, records kept for a resource that is gone, and
+// idempotency entries and name claims whose record or dog is gone
+func check(store backend.Store) ([]problem, error) {
+	records, err := store.List("")
+	if err != nil {
+		return nil, err
+	}
+	return inconsistencies(records), nil
+}
+
+func inconsistencies(records []backend.Record) []problem {
+	exists := map[string]bool{}
+	for _, rec := range records {
+		exists[backend.RecordKey(rec.Kind, rec.ID)] = true
+	}
+
+	var problems []problem
+	for _, rec := range records {
+		found := func(reason string, droppable bool) {
+			problems = append(problems, problem{Kind: rec.Kind, ID: rec.ID, Version: rec.Version, Reason: reason, Droppable: droppable})
+		}
+
+		var payload map[string]any
+		if err := json.Unmarshal(rec.Payload, &payload); err != nil {
+			found("payload doesn't decode: "+err.Error(), false)
+			continue
+		}
+		if owners, ok := relatedKinds[rec.Kind]; ok && !anyExists(exists, owners, rec.ID) {
+			found(fmt.Sprintf("no %s with this ID is left", owners[0]), true)
+		}
+		if rec.Kind == "idempotency" {
+			kind, _ := payload["kind"].(string)
+			id, _ := payload["id"].(string)
+			if !exists[backend.RecordKey(kind, id)] {
+				found(fmt.Sprintf("the %s %s it created is gone", kind, id), true)
+			}
+		}
+		if rec.Kind == "dog-name" {
+			if dogID, _ := payload["dogId"].(string); !exists[backend.RecordKey("dog", dogID)] {
+				found(fmt.Sprintf("dog %s holding the name is gone", dogID), true)
+			}
+		}
+		if dogID, _ := payload["DogID"].(string); dogID != "" && !exists[backend.RecordKey("dog", dogID)] {
+			found(fmt.Sprintf("dog %s is not registered", dogID), false)
+		}
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		return backend.RecordKey(problems[i].Kind, problems[i].ID) < backend.RecordKey(problems[j].Kind, problems[j].ID)
+	})
+	return problems
+}
+
+func anyExists(exists map[string]bool, kinds []string, id string) bool {
+	for _, kind := range kinds {
+		if exists[backend.RecordKey(kind, id)] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+)
+
+func TestInconsistencies(t *testing.T) {
+	rec := func(kind, id, payload string) backend.Record {
+		return backend.Record{Kind: kind, ID: id, Version: 1, Payload: []byte(payload)}
+	}
+	records := []backend.Record{
+		rec("dog", "dog-rex", `{"Name": "Rex"}`),
+		rec("dog-history", "dog-rex", `{"behaviorNotes": []}`),
+		rec("dog-history", "dog-gone", `{"behaviorNotes": []}`),
+		rec("walk", "walk-1", `{"DogID": "dog-rex"}`),
+		rec("walk", "walk-2", `{"DogID": "dog-gone"}`),
+		rec("idempotency", "abc", `{"kind": "walk", "id": "walk-1"}`),
+		rec("idempotency", "def", `{"kind": "walk", "id": "walk-9"}`),
+		rec("dog-name", "sam/rex", `{"dogId": "dog-rex"}`),
+		rec("dog-name", "sam/max", `{"dogId": "dog-max"}`),
+		rec("approval", "visit-1", `{}`),
+		rec("audit", "collar-1", `{"entries": []}`),
+		rec("insurance", "policy-1", `{"DogID": `),
+	}
+
+	var got []string
+	for _, p := range inconsistencies(records) {
+		got = append(got, fmt.Sprintf("%s %s droppable=%v", p.Kind, p.ID, p.Droppable))
+	}
+	want := []string{
+		"approval visit-1 droppable=true",
+		"dog-history dog-gone droppable=true",
+		"dog-name sam/max droppable=true",
+		"idempotency def droppable=true",
+		"insurance policy-1 droppable=false",
+		"walk walk-2 droppable=false",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("problems = %q, want %q", got, want)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	store := backend.NewFileStore(filepath.Join(t.TempDir(), "registry.json"))
+	defer store.Close()
+	for _, rec := range []backend.Record{
+		{Kind: "dog", ID: "dog-rex", Payload: []byte(`{"Name": "Rex"}`)},
+		{Kind: "ownership", ID: "dog-gone", Payload: []byte(`{"transfers": []}`)},
+		{Kind: "walk", ID: "walk-2", Payload: []byte(`{"DogID": "dog-gone"}`)},
+	} {
+		if _, err := store.Put(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := run(&out, store, "compact", nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "dropped 1 records; 1 problems need a look") {
+		t.Errorf("compact said %q", out.String())
+	}
+	if _, err := store.Get("ownership", "dog-gone"); err == nil {
+		t.Error("orphaned ownership record survived compact")
+	}
+
+	out.Reset()
+	if err := run(&out, store, "check", nil); err == nil || !strings.Contains(out.String(), "walk walk-2: dog dog-gone is not registered") {
+		t.Errorf("check = %v, said %q", err, out.String())
+	}
+	if err := run(&out, store, "delete", []string{"walk", "walk-2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(&out, store, "check", nil); err != nil {
+		t.Errorf("check after repair = %v", err)
+	}
+	if err := run(&out, store, "get", []string{"dog"}); err != errUsage {
+		t.Errorf("get without an id = %v, want a usage error", err)
+	}
+}
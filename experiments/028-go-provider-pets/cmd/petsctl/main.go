@@ -0,0 +1,156 @@
+// Command petsctl inspects and repairs the pets registry directly, without
+// going through a Pulumi program. It opens the same registry the provider
+// would: the data directory from -data-dir or PETS_DATA_DIR, decrypted with
+// -key or PETS_ENCRYPTION_KEY when the records are encrypted.
+//
+//	petsctl list [kind]      list records, optionally of one kind
+//	petsctl get kind id      print a record's payload
+//	petsctl delete kind id   delete a record
+//	petsctl check            report records that are inconsistent
+//	petsctl compact          drop the records check finds safe to drop
+//
+// Stop any running deployment first; petsctl doesn't take part in the
+// provider's locking.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+func main() {
+	dataDir := flag.String("data-dir", "", "registry directory (default: PETS_DATA_DIR, else ~/.pulumi-pets)")
+	key := flag.String("key", os.Getenv("PETS_ENCRYPTION_KEY"), "encryption key of the registry (default: PETS_ENCRYPTION_KEY)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: petsctl [flags] list [kind] | get kind id | delete kind id | check | compact\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var dir *string
+	if *dataDir != "" {
+		dir = dataDir
+	}
+	store, err := registry.OpenRegistry(dir, *key)
+	if err == nil {
+		err = run(os.Stdout, store, flag.Arg(0), flag.Args()[1:])
+		if cerr := store.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if errors.Is(err, errUsage) {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "petsctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+var errUsage = errors.New("usage")
+
+func run(out io.Writer, store backend.Store, command string, args []string) error {
+	switch {
+	case command == "list" && len(args) <= 1:
+		kind := ""
+		if len(args) == 1 {
+			kind = args[0]
+		}
+		return list(out, store, kind)
+	case command == "get" && len(args) == 2:
+		return get(out, store, args[0], args[1])
+	case command == "delete" && len(args) == 2:
+		return remove(out, store, args[0], args[1])
+	case command == "check" && len(args) == 0:
+		problems, err := check(store)
+		if err != nil {
+			return err
+		}
+		for _, problem := range problems {
+			fmt.Fprintln(out, problem)
+		}
+		fmt.Fprintf(out, "%d problems\n", len(problems))
+		if len(problems) > 0 {
+			return errors.New("registry is inconsistent")
+		}
+		return nil
+	case command == "compact" && len(args) == 0:
+		return compact(out, store)
+	}
+	return errUsage
+}
+
+func list(out io.Writer, store backend.Store, kind string) error {
+	records, err := store.List(kind)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tID\tVERSION\tUPDATED")
+	for _, rec := range records {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", rec.Kind, rec.ID, rec.Version, rec.Updated)
+	}
+	return w.Flush()
+}
+
+func get(out io.Writer, store backend.Store, kind, id string) error {
+	rec, err := store.Get(kind, id)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", kind, id, err)
+	}
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, rec.Payload, "", "  "); err != nil {
+		// Show what is there, even if it doesn't decode
+		pretty.Reset()
+		pretty.Write(rec.Payload)
+	}
+	fmt.Fprintf(out, "# %s %s version %d, updated %s\n%s\n", rec.Kind, rec.ID, rec.Version, rec.Updated, pretty.String())
+	return nil
+}
+
+// remove deletes one record. Records kept alongside it, such as a dog's
+// history, stay; check reports them and compact drops them.
+func remove(out io.Writer, store backend.Store, kind, id string) error {
+	rec, err := store.Get(kind, id)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", kind, id, err)
+	}
+	if err := store.Delete(kind, id, rec.Version); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "deleted %s %s\n", kind, id)
+	return nil
+}
+
+func compact(out io.Writer, store backend.Store) error {
+	problems, err := check(store)
+	if err != nil {
+		return err
+	}
+	dropped := 0
+	for _, problem := range problems {
+		if !problem.Droppable {
+			continue
+		}
+		if err := store.Delete(problem.Kind, problem.ID, problem.Version); err != nil {
+			return err
+		}
+		dropped++
+	}
+	fmt.Fprintf(out, "dropped %d records; %d problems need a look\n", dropped, len(problems)-dropped)
+	return nil
+}
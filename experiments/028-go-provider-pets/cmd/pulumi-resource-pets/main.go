@@ -0,0 +1,136 @@
+// Command pulumi-resource-pets is the resource plugin for the pets provider.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/provider"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/restapi"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/rpclog"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/selftest"
+)
+
+// Build metadata, stamped by the Makefile through -ldflags -X
+var (
+	version = "0.1.0"
+	commit  = "unknown"
+	date    = "unknown"
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "--version" {
+		fmt.Printf("pulumi-resource-pets %s (commit %s, built %s)\n", version, commit, date)
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "self-test" {
+		os.Exit(selfTest())
+	}
+	if len(os.Args) > 1 && os.Args[1] == "serve-api" {
+		os.Exit(serveAPI(os.Args[2:]))
+	}
+
+	prov := provider.New()
+
+	// PETS_RECORD_FILE appends every resource RPC to a JSONL file that
+	// cmd/pets-replay can re-run against another build
+	if path := os.Getenv("PETS_RECORD_FILE"); path != "" {
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "pulumi-resource-pets: opening RPC recording: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		keepSecrets, _ := strconv.ParseBool(os.Getenv("PETS_RECORD_SECRETS"))
+		prov = rpclog.NewRecorder(file, keepSecrets).Wrap(prov)
+	}
+
+	// PETS_DEBUG logs every RPC from startup; pets:debugRpc=true turns the
+	// same logging on once the provider is configured
+	debug, _ := strconv.ParseBool(os.Getenv("PETS_DEBUG"))
+	prov = rpclog.NewDebugger(debug).Wrap(prov)
+
+	registry.StartHealthServer()
+	go registry.ShutdownOnSignal()
+	provider.Serve("pets", version, prov)
+}
+
+// selfTest runs a create, read, update and delete cycle for every resource
+// against the backend configured through the PETS_* environment, so a
+// configuration can be checked before the first `pulumi up`.
+func selfTest() int {
+	results, err := selftest.Run(context.Background(), provider.New(), resource.PropertyMap{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "self-test: %v\n", err)
+		return 1
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Printf("FAIL  %-16s %v\n", r.Resource, r.Err)
+			continue
+		}
+		fmt.Printf("ok    %-16s %s\n", r.Resource, strings.Join(r.Steps, ", "))
+	}
+	fmt.Printf("%d of %d resources passed\n", len(results)-failed, len(results))
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// serveAPI serves the registry configured through the PETS_* environment as
+// a read-only JSON API until interrupted. Clients authenticate with the
+// token in PETS_API_TOKEN.
+func serveAPI(args []string) int {
+	flags := flag.NewFlagSet("serve-api", flag.ExitOnError)
+	addr := flags.String("addr", "127.0.0.1:8091", "address to listen on")
+	dataDir := flags.String("data-dir", "", "registry directory (default: PETS_DATA_DIR, else ~/.pulumi-pets)")
+	flags.Parse(args)
+
+	token := os.Getenv("PETS_API_TOKEN")
+	if token == "" {
+		fmt.Fprintln(os.Stderr, "serve-api: set PETS_API_TOKEN to the token clients must present")
+		return 2
+	}
+	var dir *string
+	if *dataDir != "" {
+		dir = dataDir
+	}
+	store, err := registry.OpenRegistry(dir, os.Getenv("PETS_ENCRYPTION_KEY"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "serve-api: %v\n", err)
+		return 1
+	}
+	defer store.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	server := &http.Server{Addr: *addr, Handler: restapi.Handler(store, token), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+	fmt.Printf("serving the registry API on http://%s/v1/kinds\n", *addr)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "serve-api: %v\n", err)
+		return 1
+	}
+	return 0
+}
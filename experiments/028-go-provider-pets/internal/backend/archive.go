@@ -0,0 +1,156 @@
+package backend
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArchiveVersion is the archive format written by WriteArchive.
+const ArchiveVersion = 1
+
+// Manifest describes the contents of a registry archive.
+type Manifest struct {
+	Version  int    `json:"version"`
+	Created  string `json:"created"`
+	Label    string `json:"label,omitempty"`
+	Records  int    `json:"records"`
+	Checksum string `json:"checksum"`
+}
+
+// ArchiveChecksum is the checksum WriteArchive would record for records.
+func ArchiveChecksum(records []Record) string {
+	h := sha256.New()
+	for _, rec := range records {
+		h.Write([]byte(RecordKey(rec.Kind, rec.ID)))
+		h.Write(rec.Payload)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// WriteArchive writes records to a tar.gz archive at path and returns the
+// checksum recorded in its manifest.
+func WriteArchive(path, label string, records []Record) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+
+	checksum := ArchiveChecksum(records)
+	manifest, err := json.MarshalIndent(Manifest{
+		Version:  ArchiveVersion,
+		Created:  time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		Label:    label,
+		Records:  len(records),
+		Checksum: checksum,
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	if err := writeTarFile(tw, "manifest.json", manifest); err != nil {
+		return "", err
+	}
+	for _, rec := range records {
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return "", err
+		}
+		if err := writeTarFile(tw, fmt.Sprintf("records/%s/%s.json", rec.Kind, rec.ID), data); err != nil {
+			return "", err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+	return checksum, file.Close()
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte) error {
+	header := &tar.Header{Name: name, Mode: 0o600, Size: int64(len(data)), ModTime: time.Now()}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// ReadArchive loads an archive written by WriteArchive, verifying its
+// format version and checksum.
+func ReadArchive(path string) (Manifest, []Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Manifest{}, nil, err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return Manifest{}, nil, err
+	}
+	tr := tar.NewReader(gz)
+
+	var manifest *Manifest
+	var records []Record
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Manifest{}, nil, err
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return Manifest{}, nil, err
+		}
+		switch {
+		case header.Name == "manifest.json":
+			manifest = &Manifest{}
+			if err := json.Unmarshal(data, manifest); err != nil {
+				return Manifest{}, nil, fmt.Errorf("parsing manifest: %w", err)
+			}
+		case strings.HasPrefix(header.Name, "records/"):
+			var rec Record
+			if err := json.Unmarshal(data, &rec); err != nil {
+				return Manifest{}, nil, fmt.Errorf("parsing %s: %w", header.Name, err)
+			}
+			records = append(records, rec)
+		}
+	}
+
+	if manifest == nil {
+		return Manifest{}, nil, errors.New("not a pets registry backup: missing manifest")
+	}
+	if manifest.Version != ArchiveVersion {
+		return Manifest{}, nil, fmt.Errorf("unsupported backup format version %d", manifest.Version)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return RecordKey(records[i].Kind, records[i].ID) < RecordKey(records[j].Kind, records[j].ID)
+	})
+	if len(records) != manifest.Records || ArchiveChecksum(records) != manifest.Checksum {
+		return Manifest{}, nil, errors.New("backup is corrupt: checksum does not match manifest")
+	}
+	return *manifest, records, nil
+}
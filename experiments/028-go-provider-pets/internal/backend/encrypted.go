@@ -0,0 +1,189 @@
+package backend
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const sealedPrefix = "pets:aes-gcm:v1:"
+
+// EncryptedStore seals record payloads with AES-GCM before they reach the
+// underlying store. The first key encrypts; every key is tried for decryption,
+// which is what makes key rotation possible.
+type EncryptedStore struct {
+	inner Store
+	keyID string
+	aeads map[string]cipher.AEAD
+}
+
+// NewEncryptedStore wraps inner, encrypting with key and also accepting
+// payloads sealed with any of the previous keys.
+func NewEncryptedStore(inner Store, key string, previous ...string) (*EncryptedStore, error) {
+	s := &EncryptedStore{inner: inner, aeads: map[string]cipher.AEAD{}}
+	for i, k := range append([]string{key}, previous...) {
+		id, aead, err := newRecordCipher(k)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			s.keyID = id
+		}
+		s.aeads[id] = aead
+	}
+	return s, nil
+}
+
+// newRecordCipher derives an AES-256 key from a passphrase and returns it with
+// a short fingerprint that is stored alongside each sealed payload.
+func newRecordCipher(passphrase string) (string, cipher.AEAD, error) {
+	if passphrase == "" {
+		return "", nil, errors.New("encryption key must not be empty")
+	}
+	key := sha256.Sum256([]byte(passphrase))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return "", nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", nil, err
+	}
+	fingerprint := sha256.Sum256(key[:])
+	return hex.EncodeToString(fingerprint[:4]), aead, nil
+}
+
+func (s *EncryptedStore) seal(rec Record) (Record, error) {
+	aead := s.aeads[s.keyID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return Record{}, err
+	}
+	// Bind the ciphertext to its record so payloads can't be swapped around
+	aad := []byte(RecordKey(rec.Kind, rec.ID))
+	sealed := aead.Seal(nonce, nonce, rec.Payload, aad)
+	rec.Payload = []byte(sealedPrefix + s.keyID + ":" + base64.StdEncoding.EncodeToString(sealed))
+	return rec, nil
+}
+
+func (s *EncryptedStore) open(rec Record) (Record, error) {
+	payload := string(rec.Payload)
+	if !strings.HasPrefix(payload, sealedPrefix) {
+		// Written before encryption was enabled
+		return rec, nil
+	}
+	keyID, encoded, ok := strings.Cut(strings.TrimPrefix(payload, sealedPrefix), ":")
+	if !ok {
+		return Record{}, fmt.Errorf("%s %q: malformed encrypted payload", rec.Kind, rec.ID)
+	}
+	aead, ok := s.aeads[keyID]
+	if !ok {
+		return Record{}, fmt.Errorf("%s %q: encrypted with unknown key %s", rec.Kind, rec.ID, keyID)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return Record{}, fmt.Errorf("%s %q: malformed encrypted payload", rec.Kind, rec.ID)
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, []byte(RecordKey(rec.Kind, rec.ID)))
+	if err != nil {
+		return Record{}, fmt.Errorf("%s %q: decrypting payload: %w", rec.Kind, rec.ID, err)
+	}
+	rec.Payload = plain
+	return rec, nil
+}
+
+func (s *EncryptedStore) Get(kind, id string) (Record, error) {
+	rec, err := s.inner.Get(kind, id)
+	if err != nil {
+		return Record{}, err
+	}
+	return s.open(rec)
+}
+
+func (s *EncryptedStore) Put(rec Record) (int64, error) {
+	sealed, err := s.seal(rec)
+	if err != nil {
+		return 0, err
+	}
+	return s.inner.Put(sealed)
+}
+
+func (s *EncryptedStore) PutAll(recs []Record) ([]int64, error) {
+	sealed := make([]Record, len(recs))
+	for i, rec := range recs {
+		var err error
+		if sealed[i], err = s.seal(rec); err != nil {
+			return nil, err
+		}
+	}
+	return s.inner.PutAll(sealed)
+}
+
+func (s *EncryptedStore) Replace(recs []Record) ([]int64, error) {
+	sealed := make([]Record, len(recs))
+	for i, rec := range recs {
+		var err error
+		if sealed[i], err = s.seal(rec); err != nil {
+			return nil, err
+		}
+	}
+	return s.inner.Replace(sealed)
+}
+
+func (s *EncryptedStore) Delete(kind, id string, version int64) error {
+	return s.inner.Delete(kind, id, version)
+}
+
+func (s *EncryptedStore) List(kind string) ([]Record, error) {
+	records, err := s.inner.List(kind)
+	if err != nil {
+		return nil, err
+	}
+	for i, rec := range records {
+		if records[i], err = s.open(rec); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+func (s *EncryptedStore) ListPage(q Query) ([]Record, string, error) {
+	records, err := s.List(q.Kind)
+	if err != nil {
+		return nil, "", err
+	}
+	return runQuery(records, q)
+}
+
+func (s *EncryptedStore) Close() error {
+	return s.inner.Close()
+}
+
+// Rotate re-encrypts every record that is not sealed with the current key.
+func (s *EncryptedStore) Rotate() error {
+	records, err := s.inner.List("")
+	if err != nil {
+		return err
+	}
+	current := sealedPrefix + s.keyID + ":"
+	for _, rec := range records {
+		if strings.HasPrefix(string(rec.Payload), current) {
+			continue
+		}
+		plain, err := s.open(rec)
+		if err != nil {
+			return err
+		}
+		if _, err := s.Put(plain); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileStore keeps the whole registry in a single JSON document on disk.
+//
+// The document is cached in memory and only re-read when the file on disk
+// has been replaced by somebody else. Writes that arrive while another is
+// being flushed are applied to the cache and written together in the next
+// flush; each caller still returns only once its own change is on disk.
+// Reads may see a change whose flush is still in progress.
+type FileStore struct {
+	mu     sync.Mutex
+	path   string
+	closed bool
+
+	records map[string]Record // cached document, nil until loaded
+	info    os.FileInfo       // the file records was read from or written to
+	buf     bytes.Buffer      // reused between flushes
+
+	flushing bool    // a goroutine is writing batches out
+	writing  *commit // the batch being written
+	next     *commit // changes waiting for the next write
+}
+
+// commit is one batch of changes flushed with a single file write.
+type commit struct {
+	done chan struct{}
+	err  error
+}
+
+// NewFileStore returns a store backed by the JSON file at path, which is
+// created on first write.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// load returns the cached document, reading the file again when it changed
+// underneath the cache; the caller holds mu.
+func (f *FileStore) load() (map[string]Record, error) {
+	if f.closed {
+		return nil, ErrClosed
+	}
+
+	info, err := os.Stat(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		if f.records == nil || f.info != nil && !f.flushing {
+			f.records, f.info = map[string]Record{}, nil
+		}
+		return f.records, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Pending changes win over the file until they are flushed
+	if f.records != nil && (f.flushing || f.info != nil && os.SameFile(info, f.info) &&
+		info.ModTime().Equal(f.info.ModTime()) && info.Size() == f.info.Size()) {
+		return f.records, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+	records := map[string]Record{}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", f.path, err)
+	}
+	f.records, f.info = records, info
+	return records, nil
+}
+
+// commit waits until the changes just made to the cache are on disk. The
+// caller holds mu; it is released while waiting. The first writer to find
+// no flush running writes batches until none are left.
+func (f *FileStore) commit() error {
+	c := f.next
+	if c == nil {
+		c = &commit{done: make(chan struct{})}
+		f.next = c
+	}
+	if f.flushing {
+		f.mu.Unlock()
+		<-c.done
+		f.mu.Lock()
+		return c.err
+	}
+
+	f.flushing = true
+	for f.next != nil {
+		batch := f.next
+		f.next, f.writing = nil, batch
+		data, err := f.encode()
+		if err == nil {
+			f.mu.Unlock()
+			err = f.write(data)
+			f.mu.Lock()
+		}
+		if err == nil {
+			f.info, err = os.Stat(f.path)
+		}
+		if err != nil {
+			// The cache now holds changes that never reached the disk:
+			// drop it, and fail everything queued on top of them too
+			f.records, f.info = nil, nil
+			if f.next != nil {
+				f.next.err = err
+				close(f.next.done)
+				f.next = nil
+			}
+		}
+		batch.err = err
+		close(batch.done)
+	}
+	f.flushing, f.writing = false, nil
+	return c.err
+}
+
+// encode renders the cache into the reused buffer; the caller holds mu.
+func (f *FileStore) encode() ([]byte, error) {
+	f.buf.Reset()
+	enc := json.NewEncoder(&f.buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(f.records); err != nil {
+		return nil, err
+	}
+	// Copy out, so the buffer can be reused while this one is written
+	return bytes.Clone(bytes.TrimSuffix(f.buf.Bytes(), []byte("\n"))), nil
+}
+
+func (f *FileStore) write(data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o700); err != nil {
+		return err
+	}
+	// Write to a temp file first so a crash never leaves a truncated registry
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+func (f *FileStore) Get(kind, id string) (Record, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	records, err := f.load()
+	if err != nil {
+		return Record{}, err
+	}
+	rec, ok := records[RecordKey(kind, id)]
+	if !ok {
+		return Record{}, fmt.Errorf("%s %q: %w", kind, id, ErrNotFound)
+	}
+	return rec, nil
+}
+
+func (f *FileStore) Put(rec Record) (int64, error) {
+	versions, err := f.PutAll([]Record{rec})
+	if err != nil {
+		return 0, err
+	}
+	return versions[0], nil
+}
+
+func (f *FileStore) PutAll(recs []Record) ([]int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	records, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range recs {
+		current := records[RecordKey(rec.Kind, rec.ID)].Version
+		if err := checkVersion(rec.Kind, rec.ID, rec.Version, current); err != nil {
+			return nil, err
+		}
+	}
+
+	versions := make([]int64, len(recs))
+	updated := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	for i, rec := range recs {
+		rec.Version = records[RecordKey(rec.Kind, rec.ID)].Version + 1
+		rec.Updated = updated
+		records[RecordKey(rec.Kind, rec.ID)] = rec
+		versions[i] = rec.Version
+	}
+	if err := f.commit(); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (f *FileStore) Replace(recs []Record) ([]int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	records, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	replaced := make(map[string]Record, len(recs))
+	versions := make([]int64, len(recs))
+	updated := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	for i, rec := range recs {
+		// Versions keep counting up, so writers holding an old one conflict
+		rec.Version = records[RecordKey(rec.Kind, rec.ID)].Version + 1
+		rec.Updated = updated
+		replaced[RecordKey(rec.Kind, rec.ID)] = rec
+		versions[i] = rec.Version
+	}
+	f.records = replaced
+	if err := f.commit(); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (f *FileStore) Delete(kind, id string, version int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	records, err := f.load()
+	if err != nil {
+		return err
+	}
+	rec, ok := records[RecordKey(kind, id)]
+	if !ok {
+		return nil
+	}
+	if err := checkVersion(kind, id, version, rec.Version); err != nil {
+		return err
+	}
+	delete(records, RecordKey(kind, id))
+	return f.commit()
+}
+
+// Close takes the lock and waits for a flush in progress, so it returns
+// only once every accepted write has been renamed into place.
+func (f *FileStore) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	for f.flushing {
+		c := f.writing
+		if f.next != nil {
+			c = f.next
+		}
+		f.mu.Unlock()
+		<-c.done
+		f.mu.Lock()
+	}
+	f.records, f.info = nil, nil
+	return nil
+}
+
+func checkVersion(kind, id string, expected, current int64) error {
+	if expected == AnyVersion || expected == current {
+		return nil
+	}
+	return fmt.Errorf("%s %q: expected version %d but the registry has version %d: %w",
+		kind, id, expected, current, ErrConflict)
+}
+
+func (f *FileStore) List(kind string) ([]Record, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	records, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	var out []Record
+	for _, rec := range records {
+		if kind == "" || rec.Kind == kind {
+			out = append(out, rec)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return RecordKey(out[i].Kind, out[i].ID) < RecordKey(out[j].Kind, out[j].ID)
+	})
+	return out, nil
+}
+
+func (f *FileStore) ListPage(q Query) ([]Record, string, error) {
+	records, err := f.List(q.Kind)
+	if err != nil {
+		return nil, "", err
+	}
+	return runQuery(records, q)
+}
@@ -0,0 +1,172 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestFileStoreConcurrentPutsAreDurable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+	store := NewFileStore(path)
+
+	const writers = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := store.Put(Record{Kind: "dog", ID: fmt.Sprintf("dog-%d", i), Payload: []byte(`{}`)})
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A fresh store sees only what reached the disk
+	records, err := NewFileStore(path).List("dog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != writers {
+		t.Errorf("file holds %d records, want %d", len(records), writers)
+	}
+}
+
+func TestFileStoreRereadsReplacedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+	store := NewFileStore(path)
+	if _, err := store.Put(Record{Kind: "dog", ID: "rex", Payload: []byte(`{"name":"Rex"}`)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another provider process writes the same registry
+	other := NewFileStore(path)
+	if _, err := other.Put(Record{Kind: "dog", ID: "rex", Version: 1, Payload: []byte(`{"name":"Rex II"}`)}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := store.Get("dog", "rex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Version != 2 || string(rec.Payload) != `{"name":"Rex II"}` {
+		t.Errorf("cached store read version %d %s, want the other process's version 2", rec.Version, rec.Payload)
+	}
+}
+
+func TestFileStoreFailedWriteIsNotKept(t *testing.T) {
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "data")
+	if err := os.WriteFile(blocker, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// The registry's directory can't be created while a file is in the way
+	store := NewFileStore(filepath.Join(blocker, "registry.json"))
+
+	if _, err := store.Put(Record{Kind: "dog", ID: "rex"}); err == nil {
+		t.Fatal("put succeeded without a writable directory")
+	}
+	if _, err := store.Get("dog", "rex"); err == nil {
+		t.Error("the failed write is still visible")
+	}
+}
+
+func TestFileStoreCloseRejectsOperations(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "registry.json"))
+	if _, err := store.Put(Record{Kind: "dog", ID: "rex"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("dog", "rex"); err != ErrClosed {
+		t.Errorf("get after close = %v, want ErrClosed", err)
+	}
+}
+
+// seededStore returns a file store already holding n dogs
+func seededStore(b *testing.B, n int) *FileStore {
+	store := NewFileStore(filepath.Join(b.TempDir(), "registry.json"))
+	recs := make([]Record, n)
+	for i := range recs {
+		recs[i] = Record{Kind: "dog", ID: fmt.Sprintf("seed-%d", i), Payload: []byte(`{"name":"Seed","breed":"beagle"}`)}
+	}
+	if _, err := store.PutAll(recs); err != nil {
+		b.Fatal(err)
+	}
+	return store
+}
+
+// BenchmarkFileStorePut measures writes against a registry of a thousand
+// dogs, one at a time and from parallel callers as the engine issues them.
+// IDs wrap around so the registry keeps its size however large b.N gets.
+func BenchmarkFileStorePut(b *testing.B) {
+	payload := []byte(`{"name":"Rex","breed":"beagle","ownerName":"Sam"}`)
+
+	b.Run("sequential", func(b *testing.B) {
+		store := seededStore(b, 1000)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := store.Put(Record{Kind: "dog", ID: fmt.Sprintf("dog-%d", i%1000), Version: AnyVersion, Payload: payload}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("parallel", func(b *testing.B) {
+		store := seededStore(b, 1000)
+		var mu sync.Mutex
+		next := 0
+		// The engine runs many resource operations at once; match that
+		// even on machines with few cores
+		b.SetParallelism(16)
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				mu.Lock()
+				id := fmt.Sprintf("dog-%d", next%1000)
+				next++
+				mu.Unlock()
+				if _, err := store.Put(Record{Kind: "dog", ID: id, Version: AnyVersion, Payload: payload}); err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		})
+	})
+}
+
+// BenchmarkFileStoreUpdate measures read-modify-write updates, the shape of
+// a resource Update.
+func BenchmarkFileStoreUpdate(b *testing.B) {
+	store := seededStore(b, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rec, err := store.Get("dog", "seed-1")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := store.Put(rec); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFileStoreGet(b *testing.B) {
+	store := seededStore(b, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := store.Get("dog", "seed-500"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package backend
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type queryRow struct {
+	rec    Record
+	fields map[string]any
+}
+
+// runQuery filters, sorts and pages records in memory. Every backend uses it;
+// the encrypted store has to, since conditions need the decrypted payload.
+func runQuery(records []Record, q Query) ([]Record, string, error) {
+	after, err := decodeCursor(q.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var rows []queryRow
+	for _, rec := range records {
+		row := queryRow{rec: rec}
+		if err := json.Unmarshal(rec.Payload, &row.fields); err != nil {
+			return nil, "", fmt.Errorf("%s %q: %w", rec.Kind, rec.ID, err)
+		}
+		matched, err := matchesAll(row.fields, q.Where)
+		if err != nil {
+			return nil, "", err
+		}
+		if matched {
+			rows = append(rows, row)
+		}
+	}
+
+	less := func(a, b queryCursor) bool {
+		if c := compareValues(a.Sort, b.Sort); c != 0 {
+			return (c < 0) != q.Descending
+		}
+		return a.Key < b.Key
+	}
+	cursorOf := func(row queryRow) queryCursor {
+		c := queryCursor{Key: RecordKey(row.rec.Kind, row.rec.ID)}
+		if q.SortBy != "" {
+			c.Sort = row.fields[q.SortBy]
+		}
+		return c
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return less(cursorOf(rows[i]), cursorOf(rows[j]))
+	})
+
+	start := 0
+	if after != nil {
+		start = sort.Search(len(rows), func(i int) bool {
+			return less(*after, cursorOf(rows[i]))
+		})
+	}
+	rows = rows[start:]
+
+	next := ""
+	if q.Limit > 0 && len(rows) > q.Limit {
+		rows = rows[:q.Limit]
+		next = encodeCursor(cursorOf(rows[len(rows)-1]))
+	}
+	page := make([]Record, len(rows))
+	for i, row := range rows {
+		page[i] = row.rec
+	}
+	return page, next, nil
+}
+
+// matchesAll reports whether fields meet every condition. An unknown op is
+// an error rather than a match, so a typo can't select every record.
+func matchesAll(fields map[string]any, where []Condition) (bool, error) {
+	for _, cond := range where {
+		value := fields[cond.Field]
+		switch cond.Op {
+		case "eq":
+			if compareValues(value, cond.Value) != 0 {
+				return false, nil
+			}
+		case "contains":
+			items, _ := value.([]any)
+			found := false
+			for _, item := range items {
+				found = found || compareValues(item, cond.Value) == 0
+			}
+			if !found {
+				return false, nil
+			}
+		case "gte":
+			if value == nil || compareValues(value, cond.Value) < 0 {
+				return false, nil
+			}
+		case "lte":
+			if value == nil || compareValues(value, cond.Value) > 0 {
+				return false, nil
+			}
+		default:
+			return false, fmt.Errorf("unsupported condition op %q on %s", cond.Op, cond.Field)
+		}
+	}
+	return true, nil
+}
+
+// compareValues orders decoded JSON values: nulls first, numbers numerically,
+// everything else as case-insensitive strings.
+func compareValues(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	af, aNum := toFloat(a)
+	bf, bNum := toFloat(b)
+	if aNum && bNum {
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(strings.ToLower(fmt.Sprint(a)), strings.ToLower(fmt.Sprint(b)))
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// Cursors are opaque to callers; they encode the sort value and key of the
+// last record seen.
+type queryCursor struct {
+	Sort any    `json:"s,omitempty"`
+	Key  string `json:"k"`
+}
+
+func encodeCursor(c queryCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(token string) (*queryCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	var c queryCursor
+	if err == nil {
+		err = json.Unmarshal(data, &c)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w %q", ErrInvalidPageToken, token)
+	}
+	return &c, nil
+}
@@ -0,0 +1,45 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunQueryConditions(t *testing.T) {
+	records := []Record{
+		{Kind: "dog", ID: "rex", Payload: []byte(`{"name":"Rex","age":3,"tags":["calm"]}`)},
+		{Kind: "dog", ID: "fido", Payload: []byte(`{"name":"Fido","age":7,"tags":[]}`)},
+	}
+	tests := []struct {
+		name    string
+		where   []Condition
+		want    string
+		wantErr string
+	}{
+		{name: "eq", where: []Condition{{Field: "name", Op: "eq", Value: "rex"}}, want: "rex"},
+		{name: "contains", where: []Condition{{Field: "tags", Op: "contains", Value: "calm"}}, want: "rex"},
+		{name: "range", where: []Condition{{Field: "age", Op: "gte", Value: 5}, {Field: "age", Op: "lte", Value: 9}}, want: "fido"},
+		{name: "unknown op", where: []Condition{{Field: "name", Op: "equals", Value: "rex"}}, wantErr: `unsupported condition op "equals" on name`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, _, err := runQuery(records, Query{Kind: "dog", Where: tt.where})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			for _, rec := range page {
+				ids = append(ids, rec.ID)
+			}
+			if strings.Join(ids, ",") != tt.want {
+				t.Errorf("matched %q, want %s", ids, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package backend
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SimulatedStore reads through to another store but keeps every write in
+// memory, so a deployment behaves as usual without changing the shared
+// registry. The overlay is discarded when the provider exits.
+type SimulatedStore struct {
+	mu      sync.Mutex
+	inner   Store
+	written map[string]Record
+	deleted map[string]bool
+	closed  bool
+}
+
+// NewSimulatedStore layers an in-memory overlay over inner.
+func NewSimulatedStore(inner Store) *SimulatedStore {
+	return &SimulatedStore{
+		inner:   inner,
+		written: map[string]Record{},
+		deleted: map[string]bool{},
+	}
+}
+
+// get returns the record as the overlay sees it; the caller holds mu.
+func (s *SimulatedStore) get(kind, id string) (Record, error) {
+	if s.closed {
+		return Record{}, ErrClosed
+	}
+	key := RecordKey(kind, id)
+	if s.deleted[key] {
+		return Record{}, ErrNotFound
+	}
+	if rec, ok := s.written[key]; ok {
+		return rec, nil
+	}
+	return s.inner.Get(kind, id)
+}
+
+func (s *SimulatedStore) Get(kind, id string) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.get(kind, id)
+}
+
+func (s *SimulatedStore) Put(rec Record) (int64, error) {
+	versions, err := s.PutAll([]Record{rec})
+	if err != nil {
+		return 0, err
+	}
+	return versions[0], nil
+}
+
+func (s *SimulatedStore) PutAll(recs []Record) ([]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := make([]int64, len(recs))
+	for i, rec := range recs {
+		existing, err := s.get(rec.Kind, rec.ID)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		if err := checkVersion(rec.Kind, rec.ID, rec.Version, existing.Version); err != nil {
+			return nil, err
+		}
+		current[i] = existing.Version
+	}
+
+	versions := make([]int64, len(recs))
+	updated := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	for i, rec := range recs {
+		key := RecordKey(rec.Kind, rec.ID)
+		rec.Version = current[i] + 1
+		rec.Updated = updated
+		s.written[key] = rec
+		delete(s.deleted, key)
+		versions[i] = rec.Version
+	}
+	return versions, nil
+}
+
+// Replace hides every record of the store underneath behind the overlay.
+func (s *SimulatedStore) Replace(recs []Record) ([]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, ErrClosed
+	}
+	current := make([]int64, len(recs))
+	for i, rec := range recs {
+		existing, err := s.get(rec.Kind, rec.ID)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		current[i] = existing.Version
+	}
+	inner, err := s.inner.List("")
+	if err != nil {
+		return nil, err
+	}
+
+	s.written = map[string]Record{}
+	for _, rec := range inner {
+		s.deleted[RecordKey(rec.Kind, rec.ID)] = true
+	}
+	versions := make([]int64, len(recs))
+	updated := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	for i, rec := range recs {
+		key := RecordKey(rec.Kind, rec.ID)
+		rec.Version = current[i] + 1
+		rec.Updated = updated
+		s.written[key] = rec
+		delete(s.deleted, key)
+		versions[i] = rec.Version
+	}
+	return versions, nil
+}
+
+func (s *SimulatedStore) Delete(kind, id string, version int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.get(kind, id)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(kind, id, version, rec.Version); err != nil {
+		return err
+	}
+	key := RecordKey(kind, id)
+	delete(s.written, key)
+	s.deleted[key] = true
+	return nil
+}
+
+func (s *SimulatedStore) List(kind string) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, ErrClosed
+	}
+	records, err := s.inner.List(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Record
+	for _, rec := range records {
+		key := RecordKey(rec.Kind, rec.ID)
+		if _, overlaid := s.written[key]; !overlaid && !s.deleted[key] {
+			out = append(out, rec)
+		}
+	}
+	for _, rec := range s.written {
+		if kind == "" || rec.Kind == kind {
+			out = append(out, rec)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return RecordKey(out[i].Kind, out[i].ID) < RecordKey(out[j].Kind, out[j].ID)
+	})
+	return out, nil
+}
+
+func (s *SimulatedStore) ListPage(q Query) ([]Record, string, error) {
+	records, err := s.List(q.Kind)
+	if err != nil {
+		return nil, "", err
+	}
+	return runQuery(records, q)
+}
+
+// Close drops the overlay and closes the store underneath.
+func (s *SimulatedStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	s.written, s.deleted = nil, nil
+	return s.inner.Close()
+}
@@ -0,0 +1,55 @@
+package backend
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestSimulatedStoreLeavesInnerUntouched(t *testing.T) {
+	inner := NewFileStore(filepath.Join(t.TempDir(), "registry.json"))
+	if _, err := inner.Put(Record{Kind: "dog", ID: "rex", Payload: []byte(`{"name":"Rex"}`)}); err != nil {
+		t.Fatal(err)
+	}
+	sim := NewSimulatedStore(inner)
+
+	// Writes, updates and deletes all land in the overlay
+	if _, err := sim.Put(Record{Kind: "dog", ID: "fido", Payload: []byte(`{"name":"Fido"}`)}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	version, err := sim.Put(Record{Kind: "dog", ID: "rex", Version: 1, Payload: []byte(`{"name":"Rex II"}`)})
+	if err != nil || version != 2 {
+		t.Fatalf("update = %d, %v; want version 2", version, err)
+	}
+	if err := sim.Delete("dog", "fido", 1); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := sim.Put(Record{Kind: "dog", ID: "rex", Version: 1}); !errors.Is(err, ErrConflict) {
+		t.Errorf("stale update = %v, want ErrConflict", err)
+	}
+
+	tests := []struct {
+		name  string
+		store Store
+		want  map[string]string
+	}{
+		{name: "simulated view", store: sim, want: map[string]string{"rex": `{"name":"Rex II"}`}},
+		{name: "shared registry", store: inner, want: map[string]string{"rex": `{"name":"Rex"}`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := tt.store.List("dog")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != len(tt.want) {
+				t.Fatalf("listed %d records, want %d", len(records), len(tt.want))
+			}
+			for _, rec := range records {
+				if string(rec.Payload) != tt.want[rec.ID] {
+					t.Errorf("%s = %s, want %s", rec.ID, rec.Payload, tt.want[rec.ID])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Package backend implements the storage behind the pets registry.
+package backend
+
+import (
+	"errors"
+)
+
+var (
+	// ErrClosed is returned by a store that has been closed.
+	ErrClosed = errors.New("registry is closed")
+
+	// ErrNotFound is returned when a record does not exist in the registry.
+	ErrNotFound = errors.New("record not found")
+	// ErrConflict is returned when a write names a version that is no longer
+	// current, i.e. somebody else changed the record in the meantime.
+	ErrConflict = errors.New("version conflict")
+	// ErrInvalidPageToken is returned by ListPage for a cursor it didn't
+	// hand out.
+	ErrInvalidPageToken = errors.New("invalid page token")
+)
+
+// AnyVersion skips the optimistic concurrency check on Put and Delete. Only
+// registry maintenance (restore, rollback) should use it.
+const AnyVersion int64 = -1
+
+// Record is a single resource persisted in the registry.
+type Record struct {
+	Kind    string `json:"kind"`
+	ID      string `json:"id"`
+	Version int64  `json:"version"`
+	Payload []byte `json:"payload"`
+	Updated string `json:"updated"`
+}
+
+// RecordKey is the unique key of a record across all kinds.
+func RecordKey(kind, id string) string {
+	return kind + "/" + id
+}
+
+// Store is the storage interface every registry backend implements.
+//
+// Put and Delete take the version the caller last saw (0 for a record that
+// should not exist yet) and fail with ErrConflict if it is stale. Put returns
+// the version the record was stored under.
+type Store interface {
+	Get(kind, id string) (Record, error)
+	Put(rec Record) (int64, error)
+	Delete(kind, id string, version int64) error
+	List(kind string) ([]Record, error)
+
+	// PutAll writes several records as one transaction: if any version check
+	// fails nothing is written.
+	PutAll(recs []Record) ([]int64, error)
+
+	// Replace swaps the whole registry for recs in one transaction: records
+	// not among them are removed, and nothing changes if the write fails.
+	Replace(recs []Record) ([]int64, error)
+
+	// ListPage returns one page of the records matching q, plus the cursor
+	// for the next page ("" once the listing is exhausted).
+	ListPage(q Query) ([]Record, string, error)
+
+	// Close waits for any write in progress and rejects further operations.
+	Close() error
+}
+
+// Query selects, orders and pages records. Conditions and sort fields refer
+// to top-level properties of the decoded payload.
+type Query struct {
+	Kind       string
+	Where      []Condition
+	SortBy     string
+	Descending bool
+	Cursor     string
+	Limit      int
+}
+
+type Condition struct {
+	Field string
+	Op    string // eq, contains, gte, lte
+	Value any
+}
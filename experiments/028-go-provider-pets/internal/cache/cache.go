@@ -0,0 +1,149 @@
+// Package cache is a small in-process TTL cache for lookups that return the
+// same answer for a while, such as breed metadata or third-party API
+// responses, so repeated previews don't fetch identical data again.
+//
+// Every cache is registered under a name and counts its hits, misses and
+// evictions; Snapshot reports them for all caches at once.
+package cache
+
+import (
+	"container/list"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Cache maps keys to values for up to ttl, holding at most size entries and
+// evicting the least recently used one to make room.
+type Cache[K comparable, V any] struct {
+	name string
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[K]*list.Element
+	order   *list.List // front is the most recently used
+	stats   Stats
+}
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+// Stats are a cache's counters since the provider started
+type Stats struct {
+	Name      string `pulumi:"name"`
+	Entries   int    `pulumi:"entries"`
+	Hits      int64  `pulumi:"hits"`
+	Misses    int64  `pulumi:"misses"`
+	Evictions int64  `pulumi:"evictions"`
+}
+
+var (
+	registryMu sync.Mutex
+	caches     = map[string]interface{ Stats() Stats }{}
+)
+
+// New creates and registers a cache. Names must be unique; a second cache
+// under the same name replaces the first in Snapshot.
+func New[K comparable, V any](name string, size int, ttl time.Duration) *Cache[K, V] {
+	if size < 1 {
+		panic("cache: size must be at least 1")
+	}
+	c := &Cache[K, V]{
+		name:    name,
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[K]*list.Element{},
+		order:   list.New(),
+	}
+	registryMu.Lock()
+	caches[name] = c
+	registryMu.Unlock()
+	return c
+}
+
+// Get returns the value cached under key, if it is there and still fresh.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*entry[K, V])
+		if c.now().Before(e.expires) {
+			c.order.MoveToFront(el)
+			c.stats.Hits++
+			return e.value, true
+		}
+		c.remove(el)
+	}
+	c.stats.Misses++
+	var zero V
+	return zero, false
+}
+
+// Put caches value under key for the cache's TTL.
+func (c *Cache[K, V]) Put(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.now().Add(c.ttl)
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*entry[K, V])
+		e.value, e.expires = value, expires
+		c.order.MoveToFront(el)
+		return
+	}
+	for c.order.Len() >= c.size {
+		c.remove(c.order.Back())
+		c.stats.Evictions++
+	}
+	c.entries[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expires: expires})
+}
+
+// GetOrLoad returns the cached value for key, or calls load and caches what
+// it returns. Errors are not cached, so the next call tries again.
+func (c *Cache[K, V]) GetOrLoad(key K, load func() (V, error)) (V, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	c.Put(key, value)
+	return value, nil
+}
+
+// remove drops an entry; the caller holds mu.
+func (c *Cache[K, V]) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*entry[K, V]).key)
+}
+
+// Stats returns the cache's counters
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Name, stats.Entries = c.name, c.order.Len()
+	return stats
+}
+
+// Snapshot returns the counters of every cache, ordered by name
+func Snapshot() []Stats {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	out := make([]Stats, 0, len(caches))
+	for _, c := range caches {
+		out = append(out, c.Stats())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// clock is a settable time source for expiry tests
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func newTestCache(t *testing.T, size int, ttl time.Duration) (*Cache[string, int], *clock) {
+	t.Helper()
+	clk := &clock{t: time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)}
+	c := New[string, int](t.Name(), size, ttl)
+	c.now = clk.now
+	return c, clk
+}
+
+func TestGetPut(t *testing.T) {
+	c, _ := newTestCache(t, 2, time.Minute)
+
+	if _, ok := c.Get("rex"); ok {
+		t.Fatal("empty cache returned a value")
+	}
+	c.Put("rex", 1)
+	if v, ok := c.Get("rex"); !ok || v != 1 {
+		t.Fatalf("Get = %d, %v, want 1, true", v, ok)
+	}
+	c.Put("rex", 2)
+	if v, _ := c.Get("rex"); v != 2 {
+		t.Errorf("Get after overwrite = %d, want 2", v)
+	}
+
+	stats := c.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("stats = %+v, want 2 hits, 1 miss, 1 entry", stats)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	c, clk := newTestCache(t, 2, time.Minute)
+	c.Put("rex", 1)
+
+	tests := []struct {
+		name    string
+		advance time.Duration
+		wantOK  bool
+	}{
+		{name: "fresh", advance: 30 * time.Second, wantOK: true},
+		{name: "at the TTL", advance: 30 * time.Second, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk.t = clk.t.Add(tt.advance)
+			if _, ok := c.Get("rex"); ok != tt.wantOK {
+				t.Errorf("Get ok = %v, want %v", ok, tt.wantOK)
+			}
+		})
+	}
+	if stats := c.Stats(); stats.Entries != 0 {
+		t.Errorf("expired entry still held: %+v", stats)
+	}
+}
+
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+	c, _ := newTestCache(t, 2, time.Minute)
+	c.Put("rex", 1)
+	c.Put("fido", 2)
+	c.Get("rex") // fido is now the oldest
+	c.Put("max", 3)
+
+	if _, ok := c.Get("fido"); ok {
+		t.Error("fido should have been evicted")
+	}
+	for _, key := range []string{"rex", "max"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("%s was evicted", key)
+		}
+	}
+	if stats := c.Stats(); stats.Evictions != 1 || stats.Entries != 2 {
+		t.Errorf("stats = %+v, want 1 eviction, 2 entries", stats)
+	}
+}
+
+func TestGetOrLoad(t *testing.T) {
+	c, _ := newTestCache(t, 2, time.Minute)
+	calls := 0
+	load := func() (int, error) {
+		calls++
+		return 7, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		if v, err := c.GetOrLoad("rex", load); err != nil || v != 7 {
+			t.Fatalf("GetOrLoad = %d, %v, want 7", v, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("load called %d times, want 1", calls)
+	}
+
+	boom := errors.New("boom")
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetOrLoad("fido", func() (int, error) { calls++; return 0, boom }); !errors.Is(err, boom) {
+			t.Fatalf("GetOrLoad err = %v, want boom", err)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("failed loads were cached: %d calls, want 3", calls)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	a, _ := newTestCache(t, 1, time.Minute)
+	a.Put("rex", 1)
+	a.Get("rex")
+
+	for _, stats := range Snapshot() {
+		if stats.Name == t.Name() {
+			if stats.Hits != 1 || stats.Entries != 1 {
+				t.Errorf("snapshot = %+v, want 1 hit, 1 entry", stats)
+			}
+			return
+		}
+	}
+	t.Errorf("%s missing from snapshot", t.Name())
+}
@@ -0,0 +1,110 @@
+// Package currency converts amounts between currencies. The provider keeps
+// every price in US dollars; this package turns them into other currencies
+// with a table of rates, either the snapshot embedded here or one fetched
+// from a rates service.
+package currency
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Base is the currency the provider's prices are in
+const Base = "USD"
+
+// Rates are the units of each currency one unit of Base buys
+type Rates struct {
+	Base   string             `json:"base"`
+	Date   string             `json:"date"`
+	Rates  map[string]float64 `json:"rates"`
+	Source string             `json:"-"` // embedded or the URL the rates came from
+}
+
+//go:embed rates.json
+var fallbackJSON []byte
+
+var fallback = mustLoadFallback()
+
+func mustLoadFallback() Rates {
+	var rates Rates
+	if err := json.Unmarshal(fallbackJSON, &rates); err != nil {
+		panic(fmt.Sprintf("embedded exchange rates: %v", err))
+	}
+	rates.Source = "embedded"
+	return rates
+}
+
+// Fallback returns the embedded snapshot of rates
+func Fallback() Rates {
+	return fallback
+}
+
+// Convert converts amount from one currency to another. Codes are ISO 4217
+// and case-insensitive.
+func (r Rates) Convert(amount float64, from, to string) (float64, error) {
+	rate, err := r.Rate(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate, nil
+}
+
+// Rate is what one unit of from is worth in to
+func (r Rates) Rate(from, to string) (float64, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	fromRate, ok := r.per(from)
+	if !ok {
+		return 0, fmt.Errorf("no exchange rate for %q", from)
+	}
+	toRate, ok := r.per(to)
+	if !ok {
+		return 0, fmt.Errorf("no exchange rate for %q", to)
+	}
+	return toRate / fromRate, nil
+}
+
+func (r Rates) per(code string) (float64, bool) {
+	if code == strings.ToUpper(r.Base) {
+		return 1, true
+	}
+	rate, ok := r.Rates[code]
+	return rate, ok && rate > 0
+}
+
+// Known reports whether there is a rate for code
+func (r Rates) Known(code string) bool {
+	_, ok := r.per(strings.ToUpper(code))
+	return ok
+}
+
+// Fetch loads rates from a service answering in the common
+// {"base": ..., "date": ..., "rates": {...}} shape, such as Frankfurter
+// or exchangerate.host
+func Fetch(ctx context.Context, client *http.Client, url string) (Rates, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Rates{}, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Rates{}, fmt.Errorf("fetching exchange rates: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Rates{}, fmt.Errorf("fetching exchange rates: unexpected status %s", resp.Status)
+	}
+	var rates Rates
+	if err := json.NewDecoder(resp.Body).Decode(&rates); err != nil {
+		return Rates{}, fmt.Errorf("fetching exchange rates: %w", err)
+	}
+	if rates.Base == "" || len(rates.Rates) == 0 {
+		return Rates{}, fmt.Errorf("fetching exchange rates: the response has no base or rates")
+	}
+	rates.Base = strings.ToUpper(rates.Base)
+	rates.Source = url
+	return rates, nil
+}
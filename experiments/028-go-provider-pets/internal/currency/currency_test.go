@@ -0,0 +1,87 @@
+package currency
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	rates := Rates{Base: "USD", Rates: map[string]float64{"EUR": 0.9, "JPY": 150, "GBP": 0.75}}
+	tests := []struct {
+		name     string
+		amount   float64
+		from, to string
+		want     float64
+		err      bool
+	}{
+		{name: "from the base", amount: 100, from: "USD", to: "EUR", want: 90},
+		{name: "to the base", amount: 90, from: "EUR", to: "USD", want: 100},
+		{name: "between two others", amount: 75, from: "GBP", to: "JPY", want: 15000},
+		{name: "same currency", amount: 42, from: "JPY", to: "JPY", want: 42},
+		{name: "lower case", amount: 100, from: "usd", to: "eur", want: 90},
+		{name: "unknown", amount: 1, from: "USD", to: "XYZ", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rates.Convert(tt.amount, tt.from, tt.to)
+			if (err != nil) != tt.err {
+				t.Fatalf("err = %v, want error %v", err, tt.err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Convert = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFallback(t *testing.T) {
+	rates := Fallback()
+	if rates.Base != Base || rates.Source != "embedded" || rates.Date == "" {
+		t.Fatalf("fallback = %+v", rates)
+	}
+	for _, code := range []string{"USD", "EUR", "GBP", "CAD", "JPY"} {
+		if !rates.Known(code) {
+			t.Errorf("no embedded rate for %s", code)
+		}
+	}
+}
+
+func TestFetch(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		err    bool
+	}{
+		{name: "frankfurter", status: http.StatusOK, body: `{"amount":1.0,"base":"usd","date":"2026-10-15","rates":{"EUR":0.91}}`},
+		{name: "no rates", status: http.StatusOK, body: `{"base":"USD","rates":{}}`, err: true},
+		{name: "not json", status: http.StatusOK, body: `<html>`, err: true},
+		{name: "server error", status: http.StatusBadGateway, body: `{}`, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			rates, err := Fetch(context.Background(), server.Client(), server.URL)
+			if (err != nil) != tt.err {
+				t.Fatalf("err = %v, want error %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if rates.Base != "USD" || rates.Date != "2026-10-15" || rates.Source != server.URL {
+				t.Errorf("rates = %+v", rates)
+			}
+			if got, _ := rates.Convert(100, "USD", "EUR"); math.Abs(got-91) > 1e-9 {
+				t.Errorf("100 USD = %g EUR, want 91", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+// Package dashboard serves a read-only web view of the pets registry: the
+// dogs with their happiness over the last week, recent walks and upcoming
+// vet visits. The provider runs it when the dashboard config is set.
+package dashboard
+
+import (
+	"embed"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+const (
+	trendDays  = 7
+	listLength = 10 // walks and visits shown
+)
+
+//go:embed dashboard.html
+var files embed.FS
+
+var page = template.Must(template.New("dashboard.html").Funcs(template.FuncMap{"sparkline": sparkline}).ParseFS(files, "dashboard.html"))
+
+// Summary is everything the dashboard shows, also served as JSON from
+// /api/summary
+type Summary struct {
+	GeneratedAt    string  `json:"generatedAt"`
+	Dogs           []Dog   `json:"dogs"`
+	RecentWalks    []Walk  `json:"recentWalks"`    // newest first
+	UpcomingVisits []Visit `json:"upcomingVisits"` // soonest first
+}
+
+type Dog struct {
+	ID        string             `json:"id"`
+	Name      string             `json:"name"`
+	Breed     resources.DogBreed `json:"breed"`
+	Owner     string             `json:"owner"`
+	Happiness int                `json:"happiness"`
+	Trend     []int              `json:"trend"` // happiness on each of the last days, oldest first
+	LastWalk  string             `json:"lastWalk"`
+}
+
+type Walk struct {
+	DogName   string  `json:"dogName"`
+	Date      string  `json:"date"`
+	Minutes   int     `json:"minutes"`
+	Miles     float64 `json:"miles"`
+	Enjoyment string  `json:"enjoyment"`
+}
+
+type Visit struct {
+	DogName    string              `json:"dogName"`
+	Due        string              `json:"due"`
+	LastVisit  resources.VisitType `json:"lastVisit"`
+	ClinicName string              `json:"clinicName"`
+}
+
+// Handler serves the dashboard page on / and its data on /api/summary.
+// Every request reads the registry afresh.
+func Handler(store backend.Store, mood registry.MoodCurve, now func() time.Time) http.Handler {
+	mux := http.NewServeMux()
+	load := func(w http.ResponseWriter) (Summary, bool) {
+		summary, err := summarize(store, mood, now())
+		if err != nil {
+			http.Error(w, "reading the registry: "+err.Error(), http.StatusInternalServerError)
+		}
+		return summary, err == nil
+	}
+	mux.HandleFunc("/api/summary", func(w http.ResponseWriter, r *http.Request) {
+		if summary, ok := load(w); ok {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(summary)
+		}
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		if summary, ok := load(w); ok {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			page.Execute(w, summary)
+		}
+	})
+	return readOnly(mux)
+}
+
+// readOnly turns away anything but reads
+func readOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "the dashboard is read-only", http.StatusMethodNotAllowed)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func summarize(store backend.Store, mood registry.MoodCurve, now time.Time) (Summary, error) {
+	var dogs []resources.DogState
+	var walks []resources.DogWalkState
+	var feeders []resources.SmartFeederState
+	var visits []resources.VeterinaryVisitState
+	for kind, out := range map[string]any{"dog": &dogs, "walk": &walks, "feeder": &feeders, "visit": &visits} {
+		if err := decode(store, kind, out); err != nil {
+			return Summary{}, err
+		}
+	}
+	return summary(dogs, walks, feeders, visits, mood, now), nil
+}
+
+// decode reads every record of kind into out, a pointer to a slice of the
+// kind's state type
+func decode(store backend.Store, kind string, out any) error {
+	records, err := store.List(kind)
+	if err != nil {
+		return err
+	}
+	payloads := make([]json.RawMessage, len(records))
+	for i, rec := range records {
+		payloads[i] = rec.Payload
+	}
+	data, err := json.Marshal(payloads)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+func summary(dogs []resources.DogState, walks []resources.DogWalkState, feeders []resources.SmartFeederState,
+	visits []resources.VeterinaryVisitState, mood registry.MoodCurve, now time.Time) Summary {
+	s := Summary{GeneratedAt: now.UTC().Format(time.RFC3339)}
+	names := map[string]string{}
+	for _, dog := range dogs {
+		names[dog.ID] = dog.Name
+	}
+
+	walksOf, feedersOf := map[string][]resources.DogWalkState{}, map[string][]resources.SmartFeederState{}
+	for _, walk := range walks {
+		walksOf[walk.DogID] = append(walksOf[walk.DogID], walk)
+	}
+	for _, feeder := range feeders {
+		feedersOf[feeder.DogID] = append(feedersOf[feeder.DogID], feeder)
+	}
+	for _, dog := range dogs {
+		trend := resources.HappinessTrend(mood, dog, walksOf[dog.ID], feedersOf[dog.ID], now, trendDays)
+		row := Dog{ID: dog.ID, Name: dog.Name, Breed: dog.Breed, Owner: dog.OwnerName, Trend: trend, LastWalk: "never"}
+		if len(trend) > 0 {
+			row.Happiness = trend[len(trend)-1]
+		}
+		for _, walk := range walksOf[dog.ID] {
+			if walk.Date > row.LastWalk || row.LastWalk == "never" {
+				row.LastWalk = walk.Date
+			}
+		}
+		s.Dogs = append(s.Dogs, row)
+	}
+	sort.Slice(s.Dogs, func(i, j int) bool { return strings.ToLower(s.Dogs[i].Name) < strings.ToLower(s.Dogs[j].Name) })
+
+	sort.Slice(walks, func(i, j int) bool { return walks[i].Date > walks[j].Date })
+	for _, walk := range walks {
+		if len(s.RecentWalks) == listLength {
+			break
+		}
+		s.RecentWalks = append(s.RecentWalks, Walk{DogName: names[walk.DogID], Date: walk.Date, Minutes: walk.Duration, Miles: walk.Distance, Enjoyment: walk.Enjoyment})
+	}
+
+	today := now.Format("2006-01-02")
+	for _, visit := range visits {
+		if visit.NextVisit >= today {
+			s.UpcomingVisits = append(s.UpcomingVisits, Visit{DogName: names[visit.DogID], Due: visit.NextVisit, LastVisit: visit.VisitType, ClinicName: visit.ClinicName})
+		}
+	}
+	sort.Slice(s.UpcomingVisits, func(i, j int) bool { return s.UpcomingVisits[i].Due < s.UpcomingVisits[j].Due })
+	if len(s.UpcomingVisits) > listLength {
+		s.UpcomingVisits = s.UpcomingVisits[:listLength]
+	}
+	return s
+}
+
+// sparkline draws scores out of 100 as a row of block characters
+func sparkline(scores []int) string {
+	const blocks = "▁▂▃▄▅▆▇█"
+	bars := []rune(blocks)
+	var b strings.Builder
+	for _, score := range scores {
+		b.WriteRune(bars[min(score*len(bars)/101, len(bars)-1)])
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+func TestHandler(t *testing.T) {
+	store := backend.NewFileStore(filepath.Join(t.TempDir(), "registry.json"))
+	records := []backend.Record{
+		{Kind: "dog", ID: "dog-rex", Payload: []byte(`{"ID":"dog-rex","Name":"Rex","Breed":"beagle","OwnerName":"Sam","RegistrationDate":"2024-02-20T09:00:00Z"}`)},
+		{Kind: "dog", ID: "dog-ada", Payload: []byte(`{"ID":"dog-ada","Name":"ada","Breed":"husky","OwnerName":"Jo","RegistrationDate":"2024-03-04T09:00:00Z"}`)},
+		{Kind: "walk", ID: "walk-1", Payload: []byte(`{"DogID":"dog-rex","Date":"2024-03-01T08:00:00Z","Duration":30,"Distance":1.5,"Enjoyment":"high"}`)},
+		{Kind: "walk", ID: "walk-2", Payload: []byte(`{"DogID":"dog-rex","Date":"2024-03-05T08:00:00Z","Duration":45,"Distance":2,"Enjoyment":"medium"}`)},
+		{Kind: "visit", ID: "visit-1", Payload: []byte(`{"DogID":"dog-rex","VisitType":"checkup","ClinicName":"Elm St","NextVisit":"2024-09-05"}`)},
+		{Kind: "visit", ID: "visit-2", Payload: []byte(`{"DogID":"dog-ada","VisitType":"dental","ClinicName":"Oak Ave","NextVisit":"2024-03-01"}`)},
+	}
+	if _, err := store.PutAll(records); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(Handler(store, registry.MoodCurve{HappinessHalfLifeHours: 24, FeedingIntervalHours: 12, ActivityWindowHours: 24}, func() time.Time { return now }))
+	defer server.Close()
+
+	t.Run("summary", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/summary")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var got Summary
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		if len(got.Dogs) != 2 || got.Dogs[0].Name != "ada" || got.Dogs[1].Name != "Rex" {
+			t.Fatalf("dogs = %+v, want ada then Rex", got.Dogs)
+		}
+		if rex := got.Dogs[1]; len(rex.Trend) != 7 || rex.LastWalk != "2024-03-05T08:00:00Z" {
+			t.Errorf("Rex = %+v, want a 7-day trend and the latest walk", rex)
+		}
+		if ada := got.Dogs[0]; len(ada.Trend) != 2 || ada.LastWalk != "never" {
+			t.Errorf("ada = %+v, want a trend from registration only and no walk", ada)
+		}
+		if len(got.RecentWalks) != 2 || got.RecentWalks[0].Date != "2024-03-05T08:00:00Z" || got.RecentWalks[0].DogName != "Rex" {
+			t.Errorf("recent walks = %+v, want the newest first", got.RecentWalks)
+		}
+		if len(got.UpcomingVisits) != 1 || got.UpcomingVisits[0].Due != "2024-09-05" {
+			t.Errorf("upcoming visits = %+v, want only the one still due", got.UpcomingVisits)
+		}
+	})
+
+	t.Run("page", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "Rex") {
+			t.Errorf("GET / = %d %q", resp.StatusCode, body)
+		}
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/api/summary", "application/json", strings.NewReader("{}"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("POST status = %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		scores []int
+		want   string
+	}{
+		{nil, ""},
+		{[]int{0, 50, 100}, "▁▄█"},
+		{[]int{12, 13, 99}, "▁▂█"},
+	}
+	for _, tt := range tests {
+		if got := sparkline(tt.scores); got != tt.want {
+			t.Errorf("sparkline(%v) = %q, want %q", tt.scores, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package functions
+
+import (
+	"context"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// ListAdoptableDogs lists a shelter's dogs still waiting for a home: those
+// its BulkDogIntakes registered that no approved Adoption has taken, the
+// longest waiting first. AdoptionEvent lists them for its booths. The list
+// is worked out from intakes and adoptions rather than read by one query,
+// so pages continue from an offset.
+type ListAdoptableDogs struct{}
+
+type ListAdoptableDogsArgs struct {
+	PageArgs
+	ShelterID string `pulumi:"shelterId"` // the shelter's name, lowercased with dashes for spaces
+}
+
+type ListAdoptableDogsResult struct {
+	Dogs          []resources.AdoptableDog `pulumi:"dogs"`
+	NextPageToken string                   `pulumi:"nextPageToken"`
+}
+
+func (ListAdoptableDogs) Call(ctx context.Context, args ListAdoptableDogsArgs) (ListAdoptableDogsResult, error) {
+	result := ListAdoptableDogsResult{}
+	size, err := args.size()
+	if err != nil {
+		return result, err
+	}
+	offset, err := decodeOffset(args.PageToken)
+	if err != nil {
+		return result, err
+	}
+
+	dogs, err := resources.AdoptableDogs(ctx, args.ShelterID)
+	if err != nil {
+		return result, err
+	}
+	result.Dogs = window(dogs, offset, size)
+	if end := offset + size; end < len(dogs) {
+		result.NextPageToken = encodeOffset(end)
+	}
+	return result, nil
+}
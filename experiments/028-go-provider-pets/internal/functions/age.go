@@ -0,0 +1,18 @@
+package functions
+
+import (
+	"context"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// DogAgeInHumanYears converts a dog's age to human-equivalent years. The
+// first two years count for 24 human years whatever the dog; after that
+// bigger dogs age faster, from 4 years a year for small dogs to 7 for
+// extra-large ones. It also says whether the dog is a puppy, an adult or
+// a senior, as care recommendations elsewhere in the provider do.
+type DogAgeInHumanYears struct{}
+
+func (DogAgeInHumanYears) Call(ctx context.Context, args resources.DogAge) (resources.HumanAge, error) {
+	return resources.DogAgeInHumanYears(ctx, args)
+}
@@ -0,0 +1,53 @@
+package functions
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// Approve marks a pending resource as approved; its next refresh reports it
+// as active.
+type Approve struct{}
+
+type ApproveArgs struct {
+	ResourceID string `pulumi:"resourceId"`
+	Token      string `pulumi:"token" provider:"secret"`
+}
+
+type ApproveResult struct {
+	ResourceID string `pulumi:"resourceId"`
+	Kind       string `pulumi:"kind"`
+	ApprovedAt string `pulumi:"approvedAt"`
+}
+
+func (Approve) Call(ctx context.Context, args ApproveArgs) (ApproveResult, error) {
+	result := ApproveResult{ResourceID: args.ResourceID}
+	var request resources.ApprovalRequest
+	version, err := registry.Load(ctx, "approval", args.ResourceID, &request)
+	if err != nil {
+		return result, err
+	}
+	hash := sha256.Sum256([]byte(args.Token))
+	if subtle.ConstantTimeCompare([]byte(hex.EncodeToString(hash[:])), []byte(request.TokenHash)) != 1 {
+		return result, fmt.Errorf("invalid approval token for %s", args.ResourceID)
+	}
+
+	result.Kind = request.Kind
+	if request.Status == "approved" {
+		result.ApprovedAt = request.ApprovedAt
+		return result, nil
+	}
+	request.Status = "approved"
+	request.ApprovedAt = registry.Now(ctx).Format("2006-01-02T15:04:05Z")
+	if _, err := registry.Save(ctx, "approval", args.ResourceID, version, request); err != nil {
+		return result, err
+	}
+	result.ApprovedAt = request.ApprovedAt
+	return result, nil
+}
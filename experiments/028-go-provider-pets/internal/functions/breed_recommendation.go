@@ -0,0 +1,181 @@
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// BreedRecommendation ranks every breed in the embedded breed catalog
+// against a household's answers to a short lifestyle questionnaire: the
+// kind of home, how active the household is, how long the dog would be
+// left alone, and whether anyone has dog allergies or there are children.
+// Each breed says what suits it to the household and what counts against
+// it, so a new stack can pick a Dog's breed from the data.
+type BreedRecommendation struct{}
+
+type BreedRecommendationArgs struct {
+	HomeSize      resources.HomeSize `pulumi:"homeSize"`           // apartment, house or house-with-yard
+	ActivityLevel int                `pulumi:"activityLevel"`      // from 1 (a stroll a day) to 5 (runs and long hikes), on the catalog's energy scale
+	HoursAway     *int               `pulumi:"hoursAway,optional"` // hours a day nobody is home; defaults to 0
+	Allergies     *bool              `pulumi:"allergies,optional"` // someone in the home is allergic to dogs
+	Kids          *bool              `pulumi:"kids,optional"`      // children live in the home
+}
+
+type BreedRecommendationResult struct {
+	Recommendations []RecommendedBreed `pulumi:"recommendations"` // best suited first
+}
+
+// RecommendedBreed is how well a breed suits the household
+type RecommendedBreed struct {
+	Rank      int                `pulumi:"rank"`
+	Breed     resources.DogBreed `pulumi:"breed"`
+	BreedName string             `pulumi:"breedName"`
+	Score     int                `pulumi:"score"`    // out of 100
+	Reasons   []string           `pulumi:"reasons"`  // what suits the breed to the household
+	Concerns  []string           `pulumi:"concerns"` // what counts against it
+}
+
+// lifestyle is the household's answers to the questionnaire
+type lifestyle struct {
+	home      resources.HomeSize
+	activity  int
+	hoursAway int
+	allergies bool
+	kids      bool
+}
+
+// Points a breed loses for each step its energy level is off the
+// household's activity, for each hour it would be alone longer than it
+// copes with, for each step of shedding above the lowest with allergies in
+// the home, and for each step it falls short of good with children
+const (
+	activityGapPenalty = 12
+	hourAlonePenalty   = 8
+	maxAlonePenalty    = 30
+	sheddingPenalty    = 10
+	kidsPenalty        = 15
+	// apartmentPenalty is for a breed that isn't apartment-friendly in an
+	// apartment, yardlessPenalty for a tireless one in a house with no yard
+	apartmentPenalty = 25
+	yardlessPenalty  = 10
+)
+
+const (
+	// lowShedding is the shedding rating up to which a breed is easy on
+	// allergies
+	lowShedding = 2
+	// goodWithKids is the kids rating from which a breed is fine with
+	// children
+	goodWithKids = 4
+	// yardEnergy is the energy level from which a breed makes use of a yard
+	yardEnergy = 4
+)
+
+func (BreedRecommendation) Call(ctx context.Context, args BreedRecommendationArgs) (BreedRecommendationResult, error) {
+	want := lifestyle{home: args.HomeSize, activity: args.ActivityLevel}
+	if args.HoursAway != nil {
+		want.hoursAway = *args.HoursAway
+	}
+	if args.Allergies != nil {
+		want.allergies = *args.Allergies
+	}
+	if args.Kids != nil {
+		want.kids = *args.Kids
+	}
+
+	switch {
+	case want.home != resources.HomeApartment && want.home != resources.HomeHouse && want.home != resources.HomeHouseWithYard:
+		return BreedRecommendationResult{}, fmt.Errorf("homeSize must be apartment, house or house-with-yard, got %q", want.home)
+	case want.activity < 1 || want.activity > 5:
+		return BreedRecommendationResult{}, errors.New("activityLevel must be between 1 and 5")
+	case want.hoursAway < 0 || want.hoursAway > 24:
+		return BreedRecommendationResult{}, errors.New("hoursAway must be between 0 and 24")
+	}
+
+	return BreedRecommendationResult{Recommendations: recommendBreeds(resources.BreedCatalog, want)}, nil
+}
+
+// recommendBreeds scores every breed in the catalog for the household,
+// best suited first
+func recommendBreeds(catalog map[resources.DogBreed]resources.BreedInfo, want lifestyle) []RecommendedBreed {
+	out := []RecommendedBreed{}
+	for breed, info := range catalog {
+		out = append(out, rateBreed(breed, info, want))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].BreedName < out[j].BreedName
+	})
+	for i := range out {
+		out[i].Rank = i + 1
+	}
+	return out
+}
+
+// rateBreed is how well the breed suits the household, and why
+func rateBreed(breed resources.DogBreed, info resources.BreedInfo, want lifestyle) RecommendedBreed {
+	rated := RecommendedBreed{Breed: breed, BreedName: info.Name, Reasons: []string{}, Concerns: []string{}}
+	penalty := 0
+	reason := func(format string, a ...any) { rated.Reasons = append(rated.Reasons, fmt.Sprintf(format, a...)) }
+	concern := func(points int, format string, a ...any) {
+		penalty += points
+		rated.Concerns = append(rated.Concerns, fmt.Sprintf(format, a...))
+	}
+
+	switch gap := info.EnergyLevel - want.activity; {
+	case gap == 0:
+		reason("energy level %d/5 matches your activity level", info.EnergyLevel)
+	case gap > 0:
+		concern(gap*activityGapPenalty, "needs more exercise than you plan for (energy %d/5)", info.EnergyLevel)
+	default:
+		concern(-gap*activityGapPenalty, "calmer than your lifestyle (energy %d/5)", info.EnergyLevel)
+	}
+
+	switch want.home {
+	case resources.HomeApartment:
+		if info.ApartmentFriendly {
+			reason("settles in an apartment")
+		} else {
+			concern(apartmentPenalty, "not suited to apartment living")
+		}
+	case resources.HomeHouse:
+		if !info.ApartmentFriendly && info.EnergyLevel > yardEnergy {
+			concern(yardlessPenalty, "happiest with a yard to run in")
+		}
+	case resources.HomeHouseWithYard:
+		if info.EnergyLevel >= yardEnergy {
+			reason("will make good use of a yard")
+		}
+	}
+
+	if over := want.hoursAway - info.AloneHours; over > 0 {
+		concern(min(maxAlonePenalty, over*hourAlonePenalty), "copes with about %d hours alone, not %d", info.AloneHours, want.hoursAway)
+	} else if want.hoursAway > 0 {
+		reason("copes with %d hours alone", want.hoursAway)
+	}
+
+	if want.allergies {
+		if info.Shedding <= lowShedding {
+			reason("low shedding, easier on allergies")
+		} else {
+			concern((info.Shedding-1)*sheddingPenalty, "sheds (%d/5), hard on allergies", info.Shedding)
+		}
+	}
+
+	if want.kids {
+		if info.GoodWithKids >= goodWithKids {
+			reason("good with children (%d/5)", info.GoodWithKids)
+		} else {
+			concern((goodWithKids-info.GoodWithKids)*kidsPenalty, "better with older children or adults (%d/5)", info.GoodWithKids)
+		}
+	}
+
+	rated.Score = max(0, 100-penalty)
+	return rated
+}
@@ -0,0 +1,51 @@
+package functions
+
+import (
+	"testing"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+func TestRateBreed(t *testing.T) {
+	easy := resources.BreedInfo{Name: "Easy", EnergyLevel: 3, Shedding: 1, GoodWithKids: 4, AloneHours: 6, ApartmentFriendly: true}
+	tireless := resources.BreedInfo{Name: "Tireless", EnergyLevel: 5, Shedding: 5, GoodWithKids: 3, AloneHours: 4}
+	tests := []struct {
+		name     string
+		info     resources.BreedInfo
+		want     lifestyle
+		score    int
+		concerns int
+	}{
+		{name: "a perfect fit", info: easy, want: lifestyle{home: resources.HomeApartment, activity: 3, hoursAway: 6, allergies: true, kids: true}, score: 100},
+		{name: "more energetic than the household", info: easy, want: lifestyle{home: resources.HomeHouse, activity: 1}, score: 76, concerns: 1},
+		{name: "long days alone", info: easy, want: lifestyle{home: resources.HomeHouse, activity: 3, hoursAway: 8}, score: 84, concerns: 1},
+		{name: "alone penalty capped", info: easy, want: lifestyle{home: resources.HomeHouse, activity: 3, hoursAway: 12}, score: 70, concerns: 1},
+		{name: "yardless", info: tireless, want: lifestyle{home: resources.HomeHouse, activity: 5}, score: 90, concerns: 1},
+		{name: "with a yard", info: tireless, want: lifestyle{home: resources.HomeHouseWithYard, activity: 5}, score: 100},
+		{name: "allergies and kids", info: tireless, want: lifestyle{home: resources.HomeHouseWithYard, activity: 5, allergies: true, kids: true}, score: 45, concerns: 2},
+		{name: "nothing fits", info: tireless, want: lifestyle{home: resources.HomeApartment, activity: 1, hoursAway: 12, allergies: true}, score: 0, concerns: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rateBreed("test", tt.info, tt.want)
+			if got.Score != tt.score || len(got.Concerns) != tt.concerns {
+				t.Errorf("rateBreed = %d with concerns %q, want %d with %d", got.Score, got.Concerns, tt.score, tt.concerns)
+			}
+		})
+	}
+}
+
+func TestRecommendBreeds(t *testing.T) {
+	got := recommendBreeds(resources.BreedCatalog, lifestyle{home: resources.HomeApartment, activity: 3, allergies: true, kids: true})
+	if len(got) != len(resources.BreedCatalog) {
+		t.Fatalf("got %d recommendations, want one per breed", len(got))
+	}
+	if got[0].Breed != resources.Poodle || got[0].Rank != 1 {
+		t.Errorf("top recommendation = %+v, want the poodle", got[0])
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i].Score > got[i-1].Score || got[i].Rank != i+1 {
+			t.Errorf("recommendation %d = %+v out of order after %+v", i, got[i], got[i-1])
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/cache"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// GetBreedImage returns a reference picture of a breed with its attribution.
+// By default the embedded breed catalog names the breed's gallery on the
+// free Dog CEO image service; with pets:dogApi configured the picture is
+// the breed's reference image on TheDogAPI instead. Either way the same
+// breed gets the same image, so stack outputs don't churn, and answers are
+// cached for an hour.
+type GetBreedImage struct{}
+
+type GetBreedImageArgs struct {
+	Breed resources.DogBreed `pulumi:"breed"`
+}
+
+type GetBreedImageResult struct {
+	Breed       resources.DogBreed `pulumi:"breed"`
+	BreedName   string             `pulumi:"breedName"`
+	ImageURL    string             `pulumi:"imageUrl"`
+	Attribution string             `pulumi:"attribution"`
+	PageURL     string             `pulumi:"pageUrl"` // where to read about the breed
+}
+
+// dogCEOURL is the Dog CEO API; tests point it at a local server
+var dogCEOURL = "https://dog.ceo/api"
+
+var breedImages = cache.New[string, GetBreedImageResult]("breed-images", 64, time.Hour)
+
+func (GetBreedImage) Call(ctx context.Context, args GetBreedImageArgs) (GetBreedImageResult, error) {
+	return breedImage(ctx, registry.HTTPClient(ctx), registry.DogAPI(ctx), args.Breed)
+}
+
+// breedImage looks the breed's image up through client, on TheDogAPI when
+// api is set and Dog CEO otherwise
+func breedImage(ctx context.Context, client *http.Client, api *registry.DogAPIConfig, breed resources.DogBreed) (GetBreedImageResult, error) {
+	entry, ok := resources.BreedCatalog[breed]
+	if !ok {
+		var valid []string
+		for known := range resources.BreedCatalog {
+			valid = append(valid, string(known))
+		}
+		sort.Strings(valid)
+		return GetBreedImageResult{}, fmt.Errorf("unknown breed %q, expected one of: %s", breed, strings.Join(valid, ", "))
+	}
+
+	result := GetBreedImageResult{Breed: breed, BreedName: entry.Name, PageURL: entry.PageURL}
+	if api != nil {
+		return breedImages.GetOrLoad("thedogapi/"+string(breed), func() (GetBreedImageResult, error) {
+			var err error
+			result.ImageURL, err = theDogAPIImage(ctx, client, api, entry.Name)
+			result.Attribution = "TheDogAPI (thedogapi.com)"
+			return result, err
+		})
+	}
+	return breedImages.GetOrLoad("dogceo/"+string(breed), func() (GetBreedImageResult, error) {
+		var err error
+		result.ImageURL, err = dogCEOImage(ctx, client, entry.DogCEOPath)
+		result.Attribution = "Dog CEO (dog.ceo), from the Stanford Dogs Dataset"
+		return result, err
+	})
+}
+
+// dogCEOImage picks the first image of the breed's gallery, in name order
+func dogCEOImage(ctx context.Context, client *http.Client, path string) (string, error) {
+	var gallery struct {
+		Message []string `json:"message"`
+		Status  string   `json:"status"`
+	}
+	if err := getJSON(ctx, client, dogCEOURL+"/breed/"+path+"/images", nil, &gallery); err != nil {
+		return "", fmt.Errorf("dog.ceo: %w", err)
+	}
+	if gallery.Status != "success" || len(gallery.Message) == 0 {
+		return "", fmt.Errorf("dog.ceo has no images of %s", path)
+	}
+	sort.Strings(gallery.Message)
+	return gallery.Message[0], nil
+}
+
+// theDogAPIImage looks up the breed's reference image on TheDogAPI
+func theDogAPIImage(ctx context.Context, client *http.Client, api *registry.DogAPIConfig, name string) (string, error) {
+	header := http.Header{"X-Api-Key": {api.APIKey}}
+	base := strings.TrimSuffix(api.URL(), "/")
+
+	var matches []struct {
+		Name             string `json:"name"`
+		ReferenceImageID string `json:"reference_image_id"`
+	}
+	if err := getJSON(ctx, client, base+"/v1/breeds/search?q="+url.QueryEscape(name), header, &matches); err != nil {
+		return "", fmt.Errorf("thedogapi: %w", err)
+	}
+	imageID := ""
+	for _, match := range matches {
+		if match.ReferenceImageID != "" && (imageID == "" || strings.EqualFold(match.Name, name)) {
+			imageID = match.ReferenceImageID
+		}
+	}
+	if imageID == "" {
+		return "", fmt.Errorf("thedogapi has no reference image for %s", name)
+	}
+
+	var image struct {
+		URL string `json:"url"`
+	}
+	if err := getJSON(ctx, client, base+"/v1/images/"+url.PathEscape(imageID), header, &image); err != nil {
+		return "", fmt.Errorf("thedogapi: %w", err)
+	}
+	return image.URL, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, header http.Header, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,95 @@
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+func TestGetBreedImageFromDogCEO(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/breed/retriever/golden/images" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"status":  "success",
+			"message": []string{"https://images.example/b.jpg", "https://images.example/a.jpg"},
+		})
+	}))
+	defer server.Close()
+	defer func(old string) { dogCEOURL = old }(dogCEOURL)
+	dogCEOURL = server.URL
+
+	for i := 0; i < 2; i++ {
+		got, err := breedImage(context.Background(), server.Client(), nil, resources.GoldenRetriever)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ImageURL != "https://images.example/a.jpg" || got.BreedName != "Golden Retriever" || got.Attribution == "" {
+			t.Errorf("result = %+v, want the first image in name order with attribution", got)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("dog.ceo called %d times, want the second answer from the cache", requests)
+	}
+
+	if _, err := breedImage(context.Background(), server.Client(), nil, "mixed"); err == nil ||
+		!strings.Contains(err.Error(), `unknown breed "mixed"`) {
+		t.Errorf("err = %v, want an unknown breed error", err)
+	}
+}
+
+func TestTheDogAPIImage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "k3y" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/breeds/search":
+			json.NewEncoder(w).Encode([]map[string]string{
+				{"name": "Siberian Husky Mix", "reference_image_id": "mix"},
+				{"name": r.URL.Query().Get("q"), "reference_image_id": "S17ZilqNm"},
+			})
+		case "/v1/images/S17ZilqNm":
+			json.NewEncoder(w).Encode(map[string]string{"url": "https://cdn.example/S17ZilqNm.jpg"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		key     string
+		want    string
+		wantErr string
+	}{
+		{name: "exact match preferred", key: "k3y", want: "https://cdn.example/S17ZilqNm.jpg"},
+		{name: "bad key", key: "nope", wantErr: "401"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &registry.DogAPIConfig{APIKey: tt.key, BaseURL: &server.URL}
+			got, err := theDogAPIImage(context.Background(), server.Client(), api, "Siberian Husky")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("image = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// ExportCalendar renders the scheduled events between two dates as an
+// iCalendar (RFC 5545) document that calendar apps can import or subscribe
+// to: the next visit each vet visit booked, as an all-day event, and when
+// each SMS reminder goes out. Events keep their UIDs from one export to
+// the next, so a re-import updates them rather than adding copies.
+type ExportCalendar struct{}
+
+type ExportCalendarArgs struct {
+	DogID      *string `pulumi:"dogId,optional"` // Only this dog's events; every dog's when unset
+	RangeStart string  `pulumi:"rangeStart"`     // First day to include, as YYYY-MM-DD
+	RangeEnd   string  `pulumi:"rangeEnd"`       // Last day to include, as YYYY-MM-DD
+}
+
+type ExportCalendarResult struct {
+	Ics    string `pulumi:"ics"`
+	Events int    `pulumi:"events"`
+}
+
+// calendarEvent is one VEVENT. An all-day event has a zero At.
+type calendarEvent struct {
+	UID         string
+	Day         string // YYYY-MM-DD
+	At          time.Time
+	Summary     string
+	Description string
+}
+
+func (ExportCalendar) Call(ctx context.Context, args ExportCalendarArgs) (ExportCalendarResult, error) {
+	start, err := time.Parse("2006-01-02", args.RangeStart)
+	if err != nil {
+		return ExportCalendarResult{}, errors.New("rangeStart must be a YYYY-MM-DD date")
+	}
+	end, err := time.Parse("2006-01-02", args.RangeEnd)
+	if err != nil {
+		return ExportCalendarResult{}, errors.New("rangeEnd must be a YYYY-MM-DD date")
+	}
+	if end.Before(start) {
+		return ExportCalendarResult{}, errors.New("rangeEnd must not be before rangeStart")
+	}
+
+	list := func(kind string, out any) error {
+		if args.DogID != nil {
+			return listForDog(ctx, kind, *args.DogID, out)
+		}
+		return listRecords(ctx, backend.Query{Kind: kind}, out)
+	}
+	var dogs []resources.DogState
+	if args.DogID != nil {
+		var dog resources.DogState
+		if _, err := registry.Load(ctx, "dog", *args.DogID, &dog); err != nil {
+			return ExportCalendarResult{}, fmt.Errorf("dog %s: %w", *args.DogID, err)
+		}
+		dogs = append(dogs, dog)
+	} else if err := listRecords(ctx, backend.Query{Kind: "dog"}, &dogs); err != nil {
+		return ExportCalendarResult{}, err
+	}
+	names := map[string]string{}
+	for _, dog := range dogs {
+		names[dog.ID] = dog.Name
+	}
+	name := func(dogID string) string {
+		if n, ok := names[dogID]; ok {
+			return n
+		}
+		return dogID
+	}
+
+	var events []calendarEvent
+	var visits []resources.VeterinaryVisitState
+	if err := list("visit", &visits); err != nil {
+		return ExportCalendarResult{}, err
+	}
+	for _, visit := range visits {
+		if visit.NextVisit == "" {
+			continue
+		}
+		events = append(events, calendarEvent{
+			UID:         visit.ID + "-next-visit",
+			Day:         visit.NextVisit,
+			Summary:     fmt.Sprintf("%s: vet visit at %s", name(visit.DogID), visit.ClinicName),
+			Description: fmt.Sprintf("Follows the %s visit with %s. %s", visit.VisitType, visit.VetName, visit.Diagnosis),
+		})
+	}
+
+	var reminders []resources.SmsReminderState
+	if err := list("sms", &reminders); err != nil {
+		return ExportCalendarResult{}, err
+	}
+	for _, reminder := range reminders {
+		at, err := time.Parse(time.RFC3339, reminder.CreatedAt)
+		if reminder.SendAt != nil {
+			at, err = time.Parse(time.RFC3339, *reminder.SendAt)
+		}
+		if err != nil {
+			continue
+		}
+		at = at.UTC()
+		events = append(events, calendarEvent{
+			UID:         reminder.ID,
+			Day:         at.Format("2006-01-02"),
+			At:          at,
+			Summary:     fmt.Sprintf("%s: text reminder", name(reminder.DogID)),
+			Description: reminder.Body,
+		})
+	}
+
+	events = inRange(events, args.RangeStart, args.RangeEnd)
+	return ExportCalendarResult{
+		Ics:    renderICS(events, registry.Now(ctx)),
+		Events: len(events),
+	}, nil
+}
+
+// inRange keeps the events on days from first to last, in date order
+func inRange(events []calendarEvent, first, last string) []calendarEvent {
+	var kept []calendarEvent
+	for _, e := range events {
+		if e.Day >= first && e.Day <= last {
+			kept = append(kept, e)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		if kept[i].Day != kept[j].Day {
+			return kept[i].Day < kept[j].Day
+		}
+		return kept[i].At.Before(kept[j].At)
+	})
+	return kept
+}
+
+// renderICS writes events as an iCalendar document stamped at now
+func renderICS(events []calendarEvent, now time.Time) string {
+	var b strings.Builder
+	line := func(content string) {
+		b.WriteString(foldICS(content))
+		b.WriteString("\r\n")
+	}
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//aygp-dr//pulumi-pets-provider//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + escapeICS(e.UID) + "@pets")
+		line("DTSTAMP:" + stamp)
+		if e.At.IsZero() {
+			day, _ := time.Parse("2006-01-02", e.Day)
+			line("DTSTART;VALUE=DATE:" + day.Format("20060102"))
+			line("DTEND;VALUE=DATE:" + day.AddDate(0, 0, 1).Format("20060102"))
+		} else {
+			line("DTSTART:" + e.At.Format("20060102T150405Z"))
+		}
+		line("SUMMARY:" + escapeICS(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION:" + escapeICS(e.Description))
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+// escapeICS escapes a TEXT value
+func escapeICS(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// foldICS splits a content line into lines of at most 75 octets, each
+// continuation starting with a space, without breaking a UTF-8 sequence
+func foldICS(content string) string {
+	const limit = 75
+	var b strings.Builder
+	width := 0
+	for _, r := range content {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
@@ -0,0 +1,71 @@
+package functions
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderICS(t *testing.T) {
+	now := time.Date(2026, 6, 15, 8, 30, 0, 0, time.UTC)
+	events := inRange([]calendarEvent{
+		{UID: "sms-rex-1", Day: "2026-07-01", At: time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC), Summary: "Rex: text reminder", Description: "Walk time, Rex; bring the ball\nand water"},
+		{UID: "vet-rex-1-next-visit", Day: "2026-06-29", Summary: "Rex: vet visit at Happy Paws"},
+		{UID: "vet-rex-0-next-visit", Day: "2026-05-01", Summary: "Rex: vet visit in the past"},
+		{UID: "vet-rex-2-next-visit", Day: "2026-07-02", Summary: "Rex: after the range"},
+	}, "2026-06-15", "2026-07-01")
+
+	got := renderICS(events, now)
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//aygp-dr//pulumi-pets-provider//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"BEGIN:VEVENT",
+		"UID:vet-rex-1-next-visit@pets",
+		"DTSTAMP:20260615T083000Z",
+		"DTSTART;VALUE=DATE:20260629",
+		"DTEND;VALUE=DATE:20260630",
+		"SUMMARY:Rex: vet visit at Happy Paws",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:sms-rex-1@pets",
+		"DTSTAMP:20260615T083000Z",
+		"DTSTART:20260701T090000Z",
+		"SUMMARY:Rex: text reminder",
+		`DESCRIPTION:Walk time\, Rex\; bring the ball\nand water`,
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	if got != want {
+		t.Errorf("renderICS =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFoldICS(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		lines []string
+	}{
+		{name: "short", input: "SUMMARY:Walk", lines: []string{"SUMMARY:Walk"}},
+		{name: "long", input: "DESCRIPTION:" + strings.Repeat("a", 100), lines: []string{"DESCRIPTION:" + strings.Repeat("a", 63), " " + strings.Repeat("a", 37)}},
+		// é is two octets and must not be split across lines
+		{name: "multibyte", input: "SUMMARY:" + strings.Repeat("é", 40), lines: []string{"SUMMARY:" + strings.Repeat("é", 33), " " + strings.Repeat("é", 7)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := strings.Split(foldICS(tt.input), "\r\n")
+			if strings.Join(lines, "|") != strings.Join(tt.lines, "|") {
+				t.Errorf("foldICS = %q, want %q", lines, tt.lines)
+			}
+			for _, l := range lines {
+				if len(l) > 75 {
+					t.Errorf("line of %d octets", len(l))
+				}
+			}
+		})
+	}
+}
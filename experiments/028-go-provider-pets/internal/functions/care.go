@@ -0,0 +1,58 @@
+package functions
+
+import (
+	"context"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// FeedDog, GiveTreat and RecordWalk look after a registered dog between
+// deployments and return how it is doing afterwards. They carry out the
+// Dog's feed, giveTreat and recordWalk methods, which the engine tells when
+// it is previewing. Called directly they take the dog's ID, and as invokes
+// also run during previews, they only report how the dog would be doing
+// unless dryRun is false, which programs should pass as the negation of
+// ctx.DryRun() or its equivalent. Either way they change the registry
+// whenever the program calls them for real, so call them from a program run
+// for the purpose rather than on every update. The next refresh of the Dog
+// shows their effect.
+type FeedDog struct{}
+
+type FeedDogArgs struct {
+	DogID        string `pulumi:"dogId"`
+	PortionGrams int    `pulumi:"portionGrams"` // Food given, in grams
+	DryRun       *bool  `pulumi:"dryRun,optional"`
+}
+
+func (FeedDog) Call(ctx context.Context, args FeedDogArgs) (resources.DogStats, error) {
+	return resources.FeedDog(ctx, args.DogID, args.PortionGrams, registry.DryRun(args.DryRun))
+}
+
+type GiveTreat struct{}
+
+type GiveTreatArgs struct {
+	DogID  string `pulumi:"dogId"`
+	Count  *int   `pulumi:"count,optional"` // Treats given, 1 by default
+	DryRun *bool  `pulumi:"dryRun,optional"`
+}
+
+func (GiveTreat) Call(ctx context.Context, args GiveTreatArgs) (resources.DogStats, error) {
+	count := 1
+	if args.Count != nil {
+		count = *args.Count
+	}
+	return resources.GiveTreats(ctx, args.DogID, count, registry.DryRun(args.DryRun))
+}
+
+type RecordWalk struct{}
+
+type RecordWalkArgs struct {
+	DogID   string `pulumi:"dogId"`
+	Minutes int    `pulumi:"minutes"` // Length of the walk that just ended
+	DryRun  *bool  `pulumi:"dryRun,optional"`
+}
+
+func (RecordWalk) Call(ctx context.Context, args RecordWalkArgs) (resources.DogStats, error) {
+	return resources.RecordWalk(ctx, args.DogID, args.Minutes, registry.DryRun(args.DryRun))
+}
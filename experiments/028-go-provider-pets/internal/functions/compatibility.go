@@ -0,0 +1,19 @@
+package functions
+
+import (
+	"context"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// CompatibilityScore scores out of 100 how well two dogs would get on,
+// with the factors behind the score: the gap in their sizes, the gap in
+// their energy levels and their behavior incidents over the last year.
+// Each dog is a registered one by ID, or described by its traits for a
+// dog that isn't in the registry. Playdates are assessed by the same
+// model.
+type CompatibilityScore struct{}
+
+func (CompatibilityScore) Call(ctx context.Context, args resources.CompatibilityScoreArgs) (resources.Compatibility, error) {
+	return resources.CompatibilityScore(ctx, args)
+}
@@ -0,0 +1,43 @@
+package functions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// ConvertCurrency converts an amount between currencies with the rates the
+// provider shows costs in: those from the configured currency.ratesUrl, or
+// the embedded snapshot when it isn't set or can't be reached.
+type ConvertCurrency struct{}
+
+type ConvertCurrencyArgs struct {
+	Amount float64 `pulumi:"amount"`
+	From   string  `pulumi:"from"` // ISO 4217 code, e.g. USD
+	To     string  `pulumi:"to"`   // ISO 4217 code, e.g. EUR
+}
+
+type ConvertCurrencyResult struct {
+	Amount    float64 `pulumi:"amount"` // converted, to two decimal places
+	Rate      float64 `pulumi:"rate"`   // what one unit of from is worth in to
+	RatesDate string  `pulumi:"ratesDate"`
+	Source    string  `pulumi:"source"` // embedded, or the URL the rates came from
+}
+
+func (ConvertCurrency) Call(ctx context.Context, args ConvertCurrencyArgs) (ConvertCurrencyResult, error) {
+	if args.From == "" || args.To == "" {
+		return ConvertCurrencyResult{}, errors.New("from and to are required")
+	}
+	rates := registry.ExchangeRates(ctx)
+	rate, err := rates.Rate(args.From, args.To)
+	if err != nil {
+		return ConvertCurrencyResult{}, err
+	}
+	return ConvertCurrencyResult{
+		Amount:    round2(args.Amount * rate),
+		Rate:      rate,
+		RatesDate: rates.Date,
+		Source:    rates.Source,
+	}, nil
+}
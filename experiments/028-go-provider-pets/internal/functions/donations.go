@@ -0,0 +1,94 @@
+package functions
+
+import (
+	"context"
+	"sort"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// GetCampaignTotals adds up Donations by the campaign they were credited
+// to, those without one under "general". Either filter can be left unset.
+type GetCampaignTotals struct{}
+
+type GetCampaignTotalsArgs struct {
+	ShelterID *string `pulumi:"shelterId,optional"`
+	Campaign  *string `pulumi:"campaign,optional"`
+}
+
+type GetCampaignTotalsResult struct {
+	Campaigns []CampaignTotal `pulumi:"campaigns"` // largest total first
+	Total     float64         `pulumi:"total"`
+}
+
+// CampaignTotal sums one campaign's donations at one shelter
+type CampaignTotal struct {
+	ShelterID     string  `pulumi:"shelterId"`
+	Campaign      string  `pulumi:"campaign"`
+	Donations     int     `pulumi:"donations"`
+	Donors        int     `pulumi:"donors"` // distinct donor contacts
+	Total         float64 `pulumi:"total"`
+	Largest       float64 `pulumi:"largest"`
+	FirstDonation string  `pulumi:"firstDonation"`
+	LastDonation  string  `pulumi:"lastDonation"`
+}
+
+func (GetCampaignTotals) Call(ctx context.Context, args GetCampaignTotalsArgs) (GetCampaignTotalsResult, error) {
+	q := backend.Query{Kind: "donation"}
+	if args.ShelterID != nil {
+		q.Where = append(q.Where, backend.Condition{Field: "ShelterID", Op: "eq", Value: *args.ShelterID})
+	}
+	var donations []resources.DonationState
+	if err := listRecords(ctx, q, &donations); err != nil {
+		return GetCampaignTotalsResult{}, err
+	}
+	if args.Campaign != nil {
+		credited := donations[:0]
+		for _, donation := range donations {
+			if donation.CreditedCampaign() == *args.Campaign {
+				credited = append(credited, donation)
+			}
+		}
+		donations = credited
+	}
+	return campaignTotals(donations), nil
+}
+
+// campaignTotals groups the donations by shelter and campaign
+func campaignTotals(donations []resources.DonationState) GetCampaignTotalsResult {
+	type key struct{ shelter, campaign string }
+	totals := map[key]*CampaignTotal{}
+	donors := map[key]map[string]bool{}
+	result := GetCampaignTotalsResult{Campaigns: []CampaignTotal{}}
+	for _, donation := range donations {
+		k := key{donation.ShelterID, donation.CreditedCampaign()}
+		total, ok := totals[k]
+		if !ok {
+			total = &CampaignTotal{ShelterID: k.shelter, Campaign: k.campaign, FirstDonation: donation.DonatedAt, LastDonation: donation.DonatedAt}
+			totals[k], donors[k] = total, map[string]bool{}
+		}
+		total.Donations++
+		donors[k][donation.DonorContact] = true
+		total.Donors = len(donors[k])
+		total.Total = round2(total.Total + donation.Amount)
+		total.Largest = max(total.Largest, donation.Amount)
+		total.FirstDonation = min(total.FirstDonation, donation.DonatedAt)
+		total.LastDonation = max(total.LastDonation, donation.DonatedAt)
+		result.Total = round2(result.Total + donation.Amount)
+	}
+	for _, total := range totals {
+		result.Campaigns = append(result.Campaigns, *total)
+	}
+	sort.Slice(result.Campaigns, func(i, j int) bool {
+		a, b := result.Campaigns[i], result.Campaigns[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		if a.ShelterID != b.ShelterID {
+			return a.ShelterID < b.ShelterID
+		}
+		return a.Campaign < b.Campaign
+	})
+	return result
+}
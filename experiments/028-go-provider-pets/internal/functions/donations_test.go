@@ -0,0 +1,47 @@
+package functions
+
+import (
+	"testing"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+func TestCampaignTotals(t *testing.T) {
+	donation := func(shelter, campaign, donor string, amount float64, at string) resources.DonationState {
+		var d resources.DonationState
+		d.ShelterID, d.DonorContact, d.Amount, d.DonatedAt = shelter, donor, amount, at
+		if campaign != "" {
+			d.Campaign = &campaign
+		}
+		return d
+	}
+	result := campaignTotals([]resources.DonationState{
+		donation("happy-tails", "Winter Coats", "ana@example.org", 50, "2026-10-03T10:00:00Z"),
+		donation("happy-tails", "Winter Coats", "ana@example.org", 25.5, "2026-10-01T10:00:00Z"),
+		donation("happy-tails", "Winter Coats", "bo@example.org", 100, "2026-10-09T10:00:00Z"),
+		donation("happy-tails", "", "cy@example.org", 500, "2026-09-15T10:00:00Z"),
+		donation("paws-place", "Winter Coats", "ana@example.org", 10, "2026-10-05T10:00:00Z"),
+	})
+
+	if result.Total != 685.5 || len(result.Campaigns) != 3 {
+		t.Fatalf("result = %+v", result)
+	}
+	general, coats, other := result.Campaigns[0], result.Campaigns[1], result.Campaigns[2]
+	if general.Campaign != resources.GeneralCampaign || general.Total != 500 {
+		t.Errorf("first campaign = %+v, want general at 500", general)
+	}
+	want := CampaignTotal{
+		ShelterID: "happy-tails", Campaign: "Winter Coats", Donations: 3, Donors: 2, Total: 175.5, Largest: 100,
+		FirstDonation: "2026-10-01T10:00:00Z", LastDonation: "2026-10-09T10:00:00Z",
+	}
+	if coats != want {
+		t.Errorf("winter coats = %+v, want %+v", coats, want)
+	}
+	if other.ShelterID != "paws-place" || other.Total != 10 {
+		t.Errorf("last campaign = %+v", other)
+	}
+
+	if empty := campaignTotals(nil); empty.Total != 0 || empty.Campaigns == nil {
+		t.Errorf("no donations = %+v, want an empty list", empty)
+	}
+}
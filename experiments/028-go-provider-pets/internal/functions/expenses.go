@@ -0,0 +1,321 @@
+package functions
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// ServiceDogExpenseReport itemizes what a working dog cost over a tax year
+// and how much of it is deductible: vet visits with a cost, wellness plan
+// fees, insurance premiums and subscription box deliveries, each weighed
+// by the rule for its category. The rules default to the embedded ruleset
+// and can be replaced wholesale through rules. The report comes back both
+// structured and as CSV for a spreadsheet or a tax preparer.
+type ServiceDogExpenseReport struct{}
+
+type ServiceDogExpenseReportArgs struct {
+	DogID   string        `pulumi:"dogId"`
+	TaxYear int           `pulumi:"taxYear"`
+	Rules   []ExpenseRule `pulumi:"rules,optional"` // replaces the default ruleset; categories without a rule are not deductible
+}
+
+// ExpenseRule says how much of a category of expense is deductible
+type ExpenseRule struct {
+	Category    string                `pulumi:"category" json:"category"` // vet, wellness, training, equipment or insurance
+	Percent     float64               `pulumi:"percent" json:"percent"`
+	VisitTypes  []resources.VisitType `pulumi:"visitTypes,optional" json:"visitTypes,omitempty"` // vet only: the visit types the rule covers; all when unset
+	Description string                `pulumi:"description,optional" json:"description,omitempty"`
+}
+
+type ServiceDogExpenseReportResult struct {
+	DogID           string          `pulumi:"dogId"`
+	DogName         string          `pulumi:"dogName"`
+	TaxYear         int             `pulumi:"taxYear"`
+	Items           []ExpenseItem   `pulumi:"items"` // oldest first
+	Totals          []CategoryTotal `pulumi:"totals"`
+	TotalAmount     float64         `pulumi:"totalAmount"`     // dollars
+	TotalDeductible float64         `pulumi:"totalDeductible"` // dollars
+	Csv             string          `pulumi:"csv"`
+}
+
+// ExpenseItem is one charge
+type ExpenseItem struct {
+	Date        string  `pulumi:"date"`
+	Category    string  `pulumi:"category"`
+	Description string  `pulumi:"description"`
+	Source      string  `pulumi:"source"` // ID of the resource the charge came from
+	Amount      float64 `pulumi:"amount"`
+	Percent     float64 `pulumi:"percent"` // share of the amount that is deductible
+	Deductible  float64 `pulumi:"deductible"`
+}
+
+// CategoryTotal sums a category's items
+type CategoryTotal struct {
+	Category   string  `pulumi:"category"`
+	Amount     float64 `pulumi:"amount"`
+	Deductible float64 `pulumi:"deductible"`
+}
+
+// expense is a charge before the rules are applied, with what the rules
+// need to know about it
+type expense struct {
+	ExpenseItem
+	visitType resources.VisitType
+}
+
+//go:embed data/expense_rules.json
+var expenseRulesJSON []byte
+
+var defaultExpenseRules = mustLoadExpenseRules()
+
+func mustLoadExpenseRules() []ExpenseRule {
+	var rules []ExpenseRule
+	if err := json.Unmarshal(expenseRulesJSON, &rules); err != nil {
+		panic(fmt.Sprintf("embedded expense rules: %v", err))
+	}
+	return rules
+}
+
+var expenseCategories = map[string]bool{"vet": true, "wellness": true, "training": true, "equipment": true, "insurance": true}
+
+func (ServiceDogExpenseReport) Call(ctx context.Context, args ServiceDogExpenseReportArgs) (ServiceDogExpenseReportResult, error) {
+	result := ServiceDogExpenseReportResult{DogID: args.DogID, TaxYear: args.TaxYear}
+	if args.TaxYear < 2000 || args.TaxYear > 2100 {
+		return result, errors.New("taxYear must be a year between 2000 and 2100")
+	}
+	rules := defaultExpenseRules
+	if args.Rules != nil {
+		rules = args.Rules
+	}
+	for i, rule := range rules {
+		if !expenseCategories[rule.Category] {
+			return result, fmt.Errorf("rules[%d]: unknown category %q", i, rule.Category)
+		}
+		if rule.Percent < 0 || rule.Percent > 100 {
+			return result, fmt.Errorf("rules[%d]: percent must be between 0 and 100", i)
+		}
+	}
+
+	var dog resources.DogState
+	if _, err := registry.Load(ctx, "dog", args.DogID, &dog); err != nil {
+		return result, fmt.Errorf("dog %q: %w", args.DogID, err)
+	}
+	result.DogName = dog.Name
+
+	from := time.Date(args.TaxYear, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+	if now := registry.Now(ctx); now.Before(to) {
+		to = now
+	}
+
+	var items []expense
+	var visits []resources.VeterinaryVisitState
+	if err := listForDog(ctx, "visit", args.DogID, &visits); err != nil {
+		return result, err
+	}
+	items = append(items, visitExpenses(visits, from, to)...)
+
+	var plans []resources.WellnessPlanState
+	if err := listForDog(ctx, "wellness", args.DogID, &plans); err != nil {
+		return result, err
+	}
+	for _, plan := range plans {
+		items = append(items, monthlyCharges("wellness", fmt.Sprintf("%s wellness plan", plan.Tier), plan.ID, plan.StartDate, plan.MonthlyFee, from, to)...)
+	}
+
+	var policies []resources.PetInsuranceState
+	if err := listForDog(ctx, "insurance", args.DogID, &policies); err != nil {
+		return result, err
+	}
+	for _, policy := range policies {
+		items = append(items, monthlyCharges("insurance", policy.Insurer+" premium", policy.PolicyNumber, policy.StartDate, policy.DiscountedPremium, from, to)...)
+	}
+
+	var boxes []resources.SubscriptionBoxState
+	if err := listForDog(ctx, "subscription", args.DogID, &boxes); err != nil {
+		return result, err
+	}
+	for _, box := range boxes {
+		items = append(items, boxExpenses(box, from, to)...)
+	}
+
+	return expenseReport(result, items, rules)
+}
+
+// expenseReport applies the rules to the charges and totals them up
+func expenseReport(result ServiceDogExpenseReportResult, charges []expense, rules []ExpenseRule) (ServiceDogExpenseReportResult, error) {
+	sort.SliceStable(charges, func(i, j int) bool {
+		if charges[i].Date != charges[j].Date {
+			return charges[i].Date < charges[j].Date
+		}
+		return charges[i].Source < charges[j].Source
+	})
+	totals := map[string]*CategoryTotal{}
+	result.Items = []ExpenseItem{}
+	for _, e := range charges {
+		item := e.ExpenseItem
+		item.Percent = expensePercent(rules, e)
+		item.Deductible = round2(item.Amount * item.Percent / 100)
+		result.Items = append(result.Items, item)
+
+		total, ok := totals[item.Category]
+		if !ok {
+			total = &CategoryTotal{Category: item.Category}
+			totals[item.Category] = total
+		}
+		total.Amount = round2(total.Amount + item.Amount)
+		total.Deductible = round2(total.Deductible + item.Deductible)
+		result.TotalAmount = round2(result.TotalAmount + item.Amount)
+		result.TotalDeductible = round2(result.TotalDeductible + item.Deductible)
+	}
+	result.Totals = []CategoryTotal{}
+	for _, total := range totals {
+		result.Totals = append(result.Totals, *total)
+	}
+	sort.Slice(result.Totals, func(i, j int) bool { return result.Totals[i].Category < result.Totals[j].Category })
+
+	var err error
+	result.Csv, err = expenseCSV(result.Items)
+	return result, err
+}
+
+// expensePercent is the deductible share of a charge under the first rule
+// for its category that covers it
+func expensePercent(rules []ExpenseRule, e expense) float64 {
+	for _, rule := range rules {
+		if rule.Category != e.Category {
+			continue
+		}
+		if len(rule.VisitTypes) > 0 && !coversVisit(rule.VisitTypes, e.visitType) {
+			continue
+		}
+		return rule.Percent
+	}
+	return 0
+}
+
+func coversVisit(types []resources.VisitType, visitType resources.VisitType) bool {
+	for _, t := range types {
+		if t == visitType {
+			return true
+		}
+	}
+	return false
+}
+
+// visitExpenses are the costed visits in [from, to)
+func visitExpenses(visits []resources.VeterinaryVisitState, from, to time.Time) []expense {
+	var items []expense
+	for _, visit := range visits {
+		at, err := time.Parse(time.RFC3339, visit.Date)
+		if err != nil || visit.Cost == nil || at.Before(from) || !at.Before(to) {
+			continue
+		}
+		items = append(items, expense{ExpenseItem: ExpenseItem{
+			Date:        at.Format("2006-01-02"),
+			Category:    "vet",
+			Description: fmt.Sprintf("%s visit at %s", visit.VisitType, visit.ClinicName),
+			Source:      visit.ID,
+			Amount:      round2(*visit.Cost),
+		}, visitType: visit.VisitType})
+	}
+	return items
+}
+
+// monthlyCharges bills amount on each monthly anniversary of started that
+// falls in [from, to)
+func monthlyCharges(category, description, source, started string, amount float64, from, to time.Time) []expense {
+	start, err := time.Parse(time.RFC3339, started)
+	if err != nil || amount == 0 {
+		return nil
+	}
+	var items []expense
+	for n := 0; ; n++ {
+		charged := start.AddDate(0, n, 0)
+		if !charged.Before(to) {
+			return items
+		}
+		if charged.Before(from) {
+			continue
+		}
+		items = append(items, expense{ExpenseItem: ExpenseItem{
+			Date:        charged.Format("2006-01-02"),
+			Category:    category,
+			Description: description,
+			Source:      source,
+			Amount:      round2(amount),
+		}})
+	}
+}
+
+// boxExpenses bills each subscription box delivered in [from, to)
+func boxExpenses(box resources.SubscriptionBoxState, from, to time.Time) []expense {
+	created, err := time.Parse(time.RFC3339, box.CreatedAt)
+	if err != nil || box.PricePerBox == 0 {
+		return nil
+	}
+	anchor := created.Truncate(24 * time.Hour)
+	if box.StartDate != nil {
+		if start, err := time.Parse("2006-01-02", *box.StartDate); err == nil {
+			anchor = start
+		}
+	}
+	var items []expense
+	for n := 0; ; n++ {
+		var delivered time.Time
+		switch box.Cadence {
+		case resources.CadenceWeekly:
+			delivered = anchor.AddDate(0, 0, 7*n)
+		case resources.CadenceBiweekly:
+			delivered = anchor.AddDate(0, 0, 14*n)
+		default:
+			delivered = anchor.AddDate(0, n, 0)
+		}
+		if !delivered.Before(to) {
+			return items
+		}
+		if delivered.Before(from) {
+			continue
+		}
+		items = append(items, expense{ExpenseItem: ExpenseItem{
+			Date:        delivered.Format("2006-01-02"),
+			Category:    "equipment",
+			Description: fmt.Sprintf("%s subscription box", box.Tier),
+			Source:      box.ID,
+			Amount:      round2(box.PricePerBox),
+		}})
+	}
+}
+
+// expenseCSV renders the items with a header row
+func expenseCSV(items []ExpenseItem) (string, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	rows := [][]string{{"date", "category", "description", "source", "amount", "percent", "deductible"}}
+	for _, item := range items {
+		rows = append(rows, []string{
+			item.Date,
+			item.Category,
+			item.Description,
+			item.Source,
+			strconv.FormatFloat(item.Amount, 'f', 2, 64),
+			strconv.FormatFloat(item.Percent, 'f', -1, 64),
+			strconv.FormatFloat(item.Deductible, 'f', 2, 64),
+		})
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,139 @@
+package functions
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+func TestMonthlyCharges(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		started string
+		to      time.Time
+		dates   []string
+	}{
+		{name: "started the year before", started: "2025-11-15T09:00:00Z", to: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), dates: []string{"2026-01-15", "2026-02-15", "2026-03-15"}},
+		{name: "started mid-year", started: "2026-10-03T09:00:00Z", to: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), dates: []string{"2026-10-03", "2026-11-03", "2026-12-03"}},
+		{name: "started after the year", started: "2027-02-01T09:00:00Z", to: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "unparseable start", started: "soon", to: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dates []string
+			for _, e := range monthlyCharges("wellness", "basic wellness plan", "wellness-rex", tt.started, 25, from, tt.to) {
+				dates = append(dates, e.Date)
+			}
+			if strings.Join(dates, ",") != strings.Join(tt.dates, ",") {
+				t.Errorf("charged on %v, want %v", dates, tt.dates)
+			}
+		})
+	}
+}
+
+func TestBoxExpenses(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	start := "2025-12-20"
+	tests := []struct {
+		cadence resources.Cadence
+		want    int
+	}{
+		{cadence: resources.CadenceWeekly, want: 5},   // Jan 3, 10, 17, 24, 31
+		{cadence: resources.CadenceBiweekly, want: 3}, // Jan 3, 17, 31
+		{cadence: resources.CadenceMonthly, want: 1},  // Jan 20
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.cadence), func(t *testing.T) {
+			var box resources.SubscriptionBoxState
+			box.ID, box.CreatedAt, box.PricePerBox, box.Tier, box.Cadence = "box-rex", "2025-12-01T08:00:00Z", 39.99, resources.TierPremium, tt.cadence
+			box.StartDate = &start
+			items := boxExpenses(box, from, to)
+			if len(items) != tt.want {
+				t.Fatalf("%d deliveries, want %d: %+v", len(items), tt.want, items)
+			}
+			for _, item := range items {
+				if item.Category != "equipment" || item.Amount != 39.99 || item.Date < "2026-01-01" {
+					t.Errorf("item = %+v", item)
+				}
+			}
+		})
+	}
+}
+
+func TestExpenseReport(t *testing.T) {
+	charge := func(date, category string, amount float64, visitType resources.VisitType) expense {
+		return expense{ExpenseItem: ExpenseItem{Date: date, Category: category, Description: category + " charge", Source: category + "-rex", Amount: amount}, visitType: visitType}
+	}
+	charges := []expense{
+		charge("2026-03-02", "vet", 180, resources.VisitEmergency),
+		charge("2026-01-15", "wellness", 45, ""),
+		charge("2026-02-10", "vet", 90, resources.VisitCheckup),
+		charge("2026-01-20", "insurance", 52.5, ""),
+		charge("2026-01-03", "equipment", 39.99, ""),
+	}
+	rules := []ExpenseRule{
+		{Category: "vet", Percent: 100, VisitTypes: []resources.VisitType{resources.VisitEmergency}},
+		{Category: "vet", Percent: 50},
+		{Category: "wellness", Percent: 100},
+		{Category: "equipment", Percent: 100},
+	}
+
+	result, err := expenseReport(ServiceDogExpenseReportResult{DogID: "dog-rex", TaxYear: 2026}, charges, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dates []string
+	for _, item := range result.Items {
+		dates = append(dates, item.Date)
+	}
+	if strings.Join(dates, ",") != "2026-01-03,2026-01-15,2026-01-20,2026-02-10,2026-03-02" {
+		t.Errorf("items in order %v", dates)
+	}
+	deductible := map[string]float64{}
+	for _, item := range result.Items {
+		deductible[item.Date] = item.Deductible
+	}
+	tests := []struct {
+		date string
+		want float64
+	}{
+		{date: "2026-03-02", want: 180}, // emergencies are fully covered
+		{date: "2026-02-10", want: 45},  // other visits fall through to the 50% rule
+		{date: "2026-01-20", want: 0},   // no rule for insurance
+		{date: "2026-01-03", want: 39.99},
+	}
+	for _, tt := range tests {
+		if deductible[tt.date] != tt.want {
+			t.Errorf("deductible on %s = %v, want %v", tt.date, deductible[tt.date], tt.want)
+		}
+	}
+	if result.TotalAmount != 407.49 || result.TotalDeductible != 309.99 {
+		t.Errorf("totals = %v / %v, want 407.49 / 309.99", result.TotalAmount, result.TotalDeductible)
+	}
+	if len(result.Totals) != 4 || result.Totals[0].Category != "equipment" || result.Totals[3] != (CategoryTotal{Category: "wellness", Amount: 45, Deductible: 45}) {
+		t.Errorf("category totals = %+v", result.Totals)
+	}
+
+	lines := strings.Split(strings.TrimSpace(result.Csv), "\n")
+	if len(lines) != 6 || lines[0] != "date,category,description,source,amount,percent,deductible" {
+		t.Fatalf("csv = %q", result.Csv)
+	}
+	if lines[5] != "2026-03-02,vet,vet charge,vet-rex,180.00,100,180.00" {
+		t.Errorf("last row = %q", lines[5])
+	}
+}
+
+func TestDefaultExpenseRules(t *testing.T) {
+	for _, rule := range defaultExpenseRules {
+		if !expenseCategories[rule.Category] {
+			t.Errorf("default rule for unknown category %q", rule.Category)
+		}
+	}
+	if len(defaultExpenseRules) != len(expenseCategories) {
+		t.Errorf("%d default rules for %d categories", len(defaultExpenseRules), len(expenseCategories))
+	}
+}
@@ -0,0 +1,70 @@
+package functions
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestPetFactsDataset(t *testing.T) {
+	if len(petFacts) == 0 {
+		t.Fatal("embedded dataset is empty")
+	}
+	for i, fact := range petFacts {
+		if fact.Species == "" || fact.Fact == "" || fact.Source == "" {
+			t.Errorf("fact %d is incomplete: %+v", i, fact)
+		}
+	}
+}
+
+func TestRandomPetFact(t *testing.T) {
+	tests := []struct {
+		name        string
+		species     *string
+		wantSpecies string
+		wantErr     string
+	}{
+		{name: "any species"},
+		{name: "dog", species: stringPtr("dog"), wantSpecies: "dog"},
+		{name: "case insensitive", species: stringPtr("Cat"), wantSpecies: "cat"},
+		{name: "unknown", species: stringPtr("dragon"), wantErr: `no facts about "dragon", expected one of: cat, dog, rabbit`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seed := int64(42)
+			got, err := RandomPetFact{}.Call(context.Background(), RandomPetFactArgs{Species: tt.species, Seed: &seed})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Fact == "" || got.Source == "" {
+				t.Errorf("result = %+v, want a fact with its source", got)
+			}
+			if tt.wantSpecies != "" && got.Species != tt.wantSpecies {
+				t.Errorf("species = %q, want %q", got.Species, tt.wantSpecies)
+			}
+
+			again, _ := RandomPetFact{}.Call(context.Background(), RandomPetFactArgs{Species: tt.species, Seed: &seed})
+			if again != got {
+				t.Errorf("same seed gave %+v, then %+v", got, again)
+			}
+		})
+	}
+}
+
+func TestPickFactReachesEveryFact(t *testing.T) {
+	seen := map[string]bool{}
+	for seed := int64(0); seed < 1000; seed++ {
+		seen[pickFact(petFacts, seed).Fact] = true
+	}
+	if len(seen) != len(petFacts) {
+		t.Errorf("1000 seeds reached %d of %d facts", len(seen), len(petFacts))
+	}
+}
+
+func stringPtr(v string) *string { return &v }
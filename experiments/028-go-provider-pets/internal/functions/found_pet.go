@@ -0,0 +1,24 @@
+package functions
+
+import (
+	"context"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// MatchFoundPet looks for a found dog among the open LostPetReports. It
+// scores each reported dog on breed, size, coat color and how far from
+// where it was last seen the dog turned up, and returns the candidates
+// best first with the reasons for each. A scanned microchip settles it:
+// the dog chipped with that number scores 100 and dogs chipped with
+// another are left out.
+type MatchFoundPet struct{}
+
+type MatchFoundPetResult struct {
+	Candidates []resources.FoundPetMatch `pulumi:"candidates"`
+}
+
+func (MatchFoundPet) Call(ctx context.Context, args resources.FoundPet) (MatchFoundPetResult, error) {
+	candidates, err := resources.MatchFoundPet(ctx, args)
+	return MatchFoundPetResult{Candidates: candidates}, err
+}
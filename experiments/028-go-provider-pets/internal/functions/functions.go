@@ -0,0 +1,2 @@
+// Package functions implements the invokes of the pets provider.
+package functions
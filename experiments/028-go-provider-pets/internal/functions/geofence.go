@@ -0,0 +1,45 @@
+package functions
+
+import (
+	"context"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// CheckGeofence locates a GpsCollar now and tells whether its dog is inside
+// one of its geofences. A dog that has just left them all, or come back,
+// is recorded in the collar's audit log and announced to the subscribed
+// NotificationChannels, exactly as a refresh of the collar would.
+type CheckGeofence struct{}
+
+type CheckGeofenceArgs struct {
+	CollarID string `pulumi:"collarId"`
+}
+
+type CheckGeofenceResult struct {
+	CollarID     string            `pulumi:"collarId"`
+	DogID        string            `pulumi:"dogId"`
+	Location     *resources.LatLng `pulumi:"location,optional"` // unset until the collar has reported a position
+	SeenAt       string            `pulumi:"seenAt"`
+	CurrentFence string            `pulumi:"currentFence"` // empty when outside every fence
+	Breached     bool              `pulumi:"breached"`
+	BatteryLevel int               `pulumi:"batteryLevel"` // percent
+	Online       bool              `pulumi:"online"`
+}
+
+func (CheckGeofence) Call(ctx context.Context, args CheckGeofenceArgs) (CheckGeofenceResult, error) {
+	collar, err := resources.CheckGeofence(ctx, args.CollarID)
+	if err != nil {
+		return CheckGeofenceResult{}, err
+	}
+	return CheckGeofenceResult{
+		CollarID:     collar.ID,
+		DogID:        collar.DogID,
+		Location:     collar.LastLocation,
+		SeenAt:       collar.LastSeenAt,
+		CurrentFence: collar.CurrentFence,
+		Breached:     collar.Breached,
+		BatteryLevel: collar.BatteryLevel,
+		Online:       collar.Online,
+	}, nil
+}
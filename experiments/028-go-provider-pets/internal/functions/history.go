@@ -0,0 +1,71 @@
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// GetFullHistory returns every behavior note and medical history entry of a
+// dog; the Dog resource only carries the most recent ones. Both lists are
+// paged together, oldest first: each page holds up to pageSize entries of
+// each, and paging continues until both are exhausted.
+type GetFullHistory struct{}
+
+type GetFullHistoryArgs struct {
+	PageArgs
+	DogID string `pulumi:"dogId"`
+}
+
+type GetFullHistoryResult struct {
+	DogID          string                   `pulumi:"dogId"`
+	BehaviorNotes  []resources.BehaviorNote `pulumi:"behaviorNotes"`
+	MedicalHistory []string                 `pulumi:"medicalHistory"`
+	NextPageToken  string                   `pulumi:"nextPageToken"`
+}
+
+func (GetFullHistory) Call(ctx context.Context, args GetFullHistoryArgs) (GetFullHistoryResult, error) {
+	result := GetFullHistoryResult{DogID: args.DogID}
+	size, err := args.size()
+	if err != nil {
+		return result, err
+	}
+	offset, err := decodeOffset(args.PageToken)
+	if err != nil {
+		return result, err
+	}
+
+	history, err := loadHistory(ctx, args.DogID)
+	if err != nil {
+		return result, err
+	}
+
+	result.BehaviorNotes = window(history.BehaviorNotes, offset, size)
+	result.MedicalHistory = window(history.MedicalHistory, offset, size)
+	if end := offset + size; end < len(history.BehaviorNotes) || end < len(history.MedicalHistory) {
+		result.NextPageToken = encodeOffset(end)
+	}
+	return result, nil
+}
+
+// loadHistory reads a dog's complete histories
+func loadHistory(ctx context.Context, dogID string) (resources.DogHistory, error) {
+	var history resources.DogHistory
+	_, err := registry.Load(ctx, "dog-history", dogID, &history)
+	if errors.Is(err, backend.ErrNotFound) {
+		// Dogs not updated since histories were split out still keep
+		// theirs in full on the dog record
+		var dog resources.DogState
+		if _, err := registry.Load(ctx, "dog", dogID, &dog); err != nil {
+			return history, fmt.Errorf("dog %s: %w", dogID, err)
+		}
+		history = resources.DogHistory{BehaviorNotes: dog.BehaviorNotes, MedicalHistory: dog.MedicalHistory}
+	} else if err != nil {
+		return history, err
+	}
+	return history, nil
+}
@@ -0,0 +1,322 @@
+package functions
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// ComparePolicies quotes a dog with every insurer in the embedded rate
+// tables, ranked from the lowest annual cost. Each quote is for the plan
+// closest to the coverage asked for and says where it falls short or goes
+// beyond it, so a program can pick the PetInsurance inputs from the data.
+type ComparePolicies struct{}
+
+type ComparePoliciesArgs struct {
+	Breed         resources.DogBreed      `pulumi:"breed"`
+	Age           int                     `pulumi:"age"`                    // years
+	CoverageType  *resources.CoverageType `pulumi:"coverageType,optional"`  // defaults to accident-illness
+	Deductible    *int                    `pulumi:"deductible,optional"`    // dollars a year; defaults to 500
+	Reimbursement *int                    `pulumi:"reimbursement,optional"` // percent of the vet bill paid back; defaults to 80
+	AnnualLimit   *int                    `pulumi:"annualLimit,optional"`   // dollars a year, 0 for unlimited; defaults to 10000
+	Wellness      *bool                   `pulumi:"wellness,optional"`      // add routine care such as checkups and vaccinations
+}
+
+type ComparePoliciesResult struct {
+	Quotes   []PolicyQuote `pulumi:"quotes"`   // cheapest first
+	Declined []string      `pulumi:"declined"` // insurers that won't cover the dog, and why
+}
+
+// PolicyQuote is one insurer's price for the plan closest to the coverage
+// asked for
+type PolicyQuote struct {
+	Rank           int                    `pulumi:"rank"`
+	Insurer        string                 `pulumi:"insurer"`
+	CoverageType   resources.CoverageType `pulumi:"coverageType"`
+	Deductible     int                    `pulumi:"deductible"`
+	Reimbursement  int                    `pulumi:"reimbursement"`
+	AnnualLimit    int                    `pulumi:"annualLimit"` // 0 for unlimited
+	Wellness       bool                   `pulumi:"wellness"`
+	MonthlyPremium float64                `pulumi:"monthlyPremium"`
+	AnnualCost     float64                `pulumi:"annualCost"`
+	Exact          bool                   `pulumi:"exact"`       // the plan has exactly the coverage asked for
+	Differences    []string               `pulumi:"differences"` // how the plan differs from the coverage asked for
+	Notes          []string               `pulumi:"notes"`       // exclusions and waiting periods worth knowing
+}
+
+//go:embed data/insurers.json
+var insurersJSON []byte
+
+// insurerRates is an insurer's rate table. Option maps are keyed by the
+// option and hold the factor it applies to the premium.
+type insurerRates struct {
+	Insurer           string                             `json:"insurer"`
+	MonthlyBase       map[resources.CoverageType]float64 `json:"monthlyBase"`
+	BreedFactors      map[resources.DogBreed]float64     `json:"breedFactors"`
+	AgeRate           float64                            `json:"ageRate"`      // added to the premium per year of age after the first
+	MaxEnrollAge      int                                `json:"maxEnrollAge"` // 0 for no limit
+	Deductibles       map[int]float64                    `json:"deductibles"`
+	Reimbursements    map[int]float64                    `json:"reimbursements"`
+	AnnualLimits      map[int]float64                    `json:"annualLimits"` // 0 for unlimited
+	WellnessMonthly   float64                            `json:"wellnessMonthly"`
+	Hereditary        bool                               `json:"hereditary"`
+	WaitingPeriodDays int                                `json:"waitingPeriodDays"`
+}
+
+var insurers = mustLoadInsurers()
+
+func mustLoadInsurers() []insurerRates {
+	var rates []insurerRates
+	if err := json.Unmarshal(insurersJSON, &rates); err != nil {
+		panic(fmt.Sprintf("embedded insurer rates: %v", err))
+	}
+	return rates
+}
+
+const (
+	defaultDeductible    = 500
+	defaultReimbursement = 80
+	defaultAnnualLimit   = 10000
+	// usualWaitingDays is the illness waiting period most policies have;
+	// longer ones are noted
+	usualWaitingDays = 14
+)
+
+// coverageOrder ranks coverage types from the least to the most
+var coverageOrder = []resources.CoverageType{
+	resources.CoverageAccidentOnly,
+	resources.CoverageAccidentIllness,
+	resources.CoverageComprehensive,
+}
+
+func (ComparePolicies) Call(ctx context.Context, args ComparePoliciesArgs) (ComparePoliciesResult, error) {
+	want := policyTerms{
+		Coverage:      resources.CoverageAccidentIllness,
+		Deductible:    defaultDeductible,
+		Reimbursement: defaultReimbursement,
+		AnnualLimit:   defaultAnnualLimit,
+	}
+	if args.CoverageType != nil {
+		want.Coverage = *args.CoverageType
+	}
+	if args.Deductible != nil {
+		want.Deductible = *args.Deductible
+	}
+	if args.Reimbursement != nil {
+		want.Reimbursement = *args.Reimbursement
+	}
+	if args.AnnualLimit != nil {
+		want.AnnualLimit = *args.AnnualLimit
+	}
+	if args.Wellness != nil {
+		want.Wellness = *args.Wellness
+	}
+
+	switch {
+	case args.Breed == "":
+		return ComparePoliciesResult{}, errors.New("breed is required")
+	case args.Age < 0 || args.Age > 30:
+		return ComparePoliciesResult{}, errors.New("age must be between 0 and 30")
+	case coverageRank(want.Coverage) < 0:
+		return ComparePoliciesResult{}, fmt.Errorf("coverageType must be accident-only, accident-illness or comprehensive, got %q", want.Coverage)
+	case want.Deductible < 0:
+		return ComparePoliciesResult{}, errors.New("deductible must be at least 0")
+	case want.Reimbursement < 1 || want.Reimbursement > 100:
+		return ComparePoliciesResult{}, errors.New("reimbursement must be a percentage between 1 and 100")
+	case want.AnnualLimit < 0:
+		return ComparePoliciesResult{}, errors.New("annualLimit must be at least 0")
+	}
+
+	return comparePolicies(insurers, args.Breed, args.Age, want), nil
+}
+
+// policyTerms is the coverage asked for or quoted
+type policyTerms struct {
+	Coverage      resources.CoverageType
+	Deductible    int
+	Reimbursement int
+	AnnualLimit   int
+	Wellness      bool
+}
+
+// comparePolicies quotes every insurer willing to cover the dog, cheapest
+// first
+func comparePolicies(rates []insurerRates, breed resources.DogBreed, age int, want policyTerms) ComparePoliciesResult {
+	result := ComparePoliciesResult{Quotes: []PolicyQuote{}, Declined: []string{}}
+	for _, insurer := range rates {
+		if insurer.MaxEnrollAge > 0 && age > insurer.MaxEnrollAge {
+			result.Declined = append(result.Declined, fmt.Sprintf("%s: does not enroll dogs older than %d", insurer.Insurer, insurer.MaxEnrollAge))
+			continue
+		}
+		result.Quotes = append(result.Quotes, quotePolicy(insurer, breed, age, want))
+	}
+	sort.SliceStable(result.Quotes, func(i, j int) bool {
+		if result.Quotes[i].AnnualCost != result.Quotes[j].AnnualCost {
+			return result.Quotes[i].AnnualCost < result.Quotes[j].AnnualCost
+		}
+		return result.Quotes[i].Insurer < result.Quotes[j].Insurer
+	})
+	for i := range result.Quotes {
+		result.Quotes[i].Rank = i + 1
+	}
+	return result
+}
+
+// quotePolicy prices the insurer's plan closest to the coverage asked for
+func quotePolicy(insurer insurerRates, breed resources.DogBreed, age int, want policyTerms) PolicyQuote {
+	got := policyTerms{
+		Coverage:      closestCoverage(insurer.MonthlyBase, want.Coverage),
+		Deductible:    closestOption(insurer.Deductibles, want.Deductible),
+		Reimbursement: closestOption(insurer.Reimbursements, want.Reimbursement),
+		AnnualLimit:   closestLimit(insurer.AnnualLimits, want.AnnualLimit),
+		Wellness:      want.Wellness && insurer.WellnessMonthly > 0,
+	}
+
+	monthly := insurer.MonthlyBase[got.Coverage]
+	if factor, ok := insurer.BreedFactors[breed]; ok {
+		monthly *= factor
+	}
+	monthly *= 1 + insurer.AgeRate*math.Max(0, float64(age-1))
+	monthly *= insurer.Deductibles[got.Deductible] * insurer.Reimbursements[got.Reimbursement] * insurer.AnnualLimits[got.AnnualLimit]
+	if got.Wellness {
+		monthly += insurer.WellnessMonthly
+	}
+	monthly = round2(monthly)
+
+	differences := policyDifferences(want, got)
+	if want.Wellness && !got.Wellness {
+		differences = append(differences, "no wellness add-on")
+	}
+	var notes []string
+	if !insurer.Hereditary && got.Coverage != resources.CoverageAccidentOnly {
+		notes = append(notes, "excludes hereditary conditions")
+	}
+	if insurer.WaitingPeriodDays > usualWaitingDays && got.Coverage != resources.CoverageAccidentOnly {
+		notes = append(notes, fmt.Sprintf("%d-day waiting period for illnesses", insurer.WaitingPeriodDays))
+	}
+
+	return PolicyQuote{
+		Insurer:        insurer.Insurer,
+		CoverageType:   got.Coverage,
+		Deductible:     got.Deductible,
+		Reimbursement:  got.Reimbursement,
+		AnnualLimit:    got.AnnualLimit,
+		Wellness:       got.Wellness,
+		MonthlyPremium: monthly,
+		AnnualCost:     round2(monthly * 12),
+		Exact:          len(differences) == 0,
+		Differences:    differences,
+		Notes:          notes,
+	}
+}
+
+// policyDifferences describes where the quoted terms differ from the ones
+// asked for
+func policyDifferences(want, got policyTerms) []string {
+	differences := []string{}
+	if got.Coverage != want.Coverage {
+		differences = append(differences, fmt.Sprintf("no %s plan; quoted %s", want.Coverage, got.Coverage))
+	}
+	if got.Deductible != want.Deductible {
+		differences = append(differences, fmt.Sprintf("%s deductible instead of %s", dollars(got.Deductible), dollars(want.Deductible)))
+	}
+	if got.Reimbursement != want.Reimbursement {
+		differences = append(differences, fmt.Sprintf("%d%% reimbursement instead of %d%%", got.Reimbursement, want.Reimbursement))
+	}
+	if got.AnnualLimit != want.AnnualLimit {
+		differences = append(differences, fmt.Sprintf("%s annual limit instead of %s", limitText(got.AnnualLimit), limitText(want.AnnualLimit)))
+	}
+	return differences
+}
+
+func coverageRank(coverage resources.CoverageType) int {
+	for i, c := range coverageOrder {
+		if c == coverage {
+			return i
+		}
+	}
+	return -1
+}
+
+// closestCoverage is the coverage asked for when the insurer sells it, else
+// the most it sells short of that, else the least it sells beyond it
+func closestCoverage(offered map[resources.CoverageType]float64, want resources.CoverageType) resources.CoverageType {
+	rank := coverageRank(want)
+	for i := rank; i >= 0; i-- {
+		if _, ok := offered[coverageOrder[i]]; ok {
+			return coverageOrder[i]
+		}
+	}
+	for i := rank + 1; i < len(coverageOrder); i++ {
+		if _, ok := offered[coverageOrder[i]]; ok {
+			return coverageOrder[i]
+		}
+	}
+	return want
+}
+
+// closestOption is the offered option nearest want, the lower one on a tie
+func closestOption(offered map[int]float64, want int) int {
+	best, found := 0, false
+	for option := range offered {
+		d, bestD := abs(option-want), abs(best-want)
+		if !found || d < bestD || (d == bestD && option < best) {
+			best, found = option, true
+		}
+	}
+	return best
+}
+
+// closestLimit is the lowest offered annual limit that covers want, or the
+// highest there is when none does. 0 is unlimited.
+func closestLimit(offered map[int]float64, want int) int {
+	ceiling := func(limit int) int {
+		if limit == 0 {
+			return math.MaxInt
+		}
+		return limit
+	}
+	best, found := 0, false
+	for limit := range offered {
+		covers := ceiling(limit) >= ceiling(want)
+		bestCovers := found && ceiling(best) >= ceiling(want)
+		switch {
+		case !found,
+			covers && !bestCovers,
+			covers && ceiling(limit) < ceiling(best),
+			!covers && !bestCovers && ceiling(limit) > ceiling(best):
+			best, found = limit, true
+		}
+	}
+	return best
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func limitText(limit int) string {
+	if limit == 0 {
+		return "unlimited"
+	}
+	return dollars(limit)
+}
+
+// dollars formats a whole amount with thousands separators, e.g. $10,000
+func dollars(amount int) string {
+	s := strconv.Itoa(amount)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return "$" + s
+}
@@ -0,0 +1,134 @@
+package functions
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+func TestClosestOption(t *testing.T) {
+	deductibles := map[int]float64{250: 1.2, 500: 1, 1000: 0.8}
+	tests := []struct {
+		want int
+		got  int
+	}{
+		{want: 500, got: 500},
+		{want: 300, got: 250},
+		{want: 750, got: 500}, // a tie takes the lower one
+		{want: 5000, got: 1000},
+		{want: 0, got: 250},
+	}
+	for _, tt := range tests {
+		if got := closestOption(deductibles, tt.want); got != tt.got {
+			t.Errorf("closestOption(%d) = %d, want %d", tt.want, got, tt.got)
+		}
+	}
+}
+
+func TestClosestLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		offered map[int]float64
+		want    int
+		got     int
+	}{
+		{name: "exact", offered: map[int]float64{5000: 1, 10000: 1, 0: 1}, want: 10000, got: 10000},
+		{name: "next one up", offered: map[int]float64{5000: 1, 15000: 1}, want: 10000, got: 15000},
+		{name: "unlimited covers anything", offered: map[int]float64{5000: 1, 0: 1}, want: 10000, got: 0},
+		{name: "nothing covers it", offered: map[int]float64{5000: 1, 15000: 1}, want: 20000, got: 15000},
+		{name: "unlimited wanted", offered: map[int]float64{5000: 1, 15000: 1}, want: 0, got: 15000},
+		{name: "unlimited offered", offered: map[int]float64{10000: 1, 0: 1}, want: 0, got: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := closestLimit(tt.offered, tt.want); got != tt.got {
+				t.Errorf("closestLimit(%d) = %d, want %d", tt.want, got, tt.got)
+			}
+		})
+	}
+}
+
+func TestComparePolicies(t *testing.T) {
+	rates := []insurerRates{
+		{
+			Insurer:        "Steady",
+			MonthlyBase:    map[resources.CoverageType]float64{resources.CoverageAccidentIllness: 40, resources.CoverageComprehensive: 60},
+			BreedFactors:   map[resources.DogBreed]float64{resources.Bulldog: 1.5},
+			AgeRate:        0.1,
+			Deductibles:    map[int]float64{500: 1},
+			Reimbursements: map[int]float64{80: 1},
+			AnnualLimits:   map[int]float64{10000: 1},
+			Hereditary:     true,
+		},
+		{
+			Insurer:           "Budget",
+			MonthlyBase:       map[resources.CoverageType]float64{resources.CoverageAccidentIllness: 30},
+			AgeRate:           0.2,
+			MaxEnrollAge:      8,
+			Deductibles:       map[int]float64{1000: 0.9},
+			Reimbursements:    map[int]float64{70: 0.9},
+			AnnualLimits:      map[int]float64{5000: 0.9},
+			WaitingPeriodDays: 30,
+		},
+		{
+			Insurer:        "Lavish",
+			MonthlyBase:    map[resources.CoverageType]float64{resources.CoverageAccidentIllness: 50},
+			AgeRate:        0.05,
+			Deductibles:    map[int]float64{500: 1},
+			Reimbursements: map[int]float64{80: 1},
+			AnnualLimits:   map[int]float64{0: 1.2},
+			Hereditary:     true,
+		},
+	}
+	want := policyTerms{Coverage: resources.CoverageAccidentIllness, Deductible: 500, Reimbursement: 80, AnnualLimit: 10000}
+
+	// A 3-year-old beagle: Budget 30*1.4*0.9^3 = 30.62, Steady 40*1.2 = 48,
+	// Lavish 50*1.1*1.2 = 66
+	result := comparePolicies(rates, resources.Beagle, 3, want)
+	var order []string
+	for _, q := range result.Quotes {
+		order = append(order, q.Insurer)
+	}
+	if !reflect.DeepEqual(order, []string{"Budget", "Steady", "Lavish"}) {
+		t.Fatalf("ranking = %v", order)
+	}
+	budget, steady, lavish := result.Quotes[0], result.Quotes[1], result.Quotes[2]
+	if budget.MonthlyPremium != 30.62 || budget.AnnualCost != 367.44 || budget.Rank != 1 {
+		t.Errorf("budget quote = %+v", budget)
+	}
+	if budget.Exact || len(budget.Differences) != 3 {
+		t.Errorf("budget differences = %q, want deductible, reimbursement and limit", budget.Differences)
+	}
+	if !reflect.DeepEqual(budget.Notes, []string{"excludes hereditary conditions", "30-day waiting period for illnesses"}) {
+		t.Errorf("budget notes = %q", budget.Notes)
+	}
+	if !steady.Exact || steady.MonthlyPremium != 48 {
+		t.Errorf("steady quote = %+v, want an exact match at 48", steady)
+	}
+	if !reflect.DeepEqual(lavish.Differences, []string{"unlimited annual limit instead of $10,000"}) {
+		t.Errorf("lavish differences = %q", lavish.Differences)
+	}
+
+	// Too old for Budget, and only Steady sells comprehensive cover. The
+	// ranking is by cost alone, so Lavish's lesser plan still comes first.
+	want.Coverage = resources.CoverageComprehensive
+	result = comparePolicies(rates, resources.Bulldog, 9, want)
+	if !reflect.DeepEqual(result.Declined, []string{"Budget: does not enroll dogs older than 8"}) {
+		t.Errorf("declined = %q", result.Declined)
+	}
+	if len(result.Quotes) != 2 || result.Quotes[1].Insurer != "Steady" || !result.Quotes[1].Exact {
+		t.Fatalf("quotes = %+v, want an exact Steady quote second", result.Quotes)
+	}
+	if got := result.Quotes[0].Differences[0]; got != "no comprehensive plan; quoted accident-illness" {
+		t.Errorf("lavish difference = %q", got)
+	}
+}
+
+func TestDollars(t *testing.T) {
+	for amount, want := range map[int]string{0: "$0", 500: "$500", 1000: "$1,000", 10000: "$10,000", 1250000: "$1,250,000"} {
+		if got := dollars(amount); got != want {
+			t.Errorf("dollars(%d) = %q, want %q", amount, got, want)
+		}
+	}
+}
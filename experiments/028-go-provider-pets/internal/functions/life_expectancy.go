@@ -0,0 +1,212 @@
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// EstimateLifeExpectancy estimates how long a dog will live, starting
+// from its breed's median lifespan and adjusting for its body condition
+// and weight trend, as weightTrendAnalysis assesses them, and for the
+// emergencies and surgeries in its medical history. The estimate comes
+// with a rough 80% interval, which widens when there are no weigh-ins or
+// vet visits to go by. A registered dog is assessed from its records; a
+// prospective one from its breed, age and what is known about it.
+type EstimateLifeExpectancy struct{}
+
+type EstimateLifeExpectancyArgs struct {
+	DogID           *string             `pulumi:"dogId,optional"`
+	Breed           *resources.DogBreed `pulumi:"breed,optional"`           // With age, instead of dogId
+	Age             *float64            `pulumi:"age,optional"`             // In years
+	Weight          *float64            `pulumi:"weight,optional"`          // In pounds; the breed's typical weight when unset
+	EmergencyVisits *int                `pulumi:"emergencyVisits,optional"` // Emergency vet visits so far
+	Surgeries       *int                `pulumi:"surgeries,optional"`       // Surgeries so far
+}
+
+type EstimateLifeExpectancyResult struct {
+	DogID          string   `pulumi:"dogId"`
+	Baseline       float64  `pulumi:"baseline"`       // The breed's median lifespan in years
+	EstimateYears  float64  `pulumi:"estimateYears"`  // Expected lifespan in years
+	Low            float64  `pulumi:"low"`            // Lower end of the 80% interval
+	High           float64  `pulumi:"high"`           // Upper end of the 80% interval
+	RemainingYears float64  `pulumi:"remainingYears"` // estimateYears less the dog's age
+	Adjustments    []string `pulumi:"adjustments"`    // What moved the estimate off the baseline, and by how much
+}
+
+// lifeHistory is what the estimate goes by about a dog
+type lifeHistory struct {
+	breed       resources.DogBreed
+	age         float64
+	condition   string // body condition, as bodyCondition grades it
+	trend       string // weight trend, as weightTrend assesses it
+	emergencies int
+	surgeries   int
+	visits      bool // whether there is a medical history to go by
+}
+
+const (
+	// defaultLifeExpectancy is the baseline for a breed missing from the
+	// catalog
+	defaultLifeExpectancy = 12.0
+	// lifeIntervalYears is the half-width of the 80% interval with full
+	// records; each missing kind of record widens it by lifeUnknownYears
+	lifeIntervalYears = 1.5
+	lifeUnknownYears  = 0.75
+	// minRemainingYears keeps the estimate ahead of an old dog's age
+	minRemainingYears = 0.5
+	// maxMedicalPenalty caps what the medical history can take off
+	maxMedicalPenalty = 3.0
+)
+
+// Years taken off for each body condition, more when the trend makes it
+// worse, and for each serious visit
+var (
+	conditionPenalty = map[string]float64{"underweight": 1, "ideal": 0, "overweight": 1, "obese": 2.5}
+	worseningTrend   = map[string]string{"underweight": "losing", "overweight": "gaining", "obese": "gaining"}
+)
+
+const (
+	worseningPenalty = 0.5
+	emergencyPenalty = 0.5
+	surgeryPenalty   = 0.25
+)
+
+func (EstimateLifeExpectancy) Call(ctx context.Context, args EstimateLifeExpectancyArgs) (EstimateLifeExpectancyResult, error) {
+	if args.DogID == nil {
+		history, err := describedLife(args)
+		if err != nil {
+			return EstimateLifeExpectancyResult{}, err
+		}
+		return lifeExpectancy(history), nil
+	}
+	if args.Breed != nil || args.Age != nil || args.Weight != nil || args.EmergencyVisits != nil || args.Surgeries != nil {
+		return EstimateLifeExpectancyResult{}, errors.New("give either dogId or the dog's attributes, not both")
+	}
+	history, err := recordedLife(ctx, *args.DogID)
+	if err != nil {
+		return EstimateLifeExpectancyResult{}, err
+	}
+	result := lifeExpectancy(history)
+	result.DogID = *args.DogID
+	return result, nil
+}
+
+// recordedLife gathers a registered dog's age, weigh-ins and vet visits
+func recordedLife(ctx context.Context, dogID string) (lifeHistory, error) {
+	age, err := resources.DogAgeInHumanYears(ctx, resources.DogAge{DogID: &dogID})
+	if err != nil {
+		return lifeHistory{}, err
+	}
+	var dog resources.DogState
+	if _, err := registry.Load(ctx, "dog", dogID, &dog); err != nil {
+		return lifeHistory{}, fmt.Errorf("dog %s: %w", dogID, err)
+	}
+	var logs []resources.WeightLogState
+	if err := listForDog(ctx, "weight", dogID, &logs); err != nil {
+		return lifeHistory{}, err
+	}
+	since := registry.Now(ctx).AddDate(0, 0, -defaultTrendWindowDays).Format("2006-01-02")
+	var recent []resources.WeightLogState
+	for _, entry := range logs {
+		if entry.MeasuredOn >= since {
+			recent = append(recent, entry)
+		}
+	}
+	weight := weightTrend(recent, dog.CurrentWeight, resources.EstimateWeightByBreed(dog.Breed))
+
+	var visits []resources.VeterinaryVisitState
+	if err := listForDog(ctx, "visit", dogID, &visits); err != nil {
+		return lifeHistory{}, err
+	}
+	history := lifeHistory{breed: dog.Breed, age: age.DogAge, condition: weight.BodyCondition, trend: weight.Trend, visits: len(visits) > 0}
+	for _, visit := range visits {
+		switch visit.VisitType {
+		case resources.VisitEmergency:
+			history.emergencies++
+		case resources.VisitSurgery:
+			history.surgeries++
+		}
+	}
+	return history, nil
+}
+
+// describedLife is the history of a dog described by its attributes
+func describedLife(args EstimateLifeExpectancyArgs) (lifeHistory, error) {
+	if args.Breed == nil || args.Age == nil {
+		return lifeHistory{}, errors.New("dogId, or breed and age, are required")
+	}
+	if _, ok := resources.BreedCatalog[*args.Breed]; !ok {
+		return lifeHistory{}, fmt.Errorf("%q is not a known breed", *args.Breed)
+	}
+	if *args.Age < 0 || *args.Age > 30 {
+		return lifeHistory{}, errors.New("age must be between 0 and 30")
+	}
+	history := lifeHistory{breed: *args.Breed, age: *args.Age, condition: "ideal", trend: "insufficient-data"}
+	if args.Weight != nil {
+		if *args.Weight <= 0 {
+			return lifeHistory{}, errors.New("weight must be positive")
+		}
+		history.condition = bodyCondition(*args.Weight, resources.EstimateWeightByBreed(*args.Breed))
+		history.trend = "stable"
+	}
+	for _, count := range []*int{args.EmergencyVisits, args.Surgeries} {
+		if count != nil && *count < 0 {
+			return lifeHistory{}, errors.New("visit counts can't be negative")
+		}
+	}
+	if args.EmergencyVisits != nil {
+		history.emergencies, history.visits = *args.EmergencyVisits, true
+	}
+	if args.Surgeries != nil {
+		history.surgeries, history.visits = *args.Surgeries, true
+	}
+	return history, nil
+}
+
+// lifeExpectancy estimates a lifespan from the history
+func lifeExpectancy(h lifeHistory) EstimateLifeExpectancyResult {
+	baseline := defaultLifeExpectancy
+	if info, ok := resources.BreedCatalog[h.breed]; ok && info.LifeExpectancy > 0 {
+		baseline = info.LifeExpectancy
+	}
+	result := EstimateLifeExpectancyResult{Baseline: baseline, Adjustments: []string{}}
+	estimate := baseline
+
+	if penalty := conditionPenalty[h.condition]; penalty > 0 {
+		if h.trend == worseningTrend[h.condition] {
+			penalty += worseningPenalty
+			result.Adjustments = append(result.Adjustments, fmt.Sprintf("-%g years: %s and still %s", penalty, h.condition, h.trend))
+		} else {
+			result.Adjustments = append(result.Adjustments, fmt.Sprintf("-%g years: %s", penalty, h.condition))
+		}
+		estimate -= penalty
+	}
+	medical := math.Min(maxMedicalPenalty, float64(h.emergencies)*emergencyPenalty+float64(h.surgeries)*surgeryPenalty)
+	if medical > 0 {
+		result.Adjustments = append(result.Adjustments, fmt.Sprintf("-%g years: medical history of %d emergencies and %d surgeries", medical, h.emergencies, h.surgeries))
+		estimate -= medical
+	}
+
+	half := lifeIntervalYears
+	if h.trend == "insufficient-data" {
+		half += lifeUnknownYears
+	}
+	if !h.visits {
+		half += lifeUnknownYears
+	}
+	// A dog that has outlived the estimate has some time yet
+	if floor := h.age + minRemainingYears; estimate < floor {
+		result.Adjustments = append(result.Adjustments, fmt.Sprintf("raised to %g years: the dog is already %g", round2(floor), round2(h.age)))
+		estimate = floor
+	}
+	result.EstimateYears = round2(estimate)
+	result.Low = round2(math.Max(h.age, estimate-half))
+	result.High = round2(estimate + half)
+	result.RemainingYears = round2(estimate - h.age)
+	return result
+}
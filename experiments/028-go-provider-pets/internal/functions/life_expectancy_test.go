@@ -0,0 +1,70 @@
+package functions
+
+import (
+	"testing"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+func TestLifeExpectancy(t *testing.T) {
+	beagle := func(age float64, condition, trend string, emergencies, surgeries int, visits bool) lifeHistory {
+		return lifeHistory{breed: resources.Beagle, age: age, condition: condition, trend: trend, emergencies: emergencies, surgeries: surgeries, visits: visits}
+	}
+	tests := []struct {
+		name                      string
+		history                   lifeHistory
+		estimate, low, high, left float64
+		adjustments               int
+	}{
+		{name: "healthy", history: beagle(4, "ideal", "stable", 0, 0, true), estimate: 13, low: 11.5, high: 14.5, left: 9},
+		{name: "obese and gaining", history: beagle(4, "obese", "gaining", 0, 0, true), estimate: 10, low: 8.5, high: 11.5, left: 6, adjustments: 1},
+		{name: "overweight but losing", history: beagle(4, "overweight", "losing", 0, 0, true), estimate: 12, low: 10.5, high: 13.5, left: 8, adjustments: 1},
+		{name: "medical history", history: beagle(4, "ideal", "stable", 2, 2, true), estimate: 11.5, low: 10, high: 13, left: 7.5, adjustments: 1},
+		{name: "medical penalty capped", history: beagle(4, "ideal", "stable", 10, 0, true), estimate: 10, low: 8.5, high: 11.5, left: 6, adjustments: 1},
+		{name: "nothing on record", history: beagle(4, "ideal", "insufficient-data", 0, 0, false), estimate: 13, low: 10, high: 16, left: 9},
+		{name: "outlived the estimate", history: beagle(14, "ideal", "stable", 0, 0, true), estimate: 14.5, low: 14, high: 16, left: 0.5, adjustments: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lifeExpectancy(tt.history)
+			if got.Baseline != 13 || got.EstimateYears != tt.estimate || got.Low != tt.low || got.High != tt.high || got.RemainingYears != tt.left {
+				t.Errorf("lifeExpectancy = %+v, want %v years (%v-%v), %v left", got, tt.estimate, tt.low, tt.high, tt.left)
+			}
+			if len(got.Adjustments) != tt.adjustments {
+				t.Errorf("adjustments = %q, want %d", got.Adjustments, tt.adjustments)
+			}
+		})
+	}
+}
+
+func TestDescribedLife(t *testing.T) {
+	beagle, wolf := resources.Beagle, resources.DogBreed("wolf")
+	number := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name      string
+		args      EstimateLifeExpectancyArgs
+		condition string
+		trend     string
+		visits    bool
+		err       bool
+	}{
+		{name: "breed and age", args: EstimateLifeExpectancyArgs{Breed: &beagle, Age: number(3)}, condition: "ideal", trend: "insufficient-data"},
+		{name: "heavy", args: EstimateLifeExpectancyArgs{Breed: &beagle, Age: number(3), Weight: number(35)}, condition: "obese", trend: "stable"},
+		{name: "with a medical history", args: EstimateLifeExpectancyArgs{Breed: &beagle, Age: number(3), Surgeries: intPtr(0)}, condition: "ideal", trend: "insufficient-data", visits: true},
+		{name: "no age", args: EstimateLifeExpectancyArgs{Breed: &beagle}, err: true},
+		{name: "not a breed", args: EstimateLifeExpectancyArgs{Breed: &wolf, Age: number(3)}, err: true},
+		{name: "no weight", args: EstimateLifeExpectancyArgs{Breed: &beagle, Age: number(3), Weight: number(0)}, err: true},
+		{name: "negative visits", args: EstimateLifeExpectancyArgs{Breed: &beagle, Age: number(3), EmergencyVisits: intPtr(-1)}, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := describedLife(tt.args)
+			if (err != nil) != tt.err {
+				t.Fatalf("err = %v, want error %v", err, tt.err)
+			}
+			if err == nil && (got.condition != tt.condition || got.trend != tt.trend || got.visits != tt.visits) {
+				t.Errorf("describedLife = %+v, want %s, %s, visits %v", got, tt.condition, tt.trend, tt.visits)
+			}
+		})
+	}
+}
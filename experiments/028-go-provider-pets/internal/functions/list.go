@@ -0,0 +1,220 @@
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// PageArgs is the continuation contract shared by every function that can
+// return more than a page of rows. A call returns at most pageSize rows
+// (50 by default, never more than 500) and a nextPageToken; passing the
+// token back as pageToken returns the following page, and an empty token
+// means there are no more rows. Tokens are opaque and only valid for the
+// function and filters that produced them.
+type PageArgs struct {
+	PageSize  *int    `pulumi:"pageSize,optional"`
+	PageToken *string `pulumi:"pageToken,optional"`
+}
+
+// size returns the requested page size, enforcing the limits
+func (page PageArgs) size() (int, error) {
+	size := defaultPageSize
+	if page.PageSize != nil {
+		size = *page.PageSize
+	}
+	if size < 1 || size > maxPageSize {
+		return 0, fmt.Errorf("pageSize must be between 1 and %d", maxPageSize)
+	}
+	return size, nil
+}
+
+// listPage reads one page of q from the registry and decodes it into out,
+// which must point to a slice of the kind's state type.
+func listPage(ctx context.Context, q backend.Query, page PageArgs, out any) (string, error) {
+	size, err := page.size()
+	if err != nil {
+		return "", err
+	}
+	q.Limit = size
+	if page.PageToken != nil {
+		q.Cursor = *page.PageToken
+	}
+
+	store, err := registry.Store(ctx)
+	if err != nil {
+		return "", err
+	}
+	records, next, err := store.ListPage(q)
+	if err != nil {
+		return "", err
+	}
+
+	// Payloads are written before the store assigns a version, so take the
+	// authoritative one from the record
+	payloads := make([]map[string]json.RawMessage, len(records))
+	for i, rec := range records {
+		if err := json.Unmarshal(rec.Payload, &payloads[i]); err != nil {
+			return "", fmt.Errorf("%s %q: %w", rec.Kind, rec.ID, err)
+		}
+		payloads[i]["Version"] = json.RawMessage(fmt.Sprint(rec.Version))
+	}
+	data, err := json.Marshal(payloads)
+	if err != nil {
+		return "", err
+	}
+	return next, json.Unmarshal(data, out)
+}
+
+type SortArgs struct {
+	SortBy     *string `pulumi:"sortBy,optional"`
+	Descending *bool   `pulumi:"descending,optional"`
+}
+
+// apply sets the query ordering, mapping the public sort key onto the state
+// property it names.
+func (args SortArgs) apply(q *backend.Query, keys map[string]string) error {
+	if args.SortBy != nil {
+		field, ok := keys[*args.SortBy]
+		if !ok {
+			var valid []string
+			for key := range keys {
+				valid = append(valid, key)
+			}
+			sort.Strings(valid)
+			return fmt.Errorf("unknown sortBy %q, expected one of: %s", *args.SortBy, strings.Join(valid, ", "))
+		}
+		q.SortBy = field
+	}
+	q.Descending = args.Descending != nil && *args.Descending
+	return nil
+}
+
+type ListDogs struct{}
+
+type ListDogsArgs struct {
+	PageArgs
+	SortArgs
+	Breed     *resources.DogBreed `pulumi:"breed,optional"`
+	OwnerName *string             `pulumi:"ownerName,optional"`
+	Tag       *string             `pulumi:"tag,optional"`
+	MinAge    *int                `pulumi:"minAge,optional"` // In whole years, as ageYears reports them
+	MaxAge    *int                `pulumi:"maxAge,optional"`
+}
+
+type ListDogsResult struct {
+	Dogs          []resources.DogState `pulumi:"dogs"`
+	NextPageToken string               `pulumi:"nextPageToken"`
+}
+
+var dogSortKeys = map[string]string{
+	"name":             "Name",
+	"breed":            "Breed",
+	"ownerName":        "OwnerName",
+	"age":              "AgeYears",
+	"registrationDate": "RegistrationDate",
+}
+
+func (ListDogs) Call(ctx context.Context, args ListDogsArgs) (ListDogsResult, error) {
+	result := ListDogsResult{}
+	q := backend.Query{Kind: "dog"}
+	if args.Breed != nil {
+		q.Where = append(q.Where, backend.Condition{Field: "Breed", Op: "eq", Value: string(*args.Breed)})
+	}
+	if args.OwnerName != nil {
+		q.Where = append(q.Where, backend.Condition{Field: "OwnerName", Op: "eq", Value: *args.OwnerName})
+	}
+	if args.Tag != nil {
+		q.Where = append(q.Where, backend.Condition{Field: "Tags", Op: "contains", Value: *args.Tag})
+	}
+	if args.MinAge != nil {
+		q.Where = append(q.Where, backend.Condition{Field: "AgeYears", Op: "gte", Value: *args.MinAge})
+	}
+	if args.MaxAge != nil {
+		q.Where = append(q.Where, backend.Condition{Field: "AgeYears", Op: "lte", Value: *args.MaxAge})
+	}
+	if err := args.SortArgs.apply(&q, dogSortKeys); err != nil {
+		return result, err
+	}
+
+	next, err := listPage(ctx, q, args.PageArgs, &result.Dogs)
+	result.NextPageToken = next
+	return result, err
+}
+
+type ListWalks struct{}
+
+type ListWalksResult struct {
+	Walks         []resources.DogWalkState `pulumi:"walks"`
+	NextPageToken string                   `pulumi:"nextPageToken"`
+}
+
+func (ListWalks) Call(ctx context.Context, args PageArgs) (ListWalksResult, error) {
+	result := ListWalksResult{}
+	next, err := listPage(ctx, backend.Query{Kind: "walk"}, args, &result.Walks)
+	result.NextPageToken = next
+	return result, err
+}
+
+type ListVisits struct{}
+
+type ListVisitsArgs struct {
+	PageArgs
+	SortArgs
+	DogID      *string              `pulumi:"dogId,optional"`
+	VisitType  *resources.VisitType `pulumi:"visitType,optional"`
+	ClinicName *string              `pulumi:"clinicName,optional"`
+	Since      *string              `pulumi:"since,optional"`
+	Until      *string              `pulumi:"until,optional"`
+}
+
+type ListVisitsResult struct {
+	Visits        []resources.VeterinaryVisitState `pulumi:"visits"`
+	NextPageToken string                           `pulumi:"nextPageToken"`
+}
+
+var visitSortKeys = map[string]string{
+	"date":      "Date",
+	"cost":      "Cost",
+	"visitType": "VisitType",
+	"nextVisit": "NextVisit",
+}
+
+func (ListVisits) Call(ctx context.Context, args ListVisitsArgs) (ListVisitsResult, error) {
+	result := ListVisitsResult{}
+	q := backend.Query{Kind: "visit"}
+	if args.DogID != nil {
+		q.Where = append(q.Where, backend.Condition{Field: "DogID", Op: "eq", Value: *args.DogID})
+	}
+	if args.VisitType != nil {
+		q.Where = append(q.Where, backend.Condition{Field: "VisitType", Op: "eq", Value: string(*args.VisitType)})
+	}
+	if args.ClinicName != nil {
+		q.Where = append(q.Where, backend.Condition{Field: "ClinicName", Op: "eq", Value: *args.ClinicName})
+	}
+	// Dates are stored as RFC 3339 strings, so they compare lexically
+	if args.Since != nil {
+		q.Where = append(q.Where, backend.Condition{Field: "Date", Op: "gte", Value: *args.Since})
+	}
+	if args.Until != nil {
+		q.Where = append(q.Where, backend.Condition{Field: "Date", Op: "lte", Value: *args.Until})
+	}
+	if err := args.SortArgs.apply(&q, visitSortKeys); err != nil {
+		return result, err
+	}
+
+	next, err := listPage(ctx, q, args.PageArgs, &result.Visits)
+	result.NextPageToken = next
+	return result, err
+}
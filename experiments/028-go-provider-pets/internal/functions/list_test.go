@@ -0,0 +1,63 @@
+package functions
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// configured is a context whose registry lives in a fresh data directory,
+// opened with config
+func configured(t *testing.T, config registry.Config) context.Context {
+	t.Helper()
+	dir := t.TempDir()
+	config.DataDir = &dir
+	if err := config.Configure(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return registry.WithConfig(context.Background(), &config)
+}
+
+func TestListDogsByAge(t *testing.T) {
+	deterministic := true
+	ctx := configured(t, registry.Config{Deterministic: &deterministic})
+	// The clock stands at 2024-01-01, so Rex is 7 however old age says he is
+	born, four := "2016-06-01", 4
+	for name, args := range map[string]resources.DogArgs{
+		"rex":  {Name: "Rex", Breed: resources.Bulldog, OwnerName: "Ann", BirthDate: &born},
+		"luna": {Name: "Luna", Breed: resources.Bulldog, OwnerName: "Ann", Age: &four},
+	} {
+		if _, _, err := (resources.Dog{}).Create(ctx, name, args, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sortBy, descending := "age", true
+	tests := []struct {
+		name string
+		args ListDogsArgs
+		want string
+	}{
+		{name: "min age", args: ListDogsArgs{MinAge: intPtr(5)}, want: "Rex"},
+		{name: "max age", args: ListDogsArgs{MaxAge: intPtr(5)}, want: "Luna"},
+		{name: "sorted", args: ListDogsArgs{SortArgs: SortArgs{SortBy: &sortBy, Descending: &descending}}, want: "Rex,Luna"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ListDogs{}.Call(ctx, tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, dog := range got.Dogs {
+				names = append(names, dog.Name)
+			}
+			if strings.Join(names, ",") != tt.want {
+				t.Errorf("dogs = %s, want %s", strings.Join(names, ","), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// BackupRegistry writes the whole registry to a portable tar.gz archive.
+// Payloads are stored decrypted so the archive can be restored on a machine
+// configured with a different encryption key.
+//
+// Invokes also run during `pulumi preview`, so the maintenance functions
+// only report what they would do unless dryRun is false, which programs
+// should pass as the negation of ctx.DryRun() or its equivalent.
+type BackupRegistry struct{}
+
+type BackupRegistryArgs struct {
+	Path   string `pulumi:"path"`
+	DryRun *bool  `pulumi:"dryRun,optional"`
+}
+
+type BackupRegistryResult struct {
+	Path     string `pulumi:"path"`
+	Records  int    `pulumi:"records"`
+	Checksum string `pulumi:"checksum"`
+}
+
+func (BackupRegistry) Call(ctx context.Context, args BackupRegistryArgs) (BackupRegistryResult, error) {
+	store, err := registry.Store(ctx)
+	if err != nil {
+		return BackupRegistryResult{}, err
+	}
+	records, err := store.List("")
+	if err != nil {
+		return BackupRegistryResult{}, err
+	}
+	if registry.DryRun(args.DryRun) {
+		return BackupRegistryResult{Path: args.Path, Records: len(records), Checksum: backend.ArchiveChecksum(records)}, nil
+	}
+	checksum, err := registry.WriteArchive(ctx, args.Path, "", records)
+	if err != nil {
+		return BackupRegistryResult{}, fmt.Errorf("writing backup %s: %w", args.Path, err)
+	}
+	return BackupRegistryResult{Path: args.Path, Records: len(records), Checksum: checksum}, nil
+}
+
+// RestoreRegistry loads a backup produced by backupRegistry. Existing records
+// are kept unless overwrite is set, in which case the registry is replaced.
+// Either way the registry changes in a single write, so a failed restore
+// leaves it as it was.
+type RestoreRegistry struct{}
+
+type RestoreRegistryArgs struct {
+	Path      string `pulumi:"path"`
+	Overwrite *bool  `pulumi:"overwrite,optional"`
+	DryRun    *bool  `pulumi:"dryRun,optional"`
+}
+
+type RestoreRegistryResult struct {
+	Restored int      `pulumi:"restored"`
+	Skipped  []string `pulumi:"skipped"`
+	Removed  int      `pulumi:"removed"`
+}
+
+func (RestoreRegistry) Call(ctx context.Context, args RestoreRegistryArgs) (RestoreRegistryResult, error) {
+	result := RestoreRegistryResult{}
+	store, err := registry.Store(ctx)
+	if err != nil {
+		return result, err
+	}
+	_, records, err := backend.ReadArchive(args.Path)
+	if err != nil {
+		return result, fmt.Errorf("reading backup %s: %w", args.Path, err)
+	}
+	return restoreRecords(store, records, args.Overwrite != nil && *args.Overwrite, registry.DryRun(args.DryRun))
+}
+
+// restoreRecords loads records into store, replacing its contents when
+// overwrite is set; on a dry run it only counts what would change.
+func restoreRecords(store backend.Store, records []backend.Record, overwrite, dryRun bool) (RestoreRegistryResult, error) {
+	result := RestoreRegistryResult{}
+	existing, err := store.List("")
+	if err != nil {
+		return result, err
+	}
+
+	if overwrite {
+		result.Removed, result.Restored = len(existing), len(records)
+		if dryRun {
+			return result, nil
+		}
+		if _, err := store.Replace(records); err != nil {
+			return RestoreRegistryResult{}, err
+		}
+		return result, nil
+	}
+
+	present := map[string]bool{}
+	for _, rec := range existing {
+		present[backend.RecordKey(rec.Kind, rec.ID)] = true
+	}
+	var restore []backend.Record
+	for _, rec := range records {
+		if present[backend.RecordKey(rec.Kind, rec.ID)] {
+			result.Skipped = append(result.Skipped, backend.RecordKey(rec.Kind, rec.ID))
+			continue
+		}
+		// Records that appear in the meantime fail the restore instead of
+		// being overwritten
+		rec.Version = 0
+		restore = append(restore, rec)
+	}
+	result.Restored = len(restore)
+	if dryRun || len(restore) == 0 {
+		return result, nil
+	}
+	if _, err := store.PutAll(restore); err != nil {
+		return RestoreRegistryResult{}, err
+	}
+	return result, nil
+}
+
+// RollbackRegistry replaces the registry with the contents of a snapshot.
+// This happens outside Pulumi state, so run `pulumi refresh` afterwards.
+type RollbackRegistry struct{}
+
+type RollbackRegistryArgs struct {
+	SnapshotID string `pulumi:"snapshotId"`
+	DryRun     *bool  `pulumi:"dryRun,optional"`
+}
+
+type RollbackRegistryResult struct {
+	SnapshotID string `pulumi:"snapshotId"`
+	Label      string `pulumi:"label"`
+	TakenAt    string `pulumi:"takenAt"`
+	Restored   int    `pulumi:"restored"`
+	Removed    int    `pulumi:"removed"`
+}
+
+func (RollbackRegistry) Call(ctx context.Context, args RollbackRegistryArgs) (RollbackRegistryResult, error) {
+	result := RollbackRegistryResult{SnapshotID: args.SnapshotID}
+	store, err := registry.Store(ctx)
+	if err != nil {
+		return result, err
+	}
+	if strings.ContainsAny(args.SnapshotID, `/\`) {
+		return result, fmt.Errorf("invalid snapshot ID %q", args.SnapshotID)
+	}
+	manifest, records, err := backend.ReadArchive(registry.SnapshotPath(ctx, args.SnapshotID))
+	if errors.Is(err, os.ErrNotExist) {
+		return result, fmt.Errorf("snapshot %q: %w", args.SnapshotID, backend.ErrNotFound)
+	}
+	if err != nil {
+		return result, fmt.Errorf("reading snapshot %q: %w", args.SnapshotID, err)
+	}
+
+	restored, err := restoreRecords(store, records, true, registry.DryRun(args.DryRun))
+	if err != nil {
+		return result, err
+	}
+	result.Label = manifest.Label
+	result.TakenAt = manifest.Created
+	result.Restored = restored.Restored
+	result.Removed = restored.Removed
+	return result, nil
+}
@@ -0,0 +1,63 @@
+package functions
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+)
+
+func TestRestoreRecords(t *testing.T) {
+	backup := []backend.Record{
+		{Kind: "dog", ID: "rex", Payload: []byte(`{"name":"Rex"}`)},
+		{Kind: "dog", ID: "fido", Payload: []byte(`{"name":"Fido"}`)},
+	}
+	tests := []struct {
+		name      string
+		overwrite bool
+		dryRun    bool
+		want      RestoreRegistryResult
+		wantDogs  string
+	}{
+		{name: "merge", want: RestoreRegistryResult{Restored: 1, Skipped: []string{"dog/rex"}}, wantDogs: "fido=Fido,luna=Luna,rex=Rex II"},
+		{name: "overwrite", overwrite: true, want: RestoreRegistryResult{Restored: 2, Removed: 2}, wantDogs: "fido=Fido,rex=Rex"},
+		{name: "merge dry run", dryRun: true, want: RestoreRegistryResult{Restored: 1, Skipped: []string{"dog/rex"}}, wantDogs: "luna=Luna,rex=Rex II"},
+		{name: "overwrite dry run", overwrite: true, dryRun: true, want: RestoreRegistryResult{Restored: 2, Removed: 2}, wantDogs: "luna=Luna,rex=Rex II"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := backend.NewFileStore(filepath.Join(t.TempDir(), "registry.json"))
+			if _, err := store.PutAll([]backend.Record{
+				{Kind: "dog", ID: "rex", Payload: []byte(`{"name":"Rex II"}`)},
+				{Kind: "dog", ID: "luna", Payload: []byte(`{"name":"Luna"}`)},
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := restoreRecords(store, backup, tt.overwrite, tt.dryRun)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Restored != tt.want.Restored || got.Removed != tt.want.Removed ||
+				strings.Join(got.Skipped, ",") != strings.Join(tt.want.Skipped, ",") {
+				t.Errorf("result = %+v, want %+v", got, tt.want)
+			}
+
+			records, err := store.List("dog")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var dogs []string
+			for _, rec := range records {
+				name := strings.TrimSuffix(strings.TrimPrefix(string(rec.Payload), `{"name":"`), `"}`)
+				dogs = append(dogs, rec.ID+"="+name)
+			}
+			sort.Strings(dogs)
+			if strings.Join(dogs, ",") != tt.wantDogs {
+				t.Errorf("registry holds %s, want %s", strings.Join(dogs, ","), tt.wantDogs)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package functions
+
+import (
+	"context"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// CheckNameAvailability tells a program whether an owner can register a
+// Dog under a name before declaring it. The name is checked against the
+// owner's registered dogs regardless of case and spacing; when it is taken
+// the result says by which dog and suggests free alternates such as
+// "Rex II" or "Rex 2". Dog refuses a taken name unless uniqueDogNames is
+// off, which the result reports as enforced.
+type CheckNameAvailability struct{}
+
+func (CheckNameAvailability) Call(ctx context.Context, args resources.NameCheck) (resources.NameAvailability, error) {
+	return resources.CheckNameAvailability(ctx, args)
+}
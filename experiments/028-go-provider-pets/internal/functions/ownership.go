@@ -0,0 +1,37 @@
+package functions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// GetOwnershipHistory lists every owner a dog has had: each Update of a
+// Dog that changed its ownerName is recorded as a transfer.
+type GetOwnershipHistory struct{}
+
+type GetOwnershipHistoryArgs struct {
+	DogID string `pulumi:"dogId"`
+}
+
+type GetOwnershipHistoryResult struct {
+	DogID        string                        `pulumi:"dogId"`
+	CurrentOwner string                        `pulumi:"currentOwner"`
+	Transfers    []resources.OwnershipTransfer `pulumi:"transfers"` // oldest first
+}
+
+func (GetOwnershipHistory) Call(ctx context.Context, args GetOwnershipHistoryArgs) (GetOwnershipHistoryResult, error) {
+	result := GetOwnershipHistoryResult{DogID: args.DogID}
+	var dog resources.DogState
+	if _, err := registry.Load(ctx, "dog", args.DogID, &dog); err != nil {
+		return result, fmt.Errorf("dog %s: %w", args.DogID, err)
+	}
+	transfers, err := resources.OwnershipTransfers(ctx, args.DogID)
+	if err != nil {
+		return result, err
+	}
+	result.CurrentOwner, result.Transfers = dog.OwnerName, transfers
+	return result, nil
+}
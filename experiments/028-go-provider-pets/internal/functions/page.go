@@ -0,0 +1,39 @@
+package functions
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Functions that page through in-memory slices rather than registry queries
+// continue from an offset. The token wraps it so callers don't come to rely
+// on its format.
+
+const offsetPrefix = "offset:"
+
+func encodeOffset(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(offsetPrefix + strconv.Itoa(offset)))
+}
+
+func decodeOffset(token *string) (int, error) {
+	if token == nil || *token == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(*token)
+	if err == nil && strings.HasPrefix(string(raw), offsetPrefix) {
+		if offset, err := strconv.Atoi(strings.TrimPrefix(string(raw), offsetPrefix)); err == nil && offset >= 0 {
+			return offset, nil
+		}
+	}
+	return 0, errors.New("invalid pageToken")
+}
+
+// window returns up to size items of s starting at offset
+func window[T any](s []T, offset, size int) []T {
+	if offset >= len(s) {
+		return nil
+	}
+	return s[offset:min(offset+size, len(s))]
+}
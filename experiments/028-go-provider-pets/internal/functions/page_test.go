@@ -0,0 +1,77 @@
+package functions
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOffsetTokens(t *testing.T) {
+	for _, offset := range []int{0, 1, 500, 123456} {
+		token := encodeOffset(offset)
+		got, err := decodeOffset(&token)
+		if err != nil || got != offset {
+			t.Errorf("round trip of %d = %d, %v", offset, got, err)
+		}
+	}
+
+	bad := []string{"offset:12", "bm90LWFuLW9mZnNldA", encodeOffset(-1)}
+	for _, token := range bad {
+		if _, err := decodeOffset(&token); err == nil {
+			t.Errorf("token %q was accepted", token)
+		}
+	}
+	if offset, err := decodeOffset(nil); offset != 0 || err != nil {
+		t.Errorf("no token = %d, %v, want the first page", offset, err)
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    *int
+		want    int
+		wantErr bool
+	}{
+		{name: "default", want: defaultPageSize},
+		{name: "explicit", size: intPtr(10), want: 10},
+		{name: "at the maximum", size: intPtr(maxPageSize), want: maxPageSize},
+		{name: "over the maximum", size: intPtr(maxPageSize + 1), wantErr: true},
+		{name: "zero", size: intPtr(0), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PageArgs{PageSize: tt.size}.size()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("size = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindowPagesBothLists(t *testing.T) {
+	notes := []string{"a", "b", "c", "d", "e"}
+	medical := []string{"x", "y"}
+
+	var gotNotes, gotMedical []string
+	offset, pages := 0, 0
+	for {
+		gotNotes = append(gotNotes, window(notes, offset, 2)...)
+		gotMedical = append(gotMedical, window(medical, offset, 2)...)
+		pages++
+		offset += 2
+		if offset >= len(notes) && offset >= len(medical) {
+			break
+		}
+	}
+	if pages != 3 {
+		t.Errorf("pages = %d, want 3", pages)
+	}
+	if strings.Join(gotNotes, "") != "abcde" || strings.Join(gotMedical, "") != "xy" {
+		t.Errorf("paged = %q / %q, want every entry once", gotNotes, gotMedical)
+	}
+}
+
+func intPtr(v int) *int { return &v }
@@ -0,0 +1,170 @@
+package functions
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// GetShelterStatistics sums up a shelter's intakes and adoptions over a
+// period, for dashboards published as stack outputs. Dogs belong to the
+// shelter whose BulkDogIntake registered them, and leave it when an
+// Adoption of them is approved. Capacity utilization needs the shelter's
+// kennel count, from its PetShelter or the provider's shelterCapacity
+// config.
+type GetShelterStatistics struct{}
+
+type GetShelterStatisticsArgs struct {
+	ShelterID string `pulumi:"shelterId"` // the PetShelter's shelterId
+	Period    string `pulumi:"period"`    // a year (2026), quarter (2026-Q3) or month (2026-10)
+}
+
+type GetShelterStatisticsResult struct {
+	ShelterID           string   `pulumi:"shelterId"`
+	From                string   `pulumi:"from"` // first day of the period
+	To                  string   `pulumi:"to"`   // last day counted: the period's, or today for the current one
+	Intakes             int      `pulumi:"intakes"`
+	Adoptions           int      `pulumi:"adoptions"`
+	AdoptionRate        float64  `pulumi:"adoptionRate"`                 // percent of the dogs in care during the period that were adopted in it
+	AverageStayDays     float64  `pulumi:"averageStayDays"`              // from intake to adoption, for the dogs adopted in the period
+	InCare              int      `pulumi:"inCare"`                       // dogs still in care at the end of the period
+	Capacity            int      `pulumi:"capacity"`                     // 0 when neither the PetShelter nor shelterCapacity says
+	CapacityUtilization *float64 `pulumi:"capacityUtilization,optional"` // average percent of kennels occupied; unset without a capacity
+}
+
+// shelterDog is a dog's time at the shelter; adopted is zero while it is
+// still there
+type shelterDog struct {
+	intake  time.Time
+	adopted time.Time
+}
+
+func (GetShelterStatistics) Call(ctx context.Context, args GetShelterStatisticsArgs) (GetShelterStatisticsResult, error) {
+	result := GetShelterStatisticsResult{ShelterID: args.ShelterID}
+	from, to, err := parsePeriod(args.Period)
+	if err != nil {
+		return result, err
+	}
+	now := registry.Now(ctx)
+	if !from.Before(now) {
+		return result, fmt.Errorf("period %s hasn't started yet", args.Period)
+	}
+	if now.Before(to) {
+		to = now
+	}
+
+	var intakes []resources.BulkDogIntakeState
+	if err := listRecords(ctx, backend.Query{Kind: "bulk-intake"}, &intakes); err != nil {
+		return result, err
+	}
+	var adoptions []resources.AdoptionState
+	if err := listRecords(ctx, backend.Query{Kind: "adoption"}, &adoptions); err != nil {
+		return result, err
+	}
+	adopted := map[string]time.Time{}
+	for _, adoption := range adoptions {
+		at, err := time.Parse(time.RFC3339, adoption.DecidedAt)
+		if err != nil || adoption.Status != "approved" {
+			continue
+		}
+		if earlier, ok := adopted[adoption.DogID]; !ok || at.Before(earlier) {
+			adopted[adoption.DogID] = at
+		}
+	}
+	var dogs []shelterDog
+	for _, intake := range intakes {
+		at, err := time.Parse(time.RFC3339, intake.IntakeDate)
+		if err != nil || intake.Shelter() != args.ShelterID {
+			continue
+		}
+		for _, id := range intake.CreatedIDs {
+			dogs = append(dogs, shelterDog{intake: at, adopted: adopted[id]})
+		}
+	}
+
+	if result.Capacity, err = resources.ShelterCapacity(ctx, args.ShelterID); err != nil {
+		return result, err
+	}
+	return shelterStatistics(result, dogs, from, to), nil
+}
+
+// shelterStatistics works out the figures for [from, to)
+func shelterStatistics(result GetShelterStatisticsResult, dogs []shelterDog, from, to time.Time) GetShelterStatisticsResult {
+	result.From = from.Format("2006-01-02")
+	result.To = to.Add(-time.Nanosecond).Format("2006-01-02")
+
+	in := func(t time.Time) bool { return !t.IsZero() && !t.Before(from) && t.Before(to) }
+	inCareDuring, stayDays := 0, 0.0
+	occupied := time.Duration(0)
+	for _, dog := range dogs {
+		if !dog.intake.Before(to) || (!dog.adopted.IsZero() && dog.adopted.Before(from)) {
+			continue
+		}
+		inCareDuring++
+		if in(dog.intake) {
+			result.Intakes++
+		}
+		if in(dog.adopted) {
+			result.Adoptions++
+			stayDays += dog.adopted.Sub(dog.intake).Hours() / 24
+		} else if dog.adopted.IsZero() || !dog.adopted.Before(to) {
+			result.InCare++
+		}
+
+		arrived, left := dog.intake, to
+		if arrived.Before(from) {
+			arrived = from
+		}
+		if in(dog.adopted) {
+			left = dog.adopted
+		}
+		occupied += left.Sub(arrived)
+	}
+	if inCareDuring > 0 {
+		result.AdoptionRate = round2(float64(result.Adoptions) / float64(inCareDuring) * 100)
+	}
+	if result.Adoptions > 0 {
+		result.AverageStayDays = round2(stayDays / float64(result.Adoptions))
+	}
+	if result.Capacity > 0 {
+		utilization := round2(occupied.Hours() / (to.Sub(from).Hours() * float64(result.Capacity)) * 100)
+		result.CapacityUtilization = &utilization
+	}
+	return result
+}
+
+// parsePeriod turns a year, quarter or month into the half-open range of
+// time it covers
+func parsePeriod(period string) (time.Time, time.Time, error) {
+	invalid := fmt.Errorf("period %q must be a year (2026), quarter (2026-Q3) or month (2026-10)", period)
+	year, rest, _ := strings.Cut(period, "-")
+	y, err := strconv.Atoi(year)
+	if err != nil || len(year) != 4 {
+		return time.Time{}, time.Time{}, invalid
+	}
+	start := time.Date(y, 1, 1, 0, 0, 0, 0, time.UTC)
+	switch {
+	case rest == "":
+		return start, start.AddDate(1, 0, 0), nil
+	case strings.HasPrefix(rest, "Q"):
+		q, err := strconv.Atoi(rest[1:])
+		if err != nil || q < 1 || q > 4 {
+			return time.Time{}, time.Time{}, invalid
+		}
+		start = start.AddDate(0, 3*(q-1), 0)
+		return start, start.AddDate(0, 3, 0), nil
+	default:
+		m, err := strconv.Atoi(rest)
+		if err != nil || m < 1 || m > 12 || len(rest) != 2 {
+			return time.Time{}, time.Time{}, invalid
+		}
+		start = start.AddDate(0, m-1, 0)
+		return start, start.AddDate(0, 1, 0), nil
+	}
+}
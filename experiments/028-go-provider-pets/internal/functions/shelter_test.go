@@ -0,0 +1,79 @@
+package functions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		period   string
+		from, to string
+		invalid  bool
+	}{
+		{period: "2026", from: "2026-01-01", to: "2027-01-01"},
+		{period: "2026-Q3", from: "2026-07-01", to: "2026-10-01"},
+		{period: "2026-Q4", from: "2026-10-01", to: "2027-01-01"},
+		{period: "2026-02", from: "2026-02-01", to: "2026-03-01"},
+		{period: "2026-Q5", invalid: true},
+		{period: "2026-2", invalid: true},
+		{period: "26", invalid: true},
+		{period: "last-month", invalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			from, to, err := parsePeriod(tt.period)
+			if tt.invalid {
+				if err == nil {
+					t.Errorf("parsePeriod(%q) = %s..%s, want an error", tt.period, from, to)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := from.Format("2006-01-02"); got != tt.from {
+				t.Errorf("from = %s, want %s", got, tt.from)
+			}
+			if got := to.Format("2006-01-02"); got != tt.to {
+				t.Errorf("to = %s, want %s", got, tt.to)
+			}
+		})
+	}
+}
+
+func TestShelterStatistics(t *testing.T) {
+	day := func(s string) time.Time {
+		t, _ := time.Parse("2006-01-02", s)
+		return t
+	}
+	from, to := day("2026-09-01"), day("2026-10-01") // 30 days
+	dogs := []shelterDog{
+		{intake: day("2026-08-22"), adopted: day("2026-09-11")}, // 10 days in care in September, stayed 20
+		{intake: day("2026-09-01"), adopted: day("2026-09-21")}, // 20 days, stayed 20
+		{intake: day("2026-09-16")},                             // 15 days, still there
+		{intake: day("2026-08-01")},                             // all 30 days
+		{intake: day("2026-08-01"), adopted: day("2026-08-20")}, // gone before September
+		{intake: day("2026-10-02")},                             // arrives after
+	}
+
+	result := shelterStatistics(GetShelterStatisticsResult{ShelterID: "happy-tails", Capacity: 5}, dogs, from, to)
+	if result.From != "2026-09-01" || result.To != "2026-09-30" {
+		t.Errorf("range = %s..%s", result.From, result.To)
+	}
+	if result.Intakes != 2 || result.Adoptions != 2 || result.InCare != 2 {
+		t.Errorf("intakes %d, adoptions %d, in care %d; want 2, 2, 2", result.Intakes, result.Adoptions, result.InCare)
+	}
+	if result.AdoptionRate != 50 || result.AverageStayDays != 20 {
+		t.Errorf("adoption rate %v, average stay %v; want 50, 20", result.AdoptionRate, result.AverageStayDays)
+	}
+	// 75 dog-days of 150 kennel-days
+	if result.CapacityUtilization == nil || *result.CapacityUtilization != 50 {
+		t.Errorf("capacity utilization = %v, want 50", result.CapacityUtilization)
+	}
+
+	result = shelterStatistics(GetShelterStatisticsResult{ShelterID: "happy-tails"}, nil, from, to)
+	if result.AdoptionRate != 0 || result.CapacityUtilization != nil {
+		t.Errorf("empty shelter = %+v", result)
+	}
+}
@@ -0,0 +1,36 @@
+package functions
+
+import (
+	"context"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/cache"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/httpclient"
+)
+
+// GetCacheStats reports the hit, miss and eviction counters of the
+// provider's lookup caches since it started.
+type GetCacheStats struct{}
+
+type GetCacheStatsArgs struct{}
+
+type GetCacheStatsResult struct {
+	Caches []cache.Stats `pulumi:"caches"`
+}
+
+func (GetCacheStats) Call(ctx context.Context, args GetCacheStatsArgs) (GetCacheStatsResult, error) {
+	return GetCacheStatsResult{Caches: cache.Snapshot()}, nil
+}
+
+// GetHttpStats reports, per host, how many outbound requests the provider
+// made since it started, how many failed and how long they took.
+type GetHttpStats struct{}
+
+type GetHttpStatsArgs struct{}
+
+type GetHttpStatsResult struct {
+	Hosts []httpclient.Stats `pulumi:"hosts"`
+}
+
+func (GetHttpStats) Call(ctx context.Context, args GetHttpStatsArgs) (GetHttpStatsResult, error) {
+	return GetHttpStatsResult{Hosts: httpclient.Snapshot()}, nil
+}
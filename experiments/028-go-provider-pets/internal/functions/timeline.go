@@ -0,0 +1,109 @@
+package functions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// GetBehaviorTimeline merges every behavior note about a dog, oldest first:
+// its own history plus the notes its walks and vet visits recorded. Notes
+// from before they were timestamped sort ahead of the rest, in the order
+// they were made.
+type GetBehaviorTimeline struct{}
+
+type GetBehaviorTimelineArgs struct {
+	DogID string `pulumi:"dogId"`
+}
+
+type GetBehaviorTimelineResult struct {
+	DogID   string          `pulumi:"dogId"`
+	Entries []TimelineEntry `pulumi:"entries"`
+}
+
+// TimelineEntry is a behavior note and the record it came from
+type TimelineEntry struct {
+	resources.BehaviorNote
+	Source string `pulumi:"source"` // ID of the dog, walk or visit that recorded the note
+}
+
+func (GetBehaviorTimeline) Call(ctx context.Context, args GetBehaviorTimelineArgs) (GetBehaviorTimelineResult, error) {
+	result := GetBehaviorTimelineResult{DogID: args.DogID}
+
+	history, err := loadHistory(ctx, args.DogID)
+	if err != nil {
+		return result, err
+	}
+	for _, note := range history.BehaviorNotes {
+		result.Entries = append(result.Entries, TimelineEntry{BehaviorNote: note, Source: args.DogID})
+	}
+
+	var walks []resources.DogWalkState
+	if err := listForDog(ctx, "walk", args.DogID, &walks); err != nil {
+		return result, err
+	}
+	for _, walk := range walks {
+		result.Entries = appendNote(result.Entries, walk.BehaviorNote, walk.ID)
+	}
+
+	var visits []resources.VeterinaryVisitState
+	if err := listForDog(ctx, "visit", args.DogID, &visits); err != nil {
+		return result, err
+	}
+	for _, visit := range visits {
+		result.Entries = appendNote(result.Entries, visit.BehaviorNote, visit.ID)
+	}
+
+	sort.SliceStable(result.Entries, func(i, j int) bool {
+		return result.Entries[i].Timestamp < result.Entries[j].Timestamp
+	})
+	return result, nil
+}
+
+// appendNote adds a walk or visit note; records from before they carried
+// one have none to add
+func appendNote(entries []TimelineEntry, note resources.BehaviorNote, source string) []TimelineEntry {
+	if note.Note == "" {
+		return entries
+	}
+	return append(entries, TimelineEntry{BehaviorNote: note, Source: source})
+}
+
+// listForDog decodes every record of kind that refers to dogID into out,
+// which must point to a slice of the kind's state type.
+func listForDog(ctx context.Context, kind, dogID string, out any) error {
+	return listRecords(ctx, backend.Query{
+		Kind:  kind,
+		Where: []backend.Condition{{Field: "DogID", Op: "eq", Value: dogID}},
+	}, out)
+}
+
+// listRecords decodes every record q matches into out, which must point to
+// a slice of the kind's state type.
+func listRecords(ctx context.Context, q backend.Query, out any) error {
+	store, err := registry.Store(ctx)
+	if err != nil {
+		return err
+	}
+	records, _, err := store.ListPage(q)
+	if err != nil {
+		return err
+	}
+	payloads := make([]json.RawMessage, len(records))
+	for i, rec := range records {
+		payloads[i] = rec.Payload
+	}
+	data, err := json.Marshal(payloads)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decoding %s records: %w", q.Kind, err)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package functions
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// NextVaccinationDue checks a dog's vaccinations against the embedded
+// booster schedule: which vaccines are due soon, which are overdue, and
+// when the next dose is needed. Core vaccines count as overdue until a
+// first dose is recorded; non-core ones are tracked once the dog has had
+// them. Programs can gate bookings on upToDate.
+type NextVaccinationDue struct{}
+
+type NextVaccinationDueArgs struct {
+	DogID         *string                 `pulumi:"dogId,optional"`        // Check the doses recorded for this dog and at its visits
+	Vaccinations  []resources.Vaccination `pulumi:"vaccinations,optional"` // Check these doses, in addition to any for dogId
+	DueWithinDays *int                    `pulumi:"dueWithinDays,optional"`
+}
+
+type NextVaccinationDueResult struct {
+	Vaccines    []VaccineStatus `pulumi:"vaccines"`
+	Due         []string        `pulumi:"due"`
+	Overdue     []string        `pulumi:"overdue"`
+	NextDueDate string          `pulumi:"nextDueDate"` // earliest date a dose is needed, "" when none is scheduled
+	UpToDate    bool            `pulumi:"upToDate"`
+}
+
+// VaccineStatus is where one vaccine stands
+type VaccineStatus struct {
+	Vaccine   string `pulumi:"vaccine"`
+	Core      bool   `pulumi:"core"`
+	LastGiven string `pulumi:"lastGiven"`
+	NextDue   string `pulumi:"nextDue"`
+	Status    string `pulumi:"status"` // current, due, overdue or missing
+}
+
+// defaultDueWithinDays is how far ahead a booster counts as due
+const defaultDueWithinDays = 30
+
+//go:embed data/vaccine_schedule.json
+var vaccineScheduleJSON []byte
+
+type scheduledVaccine struct {
+	Vaccine       string   `json:"vaccine"`
+	Aliases       []string `json:"aliases"`
+	Core          bool     `json:"core"`
+	BoosterMonths int      `json:"boosterMonths"`
+}
+
+var vaccineSchedule = mustLoadVaccineSchedule()
+
+func mustLoadVaccineSchedule() []scheduledVaccine {
+	var schedule []scheduledVaccine
+	if err := json.Unmarshal(vaccineScheduleJSON, &schedule); err != nil {
+		panic(fmt.Sprintf("embedded vaccine schedule: %v", err))
+	}
+	return schedule
+}
+
+func (NextVaccinationDue) Call(ctx context.Context, args NextVaccinationDueArgs) (NextVaccinationDueResult, error) {
+	if args.DogID == nil && len(args.Vaccinations) == 0 {
+		return NextVaccinationDueResult{}, errors.New("set dogId, vaccinations or both")
+	}
+	within := defaultDueWithinDays
+	if args.DueWithinDays != nil {
+		within = *args.DueWithinDays
+	}
+	if within < 0 {
+		return NextVaccinationDueResult{}, errors.New("dueWithinDays must be at least 0")
+	}
+
+	doses := args.Vaccinations
+	if args.DogID != nil {
+		recorded, err := resources.DogVaccinations(ctx, *args.DogID)
+		if err != nil {
+			return NextVaccinationDueResult{}, err
+		}
+		doses = append(recorded, doses...)
+	}
+	return vaccinationsDue(doses, registry.Now(ctx), within), nil
+}
+
+// vaccinationsDue evaluates doses against the schedule on the day of now.
+// A dose's own expiresOn wins over the scheduled booster interval.
+func vaccinationsDue(doses []resources.Vaccination, now time.Time, within int) NextVaccinationDueResult {
+	latest := map[string]resources.Vaccination{}
+	for _, dose := range doses {
+		name := canonicalVaccine(dose.Vaccine)
+		if prev, ok := latest[name]; !ok || dose.DateGiven > prev.DateGiven {
+			latest[name] = dose
+		}
+	}
+
+	today := now.Format("2006-01-02")
+	soon := now.AddDate(0, 0, within).Format("2006-01-02")
+	var result NextVaccinationDueResult
+	add := func(status VaccineStatus) {
+		switch {
+		case status.LastGiven == "":
+			status.Status = "missing"
+		case status.NextDue == "":
+			status.Status = "current"
+		case status.NextDue < today:
+			status.Status = "overdue"
+		case status.NextDue <= soon:
+			status.Status = "due"
+		default:
+			status.Status = "current"
+		}
+		switch status.Status {
+		case "missing", "overdue":
+			result.Overdue = append(result.Overdue, status.Vaccine)
+		case "due":
+			result.Due = append(result.Due, status.Vaccine)
+		}
+		if status.NextDue != "" && (result.NextDueDate == "" || status.NextDue < result.NextDueDate) {
+			result.NextDueDate = status.NextDue
+		}
+		result.Vaccines = append(result.Vaccines, status)
+	}
+
+	for _, scheduled := range vaccineSchedule {
+		dose, given := latest[scheduled.Vaccine]
+		delete(latest, scheduled.Vaccine)
+		if !given {
+			if scheduled.Core {
+				add(VaccineStatus{Vaccine: scheduled.Vaccine, Core: true, NextDue: today})
+			}
+			continue
+		}
+		status := VaccineStatus{Vaccine: scheduled.Vaccine, Core: scheduled.Core, LastGiven: dose.DateGiven}
+		if dose.ExpiresOn != nil {
+			status.NextDue = *dose.ExpiresOn
+		} else if given, err := time.Parse("2006-01-02", dose.DateGiven); err == nil {
+			status.NextDue = given.AddDate(0, scheduled.BoosterMonths, 0).Format("2006-01-02")
+		}
+		add(status)
+	}
+
+	// Vaccines the schedule doesn't know only lapse when the dose says so
+	var others []string
+	for name := range latest {
+		others = append(others, name)
+	}
+	sort.Strings(others)
+	for _, name := range others {
+		dose := latest[name]
+		status := VaccineStatus{Vaccine: name, LastGiven: dose.DateGiven}
+		if dose.ExpiresOn != nil {
+			status.NextDue = *dose.ExpiresOn
+		}
+		add(status)
+	}
+
+	result.UpToDate = len(result.Overdue) == 0
+	return result
+}
+
+// canonicalVaccine maps a recorded vaccine name onto the schedule's name
+// for it, or a normalized form of itself when the schedule doesn't list it
+func canonicalVaccine(name string) string {
+	name = strings.ToLower(strings.Join(strings.Fields(name), "-"))
+	for _, scheduled := range vaccineSchedule {
+		if name == scheduled.Vaccine {
+			return name
+		}
+		for _, alias := range scheduled.Aliases {
+			if name == alias {
+				return scheduled.Vaccine
+			}
+		}
+	}
+	return name
+}
@@ -0,0 +1,97 @@
+package functions
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+func TestVaccinationsDue(t *testing.T) {
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+	dose := func(vaccine, given string) resources.Vaccination {
+		return resources.Vaccination{Vaccine: vaccine, DateGiven: given}
+	}
+	tests := []struct {
+		name        string
+		doses       []resources.Vaccination
+		wantDue     []string
+		wantOverdue []string
+		wantNext    string
+		wantStatus  map[string]string
+	}{
+		{
+			name:        "nothing recorded",
+			wantOverdue: []string{"rabies", "dhpp"},
+			wantNext:    "2026-06-15",
+			wantStatus:  map[string]string{"rabies": "missing", "dhpp": "missing"},
+		},
+		{
+			name:       "core vaccines current",
+			doses:      []resources.Vaccination{dose("Rabies", "2025-01-10"), dose("DA2PP", "2025-01-10")},
+			wantNext:   "2028-01-10",
+			wantStatus: map[string]string{"rabies": "current", "dhpp": "current"},
+		},
+		{
+			name: "booster due soon and one overdue",
+			doses: []resources.Vaccination{
+				dose("rabies", "2023-07-01"), dose("dhpp", "2025-01-10"),
+				dose("kennel cough", "2025-05-01"),
+			},
+			wantDue:     []string{"rabies"},
+			wantOverdue: []string{"bordetella"},
+			wantNext:    "2026-05-01",
+			wantStatus:  map[string]string{"rabies": "due", "dhpp": "current", "bordetella": "overdue"},
+		},
+		{
+			name: "expiresOn wins over the schedule and later doses supersede",
+			doses: []resources.Vaccination{
+				{Vaccine: "rabies", DateGiven: "2025-06-01", ExpiresOn: stringPtr("2026-06-01")},
+				dose("rabies", "2022-06-01"),
+				dose("dhpp", "2025-01-10"),
+			},
+			wantOverdue: []string{"rabies"},
+			wantNext:    "2026-06-01",
+			wantStatus:  map[string]string{"rabies": "overdue", "dhpp": "current"},
+		},
+		{
+			name: "unscheduled vaccine",
+			doses: []resources.Vaccination{
+				dose("rabies", "2025-01-10"), dose("dhpp", "2025-01-10"),
+				{Vaccine: "rattlesnake", DateGiven: "2026-01-01", ExpiresOn: stringPtr("2026-07-01")},
+			},
+			wantDue:    []string{"rattlesnake"},
+			wantNext:   "2026-07-01",
+			wantStatus: map[string]string{"rabies": "current", "dhpp": "current", "rattlesnake": "due"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := vaccinationsDue(tt.doses, now, 30)
+			if !reflect.DeepEqual(got.Due, tt.wantDue) || !reflect.DeepEqual(got.Overdue, tt.wantOverdue) {
+				t.Errorf("due/overdue = %q/%q, want %q/%q", got.Due, got.Overdue, tt.wantDue, tt.wantOverdue)
+			}
+			if got.NextDueDate != tt.wantNext {
+				t.Errorf("next due = %q, want %q", got.NextDueDate, tt.wantNext)
+			}
+			if got.UpToDate != (len(tt.wantOverdue) == 0) {
+				t.Errorf("upToDate = %v with overdue %q", got.UpToDate, got.Overdue)
+			}
+			statuses := map[string]string{}
+			for _, v := range got.Vaccines {
+				statuses[v.Vaccine] = v.Status
+			}
+			if !reflect.DeepEqual(statuses, tt.wantStatus) {
+				t.Errorf("statuses = %v, want %v", statuses, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestNextVaccinationDueNeedsInput(t *testing.T) {
+	if _, err := (NextVaccinationDue{}).Call(context.Background(), NextVaccinationDueArgs{}); err == nil {
+		t.Error("Call without dogId or vaccinations succeeded")
+	}
+}
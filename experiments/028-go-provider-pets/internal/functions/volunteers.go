@@ -0,0 +1,119 @@
+package functions
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// ExportVolunteerSchedule lists a shelter's volunteer shifts for the
+// Monday-to-Sunday week containing weekOf, with the stretches in which a
+// role is below its minimum staffing, ready to print or share as CSV.
+// Volunteers are shown by a masked contact, since the contact is a secret.
+type ExportVolunteerSchedule struct{}
+
+type ExportVolunteerScheduleArgs struct {
+	ShelterID string `pulumi:"shelterId"`
+	WeekOf    string `pulumi:"weekOf"` // Any day of the week, as YYYY-MM-DD
+}
+
+type ExportVolunteerScheduleResult struct {
+	WeekStart string                  `pulumi:"weekStart"`
+	WeekEnd   string                  `pulumi:"weekEnd"`
+	Shifts    []ScheduledShift        `pulumi:"shifts"` // in start order
+	Gaps      []resources.StaffingGap `pulumi:"gaps"`
+	Hours     map[string]float64      `pulumi:"hours"` // volunteer hours by role
+	Csv       string                  `pulumi:"csv"`
+}
+
+// ScheduledShift is a shift as it appears on the schedule
+type ScheduledShift struct {
+	ID        string                  `pulumi:"id"`
+	Role      resources.VolunteerRole `pulumi:"role"`
+	Volunteer string                  `pulumi:"volunteer"`
+	Start     string                  `pulumi:"start"`
+	End       string                  `pulumi:"end"`
+	Hours     float64                 `pulumi:"hours"`
+}
+
+func (ExportVolunteerSchedule) Call(ctx context.Context, args ExportVolunteerScheduleArgs) (ExportVolunteerScheduleResult, error) {
+	day, err := time.Parse("2006-01-02", args.WeekOf)
+	if err != nil {
+		return ExportVolunteerScheduleResult{}, errors.New("weekOf must be a YYYY-MM-DD date")
+	}
+	shifts, err := resources.ShelterShifts(ctx, args.ShelterID)
+	if err != nil {
+		return ExportVolunteerScheduleResult{}, err
+	}
+	return volunteerSchedule(shifts, resources.VolunteerMinimums(ctx), day)
+}
+
+// volunteerSchedule lays out the week of day
+func volunteerSchedule(shifts []resources.VolunteerShiftState, minimums map[resources.VolunteerRole]int, day time.Time) (ExportVolunteerScheduleResult, error) {
+	monday := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	next := monday.AddDate(0, 0, 7)
+	result := ExportVolunteerScheduleResult{
+		WeekStart: monday.Format("2006-01-02"),
+		WeekEnd:   next.AddDate(0, 0, -1).Format("2006-01-02"),
+		Shifts:    []ScheduledShift{},
+		Hours:     map[string]float64{},
+	}
+
+	var week []resources.VolunteerShiftState
+	for _, shift := range shifts {
+		start, err1 := time.Parse(time.RFC3339, shift.Start)
+		end, err2 := time.Parse(time.RFC3339, shift.End)
+		if err1 != nil || err2 != nil || !start.Before(next) || !end.After(monday) {
+			continue
+		}
+		week = append(week, shift)
+		result.Shifts = append(result.Shifts, ScheduledShift{
+			ID:        shift.ID,
+			Role:      shift.Role,
+			Volunteer: maskContact(shift.VolunteerContact),
+			Start:     shift.Start,
+			End:       shift.End,
+			Hours:     shift.Hours,
+		})
+		result.Hours[string(shift.Role)] = round2(result.Hours[string(shift.Role)] + shift.Hours)
+	}
+	sort.SliceStable(result.Shifts, func(i, j int) bool {
+		if result.Shifts[i].Start != result.Shifts[j].Start {
+			return result.Shifts[i].Start < result.Shifts[j].Start
+		}
+		return result.Shifts[i].Role < result.Shifts[j].Role
+	})
+	result.Gaps = resources.StaffingGaps(week, minimums, monday, next)
+	if result.Gaps == nil {
+		result.Gaps = []resources.StaffingGap{}
+	}
+
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	w.Write([]string{"start", "end", "role", "volunteer", "hours"})
+	for _, shift := range result.Shifts {
+		w.Write([]string{shift.Start, shift.End, string(shift.Role), shift.Volunteer, strconv.FormatFloat(shift.Hours, 'f', -1, 64)})
+	}
+	w.Flush()
+	result.Csv = b.String()
+	return result, w.Error()
+}
+
+// maskContact keeps enough of an email address or phone number to tell
+// volunteers apart: the first letter and domain, or the last four digits
+func maskContact(contact string) string {
+	if name, domain, ok := strings.Cut(contact, "@"); ok && name != "" {
+		return name[:1] + "***@" + domain
+	}
+	if len(contact) > 4 {
+		return "***" + contact[len(contact)-4:]
+	}
+	return "***"
+}
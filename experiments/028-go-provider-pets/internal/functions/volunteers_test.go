@@ -0,0 +1,52 @@
+package functions
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+func TestVolunteerSchedule(t *testing.T) {
+	shift := func(id string, role resources.VolunteerRole, contact, start, end string, hours float64) resources.VolunteerShiftState {
+		var s resources.VolunteerShiftState
+		s.ID, s.Role, s.VolunteerContact, s.Start, s.End, s.Hours = id, role, contact, start, end, hours
+		return s
+	}
+	shifts := []resources.VolunteerShiftState{
+		shift("shift-2", resources.RoleFrontDesk, "+15551234567", "2026-10-14T12:00:00Z", "2026-10-14T16:00:00Z", 4),
+		shift("shift-1", resources.RoleFrontDesk, "ana@example.org", "2026-10-14T08:00:00Z", "2026-10-14T12:00:00Z", 4),
+		shift("shift-3", resources.RoleKennel, "bo@example.org", "2026-10-14T08:00:00Z", "2026-10-14T11:30:00Z", 3.5),
+		shift("shift-4", resources.RoleKennel, "bo@example.org", "2026-10-21T08:00:00Z", "2026-10-21T12:00:00Z", 4), // next week
+	}
+	minimums := map[resources.VolunteerRole]int{resources.RoleKennel: 1, resources.RoleFrontDesk: 1}
+
+	day, _ := time.Parse("2006-01-02", "2026-10-16")
+	result, err := volunteerSchedule(shifts, minimums, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.WeekStart != "2026-10-12" || result.WeekEnd != "2026-10-18" {
+		t.Errorf("week = %s..%s", result.WeekStart, result.WeekEnd)
+	}
+	var order []string
+	for _, s := range result.Shifts {
+		order = append(order, s.ID)
+	}
+	if strings.Join(order, ",") != "shift-1,shift-3,shift-2" {
+		t.Errorf("shifts in order %v", order)
+	}
+	if result.Shifts[0].Volunteer != "a***@example.org" || result.Shifts[2].Volunteer != "***4567" {
+		t.Errorf("volunteers = %q, %q", result.Shifts[0].Volunteer, result.Shifts[2].Volunteer)
+	}
+	if result.Hours["kennel"] != 3.5 || result.Hours["front-desk"] != 8 {
+		t.Errorf("hours = %v", result.Hours)
+	}
+	if len(result.Gaps) != 1 || result.Gaps[0].Role != resources.RoleKennel || result.Gaps[0].Start != "2026-10-14T11:30:00Z" || result.Gaps[0].End != "2026-10-14T16:00:00Z" {
+		t.Errorf("gaps = %+v, want kennel uncovered from 11:30", result.Gaps)
+	}
+	if lines := strings.Split(strings.TrimSpace(result.Csv), "\n"); len(lines) != 4 || lines[2] != "2026-10-14T08:00:00Z,2026-10-14T11:30:00Z,kennel,b***@example.org,3.5" {
+		t.Errorf("csv = %q", result.Csv)
+	}
+}
@@ -0,0 +1,193 @@
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// WeightTrendAnalysis fits a line through a dog's recent WeightLog entries
+// to tell whether it is gaining, losing or holding steady, assesses its
+// body condition against the breed's typical weight, and recommends a
+// percentage to adjust its daily calories by.
+type WeightTrendAnalysis struct{}
+
+type WeightTrendAnalysisArgs struct {
+	DogID      string `pulumi:"dogId"`
+	WindowDays *int   `pulumi:"windowDays,optional"` // Only weigh-ins from this many days back count towards the trend; defaults to 90
+}
+
+type WeightTrendAnalysisResult struct {
+	DogID              string  `pulumi:"dogId"`
+	Entries            int     `pulumi:"entries"`            // weigh-ins inside the window
+	CurrentWeight      float64 `pulumi:"currentWeight"`      // pounds
+	BreedWeight        float64 `pulumi:"breedWeight"`        // typical weight of the breed in pounds
+	Trend              string  `pulumi:"trend"`              // gaining, losing, stable or insufficient-data
+	RatePerWeek        float64 `pulumi:"ratePerWeek"`        // pounds per week, negative when losing
+	RatePercentPerWeek float64 `pulumi:"ratePercentPerWeek"` // ratePerWeek as a percentage of currentWeight
+	BodyCondition      string  `pulumi:"bodyCondition"`      // underweight, ideal, overweight or obese
+	CalorieAdjustment  int     `pulumi:"calorieAdjustment"`  // percent to change daily calories by, e.g. -10
+}
+
+const (
+	defaultTrendWindowDays = 90
+	// stableRatePercent is the weekly change, as a percentage of body
+	// weight, below which the weight is holding steady
+	stableRatePercent = 0.25
+)
+
+func (WeightTrendAnalysis) Call(ctx context.Context, args WeightTrendAnalysisArgs) (WeightTrendAnalysisResult, error) {
+	window := defaultTrendWindowDays
+	if args.WindowDays != nil {
+		window = *args.WindowDays
+	}
+	if window < 1 {
+		return WeightTrendAnalysisResult{}, errors.New("windowDays must be at least 1")
+	}
+
+	var dog resources.DogState
+	if _, err := registry.Load(ctx, "dog", args.DogID, &dog); err != nil {
+		return WeightTrendAnalysisResult{}, fmt.Errorf("dog %s: %w", args.DogID, err)
+	}
+	var logs []resources.WeightLogState
+	if err := listForDog(ctx, "weight", args.DogID, &logs); err != nil {
+		return WeightTrendAnalysisResult{}, err
+	}
+
+	since := registry.Now(ctx).AddDate(0, 0, -window).Format("2006-01-02")
+	var recent []resources.WeightLogState
+	for _, entry := range logs {
+		if entry.MeasuredOn >= since {
+			recent = append(recent, entry)
+		}
+	}
+	result := weightTrend(recent, dog.CurrentWeight, resources.EstimateWeightByBreed(dog.Breed))
+	result.DogID = args.DogID
+	return result, nil
+}
+
+// weightTrend analyzes weigh-ins against the breed's typical weight.
+// current is the dog's weight when there are no weigh-ins to go by.
+func weightTrend(logs []resources.WeightLogState, current, breedWeight float64) WeightTrendAnalysisResult {
+	sort.Slice(logs, func(i, j int) bool {
+		if logs[i].MeasuredOn != logs[j].MeasuredOn {
+			return logs[i].MeasuredOn < logs[j].MeasuredOn
+		}
+		return logs[i].RecordedAt < logs[j].RecordedAt
+	})
+	result := WeightTrendAnalysisResult{
+		Entries:       len(logs),
+		CurrentWeight: current,
+		BreedWeight:   breedWeight,
+		Trend:         "insufficient-data",
+	}
+	if len(logs) > 0 {
+		result.CurrentWeight = logs[len(logs)-1].Pounds
+	}
+
+	if perDay, ok := weightSlope(logs); ok {
+		result.RatePerWeek = round2(perDay * 7)
+		if result.CurrentWeight > 0 {
+			result.RatePercentPerWeek = round2(result.RatePerWeek / result.CurrentWeight * 100)
+		}
+		switch {
+		case result.RatePercentPerWeek >= stableRatePercent:
+			result.Trend = "gaining"
+		case result.RatePercentPerWeek <= -stableRatePercent:
+			result.Trend = "losing"
+		default:
+			result.Trend = "stable"
+		}
+	}
+
+	result.BodyCondition = bodyCondition(result.CurrentWeight, breedWeight)
+	result.CalorieAdjustment = calorieAdjustment(result.BodyCondition, result.Trend)
+	return result
+}
+
+// weightSlope is the least-squares change in pounds per day. It needs
+// weigh-ins on at least two different days.
+func weightSlope(logs []resources.WeightLogState) (float64, bool) {
+	var days, pounds []float64
+	for _, entry := range logs {
+		day, err := time.Parse("2006-01-02", entry.MeasuredOn)
+		if err != nil {
+			continue
+		}
+		days = append(days, float64(day.Unix())/86400)
+		pounds = append(pounds, entry.Pounds)
+	}
+	if len(days) < 2 {
+		return 0, false
+	}
+
+	var meanDay, meanPounds float64
+	for i := range days {
+		meanDay += days[i]
+		meanPounds += pounds[i]
+	}
+	meanDay /= float64(len(days))
+	meanPounds /= float64(len(days))
+
+	var cov, variance float64
+	for i := range days {
+		cov += (days[i] - meanDay) * (pounds[i] - meanPounds)
+		variance += (days[i] - meanDay) * (days[i] - meanDay)
+	}
+	if variance == 0 {
+		return 0, false
+	}
+	return cov / variance, true
+}
+
+// bodyCondition grades a weight against the breed's typical weight. Breed
+// norms are a single figure, so a band of 15% either side counts as ideal.
+func bodyCondition(pounds, breedWeight float64) string {
+	if pounds <= 0 || breedWeight <= 0 {
+		return "ideal"
+	}
+	ratio := pounds / breedWeight
+	switch {
+	case ratio < 0.85:
+		return "underweight"
+	case ratio <= 1.15:
+		return "ideal"
+	case ratio <= 1.3:
+		return "overweight"
+	default:
+		return "obese"
+	}
+}
+
+// calorieAdjustment recommends a change to daily calories: a step towards
+// the breed norm for the body condition, halved when the trend is already
+// heading there and increased when it is heading the wrong way.
+func calorieAdjustment(condition, trend string) int {
+	base := map[string]int{"underweight": 10, "ideal": 0, "overweight": -10, "obese": -20}[condition]
+	toward := map[string]string{"underweight": "gaining", "overweight": "losing", "obese": "losing"}[condition]
+
+	switch {
+	case trend == "insufficient-data" || trend == "stable":
+		return base
+	case condition == "ideal":
+		// Hold an ideal weight by nudging against the drift
+		if trend == "gaining" {
+			return -5
+		}
+		return 5
+	case trend == toward:
+		return int(math.Round(float64(base) / 2))
+	default:
+		return base + base/2
+	}
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
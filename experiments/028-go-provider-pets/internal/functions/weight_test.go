@@ -0,0 +1,101 @@
+package functions
+
+import (
+	"testing"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+func weighIn(measured string, pounds float64) resources.WeightLogState {
+	var entry resources.WeightLogState
+	entry.MeasuredOn, entry.RecordedAt, entry.Pounds = measured, measured+"T09:00:00Z", pounds
+	return entry
+}
+
+func TestWeightTrend(t *testing.T) {
+	tests := []struct {
+		name          string
+		logs          []resources.WeightLogState
+		current       float64
+		breedWeight   float64
+		wantTrend     string
+		wantRate      float64
+		wantCondition string
+		wantAdjust    int
+	}{
+		{
+			name:          "never weighed",
+			current:       25,
+			breedWeight:   25,
+			wantTrend:     "insufficient-data",
+			wantCondition: "ideal",
+		},
+		{
+			name:          "one weigh-in",
+			logs:          []resources.WeightLogState{weighIn("2026-06-01", 34)},
+			current:       25,
+			breedWeight:   25,
+			wantTrend:     "insufficient-data",
+			wantCondition: "obese",
+			wantAdjust:    -20,
+		},
+		{
+			name:          "holding steady",
+			logs:          []resources.WeightLogState{weighIn("2026-06-15", 25), weighIn("2026-06-01", 25), weighIn("2026-06-08", 25.05)},
+			breedWeight:   25,
+			wantTrend:     "stable",
+			wantCondition: "ideal",
+		},
+		{
+			name:          "ideal but creeping up",
+			logs:          []resources.WeightLogState{weighIn("2026-06-01", 25), weighIn("2026-06-15", 26)},
+			breedWeight:   25,
+			wantTrend:     "gaining",
+			wantRate:      0.5,
+			wantCondition: "ideal",
+			wantAdjust:    -5,
+		},
+		{
+			name:          "overweight and already losing",
+			logs:          []resources.WeightLogState{weighIn("2026-06-01", 32), weighIn("2026-06-08", 31.5), weighIn("2026-06-15", 31)},
+			breedWeight:   25,
+			wantTrend:     "losing",
+			wantRate:      -0.5,
+			wantCondition: "overweight",
+			wantAdjust:    -5,
+		},
+		{
+			name:          "obese and still gaining",
+			logs:          []resources.WeightLogState{weighIn("2026-06-01", 34), weighIn("2026-06-15", 36)},
+			breedWeight:   25,
+			wantTrend:     "gaining",
+			wantRate:      1,
+			wantCondition: "obese",
+			wantAdjust:    -30,
+		},
+		{
+			name:          "underweight and losing",
+			logs:          []resources.WeightLogState{weighIn("2026-06-01", 60), weighIn("2026-06-15", 58)},
+			breedWeight:   75,
+			wantTrend:     "losing",
+			wantRate:      -1,
+			wantCondition: "underweight",
+			wantAdjust:    15,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := weightTrend(tt.logs, tt.current, tt.breedWeight)
+			if got.Trend != tt.wantTrend || got.RatePerWeek != tt.wantRate {
+				t.Errorf("trend = %s at %g lbs/week, want %s at %g", got.Trend, got.RatePerWeek, tt.wantTrend, tt.wantRate)
+			}
+			if got.BodyCondition != tt.wantCondition || got.CalorieAdjustment != tt.wantAdjust {
+				t.Errorf("condition = %s, adjust %d%%, want %s, %d%%", got.BodyCondition, got.CalorieAdjustment, tt.wantCondition, tt.wantAdjust)
+			}
+			if got.Entries != len(tt.logs) {
+				t.Errorf("entries = %d, want %d", got.Entries, len(tt.logs))
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+// Package gcal keeps events in a Google Calendar in step with the registry.
+// It authenticates as a service account with the JWT bearer grant and only
+// needs the calendar.events scope; share the calendar with the service
+// account's email to let it write there.
+package gcal
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBaseURL is the Calendar API
+const DefaultBaseURL = "https://www.googleapis.com/calendar/v3"
+
+// Scope is the only permission the client asks for
+const Scope = "https://www.googleapis.com/auth/calendar.events"
+
+// Credentials are the fields of a service account key file the client uses
+type Credentials struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+
+	key *rsa.PrivateKey
+}
+
+// ParseCredentials reads a service account key file's JSON
+func ParseCredentials(data []byte) (*Credentials, error) {
+	var creds Credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("service account key: %w", err)
+	}
+	switch {
+	case creds.ClientEmail == "":
+		return nil, errors.New("service account key has no client_email")
+	case creds.TokenURI == "":
+		return nil, errors.New("service account key has no token_uri")
+	}
+	block, _ := pem.Decode([]byte(creds.PrivateKey))
+	if block == nil {
+		return nil, errors.New("service account key has no PEM private_key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("service account private_key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("service account private_key is not an RSA key")
+	}
+	creds.key = key
+	return &creds, nil
+}
+
+// Event is an all-day calendar entry. End is the last day of the event,
+// inclusive; the API's exclusive end is worked out when it is sent.
+type Event struct {
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+}
+
+// Client writes events to one calendar
+type Client struct {
+	HTTP       *http.Client
+	Creds      *Credentials
+	CalendarID string
+	BaseURL    string
+	Now        func() time.Time
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// expiryLeeway refreshes tokens this long before they expire
+const expiryLeeway = time.Minute
+
+// Insert creates an event and returns its ID
+func (c *Client) Insert(ctx context.Context, e Event) (string, error) {
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := c.call(ctx, http.MethodPost, c.eventsURL(""), &e, &created); err != nil {
+		return "", fmt.Errorf("creating calendar event: %w", err)
+	}
+	return created.ID, nil
+}
+
+// Update replaces an event's details
+func (c *Client) Update(ctx context.Context, id string, e Event) error {
+	if err := c.call(ctx, http.MethodPut, c.eventsURL(id), &e, nil); err != nil {
+		return fmt.Errorf("updating calendar event %s: %w", id, err)
+	}
+	return nil
+}
+
+// Delete removes an event. One already gone from the calendar is not an
+// error.
+func (c *Client) Delete(ctx context.Context, id string) error {
+	err := c.call(ctx, http.MethodDelete, c.eventsURL(id), nil, nil)
+	var status statusError
+	if errors.As(err, &status) && (status == http.StatusNotFound || status == http.StatusGone) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("deleting calendar event %s: %w", id, err)
+	}
+	return nil
+}
+
+func (c *Client) eventsURL(id string) string {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	u := fmt.Sprintf("%s/calendars/%s/events", strings.TrimSuffix(base, "/"), url.PathEscape(c.CalendarID))
+	if id != "" {
+		u += "/" + url.PathEscape(id)
+	}
+	return u
+}
+
+// statusError is an unexpected HTTP status from the API
+type statusError int
+
+func (s statusError) Error() string {
+	return fmt.Sprintf("unexpected status %d %s", int(s), http.StatusText(int(s)))
+}
+
+func (c *Client) call(ctx context.Context, method, endpoint string, e *Event, out any) error {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	var body bytes.Buffer
+	if e != nil {
+		json.NewEncoder(&body).Encode(wireEvent(*e))
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body.Len() > 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return statusError(resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// wireEvent is an Event as the API takes it
+func wireEvent(e Event) map[string]any {
+	return map[string]any{
+		"summary":     e.Summary,
+		"description": e.Description,
+		"start":       map[string]string{"date": e.Start.Format("2006-01-02")},
+		"end":         map[string]string{"date": e.End.AddDate(0, 0, 1).Format("2006-01-02")},
+	}
+}
+
+// accessToken returns a cached token, or exchanges a freshly signed JWT
+// for one when there is none or it is about to expire
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if c.token != "" && now.Before(c.expires) {
+		return c.token, nil
+	}
+
+	assertion, err := c.Creds.assertion(now)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Creds.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("service account token request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("service account token request: unexpected status %s", resp.Status)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("service account token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("service account token response has no access_token")
+	}
+	c.token = token.AccessToken
+	c.expires = now.Add(time.Duration(token.ExpiresIn)*time.Second - expiryLeeway)
+	return c.token, nil
+}
+
+func (c *Client) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+// assertion is the signed JWT the token endpoint exchanges for an access
+// token
+func (creds *Credentials) assertion(now time.Time) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]any{
+		"iss":   creds.ClientEmail,
+		"scope": Scope,
+		"aud":   creds.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	enc := base64.RawURLEncoding
+	signed := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, creds.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("signing service account assertion: %w", err)
+	}
+	return signed + "." + enc.EncodeToString(signature), nil
+}
@@ -0,0 +1,162 @@
+package gcal
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testKey is a service account key file for a freshly generated key
+func testKey(t *testing.T, tokenURI string) ([]byte, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "pets@lab.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    tokenURI,
+	})
+	return data, key
+}
+
+func TestParseCredentials(t *testing.T) {
+	valid, _ := testKey(t, "https://oauth2.googleapis.com/token")
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "valid", data: string(valid)},
+		{name: "not JSON", data: "pets", wantErr: "service account key"},
+		{name: "no email", data: `{"token_uri": "https://oauth2.googleapis.com/token"}`, wantErr: "no client_email"},
+		{name: "no key", data: `{"client_email": "a@b", "token_uri": "https://oauth2.googleapis.com/token"}`, wantErr: "no PEM private_key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCredentials([]byte(tt.data))
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatal(err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClientEvents(t *testing.T) {
+	var key *rsa.PrivateKey
+	tokens := 0
+	events := map[string]map[string]any{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(r.FormValue("assertion"), ".")
+		if r.FormValue("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" || len(parts) != 3 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		if rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature) != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		claims, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		if !strings.Contains(string(claims), `"scope":"`+Scope+`"`) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		tokens++
+		json.NewEncoder(w).Encode(map[string]any{"access_token": "ya29", "expires_in": 3600})
+	})
+	mux.HandleFunc("/calendars/vet@lab/events", func(w http.ResponseWriter, r *http.Request) {
+		var event map[string]any
+		json.NewDecoder(r.Body).Decode(&event)
+		events["evt1"] = event
+		json.NewEncoder(w).Encode(map[string]string{"id": "evt1"})
+	})
+	mux.HandleFunc("/calendars/vet@lab/events/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/calendars/vet@lab/events/")
+		if _, ok := events[id]; !ok {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			var event map[string]any
+			json.NewDecoder(r.Body).Decode(&event)
+			events[id] = event
+		case http.MethodDelete:
+			delete(events, id)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	authed := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/token" && r.Header.Get("Authorization") != "Bearer ya29" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	server := httptest.NewServer(authed(mux))
+	defer server.Close()
+
+	data, k := testKey(t, server.URL+"/token")
+	key = k
+	creds, err := ParseCredentials(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &Client{HTTP: server.Client(), Creds: creds, CalendarID: "vet@lab", BaseURL: server.URL}
+	ctx := context.Background()
+
+	day := time.Date(2027, 6, 15, 0, 0, 0, 0, time.UTC)
+	id, err := client.Insert(ctx, Event{Summary: "Rex: vet visit", Start: day, End: day})
+	if err != nil {
+		t.Fatal(err)
+	}
+	end := events[id]["end"].(map[string]any)["date"]
+	if id != "evt1" || events[id]["summary"] != "Rex: vet visit" || end != "2027-06-16" {
+		t.Errorf("inserted %q: %v", id, events[id])
+	}
+
+	if err := client.Update(ctx, id, Event{Summary: "Rex: rescheduled", Start: day, End: day.AddDate(0, 0, 2)}); err != nil {
+		t.Fatal(err)
+	}
+	if events[id]["summary"] != "Rex: rescheduled" {
+		t.Errorf("updated: %v", events[id])
+	}
+
+	for i := 0; i < 2; i++ {
+		// The second delete finds the event gone, which is fine
+		if err := client.Delete(ctx, id); err != nil {
+			t.Fatalf("delete %d: %v", i, err)
+		}
+	}
+	if tokens != 1 {
+		t.Errorf("fetched %d tokens, want the first reused", tokens)
+	}
+	if err := client.Update(ctx, id, Event{Start: day, End: day}); err == nil {
+		t.Errorf("updating a deleted event succeeded")
+	}
+}
@@ -0,0 +1,171 @@
+// Package httpclient builds the one HTTP client the provider uses for every
+// outbound call, so integrations share pooled connections and per-host
+// limits instead of each constructing its own client. The client counts
+// requests, failures and time spent per host; Snapshot reports them.
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Defaults for Options fields left at zero
+const (
+	DefaultTimeout         = 30 * time.Second
+	DefaultMaxConnsPerHost = 8
+)
+
+// Options tune the client
+type Options struct {
+	Timeout         time.Duration // whole request, including reading the body
+	MaxConnsPerHost int           // connections open to one host at a time
+	// Proxy is the URL of the proxy for every request. When it is empty the
+	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY variables decide.
+	Proxy string
+	// CABundle is a PEM file of certificate authorities to trust on top of
+	// the system ones, for networks that intercept TLS.
+	CABundle string
+	// ClientCert and ClientKey are a PEM certificate and private key to
+	// present to servers that require mutual TLS. Set both or neither.
+	ClientCert string
+	ClientKey  string
+	// OAuth2 authenticates requests to its hosts with client-credentials
+	// tokens, fetched and refreshed as needed
+	OAuth2 *OAuth2
+}
+
+// New creates a client with a pooled transport. Clients from New report
+// into the same per-host counters.
+func New(opts Options) (*http.Client, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxConnsPerHost <= 0 {
+		opts.MaxConnsPerHost = DefaultMaxConnsPerHost
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if opts.Proxy != "" {
+		u, err := url.Parse(opts.Proxy)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", opts.Proxy)
+		}
+		proxy = http.ProxyURL(u)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if opts.CABundle != "" {
+		pool, err := loadCABundle(opts.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if opts.ClientCert != "" || opts.ClientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(opts.ClientCert), []byte(opts.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := &http.Transport{
+		Proxy:           proxy,
+		TLSClientConfig: tlsConfig,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          64,
+		MaxIdleConnsPerHost:   opts.MaxConnsPerHost,
+		MaxConnsPerHost:       opts.MaxConnsPerHost,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+	var rt http.RoundTripper = instrumented{next: transport}
+	if opts.OAuth2 != nil {
+		auth, err := newBearer(rt, *opts.OAuth2, opts.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		rt = auth
+	}
+	return &http.Client{Timeout: opts.Timeout, Transport: rt}, nil
+}
+
+// loadCABundle adds the certificates in a PEM file to the system pool
+func loadCABundle(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("CA bundle %s holds no PEM certificates", path)
+	}
+	return pool, nil
+}
+
+// Stats are the counters for one host since the provider started
+type Stats struct {
+	Host     string `pulumi:"host"`
+	Requests int64  `pulumi:"requests"`
+	Failures int64  `pulumi:"failures"` // transport errors and 5xx responses
+	TotalMs  int64  `pulumi:"totalMs"`  // time until the response headers arrived
+}
+
+var (
+	statsMu sync.Mutex
+	hosts   = map[string]*Stats{}
+)
+
+// instrumented counts every round trip against its host
+type instrumented struct {
+	next http.RoundTripper
+}
+
+func (t instrumented) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start)
+
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	stats, ok := hosts[req.URL.Host]
+	if !ok {
+		stats = &Stats{Host: req.URL.Host}
+		hosts[req.URL.Host] = stats
+	}
+	stats.Requests++
+	stats.TotalMs += elapsed.Milliseconds()
+	if err != nil || resp.StatusCode >= 500 {
+		stats.Failures++
+	}
+	return resp, err
+}
+
+// Snapshot returns the counters of every host called so far, ordered by host
+func Snapshot() []Stats {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+
+	out := make([]Stats, 0, len(hosts))
+	for _, stats := range hosts {
+		out = append(out, *stats)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Host < out[j].Host })
+	return out
+}
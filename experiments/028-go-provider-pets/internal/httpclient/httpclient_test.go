@@ -0,0 +1,241 @@
+package httpclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewDefaults(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      Options
+		wantTime  time.Duration
+		wantConns int
+	}{
+		{name: "zero options", wantTime: DefaultTimeout, wantConns: DefaultMaxConnsPerHost},
+		{name: "explicit", opts: Options{Timeout: time.Second, MaxConnsPerHost: 2}, wantTime: time.Second, wantConns: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := New(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if client.Timeout != tt.wantTime {
+				t.Errorf("timeout = %v, want %v", client.Timeout, tt.wantTime)
+			}
+			transport := client.Transport.(instrumented).next.(*http.Transport)
+			if transport.MaxConnsPerHost != tt.wantConns {
+				t.Errorf("max conns per host = %d, want %d", transport.MaxConnsPerHost, tt.wantConns)
+			}
+		})
+	}
+}
+
+func TestCountsRequestsPerHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/ok", "/ok", "/broken"} {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		resp.Body.Close()
+	}
+
+	host := mustHost(t, server.URL)
+	for _, stats := range Snapshot() {
+		if stats.Host == host {
+			if stats.Requests != 3 || stats.Failures != 1 {
+				t.Errorf("stats = %+v, want 3 requests, 1 failure", stats)
+			}
+			return
+		}
+	}
+	t.Errorf("%s missing from snapshot", host)
+}
+
+func mustHost(t *testing.T, raw string) string {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Host
+}
+
+func TestProxy(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxy   string
+		want    string
+		wantErr string
+	}{
+		{name: "explicit proxy", proxy: "http://proxy.lab:3128", want: "http://proxy.lab:3128"},
+		{name: "not a URL", proxy: "proxy.lab:3128", wantErr: "invalid proxy URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := New(Options{Proxy: tt.proxy})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			transport := client.Transport.(instrumented).next.(*http.Transport)
+			req, _ := http.NewRequest(http.MethodGet, "https://registry.example.com", nil)
+			got, err := transport.Proxy(req)
+			if err != nil || got.String() != tt.want {
+				t.Errorf("proxy = %v, %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	bundle := filepath.Join(dir, "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, cert, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	empty := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(empty, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := New(Options{CABundle: empty}); err == nil {
+		t.Error("a bundle without certificates was accepted")
+	}
+	if _, err := New(Options{CABundle: filepath.Join(dir, "missing.pem")}); err == nil {
+		t.Error("a missing bundle was accepted")
+	}
+
+	client, err := New(Options{CABundle: bundle})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("server signed by the bundled CA was rejected: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestClientCertificate(t *testing.T) {
+	certPEM, keyPEM := selfSignedClientCert(t)
+	clientCert, err := x509.ParseCertificate(mustDecodePEM(t, certPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	trusted := x509.NewCertPool()
+	trusted.AddCert(clientCert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: trusted}
+	server.StartTLS()
+	defer server.Close()
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	serverCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, serverCA, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		cert, key  string
+		wantNewErr bool
+		wantGetErr bool
+	}{
+		{name: "with certificate", cert: certPEM, key: keyPEM},
+		{name: "without certificate", wantGetErr: true},
+		{name: "key missing", cert: certPEM, wantNewErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := New(Options{CABundle: bundle, ClientCert: tt.cert, ClientKey: tt.key})
+			if (err != nil) != tt.wantNewErr {
+				t.Fatalf("New err = %v, want error %v", err, tt.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+			resp, err := client.Get(server.URL)
+			if (err != nil) != tt.wantGetErr {
+				t.Fatalf("GET err = %v, want error %v", err, tt.wantGetErr)
+			}
+			if err == nil {
+				resp.Body.Close()
+			}
+		})
+	}
+}
+
+// selfSignedClientCert returns a PEM certificate and key for client auth
+func selfSignedClientCert(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "pets-provider"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func mustDecodePEM(t *testing.T, data string) []byte {
+	t.Helper()
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		t.Fatal("no PEM block")
+	}
+	return block.Bytes
+}
@@ -0,0 +1,153 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OAuth2 configures the client-credentials grant. Tokens are only sent to
+// Hosts, so a bearer token never leaks to an arbitrary URL such as an
+// adoption reviewer.
+type OAuth2 struct {
+	ClientID     string
+	ClientSecret string
+	TokenURL     string
+	Scopes       []string
+	Hosts        []string // host or host:port of every API that takes the token
+}
+
+// expiryLeeway refreshes tokens this long before the server says they expire
+const expiryLeeway = 30 * time.Second
+
+// tokenSource fetches and caches client-credentials tokens
+type tokenSource struct {
+	config OAuth2
+	client *http.Client // for the token endpoint, without bearer auth
+	now    func() time.Time
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time // zero when the server gave no lifetime
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// get returns a cached token, or fetches a new one when there is none or
+// it is about to expire.
+func (s *tokenSource) get(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && (s.expires.IsZero() || s.now().Before(s.expires)) {
+		return s.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(s.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.config.Scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("oauth2 token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(s.config.ClientID), url.QueryEscape(s.config.ClientSecret))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("oauth2 token request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("oauth2 token request: unexpected status %s", resp.Status)
+	}
+	var token tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("oauth2 token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("oauth2 token response has no access_token")
+	}
+	if token.TokenType != "" && !strings.EqualFold(token.TokenType, "bearer") {
+		return "", fmt.Errorf("oauth2 token type %q is not supported", token.TokenType)
+	}
+
+	s.token, s.expires = token.AccessToken, time.Time{}
+	if token.ExpiresIn > 0 {
+		s.expires = s.now().Add(time.Duration(token.ExpiresIn)*time.Second - expiryLeeway)
+	}
+	return s.token, nil
+}
+
+// invalidate drops token if it is still the cached one, so the next
+// request fetches a fresh token.
+func (s *tokenSource) invalidate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == token {
+		s.token = ""
+	}
+}
+
+// bearer adds the OAuth2 token to requests for the configured hosts
+type bearer struct {
+	next   http.RoundTripper
+	source *tokenSource
+	hosts  map[string]bool
+}
+
+func newBearer(next http.RoundTripper, config OAuth2, timeout time.Duration) (*bearer, error) {
+	switch {
+	case config.ClientID == "" || config.ClientSecret == "":
+		return nil, errors.New("oauth2 needs a client ID and secret")
+	case len(config.Hosts) == 0:
+		return nil, errors.New("oauth2 needs at least one host to send tokens to")
+	}
+	if u, err := url.Parse(config.TokenURL); err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid oauth2 token URL %q", config.TokenURL)
+	}
+
+	hosts := map[string]bool{}
+	for _, host := range config.Hosts {
+		hosts[strings.ToLower(host)] = true
+	}
+	return &bearer{
+		next: next,
+		source: &tokenSource{
+			config: config,
+			client: &http.Client{Timeout: timeout, Transport: next},
+			now:    time.Now,
+		},
+		hosts: hosts,
+	}, nil
+}
+
+func (b *bearer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !b.hosts[strings.ToLower(req.URL.Host)] && !b.hosts[strings.ToLower(req.URL.Hostname())] {
+		return b.next.RoundTrip(req)
+	}
+
+	token, err := b.source.get(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	authed := req.Clone(req.Context())
+	authed.Header.Set("Authorization", "Bearer "+token)
+	resp, err := b.next.RoundTrip(authed)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		// Revoked or rotated early; the caller's retry gets a new token
+		b.source.invalidate(token)
+	}
+	return resp, err
+}
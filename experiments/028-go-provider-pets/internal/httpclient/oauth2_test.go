@@ -0,0 +1,152 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// oauthServers starts a token endpoint that issues numbered tokens and an
+// API that answers 401 to anything but the current token.
+func oauthServers(t *testing.T, expiresIn int64) (tokenServer, api *httptest.Server, issued *atomic.Int64) {
+	t.Helper()
+	issued = &atomic.Int64{}
+	tokenServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "lab" || secret != "s3cret" || r.FormValue("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		n := issued.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token": fmt.Sprintf("token-%d", n),
+			"token_type":   "Bearer",
+			"expires_in":   expiresIn,
+			"scope":        r.FormValue("scope"),
+		})
+	}))
+	t.Cleanup(tokenServer.Close)
+
+	api = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := fmt.Sprintf("Bearer token-%d", issued.Load())
+		if r.Header.Get("Authorization") != want {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	t.Cleanup(api.Close)
+	return tokenServer, api, issued
+}
+
+func get(t *testing.T, client *http.Client, url string) int {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestOAuth2ReusesToken(t *testing.T) {
+	tokenServer, api, issued := oauthServers(t, 3600)
+	client, err := New(Options{OAuth2: &OAuth2{
+		ClientID: "lab", ClientSecret: "s3cret", TokenURL: tokenServer.URL,
+		Scopes: []string{"registry.read"}, Hosts: []string{mustHost(t, api.URL)},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if status := get(t, client, api.URL); status != http.StatusOK {
+			t.Fatalf("request %d: status %d", i, status)
+		}
+	}
+	if issued.Load() != 1 {
+		t.Errorf("issued %d tokens, want 1", issued.Load())
+	}
+}
+
+func TestOAuth2Refresh(t *testing.T) {
+	tokenServer, api, issued := oauthServers(t, 3600)
+	client, err := New(Options{OAuth2: &OAuth2{
+		ClientID: "lab", ClientSecret: "s3cret", TokenURL: tokenServer.URL, Hosts: []string{mustHost(t, api.URL)},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := client.Transport.(*bearer).source
+	clock := time.Now()
+	source.now = func() time.Time { return clock }
+
+	get(t, client, api.URL)
+	clock = clock.Add(time.Hour - expiryLeeway)
+	get(t, client, api.URL)
+	if issued.Load() != 2 {
+		t.Errorf("issued %d tokens, want a refresh before expiry", issued.Load())
+	}
+
+	// A token the API stops accepting is dropped after its 401
+	issued.Add(1)
+	if status := get(t, client, api.URL); status != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401 for the stale token", status)
+	}
+	if status := get(t, client, api.URL); status != http.StatusOK {
+		t.Errorf("status = %d, want a fresh token after the 401", status)
+	}
+}
+
+func TestOAuth2OnlyConfiguredHosts(t *testing.T) {
+	tokenServer, api, issued := oauthServers(t, 3600)
+	var leaked atomic.Bool
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		leaked.Store(r.Header.Get("Authorization") != "")
+	}))
+	defer other.Close()
+
+	client, err := New(Options{OAuth2: &OAuth2{
+		ClientID: "lab", ClientSecret: "s3cret", TokenURL: tokenServer.URL, Hosts: []string{mustHost(t, api.URL)},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	get(t, client, other.URL)
+	if leaked.Load() || issued.Load() != 0 {
+		t.Error("token fetched or sent for a host that isn't configured")
+	}
+}
+
+func TestOAuth2Config(t *testing.T) {
+	valid := OAuth2{ClientID: "lab", ClientSecret: "s3cret", TokenURL: "https://auth.lab/token", Hosts: []string{"api.lab"}}
+	tests := []struct {
+		name    string
+		edit    func(*OAuth2)
+		wantErr string
+	}{
+		{name: "valid", edit: func(*OAuth2) {}},
+		{name: "no secret", edit: func(c *OAuth2) { c.ClientSecret = "" }, wantErr: "client ID and secret"},
+		{name: "no hosts", edit: func(c *OAuth2) { c.Hosts = nil }, wantErr: "at least one host"},
+		{name: "bad token URL", edit: func(c *OAuth2) { c.TokenURL = "auth.lab/token" }, wantErr: "invalid oauth2 token URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid
+			tt.edit(&config)
+			_, err := New(Options{OAuth2: &config})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	pprovider "github.com/pulumi/pulumi/pkg/v3/resource/provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+// dogToken is the Dog resource, whose methods Serve adds
+const dogToken = "pets:index:Dog"
+
+// dogMethods are the Dog's methods and the functions that carry them out.
+// A method takes the function's arguments except for the dog's ID, which
+// is the dog it is called on, and dryRun, which the engine says.
+var dogMethods = map[string]string{
+	"feed":       "pets:index:feedDog",
+	"giveTreat":  "pets:index:giveTreat",
+	"recordWalk": "pets:index:recordWalk",
+}
+
+// Serve runs prov as the plugin named name. This version of the provider
+// framework answers Call with Unimplemented and can't declare methods, so
+// Serve adds the Dog's methods to the schema and answers their calls itself
+// with the functions behind them.
+func Serve(name, version string, prov p.Provider) error {
+	server := p.RawServer(name, version, prov)
+	return pprovider.Main(name, func(host *pprovider.HostClient) (rpc.ResourceProviderServer, error) {
+		inner, err := server(host)
+		if err != nil {
+			return nil, err
+		}
+		return methodServer{inner}, nil
+	})
+}
+
+// methodServer is a plugin server that also serves the Dog's methods
+type methodServer struct {
+	rpc.ResourceProviderServer
+}
+
+func (s methodServer) GetSchema(ctx context.Context, req *rpc.GetSchemaRequest) (*rpc.GetSchemaResponse, error) {
+	resp, err := s.ResourceProviderServer.GetSchema(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	spec, err := withDogMethods(resp.GetSchema())
+	if err != nil {
+		return nil, fmt.Errorf("adding Dog methods to the schema: %w", err)
+	}
+	return &rpc.GetSchemaResponse{Schema: spec}, nil
+}
+
+// Call runs a Dog method as an invoke of its function on the dog's ID
+func (s methodServer) Call(ctx context.Context, req *rpc.CallRequest) (*rpc.CallResponse, error) {
+	resourceToken, method, _ := strings.Cut(req.GetTok(), "/")
+	function, ok := dogMethods[method]
+	if resourceToken != dogToken || !ok {
+		return s.ResourceProviderServer.Call(ctx, req)
+	}
+
+	args, err := plugin.UnmarshalProperties(req.GetArgs(), plugin.MarshalOptions{KeepUnknowns: true, KeepResources: true})
+	if err != nil {
+		return nil, err
+	}
+	self := args["__self__"]
+	if !self.IsResourceReference() {
+		return nil, fmt.Errorf("%s: __self__ is not a resource", req.GetTok())
+	}
+	id, _ := self.ResourceReferenceValue().IDString()
+	if id == "" || args.ContainsUnknowns() {
+		if req.GetDryRun() {
+			// A dog still to be created, or arguments still to be worked
+			// out: nothing is known about the result yet
+			return &rpc.CallResponse{}, nil
+		}
+		return nil, fmt.Errorf("%s: the dog has no ID", req.GetTok())
+	}
+
+	delete(args, "__self__")
+	args["dogId"] = resource.NewStringProperty(id)
+	args["dryRun"] = resource.NewBoolProperty(req.GetDryRun())
+	invokeArgs, err := plugin.MarshalProperties(args, plugin.MarshalOptions{})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.Invoke(ctx, &rpc.InvokeRequest{Tok: function, Args: invokeArgs})
+	if err != nil {
+		return nil, err
+	}
+	failures := make([]*rpc.CheckFailure, len(resp.GetFailures()))
+	for i, failure := range resp.GetFailures() {
+		failures[i] = &rpc.CheckFailure{Property: failure.GetProperty(), Reason: failure.GetReason()}
+	}
+	return &rpc.CallResponse{Return: resp.GetReturn(), Failures: failures}, nil
+}
+
+// withDogMethods is the package schema spec with the Dog's methods declared
+// on it, each a function made from the one behind it
+func withDogMethods(spec string) (string, error) {
+	var pkg map[string]any
+	if err := json.Unmarshal([]byte(spec), &pkg); err != nil {
+		return "", err
+	}
+	resources, _ := pkg["resources"].(map[string]any)
+	dog, ok := resources[dogToken].(map[string]any)
+	if !ok {
+		return "", fmt.Errorf("no %s resource", dogToken)
+	}
+	functions, _ := pkg["functions"].(map[string]any)
+
+	methods := map[string]any{}
+	names := make([]string, 0, len(dogMethods))
+	for name := range dogMethods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		function, ok := functions[dogMethods[name]].(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("no %s function", dogMethods[name])
+		}
+		token := dogToken + "/" + name
+		functions[token] = dogMethod(function)
+		methods[name] = token
+	}
+	dog["methods"] = methods
+	out, err := json.Marshal(pkg)
+	return string(out), err
+}
+
+// dogMethod is the method spec for function: its inputs with the dog the
+// method is called on in place of dogId and dryRun, and the same outputs
+func dogMethod(function map[string]any) map[string]any {
+	method := map[string]any{}
+	for key, value := range function {
+		method[key] = value
+	}
+	in, _ := function["inputs"].(map[string]any)
+	properties := map[string]any{
+		"__self__": map[string]any{"$ref": "#/resources/" + dogToken},
+	}
+	inProperties, _ := in["properties"].(map[string]any)
+	for name, property := range inProperties {
+		if name != "dogId" && name != "dryRun" {
+			properties[name] = property
+		}
+	}
+	required := []any{"__self__"}
+	inRequired, _ := in["required"].([]any)
+	for _, name := range inRequired {
+		if name != "dogId" && name != "dryRun" {
+			required = append(required, name)
+		}
+	}
+	inputs := map[string]any{}
+	for key, value := range in {
+		inputs[key] = value
+	}
+	inputs["properties"] = properties
+	inputs["required"] = required
+	method["inputs"] = inputs
+	return method
+}
@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+func TestSchemaDeclaresDogMethods(t *testing.T) {
+	function := func(arg string) schema.FunctionSpec {
+		return schema.FunctionSpec{
+			Inputs: &schema.ObjectTypeSpec{
+				Type: "object",
+				Properties: map[string]schema.PropertySpec{
+					"dogId":  {TypeSpec: schema.TypeSpec{Type: "string"}},
+					arg:      {TypeSpec: schema.TypeSpec{Type: "integer"}},
+					"dryRun": {TypeSpec: schema.TypeSpec{Type: "boolean"}},
+				},
+				Required: []string{"dogId", arg},
+			},
+			Outputs: &schema.ObjectTypeSpec{
+				Type:       "object",
+				Properties: map[string]schema.PropertySpec{"happiness": {TypeSpec: schema.TypeSpec{Type: "integer"}}},
+			},
+		}
+	}
+	spec, err := json.Marshal(schema.PackageSpec{
+		Name: "pets",
+		Resources: map[string]schema.ResourceSpec{
+			dogToken: {ObjectTypeSpec: schema.ObjectTypeSpec{Type: "object"}},
+		},
+		Functions: map[string]schema.FunctionSpec{
+			"pets:index:feedDog":    function("portionGrams"),
+			"pets:index:giveTreat":  function("count"),
+			"pets:index:recordWalk": function("minutes"),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withMethods, err := withDogMethods(string(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pkgSpec schema.PackageSpec
+	if err := json.Unmarshal([]byte(withMethods), &pkgSpec); err != nil {
+		t.Fatal(err)
+	}
+	pkg, diags, err := schema.BindSpec(pkgSpec, nil)
+	if err != nil || diags.HasErrors() {
+		t.Fatalf("binding the schema: %v %v", err, diags)
+	}
+	dog, ok := pkg.GetResource(dogToken)
+	if !ok {
+		t.Fatalf("no %s in the schema", dogToken)
+	}
+	var names []string
+	for _, method := range dog.Methods {
+		names = append(names, method.Name)
+		for _, property := range method.Function.Inputs.Properties {
+			if property.Name == "dogId" || property.Name == "dryRun" {
+				t.Errorf("%s takes %s", method.Name, property.Name)
+			}
+		}
+	}
+	if strings.Join(names, ",") != "feed,giveTreat,recordWalk" {
+		t.Errorf("Dog methods = %q, want feed, giveTreat and recordWalk", names)
+	}
+}
+
+// invokeServer answers invokes with their arguments, and remembers the token
+type invokeServer struct {
+	rpc.UnimplementedResourceProviderServer
+	tok string
+}
+
+func (s *invokeServer) Invoke(_ context.Context, req *rpc.InvokeRequest) (*rpc.InvokeResponse, error) {
+	s.tok = req.GetTok()
+	return &rpc.InvokeResponse{Return: req.GetArgs()}, nil
+}
+
+func TestCallRunsTheMethodsFunction(t *testing.T) {
+	self := resource.MakeCustomResourceReference("urn:pulumi:dev::lab::pets:index:Dog::rex", "dog-rex-1", "")
+	tests := []struct {
+		name   string
+		id     resource.PropertyValue
+		dryRun bool
+		want   resource.PropertyMap
+	}{
+		{
+			name: "up",
+			id:   resource.NewStringProperty("dog-rex-1"),
+			want: resource.PropertyMap{
+				"dogId":        resource.NewStringProperty("dog-rex-1"),
+				"dryRun":       resource.NewBoolProperty(false),
+				"portionGrams": resource.NewNumberProperty(200),
+			},
+		},
+		{
+			name:   "preview",
+			id:     resource.NewStringProperty("dog-rex-1"),
+			dryRun: true,
+			want: resource.PropertyMap{
+				"dogId":        resource.NewStringProperty("dog-rex-1"),
+				"dryRun":       resource.NewBoolProperty(true),
+				"portionGrams": resource.NewNumberProperty(200),
+			},
+		},
+		{
+			name:   "preview of a new dog",
+			id:     resource.MakeComputed(resource.NewStringProperty("")),
+			dryRun: true,
+			want:   resource.PropertyMap{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref := self.ResourceReferenceValue()
+			ref.ID = tt.id
+			args, err := plugin.MarshalProperties(resource.PropertyMap{
+				"__self__":     resource.NewResourceReferenceProperty(ref),
+				"portionGrams": resource.NewNumberProperty(200),
+			}, plugin.MarshalOptions{KeepUnknowns: true, KeepResources: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			inner := &invokeServer{}
+			resp, err := methodServer{inner}.Call(context.Background(), &rpc.CallRequest{Tok: dogToken + "/feed", Args: args, DryRun: tt.dryRun})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := plugin.UnmarshalProperties(resp.GetReturn(), plugin.MarshalOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.DeepEquals(tt.want) {
+				t.Errorf("invoked with %v, want %v", got, tt.want)
+			}
+			if len(tt.want) > 0 && inner.tok != "pets:index:feedDog" {
+				t.Errorf("invoked %q, want pets:index:feedDog", inner.tok)
+			}
+		})
+	}
+}
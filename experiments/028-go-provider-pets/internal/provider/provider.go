@@ -0,0 +1,106 @@
+// Package provider assembles the pets provider from its resources, functions
+// and configuration, for the plugin binary and the replay harness alike.
+package provider
+
+import (
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/dashboard"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/functions"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+// New creates the provider using infer
+func New() p.Provider {
+	registry.DashboardHandler = dashboard.Handler
+	return withURNs(infer.Provider(infer.Options{
+		Resources: []infer.InferredResource{
+			infer.Resource[resources.Dog, resources.DogArgs, resources.DogState](),
+			infer.Resource[resources.DogWalk, resources.DogWalkArgs, resources.DogWalkState](),
+			infer.Resource[resources.WeightLog, resources.WeightLogArgs, resources.WeightLogState](),
+			infer.Resource[resources.DietTransition, resources.DietTransitionArgs, resources.DietTransitionState](),
+			infer.Resource[resources.AllergyRecord, resources.AllergyRecordArgs, resources.AllergyRecordState](),
+			infer.Resource[resources.ToyInventory, resources.ToyInventoryArgs, resources.ToyInventoryState](),
+			infer.Resource[resources.SubscriptionBox, resources.SubscriptionBoxArgs, resources.SubscriptionBoxState](),
+			infer.Resource[resources.NotificationChannel, resources.NotificationChannelArgs, resources.NotificationChannelState](),
+			infer.Resource[resources.SmsReminder, resources.SmsReminderArgs, resources.SmsReminderState](),
+			infer.Resource[resources.SmartFeeder, resources.SmartFeederArgs, resources.SmartFeederState](),
+			infer.Resource[resources.GpsCollar, resources.GpsCollarArgs, resources.GpsCollarState](),
+			infer.Resource[resources.Microchip, resources.MicrochipArgs, resources.MicrochipState](),
+			infer.Resource[resources.PetDoor, resources.PetDoorArgs, resources.PetDoorState](),
+			infer.Resource[resources.PetCamera, resources.PetCameraArgs, resources.PetCameraState](),
+			infer.Resource[resources.VeterinaryVisit, resources.VeterinaryVisitArgs, resources.VeterinaryVisitState](),
+			infer.Resource[resources.DogTraining, resources.DogTrainingArgs, resources.DogTrainingState](),
+			infer.Resource[resources.PetInsurance, resources.PetInsuranceArgs, resources.PetInsuranceState](),
+			infer.Resource[resources.WellnessPlan, resources.WellnessPlanArgs, resources.WellnessPlanState](),
+			infer.Resource[resources.ServiceDogCertification, resources.ServiceDogCertificationArgs, resources.ServiceDogCertificationState](),
+			infer.Resource[resources.TherapyDogVisit, resources.TherapyDogVisitArgs, resources.TherapyDogVisitState](),
+			infer.Resource[resources.WorkingDog, resources.WorkingDogArgs, resources.WorkingDogState](),
+			infer.Resource[resources.RegistrySnapshot, resources.RegistrySnapshotArgs, resources.RegistrySnapshotState](),
+			infer.Resource[resources.PetShelter, resources.PetShelterArgs, resources.PetShelterState](),
+			infer.Resource[resources.BulkDogIntake, resources.BulkDogIntakeArgs, resources.BulkDogIntakeState](),
+			infer.Resource[resources.Adoption, resources.AdoptionArgs, resources.AdoptionState](),
+			infer.Resource[resources.AdoptionWaitlist, resources.AdoptionWaitlistArgs, resources.AdoptionWaitlistState](),
+			infer.Resource[resources.FosterPlacement, resources.FosterPlacementArgs, resources.FosterPlacementState](),
+			infer.Resource[resources.LostPetReport, resources.LostPetReportArgs, resources.LostPetReportState](),
+			infer.Resource[resources.PetSitterBooking, resources.PetSitterBookingArgs, resources.PetSitterBookingState](),
+			infer.Resource[resources.DogPark, resources.DogParkArgs, resources.DogParkState](),
+			infer.Resource[resources.DogParkVisit, resources.DogParkVisitArgs, resources.DogParkVisitState](),
+			infer.Resource[resources.Playdate, resources.PlaydateArgs, resources.PlaydateState](),
+			infer.Resource[resources.Donation, resources.DonationArgs, resources.DonationState](),
+			infer.Resource[resources.VolunteerShift, resources.VolunteerShiftArgs, resources.VolunteerShiftState](),
+			infer.Resource[resources.Listing, resources.ListingArgs, resources.ListingState](),
+			infer.Resource[resources.PhotoAlbum, resources.PhotoAlbumArgs, resources.PhotoAlbumState](),
+		},
+		Functions: []infer.InferredFunction{
+			infer.Function[functions.BackupRegistry, functions.BackupRegistryArgs, functions.BackupRegistryResult](),
+			infer.Function[functions.RestoreRegistry, functions.RestoreRegistryArgs, functions.RestoreRegistryResult](),
+			infer.Function[functions.RollbackRegistry, functions.RollbackRegistryArgs, functions.RollbackRegistryResult](),
+			infer.Function[functions.ListDogs, functions.ListDogsArgs, functions.ListDogsResult](),
+			infer.Function[functions.ListWalks, functions.PageArgs, functions.ListWalksResult](),
+			infer.Function[functions.ListVisits, functions.ListVisitsArgs, functions.ListVisitsResult](),
+			infer.Function[functions.Approve, functions.ApproveArgs, functions.ApproveResult](),
+			infer.Function[functions.GetFullHistory, functions.GetFullHistoryArgs, functions.GetFullHistoryResult](),
+			infer.Function[functions.GetOwnershipHistory, functions.GetOwnershipHistoryArgs, functions.GetOwnershipHistoryResult](),
+			infer.Function[functions.FeedDog, functions.FeedDogArgs, resources.DogStats](),
+			infer.Function[functions.GiveTreat, functions.GiveTreatArgs, resources.DogStats](),
+			infer.Function[functions.RecordWalk, functions.RecordWalkArgs, resources.DogStats](),
+			infer.Function[functions.GetBehaviorTimeline, functions.GetBehaviorTimelineArgs, functions.GetBehaviorTimelineResult](),
+			infer.Function[functions.GetCacheStats, functions.GetCacheStatsArgs, functions.GetCacheStatsResult](),
+			infer.Function[functions.GetHttpStats, functions.GetHttpStatsArgs, functions.GetHttpStatsResult](),
+			infer.Function[functions.RandomPetFact, functions.RandomPetFactArgs, functions.RandomPetFactResult](),
+			infer.Function[functions.GetBreedImage, functions.GetBreedImageArgs, functions.GetBreedImageResult](),
+			infer.Function[functions.NextVaccinationDue, functions.NextVaccinationDueArgs, functions.NextVaccinationDueResult](),
+			infer.Function[functions.WeightTrendAnalysis, functions.WeightTrendAnalysisArgs, functions.WeightTrendAnalysisResult](),
+			infer.Function[functions.ExportCalendar, functions.ExportCalendarArgs, functions.ExportCalendarResult](),
+			infer.Function[functions.CheckGeofence, functions.CheckGeofenceArgs, functions.CheckGeofenceResult](),
+			infer.Function[functions.ComparePolicies, functions.ComparePoliciesArgs, functions.ComparePoliciesResult](),
+			infer.Function[functions.ConvertCurrency, functions.ConvertCurrencyArgs, functions.ConvertCurrencyResult](),
+			infer.Function[functions.ServiceDogExpenseReport, functions.ServiceDogExpenseReportArgs, functions.ServiceDogExpenseReportResult](),
+			infer.Function[functions.GetShelterStatistics, functions.GetShelterStatisticsArgs, functions.GetShelterStatisticsResult](),
+			infer.Function[functions.GetCampaignTotals, functions.GetCampaignTotalsArgs, functions.GetCampaignTotalsResult](),
+			infer.Function[functions.ExportVolunteerSchedule, functions.ExportVolunteerScheduleArgs, functions.ExportVolunteerScheduleResult](),
+			infer.Function[functions.ListAdoptableDogs, functions.ListAdoptableDogsArgs, functions.ListAdoptableDogsResult](),
+			infer.Function[functions.MatchFoundPet, resources.FoundPet, functions.MatchFoundPetResult](),
+			infer.Function[functions.CompatibilityScore, resources.CompatibilityScoreArgs, resources.Compatibility](),
+			infer.Function[functions.DogAgeInHumanYears, resources.DogAge, resources.HumanAge](),
+			infer.Function[functions.EstimateLifeExpectancy, functions.EstimateLifeExpectancyArgs, functions.EstimateLifeExpectancyResult](),
+			infer.Function[functions.BreedRecommendation, functions.BreedRecommendationArgs, functions.BreedRecommendationResult](),
+			infer.Function[functions.CheckNameAvailability, resources.NameCheck, resources.NameAvailability](),
+		},
+		Components: []infer.InferredComponent{
+			infer.Component[resources.AdoptionEvent, resources.AdoptionEventArgs, *resources.AdoptionEventState](),
+		},
+		Config: infer.Config[*registry.Config](),
+		// Tokens are named after the Go package otherwise; the SDKs and the
+		// component's own invokes have everything in the index module
+		ModuleMap: map[tokens.ModuleName]tokens.ModuleName{
+			"resources": "index",
+			"functions": "index",
+			"registry":  "index",
+		},
+	}))
+}
@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"context"
+
+	p "github.com/pulumi/pulumi-go-provider"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// withURNs is prov with the URN of each resource RPC in its ctx, for the
+// registry to key idempotent creates on; infer only hands resources their
+// logical name. Check also tells the registry whether the engine already
+// has the resource.
+func withURNs(prov p.Provider) p.Provider {
+	wrapped := prov
+	if check := prov.Check; check != nil {
+		wrapped.Check = func(ctx context.Context, req p.CheckRequest) (p.CheckResponse, error) {
+			registry.Checked(string(req.Urn), len(req.Olds) > 0)
+			return check(registry.WithURN(ctx, string(req.Urn)), req)
+		}
+	}
+	if diff := prov.Diff; diff != nil {
+		wrapped.Diff = func(ctx context.Context, req p.DiffRequest) (p.DiffResponse, error) {
+			return diff(registry.WithURN(ctx, string(req.Urn)), req)
+		}
+	}
+	if create := prov.Create; create != nil {
+		wrapped.Create = func(ctx context.Context, req p.CreateRequest) (p.CreateResponse, error) {
+			return create(registry.WithURN(ctx, string(req.Urn)), req)
+		}
+	}
+	if read := prov.Read; read != nil {
+		wrapped.Read = func(ctx context.Context, req p.ReadRequest) (p.ReadResponse, error) {
+			return read(registry.WithURN(ctx, string(req.Urn)), req)
+		}
+	}
+	if update := prov.Update; update != nil {
+		wrapped.Update = func(ctx context.Context, req p.UpdateRequest) (p.UpdateResponse, error) {
+			return update(registry.WithURN(ctx, string(req.Urn)), req)
+		}
+	}
+	if del := prov.Delete; del != nil {
+		wrapped.Delete = func(ctx context.Context, req p.DeleteRequest) error {
+			return del(registry.WithURN(ctx, string(req.Urn)), req)
+		}
+	}
+	return wrapped
+}
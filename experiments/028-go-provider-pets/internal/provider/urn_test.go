@@ -0,0 +1,28 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+func TestWithURNs(t *testing.T) {
+	urn := resource.URN("urn:pulumi:dev::lab::pets:index:Dog::rex")
+	var got string
+	prov := withURNs(p.Provider{
+		Create: func(ctx context.Context, req p.CreateRequest) (p.CreateResponse, error) {
+			got = registry.IdempotencyKey(ctx, "dog", "rex")
+			return p.CreateResponse{ID: "dog-rex-1"}, nil
+		},
+	})
+	if _, err := prov.Create(context.Background(), p.CreateRequest{Urn: urn}); err != nil {
+		t.Fatal(err)
+	}
+	if want := registry.IdempotencyKey(registry.WithURN(context.Background(), string(urn)), "dog", "rex"); got != want {
+		t.Errorf("Create keyed on %s, want the URN's key %s", got, want)
+	}
+}
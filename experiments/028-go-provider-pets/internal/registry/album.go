@@ -0,0 +1,48 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// albumDir is where the photos of an album are extracted
+func albumDir(ctx context.Context, albumID string) string {
+	config := configOf(ctx)
+	return filepath.Join(config.dataDir, "albums", albumID)
+}
+
+// WriteAlbum replaces the extracted photos of an album with files, keyed by
+// their path inside the album. In simulation mode nothing is written.
+func WriteAlbum(ctx context.Context, albumID string, files map[string][]byte) error {
+	if configOf(ctx).simulate {
+		return nil
+	}
+	dir := albumDir(ctx, albumID)
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return fmt.Errorf("photo path %q leaves the album", name)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveAlbum deletes the extracted photos of an album.
+func RemoveAlbum(ctx context.Context, albumID string) error {
+	if configOf(ctx).simulate {
+		return nil
+	}
+	return os.RemoveAll(albumDir(ctx, albumID))
+}
@@ -0,0 +1,57 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/gcal"
+)
+
+// GoogleCalendarConfig mirrors scheduled visits into a Google Calendar.
+// credentials is the service account's JSON key; the calendar has to be
+// shared with the service account's email.
+type GoogleCalendarConfig struct {
+	Credentials string  `pulumi:"credentials" provider:"secret" validate:"required"`
+	CalendarID  *string `pulumi:"calendarId,optional"` // defaults to the service account's primary calendar
+	BaseURL     *string `pulumi:"baseUrl,optional"`
+}
+
+// client builds the calendar client for the config
+func (g *GoogleCalendarConfig) client(httpClient *http.Client) (*gcal.Client, error) {
+	creds, err := gcal.ParseCredentials([]byte(g.Credentials))
+	if err != nil {
+		return nil, err
+	}
+	client := &gcal.Client{HTTP: httpClient, Creds: creds, CalendarID: "primary"}
+	if g.CalendarID != nil {
+		client.CalendarID = *g.CalendarID
+	}
+	if g.BaseURL != nil {
+		client.BaseURL = *g.BaseURL
+	}
+	return client, nil
+}
+
+// Calendar returns the Google Calendar client, or nil when the integration
+// isn't configured or the provider is simulating
+func Calendar(ctx context.Context) *gcal.Client {
+	config := configOf(ctx)
+	if config.simulate {
+		return nil
+	}
+	return config.calendar
+}
+
+// configureCalendar validates the googleCalendar config and builds its client
+func (c *Config) configureCalendar(httpClient *http.Client) error {
+	if c.GoogleCalendar == nil {
+		return nil
+	}
+	client, err := c.GoogleCalendar.client(httpClient)
+	if err != nil {
+		return fmt.Errorf("invalid googleCalendar config: %w", err)
+	}
+	c.calendar = client
+	return nil
+}
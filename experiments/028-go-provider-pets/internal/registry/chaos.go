@@ -0,0 +1,86 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// ChaosConfig turns on fault injection for resilience exercises. Whether an
+// operation fails is derived from the seed, the operation and the resource,
+// so the same deployment fails in the same places every time; change the
+// seed to move the failures around.
+type ChaosConfig struct {
+	FailureRate float64  `pulumi:"failureRate" validate:"min=0,max=1"`
+	FailOn      []string `pulumi:"failOn,optional"`
+	ErrorType   *string  `pulumi:"errorType,optional" validate:"oneof=error|partial|timeout"`
+	Seed        *string  `pulumi:"seed,optional"`
+}
+
+// Fault is an injected failure. An "error" fault fails the operation before
+// it changes anything, "timeout" does the same but wraps
+// context.DeadlineExceeded, and "partial" lets the operation finish its
+// writes before reporting failure.
+type Fault struct {
+	Op      string
+	Subject string
+	Type    string
+}
+
+func (f *Fault) Error() string {
+	return fmt.Sprintf("chaos: injected %s failure during %s of %s", f.Type, f.Op, f.Subject)
+}
+
+func (f *Fault) Unwrap() error {
+	if f.Type == "timeout" {
+		return context.DeadlineExceeded
+	}
+	return nil
+}
+
+// Partial reports whether the operation should complete before failing.
+func (f *Fault) Partial() bool {
+	return f.Type == "partial"
+}
+
+// Chaos returns the fault to inject into op ("create", "update" or "delete")
+// on subject, or nil when the operation should go ahead normally.
+func Chaos(ctx context.Context, op, subject string) *Fault {
+	return configOf(ctx).Chaos.pick(op, subject)
+}
+
+func (c *ChaosConfig) pick(op, subject string) *Fault {
+	if c == nil || c.FailureRate <= 0 {
+		return nil
+	}
+	if len(c.FailOn) > 0 && !contains(c.FailOn, op) {
+		return nil
+	}
+
+	seed := ""
+	if c.Seed != nil {
+		seed = *c.Seed
+	}
+	sum := sha256.Sum256([]byte(seed + "\x00" + op + "\x00" + subject))
+	roll := float64(binary.BigEndian.Uint64(sum[:8])) / math.MaxUint64
+	if roll >= c.FailureRate {
+		return nil
+	}
+
+	errorType := "error"
+	if c.ErrorType != nil {
+		errorType = *c.ErrorType
+	}
+	return &Fault{Op: op, Subject: subject, Type: errorType}
+}
+
+func contains(values []string, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestChaosPick(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name     string
+		config   *ChaosConfig
+		op       string
+		wantType string // "" for no fault
+	}{
+		{name: "disabled", config: nil, op: "create"},
+		{name: "zero rate", config: &ChaosConfig{FailureRate: 0}, op: "create"},
+		{name: "always fails", config: &ChaosConfig{FailureRate: 1}, op: "create", wantType: "error"},
+		{
+			name:   "op not selected",
+			config: &ChaosConfig{FailureRate: 1, FailOn: []string{"delete"}},
+			op:     "update",
+		},
+		{
+			name:     "op selected",
+			config:   &ChaosConfig{FailureRate: 1, FailOn: []string{"delete"}},
+			op:       "delete",
+			wantType: "error",
+		},
+		{
+			name:     "error type",
+			config:   &ChaosConfig{FailureRate: 1, ErrorType: str("partial")},
+			op:       "create",
+			wantType: "partial",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fault := tt.config.pick(tt.op, "rex")
+			switch {
+			case tt.wantType == "" && fault != nil:
+				t.Errorf("got %v, want no fault", fault)
+			case tt.wantType != "" && (fault == nil || fault.Type != tt.wantType):
+				t.Errorf("got %v, want a %s fault", fault, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestChaosIsDeterministic(t *testing.T) {
+	config := &ChaosConfig{FailureRate: 0.3}
+	failed := 0
+	for i := 0; i < 1000; i++ {
+		subject := fmt.Sprintf("dog-%d", i)
+		first := config.pick("create", subject)
+		if again := config.pick("create", subject); (first == nil) != (again == nil) {
+			t.Fatalf("%s: outcome changed between calls", subject)
+		}
+		if first != nil {
+			failed++
+		}
+	}
+	if failed < 200 || failed > 400 {
+		t.Errorf("%d of 1000 operations failed, want roughly 300", failed)
+	}
+}
+
+func TestTimeoutFaultWrapsDeadline(t *testing.T) {
+	fault := &Fault{Op: "create", Subject: "rex", Type: "timeout"}
+	if !errors.Is(fault, context.DeadlineExceeded) {
+		t.Error("timeout fault does not wrap context.DeadlineExceeded")
+	}
+	if errors.Is(&Fault{Type: "error"}, context.DeadlineExceeded) {
+		t.Error("error fault wraps context.DeadlineExceeded")
+	}
+}
@@ -0,0 +1,77 @@
+package registry
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"strconv"
+	"time"
+)
+
+// defaultFrozenTime is where the clock stands in deterministic mode unless
+// frozenTime says otherwise.
+const defaultFrozenTime = "2024-01-01T00:00:00Z"
+
+// Now is the provider clock. In deterministic mode it always reads the
+// configured instant, so timestamps in state match across machines. It
+// reads UTC either way, since stamps are formatted with a literal Z.
+func Now(ctx context.Context) time.Time {
+	config := configOf(ctx)
+	return config.now()
+}
+
+func (c *Config) now() time.Time {
+	if c.deterministic {
+		return c.frozenTime
+	}
+	return time.Now().UTC()
+}
+
+// IDSuffix is the part of a generated ID that keeps it unique, normally the
+// creation time in the unit the caller picked. A frozen clock can't tell
+// resources apart, so deterministic mode uses a hash of name instead.
+func IDSuffix(ctx context.Context, name string, stamp int64) string {
+	if !configOf(ctx).deterministic {
+		return strconv.FormatInt(stamp, 10)
+	}
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:4])
+}
+
+// Random is the source for generated secrets such as approval tokens:
+// crypto/rand normally, and in deterministic mode a stream derived from the
+// seed and subject, so parallel operations can't change each other's values.
+func Random(ctx context.Context, subject string) io.Reader {
+	config := configOf(ctx)
+	if !config.deterministic {
+		return rand.Reader
+	}
+	return &seededReader{seed: strconv.FormatInt(config.seed, 10) + "\x00" + subject}
+}
+
+// seededReader expands a seed into bytes with SHA-256 in counter mode.
+type seededReader struct {
+	seed    string
+	counter uint64
+	buf     []byte
+}
+
+func (r *seededReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			var block [8]byte
+			binary.BigEndian.PutUint64(block[:], r.counter)
+			r.counter++
+			sum := sha256.Sum256(append([]byte(r.seed), block[:]...))
+			r.buf = sum[:]
+		}
+		copied := copy(p[n:], r.buf)
+		r.buf = r.buf[copied:]
+		n += copied
+	}
+	return n, nil
+}
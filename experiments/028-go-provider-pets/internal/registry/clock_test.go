@@ -0,0 +1,54 @@
+package registry
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestSeededReader(t *testing.T) {
+	read := func(seed string, n int) []byte {
+		out := make([]byte, n)
+		if _, err := io.ReadFull(&seededReader{seed: seed}, out); err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name      string
+		a, b      []byte
+		wantEqual bool
+	}{
+		{name: "same seed repeats", a: read("1\x00dog/rex", 16), b: read("1\x00dog/rex", 16), wantEqual: true},
+		{name: "other subject differs", a: read("1\x00dog/rex", 16), b: read("1\x00dog/fido", 16)},
+		{name: "other seed differs", a: read("1\x00dog/rex", 16), b: read("2\x00dog/rex", 16)},
+		{name: "spans blocks consistently", a: read("1\x00dog/rex", 80)[:16], b: read("1\x00dog/rex", 16), wantEqual: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bytes.Equal(tt.a, tt.b); got != tt.wantEqual {
+				t.Errorf("equal = %v, want %v (%x vs %x)", got, tt.wantEqual, tt.a, tt.b)
+			}
+		})
+	}
+}
+
+// Stamps are formatted with a literal Z and read back as UTC, so the clock
+// must not follow the machine's zone
+func TestNowIsUTC(t *testing.T) {
+	defer func(local *time.Location) { time.Local = local }(time.Local)
+	time.Local = time.FixedZone("CEST", 2*60*60)
+
+	before := time.Now()
+	now := (&Config{}).now()
+	stamp, err := time.Parse("2006-01-02T15:04:05Z", now.Format("2006-01-02T15:04:05Z"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if now.Location() != time.UTC || stamp.Before(before.Truncate(time.Second)) || stamp.After(time.Now()) {
+		t.Errorf("now = %v, stamped %s; want the current time in UTC", now, stamp)
+	}
+}
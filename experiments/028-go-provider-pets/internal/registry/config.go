@@ -0,0 +1,332 @@
+// Package registry connects the provider configuration to the backend store
+// and gives resources and functions access to it.
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/gcal"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/httpclient"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/validate"
+)
+
+// Config is the pets provider configuration.
+type Config struct {
+	DataDir                *string               `pulumi:"dataDir,optional"`
+	EncryptionKey          *string               `pulumi:"encryptionKey,optional" provider:"secret"`
+	PreviousEncryptionKeys []string              `pulumi:"previousEncryptionKeys,optional" provider:"secret"`
+	Simulate               *bool                 `pulumi:"simulate,optional"`
+	Chaos                  *ChaosConfig          `pulumi:"chaos,optional"`
+	LatencyMs              *int                  `pulumi:"latencyMs,optional" validate:"min=0"`
+	OperationLatencyMs     map[string]int        `pulumi:"operationLatencyMs,optional"`
+	Deterministic          *bool                 `pulumi:"deterministic,optional"`
+	FrozenTime             *string               `pulumi:"frozenTime,optional"`
+	RandomSeed             *int64                `pulumi:"randomSeed,optional"`
+	HTTP                   *HTTPConfig           `pulumi:"http,optional"`
+	DogAPI                 *DogAPIConfig         `pulumi:"dogApi,optional"`
+	Twilio                 *TwilioConfig         `pulumi:"twilio,optional"`
+	GoogleCalendar         *GoogleCalendarConfig `pulumi:"googleCalendar,optional"`
+	DeviceAPI              *DeviceAPIConfig      `pulumi:"deviceApi,optional"`
+	TrackingAPI            *DeviceAPIConfig      `pulumi:"trackingApi,optional"`
+	CameraAPI              *DeviceAPIConfig      `pulumi:"cameraApi,optional"`
+	Insurance              *InsuranceConfig      `pulumi:"insurance,optional"`
+	Currency               *CurrencyConfig       `pulumi:"currency,optional"`
+	Mood                   *MoodConfig           `pulumi:"mood,optional"`
+	WalkEnjoyment          *EnjoymentConfig      `pulumi:"walkEnjoyment,optional"`
+	ShelterCapacity        map[string]int        `pulumi:"shelterCapacity,optional"`   // kennels per shelter ID, for shelters without a PetShelter
+	VolunteerMinimums      map[string]int        `pulumi:"volunteerMinimums,optional"` // volunteers needed per role whenever a shelter is staffed
+	UniqueDogNames         *bool                 `pulumi:"uniqueDogNames,optional"`    // one dog of a name per owner; on unless set to false
+	Dashboard              *DashboardConfig      `pulumi:"dashboard,optional"`
+	// DebugRpc is acted on by the rpclog wrapper around the provider, which
+	// sees it before Configure runs; it is declared here for the schema
+	DebugRpc *bool `pulumi:"debugRpc,optional"`
+
+	store         backend.Store
+	httpClient    *http.Client
+	calendar      *gcal.Client
+	dataDir       string
+	simulate      bool
+	deterministic bool
+	frozenTime    time.Time
+	seed          int64
+}
+
+// configOf is the provider configuration, or an empty one when ctx carries
+// none, as in unit tests; an empty configuration behaves as the defaults do.
+// infer has no way to ask whether a context is configured, only GetConfig,
+// which panics when it isn't. Any other panic is a real failure and goes on.
+func configOf(ctx context.Context) (config Config) {
+	if c, ok := ctx.Value(configKey{}).(*Config); ok {
+		return *c
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			if msg, ok := r.(string); !ok || !strings.HasSuffix(msg, noConfig) {
+				panic(r)
+			}
+			config = Config{}
+		}
+	}()
+	return infer.GetConfig[Config](ctx)
+}
+
+// noConfig ends the message infer panics with when a context has no config
+const noConfig = "called on a provider without a config"
+
+type configKey struct{}
+
+// WithConfig is ctx carrying config, once Configure has opened it, for
+// running resources and functions outside the engine, as tests do.
+func WithConfig(ctx context.Context, config *Config) context.Context {
+	return context.WithValue(ctx, configKey{}, config)
+}
+
+// Configure opens the registry backend. Settings missing from config are
+// taken from the PETS_* environment variables listed with applyEnv. Records
+// are encrypted at rest when an encryption key is set. The provider reports
+// ready on the health service only once the backend answers.
+//
+// With simulate set the registry is read as usual but
+// every write stays in memory, so a classroom can run the same stack again
+// and again against a shared data directory without changing it. With
+// dashboard set the provider also serves a read-only web view of the
+// registry while it runs.
+func (c *Config) Configure(ctx context.Context) error {
+	healthServer.SetServingStatus(healthService, healthpb.HealthCheckResponse_NOT_SERVING)
+
+	if err := c.applyEnv(); err != nil {
+		return fmt.Errorf("invalid provider config: %w", err)
+	}
+
+	if c.Chaos != nil {
+		if failures := validate.Struct(c.Chaos); len(failures) > 0 {
+			return fmt.Errorf("invalid chaos config: %s", failures[0].Reason)
+		}
+		for _, op := range c.Chaos.FailOn {
+			if op != "create" && op != "update" && op != "delete" {
+				return fmt.Errorf("invalid chaos config: failOn entries must be create, update or delete, got %q", op)
+			}
+		}
+		p.GetLogger(ctx).Warningf("chaos mode: failing %.0f%% of operations", c.Chaos.FailureRate*100)
+	}
+
+	if failures := validate.Struct(c); len(failures) > 0 {
+		return fmt.Errorf("invalid provider config: %s", failures[0].Reason)
+	}
+	if c.HTTP != nil {
+		if failures := validate.Struct(c.HTTP); len(failures) > 0 {
+			return fmt.Errorf("invalid http config: %s", failures[0].Reason)
+		}
+		if c.HTTP.OAuth2 != nil {
+			if failures := validate.Struct(c.HTTP.OAuth2); len(failures) > 0 {
+				return fmt.Errorf("invalid http.oauth2 config: %s", failures[0].Reason)
+			}
+		}
+	}
+	if c.DogAPI != nil {
+		if failures := validate.Struct(c.DogAPI); len(failures) > 0 {
+			return fmt.Errorf("invalid dogApi config: %s", failures[0].Reason)
+		}
+	}
+	if c.Dashboard != nil {
+		if failures := validate.Struct(c.Dashboard); len(failures) > 0 {
+			return fmt.Errorf("invalid dashboard config: %s", failures[0].Reason)
+		}
+	}
+	if c.Twilio != nil {
+		if failures := validate.Struct(c.Twilio); len(failures) > 0 {
+			return fmt.Errorf("invalid twilio config: %s", failures[0].Reason)
+		}
+	}
+	if c.GoogleCalendar != nil {
+		if failures := validate.Struct(c.GoogleCalendar); len(failures) > 0 {
+			return fmt.Errorf("invalid googleCalendar config: %s", failures[0].Reason)
+		}
+	}
+	if c.DeviceAPI != nil {
+		if failures := validate.Struct(c.DeviceAPI); len(failures) > 0 {
+			return fmt.Errorf("invalid deviceApi config: %s", failures[0].Reason)
+		}
+	}
+	if c.TrackingAPI != nil {
+		if failures := validate.Struct(c.TrackingAPI); len(failures) > 0 {
+			return fmt.Errorf("invalid trackingApi config: %s", failures[0].Reason)
+		}
+	}
+	if c.CameraAPI != nil {
+		if failures := validate.Struct(c.CameraAPI); len(failures) > 0 {
+			return fmt.Errorf("invalid cameraApi config: %s", failures[0].Reason)
+		}
+	}
+	if c.Insurance != nil {
+		if failures := validate.Struct(c.Insurance); len(failures) > 0 {
+			return fmt.Errorf("invalid insurance config: %s", failures[0].Reason)
+		}
+	}
+	if c.Currency != nil {
+		if failures := validate.Struct(c.Currency); len(failures) > 0 {
+			return fmt.Errorf("invalid currency config: %s", failures[0].Reason)
+		}
+		if err := c.Currency.check(); err != nil {
+			return fmt.Errorf("invalid currency config: %w", err)
+		}
+	}
+	if c.Mood != nil {
+		if failures := validate.Struct(c.Mood); len(failures) > 0 {
+			return fmt.Errorf("invalid mood config: %s", failures[0].Reason)
+		}
+	}
+	if c.WalkEnjoyment != nil {
+		if failures := validate.Struct(c.WalkEnjoyment); len(failures) > 0 {
+			return fmt.Errorf("invalid walkEnjoyment config: %s", failures[0].Reason)
+		}
+		if _, err := c.WalkEnjoyment.weights(); err != nil {
+			return fmt.Errorf("invalid walkEnjoyment config: %w", err)
+		}
+	}
+	for shelter, capacity := range c.ShelterCapacity {
+		if capacity <= 0 {
+			return fmt.Errorf("invalid shelterCapacity: %s must have room for at least one dog", shelter)
+		}
+	}
+	for role, minimum := range c.VolunteerMinimums {
+		if minimum < 0 {
+			return fmt.Errorf("invalid volunteerMinimums: %s must not be negative", role)
+		}
+	}
+	httpOptions, err := c.HTTP.options()
+	if err != nil {
+		return fmt.Errorf("invalid http config: %w", err)
+	}
+	httpClient, err := httpclient.New(httpOptions)
+	if err != nil {
+		return fmt.Errorf("invalid http config: %w", err)
+	}
+	if err := c.configureCalendar(httpClient); err != nil {
+		return err
+	}
+	for op, ms := range c.OperationLatencyMs {
+		if op != "create" && op != "read" && op != "update" && op != "delete" {
+			return fmt.Errorf("invalid provider config: operationLatencyMs keys must be create, read, update or delete, got %q", op)
+		}
+		if ms < 0 {
+			return fmt.Errorf("invalid provider config: operationLatencyMs.%s must be at least 0", op)
+		}
+	}
+
+	// Deterministic mode stops the clock and seeds generated secrets, so
+	// golden-file tests and demos produce byte-identical state anywhere.
+	if c.Deterministic != nil && *c.Deterministic {
+		frozen := defaultFrozenTime
+		if c.FrozenTime != nil {
+			frozen = *c.FrozenTime
+		}
+		at, err := time.Parse(time.RFC3339, frozen)
+		if err != nil {
+			return fmt.Errorf("invalid provider config: frozenTime: %w", err)
+		}
+		c.deterministic, c.frozenTime, c.seed = true, at.UTC(), 1
+		if c.RandomSeed != nil {
+			c.seed = *c.RandomSeed
+		}
+		p.GetLogger(ctx).Warningf("deterministic mode: clock frozen at %s and generated secrets are predictable",
+			c.frozenTime.Format(time.RFC3339))
+	}
+
+	dir, err := DataDir(c.DataDir)
+	if err != nil {
+		return err
+	}
+
+	var store backend.Store = backend.NewFileStore(RegistryFile(dir))
+
+	simulate := c.Simulate != nil && *c.Simulate
+	if simulate {
+		store = backend.NewSimulatedStore(store)
+		p.GetLogger(ctx).Warning("simulation mode: registry changes are kept in memory and discarded on exit")
+	}
+
+	if c.EncryptionKey != nil && *c.EncryptionKey != "" {
+		encrypted, err := backend.NewEncryptedStore(store, *c.EncryptionKey, c.PreviousEncryptionKeys...)
+		if err != nil {
+			return err
+		}
+		// Re-wrap anything still sealed with a retired key so it can be dropped
+		// from config after the next successful deployment.
+		if len(c.PreviousEncryptionKeys) > 0 {
+			if err := encrypted.Rotate(); err != nil {
+				return fmt.Errorf("rotating encryption keys: %w", err)
+			}
+		}
+		store = encrypted
+	}
+
+	if _, err := store.List(""); err != nil {
+		return fmt.Errorf("registry backend unreachable: %w", err)
+	}
+
+	c.store = store
+	c.httpClient = httpClient
+	c.dataDir = dir
+	c.simulate = simulate
+	lifecycle.setStore(store)
+	if err := c.serveDashboard(); err != nil {
+		return err
+	}
+	healthServer.SetServingStatus(healthService, healthpb.HealthCheckResponse_SERVING)
+	return nil
+}
+
+// DataDir is where the registry lives: the dataDir setting when there is
+// one, else PETS_DATA_DIR, else ~/.pulumi-pets
+func DataDir(configured *string) (string, error) {
+	dir := os.Getenv("PETS_DATA_DIR")
+	if configured != nil {
+		dir = *configured
+	}
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("resolving default data directory: %w", err)
+		}
+		dir = filepath.Join(home, ".pulumi-pets")
+	}
+	return dir, nil
+}
+
+// RegistryFile is the registry document in dir
+func RegistryFile(dir string) string {
+	return filepath.Join(dir, "registry.json")
+}
+
+// OpenRegistry opens an existing registry outside a deployment, for tools
+// such as petsctl: the one in dir as DataDir resolves it, decrypted with key
+// when that is set.
+func OpenRegistry(dir *string, key string) (backend.Store, error) {
+	path, err := DataDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	file := RegistryFile(path)
+	if _, err := os.Stat(file); err != nil {
+		return nil, fmt.Errorf("no registry at %s: %w", path, err)
+	}
+	var store backend.Store = backend.NewFileStore(file)
+	if key != "" {
+		if store, err = backend.NewEncryptedStore(store, key); err != nil {
+			return nil, err
+		}
+	}
+	return store, nil
+}
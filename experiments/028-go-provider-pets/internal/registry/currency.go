@@ -0,0 +1,78 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/currency"
+)
+
+// CurrencyConfig sets the currency costs are shown in and where exchange
+// rates come from. Without ratesUrl the embedded snapshot of rates is used.
+type CurrencyConfig struct {
+	DisplayCurrency *string `pulumi:"displayCurrency,optional"` // ISO 4217 code; USD when unset
+	RatesURL        *string `pulumi:"ratesUrl,optional"`        // e.g. https://api.frankfurter.app/latest?from=USD
+}
+
+// ratesTTL is how long fetched rates are used before they are fetched again
+const ratesTTL = time.Hour
+
+var rateCache struct {
+	sync.Mutex
+	url     string
+	rates   currency.Rates
+	fetched time.Time
+}
+
+// DisplayCurrency returns the currency costs are shown in
+func DisplayCurrency(ctx context.Context) string {
+	config := configOf(ctx).Currency
+	if config == nil || config.DisplayCurrency == nil {
+		return currency.Base
+	}
+	return strings.ToUpper(*config.DisplayCurrency)
+}
+
+// ExchangeRates returns the rates from ratesUrl, fetched at most once an
+// hour, or the embedded ones when it isn't set or can't be reached
+func ExchangeRates(ctx context.Context) currency.Rates {
+	config := configOf(ctx).Currency
+	if config == nil || config.RatesURL == nil {
+		return currency.Fallback()
+	}
+	url := *config.RatesURL
+
+	rateCache.Lock()
+	defer rateCache.Unlock()
+	if rateCache.url == url && time.Since(rateCache.fetched) < ratesTTL {
+		return rateCache.rates
+	}
+	rates, err := currency.Fetch(ctx, HTTPClient(ctx), url)
+	if err != nil {
+		p.GetLogger(ctx).Warningf("%v; using the embedded rates", err)
+		return currency.Fallback()
+	}
+	rateCache.url, rateCache.rates, rateCache.fetched = url, rates, time.Now()
+	return rates
+}
+
+// check rejects a display currency that can't be converted to
+func (c *CurrencyConfig) check() error {
+	if c.DisplayCurrency == nil {
+		return nil
+	}
+	code := *c.DisplayCurrency
+	if len(code) != 3 {
+		return fmt.Errorf("displayCurrency must be a three-letter ISO 4217 code, got %q", code)
+	}
+	// Fetched rates may know more currencies than the embedded ones
+	if c.RatesURL == nil && !currency.Fallback().Known(code) {
+		return fmt.Errorf("no exchange rate for displayCurrency %q", code)
+	}
+	return nil
+}
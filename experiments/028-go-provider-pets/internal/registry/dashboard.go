@@ -0,0 +1,57 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+)
+
+// DashboardConfig turns on a read-only web view of the registry, served by
+// the provider process for as long as it runs.
+type DashboardConfig struct {
+	Address string `pulumi:"address" validate:"required"` // host:port to listen on, for example 127.0.0.1:8090
+}
+
+// DashboardHandler builds the dashboard for a registry. The views need the
+// resource types, which depend on this package, so the provider package
+// sets it.
+var DashboardHandler func(store backend.Store, mood MoodCurve, now func() time.Time) http.Handler
+
+// dashboard is the server of the last Configure that asked for one
+var dashboard struct {
+	mu     sync.Mutex
+	server *http.Server
+}
+
+// serveDashboard starts the dashboard on the configured address, replacing
+// the one an earlier Configure started
+func (c *Config) serveDashboard() error {
+	dashboard.mu.Lock()
+	defer dashboard.mu.Unlock()
+	if dashboard.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		dashboard.server.Shutdown(ctx)
+		dashboard.server = nil
+	}
+	if c.Dashboard == nil || DashboardHandler == nil {
+		return nil
+	}
+
+	lis, err := net.Listen("tcp", c.Dashboard.Address)
+	if err != nil {
+		return fmt.Errorf("starting dashboard: %w", err)
+	}
+	server := &http.Server{
+		Handler:           DashboardHandler(c.store, c.Mood.curve(), c.now),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go server.Serve(lis)
+	dashboard.server = server
+	return nil
+}
@@ -0,0 +1,43 @@
+package registry
+
+import (
+	"context"
+)
+
+// DeviceAPIConfig points devices such as SmartFeeder, GpsCollar and PetCamera
+// at a real service. Without it, and always in simulate mode, a simulator
+// stands in for the device.
+type DeviceAPIConfig struct {
+	BaseURL string  `pulumi:"baseUrl" validate:"required"`
+	APIKey  *string `pulumi:"apiKey,optional" provider:"secret"`
+}
+
+// DeviceAPI returns the device cloud settings, or nil when devices are
+// simulated
+func DeviceAPI(ctx context.Context) *DeviceAPIConfig {
+	config := configOf(ctx)
+	if config.simulate {
+		return nil
+	}
+	return config.DeviceAPI
+}
+
+// TrackingAPI returns the settings of the service GPS collars report to, or
+// nil when collars are simulated
+func TrackingAPI(ctx context.Context) *DeviceAPIConfig {
+	config := configOf(ctx)
+	if config.simulate {
+		return nil
+	}
+	return config.TrackingAPI
+}
+
+// CameraAPI returns the settings of the service PetCameras are provisioned
+// with, or nil when cameras are simulated
+func CameraAPI(ctx context.Context) *DeviceAPIConfig {
+	config := configOf(ctx)
+	if config.simulate {
+		return nil
+	}
+	return config.CameraAPI
+}
@@ -0,0 +1,28 @@
+package registry
+
+import (
+	"context"
+)
+
+// DefaultDogAPIURL is TheDogAPI's public endpoint
+const DefaultDogAPIURL = "https://api.thedogapi.com"
+
+// DogAPIConfig points breed lookups at TheDogAPI instead of the free
+// image service the embedded breed catalog refers to.
+type DogAPIConfig struct {
+	APIKey  string  `pulumi:"apiKey" provider:"secret" validate:"required"`
+	BaseURL *string `pulumi:"baseUrl,optional"`
+}
+
+// DogAPI returns TheDogAPI settings, or nil when it isn't configured
+func DogAPI(ctx context.Context) *DogAPIConfig {
+	return configOf(ctx).DogAPI
+}
+
+// URL returns the API's base URL
+func (d *DogAPIConfig) URL() string {
+	if d.BaseURL != nil {
+		return *d.BaseURL
+	}
+	return DefaultDogAPIURL
+}
@@ -0,0 +1,42 @@
+package registry
+
+import (
+	"context"
+	"errors"
+)
+
+// EnjoymentConfig weighs the factors of the walk enjoyment model against
+// each other. Weights are relative, so only their proportions matter; a
+// factor weighted 0 is ignored.
+type EnjoymentConfig struct {
+	BreedEnergyWeight *float64 `pulumi:"breedEnergyWeight,optional" validate:"min=0"`
+	WeatherWeight     *float64 `pulumi:"weatherWeight,optional" validate:"min=0"`
+	PaceWeight        *float64 `pulumi:"paceWeight,optional" validate:"min=0"`
+	AgeWeight         *float64 `pulumi:"ageWeight,optional" validate:"min=0"`
+}
+
+// EnjoymentWeights are the resolved weights, summing to 1
+type EnjoymentWeights struct {
+	BreedEnergy, Weather, Pace, Age float64
+}
+
+// Enjoyment returns the configured enjoyment weights
+func Enjoyment(ctx context.Context) EnjoymentWeights {
+	weights, _ := configOf(ctx).WalkEnjoyment.weights()
+	return weights
+}
+
+func (e *EnjoymentConfig) weights() (EnjoymentWeights, error) {
+	w := EnjoymentWeights{BreedEnergy: 0.35, Weather: 0.25, Pace: 0.2, Age: 0.2}
+	if e != nil {
+		setFloat(&w.BreedEnergy, e.BreedEnergyWeight)
+		setFloat(&w.Weather, e.WeatherWeight)
+		setFloat(&w.Pace, e.PaceWeight)
+		setFloat(&w.Age, e.AgeWeight)
+	}
+	total := w.BreedEnergy + w.Weather + w.Pace + w.Age
+	if total <= 0 {
+		return w, errors.New("at least one weight must be above 0")
+	}
+	return EnjoymentWeights{w.BreedEnergy / total, w.Weather / total, w.Pace / total, w.Age / total}, nil
+}
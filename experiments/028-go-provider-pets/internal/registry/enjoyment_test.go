@@ -0,0 +1,36 @@
+package registry
+
+import "testing"
+
+func TestEnjoymentWeights(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name    string
+		config  *EnjoymentConfig
+		want    EnjoymentWeights
+		wantErr bool
+	}{
+		{name: "defaults", config: nil, want: EnjoymentWeights{BreedEnergy: 0.35, Weather: 0.25, Pace: 0.2, Age: 0.2}},
+		{
+			name:   "relative weights are normalized",
+			config: &EnjoymentConfig{BreedEnergyWeight: f(2), WeatherWeight: f(1), PaceWeight: f(1), AgeWeight: f(0)},
+			want:   EnjoymentWeights{BreedEnergy: 0.5, Weather: 0.25, Pace: 0.25},
+		},
+		{
+			name:    "all zero",
+			config:  &EnjoymentConfig{BreedEnergyWeight: f(0), WeatherWeight: f(0), PaceWeight: f(0), AgeWeight: f(0)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.weights()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("weights = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package registry
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Environment variables stand in for provider configuration that isn't
+// set, the way other providers take credentials from the environment.
+// Pulumi config always wins, then the environment, then the defaults; for
+// a block such as twilio, a variable fills only the fields its config
+// leaves empty, and creates the block when there is none.
+//
+//	PETS_DATA_DIR                       dataDir
+//	PETS_ENCRYPTION_KEY                 encryptionKey (secret)
+//	PETS_PREVIOUS_ENCRYPTION_KEYS       previousEncryptionKeys, comma-separated (secret)
+//	PETS_SIMULATE                       simulate
+//	PETS_LATENCY_MS                     latencyMs
+//	PETS_DETERMINISTIC                  deterministic
+//	PETS_FROZEN_TIME                    frozenTime
+//	PETS_RANDOM_SEED                    randomSeed
+//	PETS_UNIQUE_DOG_NAMES               uniqueDogNames
+//	PETS_CA_BUNDLE                      http.caBundle
+//	PETS_DOG_API_KEY                    dogApi.apiKey (secret)
+//	PETS_DOG_API_URL                    dogApi.baseUrl
+//	PETS_TWILIO_ACCOUNT_SID             twilio.accountSid
+//	PETS_TWILIO_AUTH_TOKEN              twilio.authToken (secret)
+//	PETS_TWILIO_FROM_NUMBER             twilio.fromNumber
+//	PETS_GOOGLE_CALENDAR_CREDENTIALS    googleCalendar.credentials (secret)
+//	PETS_GOOGLE_CALENDAR_ID             googleCalendar.calendarId
+//	PETS_DEVICE_API_URL, _KEY           deviceApi.baseUrl, apiKey (secret)
+//	PETS_TRACKING_API_URL, _KEY         trackingApi.baseUrl, apiKey (secret)
+//	PETS_CAMERA_API_URL, _KEY           cameraApi.baseUrl, apiKey (secret)
+//	PETS_DISPLAY_CURRENCY               currency.displayCurrency
+//	PETS_DASHBOARD_ADDRESS              dashboard.address
+//
+// Secrets taken from the environment never pass through the engine, so
+// they stay out of the stack's config and checkpoints altogether; that
+// makes the environment the better place for them on a shared machine.
+// The provider never logs them. Outbound HTTP also follows the usual
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+func (c *Config) applyEnv() error {
+	setString(&c.DataDir, "PETS_DATA_DIR")
+	setString(&c.EncryptionKey, "PETS_ENCRYPTION_KEY")
+	if v, ok := lookupEnv("PETS_PREVIOUS_ENCRYPTION_KEYS"); ok && len(c.PreviousEncryptionKeys) == 0 {
+		for _, key := range strings.Split(v, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				c.PreviousEncryptionKeys = append(c.PreviousEncryptionKeys, key)
+			}
+		}
+	}
+	setString(&c.FrozenTime, "PETS_FROZEN_TIME")
+	if err := setParsed(&c.Simulate, "PETS_SIMULATE", strconv.ParseBool); err != nil {
+		return err
+	}
+	if err := setParsed(&c.Deterministic, "PETS_DETERMINISTIC", strconv.ParseBool); err != nil {
+		return err
+	}
+	if err := setParsed(&c.LatencyMs, "PETS_LATENCY_MS", strconv.Atoi); err != nil {
+		return err
+	}
+	if err := setParsed(&c.RandomSeed, "PETS_RANDOM_SEED", func(v string) (int64, error) { return strconv.ParseInt(v, 10, 64) }); err != nil {
+		return err
+	}
+	if err := setParsed(&c.UniqueDogNames, "PETS_UNIQUE_DOG_NAMES", strconv.ParseBool); err != nil {
+		return err
+	}
+
+	if dogAPI := section(&c.DogAPI, "PETS_DOG_API_KEY", "PETS_DOG_API_URL"); dogAPI != nil {
+		setRequired(&dogAPI.APIKey, "PETS_DOG_API_KEY")
+		setString(&dogAPI.BaseURL, "PETS_DOG_API_URL")
+	}
+	if twilio := section(&c.Twilio, "PETS_TWILIO_ACCOUNT_SID", "PETS_TWILIO_AUTH_TOKEN", "PETS_TWILIO_FROM_NUMBER"); twilio != nil {
+		setRequired(&twilio.AccountSID, "PETS_TWILIO_ACCOUNT_SID")
+		setRequired(&twilio.AuthToken, "PETS_TWILIO_AUTH_TOKEN")
+		setRequired(&twilio.FromNumber, "PETS_TWILIO_FROM_NUMBER")
+	}
+	if calendar := section(&c.GoogleCalendar, "PETS_GOOGLE_CALENDAR_CREDENTIALS", "PETS_GOOGLE_CALENDAR_ID"); calendar != nil {
+		setRequired(&calendar.Credentials, "PETS_GOOGLE_CALENDAR_CREDENTIALS")
+		setString(&calendar.CalendarID, "PETS_GOOGLE_CALENDAR_ID")
+	}
+	for prefix, api := range map[string]**DeviceAPIConfig{"PETS_DEVICE_API": &c.DeviceAPI, "PETS_TRACKING_API": &c.TrackingAPI, "PETS_CAMERA_API": &c.CameraAPI} {
+		if device := section(api, prefix+"_URL", prefix+"_KEY"); device != nil {
+			setRequired(&device.BaseURL, prefix+"_URL")
+			setString(&device.APIKey, prefix+"_KEY")
+		}
+	}
+	if currency := section(&c.Currency, "PETS_DISPLAY_CURRENCY"); currency != nil {
+		setString(&currency.DisplayCurrency, "PETS_DISPLAY_CURRENCY")
+	}
+	if dashboard := section(&c.Dashboard, "PETS_DASHBOARD_ADDRESS"); dashboard != nil {
+		setRequired(&dashboard.Address, "PETS_DASHBOARD_ADDRESS")
+	}
+	return nil
+}
+
+// lookupEnv treats a variable set to nothing as unset
+func lookupEnv(name string) (string, bool) {
+	v, ok := os.LookupEnv(name)
+	return v, ok && v != ""
+}
+
+// section returns the config block, creating it when it is missing and
+// one of its variables is set
+func section[T any](block **T, names ...string) *T {
+	if *block == nil {
+		for _, name := range names {
+			if _, ok := lookupEnv(name); ok {
+				*block = new(T)
+				break
+			}
+		}
+	}
+	return *block
+}
+
+func setString(field **string, name string) {
+	if v, ok := lookupEnv(name); ok && *field == nil {
+		*field = &v
+	}
+}
+
+func setRequired(field *string, name string) {
+	if v, ok := lookupEnv(name); ok && *field == "" {
+		*field = v
+	}
+}
+
+func setParsed[T any](field **T, name string, parse func(string) (T, error)) error {
+	v, ok := lookupEnv(name)
+	if !ok || *field != nil {
+		return nil
+	}
+	parsed, err := parse(v)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q", name, v)
+	}
+	*field = &parsed
+	return nil
+}
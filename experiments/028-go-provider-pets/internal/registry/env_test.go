@@ -0,0 +1,85 @@
+package registry
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyEnv(t *testing.T) {
+	boolPtr := func(v bool) *bool { return &v }
+
+	tests := []struct {
+		name    string
+		config  Config
+		env     map[string]string
+		want    Config
+		wantErr string
+	}{
+		{name: "nothing set"},
+		{
+			name: "fills unset settings",
+			env: map[string]string{
+				"PETS_DATA_DIR":                 "/srv/pets",
+				"PETS_PREVIOUS_ENCRYPTION_KEYS": "old1, old2,",
+				"PETS_SIMULATE":                 "true",
+				"PETS_LATENCY_MS":               "250",
+				"PETS_UNIQUE_DOG_NAMES":         "false",
+			},
+			want: Config{
+				DataDir:                stringPtr("/srv/pets"),
+				PreviousEncryptionKeys: []string{"old1", "old2"},
+				Simulate:               boolPtr(true),
+				LatencyMs:              intPtr(250),
+				UniqueDogNames:         boolPtr(false),
+			},
+		},
+		{
+			name:   "config wins",
+			config: Config{DataDir: stringPtr("/home/lab"), Simulate: boolPtr(false)},
+			env:    map[string]string{"PETS_DATA_DIR": "/srv/pets", "PETS_SIMULATE": "true"},
+			want:   Config{DataDir: stringPtr("/home/lab"), Simulate: boolPtr(false)},
+		},
+		{
+			name: "empty variables count as unset",
+			env:  map[string]string{"PETS_DATA_DIR": "", "PETS_DOG_API_KEY": ""},
+			want: Config{},
+		},
+		{
+			name: "creates a block",
+			env:  map[string]string{"PETS_DOG_API_KEY": "k3y", "PETS_TRACKING_API_URL": "https://track.lab"},
+			want: Config{DogAPI: &DogAPIConfig{APIKey: "k3y"}, TrackingAPI: &DeviceAPIConfig{BaseURL: "https://track.lab"}},
+		},
+		{
+			name:   "completes a block",
+			config: Config{Twilio: &TwilioConfig{AccountSID: "AC1", FromNumber: "+15550100"}},
+			env:    map[string]string{"PETS_TWILIO_AUTH_TOKEN": "t0ken", "PETS_TWILIO_ACCOUNT_SID": "AC2"},
+			want:   Config{Twilio: &TwilioConfig{AccountSID: "AC1", AuthToken: "t0ken", FromNumber: "+15550100"}},
+		},
+		{
+			name:    "unparseable",
+			env:     map[string]string{"PETS_LATENCY_MS": "slow"},
+			wantErr: `invalid PETS_LATENCY_MS "slow"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			got := tt.config
+			err := got.applyEnv()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("config = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package registry
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthService is the readiness service name; the empty service name
+// reports plain liveness, as the health protocol prescribes.
+const healthService = "pets"
+
+var healthServer = health.NewServer()
+
+// StartHealthServer serves the standard gRPC health protocol on
+// PETS_HEALTH_ADDR. RunProvider owns the plugin's own gRPC server, so health
+// is served from a listener of its own.
+func StartHealthServer() {
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(healthService, healthpb.HealthCheckResponse_NOT_SERVING)
+
+	addr := os.Getenv("PETS_HEALTH_ADDR")
+	if addr == "" {
+		return
+	}
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		// stdout belongs to the plugin handshake
+		fmt.Fprintf(os.Stderr, "pets: health service disabled: %v\n", err)
+		return
+	}
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(lis)
+}
@@ -0,0 +1,111 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/httpclient"
+)
+
+// HTTPConfig tunes outbound HTTP for networks that need it. Without a proxy
+// the usual HTTP_PROXY, HTTPS_PROXY and NO_PROXY variables apply, and
+// PETS_CA_BUNDLE stands in for caBundle.
+//
+// A client certificate for mutual TLS is given either as files
+// (clientCertFile, clientKeyFile) or inline as PEM (clientCert, clientKey),
+// which keeps the key in the stack's encrypted secrets.
+//
+// oauth2 authenticates calls to its hosts with client-credentials tokens,
+// refreshed before they expire.
+type HTTPConfig struct {
+	TimeoutSeconds *int          `pulumi:"timeoutSeconds,optional" validate:"gt=0"`
+	Proxy          *string       `pulumi:"proxy,optional"`
+	CABundle       *string       `pulumi:"caBundle,optional"` // path to a PEM file of extra trusted CAs
+	ClientCertFile *string       `pulumi:"clientCertFile,optional"`
+	ClientKeyFile  *string       `pulumi:"clientKeyFile,optional"`
+	ClientCert     *string       `pulumi:"clientCert,optional" provider:"secret"`
+	ClientKey      *string       `pulumi:"clientKey,optional" provider:"secret"`
+	OAuth2         *OAuth2Config `pulumi:"oauth2,optional"`
+}
+
+// OAuth2Config is a client-credentials grant. Tokens are only sent to hosts.
+type OAuth2Config struct {
+	ClientID     string   `pulumi:"clientId" validate:"required"`
+	ClientSecret string   `pulumi:"clientSecret" provider:"secret" validate:"required"`
+	TokenURL     string   `pulumi:"tokenUrl" validate:"required"`
+	Scopes       []string `pulumi:"scopes,optional"`
+	Hosts        []string `pulumi:"hosts"`
+}
+
+// options resolves the config, which may be nil, into client options
+func (h *HTTPConfig) options() (httpclient.Options, error) {
+	opts := httpclient.Options{CABundle: os.Getenv("PETS_CA_BUNDLE")}
+	if h == nil {
+		return opts, nil
+	}
+	if h.TimeoutSeconds != nil {
+		opts.Timeout = time.Duration(*h.TimeoutSeconds) * time.Second
+	}
+	if h.Proxy != nil {
+		opts.Proxy = *h.Proxy
+	}
+	if h.CABundle != nil {
+		opts.CABundle = *h.CABundle
+	}
+
+	var err error
+	if opts.ClientCert, err = pemSetting(h.ClientCert, h.ClientCertFile, "clientCert"); err != nil {
+		return opts, err
+	}
+	if opts.ClientKey, err = pemSetting(h.ClientKey, h.ClientKeyFile, "clientKey"); err != nil {
+		return opts, err
+	}
+	if h.OAuth2 != nil {
+		opts.OAuth2 = &httpclient.OAuth2{
+			ClientID:     h.OAuth2.ClientID,
+			ClientSecret: h.OAuth2.ClientSecret,
+			TokenURL:     h.OAuth2.TokenURL,
+			Scopes:       h.OAuth2.Scopes,
+			Hosts:        h.OAuth2.Hosts,
+		}
+	}
+	return opts, nil
+}
+
+// pemSetting reads a PEM value given either inline or as a file path
+func pemSetting(inline, file *string, name string) (string, error) {
+	switch {
+	case inline != nil && file != nil:
+		return "", fmt.Errorf("set %s or %sFile, not both", name, name)
+	case inline != nil:
+		return *inline, nil
+	case file != nil:
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			return "", fmt.Errorf("reading %sFile: %w", name, err)
+		}
+		return string(data), nil
+	}
+	return "", nil
+}
+
+// unconfigured serves callers that run before Configure, such as tests
+var unconfigured, _ = httpclient.New(httpclient.Options{})
+
+// HTTPClient returns the client every outbound call goes through, so
+// integrations share its connection pool and per-host limits.
+func HTTPClient(ctx context.Context) *http.Client {
+	if client := configOf(ctx).httpClient; client != nil {
+		return client
+	}
+	return unconfigured
+}
+
+// Simulating reports whether the registry keeps its writes in memory, in
+// which case integrations shouldn't reach out to the world either
+func Simulating(ctx context.Context) bool {
+	return configOf(ctx).simulate
+}
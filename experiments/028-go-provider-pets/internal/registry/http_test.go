@@ -0,0 +1,121 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/httpclient"
+)
+
+func intPtr(v int) *int          { return &v }
+func stringPtr(v string) *string { return &v }
+
+func TestHTTPConfigOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *HTTPConfig
+		env    string
+		want   httpclient.Options
+	}{
+		{name: "unset"},
+		{name: "environment bundle", env: "/etc/lab/ca.pem", want: httpclient.Options{CABundle: "/etc/lab/ca.pem"}},
+		{
+			name: "config wins",
+			config: &HTTPConfig{
+				TimeoutSeconds: intPtr(5),
+				Proxy:          stringPtr("http://proxy.lab:3128"),
+				CABundle:       stringPtr("/srv/ca.pem"),
+			},
+			env:  "/etc/lab/ca.pem",
+			want: httpclient.Options{Timeout: 5 * time.Second, Proxy: "http://proxy.lab:3128", CABundle: "/srv/ca.pem"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PETS_CA_BUNDLE", tt.env)
+			got, err := tt.config.options()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("options = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientCertificateSettings(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "client.pem")
+	if err := os.WriteFile(file, []byte("from file"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		config   HTTPConfig
+		wantCert string
+		wantKey  string
+		wantErr  string
+	}{
+		{name: "none"},
+		{
+			name:     "inline",
+			config:   HTTPConfig{ClientCert: stringPtr("cert"), ClientKey: stringPtr("key")},
+			wantCert: "cert",
+			wantKey:  "key",
+		},
+		{
+			name:     "files",
+			config:   HTTPConfig{ClientCertFile: &file, ClientKeyFile: &file},
+			wantCert: "from file",
+			wantKey:  "from file",
+		},
+		{
+			name:    "both forms",
+			config:  HTTPConfig{ClientCert: stringPtr("cert"), ClientCertFile: &file},
+			wantErr: "set clientCert or clientCertFile, not both",
+		},
+		{
+			name:    "missing file",
+			config:  HTTPConfig{ClientKeyFile: stringPtr(filepath.Join(t.TempDir(), "missing.pem"))},
+			wantErr: "reading clientKeyFile",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.options()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.ClientCert != tt.wantCert || got.ClientKey != tt.wantKey {
+				t.Errorf("client cert/key = %q/%q, want %q/%q", got.ClientCert, got.ClientKey, tt.wantCert, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestOAuth2Options(t *testing.T) {
+	config := HTTPConfig{OAuth2: &OAuth2Config{
+		ClientID:     "lab",
+		ClientSecret: "s3cret",
+		TokenURL:     "https://auth.lab/token",
+		Scopes:       []string{"registry.read"},
+		Hosts:        []string{"registry.lab"},
+	}}
+	got, err := config.options()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.OAuth2 == nil || got.OAuth2.ClientSecret != "s3cret" || got.OAuth2.Hosts[0] != "registry.lab" {
+		t.Errorf("oauth2 options = %+v, want the configured grant", got.OAuth2)
+	}
+}
@@ -0,0 +1,23 @@
+package registry
+
+import (
+	"context"
+)
+
+// InsuranceConfig tunes PetInsurance pricing
+type InsuranceConfig struct {
+	// MultiPetDiscountPercent comes off every policy in a household that
+	// insures more than one dog; 10 when unset
+	MultiPetDiscountPercent *float64 `pulumi:"multiPetDiscountPercent,optional" validate:"min=0,max=50"`
+}
+
+const defaultMultiPetDiscountPercent = 10
+
+// MultiPetDiscount returns the configured multi-pet discount in percent
+func MultiPetDiscount(ctx context.Context) float64 {
+	insurance := configOf(ctx).Insurance
+	if insurance == nil || insurance.MultiPetDiscountPercent == nil {
+		return defaultMultiPetDiscountPercent
+	}
+	return *insurance.MultiPetDiscountPercent
+}
@@ -0,0 +1,34 @@
+package registry
+
+import (
+	"context"
+	"time"
+)
+
+// Delay sleeps for the artificial latency configured for op ("create",
+// "read", "update" or "delete"), so instructors can stand in for a slow API.
+// An operationLatencyMs entry overrides latencyMs for its operation. The
+// wait ends early, with the context's error, when the engine cancels.
+func Delay(ctx context.Context, op string) error {
+	config := configOf(ctx)
+
+	ms := 0
+	if config.LatencyMs != nil {
+		ms = *config.LatencyMs
+	}
+	if perOp, ok := config.OperationLatencyMs[op]; ok {
+		ms = perOp
+	}
+	if ms <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(time.Duration(ms) * time.Millisecond)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,85 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+)
+
+// shutdownGrace bounds how long shutdown waits for in-flight operations.
+const shutdownGrace = 30 * time.Second
+
+// providerLifecycle tracks mutating operations so a shutdown can drain them
+// before the registry is closed.
+type providerLifecycle struct {
+	mu       sync.Mutex
+	inflight sync.WaitGroup
+	draining bool
+	store    backend.Store
+}
+
+var lifecycle = &providerLifecycle{}
+
+func (l *providerLifecycle) setStore(store backend.Store) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.store = store
+}
+
+// BeginOperation registers a Create/Update/Delete, refusing new work once
+// shutdown has started. Every successful call must be paired with
+// EndOperation.
+func BeginOperation() error {
+	lifecycle.mu.Lock()
+	defer lifecycle.mu.Unlock()
+	if lifecycle.draining {
+		return errors.New("pets provider is shutting down")
+	}
+	lifecycle.inflight.Add(1)
+	return nil
+}
+
+// EndOperation marks an operation started with BeginOperation as finished.
+func EndOperation() {
+	lifecycle.inflight.Done()
+}
+
+// ShutdownOnSignal drains in-flight operations and closes the registry on
+// SIGTERM or SIGINT so the engine tearing the plugin down can't leave a
+// half-written store behind.
+func ShutdownOnSignal() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	sig := <-signals
+
+	healthServer.Shutdown()
+	lifecycle.mu.Lock()
+	lifecycle.draining = true
+	store := lifecycle.store
+	lifecycle.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		lifecycle.inflight.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-time.After(shutdownGrace):
+		fmt.Fprintf(os.Stderr, "pets: operations still running after %s, exiting anyway\n", shutdownGrace)
+	}
+
+	if store != nil {
+		if err := store.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "pets: closing registry: %v\n", err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "pets: shut down on %s\n", sig)
+	os.Exit(0)
+}
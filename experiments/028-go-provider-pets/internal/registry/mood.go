@@ -0,0 +1,72 @@
+package registry
+
+import (
+	"context"
+)
+
+// MoodConfig tunes how a dog's happiness and energy evolve between
+// deployments. Every setting is optional; the defaults describe a dog that
+// wants a walk every day and two meals.
+type MoodConfig struct {
+	HappinessHalfLifeHours      *float64 `pulumi:"happinessHalfLifeHours,optional" validate:"gt=0"`
+	HappinessFloor              *int     `pulumi:"happinessFloor,optional" validate:"min=0,max=100"`
+	FeedingIntervalHours        *float64 `pulumi:"feedingIntervalHours,optional" validate:"gt=0"`
+	HungerPenaltyPerHour        *float64 `pulumi:"hungerPenaltyPerHour,optional" validate:"min=0"`
+	EnergyPerWalkMinute         *float64 `pulumi:"energyPerWalkMinute,optional" validate:"min=0"`
+	EnergyRecoveryHalfLifeHours *float64 `pulumi:"energyRecoveryHalfLifeHours,optional" validate:"gt=0"`
+	ActivityWindowHours         *float64 `pulumi:"activityWindowHours,optional" validate:"gt=0"`
+}
+
+// MoodCurve is a MoodConfig with the defaults filled in.
+//
+// Happiness decays from 100 towards HappinessFloor with time since the
+// last walk, halving the distance every HappinessHalfLifeHours, and loses
+// HungerPenaltyPerHour for each hour a meal is overdue. Energy drops by
+// EnergyPerWalkMinute for each minute walked within the last
+// ActivityWindowHours, and that fatigue halves every
+// EnergyRecoveryHalfLifeHours of rest.
+type MoodCurve struct {
+	HappinessHalfLifeHours      float64
+	HappinessFloor              int
+	FeedingIntervalHours        float64
+	HungerPenaltyPerHour        float64
+	EnergyPerWalkMinute         float64
+	EnergyRecoveryHalfLifeHours float64
+	ActivityWindowHours         float64
+}
+
+// Mood returns the configured mood curve
+func Mood(ctx context.Context) MoodCurve {
+	return configOf(ctx).Mood.curve()
+}
+
+func (m *MoodConfig) curve() MoodCurve {
+	curve := MoodCurve{
+		HappinessHalfLifeHours:      24,
+		HappinessFloor:              20,
+		FeedingIntervalHours:        12,
+		HungerPenaltyPerHour:        5,
+		EnergyPerWalkMinute:         1,
+		EnergyRecoveryHalfLifeHours: 3,
+		ActivityWindowHours:         24,
+	}
+	if m == nil {
+		return curve
+	}
+	setFloat(&curve.HappinessHalfLifeHours, m.HappinessHalfLifeHours)
+	if m.HappinessFloor != nil {
+		curve.HappinessFloor = *m.HappinessFloor
+	}
+	setFloat(&curve.FeedingIntervalHours, m.FeedingIntervalHours)
+	setFloat(&curve.HungerPenaltyPerHour, m.HungerPenaltyPerHour)
+	setFloat(&curve.EnergyPerWalkMinute, m.EnergyPerWalkMinute)
+	setFloat(&curve.EnergyRecoveryHalfLifeHours, m.EnergyRecoveryHalfLifeHours)
+	setFloat(&curve.ActivityWindowHours, m.ActivityWindowHours)
+	return curve
+}
+
+func setFloat(dst *float64, v *float64) {
+	if v != nil {
+		*dst = *v
+	}
+}
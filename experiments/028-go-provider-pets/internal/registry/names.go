@@ -0,0 +1,13 @@
+package registry
+
+import (
+	"context"
+)
+
+// UniqueDogNames reports whether an owner's dogs must each have a name of
+// their own. It is on unless uniqueDogNames is set to false, for labs that
+// register the same dog over and over.
+func UniqueDogNames(ctx context.Context) bool {
+	unique := configOf(ctx).UniqueDogNames
+	return unique == nil || *unique
+}
@@ -0,0 +1,196 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"sync"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+)
+
+// Store returns the registry backend opened by Configure.
+func Store(ctx context.Context) (backend.Store, error) {
+	config := configOf(ctx)
+	if config.store == nil {
+		return nil, errors.New("pets provider is not configured")
+	}
+	return config.store, nil
+}
+
+// Save stores value if the record is still at version and returns the
+// record's new version.
+func Save(ctx context.Context, kind, id string, version int64, value any) (int64, error) {
+	store, err := Store(ctx)
+	if err != nil {
+		return 0, err
+	}
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return 0, err
+	}
+	return store.Put(backend.Record{Kind: kind, ID: id, Version: version, Payload: payload})
+}
+
+// Load decodes a record into value and returns its current version.
+func Load(ctx context.Context, kind, id string, value any) (int64, error) {
+	store, err := Store(ctx)
+	if err != nil {
+		return 0, err
+	}
+	rec, err := store.Get(kind, id)
+	if err != nil {
+		return 0, err
+	}
+	return rec.Version, json.Unmarshal(rec.Payload, value)
+}
+
+// Delete removes a record if it is still at version.
+func Delete(ctx context.Context, kind, id string, version int64) error {
+	store, err := Store(ctx)
+	if err != nil {
+		return err
+	}
+	return store.Delete(kind, id, version)
+}
+
+// Idempotent creates
+
+type idempotencyEntry struct {
+	Kind   string `json:"kind"`
+	ID     string `json:"id"`
+	Inputs string `json:"inputs,omitempty"` // hash of the inputs the record was created from
+}
+
+// urnKey carries the URN of the resource an RPC is about
+type urnKey struct{}
+
+// WithURN is ctx for an RPC about the resource with the URN. infer only
+// hands resources their logical name, so the provider adds it.
+func WithURN(ctx context.Context, urn string) context.Context {
+	return context.WithValue(ctx, urnKey{}, urn)
+}
+
+// URN is the URN of the resource ctx is about, or empty outside the engine
+func URN(ctx context.Context) string {
+	urn, _ := ctx.Value(urnKey{}).(string)
+	return urn
+}
+
+// inState holds the URNs the engine has in its state, which it tells by
+// checking them against old inputs
+var inState sync.Map
+
+// Checked notes whether the engine checked the resource with the URN
+// against old inputs, which it only has for a resource in its state. A
+// Create for such a resource is a replacement rather than a retry.
+func Checked(urn string, existing bool) {
+	if existing {
+		inState.Store(urn, true)
+	} else {
+		inState.Delete(urn)
+	}
+}
+
+// DryRun reports whether a mutating invoke should leave the registry alone.
+// Invokes aren't told whether the engine is previewing, so they only make
+// changes when the caller passes dryRun false, as a Go program does with
+// !ctx.DryRun(); left out, dryRun is on.
+func DryRun(asked *bool) bool {
+	return asked == nil || *asked
+}
+
+// IdempotencyKey identifies a Create by resource kind and URN, so stacks
+// sharing the registry don't adopt each other's records. Outside the engine,
+// as in replays and tests, there is no URN and the logical name stands in.
+func IdempotencyKey(ctx context.Context, kind, name string) string {
+	if urn := URN(ctx); urn != "" {
+		name = urn
+	}
+	sum := sha256.Sum256([]byte(kind + "\x00" + name))
+	return hex.EncodeToString(sum[:16])
+}
+
+// inputsHash is what an idempotency entry remembers of the inputs
+func inputsHash(input any) string {
+	data, _ := json.Marshal(input)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
+// CreatedBefore looks up a Create that already completed under key from the
+// same inputs. If the record it produced still exists it is decoded into
+// state and its ID and version are returned; otherwise the ID is empty. A
+// replacement never gets the record back, since the engine deletes the
+// resource it replaces.
+func CreatedBefore(ctx context.Context, kind, key string, input, state any) (string, int64, error) {
+	if _, replacing := inState.Load(URN(ctx)); replacing {
+		return "", 0, nil
+	}
+	var entry idempotencyEntry
+	_, err := Load(ctx, "idempotency", key, &entry)
+	if errors.Is(err, backend.ErrNotFound) {
+		return "", 0, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	if entry.Inputs != inputsHash(input) {
+		return "", 0, nil
+	}
+	version, err := Load(ctx, kind, entry.ID, state)
+	if errors.Is(err, backend.ErrNotFound) {
+		return "", 0, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	return entry.ID, version, nil
+}
+
+// RememberCreate records that key produced the record kind/id from input.
+func RememberCreate(ctx context.Context, key, kind, id string, input any) error {
+	_, err := Save(ctx, "idempotency", key, backend.AnyVersion, idempotencyEntry{Kind: kind, ID: id, Inputs: inputsHash(input)})
+	return err
+}
+
+// ForgetCreate drops the idempotency entry of the deleted record kind/id,
+// unless a replacement has taken it over. Without a URN there is no entry
+// to find.
+func ForgetCreate(ctx context.Context, kind, id string) error {
+	if URN(ctx) == "" {
+		return nil
+	}
+	key := IdempotencyKey(ctx, kind, "")
+	var entry idempotencyEntry
+	version, err := Load(ctx, "idempotency", key, &entry)
+	if errors.Is(err, backend.ErrNotFound) {
+		return nil
+	}
+	if err != nil || entry.ID != id {
+		return err
+	}
+	if err := Delete(ctx, "idempotency", key, version); !errors.Is(err, backend.ErrConflict) {
+		return err
+	}
+	// A Create has taken the entry over since
+	return nil
+}
+
+// SnapshotPath is where the registry snapshot with the given ID is kept.
+func SnapshotPath(ctx context.Context, snapshotID string) string {
+	config := configOf(ctx)
+	return filepath.Join(config.dataDir, "snapshots", snapshotID+".tar.gz")
+}
+
+// WriteArchive writes records to an archive at path and returns its checksum.
+// In simulation mode nothing is written, but the checksum is still reported.
+func WriteArchive(ctx context.Context, path, label string, records []backend.Record) (string, error) {
+	if configOf(ctx).simulate {
+		return backend.ArchiveChecksum(records), nil
+	}
+	return backend.WriteArchive(path, label, records)
+}
@@ -0,0 +1,56 @@
+package registry
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	dev := WithURN(ctx, "urn:pulumi:dev::lab::pets:index:Dog::rex")
+	prod := WithURN(ctx, "urn:pulumi:prod::lab::pets:index:Dog::rex")
+
+	if IdempotencyKey(dev, "dog", "rex") == IdempotencyKey(prod, "dog", "rex") {
+		t.Error("two stacks' dogs named rex share an idempotency key")
+	}
+	if IdempotencyKey(dev, "dog", "rex") != IdempotencyKey(dev, "dog", "") {
+		t.Error("with a URN the key depends on the logical name")
+	}
+	if IdempotencyKey(ctx, "dog", "rex") == IdempotencyKey(ctx, "dog", "max") {
+		t.Error("without a URN two names share an idempotency key")
+	}
+	if IdempotencyKey(dev, "dog", "rex") == IdempotencyKey(dev, "walk", "rex") {
+		t.Error("two kinds share an idempotency key")
+	}
+}
+
+func TestCreatedBeforeSkipsReplacements(t *testing.T) {
+	urn := "urn:pulumi:dev::lab::pets:index:Dog::rex"
+	ctx := WithURN(context.Background(), urn)
+	Checked(urn, true)
+	defer Checked(urn, false)
+
+	// A replacement doesn't look the key up at all, so the missing store
+	// doesn't matter
+	var state struct{}
+	if id, _, err := CreatedBefore(ctx, "dog", IdempotencyKey(ctx, "dog", "rex"), nil, &state); id != "" || err != nil {
+		t.Errorf("CreatedBefore = %q, %v, want a fresh create for a replacement", id, err)
+	}
+	Checked(urn, false)
+	if _, _, err := CreatedBefore(ctx, "dog", IdempotencyKey(ctx, "dog", "rex"), nil, &state); err == nil {
+		t.Error("CreatedBefore of a new resource didn't look the key up")
+	}
+}
+
+func TestDryRunUnlessTurnedOff(t *testing.T) {
+	no, yes := false, true
+	if !DryRun(nil) {
+		t.Error("DryRun(nil) = false, want a dry run when the caller says nothing")
+	}
+	if !DryRun(&yes) {
+		t.Error("DryRun(true) = false")
+	}
+	if DryRun(&no) {
+		t.Error("DryRun(false) = true")
+	}
+}
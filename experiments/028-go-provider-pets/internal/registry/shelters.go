@@ -0,0 +1,18 @@
+package registry
+
+import (
+	"context"
+)
+
+// ShelterCapacity returns how many dogs shelterCapacity gives the shelter
+// room for, or 0 when it doesn't say. A PetShelter's own capacity comes
+// first; this covers shelters registered before there were any.
+func ShelterCapacity(ctx context.Context, shelterID string) int {
+	return configOf(ctx).ShelterCapacity[shelterID]
+}
+
+// VolunteerMinimums returns the configured minimum staffing by role; roles
+// it leaves out keep their defaults
+func VolunteerMinimums(ctx context.Context) map[string]int {
+	return configOf(ctx).VolunteerMinimums
+}
@@ -0,0 +1,31 @@
+package registry
+
+import (
+	"context"
+)
+
+// DefaultTwilioURL is Twilio's REST API
+const DefaultTwilioURL = "https://api.twilio.com"
+
+// TwilioConfig holds the credentials SmsReminders send with. With dryRun
+// set, reminders render their message but nothing is sent.
+type TwilioConfig struct {
+	AccountSID string  `pulumi:"accountSid" validate:"required"`
+	AuthToken  string  `pulumi:"authToken" provider:"secret" validate:"required"`
+	FromNumber string  `pulumi:"fromNumber" validate:"required"`
+	BaseURL    *string `pulumi:"baseUrl,optional"`
+	DryRun     *bool   `pulumi:"dryRun,optional"`
+}
+
+// Twilio returns the Twilio settings, or nil when they aren't configured
+func Twilio(ctx context.Context) *TwilioConfig {
+	return configOf(ctx).Twilio
+}
+
+// URL returns the API's base URL
+func (t *TwilioConfig) URL() string {
+	if t.BaseURL != nil {
+		return *t.BaseURL
+	}
+	return DefaultTwilioURL
+}
@@ -0,0 +1,287 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// Adoption Resource - submits an application and waits for it to be decided.
+// A shelter dog that applicants on its shelter's AdoptionWaitlist are
+// waiting for goes to the first of them, whose entry the approved adoption
+// takes off the list. A dog can only be adopted once. Approval transfers
+// the dog to the adopter in its ownership history, closes its listings
+// and draws up a contract numbered per shelter and year. Deleting an
+// adoption reopens the listings but leaves the ownership history as it is.
+type Adoption struct{}
+
+//pets:state id=ApplicationID created=SubmittedAt
+//pets:output ApplicationID string applicationId Identifier of the adoption application
+//pets:output Status string status Decision on the application
+//pets:output SubmittedAt string submittedAt When the application was filed
+//pets:output DecidedAt string decidedAt When the application was decided
+//pets:output Polls int polls How many times the application was polled
+//pets:output ContractNumber string contractNumber Number of the adoption contract, running per shelter and year
+//pets:output GotchaDay string gotchaDay The day the dog went home with the adopter, YYYY-MM-DD
+type AdoptionArgs struct {
+	DogID          string   `pulumi:"dogId" validate:"required"`
+	AdopterName    string   `pulumi:"adopterName" validate:"required"`
+	AdopterContact string   `pulumi:"adopterContact" provider:"secret" validate:"required"`
+	ShelterID      *string  `pulumi:"shelterId,optional"`                                    // The PetShelter the dog is adopted from; must be the one whose intake registered it
+	Fee            *float64 `pulumi:"fee,optional" validate:"min=0"`                         // Adoption fee in dollars
+	ReviewSeconds  *int     `pulumi:"reviewSeconds,optional" default:"5" validate:"min=0"`   // Simulated reviewer delay in seconds
+	TimeoutSeconds *int     `pulumi:"timeoutSeconds,optional" default:"300" validate:"gt=0"` // How long Create waits for a decision, in seconds
+	ApprovalURL    *string  `pulumi:"approvalUrl,optional"`                                  // External reviewer to poll instead of the simulator
+	WaitlistID     *string  `pulumi:"waitlistId,optional"`                                   // The adopter's AdoptionWaitlist entry, when applicants are waiting for a dog like this one
+}
+
+// adoptionApplication is the backend record a reviewer (or the simulator)
+// moves from pending to approved or rejected.
+type adoptionApplication struct {
+	DogID       string `json:"dogId"`
+	AdopterName string `json:"adopterName"`
+	Status      string `json:"status"`
+	DecideAfter string `json:"decideAfter"`
+}
+
+var adoptions = crudResource[AdoptionArgs, AdoptionState, *AdoptionState]{
+	kind:     stored[AdoptionState]("adoption"),
+	prefix:   "adopt",
+	slug:     func(input AdoptionArgs) string { return input.DogID },
+	newState: newAdoptionState,
+	populate: submitAdoption,
+	related:  []string{"adoption-application"},
+}
+
+func (Adoption) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (AdoptionArgs, []p.CheckFailure, error) {
+	args, failures, err := adoptions.check(newInputs)
+	if args.Fee != nil && *args.Fee != round2(*args.Fee) {
+		failures = append(failures, p.CheckFailure{Property: "fee", Reason: "fee must be in whole cents"})
+	}
+	return args, failures, err
+}
+
+func (Adoption) Create(ctx context.Context, name string, input AdoptionArgs, preview bool) (string, AdoptionState, error) {
+	return adoptions.create(ctx, name, input, preview)
+}
+
+func (Adoption) Delete(ctx context.Context, id string, state AdoptionState) error {
+	if state.WaitlistID != nil && state.Status == "approved" {
+		if err := returnWaitlistEntry(ctx, *state.WaitlistID, state.ApplicationID); err != nil {
+			return err
+		}
+	}
+	if state.Status == "approved" {
+		if err := reopenListings(ctx, state.DogID, state.ApplicationID); err != nil {
+			return err
+		}
+	}
+	return adoptions.delete(ctx, id, state)
+}
+
+// submitAdoption files the application and blocks until it is decided
+func submitAdoption(ctx context.Context, state *AdoptionState, input AdoptionArgs) error {
+	dog, err := walkedDog(ctx, input.DogID)
+	if err != nil {
+		return err
+	}
+	earlier, err := listRecords[AdoptionState](ctx, backend.Query{Kind: "adoption"})
+	if err != nil {
+		return err
+	}
+	shelterID, err := adoptableFrom(ctx, dog, input.ShelterID, earlier)
+	if err != nil {
+		return err
+	}
+	if err := claimWaitlistTurn(ctx, dog, input.WaitlistID); err != nil {
+		return err
+	}
+
+	review := time.Duration(*input.ReviewSeconds) * time.Second
+	timeout := time.Duration(*input.TimeoutSeconds) * time.Second
+
+	application := adoptionApplication{
+		DogID:       input.DogID,
+		AdopterName: input.AdopterName,
+		Status:      "pending",
+		DecideAfter: time.Now().Add(review).Format(time.RFC3339Nano),
+	}
+	if _, err := registry.Save(ctx, "adoption-application", state.ApplicationID, 0, application); err != nil {
+		return err
+	}
+
+	status, polls, err := awaitAdoptionDecision(ctx, state.ApplicationID, input.ApprovalURL, timeout)
+	state.Polls = polls
+	if err != nil {
+		return err
+	}
+	if status == "rejected" {
+		return fmt.Errorf("adoption application %s was rejected", state.ApplicationID)
+	}
+	now := registry.Now(ctx)
+	state.Status = status
+	state.DecidedAt = now.Format("2006-01-02T15:04:05Z")
+	state.GotchaDay = now.Format(dateLayout)
+	state.ContractNumber = contractNumber(shelterID, now.Year(), earlier)
+
+	adopted := dog
+	adopted.OwnerName = input.AdopterName
+	if _, err := recordTransfer(ctx, adopted, dog.OwnerName, now); err != nil {
+		return err
+	}
+	closed, err := closeListings(ctx, input.DogID, state.ApplicationID)
+	if err != nil {
+		return err
+	}
+	if closed > 0 {
+		p.GetLogger(ctx).Infof("adoption %s closed %d listing(s) of %s", state.ApplicationID, closed, dog.Name)
+	}
+	if input.WaitlistID != nil {
+		return takeWaitlistEntry(ctx, *input.WaitlistID, input.DogID, state.ApplicationID)
+	}
+	return nil
+}
+
+// adoptableFrom checks that nobody has adopted the dog yet, and that it
+// comes from shelterID when that is set. It returns the dog's shelter, ""
+// for a dog that didn't come from one.
+func adoptableFrom(ctx context.Context, dog DogState, shelterID *string, earlier []AdoptionState) (string, error) {
+	for _, adoption := range earlier {
+		if adoption.DogID == dog.ID && adoption.Status == "approved" {
+			return "", fmt.Errorf("%s was already adopted by %s on %s (%s)", dog.Name, adoption.AdopterName, adoption.DecidedAt, adoption.ApplicationID)
+		}
+	}
+	shelter, err := dogShelter(ctx, dog.ID)
+	if err != nil {
+		return "", err
+	}
+	if shelterID != nil && *shelterID != shelter {
+		if shelter == "" {
+			return "", fmt.Errorf("%s didn't come from a shelter; drop shelterId", dog.Name)
+		}
+		return "", fmt.Errorf("%s arrived at %s, not %s", dog.Name, shelter, *shelterID)
+	}
+	return shelter, nil
+}
+
+// contractNumber is the next adoption contract number of the shelter's for
+// the year, or of private adoptions for a dog without a shelter
+func contractNumber(shelterID string, year int, earlier []AdoptionState) string {
+	prefix := fmt.Sprintf("ADOPT-%d-", year)
+	if shelterID != "" {
+		prefix = fmt.Sprintf("ADOPT-%s-%d-", strings.ToUpper(shelterID), year)
+	}
+	last := 0
+	for _, adoption := range earlier {
+		seq, ok := strings.CutPrefix(adoption.ContractNumber, prefix)
+		if n, err := strconv.Atoi(seq); ok && err == nil && n > last {
+			last = n
+		}
+	}
+	return fmt.Sprintf("%s%04d", prefix, last+1)
+}
+
+// awaitAdoptionDecision polls an application until it leaves the pending
+// state, backing off between polls, and gives up on timeout or cancellation.
+func awaitAdoptionDecision(ctx context.Context, applicationID string, approvalURL *string, timeout time.Duration) (string, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	logger := p.GetLogger(ctx)
+	interval := time.Second
+	for polls := 1; ; polls++ {
+		status, err := pollAdoption(ctx, applicationID, approvalURL)
+		if err != nil {
+			return "", polls, err
+		}
+		if status != "pending" {
+			return status, polls, nil
+		}
+		logger.InfoStatusf("adoption application %s pending review (poll %d)", applicationID, polls)
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return "", polls, fmt.Errorf("adoption application %s still pending after %s", applicationID, timeout)
+			}
+			return "", polls, fmt.Errorf("waiting for adoption application %s: %w", applicationID, ctx.Err())
+		case <-time.After(interval):
+		}
+		if interval < 10*time.Second {
+			interval *= 2
+		}
+	}
+}
+
+func pollAdoption(ctx context.Context, applicationID string, approvalURL *string) (string, error) {
+	if approvalURL != nil {
+		return pollAdoptionURL(ctx, *approvalURL, applicationID)
+	}
+
+	var application adoptionApplication
+	if _, err := registry.Load(ctx, "adoption-application", applicationID, &application); err != nil {
+		return "", err
+	}
+	// A reviewer can decide early by editing the record in the backend;
+	// otherwise the simulated review approves once its delay has passed
+	if application.Status != "pending" {
+		return application.Status, nil
+	}
+	decideAfter, err := time.Parse(time.RFC3339Nano, application.DecideAfter)
+	if err != nil {
+		return "", fmt.Errorf("adoption application %s: %w", applicationID, err)
+	}
+	if time.Now().Before(decideAfter) {
+		return "pending", nil
+	}
+	return "approved", nil
+}
+
+// pollAdoptionURL asks an external reviewer for a decision. The endpoint gets
+// the application ID as a query parameter and answers {"status": "..."}.
+func pollAdoptionURL(ctx context.Context, approvalURL, applicationID string) (string, error) {
+	u, err := url.Parse(approvalURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid approvalUrl: %w", err)
+	}
+	query := u.Query()
+	query.Set("applicationId", applicationID)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := registry.HTTPClient(ctx).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("polling adoption reviewer: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("polling adoption reviewer: unexpected status %s", resp.Status)
+	}
+
+	var decision struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decision); err != nil {
+		return "", fmt.Errorf("polling adoption reviewer: %w", err)
+	}
+	switch decision.Status {
+	case "pending", "approved", "rejected":
+		return decision.Status, nil
+	}
+	return "", fmt.Errorf("polling adoption reviewer: unknown status %q", decision.Status)
+}
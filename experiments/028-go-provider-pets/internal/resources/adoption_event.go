@@ -0,0 +1,173 @@
+package resources
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// AdoptionEvent Component - an adoption day at a shelter. It looks up the
+// shelter's dogs still waiting for a home through listAdoptableDogs, and
+// creates a Listing for each of them as its child, spread over the booths
+// in time slots from startTime, the longest waiting first. Every update
+// looks the dogs up again, listing those that arrived since and dropping
+// those adopted.
+type AdoptionEvent struct{}
+
+type AdoptionEventArgs struct {
+	ShelterID   string  `pulumi:"shelterId"`
+	Date        string  `pulumi:"date"`                 // YYYY-MM-DD
+	Booths      *int    `pulumi:"booths,optional"`      // Dogs shown at the same time, 4 by default
+	StartTime   *string `pulumi:"startTime,optional"`   // HH:MM the first slot starts, 10:00 by default
+	SlotMinutes *int    `pulumi:"slotMinutes,optional"` // How long each dog is shown, 30 by default
+}
+
+type AdoptionEventState struct {
+	pulumi.ResourceState
+
+	Roster     pulumi.StringArrayOutput `pulumi:"roster"`     // One printable line per listing, by slot and booth
+	ListingIDs pulumi.StringArrayOutput `pulumi:"listingIds"` // The child Listings, in roster order
+	DogCount   pulumi.IntOutput         `pulumi:"dogCount"`   // Dogs listed
+	EndTime    pulumi.StringOutput      `pulumi:"endTime"`    // When the last slot ends
+}
+
+// RosterEntry is where and when a dog is shown at an event
+type RosterEntry struct {
+	Booth int
+	Slot  string
+	Dog   AdoptableDog
+}
+
+// listingResource is a child Listing as the component sees it
+type listingResource struct {
+	pulumi.CustomResourceState
+
+	ListingID pulumi.StringOutput `pulumi:"listingId"`
+}
+
+type listAdoptableDogsArgs struct {
+	ShelterID string  `pulumi:"shelterId"`
+	PageToken *string `pulumi:"pageToken,optional"`
+}
+
+type listAdoptableDogsResult struct {
+	Dogs          []AdoptableDog `pulumi:"dogs"`
+	NextPageToken string         `pulumi:"nextPageToken"`
+}
+
+func (AdoptionEvent) Construct(ctx *pulumi.Context, name, typ string, args AdoptionEventArgs, opts pulumi.ResourceOption) (*AdoptionEventState, error) {
+	event := &AdoptionEventState{}
+	if err := ctx.RegisterComponentResource(typ, name, event, opts); err != nil {
+		return nil, err
+	}
+	start, err := args.start()
+	if err != nil {
+		return nil, err
+	}
+
+	// The component runs outside the provider's configured registry, so
+	// the dogs come from the provider through the engine, a page at a time
+	var dogs []AdoptableDog
+	page := listAdoptableDogsArgs{ShelterID: args.ShelterID}
+	for {
+		var adoptable listAdoptableDogsResult
+		if err := ctx.Invoke("pets:index:listAdoptableDogs", page, &adoptable, pulumi.Parent(event)); err != nil {
+			return nil, err
+		}
+		dogs = append(dogs, adoptable.Dogs...)
+		if adoptable.NextPageToken == "" {
+			break
+		}
+		page.PageToken = &adoptable.NextPageToken
+	}
+	roster, end, err := eventRoster(dogs, start, args.booths(), args.slotMinutes())
+	if err != nil {
+		return nil, err
+	}
+
+	var lines, ids pulumi.StringArray
+	for _, entry := range roster {
+		listing := &listingResource{}
+		err := ctx.RegisterResource("pets:index:Listing", name+"-"+entry.Dog.DogID, pulumi.Map{
+			"dogId":     pulumi.String(entry.Dog.DogID),
+			"shelterId": pulumi.String(args.ShelterID),
+			"eventDate": pulumi.String(args.Date),
+			"booth":     pulumi.Int(entry.Booth),
+			"slot":      pulumi.String(entry.Slot),
+		}, listing, pulumi.Parent(event))
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, pulumi.String(rosterLine(entry)))
+		ids = append(ids, listing.ListingID)
+	}
+
+	event.Roster = lines.ToStringArrayOutput()
+	event.ListingIDs = ids.ToStringArrayOutput()
+	event.DogCount = pulumi.Int(len(roster)).ToIntOutput()
+	event.EndTime = pulumi.String(end).ToStringOutput()
+	if err := ctx.RegisterResourceOutputs(event, pulumi.Map{
+		"roster":     event.Roster,
+		"listingIds": event.ListingIDs,
+		"dogCount":   event.DogCount,
+		"endTime":    event.EndTime,
+	}); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func (args AdoptionEventArgs) booths() int {
+	if args.Booths == nil {
+		return 4
+	}
+	return *args.Booths
+}
+
+func (args AdoptionEventArgs) slotMinutes() int {
+	if args.SlotMinutes == nil {
+		return 30
+	}
+	return *args.SlotMinutes
+}
+
+// start is when the event's first slot begins
+func (args AdoptionEventArgs) start() (time.Time, error) {
+	if args.ShelterID == "" {
+		return time.Time{}, fmt.Errorf("shelterId is required")
+	}
+	if args.booths() < 1 || args.slotMinutes() < 1 {
+		return time.Time{}, fmt.Errorf("booths and slotMinutes must be at least 1")
+	}
+	startTime := "10:00"
+	if args.StartTime != nil {
+		startTime = *args.StartTime
+	}
+	start, err := time.Parse(dateLayout+" 15:04", args.Date+" "+startTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("date must be YYYY-MM-DD and startTime HH:MM: %w", err)
+	}
+	return start, nil
+}
+
+// eventRoster fills the booths slot by slot in the order the dogs come, and
+// returns when the last slot ends. The event can't run past midnight.
+func eventRoster(dogs []AdoptableDog, start time.Time, booths, slotMinutes int) ([]RosterEntry, string, error) {
+	slot := time.Duration(slotMinutes) * time.Minute
+	slots := (len(dogs) + booths - 1) / booths
+	end := start.Add(time.Duration(slots) * slot)
+	if end.After(start.Truncate(24 * time.Hour).Add(24 * time.Hour)) {
+		return nil, "", fmt.Errorf("%d dogs in %d booths would run past midnight; add booths or shorten the slots", len(dogs), booths)
+	}
+	roster := make([]RosterEntry, len(dogs))
+	for i, dog := range dogs {
+		at := start.Add(time.Duration(i/booths) * slot)
+		roster[i] = RosterEntry{Booth: i%booths + 1, Slot: at.Format("15:04"), Dog: dog}
+	}
+	return roster, end.Format("15:04"), nil
+}
+
+func rosterLine(entry RosterEntry) string {
+	return fmt.Sprintf("%s  booth %d  %s (%s, %d years)  %s", entry.Slot, entry.Booth, entry.Dog.Name, BreedCatalog[entry.Dog.Breed].Name, entry.Dog.Age, entry.Dog.DogID)
+}
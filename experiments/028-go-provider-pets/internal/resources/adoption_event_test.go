@@ -0,0 +1,94 @@
+package resources
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEventRoster(t *testing.T) {
+	var dogs []AdoptableDog
+	for _, id := range []string{"dog-a", "dog-b", "dog-c", "dog-d", "dog-e"} {
+		dogs = append(dogs, AdoptableDog{DogID: id})
+	}
+	start := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		dogs        []AdoptableDog
+		start       time.Time
+		booths      int
+		slotMinutes int
+		booth       []int
+		slot        []string
+		end         string
+		wantErr     bool
+	}{
+		{name: "fills booths slot by slot", dogs: dogs, start: start, booths: 2, slotMinutes: 30,
+			booth: []int{1, 2, 1, 2, 1}, slot: []string{"10:00", "10:00", "10:30", "10:30", "11:00"}, end: "11:30"},
+		{name: "a booth each", dogs: dogs[:3], start: start, booths: 4, slotMinutes: 45,
+			booth: []int{1, 2, 3}, slot: []string{"10:00", "10:00", "10:00"}, end: "10:45"},
+		{name: "no dogs", start: start, booths: 4, slotMinutes: 30, end: "10:00"},
+		{name: "ends at midnight", dogs: dogs, start: start.Add(12 * time.Hour), booths: 1, slotMinutes: 24, end: "00:00",
+			booth: []int{1, 1, 1, 1, 1}, slot: []string{"22:00", "22:24", "22:48", "23:12", "23:36"}},
+		{name: "past midnight", dogs: dogs, start: start.Add(12 * time.Hour), booths: 1, slotMinutes: 30, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roster, end, err := eventRoster(tt.dogs, tt.start, tt.booths, tt.slotMinutes)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("roster = %+v, want an error", roster)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var booth []int
+			var slot []string
+			for i, entry := range roster {
+				if entry.Dog.DogID != tt.dogs[i].DogID {
+					t.Errorf("entry %d is %s, want the dogs in order", i, entry.Dog.DogID)
+				}
+				booth, slot = append(booth, entry.Booth), append(slot, entry.Slot)
+			}
+			if !reflect.DeepEqual(booth, tt.booth) || !reflect.DeepEqual(slot, tt.slot) || end != tt.end {
+				t.Errorf("booths %v, slots %v, end %s; want %v, %v, %s", booth, slot, end, tt.booth, tt.slot, tt.end)
+			}
+		})
+	}
+}
+
+func TestAdoptionEventStart(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    AdoptionEventArgs
+		want    string
+		wantErr bool
+	}{
+		{name: "defaults", args: AdoptionEventArgs{ShelterID: "happy-tails", Date: "2026-10-17"}, want: "2026-10-17T10:00:00Z"},
+		{name: "start time", args: AdoptionEventArgs{ShelterID: "happy-tails", Date: "2026-10-17", StartTime: stringPtr("13:30")}, want: "2026-10-17T13:30:00Z"},
+		{name: "no shelter", args: AdoptionEventArgs{Date: "2026-10-17"}, wantErr: true},
+		{name: "bad date", args: AdoptionEventArgs{ShelterID: "happy-tails", Date: "17/10/2026"}, wantErr: true},
+		{name: "no booths", args: AdoptionEventArgs{ShelterID: "happy-tails", Date: "2026-10-17", Booths: intPtr(0)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, err := tt.args.start()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && start.Format(time.RFC3339) != tt.want {
+				t.Errorf("start = %s, want %s", start.Format(time.RFC3339), tt.want)
+			}
+		})
+	}
+}
+
+func TestRosterLine(t *testing.T) {
+	entry := RosterEntry{Booth: 3, Slot: "10:30", Dog: AdoptableDog{DogID: "dog-rex-1", Name: "Rex", Breed: Beagle, Age: 4}}
+	if got, want := rosterLine(entry), "10:30  booth 3  Rex (Beagle, 4 years)  dog-rex-1"; got != want {
+		t.Errorf("rosterLine = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,66 @@
+// Code generated by genstate from adoption.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// AdoptionOutputs are computed by the provider; Check rejects them as inputs
+type AdoptionOutputs struct {
+	ApplicationID  string `pulumi:"applicationId"`
+	Status         string `pulumi:"status"`
+	SubmittedAt    string `pulumi:"submittedAt"`
+	DecidedAt      string `pulumi:"decidedAt"`
+	Polls          int    `pulumi:"polls"`
+	ContractNumber string `pulumi:"contractNumber"`
+	GotchaDay      string `pulumi:"gotchaDay"`
+	Version        int64  `pulumi:"version"`
+}
+
+// AdoptionState echoes the inputs next to the computed outputs
+type AdoptionState struct {
+	AdoptionArgs
+	AdoptionOutputs
+}
+
+// newAdoptionState copies the inputs into an otherwise empty state
+func newAdoptionState(input AdoptionArgs) AdoptionState { return AdoptionState{AdoptionArgs: input} }
+
+func (s *AdoptionState) stamp(id, created string)   { s.ApplicationID, s.SubmittedAt = id, created }
+func (s *AdoptionState) identity() (string, string) { return s.ApplicationID, s.SubmittedAt }
+func (s *AdoptionState) setVersion(version int64)   { s.Version = version }
+func (s *AdoptionState) storedVersion() int64       { return s.Version }
+
+func (args *AdoptionArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.ShelterID, "The PetShelter the dog is adopted from; must be the one whose intake registered it")
+	a.Describe(&args.Fee, "Adoption fee in dollars")
+	a.Describe(&args.ReviewSeconds, "Simulated reviewer delay in seconds")
+	a.SetDefault(&args.ReviewSeconds, 5)
+	a.Describe(&args.TimeoutSeconds, "How long Create waits for a decision, in seconds")
+	a.SetDefault(&args.TimeoutSeconds, 300)
+	a.Describe(&args.ApprovalURL, "External reviewer to poll instead of the simulator")
+	a.Describe(&args.WaitlistID, "The adopter's AdoptionWaitlist entry, when applicants are waiting for a dog like this one")
+}
+
+// applyDefaults fills unset optional inputs with their schema defaults
+func (args *AdoptionArgs) applyDefaults() {
+	if args.ReviewSeconds == nil {
+		v := 5
+		args.ReviewSeconds = &v
+	}
+	if args.TimeoutSeconds == nil {
+		v := 300
+		args.TimeoutSeconds = &v
+	}
+}
+
+func (state *AdoptionState) Annotate(a infer.Annotator) {
+	state.AdoptionArgs.Annotate(a)
+	a.Describe(&state.ApplicationID, "Identifier of the adoption application")
+	a.Describe(&state.Status, "Decision on the application")
+	a.Describe(&state.SubmittedAt, "When the application was filed")
+	a.Describe(&state.DecidedAt, "When the application was decided")
+	a.Describe(&state.Polls, "How many times the application was polled")
+	a.Describe(&state.ContractNumber, "Number of the adoption contract, running per shelter and year")
+	a.Describe(&state.GotchaDay, "The day the dog went home with the adopter, YYYY-MM-DD")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
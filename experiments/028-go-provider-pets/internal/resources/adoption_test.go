@@ -0,0 +1,36 @@
+package resources
+
+import "testing"
+
+func TestContractNumber(t *testing.T) {
+	adopted := func(contract string) AdoptionState {
+		var a AdoptionState
+		a.ContractNumber = contract
+		return a
+	}
+	earlier := []AdoptionState{
+		adopted("ADOPT-HAPPY-TAILS-2026-0001"),
+		adopted("ADOPT-HAPPY-TAILS-2026-0007"),
+		adopted("ADOPT-HAPPY-TAILS-2025-0042"),
+		adopted("ADOPT-2026-0003"),
+		adopted(""),
+	}
+	tests := []struct {
+		name    string
+		shelter string
+		year    int
+		want    string
+	}{
+		{name: "after the highest", shelter: "happy-tails", year: 2026, want: "ADOPT-HAPPY-TAILS-2026-0008"},
+		{name: "new year", shelter: "happy-tails", year: 2027, want: "ADOPT-HAPPY-TAILS-2027-0001"},
+		{name: "other shelter", shelter: "paws", year: 2026, want: "ADOPT-PAWS-2026-0001"},
+		{name: "private", year: 2026, want: "ADOPT-2026-0004"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := contractNumber(tt.shelter, tt.year, earlier); got != tt.want {
+				t.Errorf("contractNumber = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
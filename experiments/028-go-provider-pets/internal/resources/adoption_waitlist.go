@@ -0,0 +1,312 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// AdoptionWaitlist Resource - an applicant's place in a shelter's queue for
+// a dog. When a dog of the shelter's comes up for adoption, the earliest
+// waiting entry whose preferences it meets has first claim on it: an
+// Adoption of that dog has to name that entry, and takes it off the list.
+// Read works out the entry's place in the queue and, from the shelter's
+// adoptions over the last 90 days, how long the wait should be.
+type AdoptionWaitlist struct{}
+
+//pets:state id=ID created=JoinedAt
+//pets:output ID string id Generated identifier of the waitlist entry
+//pets:output JoinedAt string joinedAt When the applicant joined the waitlist
+//pets:output Status string status waiting, or adopted once an Adoption takes the entry
+//pets:output Position int position Place in the shelter's queue, 1 for the next in line; 0 once adopted
+//pets:output EstimatedWaitDays *float64 estimatedWaitDays Days until the entry's turn at the shelter's recent adoption rate; unset without recent adoptions
+//pets:output MatchedDogID string matchedDogId The dog the entry was adopted with
+//pets:output AdoptionID string adoptionId The Adoption that took the entry
+type AdoptionWaitlistArgs struct {
+	ShelterID        string              `pulumi:"shelterId" validate:"required"` // The PetShelter's shelterId
+	ApplicantName    string              `pulumi:"applicantName" validate:"required"`
+	ApplicantContact string              `pulumi:"applicantContact" provider:"secret" validate:"required"`
+	Preferences      *AdopterPreferences `pulumi:"preferences,optional"`
+}
+
+// AdopterPreferences narrow the dogs an applicant waits for; any dog
+// matches what is left unset
+type AdopterPreferences struct {
+	Breeds []DogBreed `pulumi:"breeds,optional" json:"breeds,omitempty"`
+	Sizes  []PetSize  `pulumi:"sizes,optional" json:"sizes,omitempty"`
+	MaxAge *int       `pulumi:"maxAge,optional" json:"maxAge,omitempty"`
+}
+
+// waitlistMatch is stored under an entry's ID once an Adoption takes it
+type waitlistMatch struct {
+	DogID      string `json:"dogId"`
+	AdoptionID string `json:"adoptionId"`
+}
+
+// throughputDays is the window a shelter's adoption rate is measured over
+const throughputDays = 90
+
+var waitlist = crudResource[AdoptionWaitlistArgs, AdoptionWaitlistState, *AdoptionWaitlistState]{
+	kind:     stored[AdoptionWaitlistState]("waitlist"),
+	prefix:   "wait",
+	slug:     func(input AdoptionWaitlistArgs) string { return input.ShelterID },
+	newState: newAdoptionWaitlistState,
+	populate: func(ctx context.Context, state *AdoptionWaitlistState, input AdoptionWaitlistArgs) error {
+		if err := requireShelter(ctx, input.ShelterID); err != nil {
+			return err
+		}
+		state.Status = "waiting"
+		return placeInQueue(ctx, state, []AdoptionWaitlistState{*state})
+	},
+	keep: func(state *AdoptionWaitlistState, oldState AdoptionWaitlistState) {
+		state.Status, state.MatchedDogID, state.AdoptionID = oldState.Status, oldState.MatchedDogID, oldState.AdoptionID
+	},
+	carry: func(ctx context.Context, state *AdoptionWaitlistState, oldState AdoptionWaitlistState, now time.Time) error {
+		return placeInQueue(ctx, state, nil)
+	},
+	refresh: func(ctx context.Context, id string, state *AdoptionWaitlistState) error {
+		return placeInQueue(ctx, state, nil)
+	},
+	related: []string{"waitlist-match"},
+}
+
+func (AdoptionWaitlist) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (AdoptionWaitlistArgs, []p.CheckFailure, error) {
+	return waitlist.check(newInputs)
+}
+
+func (AdoptionWaitlist) Diff(ctx context.Context, id string, olds AdoptionWaitlistState, news AdoptionWaitlistArgs) (p.DiffResponse, error) {
+	// Moving to another shelter's list means joining the back of it
+	diff := map[string]p.PropertyDiff{}
+	if olds.ShelterID != news.ShelterID {
+		diff["shelterId"] = p.PropertyDiff{Kind: p.UpdateReplace, InputDiff: true}
+	}
+	if olds.ApplicantName != news.ApplicantName {
+		diff["applicantName"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	if olds.ApplicantContact != news.ApplicantContact {
+		diff["applicantContact"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	if !reflect.DeepEqual(olds.Preferences, news.Preferences) {
+		diff["preferences"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	return p.DiffResponse{HasChanges: len(diff) > 0, DetailedDiff: diff}, nil
+}
+
+func (AdoptionWaitlist) Create(ctx context.Context, name string, input AdoptionWaitlistArgs, preview bool) (string, AdoptionWaitlistState, error) {
+	return waitlist.create(ctx, name, input, preview)
+}
+
+func (AdoptionWaitlist) Read(ctx context.Context, id string, inputs AdoptionWaitlistArgs, state AdoptionWaitlistState) (string, AdoptionWaitlistArgs, AdoptionWaitlistState, error) {
+	return waitlist.read(ctx, id, inputs, state)
+}
+
+func (AdoptionWaitlist) Update(ctx context.Context, id string, oldState AdoptionWaitlistState, input AdoptionWaitlistArgs, preview bool) (AdoptionWaitlistState, error) {
+	return waitlist.update(ctx, id, oldState, input, preview)
+}
+
+func (AdoptionWaitlist) Delete(ctx context.Context, id string, state AdoptionWaitlistState) error {
+	return waitlist.delete(ctx, id, state)
+}
+
+// placeInQueue works out the entry's status, position and expected wait.
+// pending are entries not saved yet that belong in the queue.
+func placeInQueue(ctx context.Context, state *AdoptionWaitlistState, pending []AdoptionWaitlistState) error {
+	var match waitlistMatch
+	_, err := registry.Load(ctx, "waitlist-match", state.ID, &match)
+	switch {
+	case err == nil:
+		state.Status, state.MatchedDogID, state.AdoptionID = "adopted", match.DogID, match.AdoptionID
+		state.Position, state.EstimatedWaitDays = 0, nil
+		return nil
+	case !errors.Is(err, backend.ErrNotFound):
+		return err
+	}
+	state.Status, state.MatchedDogID, state.AdoptionID = "waiting", "", ""
+
+	queue, err := shelterQueue(ctx, state.ShelterID)
+	if err != nil {
+		return err
+	}
+	queue = append(queue, pending...)
+	sortQueue(queue)
+	for i, entry := range queue {
+		if entry.ID == state.ID {
+			state.Position = i + 1
+		}
+	}
+	rate, err := adoptionRate(ctx, state.ShelterID, registry.Now(ctx))
+	if err != nil {
+		return err
+	}
+	state.EstimatedWaitDays = nil
+	if rate > 0 {
+		wait := round2(float64(state.Position) / rate)
+		state.EstimatedWaitDays = &wait
+	}
+	return nil
+}
+
+// shelterQueue loads the shelter's entries still waiting, in no particular
+// order
+func shelterQueue(ctx context.Context, shelterID string) ([]AdoptionWaitlistState, error) {
+	entries, err := listRecords[AdoptionWaitlistState](ctx, backend.Query{
+		Kind:  "waitlist",
+		Where: []backend.Condition{{Field: "ShelterID", Op: "eq", Value: shelterID}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var waiting []AdoptionWaitlistState
+	for _, entry := range entries {
+		_, err := registry.Load(ctx, "waitlist-match", entry.ID, &waitlistMatch{})
+		if errors.Is(err, backend.ErrNotFound) {
+			waiting = append(waiting, entry)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return waiting, nil
+}
+
+// sortQueue orders entries first come, first served
+func sortQueue(queue []AdoptionWaitlistState) {
+	sort.SliceStable(queue, func(i, j int) bool {
+		if queue[i].JoinedAt != queue[j].JoinedAt {
+			return queue[i].JoinedAt < queue[j].JoinedAt
+		}
+		return queue[i].ID < queue[j].ID
+	})
+}
+
+// shelterDogs are the IDs of the dogs the shelter's intakes registered
+func shelterDogs(ctx context.Context, shelterID string) (map[string]bool, error) {
+	intakes, err := listRecords[BulkDogIntakeState](ctx, backend.Query{Kind: "bulk-intake"})
+	if err != nil {
+		return nil, err
+	}
+	dogs := map[string]bool{}
+	for _, intake := range intakes {
+		if intake.Shelter() == shelterID {
+			for _, id := range intake.CreatedIDs {
+				dogs[id] = true
+			}
+		}
+	}
+	return dogs, nil
+}
+
+// adoptionRate is how many of the shelter's dogs were adopted a day over
+// the last throughputDays
+func adoptionRate(ctx context.Context, shelterID string, now time.Time) (float64, error) {
+	dogs, err := shelterDogs(ctx, shelterID)
+	if err != nil || len(dogs) == 0 {
+		return 0, err
+	}
+	adoptions, err := listRecords[AdoptionState](ctx, backend.Query{Kind: "adoption"})
+	if err != nil {
+		return 0, err
+	}
+	since := now.AddDate(0, 0, -throughputDays)
+	adopted := 0
+	for _, adoption := range adoptions {
+		at, err := time.Parse(time.RFC3339, adoption.DecidedAt)
+		if err == nil && adoption.Status == "approved" && dogs[adoption.DogID] && at.After(since) {
+			adopted++
+		}
+	}
+	return float64(adopted) / throughputDays, nil
+}
+
+// wants reports whether the dog meets the applicant's preferences
+func (prefs *AdopterPreferences) wants(dog DogState) bool {
+	if prefs == nil {
+		return true
+	}
+	if len(prefs.Breeds) > 0 && !hasBreed(prefs.Breeds, dog.Breed) {
+		return false
+	}
+	size := determineSizeByBreed(dog.Breed)
+	if dog.Size != nil {
+		size = *dog.Size
+	}
+	if len(prefs.Sizes) > 0 && !hasSize(prefs.Sizes, size) {
+		return false
+	}
+	return prefs.MaxAge == nil || dog.years() <= *prefs.MaxAge
+}
+
+func hasSize(sizes []PetSize, size PetSize) bool {
+	for _, s := range sizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
+
+// waitlistTurn finds the entry with first claim on the dog: the earliest
+// waiting entry of the dog's shelter that wants it. It is empty when the
+// dog didn't come from a shelter or nobody there is waiting for it.
+func waitlistTurn(ctx context.Context, dog DogState) (AdoptionWaitlistState, error) {
+	shelterID, err := dogShelter(ctx, dog.ID)
+	if err != nil || shelterID == "" {
+		return AdoptionWaitlistState{}, err
+	}
+	queue, err := shelterQueue(ctx, shelterID)
+	if err != nil {
+		return AdoptionWaitlistState{}, err
+	}
+	sortQueue(queue)
+	for _, entry := range queue {
+		if entry.Preferences.wants(dog) {
+			return entry, nil
+		}
+	}
+	return AdoptionWaitlistState{}, nil
+}
+
+// claimWaitlistTurn checks that an adoption is by the applicant whose turn
+// it is for the dog, if anyone's
+func claimWaitlistTurn(ctx context.Context, dog DogState, waitlistID *string) error {
+	turn, err := waitlistTurn(ctx, dog)
+	if err != nil {
+		return err
+	}
+	switch {
+	case turn.ID == "" && waitlistID != nil:
+		return fmt.Errorf("nobody on %s's shelter waitlist is waiting for %s; drop waitlistId", dog.Name, dog.Name)
+	case turn.ID != "" && (waitlistID == nil || *waitlistID != turn.ID):
+		return fmt.Errorf("%s goes to %s first, waiting since %s as %s", dog.Name, turn.ApplicantName, turn.JoinedAt, turn.ID)
+	}
+	return nil
+}
+
+// takeWaitlistEntry records that an approved adoption took the entry off
+// the list
+func takeWaitlistEntry(ctx context.Context, waitlistID, dogID, adoptionID string) error {
+	_, err := registry.Save(ctx, "waitlist-match", waitlistID, 0, waitlistMatch{DogID: dogID, AdoptionID: adoptionID})
+	return err
+}
+
+// returnWaitlistEntry puts the entry an adoption took back in its place in
+// the queue
+func returnWaitlistEntry(ctx context.Context, waitlistID, adoptionID string) error {
+	var match waitlistMatch
+	_, err := registry.Load(ctx, "waitlist-match", waitlistID, &match)
+	if errors.Is(err, backend.ErrNotFound) || (err == nil && match.AdoptionID != adoptionID) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return registry.Delete(ctx, "waitlist-match", waitlistID, backend.AnyVersion)
+}
@@ -0,0 +1,49 @@
+// Code generated by genstate from adoption_waitlist.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// AdoptionWaitlistOutputs are computed by the provider; Check rejects them as inputs
+type AdoptionWaitlistOutputs struct {
+	ID                string   `pulumi:"id"`
+	JoinedAt          string   `pulumi:"joinedAt"`
+	Status            string   `pulumi:"status"`
+	Position          int      `pulumi:"position"`
+	EstimatedWaitDays *float64 `pulumi:"estimatedWaitDays"`
+	MatchedDogID      string   `pulumi:"matchedDogId"`
+	AdoptionID        string   `pulumi:"adoptionId"`
+	Version           int64    `pulumi:"version"`
+}
+
+// AdoptionWaitlistState echoes the inputs next to the computed outputs
+type AdoptionWaitlistState struct {
+	AdoptionWaitlistArgs
+	AdoptionWaitlistOutputs
+}
+
+// newAdoptionWaitlistState copies the inputs into an otherwise empty state
+func newAdoptionWaitlistState(input AdoptionWaitlistArgs) AdoptionWaitlistState {
+	return AdoptionWaitlistState{AdoptionWaitlistArgs: input}
+}
+
+func (s *AdoptionWaitlistState) stamp(id, created string)   { s.ID, s.JoinedAt = id, created }
+func (s *AdoptionWaitlistState) identity() (string, string) { return s.ID, s.JoinedAt }
+func (s *AdoptionWaitlistState) setVersion(version int64)   { s.Version = version }
+func (s *AdoptionWaitlistState) storedVersion() int64       { return s.Version }
+
+func (args *AdoptionWaitlistArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.ShelterID, "The PetShelter's shelterId")
+}
+
+func (state *AdoptionWaitlistState) Annotate(a infer.Annotator) {
+	state.AdoptionWaitlistArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the waitlist entry")
+	a.Describe(&state.JoinedAt, "When the applicant joined the waitlist")
+	a.Describe(&state.Status, "waiting, or adopted once an Adoption takes the entry")
+	a.Describe(&state.Position, "Place in the shelter's queue, 1 for the next in line; 0 once adopted")
+	a.Describe(&state.EstimatedWaitDays, "Days until the entry's turn at the shelter's recent adoption rate; unset without recent adoptions")
+	a.Describe(&state.MatchedDogID, "The dog the entry was adopted with")
+	a.Describe(&state.AdoptionID, "The Adoption that took the entry")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
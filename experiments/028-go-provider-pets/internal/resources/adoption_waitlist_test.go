@@ -0,0 +1,56 @@
+package resources
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAdopterPreferencesWants(t *testing.T) {
+	dog := func(breed DogBreed, size *PetSize, age int) DogState {
+		var d DogState
+		d.Breed, d.Size, d.Age = breed, size, intPtr(age)
+		return d
+	}
+	tests := []struct {
+		name  string
+		prefs *AdopterPreferences
+		dog   DogState
+		want  bool
+	}{
+		{name: "no preferences", dog: dog(Husky, nil, 9), want: true},
+		{name: "breed matches", prefs: &AdopterPreferences{Breeds: []DogBreed{Beagle, Poodle}}, dog: dog(Poodle, nil, 3), want: true},
+		{name: "wrong breed", prefs: &AdopterPreferences{Breeds: []DogBreed{Beagle}}, dog: dog(Poodle, nil, 3)},
+		{name: "size from the breed", prefs: &AdopterPreferences{Sizes: []PetSize{Medium}}, dog: dog(Beagle, nil, 3), want: true},
+		{name: "size given", prefs: &AdopterPreferences{Sizes: []PetSize{Small}}, dog: dog(Beagle, sizePtr(Medium), 3)},
+		{name: "young enough", prefs: &AdopterPreferences{MaxAge: intPtr(3)}, dog: dog(Husky, nil, 3), want: true},
+		{name: "too old", prefs: &AdopterPreferences{MaxAge: intPtr(3)}, dog: dog(Husky, nil, 4)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.prefs.wants(tt.dog); got != tt.want {
+				t.Errorf("wants = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortQueue(t *testing.T) {
+	entry := func(id, joined string) AdoptionWaitlistState {
+		var e AdoptionWaitlistState
+		e.ID, e.JoinedAt = id, joined
+		return e
+	}
+	queue := []AdoptionWaitlistState{
+		entry("wait-b", "2026-10-02T09:00:00Z"),
+		entry("wait-c", "2026-09-30T09:00:00Z"),
+		entry("wait-a", "2026-10-02T09:00:00Z"),
+	}
+	sortQueue(queue)
+	var order []string
+	for _, e := range queue {
+		order = append(order, e.ID)
+	}
+	if !reflect.DeepEqual(order, []string{"wait-c", "wait-a", "wait-b"}) {
+		t.Errorf("queue order = %v", order)
+	}
+}
@@ -0,0 +1,112 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// AllergyRecord Resource - an allergen a dog has been found to react to.
+// Diet transitions to foods containing a recorded allergen are refused.
+type AllergyRecord struct{}
+
+//pets:state id=ID created=RecordedAt
+//pets:output ID string id Generated identifier of the record
+//pets:output RecordedAt string recordedAt When the allergy was recorded
+type AllergyRecordArgs struct {
+	DogID       string          `pulumi:"dogId" validate:"required"`
+	Allergen    string          `pulumi:"allergen" validate:"required,max=64"`                     // Ingredient the dog reacts to, as it appears in food names
+	Severity    AllergySeverity `pulumi:"severity" validate:"required,oneof=mild|moderate|severe"` // One of mild, moderate or severe
+	DiagnosedBy *string         `pulumi:"diagnosedBy,optional"`                                    // Vet or owner who identified the allergy
+}
+
+var allergies = crudResource[AllergyRecordArgs, AllergyRecordState, *AllergyRecordState]{
+	kind:     stored[AllergyRecordState]("allergy"),
+	prefix:   "allergy",
+	slug:     func(input AllergyRecordArgs) string { return input.DogID },
+	newState: newAllergyRecordState,
+	populate: func(ctx context.Context, state *AllergyRecordState, input AllergyRecordArgs) error {
+		_, err := walkedDog(ctx, input.DogID)
+		return err
+	},
+}
+
+func (AllergyRecord) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (AllergyRecordArgs, []p.CheckFailure, error) {
+	return allergies.check(newInputs)
+}
+
+func (AllergyRecord) Create(ctx context.Context, name string, input AllergyRecordArgs, preview bool) (string, AllergyRecordState, error) {
+	return allergies.create(ctx, name, input, preview)
+}
+
+func (AllergyRecord) Read(ctx context.Context, id string, inputs AllergyRecordArgs, state AllergyRecordState) (string, AllergyRecordArgs, AllergyRecordState, error) {
+	return allergies.read(ctx, id, inputs, state)
+}
+
+func (AllergyRecord) Delete(ctx context.Context, id string, state AllergyRecordState) error {
+	return allergies.delete(ctx, id, state)
+}
+
+// knownAllergy is an allergen from either the dog's allergies input or an
+// AllergyRecord, with what is known about the reaction
+type knownAllergy struct {
+	Allergen string
+	Source   string
+}
+
+// dogAllergies collects every allergen recorded for a dog
+func dogAllergies(ctx context.Context, dog DogState) ([]knownAllergy, error) {
+	var known []knownAllergy
+	for _, allergen := range dog.Allergies {
+		known = append(known, knownAllergy{Allergen: allergen, Source: "listed on the dog"})
+	}
+	records, err := listForDog[AllergyRecordState](ctx, "allergy", dog.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		source := fmt.Sprintf("%s allergy %s", record.Severity, record.ID)
+		if record.DiagnosedBy != nil && *record.DiagnosedBy != "" {
+			source += ", diagnosed by " + *record.DiagnosedBy
+		}
+		known = append(known, knownAllergy{Allergen: record.Allergen, Source: source})
+	}
+	return known, nil
+}
+
+// allergyConflict reports the first known allergen a food's name mentions,
+// or nil when it mentions none
+func allergyConflict(dog DogState, property, food string, known []knownAllergy) error {
+	lower := strings.ToLower(food)
+	for _, allergy := range known {
+		if a := strings.ToLower(strings.TrimSpace(allergy.Allergen)); a != "" && strings.Contains(lower, a) {
+			return fmt.Errorf("allergy conflict: %s %q contains %s, which %s is allergic to (%s)",
+				property, food, allergy.Allergen, dog.Name, allergy.Source)
+		}
+	}
+	return nil
+}
+
+// checkAllergies fails property when food conflicts with one of the dog's
+// allergies. Check can run before the dog or the registry exists, so
+// anything it can't look up is left for Create to enforce.
+func checkAllergies(ctx context.Context, dogID, property, food string) []p.CheckFailure {
+	if dogID == "" || food == "" {
+		return nil
+	}
+	dog, err := walkedDog(ctx, dogID)
+	if err != nil {
+		return nil
+	}
+	known, err := dogAllergies(ctx, dog)
+	if err != nil {
+		return nil
+	}
+	if err := allergyConflict(dog, property, food, known); err != nil {
+		return []p.CheckFailure{{Property: property, Reason: err.Error()}}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+// Code generated by genstate from allergy.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// AllergyRecordOutputs are computed by the provider; Check rejects them as inputs
+type AllergyRecordOutputs struct {
+	ID         string `pulumi:"id"`
+	RecordedAt string `pulumi:"recordedAt"`
+	Version    int64  `pulumi:"version"`
+}
+
+// AllergyRecordState echoes the inputs next to the computed outputs
+type AllergyRecordState struct {
+	AllergyRecordArgs
+	AllergyRecordOutputs
+}
+
+// newAllergyRecordState copies the inputs into an otherwise empty state
+func newAllergyRecordState(input AllergyRecordArgs) AllergyRecordState {
+	return AllergyRecordState{AllergyRecordArgs: input}
+}
+
+func (s *AllergyRecordState) stamp(id, created string)   { s.ID, s.RecordedAt = id, created }
+func (s *AllergyRecordState) identity() (string, string) { return s.ID, s.RecordedAt }
+func (s *AllergyRecordState) setVersion(version int64)   { s.Version = version }
+func (s *AllergyRecordState) storedVersion() int64       { return s.Version }
+
+func (args *AllergyRecordArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Allergen, "Ingredient the dog reacts to, as it appears in food names")
+	a.Describe(&args.Severity, "One of mild, moderate or severe")
+	a.Describe(&args.DiagnosedBy, "Vet or owner who identified the allergy")
+}
+
+func (state *AllergyRecordState) Annotate(a infer.Annotator) {
+	state.AllergyRecordArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the record")
+	a.Describe(&state.RecordedAt, "When the allergy was recorded")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
@@ -0,0 +1,34 @@
+package resources
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAllergyConflict(t *testing.T) {
+	dog := DogState{DogArgs: DogArgs{Name: "Rex"}}
+	known := []knownAllergy{
+		{Allergen: " ", Source: "listed on the dog"},
+		{Allergen: "Chicken", Source: "listed on the dog"},
+		{Allergen: "lamb", Source: "severe allergy allergy-rex-1, diagnosed by Dr. Smith"},
+	}
+	tests := []struct {
+		food string
+		want string // substring of the error, "" for no conflict
+	}{
+		{food: "Salmon & Sweet Potato"},
+		{food: "chicken and rice formula", want: `toFood "chicken and rice formula" contains Chicken, which Rex is allergic to (listed on the dog)`},
+		{food: "Lamb Kibble", want: "diagnosed by Dr. Smith"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.food, func(t *testing.T) {
+			err := allergyConflict(dog, "toFood", tt.food, known)
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("unexpected conflict: %v", err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("err = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
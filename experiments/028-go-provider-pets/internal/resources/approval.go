@@ -0,0 +1,80 @@
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+
+	p "github.com/pulumi/pulumi-go-provider"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// ApprovalArgs is embedded by resources that support human-in-the-loop
+// provisioning. Flagged resources are created in the pending state and only
+// become active once approved.
+type ApprovalArgs struct {
+	RequiresApproval *bool `pulumi:"requiresApproval,optional"`
+}
+
+type ApprovalState struct {
+	ApprovalStatus string `pulumi:"approvalStatus"` // pending or active
+	ApprovalToken  string `pulumi:"approvalToken" provider:"secret"`
+}
+
+// ApprovalRequest is the registry record behind a pending approval.
+type ApprovalRequest struct {
+	Kind        string `json:"kind"`
+	TokenHash   string `json:"tokenHash"`
+	Status      string `json:"status"`
+	RequestedAt string `json:"requestedAt"`
+	ApprovedAt  string `json:"approvedAt,omitempty"`
+}
+
+// requestApproval files an approval request for a newly created resource if
+// it asked for one. The returned token is what the approver must present.
+func requestApproval(ctx context.Context, kind, id string, args ApprovalArgs) (ApprovalState, error) {
+	if args.RequiresApproval == nil || !*args.RequiresApproval {
+		return ApprovalState{ApprovalStatus: "active"}, nil
+	}
+
+	raw := make([]byte, 16)
+	if _, err := io.ReadFull(registry.Random(ctx, kind+"/"+id), raw); err != nil {
+		return ApprovalState{}, err
+	}
+	token := hex.EncodeToString(raw)
+	hash := sha256.Sum256([]byte(token))
+	request := ApprovalRequest{
+		Kind:        kind,
+		TokenHash:   hex.EncodeToString(hash[:]),
+		Status:      "pending",
+		RequestedAt: registry.Now(ctx).Format("2006-01-02T15:04:05Z"),
+	}
+	if _, err := registry.Save(ctx, "approval", id, backend.AnyVersion, request); err != nil {
+		return ApprovalState{}, err
+	}
+	p.GetLogger(ctx).Warningf("%s %s is pending approval", kind, id)
+	return ApprovalState{ApprovalStatus: "pending", ApprovalToken: token}, nil
+}
+
+// refreshApproval updates state from the approval record during Read.
+func refreshApproval(ctx context.Context, id string, state *ApprovalState) error {
+	if state.ApprovalStatus != "pending" {
+		return nil
+	}
+	var request ApprovalRequest
+	_, err := registry.Load(ctx, "approval", id, &request)
+	if errors.Is(err, backend.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if request.Status == "approved" {
+		state.ApprovalStatus = "active"
+	}
+	return nil
+}
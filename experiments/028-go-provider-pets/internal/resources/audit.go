@@ -0,0 +1,40 @@
+package resources
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// AuditEntry records something the provider noticed or did on its own, as
+// opposed to what a deployment asked of it
+type AuditEntry struct {
+	At      string `json:"at"`
+	Action  string `json:"action"`
+	DogID   string `json:"dogId,omitempty"`
+	Message string `json:"message"`
+}
+
+// auditLog is the registry record holding the audit entries about one
+// resource, stored under its ID
+type auditLog struct {
+	Entries []AuditEntry `json:"entries"`
+}
+
+// recordAudit appends an entry, stamped with the current time, to the
+// audit log of subject
+func recordAudit(ctx context.Context, subject string, entry AuditEntry) error {
+	var log auditLog
+	version, err := registry.Load(ctx, "audit", subject, &log)
+	if errors.Is(err, backend.ErrNotFound) {
+		version = 0
+	} else if err != nil {
+		return err
+	}
+	entry.At = registry.Now(ctx).Format("2006-01-02T15:04:05Z")
+	log.Entries = append(log.Entries, entry)
+	_, err = registry.Save(ctx, "audit", subject, version, log)
+	return err
+}
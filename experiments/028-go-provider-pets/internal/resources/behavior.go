@@ -0,0 +1,54 @@
+package resources
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// NoteAuthor is who made a behavior observation
+type NoteAuthor string
+
+const (
+	Owner   NoteAuthor = "owner"
+	Vet     NoteAuthor = "vet"
+	Trainer NoteAuthor = "trainer"
+)
+
+// NoteSeverity is how much attention a behavior observation needs
+type NoteSeverity string
+
+const (
+	Info    NoteSeverity = "info"
+	Concern NoteSeverity = "concern"
+	Alert   NoteSeverity = "alert"
+)
+
+// BehaviorNote is one observation about a dog's behavior
+type BehaviorNote struct {
+	Timestamp string       `pulumi:"timestamp" json:"timestamp"`
+	Author    NoteAuthor   `pulumi:"author" json:"author"`
+	Severity  NoteSeverity `pulumi:"severity" json:"severity"`
+	Note      string       `pulumi:"note" json:"note"`
+}
+
+// observed is a note made at now
+func observed(now time.Time, author NoteAuthor, severity NoteSeverity, note string) BehaviorNote {
+	return BehaviorNote{
+		Timestamp: now.Format("2006-01-02T15:04:05Z"),
+		Author:    author,
+		Severity:  severity,
+		Note:      note,
+	}
+}
+
+// UnmarshalJSON also reads the plain strings notes were recorded as before
+// they were attributed. Those carry no timestamp and count as the owner's.
+func (n *BehaviorNote) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*n = BehaviorNote{Author: Owner, Severity: Info, Note: text}
+		return nil
+	}
+	type plain BehaviorNote
+	return json.Unmarshal(data, (*plain)(n))
+}
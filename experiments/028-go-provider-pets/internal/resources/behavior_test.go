@@ -0,0 +1,96 @@
+package resources
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestBehaviorNoteUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want BehaviorNote
+	}{
+		{
+			name: "attributed",
+			json: `{"timestamp":"2024-03-05T12:00:00Z","author":"vet","severity":"alert","note":"Limping"}`,
+			want: BehaviorNote{Timestamp: "2024-03-05T12:00:00Z", Author: Vet, Severity: Alert, Note: "Limping"},
+		},
+		{
+			name: "legacy plain string",
+			json: `"Loves the park"`,
+			want: BehaviorNote{Author: Owner, Severity: Info, Note: "Loves the park"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got BehaviorNote
+			if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("note = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalkNote(t *testing.T) {
+	now := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		input        DogWalkArgs
+		enjoyment    string
+		wantNote     string
+		wantSeverity NoteSeverity
+	}{
+		{
+			name:         "generated",
+			input:        DogWalkArgs{Duration: 45},
+			enjoyment:    "high",
+			wantNote:     "Went on a 45-minute walk and showed high enjoyment",
+			wantSeverity: Info,
+		},
+		{
+			name:         "walker's notes",
+			input:        DogWalkArgs{Duration: 10, Notes: stringPtr(" Pulled hard at the squirrels ")},
+			enjoyment:    "low",
+			wantNote:     "Pulled hard at the squirrels",
+			wantSeverity: Concern,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := walkNote(tt.input, tt.enjoyment, now)
+			if got.Note != tt.wantNote || got.Severity != tt.wantSeverity || got.Author != Owner {
+				t.Errorf("note = %+v, want %q from the owner at %s", got, tt.wantNote, tt.wantSeverity)
+			}
+			if got.Timestamp != "2024-03-05T12:00:00Z" {
+				t.Errorf("timestamp = %q", got.Timestamp)
+			}
+		})
+	}
+}
+
+func TestVisitNote(t *testing.T) {
+	now := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		visitType    VisitType
+		symptoms     *string
+		wantNote     string
+		wantSeverity NoteSeverity
+	}{
+		{"checkup", nil, "Visit with Dr. Lee (checkup)", Info},
+		{"surgery", nil, "Visit with Dr. Lee (surgery)", Concern},
+		{"emergency", stringPtr("ate chocolate"), "Visit with Dr. Lee (emergency): ate chocolate", Alert},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.visitType), func(t *testing.T) {
+			got := visitNote(VeterinaryVisitArgs{VisitType: tt.visitType, VetName: "Dr. Lee", Symptoms: tt.symptoms}, now)
+			if got.Note != tt.wantNote || got.Severity != tt.wantSeverity || got.Author != Vet {
+				t.Errorf("note = %+v, want %q from the vet at %s", got, tt.wantNote, tt.wantSeverity)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package resources
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+// BreedInfo is a breed's entry in the embedded breed catalog
+type BreedInfo struct {
+	Name        string `json:"name"`
+	DogCEOPath  string `json:"dogCeoPath"` // the breed's gallery on the Dog CEO image service
+	PageURL     string `json:"pageUrl"`
+	EnergyLevel int    `json:"energyLevel"` // exercise need from 1 (low) to 5 (very high)
+	ShortNosed  bool   `json:"shortNosed"`  // brachycephalic, so at risk in extreme temperatures
+	// LifeExpectancy is the breed's median lifespan in years
+	LifeExpectancy    float64 `json:"lifeExpectancy"`
+	GoodWithKids      int     `json:"goodWithKids"`      // from 1 (best with adults only) to 5 (great with children)
+	Shedding          int     `json:"shedding"`          // from 1 (barely sheds, easier on allergies) to 5 (heavy)
+	AloneHours        int     `json:"aloneHours"`        // hours a day an adult dog copes on its own
+	ApartmentFriendly bool    `json:"apartmentFriendly"` // settles in a home without a yard
+}
+
+//go:embed data/breeds.json
+var breedsJSON []byte
+
+// BreedCatalog describes every DogBreed
+var BreedCatalog = mustLoadBreedCatalog()
+
+func mustLoadBreedCatalog() map[DogBreed]BreedInfo {
+	var catalog map[DogBreed]BreedInfo
+	if err := json.Unmarshal(breedsJSON, &catalog); err != nil {
+		panic(fmt.Sprintf("embedded breed catalog: %v", err))
+	}
+	return catalog
+}
@@ -0,0 +1,25 @@
+package resources
+
+import "testing"
+
+func TestBreedCatalogCoversEveryBreed(t *testing.T) {
+	breeds := []DogBreed{GoldenRetriever, LabradorRetriever, GermanShepherd, Bulldog, Poodle, Beagle, Rottweiler, Husky}
+	for _, breed := range breeds {
+		entry, ok := BreedCatalog[breed]
+		if !ok || entry.Name == "" || entry.DogCEOPath == "" || entry.PageURL == "" {
+			t.Errorf("catalog entry for %s = %+v, want name, gallery and page", breed, entry)
+		}
+		if entry.EnergyLevel < 1 || entry.EnergyLevel > 5 {
+			t.Errorf("energy level of %s = %d, want 1 to 5", breed, entry.EnergyLevel)
+		}
+		if entry.LifeExpectancy < 6 || entry.LifeExpectancy > 18 {
+			t.Errorf("life expectancy of %s = %v, want 6 to 18 years", breed, entry.LifeExpectancy)
+		}
+		if entry.GoodWithKids < 1 || entry.GoodWithKids > 5 || entry.Shedding < 1 || entry.Shedding > 5 {
+			t.Errorf("kids and shedding ratings of %s = %d, %d, want 1 to 5", breed, entry.GoodWithKids, entry.Shedding)
+		}
+		if entry.AloneHours < 1 || entry.AloneHours > 12 {
+			t.Errorf("hours alone of %s = %d, want 1 to 12", breed, entry.AloneHours)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// careHistory is how many meals and walks a dog's care record keeps; the
+// mood only looks back a day or so
+const careHistory = 20
+
+// dogCare is what feedDog, giveTreat and recordWalk have logged for a dog,
+// stored under the dog's ID. Meals and walks count towards lastFed,
+// lastWalk and the mood like SmartFeeder reports and DogWalks do.
+type dogCare struct {
+	Treats    int         `json:"treats"`
+	WalkCount int         `json:"walkCount"`
+	Meals     []careEvent `json:"meals"` // newest last
+	Walks     []careEvent `json:"walks"` // newest last
+}
+
+type careEvent struct {
+	At     string `json:"at"`
+	Amount int    `json:"amount"` // grams fed or minutes walked
+}
+
+// DogStats is a dog's condition right after it was cared for
+type DogStats struct {
+	DogID       string `pulumi:"dogId"`
+	Happiness   int    `pulumi:"happiness"`
+	Energy      int    `pulumi:"energy"`
+	LastFed     string `pulumi:"lastFed"`
+	LastWalk    string `pulumi:"lastWalk"`
+	TotalWalks  int    `pulumi:"totalWalks"`
+	TotalTreats int    `pulumi:"totalTreats"`
+}
+
+// FeedDog logs a meal of portionGrams given now
+func FeedDog(ctx context.Context, dogID string, portionGrams int, dryRun bool) (DogStats, error) {
+	if portionGrams < 1 || portionGrams > 1000 {
+		return DogStats{}, fmt.Errorf("portion must be between 1 and 1000 grams")
+	}
+	return careFor(ctx, dogID, dryRun, func(care *dogCare, at string) {
+		care.Meals = latest(append(care.Meals, careEvent{At: at, Amount: portionGrams}))
+	})
+}
+
+// GiveTreats logs count treats
+func GiveTreats(ctx context.Context, dogID string, count int, dryRun bool) (DogStats, error) {
+	if count < 1 || count > 20 {
+		return DogStats{}, fmt.Errorf("count must be between 1 and 20")
+	}
+	return careFor(ctx, dogID, dryRun, func(care *dogCare, at string) {
+		care.Treats += count
+	})
+}
+
+// RecordWalk logs a walk of minutes that just ended
+func RecordWalk(ctx context.Context, dogID string, minutes int, dryRun bool) (DogStats, error) {
+	if minutes < 1 || minutes > 600 {
+		return DogStats{}, fmt.Errorf("minutes must be between 1 and 600")
+	}
+	return careFor(ctx, dogID, dryRun, func(care *dogCare, at string) {
+		care.WalkCount++
+		care.Walks = latest(append(care.Walks, careEvent{At: at, Amount: minutes}))
+	})
+}
+
+// careFor applies a change to the dog's care record and works out how the
+// dog is doing now. A dry run works it out without saving the change.
+func careFor(ctx context.Context, dogID string, dryRun bool, change func(care *dogCare, at string)) (DogStats, error) {
+	dog, err := walkedDog(ctx, dogID)
+	if err != nil {
+		return DogStats{}, err
+	}
+	care, version, err := loadCare(ctx, dogID)
+	if err != nil {
+		return DogStats{}, err
+	}
+	change(&care, registry.Now(ctx).Format("2006-01-02T15:04:05Z"))
+	if !dryRun {
+		if _, err := registry.Save(ctx, "dog-care", dogID, version, care); err != nil {
+			return DogStats{}, err
+		}
+	}
+	if err := moodWithCare(ctx, &dog, care); err != nil {
+		return DogStats{}, err
+	}
+	return DogStats{
+		DogID:       dog.ID,
+		Happiness:   dog.Happiness,
+		Energy:      dog.Energy,
+		LastFed:     dog.LastFed,
+		LastWalk:    dog.LastWalk,
+		TotalWalks:  dog.TotalWalks,
+		TotalTreats: dog.TotalTreats,
+	}, nil
+}
+
+func loadCare(ctx context.Context, dogID string) (dogCare, int64, error) {
+	var care dogCare
+	version, err := registry.Load(ctx, "dog-care", dogID, &care)
+	if errors.Is(err, backend.ErrNotFound) {
+		return care, 0, nil
+	}
+	return care, version, err
+}
+
+func latest(events []careEvent) []careEvent {
+	if len(events) > careHistory {
+		return events[len(events)-careHistory:]
+	}
+	return events
+}
+
+// asRecords presents the logged meals and walks as the feeder reports and
+// walks the mood is worked out from
+func (care dogCare) asRecords() ([]DogWalkState, []SmartFeederState) {
+	walks := make([]DogWalkState, len(care.Walks))
+	for i, walk := range care.Walks {
+		walks[i].Date, walks[i].Duration = walk.At, walk.Amount
+	}
+	meals := make([]SmartFeederState, len(care.Meals))
+	for i, meal := range care.Meals {
+		meals[i].LastDispense = meal.At
+	}
+	return walks, meals
+}
@@ -0,0 +1,46 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestCareLimits(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		care func() (DogStats, error)
+		want string
+	}{
+		{name: "empty meal", care: func() (DogStats, error) { return FeedDog(ctx, "dog-rex", 0, false) }, want: "portion must be between 1 and 1000 grams"},
+		{name: "whole sack", care: func() (DogStats, error) { return FeedDog(ctx, "dog-rex", 5000, false) }, want: "portion must be between 1 and 1000 grams"},
+		{name: "no treats", care: func() (DogStats, error) { return GiveTreats(ctx, "dog-rex", 0, false) }, want: "count must be between 1 and 20"},
+		{name: "all the treats", care: func() (DogStats, error) { return GiveTreats(ctx, "dog-rex", 50, false) }, want: "count must be between 1 and 20"},
+		{name: "endless walk", care: func() (DogStats, error) { return RecordWalk(ctx, "dog-rex", 900, false) }, want: "minutes must be between 1 and 600"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.care(); err == nil || err.Error() != tt.want {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCareAsRecords(t *testing.T) {
+	var care dogCare
+	for i := 0; i < careHistory+5; i++ {
+		care.Walks = latest(append(care.Walks, careEvent{At: fmt.Sprintf("2026-10-%02dT08:00:00Z", i+1), Amount: 30 + i}))
+	}
+	care.Meals = []careEvent{{At: "2026-10-16T07:00:00Z", Amount: 200}}
+
+	walks, feeders := care.asRecords()
+	if len(walks) != careHistory || walks[0].Date != "2026-10-06T08:00:00Z" || walks[0].Duration != 35 {
+		t.Errorf("walks = %d starting %+v, want the latest %d", len(walks), walks[0], careHistory)
+	}
+	lastWalk, lastFed := lastActivity(walks, feeders)
+	if lastWalk != "2026-10-25T08:00:00Z" || lastFed != "2026-10-16T07:00:00Z" {
+		t.Errorf("last activity = %s/%s", lastWalk, lastFed)
+	}
+}
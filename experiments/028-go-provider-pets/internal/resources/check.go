@@ -0,0 +1,129 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/validate"
+)
+
+// checkInputs decodes newInputs the way infer does by default, then applies
+// the `validate` tags of the Args struct, so a resource's Check only needs
+// to forward here. Properties that only exist on the State are computed by
+// the provider and rejected when a program tries to set them.
+func checkInputs[A, S any](newInputs resource.PropertyMap) (A, []p.CheckFailure, error) {
+	var failures []p.CheckFailure
+	inputs := properties(reflect.TypeOf((*A)(nil)).Elem())
+	var computed []string
+	for property := range properties(reflect.TypeOf((*S)(nil)).Elem()) {
+		if !inputs[property] {
+			computed = append(computed, property)
+		}
+	}
+	sort.Strings(computed)
+	for _, property := range computed {
+		if _, set := newInputs[resource.PropertyKey(property)]; set {
+			failures = append(failures, p.CheckFailure{
+				Property: property,
+				Reason:   property + " is computed by the provider and cannot be set",
+			})
+		}
+	}
+
+	// The decoder panics on a null it has to put in a list, so those are
+	// failures before it runs
+	var nulls []p.CheckFailure
+	for _, key := range newInputs.StableKeys() {
+		nulls = append(nulls, nullElements(string(key), newInputs[key])...)
+	}
+	if len(nulls) > 0 {
+		var args A
+		return args, append(failures, nulls...), nil
+	}
+
+	args, decodeFailures, err := infer.DefaultCheck[A](newInputs)
+	if err != nil {
+		return args, failures, err
+	}
+	failures = append(failures, decodeFailures...)
+	return args, append(failures, validate.Struct(&args)...), nil
+}
+
+// nullElements reports the null elements of lists in v, wherever they are
+// nested
+func nullElements(property string, v resource.PropertyValue) []p.CheckFailure {
+	if v.IsSecret() {
+		v = v.SecretValue().Element
+	}
+	var failures []p.CheckFailure
+	switch {
+	case v.IsArray():
+		for i, element := range v.ArrayValue() {
+			path := fmt.Sprintf("%s[%d]", property, i)
+			if element.IsNull() {
+				failures = append(failures, p.CheckFailure{Property: path, Reason: path + " cannot be null"})
+				continue
+			}
+			failures = append(failures, nullElements(path, element)...)
+		}
+	case v.IsObject():
+		object := v.ObjectValue()
+		for _, key := range object.StableKeys() {
+			failures = append(failures, nullElements(property+"."+string(key), object[key])...)
+		}
+	}
+	return failures
+}
+
+// properties lists the pulumi property names of a struct, flattening
+// embedded structs the way infer does.
+func properties(t reflect.Type) map[string]bool {
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for name := range properties(field.Type) {
+				names[name] = true
+			}
+			continue
+		}
+		if name, _, _ := strings.Cut(field.Tag.Get("pulumi"), ","); name != "" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// warnDeprecated warns about each deprecated input the program sets. The
+// schema can't mark an input deprecated, so this is how programs hear of it
+// besides its description.
+func warnDeprecated[A any](ctx context.Context, newInputs resource.PropertyMap) {
+	for _, notice := range deprecatedSet[A](newInputs) {
+		p.GetLogger(ctx).Warning(notice)
+	}
+}
+
+// deprecatedSet describes the deprecated inputs of A set in newInputs, in
+// name order
+func deprecatedSet[A any](newInputs resource.PropertyMap) []string {
+	d, ok := any((*A)(nil)).(interface{ deprecatedInputs() map[string]string })
+	if !ok {
+		return nil
+	}
+	deprecated := d.deprecatedInputs()
+	var notices []string
+	for name, instead := range deprecated {
+		if v, set := newInputs[resource.PropertyKey(name)]; set && !v.IsNull() {
+			notices = append(notices, name+" is deprecated: "+instead)
+		}
+	}
+	sort.Strings(notices)
+	return notices
+}
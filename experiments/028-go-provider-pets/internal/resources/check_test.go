@@ -0,0 +1,173 @@
+package resources
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/validate"
+)
+
+// Malformed validate tags panic, so exercise every Args struct once with
+// its zero value to catch them before a deployment does.
+func TestArgsValidateTagsParse(t *testing.T) {
+	for name, args := range map[string]any{
+		"Dog":              &DogArgs{},
+		"DogWalk":          &DogWalkArgs{},
+		"VeterinaryVisit":  &VeterinaryVisitArgs{},
+		"Adoption":         &AdoptionArgs{},
+		"BulkDogIntake":    &BulkDogIntakeArgs{},
+		"RegistrySnapshot": &RegistrySnapshotArgs{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			validate.Struct(args)
+		})
+	}
+}
+
+func TestIntakeProblem(t *testing.T) {
+	tests := []struct {
+		name string
+		spec DogArgs
+		seen map[string]bool
+		want string
+	}{
+		{
+			name: "valid",
+			spec: DogArgs{Name: "Rex", Breed: Beagle, OwnerName: "Shelter"},
+			want: "",
+		},
+		{
+			name: "missing name",
+			spec: DogArgs{Breed: Beagle, OwnerName: "Shelter"},
+			want: "name is required",
+		},
+		{
+			name: "negative age",
+			spec: DogArgs{Name: "Rex", Breed: Beagle, OwnerName: "Shelter", Age: intPtr(-2)},
+			want: "age must be at least 0",
+		},
+		{
+			name: "zero weight",
+			spec: DogArgs{Name: "Rex", Breed: Beagle, OwnerName: "Shelter", Weight: floatPtr(0)},
+			want: "weight must be greater than 0",
+		},
+		{
+			name: "duplicate",
+			spec: DogArgs{Name: "Rex", Breed: Beagle, OwnerName: "Shelter"},
+			seen: map[string]bool{"shelter/rex": true},
+			want: "duplicate name in this intake batch",
+		},
+		{
+			name: "another owner's name",
+			spec: DogArgs{Name: "Rex", Breed: Beagle, OwnerName: "Ann"},
+			seen: map[string]bool{"shelter/rex": true},
+			want: "",
+		},
+		{
+			name: "approval",
+			spec: DogArgs{Name: "Rex", Breed: Beagle, OwnerName: "Shelter", ApprovalArgs: ApprovalArgs{RequiresApproval: boolPtr(true)}},
+			want: "requiresApproval is not supported in bulk intakes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := intakeProblem(tt.spec, tt.seen); got != tt.want {
+				t.Errorf("intakeProblem = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckRejectsComputedProperties(t *testing.T) {
+	tests := []struct {
+		name   string
+		inputs resource.PropertyMap
+		want   []string
+	}{
+		{
+			name:   "inputs only",
+			inputs: resource.PropertyMap{"dogId": {V: "dog-rex-1"}, "duration": {V: 30.0}},
+		},
+		{
+			name:   "computed outputs",
+			inputs: resource.PropertyMap{"dogId": {V: "dog-rex-1"}, "enjoyment": {V: "high"}, "calories": {V: 9000.0}},
+			want:   []string{"calories", "enjoyment"},
+		},
+		{
+			name:   "version is computed too",
+			inputs: resource.PropertyMap{"version": {V: 7.0}},
+			want:   []string{"version"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, failures, err := checkInputs[DogWalkArgs, DogWalkState](tt.inputs)
+			if err != nil {
+				t.Fatalf("checkInputs: %v", err)
+			}
+			var got []string
+			for _, f := range failures {
+				if strings.HasSuffix(f.Reason, "is computed by the provider and cannot be set") {
+					got = append(got, f.Property)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("rejected %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckRejectsNullListElements(t *testing.T) {
+	inputs := resource.PropertyMap{
+		"shelterId": {V: "shelter"},
+		"dogs": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewNullProperty(),
+			resource.NewObjectProperty(resource.PropertyMap{"tags": resource.NewArrayProperty([]resource.PropertyValue{resource.NewNullProperty()})}),
+		}),
+	}
+	_, failures, err := checkInputs[BulkDogIntakeArgs, BulkDogIntakeState](inputs)
+	if err != nil {
+		t.Fatalf("checkInputs: %v", err)
+	}
+	var got []string
+	for _, f := range failures {
+		got = append(got, f.Property)
+	}
+	if want := "dogs[0],dogs[1].tags[0]"; strings.Join(got, ",") != want {
+		t.Errorf("failures on %q, want %s", got, want)
+	}
+}
+
+func TestDeprecatedSet(t *testing.T) {
+	tests := []struct {
+		name   string
+		inputs resource.PropertyMap
+		want   []string
+	}{
+		{name: "none set", inputs: resource.PropertyMap{"name": {V: "Rex"}, "birthDate": {V: "2020-05-01"}}},
+		{name: "age", inputs: resource.PropertyMap{"age": {V: 4.0}}, want: []string{"age"}},
+		{name: "null age", inputs: resource.PropertyMap{"age": resource.NewNullProperty()}},
+		{name: "favorite activity", inputs: resource.PropertyMap{"favoriteActivity": {V: "fetch"}}, want: []string{"favoriteActivity"}},
+		{name: "both", inputs: resource.PropertyMap{"favoriteActivity": {V: "fetch"}, "age": {V: 4.0}}, want: []string{"age", "favoriteActivity"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, notice := range deprecatedSet[DogArgs](tt.inputs) {
+				name, _, _ := strings.Cut(notice, " is deprecated: ")
+				got = append(got, name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("deprecated inputs set = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if got := deprecatedSet[DogWalkArgs](resource.PropertyMap{"age": {V: 4.0}}); got != nil {
+		t.Errorf("DogWalk has no deprecated inputs, got %q", got)
+	}
+}
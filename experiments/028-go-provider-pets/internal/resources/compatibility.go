@@ -0,0 +1,298 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// dogProfile is what the compatibility model goes by about a dog
+type dogProfile struct {
+	Name      string
+	Size      PetSize
+	Energy    int // from 1 (low) to 5 (very high)
+	Incidents int // recent behavior concerns, alerts counting double
+}
+
+// CompatibilityFactor is one thing the compatibility model weighs, scored
+// out of 100
+type CompatibilityFactor struct {
+	Factor string `pulumi:"factor" json:"factor"` // size, energy or behavior
+	Score  int    `pulumi:"score" json:"score"`
+	Weight int    `pulumi:"weight" json:"weight"` // Percent of the overall score the factor makes up
+	Detail string `pulumi:"detail" json:"detail"`
+}
+
+// Compatibility is how well two dogs suit each other
+type Compatibility struct {
+	Score       int                   `pulumi:"score"` // Out of 100
+	Risk        string                `pulumi:"risk"`  // low, moderate or high
+	Factors     []CompatibilityFactor `pulumi:"factors"`
+	Supervision string                `pulumi:"supervision"` // How closely to supervise the dogs together
+}
+
+// DogTraits describes a dog to the compatibility model without it being
+// registered
+type DogTraits struct {
+	Name      *string  `pulumi:"name,optional"`
+	Breed     DogBreed `pulumi:"breed"`
+	Size      *PetSize `pulumi:"size,optional"`      // Worked out from the breed when unset
+	Age       *int     `pulumi:"age,optional"`       // In years; 2 when unset
+	Incidents *int     `pulumi:"incidents,optional"` // Behavior concerns in the last year, alerts counting double
+}
+
+// CompatibilityScoreArgs are two dogs, each given by the ID of a
+// registered dog or by its traits
+type CompatibilityScoreArgs struct {
+	DogAID *string    `pulumi:"dogAId,optional"`
+	DogBID *string    `pulumi:"dogBId,optional"`
+	DogA   *DogTraits `pulumi:"dogA,optional"` // The first dog's traits, instead of dogAId
+	DogB   *DogTraits `pulumi:"dogB,optional"` // The second dog's traits, instead of dogBId
+}
+
+// Compatibility model weights, adding up to 100, and the thresholds the
+// overall score is judged by. A pairing is high risk when any one factor
+// scores under riskyFactor, however well the others do.
+const (
+	sizeWeight     = 35
+	energyWeight   = 30
+	behaviorWeight = 35
+
+	lowRiskScore      = 70
+	moderateRiskScore = 45
+	riskyFactor       = 25
+
+	// incidentWindowDays is how far back behavior incidents count
+	incidentWindowDays = 365
+)
+
+// Pairing risks
+const (
+	RiskLow      = "low"
+	RiskModerate = "moderate"
+	RiskHigh     = "high"
+)
+
+// sizeGapScores and energyGapScores score a difference in size rank or
+// energy level
+var (
+	sizeGapScores   = []int{100, 75, 35, 0}
+	energyGapScores = []int{100, 80, 50, 20, 0}
+)
+
+// supervisionAdvice is how closely to supervise two dogs of each risk
+var supervisionAdvice = map[string]string{
+	RiskLow:      "Light supervision: keep an eye on play and step in if it gets rough",
+	RiskModerate: "Close supervision: introduce the dogs on leash, stay within reach and give them breaks",
+	RiskHigh:     "One handler per dog: meet on neutral ground on leash, keep the first session short and separate them at the first sign of stress",
+}
+
+// CompatibilityScore runs the compatibility model on two dogs. Playdates
+// are assessed the same way.
+func CompatibilityScore(ctx context.Context, args CompatibilityScoreArgs) (Compatibility, error) {
+	a, err := resolveProfile(ctx, "dogA", args.DogAID, args.DogA)
+	if err != nil {
+		return Compatibility{}, err
+	}
+	b, err := resolveProfile(ctx, "dogB", args.DogBID, args.DogB)
+	if err != nil {
+		return Compatibility{}, err
+	}
+	if args.DogAID != nil && args.DogBID != nil && *args.DogAID == *args.DogBID {
+		return Compatibility{}, fmt.Errorf("dogAId and dogBId are the same dog")
+	}
+	return assess(a, b), nil
+}
+
+// resolveProfile is the profile of the dog given as side: the registered
+// dog id, or one with traits
+func resolveProfile(ctx context.Context, side string, id *string, traits *DogTraits) (dogProfile, error) {
+	switch {
+	case id != nil && traits != nil:
+		return dogProfile{}, fmt.Errorf("give either %sId or %s, not both", side, side)
+	case id != nil:
+		return loadProfile(ctx, *id)
+	case traits != nil:
+		if reason := traits.check(); reason != "" {
+			return dogProfile{}, fmt.Errorf("%s: %s", side, reason)
+		}
+		return traits.profile(side), nil
+	default:
+		return dogProfile{}, fmt.Errorf("%sId or %s is required", side, side)
+	}
+}
+
+// check is what is wrong with the traits, or empty when nothing is
+func (t DogTraits) check() string {
+	switch {
+	case BreedCatalog[t.Breed].Name == "":
+		return fmt.Sprintf("%q is not a known breed", t.Breed)
+	case t.Size != nil && sizeRank[*t.Size] == 0:
+		return "size must be one of small, medium, large or extra-large"
+	case t.Age != nil && (*t.Age < 0 || *t.Age > 30):
+		return "age must be between 0 and 30"
+	case t.Incidents != nil && *t.Incidents < 0:
+		return "incidents can't be negative"
+	}
+	return ""
+}
+
+// profile is the profile of a dog with the traits, called name when the
+// traits don't name it
+func (t DogTraits) profile(name string) dogProfile {
+	age := 2
+	if t.Age != nil {
+		age = *t.Age
+	}
+	if t.Name != nil && *t.Name != "" {
+		name = *t.Name
+	}
+	profile := newProfile(name, t.Breed, t.Size, float64(age))
+	if t.Incidents != nil {
+		profile.Incidents = *t.Incidents
+	}
+	return profile
+}
+
+// loadProfile sizes a dog up from its record and behavior history
+func loadProfile(ctx context.Context, dogID string) (dogProfile, error) {
+	dog, err := walkedDog(ctx, dogID)
+	if err != nil {
+		return dogProfile{}, err
+	}
+	var history DogHistory
+	_, err = registry.Load(ctx, "dog-history", dogID, &history)
+	if errors.Is(err, backend.ErrNotFound) {
+		history.BehaviorNotes = dog.BehaviorNotes
+	} else if err != nil {
+		return dogProfile{}, err
+	}
+	notes := history.BehaviorNotes
+	walks, err := listForDog[DogWalkState](ctx, "walk", dogID)
+	if err != nil {
+		return dogProfile{}, err
+	}
+	for _, walk := range walks {
+		notes = append(notes, walk.BehaviorNote)
+	}
+	return profileOf(dog, notes, registry.Now(ctx)), nil
+}
+
+// profileOf is the dog's profile going by its behavior notes as of now.
+// The vet's notes are about its health, not its behavior, and leave the
+// incident count alone.
+func profileOf(dog DogState, notes []BehaviorNote, now time.Time) dogProfile {
+	profile := newProfile(dog.Name, dog.Breed, dog.Size, dogAgeYears(dog))
+	since := now.AddDate(0, 0, -incidentWindowDays)
+	for _, note := range notes {
+		if note.Author == Vet {
+			continue
+		}
+		if at, ok := parseStamp(note.Timestamp); ok && at.Before(since) {
+			continue
+		}
+		switch note.Severity {
+		case Concern:
+			profile.Incidents++
+		case Alert:
+			profile.Incidents += 2
+		}
+	}
+	return profile
+}
+
+// newProfile is the profile of a dog with no incidents: its size, else its
+// breed's, and its breed's energy level adjusted for its life stage
+func newProfile(name string, breed DogBreed, size *PetSize, age float64) dogProfile {
+	profile := dogProfile{Name: name, Size: determineSizeByBreed(breed), Energy: 3}
+	if size != nil {
+		profile.Size = *size
+	}
+	if info, ok := BreedCatalog[breed]; ok && info.EnergyLevel > 0 {
+		profile.Energy = info.EnergyLevel
+	}
+	// Puppies have energy to spare; old dogs tire of a young one quickly
+	switch lifeStage(profile.Size, age) {
+	case LifeStagePuppy:
+		profile.Energy = min(5, profile.Energy+1)
+	case LifeStageSenior:
+		profile.Energy = max(1, profile.Energy-1)
+	}
+	return profile
+}
+
+// assess is the model's verdict on a pairing
+func assess(a, b dogProfile) Compatibility {
+	score, factors := compatibility(a, b)
+	risk := pairingRisk(score, factors)
+	return Compatibility{Score: score, Risk: risk, Factors: factors, Supervision: supervisionAdvice[risk]}
+}
+
+// compatibility scores a pairing out of 100 with the factors that went
+// into it
+func compatibility(a, b dogProfile) (int, []CompatibilityFactor) {
+	sizeGap := abs(sizeRank[a.Size] - sizeRank[b.Size])
+	energyGap := abs(a.Energy - b.Energy)
+	incidents := a.Incidents + b.Incidents
+
+	factors := []CompatibilityFactor{
+		{Factor: "size", Weight: sizeWeight, Score: sizeGapScores[min(sizeGap, len(sizeGapScores)-1)], Detail: sizeDetail(a, b, sizeGap)},
+		{Factor: "energy", Weight: energyWeight, Score: energyGapScores[min(energyGap, len(energyGapScores)-1)], Detail: energyDetail(a, b, energyGap)},
+		{Factor: "behavior", Weight: behaviorWeight, Score: clampScore(100 * halve(float64(incidents), 1)), Detail: behaviorDetail(a, b)},
+	}
+	total := 0.0
+	for _, f := range factors {
+		total += float64(f.Score*f.Weight) / 100
+	}
+	return int(math.Round(total)), factors
+}
+
+// pairingRisk judges a compatibility score and its factors
+func pairingRisk(score int, factors []CompatibilityFactor) string {
+	for _, f := range factors {
+		if f.Score < riskyFactor {
+			return RiskHigh
+		}
+	}
+	switch {
+	case score >= lowRiskScore:
+		return RiskLow
+	case score >= moderateRiskScore:
+		return RiskModerate
+	default:
+		return RiskHigh
+	}
+}
+
+func sizeDetail(a, b dogProfile, gap int) string {
+	if gap == 0 {
+		return fmt.Sprintf("both are %s", a.Size)
+	}
+	return fmt.Sprintf("%s is %s and %s is %s", a.Name, a.Size, b.Name, b.Size)
+}
+
+func energyDetail(a, b dogProfile, gap int) string {
+	if gap == 0 {
+		return fmt.Sprintf("both have energy level %d of 5", a.Energy)
+	}
+	return fmt.Sprintf("%s has energy level %d and %s %d, of 5", a.Name, a.Energy, b.Name, b.Energy)
+}
+
+func behaviorDetail(a, b dogProfile) string {
+	if a.Incidents+b.Incidents == 0 {
+		return fmt.Sprintf("no behavior incidents in the last %d days", incidentWindowDays)
+	}
+	return fmt.Sprintf("behavior incidents in the last %d days: %s %d, %s %d (alerts count double)", incidentWindowDays, a.Name, a.Incidents, b.Name, b.Incidents)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
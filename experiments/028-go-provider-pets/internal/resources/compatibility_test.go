@@ -0,0 +1,128 @@
+package resources
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCompatibility(t *testing.T) {
+	dog := func(name string, size PetSize, energy, incidents int) dogProfile {
+		return dogProfile{Name: name, Size: size, Energy: energy, Incidents: incidents}
+	}
+	tests := []struct {
+		name  string
+		a, b  dogProfile
+		score int
+		risk  string
+	}{
+		{name: "well matched", a: dog("Rex", Medium, 3, 0), b: dog("Pip", Medium, 3, 0), score: 100, risk: RiskLow},
+		{name: "a step apart", a: dog("Rex", Medium, 3, 0), b: dog("Pip", Large, 5, 0), score: 76, risk: RiskLow},
+		{name: "incidents and a size gap", a: dog("Rex", Medium, 3, 1), b: dog("Pip", Large, 3, 1), score: 65, risk: RiskModerate},
+		{name: "repeat incidents", a: dog("Rex", Medium, 3, 3), b: dog("Pip", Medium, 3, 0), score: 70, risk: RiskHigh},
+		{name: "toy and giant", a: dog("Rex", Small, 3, 0), b: dog("Pip", ExtraLarge, 3, 0), score: 65, risk: RiskHigh},
+		{name: "nothing in common", a: dog("Rex", Small, 1, 2), b: dog("Pip", Large, 5, 0), score: 21, risk: RiskHigh},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, factors := compatibility(tt.a, tt.b)
+			if score != tt.score {
+				t.Errorf("score = %d, want %d (factors %+v)", score, tt.score, factors)
+			}
+			if risk := pairingRisk(score, factors); risk != tt.risk {
+				t.Errorf("risk = %s, want %s", risk, tt.risk)
+			}
+		})
+	}
+}
+
+func TestProfileOf(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	stamp := func(ago time.Duration) string { return now.Add(-ago).Format("2006-01-02T15:04:05Z") }
+	notes := []BehaviorNote{
+		{Timestamp: stamp(time.Hour), Author: Owner, Severity: Info, Note: "Played nicely"},
+		{Timestamp: stamp(24 * time.Hour), Author: Owner, Severity: Concern, Note: "Growled at a puppy"},
+		{Timestamp: stamp(48 * time.Hour), Author: Trainer, Severity: Alert, Note: "Snapped at another dog"},
+		{Timestamp: stamp(72 * time.Hour), Author: Vet, Severity: Alert, Note: "Emergency visit"},
+		{Timestamp: stamp(2 * 365 * 24 * time.Hour), Author: Owner, Severity: Alert, Note: "Fought at the park"},
+		{Author: Owner, Severity: Concern, Note: "Noted before notes were timestamped"},
+	}
+	tests := []struct {
+		name   string
+		age    int
+		energy int
+	}{
+		{name: "adult", age: 4, energy: 4},
+		{name: "puppy", age: 0, energy: 5},
+		{name: "senior", age: 9, energy: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var beagle DogState
+			beagle.Name, beagle.Breed, beagle.Age = "Rex", Beagle, intPtr(tt.age)
+			profile := profileOf(beagle, notes, now)
+			if profile.Size != Medium || profile.Energy != tt.energy || profile.Incidents != 4 {
+				t.Errorf("profile = %+v, want a medium dog of energy %d with 4 incidents", profile, tt.energy)
+			}
+		})
+	}
+}
+
+func TestPairingVerdict(t *testing.T) {
+	a, b := dogProfile{Name: "Rex", Size: Small, Energy: 3}, dogProfile{Name: "Pip", Size: ExtraLarge, Energy: 3}
+	score, factors := compatibility(a, b)
+	if pairingVerdict(a, b, score, factors, false) == "" {
+		t.Error("a high-risk pairing went ahead without acceptRisk")
+	}
+	if reason := pairingVerdict(a, b, score, factors, true); reason != "" {
+		t.Errorf("an accepted high-risk pairing was refused: %s", reason)
+	}
+	if got := concerns(factors); len(got) != 1 || got[0] != "Rex is small and Pip is extra-large" {
+		t.Errorf("concerns = %q", got)
+	}
+}
+
+func TestCompatibilityScoreFromTraits(t *testing.T) {
+	small, giant := Small, ExtraLarge
+	tests := []struct {
+		name  string
+		args  CompatibilityScoreArgs
+		score int
+		risk  string
+		err   bool
+	}{
+		{
+			name:  "two adult beagles",
+			args:  CompatibilityScoreArgs{DogA: &DogTraits{Breed: Beagle, Age: intPtr(4)}, DogB: &DogTraits{Breed: Beagle, Age: intPtr(5)}},
+			score: 100, risk: RiskLow,
+		},
+		{
+			name:  "a puppy and a senior",
+			args:  CompatibilityScoreArgs{DogA: &DogTraits{Breed: Beagle, Age: intPtr(0)}, DogB: &DogTraits{Breed: Beagle, Age: intPtr(10)}},
+			score: 85, risk: RiskLow,
+		},
+		{
+			name:  "sized by hand",
+			args:  CompatibilityScoreArgs{DogA: &DogTraits{Breed: Beagle, Size: &small}, DogB: &DogTraits{Breed: Beagle, Size: &giant}},
+			score: 65, risk: RiskHigh,
+		},
+		{name: "second dog missing", args: CompatibilityScoreArgs{DogA: &DogTraits{Breed: Beagle}}, err: true},
+		{name: "both ways", args: CompatibilityScoreArgs{DogA: &DogTraits{Breed: Beagle}, DogAID: stringPtr("dog-a"), DogB: &DogTraits{Breed: Beagle}}, err: true},
+		{name: "not a breed", args: CompatibilityScoreArgs{DogA: &DogTraits{Breed: "wolf"}, DogB: &DogTraits{Breed: Beagle}}, err: true},
+		{name: "negative incidents", args: CompatibilityScoreArgs{DogA: &DogTraits{Breed: Beagle, Incidents: intPtr(-1)}, DogB: &DogTraits{Breed: Beagle}}, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CompatibilityScore(context.Background(), tt.args)
+			if (err != nil) != tt.err {
+				t.Fatalf("err = %v, want error %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if got.Score != tt.score || got.Risk != tt.risk || len(got.Factors) != 3 || got.Supervision == "" {
+				t.Errorf("CompatibilityScore = %+v, want %d and %s risk", got, tt.score, tt.risk)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package resources
+
+import (
+	"context"
+
+	p "github.com/pulumi/pulumi-go-provider"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/currency"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// CostDisplay is embedded by resources that carry costs. Their cost outputs
+// stay in US dollars; this repeats them in the provider's displayCurrency.
+type CostDisplay struct {
+	DisplayCurrency string             `pulumi:"displayCurrency"`
+	DisplayCosts    map[string]float64 `pulumi:"displayCosts"` // each cost, keyed by its property name, in displayCurrency
+}
+
+// showCosts converts costs, in dollars, to the display currency. Should
+// that fail they are shown in dollars rather than failing the operation.
+func showCosts(ctx context.Context, costs map[string]float64) CostDisplay {
+	display, err := convertCosts(registry.ExchangeRates(ctx), registry.DisplayCurrency(ctx), costs)
+	if err != nil {
+		p.GetLogger(ctx).Warningf("showing costs in %s: %v", currency.Base, err)
+		display, _ = convertCosts(currency.Fallback(), currency.Base, costs)
+	}
+	return display
+}
+
+func convertCosts(rates currency.Rates, code string, costs map[string]float64) (CostDisplay, error) {
+	display := CostDisplay{DisplayCurrency: code, DisplayCosts: map[string]float64{}}
+	for name, amount := range costs {
+		converted, err := rates.Convert(amount, currency.Base, code)
+		if err != nil {
+			return display, err
+		}
+		display.DisplayCosts[name] = round2(converted)
+	}
+	return display, nil
+}
@@ -0,0 +1,38 @@
+package resources
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/currency"
+)
+
+func TestConvertCosts(t *testing.T) {
+	rates := currency.Rates{Base: "USD", Rates: map[string]float64{"EUR": 0.92, "JPY": 151.5}}
+	costs := map[string]float64{"monthlyFee": 45, "annualCost": 518.4}
+
+	tests := []struct {
+		code string
+		want map[string]float64
+		err  bool
+	}{
+		{code: "USD", want: map[string]float64{"monthlyFee": 45, "annualCost": 518.4}},
+		{code: "EUR", want: map[string]float64{"monthlyFee": 41.4, "annualCost": 476.93}},
+		{code: "JPY", want: map[string]float64{"monthlyFee": 6817.5, "annualCost": 78537.6}},
+		{code: "XYZ", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			display, err := convertCosts(rates, tt.code, costs)
+			if (err != nil) != tt.err {
+				t.Fatalf("err = %v, want error %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if display.DisplayCurrency != tt.code || !reflect.DeepEqual(display.DisplayCosts, tt.want) {
+				t.Errorf("display = %+v, want %s %v", display, tt.code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// crudState is implemented by the state of every registry-backed resource,
+// so the shared scaffolding can stamp and version it without knowing its
+// field names.
+type crudState interface {
+	stamp(id, created string)
+	identity() (id, created string)
+	setVersion(version int64)
+	storedVersion() int64
+}
+
+// crudStatePtr lets crudResource hold a State value and still call the
+// pointer methods of crudState on it.
+type crudStatePtr[S any] interface {
+	*S
+	crudState
+}
+
+// crudResource is the scaffolding shared by the registry-backed resources:
+// preview short-circuiting, idempotent creates, ID generation, timestamping
+// and persistence with optimistic versions. A concrete resource fills in the
+// hooks with its domain logic and forwards its CRUD methods here.
+type crudResource[A any, S any, P crudStatePtr[S]] struct {
+	// kind is the registry record kind the state is stored under
+	kind string
+	// Generated IDs read <prefix>-<slug(input)>-<registry.IDSuffix>
+	prefix string
+	slug   func(input A) string
+	// naturalID, when set, is the ID instead of a generated one, for
+	// resources others refer to by a key people already use; a second
+	// resource with the same key fails to create
+	naturalID func(input A) string
+	// newState copies the inputs into an otherwise empty state
+	newState func(input A) S
+	// defaults fills in optional inputs whose default depends on other
+	// inputs; static defaults are declared with `default` tags instead
+	defaults func(input *A)
+	// populate applies defaults and domain logic to a state being created
+	populate func(ctx context.Context, state P, input A) error
+	// abandon undoes what populate wrote elsewhere in the registry for a
+	// state that didn't get saved
+	abandon func(ctx context.Context, state S) error
+	// keep copies fields the provider owns from the old state on update,
+	// previews included
+	keep func(state P, oldState S)
+	// carry preserves dynamic state across an update that is applied
+	carry func(ctx context.Context, state P, oldState S, now time.Time) error
+	// announce runs once a new state is saved, to tell subscribers about it
+	announce func(ctx context.Context, state S)
+	// refresh brings a state loaded from the registry up to date
+	refresh func(ctx context.Context, id string, state P) error
+	// related record kinds share the resource ID and go away with it
+	related []string
+}
+
+// check decodes and validates new inputs with their defaults filled in, so
+// previews and SDK docs show the same values Create will store
+func (c crudResource[A, S, P]) check(newInputs resource.PropertyMap) (A, []p.CheckFailure, error) {
+	args, failures, err := checkInputs[A, S](newInputs)
+	if err != nil {
+		return args, failures, err
+	}
+	c.fillDefaults(&args)
+	return args, failures, nil
+}
+
+// fillDefaults applies the schema defaults and the defaults hook. The engine
+// runs Check first, so for Create and Update this is only a safety net.
+func (c crudResource[A, S, P]) fillDefaults(input *A) {
+	if d, ok := any(input).(interface{ applyDefaults() }); ok {
+		d.applyDefaults()
+	}
+	if c.defaults != nil {
+		c.defaults(input)
+	}
+}
+
+func (c crudResource[A, S, P]) create(ctx context.Context, name string, input A, preview bool) (string, S, error) {
+	c.fillDefaults(&input)
+	state := c.newState(input)
+
+	if preview {
+		return name, state, nil
+	}
+
+	if err := registry.BeginOperation(); err != nil {
+		return "", state, err
+	}
+	defer registry.EndOperation()
+
+	if err := registry.Delay(ctx, "create"); err != nil {
+		return "", state, err
+	}
+
+	fault := registry.Chaos(ctx, "create", name)
+	if fault != nil && !fault.Partial() {
+		return "", state, fault
+	}
+
+	// A retried deployment gets back the record it already created
+	key := registry.IdempotencyKey(ctx, c.kind, name)
+	if id, version, err := registry.CreatedBefore(ctx, c.kind, key, input, &state); err != nil {
+		return "", state, err
+	} else if id != "" {
+		P(&state).setVersion(version)
+		return id, state, nil
+	}
+
+	now := registry.Now(ctx)
+	state = c.stamped(input, now, registry.IDSuffix(ctx, name, now.Unix()))
+	id, _ := P(&state).identity()
+
+	if c.populate != nil {
+		if err := c.populate(ctx, &state, input); err != nil {
+			return "", state, c.abandoned(ctx, state, err)
+		}
+	}
+
+	version, err := registry.Save(ctx, c.kind, id, 0, state)
+	if c.naturalID != nil && errors.Is(err, backend.ErrConflict) {
+		err = fmt.Errorf("%s %s is already registered", c.kind, id)
+	}
+	if err != nil {
+		return "", state, c.abandoned(ctx, state, err)
+	}
+	P(&state).setVersion(version)
+
+	if err := registry.RememberCreate(ctx, key, c.kind, id, input); err != nil {
+		return "", state, err
+	}
+	if c.announce != nil {
+		c.announce(ctx, state)
+	}
+
+	if fault != nil {
+		// The record exists, so hand back the state with the failure
+		return id, state, infer.ResourceInitFailedError{Reasons: []string{fault.Error()}}
+	}
+	return id, state, nil
+}
+
+// abandoned runs the abandon hook for a state that failed to create with
+// err, and returns err along with anything the hook couldn't undo
+func (c crudResource[A, S, P]) abandoned(ctx context.Context, state S, err error) error {
+	if c.abandon == nil {
+		return err
+	}
+	if undo := c.abandon(ctx, state); undo != nil {
+		return errors.Join(err, undo)
+	}
+	return err
+}
+
+// stamped is a fresh state for input carrying its generated ID and timestamp
+func (c crudResource[A, S, P]) stamped(input A, now time.Time, suffix string) S {
+	state := c.newState(input)
+	id := fmt.Sprintf("%s-%s-%s", c.prefix, c.slug(input), suffix)
+	if c.naturalID != nil {
+		id = c.naturalID(input)
+	}
+	P(&state).stamp(id, now.Format("2006-01-02T15:04:05Z"))
+	return state
+}
+
+func (c crudResource[A, S, P]) update(ctx context.Context, id string, oldState S, input A, preview bool) (S, error) {
+	c.fillDefaults(&input)
+	state := c.newState(input)
+	P(&state).stamp(P(&oldState).identity())
+	if c.keep != nil {
+		c.keep(&state, oldState)
+	}
+
+	if preview {
+		return state, nil
+	}
+
+	if err := registry.BeginOperation(); err != nil {
+		return state, err
+	}
+	defer registry.EndOperation()
+
+	if err := registry.Delay(ctx, "update"); err != nil {
+		return oldState, err
+	}
+
+	fault := registry.Chaos(ctx, "update", id)
+	if fault != nil && !fault.Partial() {
+		return oldState, fault
+	}
+
+	if c.carry != nil {
+		if err := c.carry(ctx, &state, oldState, registry.Now(ctx)); err != nil {
+			return oldState, err
+		}
+	}
+
+	version, err := c.updateVersion(ctx, id, oldState)
+	if err != nil {
+		return state, err
+	}
+	version, err = registry.Save(ctx, c.kind, id, version, state)
+	if err != nil {
+		return state, err
+	}
+	P(&state).setVersion(version)
+
+	if fault != nil {
+		return state, infer.ResourceInitFailedError{Reasons: []string{fault.Error()}}
+	}
+	return state, nil
+}
+
+// updateVersion is the version an update of oldState has to find. A
+// simulated run starts from the registry on disk, which never got the
+// records earlier simulated runs wrote, so there a missing record is
+// created afresh.
+func (c crudResource[A, S, P]) updateVersion(ctx context.Context, id string, oldState S) (int64, error) {
+	if !registry.Simulating(ctx) {
+		return P(&oldState).storedVersion(), nil
+	}
+	var stored S
+	_, err := registry.Load(ctx, c.kind, id, &stored)
+	if errors.Is(err, backend.ErrNotFound) {
+		return 0, nil
+	}
+	return P(&oldState).storedVersion(), err
+}
+
+func (c crudResource[A, S, P]) read(ctx context.Context, id string, inputs A, state S) (string, A, S, error) {
+	if err := registry.Delay(ctx, "read"); err != nil {
+		return id, inputs, state, err
+	}
+
+	var stored S
+	version, err := registry.Load(ctx, c.kind, id, &stored)
+	if errors.Is(err, backend.ErrNotFound) {
+		// Removed from the registry out-of-band
+		return "", inputs, state, nil
+	}
+	if err != nil {
+		return id, inputs, state, err
+	}
+	P(&stored).setVersion(version)
+
+	if c.refresh != nil {
+		if err := c.refresh(ctx, id, &stored); err != nil {
+			return id, inputs, state, err
+		}
+	}
+	return id, inputs, stored, nil
+}
+
+func (c crudResource[A, S, P]) delete(ctx context.Context, id string, state S) error {
+	if err := registry.BeginOperation(); err != nil {
+		return err
+	}
+	defer registry.EndOperation()
+
+	if err := registry.Delay(ctx, "delete"); err != nil {
+		return err
+	}
+
+	fault := registry.Chaos(ctx, "delete", id)
+	if fault != nil && !fault.Partial() {
+		return fault
+	}
+
+	for _, kind := range c.related {
+		if err := registry.Delete(ctx, kind, id, backend.AnyVersion); err != nil {
+			return err
+		}
+	}
+	if fault != nil {
+		// Related records are gone but the resource itself stays behind
+		return fault
+	}
+	if err := registry.Delete(ctx, c.kind, id, P(&state).storedVersion()); err != nil {
+		return err
+	}
+	return registry.ForgetCreate(ctx, c.kind, id)
+}
+
+// listForDog loads every record of kind whose DogID is dogID
+func listForDog[S any](ctx context.Context, kind, dogID string) ([]S, error) {
+	return listRecords[S](ctx, backend.Query{
+		Kind:  kind,
+		Where: []backend.Condition{{Field: "DogID", Op: "eq", Value: dogID}},
+	})
+}
+
+// listRecords decodes every record q matches
+func listRecords[S any](ctx context.Context, q backend.Query) ([]S, error) {
+	store, err := registry.Store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	records, _, err := store.ListPage(q)
+	if err != nil {
+		return nil, err
+	}
+	states := make([]S, len(records))
+	for i, rec := range records {
+		if err := json.Unmarshal(rec.Payload, &states[i]); err != nil {
+			return nil, fmt.Errorf("%s %q: %w", rec.Kind, rec.ID, err)
+		}
+	}
+	return states, nil
+}
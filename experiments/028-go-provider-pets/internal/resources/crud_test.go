@@ -0,0 +1,118 @@
+package resources
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// configured is a context whose registry is opened with config, in a fresh
+// data directory unless config names one
+func configured(t *testing.T, config registry.Config) context.Context {
+	t.Helper()
+	if config.DataDir == nil {
+		dir := t.TempDir()
+		config.DataDir = &dir
+	}
+	if err := config.Configure(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return registry.WithConfig(context.Background(), &config)
+}
+
+func TestCrudStamped(t *testing.T) {
+	now := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		stamp       func() (string, string)
+		wantID      string
+		wantCreated string
+	}{
+		{
+			name: "dog",
+			stamp: func() (string, string) {
+				state := dogs.stamped(DogArgs{Name: "Good Boy"}, now, "1709640000")
+				return state.ID, state.RegistrationDate
+			},
+			wantID:      "dog-good-boy-1709640000",
+			wantCreated: "2024-03-05T12:00:00Z",
+		},
+		{
+			name: "walk",
+			stamp: func() (string, string) {
+				state := walks.stamped(DogWalkArgs{DogID: "dog-rex-1"}, now, "1709640000")
+				return state.ID, state.Date
+			},
+			wantID:      "walk-dog-rex-1-1709640000",
+			wantCreated: "2024-03-05T12:00:00Z",
+		},
+		{
+			name: "visit",
+			stamp: func() (string, string) {
+				state := visits.stamped(VeterinaryVisitArgs{DogID: "dog-rex-1"}, now, "1709640000")
+				return state.ID, state.Date
+			},
+			wantID:      "vet-dog-rex-1-1709640000",
+			wantCreated: "2024-03-05T12:00:00Z",
+		},
+		{
+			name: "adoption",
+			stamp: func() (string, string) {
+				state := adoptions.stamped(AdoptionArgs{DogID: "dog-rex-1"}, now, "1709640000")
+				return state.ApplicationID, state.SubmittedAt
+			},
+			wantID:      "adopt-dog-rex-1-1709640000",
+			wantCreated: "2024-03-05T12:00:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, created := tt.stamp()
+			if id != tt.wantID {
+				t.Errorf("id = %q, want %q", id, tt.wantID)
+			}
+			if created != tt.wantCreated {
+				t.Errorf("created = %q, want %q", created, tt.wantCreated)
+			}
+		})
+	}
+}
+
+func TestCrudUpdatePreviewKeepsIdentity(t *testing.T) {
+	old := DogWalkState{DogWalkOutputs: DogWalkOutputs{ID: "walk-dog-rex-1-1", Date: "2024-01-01T00:00:00Z", Calories: 120, Version: 3}}
+	state, err := walks.update(context.Background(), old.ID, old, DogWalkArgs{DogID: "dog-rex-1", Duration: 40}, true)
+	if err != nil {
+		t.Fatalf("preview update: %v", err)
+	}
+	if state.ID != old.ID || state.Date != old.Date {
+		t.Errorf("identity = %q/%q, want %q/%q", state.ID, state.Date, old.ID, old.Date)
+	}
+	if state.Duration != 40 {
+		t.Errorf("duration = %d, want the new input", state.Duration)
+	}
+	if state.Calories != 0 {
+		t.Errorf("calories = %d, want them recomputed rather than carried", state.Calories)
+	}
+}
+
+func TestCrudUpdateInALaterSimulatedRun(t *testing.T) {
+	dir, simulate := t.TempDir(), true
+	run := func() context.Context {
+		return configured(t, registry.Config{DataDir: &dir, Simulate: &simulate})
+	}
+
+	args := DogArgs{Name: "Rex", Breed: Bulldog, OwnerName: "Ann"}
+	id, state, err := Dog{}.Create(run(), "rex", args, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The next run has the first one's state but not its records
+	args.Tags = []string{"good"}
+	if _, err := (Dog{}).Update(run(), id, state, args, false); err != nil {
+		t.Errorf("Update in the next simulated run: %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// DietTransition Resource - switches a dog from one food to another over a
+// number of days, mixing in a little more of the new food each day. Moving
+// a dog off one of its favorite foods is logged as a warning.
+type DietTransition struct{}
+
+//pets:state id=ID created=CreatedAt
+//pets:output ID string id Generated identifier of the transition
+//pets:output CreatedAt string createdAt When the transition was planned
+//pets:output Plan []MixingDay plan What to feed on each day of the transition
+//pets:output CompletesOn string completesOn The first day the dog eats only toFood
+//pets:output Status string status Where the transition stands: scheduled, in-progress or complete; recomputed on refresh
+//pets:output ToFoodFavorite bool toFoodFavorite Whether toFood is among the dog's favorite foods
+type DietTransitionArgs struct {
+	DogID     string  `pulumi:"dogId" validate:"required"`
+	FromFood  string  `pulumi:"fromFood" validate:"required"`
+	ToFood    string  `pulumi:"toFood" validate:"required"`
+	Days      *int    `pulumi:"days,optional" default:"7" validate:"min=2,max=28"` // Length of the transition in days
+	StartDate *string `pulumi:"startDate,optional"`                                // First day of the transition as YYYY-MM-DD; defaults to today
+}
+
+// MixingDay is one day of a diet transition
+type MixingDay struct {
+	Day         int    `pulumi:"day" json:"day"`
+	Date        string `pulumi:"date" json:"date"`
+	FromPercent int    `pulumi:"fromPercent" json:"fromPercent"`
+	ToPercent   int    `pulumi:"toPercent" json:"toPercent"`
+}
+
+var transitions = crudResource[DietTransitionArgs, DietTransitionState, *DietTransitionState]{
+	kind:     stored[DietTransitionState]("diet-transition"),
+	prefix:   "diet",
+	slug:     func(input DietTransitionArgs) string { return input.DogID },
+	newState: newDietTransitionState,
+	populate: func(ctx context.Context, state *DietTransitionState, input DietTransitionArgs) error {
+		dog, err := walkedDog(ctx, input.DogID)
+		if err != nil {
+			return err
+		}
+		known, err := dogAllergies(ctx, dog)
+		if err != nil {
+			return err
+		}
+		if err := allergyConflict(dog, "toFood", input.ToFood, known); err != nil {
+			return err
+		}
+		state.ToFoodFavorite = dog.favoriteFood(input.ToFood)
+		if dog.favoriteFood(input.FromFood) && !state.ToFoodFavorite {
+			p.GetLogger(ctx).Warningf("%s is moving off %s, a favorite food; a longer transition may go down better", dog.Name, input.FromFood)
+		}
+
+		now := registry.Now(ctx)
+		start := now
+		if input.StartDate != nil {
+			if start, err = time.Parse(dateLayout, *input.StartDate); err != nil {
+				return fmt.Errorf("startDate: %w", err)
+			}
+		}
+		state.Plan = mixingPlan(start, *input.Days)
+		state.CompletesOn = state.Plan[len(state.Plan)-1].Date
+		state.Status = transitionStatus(state.Plan, now)
+		return nil
+	},
+	// The days pass without the program changing, so Read moves the status
+	// along
+	refresh: func(ctx context.Context, id string, state *DietTransitionState) error {
+		state.Status = transitionStatus(state.Plan, registry.Now(ctx))
+		return nil
+	},
+}
+
+func (DietTransition) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (DietTransitionArgs, []p.CheckFailure, error) {
+	args, failures, err := transitions.check(newInputs)
+	if args.StartDate != nil {
+		if _, perr := time.Parse(dateLayout, *args.StartDate); perr != nil {
+			failures = append(failures, p.CheckFailure{Property: "startDate", Reason: "startDate must be a YYYY-MM-DD date"})
+		}
+	}
+	if strings.EqualFold(strings.TrimSpace(args.FromFood), strings.TrimSpace(args.ToFood)) && args.ToFood != "" {
+		failures = append(failures, p.CheckFailure{Property: "toFood", Reason: "toFood must differ from fromFood"})
+	}
+	failures = append(failures, checkAllergies(ctx, args.DogID, "toFood", args.ToFood)...)
+	return args, failures, err
+}
+
+func (DietTransition) Create(ctx context.Context, name string, input DietTransitionArgs, preview bool) (string, DietTransitionState, error) {
+	return transitions.create(ctx, name, input, preview)
+}
+
+func (DietTransition) Read(ctx context.Context, id string, inputs DietTransitionArgs, state DietTransitionState) (string, DietTransitionArgs, DietTransitionState, error) {
+	return transitions.read(ctx, id, inputs, state)
+}
+
+func (DietTransition) Delete(ctx context.Context, id string, state DietTransitionState) error {
+	return transitions.delete(ctx, id, state)
+}
+
+// mixingPlan spreads the switch evenly over days starting on start. The
+// last day is all new food.
+func mixingPlan(start time.Time, days int) []MixingDay {
+	plan := make([]MixingDay, days)
+	for i := range plan {
+		to := int(math.Round(float64(i+1) * 100 / float64(days)))
+		plan[i] = MixingDay{
+			Day:         i + 1,
+			Date:        start.AddDate(0, 0, i).Format(dateLayout),
+			FromPercent: 100 - to,
+			ToPercent:   to,
+		}
+	}
+	return plan
+}
+
+// transitionStatus places the day of now in the plan; the transition is
+// complete once its last day has passed
+func transitionStatus(plan []MixingDay, now time.Time) string {
+	today := now.Format(dateLayout)
+	switch {
+	case len(plan) == 0 || today > plan[len(plan)-1].Date:
+		return "complete"
+	case today < plan[0].Date:
+		return "scheduled"
+	default:
+		return "in-progress"
+	}
+}
@@ -0,0 +1,57 @@
+// Code generated by genstate from diet_transition.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// DietTransitionOutputs are computed by the provider; Check rejects them as inputs
+type DietTransitionOutputs struct {
+	ID             string      `pulumi:"id"`
+	CreatedAt      string      `pulumi:"createdAt"`
+	Plan           []MixingDay `pulumi:"plan"`
+	CompletesOn    string      `pulumi:"completesOn"`
+	Status         string      `pulumi:"status"`
+	ToFoodFavorite bool        `pulumi:"toFoodFavorite"`
+	Version        int64       `pulumi:"version"`
+}
+
+// DietTransitionState echoes the inputs next to the computed outputs
+type DietTransitionState struct {
+	DietTransitionArgs
+	DietTransitionOutputs
+}
+
+// newDietTransitionState copies the inputs into an otherwise empty state
+func newDietTransitionState(input DietTransitionArgs) DietTransitionState {
+	return DietTransitionState{DietTransitionArgs: input}
+}
+
+func (s *DietTransitionState) stamp(id, created string)   { s.ID, s.CreatedAt = id, created }
+func (s *DietTransitionState) identity() (string, string) { return s.ID, s.CreatedAt }
+func (s *DietTransitionState) setVersion(version int64)   { s.Version = version }
+func (s *DietTransitionState) storedVersion() int64       { return s.Version }
+
+func (args *DietTransitionArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Days, "Length of the transition in days")
+	a.SetDefault(&args.Days, 7)
+	a.Describe(&args.StartDate, "First day of the transition as YYYY-MM-DD; defaults to today")
+}
+
+// applyDefaults fills unset optional inputs with their schema defaults
+func (args *DietTransitionArgs) applyDefaults() {
+	if args.Days == nil {
+		v := 7
+		args.Days = &v
+	}
+}
+
+func (state *DietTransitionState) Annotate(a infer.Annotator) {
+	state.DietTransitionArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the transition")
+	a.Describe(&state.CreatedAt, "When the transition was planned")
+	a.Describe(&state.Plan, "What to feed on each day of the transition")
+	a.Describe(&state.CompletesOn, "The first day the dog eats only toFood")
+	a.Describe(&state.Status, "Where the transition stands: scheduled, in-progress or complete; recomputed on refresh")
+	a.Describe(&state.ToFoodFavorite, "Whether toFood is among the dog's favorite foods")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
@@ -0,0 +1,56 @@
+package resources
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMixingPlan(t *testing.T) {
+	start := time.Date(2026, 6, 28, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		days   int
+		wantTo []int
+	}{
+		{name: "two days", days: 2, wantTo: []int{50, 100}},
+		{name: "four days", days: 4, wantTo: []int{25, 50, 75, 100}},
+		{name: "a week", days: 7, wantTo: []int{14, 29, 43, 57, 71, 86, 100}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := mixingPlan(start, tt.days)
+			if len(plan) != len(tt.wantTo) {
+				t.Fatalf("got %d days, want %d", len(plan), len(tt.wantTo))
+			}
+			for i, day := range plan {
+				if day.Day != i+1 || day.ToPercent != tt.wantTo[i] || day.FromPercent+day.ToPercent != 100 {
+					t.Errorf("day %d = %+v, want %d%% new food", i+1, day, tt.wantTo[i])
+				}
+			}
+			if want := start.AddDate(0, 0, tt.days-1).Format(dateLayout); plan[len(plan)-1].Date != want {
+				t.Errorf("last day %s, want %s", plan[len(plan)-1].Date, want)
+			}
+		})
+	}
+}
+
+func TestTransitionStatus(t *testing.T) {
+	plan := mixingPlan(time.Date(2026, 6, 10, 0, 0, 0, 0, time.UTC), 7)
+	tests := []struct {
+		today string
+		want  string
+	}{
+		{today: "2026-06-09", want: "scheduled"},
+		{today: "2026-06-10", want: "in-progress"},
+		{today: "2026-06-16", want: "in-progress"},
+		{today: "2026-06-17", want: "complete"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.today, func(t *testing.T) {
+			now, _ := time.Parse(dateLayout, tt.today)
+			if got := transitionStatus(plan, now.Add(12*time.Hour)); got != tt.want {
+				t.Errorf("transitionStatus = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
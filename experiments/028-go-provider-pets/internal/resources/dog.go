@@ -0,0 +1,293 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// Dog Resource
+type Dog struct{}
+
+//pets:state id=ID created=RegistrationDate
+//pets:output ID string id Generated identifier of the dog
+//pets:output RegistrationDate string registrationDate When the dog joined the registry
+//pets:output AgeYears int ageYears Age in whole years, from birthDate as of the last refresh, else the age input
+//pets:output AgeMonths int ageMonths Months of age past ageYears; 0 without a birthDate
+//pets:output Health string health Current health assessment
+//pets:output Happiness int happiness Happiness score out of 100, recomputed on refresh from time since the last walk and meal
+//pets:output Energy int energy Energy score out of 100, recomputed on refresh from recent walks and dog park visits
+//pets:output Socialization int socialization Socialization score out of 100, recomputed on refresh from time at dog parks over the last few weeks
+//pets:output LastFed string lastFed When a SmartFeeder of the dog last reported dispensing, as of the last refresh; empty until one has
+//pets:output LastWalk string lastWalk When the dog's latest DogWalk was, as of the last refresh; empty until it has one
+//pets:output TotalWalks int totalWalks Walks recorded for the dog, as DogWalks or through recordWalk
+//pets:output TotalTreats int totalTreats Treats given to the dog through giveTreat
+//pets:output BehaviorNotes []BehaviorNote behaviorNotes The most recent observations about the dog's behavior
+//pets:output MedicalHistory []string medicalHistory The most recent notes from health checks and visits
+//pets:output BehaviorNoteCount int behaviorNoteCount Behavior notes recorded in total; getFullHistory returns them all
+//pets:output MedicalHistoryCount int medicalHistoryCount Medical history entries recorded in total; getFullHistory returns them all
+//pets:output CurrentWeight float64 currentWeight Weight in pounds from the dog's latest WeightLog, or the weight input until it has one
+//pets:output OwnershipTransfers []OwnershipTransfer ownershipTransfers Previous changes of owner, oldest first
+//pets:output VaccinationCurrent bool vaccinationCurrent Whether the latest dose of every recorded vaccine, including those given at visits, is unexpired
+//pets:embed ApprovalState
+type DogArgs struct {
+	Name             string         `pulumi:"name" validate:"required,max=64"`
+	Breed            DogBreed       `pulumi:"breed" validate:"required"`
+	Age              *int           `pulumi:"age,optional" default:"2" validate:"min=0,max=30" deprecated:"Use birthDate, which keeps the dog's age current; age will be removed in the next release."`
+	BirthDate        *string        `pulumi:"birthDate,optional"` // YYYY-MM-DD; the dog's age is worked out from it on every refresh, and age is ignored
+	Weight           *float64       `pulumi:"weight,optional" validate:"gt=0,max=350"`
+	Size             *PetSize       `pulumi:"size,optional" validate:"oneof=small|medium|large|extra-large"`
+	Color            *string        `pulumi:"color,optional" validate:"max=64"` // Coat color as people would describe it, e.g. black and tan; matchFoundPet compares it
+	IsGoodBoy        *bool          `pulumi:"isGoodBoy,optional" default:"true"`
+	FavoriteActivity *string        `pulumi:"favoriteActivity,optional" deprecated:"Use preferences.activityPreferences, which walks are scored against; favoriteActivity will be removed in the next release."`
+	Preferences      *Preferences   `pulumi:"preferences,optional"` // Favorite foods, toy types and activities
+	OwnerName        string         `pulumi:"ownerName" validate:"required"`
+	Microchipped     *bool          `pulumi:"microchipped,optional" default:"false"`
+	Vaccinations     []Vaccination  `pulumi:"vaccinations,optional"` // Doses given before the dog joined the registry or outside recorded visits
+	TrainingLevel    *TrainingLevel `pulumi:"trainingLevel,optional" default:"basic" validate:"oneof=untrained|basic|intermediate|advanced|professional"`
+	Tags             []string       `pulumi:"tags,optional"`
+	Allergies        []string       `pulumi:"allergies,optional"` // Ingredients the dog reacts to; diet transitions to foods containing them are refused
+	ApprovalArgs
+}
+
+var dogs = crudResource[DogArgs, DogState, *DogState]{
+	kind:     stored[DogState]("dog"),
+	prefix:   "dog",
+	slug:     dogSlug,
+	newState: newDogState,
+	defaults: breedDefaults,
+	populate: func(ctx context.Context, state *DogState, input DogArgs) error {
+		if err := claimName(ctx, *state); err != nil {
+			return err
+		}
+		initDogState(state, registry.Now(ctx))
+		if err := startHistory(ctx, state); err != nil {
+			return err
+		}
+
+		var err error
+		state.ApprovalState, err = requestApproval(ctx, "dog", state.ID, input.ApprovalArgs)
+		return err
+	},
+	abandon: releaseName,
+	keep: func(state *DogState, oldState DogState) {
+		state.ApprovalState = oldState.ApprovalState
+	},
+	carry: func(ctx context.Context, state *DogState, oldState DogState, now time.Time) error {
+		if renamed(oldState.Name, oldState.OwnerName, state.Name, state.OwnerName) {
+			if err := claimName(ctx, *state); err != nil {
+				return err
+			}
+			if err := releaseName(ctx, oldState); err != nil {
+				return err
+			}
+		}
+		carryDogState(state, oldState, now)
+		refreshAge(state, now)
+		if err := refreshMood(ctx, state); err != nil {
+			return err
+		}
+		if err := transferOwnership(ctx, state, oldState, now); err != nil {
+			return err
+		}
+		if err := refreshWeight(ctx, state); err != nil {
+			return err
+		}
+		if err := refreshVaccination(ctx, state); err != nil {
+			return err
+		}
+		return extendHistory(ctx, state, oldState, []BehaviorNote{updateNote(now)}, nil)
+	},
+	// Dogs age, doses expire, moods change and dogs get weighed as time
+	// passes, so Read recomputes ageYears, ageMonths, vaccinationCurrent,
+	// happiness, energy, socialization and currentWeight
+	refresh: func(ctx context.Context, id string, state *DogState) error {
+		refreshAge(state, registry.Now(ctx))
+		if err := refreshWeight(ctx, state); err != nil {
+			return err
+		}
+		if err := refreshVaccination(ctx, state); err != nil {
+			return err
+		}
+		if err := refreshMood(ctx, state); err != nil {
+			return err
+		}
+		var err error
+		if state.OwnershipTransfers, err = OwnershipTransfers(ctx, id); err != nil {
+			return err
+		}
+		return refreshApproval(ctx, id, &state.ApprovalState)
+	},
+	// Sad to see a dog go, but sometimes they find new homes
+	related: []string{"approval", "dog-care", "dog-history", "ownership"},
+}
+
+func (Dog) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (DogArgs, []p.CheckFailure, error) {
+	args, failures, err := dogs.check(newInputs)
+	warnDeprecated[DogArgs](ctx, newInputs)
+	failures = append(failures, checkBirthDate(args.BirthDate, registry.Now(ctx))...)
+	failures = append(failures, checkPreferences("preferences", args.Preferences)...)
+	warnNameTaken(ctx, oldInputs, args)
+	return args, append(failures, checkVaccinations("vaccinations", args.Vaccinations)...), err
+}
+
+func (Dog) Create(ctx context.Context, name string, input DogArgs, preview bool) (string, DogState, error) {
+	return dogs.create(ctx, name, input, preview)
+}
+
+// dogSlug turns a dog's name into the readable part of its ID
+func dogSlug(input DogArgs) string {
+	return slugify(input.Name)
+}
+
+// slugify makes a name fit for an ID
+func slugify(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, " ", "-"))
+}
+
+// registeredDog is a freshly registered dog, for callers that persist it
+// themselves as bulk intakes do
+func registeredDog(input DogArgs, now time.Time, suffix string) DogState {
+	dogs.fillDefaults(&input)
+	state := dogs.stamped(input, now, suffix)
+	initDogState(&state, now)
+	return state
+}
+
+// breedDefaults sizes a dog from its breed when the program doesn't say.
+// These depend on another input, so they can't be schema defaults.
+func breedDefaults(input *DogArgs) {
+	if input.Size == nil {
+		size := determineSizeByBreed(input.Breed)
+		input.Size = &size
+	}
+
+	if input.Weight == nil {
+		weight := EstimateWeightByBreed(input.Breed)
+		input.Weight = &weight
+	}
+}
+
+// initDogState gives a freshly registered dog its starting condition
+func initDogState(state *DogState, now time.Time) {
+	state.Health = "excellent"
+	state.Happiness = 95
+	state.Energy = 80
+	state.TotalWalks = 0
+	state.TotalTreats = 0
+	state.BehaviorNotes = []BehaviorNote{
+		observed(now, Owner, Info, fmt.Sprintf("%s is a lovely %s who loves attention", state.Name, state.Breed)),
+		observed(now, Owner, Info, "Shows excellent potential for training"),
+	}
+	state.MedicalHistory = []string{
+		"Initial health check - all systems normal",
+	}
+	if state.Weight != nil {
+		state.CurrentWeight = *state.Weight
+	}
+	state.VaccinationCurrent = vaccinationCurrent(state.Vaccinations, now)
+	refreshAge(state, now)
+}
+
+// refreshAge works out the dog's age as of now
+func refreshAge(state *DogState, now time.Time) {
+	state.AgeYears, state.AgeMonths = 0, 0
+	if state.BirthDate != nil {
+		if born, err := time.Parse(dateLayout, *state.BirthDate); err == nil {
+			state.AgeYears, state.AgeMonths = ageOn(born, now)
+			return
+		}
+	}
+	if state.Age != nil {
+		state.AgeYears = *state.Age
+	}
+}
+
+// ageOn is how old someone born on born is at now, in whole years and
+// months past them
+func ageOn(born, now time.Time) (int, int) {
+	months := (now.Year()-born.Year())*12 + int(now.Month()) - int(born.Month())
+	if now.Day() < born.Day() {
+		months--
+	}
+	if months < 0 {
+		return 0, 0
+	}
+	return months / 12, months % 12
+}
+
+// years is the dog's age in whole years, for callers that loaded it
+// through walkedDog
+func (dog DogState) years() int {
+	if dog.BirthDate != nil {
+		return dog.AgeYears
+	}
+	if dog.Age != nil {
+		return *dog.Age
+	}
+	return 2
+}
+
+// checkBirthDate rejects birth dates that aren't dates, are in the future,
+// or are further back than the oldest age allowed
+func checkBirthDate(birthDate *string, now time.Time) []p.CheckFailure {
+	if birthDate == nil {
+		return nil
+	}
+	born, err := time.Parse(dateLayout, *birthDate)
+	switch {
+	case err != nil:
+		return []p.CheckFailure{{Property: "birthDate", Reason: "birthDate must be a date like 2024-05-17"}}
+	case born.After(now):
+		return []p.CheckFailure{{Property: "birthDate", Reason: "birthDate can't be in the future"}}
+	case born.Before(now.AddDate(-30, 0, 0)):
+		return []p.CheckFailure{{Property: "birthDate", Reason: "birthDate makes the dog older than 30"}}
+	}
+	return nil
+}
+
+func (Dog) Update(ctx context.Context, id string, oldState DogState, input DogArgs, preview bool) (DogState, error) {
+	return dogs.update(ctx, id, oldState, input, preview)
+}
+
+// carryDogState preserves the dynamic state of a dog across an update
+func carryDogState(state *DogState, oldState DogState, now time.Time) {
+	state.Health = oldState.Health
+	state.Happiness = oldState.Happiness
+	state.Energy = oldState.Energy
+	state.Socialization = oldState.Socialization
+	state.LastFed = oldState.LastFed
+	state.LastWalk = oldState.LastWalk
+	state.TotalWalks = oldState.TotalWalks
+	state.TotalTreats = oldState.TotalTreats
+	state.BehaviorNotes = oldState.BehaviorNotes
+	state.MedicalHistory = oldState.MedicalHistory
+	state.BehaviorNoteCount = oldState.BehaviorNoteCount
+	state.MedicalHistoryCount = oldState.MedicalHistoryCount
+
+	// Add update note
+	state.BehaviorNotes = recent(append(state.BehaviorNotes, updateNote(now)))
+}
+
+// updateNote is the behavior note every applied update adds
+func updateNote(now time.Time) BehaviorNote {
+	return observed(now, Owner, Info, fmt.Sprintf("Updated information on %s", now.Format("2006-01-02")))
+}
+
+func (Dog) Read(ctx context.Context, id string, inputs DogArgs, state DogState) (string, DogArgs, DogState, error) {
+	return dogs.read(ctx, id, inputs, state)
+}
+
+// Delete removes the dog and frees its name for the owner's other dogs
+func (Dog) Delete(ctx context.Context, id string, state DogState) error {
+	if err := dogs.delete(ctx, id, state); err != nil {
+		return err
+	}
+	return releaseName(ctx, state)
+}
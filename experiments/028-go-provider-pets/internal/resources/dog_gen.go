@@ -0,0 +1,110 @@
+// Code generated by genstate from dog.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// DogOutputs are computed by the provider; Check rejects them as inputs
+type DogOutputs struct {
+	ID                  string              `pulumi:"id"`
+	RegistrationDate    string              `pulumi:"registrationDate"`
+	AgeYears            int                 `pulumi:"ageYears"`
+	AgeMonths           int                 `pulumi:"ageMonths"`
+	Health              string              `pulumi:"health"`
+	Happiness           int                 `pulumi:"happiness"`
+	Energy              int                 `pulumi:"energy"`
+	Socialization       int                 `pulumi:"socialization"`
+	LastFed             string              `pulumi:"lastFed"`
+	LastWalk            string              `pulumi:"lastWalk"`
+	TotalWalks          int                 `pulumi:"totalWalks"`
+	TotalTreats         int                 `pulumi:"totalTreats"`
+	BehaviorNotes       []BehaviorNote      `pulumi:"behaviorNotes"`
+	MedicalHistory      []string            `pulumi:"medicalHistory"`
+	BehaviorNoteCount   int                 `pulumi:"behaviorNoteCount"`
+	MedicalHistoryCount int                 `pulumi:"medicalHistoryCount"`
+	CurrentWeight       float64             `pulumi:"currentWeight"`
+	OwnershipTransfers  []OwnershipTransfer `pulumi:"ownershipTransfers"`
+	VaccinationCurrent  bool                `pulumi:"vaccinationCurrent"`
+	Version             int64               `pulumi:"version"`
+	ApprovalState
+}
+
+// DogState echoes the inputs next to the computed outputs
+type DogState struct {
+	DogArgs
+	DogOutputs
+}
+
+// newDogState copies the inputs into an otherwise empty state
+func newDogState(input DogArgs) DogState { return DogState{DogArgs: input} }
+
+func (s *DogState) stamp(id, created string)   { s.ID, s.RegistrationDate = id, created }
+func (s *DogState) identity() (string, string) { return s.ID, s.RegistrationDate }
+func (s *DogState) setVersion(version int64)   { s.Version = version }
+func (s *DogState) storedVersion() int64       { return s.Version }
+
+func (args *DogArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Age, "Deprecated: Use birthDate, which keeps the dog's age current; age will be removed in the next release.")
+	a.SetDefault(&args.Age, 2)
+	a.Describe(&args.BirthDate, "YYYY-MM-DD; the dog's age is worked out from it on every refresh, and age is ignored")
+	a.Describe(&args.Color, "Coat color as people would describe it, e.g. black and tan; matchFoundPet compares it")
+	a.SetDefault(&args.IsGoodBoy, true)
+	a.Describe(&args.FavoriteActivity, "Deprecated: Use preferences.activityPreferences, which walks are scored against; favoriteActivity will be removed in the next release.")
+	a.Describe(&args.Preferences, "Favorite foods, toy types and activities")
+	a.SetDefault(&args.Microchipped, false)
+	a.Describe(&args.Vaccinations, "Doses given before the dog joined the registry or outside recorded visits")
+	a.SetDefault(&args.TrainingLevel, TrainingLevel("basic"))
+	a.Describe(&args.Allergies, "Ingredients the dog reacts to; diet transitions to foods containing them are refused")
+}
+
+// deprecatedInputs maps each deprecated input to what to use instead
+func (*DogArgs) deprecatedInputs() map[string]string {
+	return map[string]string{
+		"age":              "Use birthDate, which keeps the dog's age current; age will be removed in the next release.",
+		"favoriteActivity": "Use preferences.activityPreferences, which walks are scored against; favoriteActivity will be removed in the next release.",
+	}
+}
+
+// applyDefaults fills unset optional inputs with their schema defaults
+func (args *DogArgs) applyDefaults() {
+	if args.Age == nil {
+		v := 2
+		args.Age = &v
+	}
+	if args.IsGoodBoy == nil {
+		v := true
+		args.IsGoodBoy = &v
+	}
+	if args.Microchipped == nil {
+		v := false
+		args.Microchipped = &v
+	}
+	if args.TrainingLevel == nil {
+		v := TrainingLevel("basic")
+		args.TrainingLevel = &v
+	}
+}
+
+func (state *DogState) Annotate(a infer.Annotator) {
+	state.DogArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the dog")
+	a.Describe(&state.RegistrationDate, "When the dog joined the registry")
+	a.Describe(&state.AgeYears, "Age in whole years, from birthDate as of the last refresh, else the age input")
+	a.Describe(&state.AgeMonths, "Months of age past ageYears; 0 without a birthDate")
+	a.Describe(&state.Health, "Current health assessment")
+	a.Describe(&state.Happiness, "Happiness score out of 100, recomputed on refresh from time since the last walk and meal")
+	a.Describe(&state.Energy, "Energy score out of 100, recomputed on refresh from recent walks and dog park visits")
+	a.Describe(&state.Socialization, "Socialization score out of 100, recomputed on refresh from time at dog parks over the last few weeks")
+	a.Describe(&state.LastFed, "When a SmartFeeder of the dog last reported dispensing, as of the last refresh; empty until one has")
+	a.Describe(&state.LastWalk, "When the dog's latest DogWalk was, as of the last refresh; empty until it has one")
+	a.Describe(&state.TotalWalks, "Walks recorded for the dog, as DogWalks or through recordWalk")
+	a.Describe(&state.TotalTreats, "Treats given to the dog through giveTreat")
+	a.Describe(&state.BehaviorNotes, "The most recent observations about the dog's behavior")
+	a.Describe(&state.MedicalHistory, "The most recent notes from health checks and visits")
+	a.Describe(&state.BehaviorNoteCount, "Behavior notes recorded in total; getFullHistory returns them all")
+	a.Describe(&state.MedicalHistoryCount, "Medical history entries recorded in total; getFullHistory returns them all")
+	a.Describe(&state.CurrentWeight, "Weight in pounds from the dog's latest WeightLog, or the weight input until it has one")
+	a.Describe(&state.OwnershipTransfers, "Previous changes of owner, oldest first")
+	a.Describe(&state.VaccinationCurrent, "Whether the latest dose of every recorded vaccine, including those given at visits, is unexpired")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
@@ -0,0 +1,273 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// An owner's dogs each need a name of their own, unless uniqueDogNames is
+// turned off. Names are compared the way people read them, so "Rex", "rex"
+// and " Rex " are the same name, and owners the way PetDoor households are.
+const (
+	// maxDogName is the longest name DogArgs accepts
+	maxDogName = 64
+	// nameAlternates is how many free names a taken one suggests
+	nameAlternates = 3
+)
+
+// nameSuffixes tell a dog apart from an older one of the same name, tried
+// in order before falling back to numbers
+var nameSuffixes = []string{"II", "Jr", "III"}
+
+// ErrNameConflict is returned when a dog would share its name with another
+// of its owner's dogs
+var ErrNameConflict = errors.New("name conflict")
+
+// NameCheck is a name an owner wants to give a dog
+type NameCheck struct {
+	OwnerName string `pulumi:"ownerName"`
+	PetName   string `pulumi:"petName"`
+}
+
+// NameAvailability says whether an owner can give a dog the name
+type NameAvailability struct {
+	Available  bool     `pulumi:"available"`
+	TakenBy    *string  `pulumi:"takenBy,optional"` // ID of the owner's dog that already has the name
+	Alternates []string `pulumi:"alternates"`       // Free names close to the one asked for; empty when it is available
+	Enforced   bool     `pulumi:"enforced"`         // Whether Dog refuses a taken name; false when uniqueDogNames is off
+}
+
+// CheckNameAvailability looks up the owner's registered dogs to tell
+// whether the name is free, and suggests free alternates when it isn't
+func CheckNameAvailability(ctx context.Context, args NameCheck) (NameAvailability, error) {
+	switch name := strings.TrimSpace(args.PetName); {
+	case strings.TrimSpace(args.OwnerName) == "":
+		return NameAvailability{}, errors.New("ownerName is required")
+	case name == "":
+		return NameAvailability{}, errors.New("petName is required")
+	case len(name) > maxDogName:
+		return NameAvailability{}, fmt.Errorf("petName must be at most %d characters", maxDogName)
+	}
+	owned, err := ownerDogs(ctx, args.OwnerName)
+	if err != nil {
+		return NameAvailability{}, err
+	}
+	availability := nameAvailability(owned, args.PetName)
+	availability.Enforced = registry.UniqueDogNames(ctx)
+	return availability, nil
+}
+
+// nameClaim holds a name for one of an owner's dogs. Claims are written
+// with the store's version check, so of two dogs registered at once only
+// one gets the name.
+type nameClaim struct {
+	DogID string `json:"dogId"`
+	URN   string `json:"urn,omitempty"` // resource that registered the dog; a replacement of it inherits the name
+}
+
+// claimName refuses the dog's name when another of its owner's dogs
+// already has it, and otherwise holds the name for the dog
+func claimName(ctx context.Context, dog DogState) error {
+	if !registry.UniqueDogNames(ctx) {
+		return nil
+	}
+	rec, err := nameClaimRecord(ctx, dog)
+	if err != nil {
+		return err
+	}
+	store, err := registry.Store(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(rec)
+	if errors.Is(err, backend.ErrConflict) {
+		return fmt.Errorf("%w: %s is registering another dog named %s right now", ErrNameConflict, dog.OwnerName, strings.TrimSpace(dog.Name))
+	}
+	return err
+}
+
+// nameClaimRecord is the claim to write for the dog's name, at the version
+// the write has to find. A name is free when nobody claimed it, the claim
+// is the dog's own or the resource's it replaces or retries, or the
+// claiming dog has been renamed since. A claim whose dog isn't registered
+// yet still counts: the dog may be on its way, and a Create that fails
+// gives its claim back.
+func nameClaimRecord(ctx context.Context, dog DogState) (backend.Record, error) {
+	rec := backend.Record{Kind: "dog-name", ID: nameClaimID(dog.OwnerName, dog.Name)}
+	payload, err := json.Marshal(nameClaim{DogID: dog.ID, URN: registry.URN(ctx)})
+	if err != nil {
+		return rec, err
+	}
+	rec.Payload = payload
+
+	var held nameClaim
+	version, err := registry.Load(ctx, rec.Kind, rec.ID, &held)
+	if errors.Is(err, backend.ErrNotFound) {
+		return rec, nil
+	}
+	if err != nil {
+		return rec, err
+	}
+	rec.Version = version
+	urn := registry.URN(ctx)
+	if held.DogID == dog.ID || (urn != "" && held.URN == urn) {
+		return rec, nil
+	}
+	var holder DogState
+	_, err = registry.Load(ctx, "dog", held.DogID, &holder)
+	if err == nil && nameClaimID(holder.OwnerName, holder.Name) != rec.ID {
+		return rec, nil
+	}
+	if err != nil && !errors.Is(err, backend.ErrNotFound) {
+		return rec, err
+	}
+	owned, err := ownerDogs(ctx, dog.OwnerName)
+	if err != nil {
+		return rec, err
+	}
+	availability := nameAvailability(otherDogs(owned, dog.ID), dog.Name)
+	availability.TakenBy = &held.DogID
+	return rec, fmt.Errorf("%w: %s", ErrNameConflict, nameTaken(dog.OwnerName, dog.Name, availability))
+}
+
+// releaseName gives up the dog's claim on its name, unless another dog
+// holds it by now
+func releaseName(ctx context.Context, dog DogState) error {
+	id := nameClaimID(dog.OwnerName, dog.Name)
+	var held nameClaim
+	version, err := registry.Load(ctx, "dog-name", id, &held)
+	if errors.Is(err, backend.ErrNotFound) {
+		return nil
+	}
+	if err != nil || held.DogID != dog.ID {
+		return err
+	}
+	if err := registry.Delete(ctx, "dog-name", id, version); !errors.Is(err, backend.ErrConflict) {
+		return err
+	}
+	// Claimed by another dog since
+	return nil
+}
+
+// nameClaimID is the claim record of an owner's name for a dog
+func nameClaimID(owner, name string) string {
+	return strings.ToLower(strings.TrimSpace(owner)) + "/" + dogNameKey(name)
+}
+
+// warnNameTaken warns during Check that a new or renamed dog's name is
+// taken. Check can run before the registry exists, so a name it can't look
+// up is left for Create.
+func warnNameTaken(ctx context.Context, oldInputs resource.PropertyMap, args DogArgs) {
+	_, existing := oldInputs["name"]
+	if existing && !renamed(inputString(oldInputs, "name"), inputString(oldInputs, "ownerName"), args.Name, args.OwnerName) {
+		return
+	}
+	if args.Name == "" || args.OwnerName == "" || !registry.UniqueDogNames(ctx) {
+		return
+	}
+	owned, err := ownerDogs(ctx, args.OwnerName)
+	if err != nil {
+		return
+	}
+	// A renamed dog's own record still has its old name, so any dog with
+	// the new one is another dog
+	if availability := nameAvailability(owned, args.Name); !availability.Available {
+		p.GetLogger(ctx).Warningf("%s; registering the dog will fail with a name conflict", nameTaken(args.OwnerName, args.Name, availability))
+	}
+}
+
+// nameTaken says which dog has the name, and what to call this one instead
+func nameTaken(owner, name string, availability NameAvailability) string {
+	msg := fmt.Sprintf("%s already has a dog named %s (%s)", owner, strings.TrimSpace(name), *availability.TakenBy)
+	if len(availability.Alternates) > 0 {
+		msg += "; try " + strings.Join(availability.Alternates, ", ")
+	}
+	return msg
+}
+
+// renamed reports whether a dog's name or owner changed enough for the
+// uniqueness rule to look at it again
+func renamed(oldName, oldOwner, name, owner string) bool {
+	return dogNameKey(oldName) != dogNameKey(name) || !sameHousehold(oldOwner, owner)
+}
+
+// otherDogs are the dogs other than the one with the ID
+func otherDogs(dogs []DogState, id string) []DogState {
+	var out []DogState
+	for _, dog := range dogs {
+		if dog.ID != id {
+			out = append(out, dog)
+		}
+	}
+	return out
+}
+
+// inputString is a string input from a property map, or empty
+func inputString(inputs resource.PropertyMap, key resource.PropertyKey) string {
+	if v, ok := inputs[key]; ok && v.IsString() {
+		return v.StringValue()
+	}
+	return ""
+}
+
+// ownerDogs are the registered dogs of the owner's household
+func ownerDogs(ctx context.Context, owner string) ([]DogState, error) {
+	all, err := listRecords[DogState](ctx, backend.Query{Kind: "dog"})
+	if err != nil {
+		return nil, err
+	}
+	var owned []DogState
+	for _, dog := range all {
+		if sameHousehold(dog.OwnerName, owner) {
+			owned = append(owned, dog)
+		}
+	}
+	return owned, nil
+}
+
+// nameAvailability is whether name is free among the owner's dogs
+func nameAvailability(owned []DogState, name string) NameAvailability {
+	taken := map[string]string{}
+	for _, dog := range owned {
+		taken[dogNameKey(dog.Name)] = dog.ID
+	}
+	id, ok := taken[dogNameKey(name)]
+	if !ok {
+		return NameAvailability{Available: true, Alternates: []string{}}
+	}
+	return NameAvailability{TakenBy: &id, Alternates: nameAlternatives(strings.TrimSpace(name), taken)}
+}
+
+// nameAlternatives are free names made from name with a suffix, the
+// familiar ones first and then numbers
+func nameAlternatives(name string, taken map[string]string) []string {
+	out := []string{}
+	try := func(candidate string) {
+		if _, ok := taken[dogNameKey(candidate)]; !ok && len(candidate) <= maxDogName {
+			out = append(out, candidate)
+		}
+	}
+	for _, suffix := range nameSuffixes {
+		try(name + " " + suffix)
+	}
+	// The owner can't have taken every number; give up only if the name is
+	// too long to take a suffix at all
+	for n := 2; len(out) < nameAlternates && len(name)+4 <= maxDogName; n++ {
+		try(fmt.Sprintf("%s %d", name, n))
+	}
+	return out[:min(len(out), nameAlternates)]
+}
+
+// dogNameKey is the name as the uniqueness rule compares it
+func dogNameKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
@@ -0,0 +1,127 @@
+package resources
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+func TestNameAvailability(t *testing.T) {
+	dog := func(id, name string) DogState {
+		var d DogState
+		d.ID, d.Name = id, name
+		return d
+	}
+	owned := []DogState{dog("dog-rex-1", "Rex"), dog("dog-rex-ii-2", "Rex II"), dog("dog-bella-3", "Bella  Rose")}
+	tests := []struct {
+		name       string
+		petName    string
+		available  bool
+		takenBy    string
+		alternates []string
+	}{
+		{name: "free", petName: "Max", available: true, alternates: []string{}},
+		{name: "taken", petName: "Rex", takenBy: "dog-rex-1", alternates: []string{"Rex Jr", "Rex III", "Rex 2"}},
+		{name: "case and spacing", petName: " bella rose ", takenBy: "dog-bella-3", alternates: []string{"bella rose II", "bella rose Jr", "bella rose III"}},
+		{name: "suffix already taken", petName: "rex ii", takenBy: "dog-rex-ii-2", alternates: []string{"rex ii II", "rex ii Jr", "rex ii III"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nameAvailability(owned, tt.petName)
+			if got.Available != tt.available || (got.TakenBy == nil) != (tt.takenBy == "") || (got.TakenBy != nil && *got.TakenBy != tt.takenBy) {
+				t.Errorf("nameAvailability(%q) = %+v, want available %v taken by %q", tt.petName, got, tt.available, tt.takenBy)
+			}
+			if !reflect.DeepEqual(got.Alternates, tt.alternates) {
+				t.Errorf("alternates = %q, want %q", got.Alternates, tt.alternates)
+			}
+		})
+	}
+}
+
+func TestNameAlternativesOfALongName(t *testing.T) {
+	name := strings.Repeat("x", 61)
+	taken := map[string]string{dogNameKey(name): "dog-x"}
+	if got := nameAlternatives(name, taken); !reflect.DeepEqual(got, []string{name + " II", name + " Jr"}) {
+		t.Errorf("nameAlternatives = %q, want only the suffixes that fit", got)
+	}
+}
+
+func TestRenamed(t *testing.T) {
+	tests := []struct {
+		name              string
+		oldName, oldOwner string
+		newName, newOwner string
+		renamed           bool
+	}{
+		{name: "unchanged", oldName: "Rex", oldOwner: "Alice", newName: "Rex", newOwner: "Alice"},
+		{name: "recased", oldName: "Rex", oldOwner: "Alice", newName: " rex", newOwner: "alice "},
+		{name: "new name", oldName: "Rex", oldOwner: "Alice", newName: "Max", newOwner: "Alice", renamed: true},
+		{name: "new owner", oldName: "Rex", oldOwner: "Alice", newName: "Rex", newOwner: "Bob", renamed: true},
+		{name: "new dog", newName: "Rex", newOwner: "Alice", renamed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renamed(tt.oldName, tt.oldOwner, tt.newName, tt.newOwner); got != tt.renamed {
+				t.Errorf("renamed = %v, want %v", got, tt.renamed)
+			}
+			// A renamed dog needs a claim of its own, and only then
+			if moved := nameClaimID(tt.oldOwner, tt.oldName) != nameClaimID(tt.newOwner, tt.newName); moved != tt.renamed {
+				t.Errorf("claim moved = %v, want %v", moved, tt.renamed)
+			}
+		})
+	}
+}
+
+func TestNameTaken(t *testing.T) {
+	var rex DogState
+	rex.ID, rex.Name = "dog-rex-1", "Rex"
+	availability := nameAvailability([]DogState{rex}, "rex ")
+	want := "Alice already has a dog named rex (dog-rex-1); try rex II, rex Jr, rex III"
+	if got := nameTaken("Alice", "rex ", availability); got != want {
+		t.Errorf("nameTaken = %q, want %q", got, want)
+	}
+	if got := nameAvailability(otherDogs([]DogState{rex}, rex.ID), "Rex"); !got.Available {
+		t.Errorf("a dog's own name counts against it: %+v", got)
+	}
+}
+
+func TestClaimName(t *testing.T) {
+	deterministic := true
+	ctx := configured(t, registry.Config{Deterministic: &deterministic})
+	create := func(name, petName string) (string, DogState, error) {
+		return Dog{}.Create(ctx, name, DogArgs{Name: petName, Breed: Bulldog, OwnerName: "Ann"}, false)
+	}
+
+	id, rex, err := create("rex", "Rex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := create("rex-again", " rex "); !errors.Is(err, ErrNameConflict) {
+		t.Fatalf("second Rex: err = %v, want a name conflict", err)
+	}
+
+	// A dog whose record can't be saved gives its name back
+	max := DogArgs{Name: "Max", Breed: Bulldog, OwnerName: "Ann"}
+	taken := dogs.stamped(max, registry.Now(ctx), registry.IDSuffix(ctx, "max", 0))
+	if _, err := registry.Save(ctx, "dog", taken.ID, 0, DogState{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := (Dog{}).Create(ctx, "max", max, false); err == nil {
+		t.Fatal("Create over an existing record succeeded")
+	}
+	var held nameClaim
+	if _, err := registry.Load(ctx, "dog-name", nameClaimID("Ann", "Max"), &held); !errors.Is(err, backend.ErrNotFound) {
+		t.Errorf("failed Create left a claim for %+v behind (err = %v)", held, err)
+	}
+
+	if err := (Dog{}).Delete(ctx, id, rex); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := create("rex-again", "Rex"); err != nil {
+		t.Errorf("Rex after the first was deleted: %v", err)
+	}
+}
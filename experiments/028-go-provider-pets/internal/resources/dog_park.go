@@ -0,0 +1,118 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// DogPark Resource - a park dogs play off the leash in. A park may only let
+// in dogs of some sizes, such as a small-dog run; DogParkVisits of dogs of
+// another size are refused.
+type DogPark struct{}
+
+//pets:state id=ID created=CreatedAt
+//pets:output ID string id Generated identifier of the park
+//pets:output CreatedAt string createdAt When the park was added
+//pets:output VisitCount int visitCount DogParkVisits recorded at the park; recomputed on refresh
+type DogParkArgs struct {
+	Name             string    `pulumi:"name" validate:"required,max=64"`
+	Location         LatLng    `pulumi:"location"`
+	Fenced           bool      `pulumi:"fenced"`                    // Whether the off-leash area is fenced in
+	SizeRestrictions []PetSize `pulumi:"sizeRestrictions,optional"` // Sizes of dog let in; any size when empty
+}
+
+var dogParks = crudResource[DogParkArgs, DogParkState, *DogParkState]{
+	kind:     stored[DogParkState]("dog-park"),
+	prefix:   "park",
+	slug:     func(input DogParkArgs) string { return input.Name },
+	newState: newDogParkState,
+	carry: func(ctx context.Context, state *DogParkState, oldState DogParkState, now time.Time) error {
+		visited, err := parkVisits(ctx, state.ID)
+		state.VisitCount = len(visited)
+		return err
+	},
+	refresh: func(ctx context.Context, id string, state *DogParkState) error {
+		visited, err := parkVisits(ctx, id)
+		state.VisitCount = len(visited)
+		return err
+	},
+}
+
+func (DogPark) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (DogParkArgs, []p.CheckFailure, error) {
+	args, failures, err := dogParks.check(newInputs)
+	if reason := checkLatLng(args.Location); reason != "" {
+		failures = append(failures, p.CheckFailure{Property: "location", Reason: reason})
+	}
+	for i, size := range args.SizeRestrictions {
+		if _, ok := sizeRank[size]; !ok {
+			failures = append(failures, p.CheckFailure{Property: fmt.Sprintf("sizeRestrictions[%d]", i), Reason: "size must be one of small, medium, large or extra-large"})
+		}
+	}
+	return args, failures, err
+}
+
+func (DogPark) Diff(ctx context.Context, id string, olds DogParkState, news DogParkArgs) (p.DiffResponse, error) {
+	// Moving the park makes it another park; the rest can change in place
+	diff := map[string]p.PropertyDiff{}
+	if !reflect.DeepEqual(olds.Location, news.Location) {
+		diff["location"] = p.PropertyDiff{Kind: p.UpdateReplace, InputDiff: true}
+	}
+	if olds.Name != news.Name {
+		diff["name"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	if olds.Fenced != news.Fenced {
+		diff["fenced"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	if !reflect.DeepEqual(olds.SizeRestrictions, news.SizeRestrictions) {
+		diff["sizeRestrictions"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	return p.DiffResponse{HasChanges: len(diff) > 0, DetailedDiff: diff}, nil
+}
+
+func (DogPark) Create(ctx context.Context, name string, input DogParkArgs, preview bool) (string, DogParkState, error) {
+	return dogParks.create(ctx, name, input, preview)
+}
+
+func (DogPark) Read(ctx context.Context, id string, inputs DogParkArgs, state DogParkState) (string, DogParkArgs, DogParkState, error) {
+	return dogParks.read(ctx, id, inputs, state)
+}
+
+func (DogPark) Update(ctx context.Context, id string, oldState DogParkState, input DogParkArgs, preview bool) (DogParkState, error) {
+	return dogParks.update(ctx, id, oldState, input, preview)
+}
+
+func (DogPark) Delete(ctx context.Context, id string, state DogParkState) error {
+	return dogParks.delete(ctx, id, state)
+}
+
+// loadPark loads the park a visit is at
+func loadPark(ctx context.Context, id string) (DogParkState, error) {
+	var park DogParkState
+	_, err := registry.Load(ctx, "dog-park", id, &park)
+	if errors.Is(err, backend.ErrNotFound) {
+		return park, fmt.Errorf("parkId %q is not a registered dog park: %w", id, err)
+	}
+	return park, err
+}
+
+// parkVisits are the visits recorded at a park
+func parkVisits(ctx context.Context, parkID string) ([]DogParkVisitState, error) {
+	return listRecords[DogParkVisitState](ctx, backend.Query{
+		Kind:  "park-visit",
+		Where: []backend.Condition{{Field: "ParkID", Op: "eq", Value: parkID}},
+	})
+}
+
+// letsIn reports whether the park lets in a dog of the given size
+func (park DogParkState) letsIn(size PetSize) bool {
+	return len(park.SizeRestrictions) == 0 || hasSize(park.SizeRestrictions, size)
+}
@@ -0,0 +1,40 @@
+// Code generated by genstate from dog_park.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// DogParkOutputs are computed by the provider; Check rejects them as inputs
+type DogParkOutputs struct {
+	ID         string `pulumi:"id"`
+	CreatedAt  string `pulumi:"createdAt"`
+	VisitCount int    `pulumi:"visitCount"`
+	Version    int64  `pulumi:"version"`
+}
+
+// DogParkState echoes the inputs next to the computed outputs
+type DogParkState struct {
+	DogParkArgs
+	DogParkOutputs
+}
+
+// newDogParkState copies the inputs into an otherwise empty state
+func newDogParkState(input DogParkArgs) DogParkState { return DogParkState{DogParkArgs: input} }
+
+func (s *DogParkState) stamp(id, created string)   { s.ID, s.CreatedAt = id, created }
+func (s *DogParkState) identity() (string, string) { return s.ID, s.CreatedAt }
+func (s *DogParkState) setVersion(version int64)   { s.Version = version }
+func (s *DogParkState) storedVersion() int64       { return s.Version }
+
+func (args *DogParkArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Fenced, "Whether the off-leash area is fenced in")
+	a.Describe(&args.SizeRestrictions, "Sizes of dog let in; any size when empty")
+}
+
+func (state *DogParkState) Annotate(a infer.Annotator) {
+	state.DogParkArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the park")
+	a.Describe(&state.CreatedAt, "When the park was added")
+	a.Describe(&state.VisitCount, "DogParkVisits recorded at the park; recomputed on refresh")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
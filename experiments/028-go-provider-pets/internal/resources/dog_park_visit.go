@@ -0,0 +1,124 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// DogParkVisit Resource - a dog's time off the leash at a DogPark. The dog
+// must be of a size the park lets in. Its Dog counts the visit on refresh:
+// play tires a dog more than walking for as long, so the visit takes more
+// off its energy than a walk would, and recent visits make up the dog's
+// socialization score.
+type DogParkVisit struct{}
+
+//pets:state id=ID created=Date
+//pets:output ID string id Generated identifier of the visit
+//pets:output Date string date When the visit was recorded
+//pets:output ParkName string parkName Name of the park at the time of the visit
+//pets:output DogSize string dogSize The dog's size the park's restrictions were checked against
+type DogParkVisitArgs struct {
+	DogID    string `pulumi:"dogId" validate:"required"`
+	ParkID   string `pulumi:"parkId" validate:"required"`
+	Duration int    `pulumi:"duration" validate:"gt=0,max=480"` // Time at the park in minutes
+}
+
+const (
+	// parkPlayEffort is how many minutes of walking a minute of off-leash
+	// play tires a dog as much as
+	parkPlayEffort = 1.5
+	// socializationHalfLifeHours is how long a park visit takes to count
+	// for half as much towards socialization
+	socializationHalfLifeHours = 7 * 24
+	// socializationMinutes is the recent park time that makes a dog half
+	// socialized; every further stretch of it halves what is left to 100
+	socializationMinutes = 60
+)
+
+var parkVisitRecords = crudResource[DogParkVisitArgs, DogParkVisitState, *DogParkVisitState]{
+	kind:     stored[DogParkVisitState]("park-visit"),
+	prefix:   "parkvisit",
+	slug:     func(input DogParkVisitArgs) string { return input.DogID },
+	newState: newDogParkVisitState,
+	populate: func(ctx context.Context, state *DogParkVisitState, input DogParkVisitArgs) error {
+		dog, err := walkedDog(ctx, input.DogID)
+		if err != nil {
+			return err
+		}
+		park, err := loadPark(ctx, input.ParkID)
+		if err != nil {
+			return err
+		}
+		size := determineSizeByBreed(dog.Breed)
+		if dog.Size != nil {
+			size = *dog.Size
+		}
+		if !park.letsIn(size) {
+			return fmt.Errorf("%s only lets in %s dogs, and %s is %s", park.Name, sizeList(park.SizeRestrictions), dog.Name, size)
+		}
+		state.ParkName, state.DogSize = park.Name, string(size)
+		return nil
+	},
+}
+
+func (DogParkVisit) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (DogParkVisitArgs, []p.CheckFailure, error) {
+	return parkVisitRecords.check(newInputs)
+}
+
+func (DogParkVisit) Create(ctx context.Context, name string, input DogParkVisitArgs, preview bool) (string, DogParkVisitState, error) {
+	return parkVisitRecords.create(ctx, name, input, preview)
+}
+
+func (DogParkVisit) Read(ctx context.Context, id string, inputs DogParkVisitArgs, state DogParkVisitState) (string, DogParkVisitArgs, DogParkVisitState, error) {
+	return parkVisitRecords.read(ctx, id, inputs, state)
+}
+
+func (DogParkVisit) Delete(ctx context.Context, id string, state DogParkVisitState) error {
+	return parkVisitRecords.delete(ctx, id, state)
+}
+
+// sizeList joins sizes for a message, e.g. "small or medium"
+func sizeList(sizes []PetSize) string {
+	list := ""
+	for i, size := range sizes {
+		switch {
+		case i == 0:
+		case i == len(sizes)-1:
+			list += " or "
+		default:
+			list += ", "
+		}
+		list += string(size)
+	}
+	return list
+}
+
+// parkPlayAsWalks presents park visits as the walks that would tire a dog
+// as much, for the energy the mood is worked out from
+func parkPlayAsWalks(visits []DogParkVisitState) []DogWalkState {
+	walks := make([]DogWalkState, len(visits))
+	for i, visit := range visits {
+		walks[i].Date = visit.Date
+		walks[i].Duration = int(math.Round(float64(visit.Duration) * parkPlayEffort))
+	}
+	return walks
+}
+
+// socialization scores out of 100 how much time the dog has spent with
+// other dogs at parks lately, as of now
+func socialization(visits []DogParkVisitState, now time.Time) int {
+	minutes := 0.0
+	for _, visit := range visits {
+		at, ok := parseStamp(visit.Date)
+		if !ok || at.After(now) {
+			continue
+		}
+		minutes += float64(visit.Duration) * halve(now.Sub(at).Hours(), socializationHalfLifeHours)
+	}
+	return clampScore(100 * (1 - halve(minutes, socializationMinutes)))
+}
@@ -0,0 +1,43 @@
+// Code generated by genstate from dog_park_visit.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// DogParkVisitOutputs are computed by the provider; Check rejects them as inputs
+type DogParkVisitOutputs struct {
+	ID       string `pulumi:"id"`
+	Date     string `pulumi:"date"`
+	ParkName string `pulumi:"parkName"`
+	DogSize  string `pulumi:"dogSize"`
+	Version  int64  `pulumi:"version"`
+}
+
+// DogParkVisitState echoes the inputs next to the computed outputs
+type DogParkVisitState struct {
+	DogParkVisitArgs
+	DogParkVisitOutputs
+}
+
+// newDogParkVisitState copies the inputs into an otherwise empty state
+func newDogParkVisitState(input DogParkVisitArgs) DogParkVisitState {
+	return DogParkVisitState{DogParkVisitArgs: input}
+}
+
+func (s *DogParkVisitState) stamp(id, created string)   { s.ID, s.Date = id, created }
+func (s *DogParkVisitState) identity() (string, string) { return s.ID, s.Date }
+func (s *DogParkVisitState) setVersion(version int64)   { s.Version = version }
+func (s *DogParkVisitState) storedVersion() int64       { return s.Version }
+
+func (args *DogParkVisitArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Duration, "Time at the park in minutes")
+}
+
+func (state *DogParkVisitState) Annotate(a infer.Annotator) {
+	state.DogParkVisitArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the visit")
+	a.Describe(&state.Date, "When the visit was recorded")
+	a.Describe(&state.ParkName, "Name of the park at the time of the visit")
+	a.Describe(&state.DogSize, "The dog's size the park's restrictions were checked against")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
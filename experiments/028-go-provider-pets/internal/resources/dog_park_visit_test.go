@@ -0,0 +1,81 @@
+package resources
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParkLetsIn(t *testing.T) {
+	tests := []struct {
+		name  string
+		sizes []PetSize
+		size  PetSize
+		want  bool
+	}{
+		{name: "any size", size: ExtraLarge, want: true},
+		{name: "small dog run", sizes: []PetSize{Small}, size: Small, want: true},
+		{name: "too big for it", sizes: []PetSize{Small}, size: Large},
+		{name: "big dogs", sizes: []PetSize{Large, ExtraLarge}, size: ExtraLarge, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var park DogParkState
+			park.SizeRestrictions = tt.sizes
+			if got := park.letsIn(tt.size); got != tt.want {
+				t.Errorf("letsIn(%s) = %v, want %v", tt.size, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSizeList(t *testing.T) {
+	tests := []struct {
+		sizes []PetSize
+		want  string
+	}{
+		{sizes: []PetSize{Small}, want: "small"},
+		{sizes: []PetSize{Small, Medium}, want: "small or medium"},
+		{sizes: []PetSize{Small, Medium, Large}, want: "small, medium or large"},
+	}
+	for _, tt := range tests {
+		if got := sizeList(tt.sizes); got != tt.want {
+			t.Errorf("sizeList(%v) = %q, want %q", tt.sizes, got, tt.want)
+		}
+	}
+}
+
+func TestSocialization(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	visit := func(ago time.Duration, minutes int) DogParkVisitState {
+		var v DogParkVisitState
+		v.Date, v.Duration = now.Add(-ago).Format("2006-01-02T15:04:05Z"), minutes
+		return v
+	}
+	tests := []struct {
+		name   string
+		visits []DogParkVisitState
+		want   int
+	}{
+		{name: "never been", want: 0},
+		{name: "an hour today", visits: []DogParkVisitState{visit(0, 60)}, want: 50},
+		{name: "two hours today", visits: []DogParkVisitState{visit(0, 60), visit(0, 60)}, want: 75},
+		{name: "an hour last week", visits: []DogParkVisitState{visit(7*24*time.Hour, 60)}, want: 29},
+		{name: "not yet", visits: []DogParkVisitState{visit(-time.Hour, 60)}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := socialization(tt.visits, now); got != tt.want {
+				t.Errorf("socialization = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParkPlayAsWalks(t *testing.T) {
+	var visit DogParkVisitState
+	visit.Date, visit.Duration = "2026-10-16T09:00:00Z", 45
+	walks := parkPlayAsWalks([]DogParkVisitState{visit})
+	if len(walks) != 1 || walks[0].Date != visit.Date || walks[0].Duration != 68 {
+		t.Errorf("parkPlayAsWalks = %+v, want one 68-minute walk at %s", walks, visit.Date)
+	}
+}
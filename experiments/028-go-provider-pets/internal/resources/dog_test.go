@@ -0,0 +1,230 @@
+package resources
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func intPtr(v int) *int           { return &v }
+func floatPtr(v float64) *float64 { return &v }
+func boolPtr(v bool) *bool        { return &v }
+func stringPtr(v string) *string  { return &v }
+func sizePtr(v PetSize) *PetSize  { return &v }
+
+func TestRegisteredDogDefaults(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      DogArgs
+		wantAge    int
+		wantSize   PetSize
+		wantWeight float64
+		wantChip   bool
+	}{
+		{
+			name:       "beagle defaults",
+			input:      DogArgs{Name: "Snoopy", Breed: Beagle, OwnerName: "Charlie"},
+			wantAge:    2,
+			wantSize:   Medium,
+			wantWeight: 25,
+		},
+		{
+			name:       "rottweiler defaults",
+			input:      DogArgs{Name: "Rex", Breed: Rottweiler, OwnerName: "Sam"},
+			wantAge:    2,
+			wantSize:   Large,
+			wantWeight: 95,
+		},
+		{
+			name:       "unknown breed falls back",
+			input:      DogArgs{Name: "Mutt", Breed: "mixed", OwnerName: "Alex"},
+			wantAge:    2,
+			wantSize:   Medium,
+			wantWeight: 50,
+		},
+		{
+			name: "explicit values win",
+			input: DogArgs{
+				Name:         "Max",
+				Breed:        Husky,
+				OwnerName:    "Jo",
+				Age:          intPtr(7),
+				Size:         sizePtr(ExtraLarge),
+				Weight:       floatPtr(80),
+				Microchipped: boolPtr(true),
+			},
+			wantAge:    7,
+			wantSize:   ExtraLarge,
+			wantWeight: 80,
+			wantChip:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := registeredDog(tt.input, time.Now(), "1")
+
+			if *state.Age != tt.wantAge {
+				t.Errorf("age = %d, want %d", *state.Age, tt.wantAge)
+			}
+			if *state.Size != tt.wantSize {
+				t.Errorf("size = %q, want %q", *state.Size, tt.wantSize)
+			}
+			if *state.Weight != tt.wantWeight {
+				t.Errorf("weight = %v, want %v", *state.Weight, tt.wantWeight)
+			}
+			if *state.Microchipped != tt.wantChip {
+				t.Errorf("microchipped = %v, want %v", *state.Microchipped, tt.wantChip)
+			}
+			if !*state.IsGoodBoy {
+				t.Error("every dog is a good boy")
+			}
+			if *state.TrainingLevel != Basic {
+				t.Errorf("training level = %q, want %q", *state.TrainingLevel, Basic)
+			}
+			if state.VaccinationCurrent {
+				t.Error("a dog with no recorded vaccinations is reported current")
+			}
+			wantPrefix := "dog-" + strings.ToLower(strings.ReplaceAll(tt.input.Name, " ", "-")) + "-"
+			if !strings.HasPrefix(state.ID, wantPrefix) {
+				t.Errorf("id = %q, want prefix %q", state.ID, wantPrefix)
+			}
+			if state.Health != "excellent" || state.Happiness != 95 || state.Energy != 80 {
+				t.Errorf("dynamic state = %q/%d/%d, want excellent/95/80", state.Health, state.Happiness, state.Energy)
+			}
+		})
+	}
+}
+
+func TestRegisteredDogSlugsName(t *testing.T) {
+	state := registeredDog(DogArgs{Name: "Sir Barks A Lot", Breed: Poodle}, time.Now(), "1")
+	if !strings.HasPrefix(state.ID, "dog-sir-barks-a-lot-") {
+		t.Errorf("id = %q, want a slug of the name", state.ID)
+	}
+}
+
+func TestDogUpdatePreview(t *testing.T) {
+	old := DogState{DogOutputs: DogOutputs{
+		ID:               "dog-rex-1",
+		RegistrationDate: "2024-01-01T00:00:00Z",
+		Happiness:        40,
+		ApprovalState:    ApprovalState{ApprovalStatus: "approved"},
+	}}
+	input := DogArgs{Name: "Rex", Breed: Rottweiler, OwnerName: "Sam", FavoriteActivity: stringPtr("fetch")}
+
+	state, err := Dog{}.Update(context.Background(), old.ID, old, input, true)
+	if err != nil {
+		t.Fatalf("preview update: %v", err)
+	}
+	if state.ID != old.ID || state.RegistrationDate != old.RegistrationDate {
+		t.Errorf("identity = %q/%q, want %q/%q", state.ID, state.RegistrationDate, old.ID, old.RegistrationDate)
+	}
+	if state.ApprovalStatus != "approved" {
+		t.Errorf("approval status = %q, want approved", state.ApprovalStatus)
+	}
+	if *state.FavoriteActivity != "fetch" {
+		t.Errorf("favorite activity = %q, want the new input", *state.FavoriteActivity)
+	}
+}
+
+func TestCarryDogState(t *testing.T) {
+	now := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		oldNotes  []BehaviorNote
+		wantNotes []string
+	}{
+		{
+			name:      "no earlier notes",
+			wantNotes: []string{"Updated information on 2024-03-05"},
+		},
+		{
+			name:      "appends to earlier notes",
+			oldNotes:  []BehaviorNote{{Author: Owner, Severity: Info, Note: "Loves the park"}},
+			wantNotes: []string{"Loves the park", "Updated information on 2024-03-05"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := DogState{DogOutputs: DogOutputs{
+				Health:        "good",
+				Happiness:     70,
+				Energy:        60,
+				TotalWalks:    12,
+				TotalTreats:   30,
+				BehaviorNotes: tt.oldNotes,
+			}}
+			var state DogState
+			carryDogState(&state, old, now)
+
+			if state.Health != "good" || state.Happiness != 70 || state.Energy != 60 {
+				t.Errorf("dynamic state = %q/%d/%d, want good/70/60", state.Health, state.Happiness, state.Energy)
+			}
+			if state.TotalWalks != 12 || state.TotalTreats != 30 {
+				t.Errorf("totals = %d/%d, want 12/30", state.TotalWalks, state.TotalTreats)
+			}
+			var got []string
+			for _, note := range state.BehaviorNotes {
+				got = append(got, note.Note)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.wantNotes, "|") {
+				t.Errorf("behavior notes = %q, want %q", got, tt.wantNotes)
+			}
+			if last := state.BehaviorNotes[len(state.BehaviorNotes)-1]; last.Timestamp != "2024-03-05T12:00:00Z" || last.Author != Owner {
+				t.Errorf("update note = %+v, want the owner's, stamped at the update", last)
+			}
+		})
+	}
+}
+
+func TestRefreshAge(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		age        *int
+		birthDate  *string
+		wantYears  int
+		wantMonths int
+	}{
+		{name: "age input", age: intPtr(2), wantYears: 2},
+		{name: "birthday today", age: intPtr(2), birthDate: stringPtr("2021-10-16"), wantYears: 5},
+		{name: "day before the birthday", birthDate: stringPtr("2021-10-17"), wantYears: 4, wantMonths: 11},
+		{name: "months past", birthDate: stringPtr("2024-05-01"), wantYears: 2, wantMonths: 5},
+		{name: "puppy", birthDate: stringPtr("2026-08-20"), wantMonths: 1},
+		{name: "not born yet", birthDate: stringPtr("2026-12-01")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var state DogState
+			state.Age, state.BirthDate = tt.age, tt.birthDate
+			refreshAge(&state, now)
+			if state.AgeYears != tt.wantYears || state.AgeMonths != tt.wantMonths {
+				t.Errorf("age = %dy %dm, want %dy %dm", state.AgeYears, state.AgeMonths, tt.wantYears, tt.wantMonths)
+			}
+			if state.years() != tt.wantYears {
+				t.Errorf("years = %d, want %d", state.years(), tt.wantYears)
+			}
+		})
+	}
+}
+
+func TestCheckBirthDate(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		birthDate *string
+		failed    bool
+	}{
+		{birthDate: nil},
+		{birthDate: stringPtr("2020-02-29")},
+		{birthDate: stringPtr("29/02/2020"), failed: true},
+		{birthDate: stringPtr("2026-10-17"), failed: true},
+		{birthDate: stringPtr("1990-01-01"), failed: true},
+	}
+	for i, tt := range tests {
+		if failures := checkBirthDate(tt.birthDate, now); (len(failures) > 0) != tt.failed {
+			t.Errorf("case %d: failures = %+v, want failed %v", i, failures, tt.failed)
+		}
+	}
+}
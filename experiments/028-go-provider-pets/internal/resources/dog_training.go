@@ -0,0 +1,78 @@
+package resources
+
+import (
+	"context"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// DogTraining Resource - one training session. Task sessions are what a
+// ServiceDogCertification counts towards each task the dog performs.
+type DogTraining struct{}
+
+//pets:state id=ID created=RecordedAt
+//pets:output ID string id Generated identifier of the session
+//pets:output RecordedAt string recordedAt When the session was recorded
+//pets:output SkillSessions int skillSessions Sessions the dog has had on this skill, this one included
+type DogTrainingArgs struct {
+	DogID           string        `pulumi:"dogId" validate:"required"`
+	Skill           string        `pulumi:"skill" validate:"required,max=64"`                            // What was practised, e.g. retrieve-medication
+	Focus           TrainingFocus `pulumi:"focus" validate:"oneof=obedience|task|agility|socialization"` // One of obedience, task, agility or socialization
+	Date            string        `pulumi:"date" validate:"required"`                                    // YYYY-MM-DD
+	DurationMinutes int           `pulumi:"durationMinutes" validate:"min=1,max=480"`
+	Trainer         *string       `pulumi:"trainer,optional"`
+}
+
+var trainings = crudResource[DogTrainingArgs, DogTrainingState, *DogTrainingState]{
+	kind:     stored[DogTrainingState]("training"),
+	prefix:   "training",
+	slug:     func(input DogTrainingArgs) string { return input.DogID + "-" + input.Skill },
+	newState: newDogTrainingState,
+	populate: func(ctx context.Context, state *DogTrainingState, input DogTrainingArgs) error {
+		if _, err := walkedDog(ctx, input.DogID); err != nil {
+			return err
+		}
+		sessions, err := listForDog[DogTrainingState](ctx, "training", input.DogID)
+		if err != nil {
+			return err
+		}
+		state.SkillSessions = countSessions(sessions, "")[input.Skill] + 1
+		return nil
+	},
+}
+
+func (DogTraining) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (DogTrainingArgs, []p.CheckFailure, error) {
+	args, failures, err := trainings.check(newInputs)
+	if args.Date != "" {
+		if _, perr := time.Parse(dateLayout, args.Date); perr != nil {
+			failures = append(failures, p.CheckFailure{Property: "date", Reason: "date must be YYYY-MM-DD"})
+		}
+	}
+	return args, failures, err
+}
+
+func (DogTraining) Create(ctx context.Context, name string, input DogTrainingArgs, preview bool) (string, DogTrainingState, error) {
+	return trainings.create(ctx, name, input, preview)
+}
+
+func (DogTraining) Read(ctx context.Context, id string, inputs DogTrainingArgs, state DogTrainingState) (string, DogTrainingArgs, DogTrainingState, error) {
+	return trainings.read(ctx, id, inputs, state)
+}
+
+func (DogTraining) Delete(ctx context.Context, id string, state DogTrainingState) error {
+	return trainings.delete(ctx, id, state)
+}
+
+// countSessions counts the sessions on each skill, only those with the
+// given focus unless it is empty
+func countSessions(sessions []DogTrainingState, focus TrainingFocus) map[string]int {
+	counts := map[string]int{}
+	for _, session := range sessions {
+		if focus == "" || session.Focus == focus {
+			counts[session.Skill]++
+		}
+	}
+	return counts
+}
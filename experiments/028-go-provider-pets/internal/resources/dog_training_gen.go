@@ -0,0 +1,43 @@
+// Code generated by genstate from dog_training.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// DogTrainingOutputs are computed by the provider; Check rejects them as inputs
+type DogTrainingOutputs struct {
+	ID            string `pulumi:"id"`
+	RecordedAt    string `pulumi:"recordedAt"`
+	SkillSessions int    `pulumi:"skillSessions"`
+	Version       int64  `pulumi:"version"`
+}
+
+// DogTrainingState echoes the inputs next to the computed outputs
+type DogTrainingState struct {
+	DogTrainingArgs
+	DogTrainingOutputs
+}
+
+// newDogTrainingState copies the inputs into an otherwise empty state
+func newDogTrainingState(input DogTrainingArgs) DogTrainingState {
+	return DogTrainingState{DogTrainingArgs: input}
+}
+
+func (s *DogTrainingState) stamp(id, created string)   { s.ID, s.RecordedAt = id, created }
+func (s *DogTrainingState) identity() (string, string) { return s.ID, s.RecordedAt }
+func (s *DogTrainingState) setVersion(version int64)   { s.Version = version }
+func (s *DogTrainingState) storedVersion() int64       { return s.Version }
+
+func (args *DogTrainingArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Skill, "What was practised, e.g. retrieve-medication")
+	a.Describe(&args.Focus, "One of obedience, task, agility or socialization")
+	a.Describe(&args.Date, "YYYY-MM-DD")
+}
+
+func (state *DogTrainingState) Annotate(a infer.Annotator) {
+	state.DogTrainingArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the session")
+	a.Describe(&state.RecordedAt, "When the session was recorded")
+	a.Describe(&state.SkillSessions, "Sessions the dog has had on this skill, this one included")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
@@ -0,0 +1,37 @@
+package resources
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCountSessions(t *testing.T) {
+	session := func(skill string, focus TrainingFocus) DogTrainingState {
+		var s DogTrainingState
+		s.Skill, s.Focus = skill, focus
+		return s
+	}
+	sessions := []DogTrainingState{
+		session("open-door", FocusTask),
+		session("open-door", FocusTask),
+		session("open-door", FocusObedience),
+		session("heel", FocusObedience),
+		session("alert", FocusTask),
+	}
+	tests := []struct {
+		name  string
+		focus TrainingFocus
+		want  map[string]int
+	}{
+		{name: "any focus", want: map[string]int{"open-door": 3, "heel": 1, "alert": 1}},
+		{name: "task sessions", focus: FocusTask, want: map[string]int{"open-door": 2, "alert": 1}},
+		{name: "none with that focus", focus: FocusAgility, want: map[string]int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countSessions(sessions, tt.focus); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("countSessions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// DogWalk Resource - represents taking a dog for a walk
+type DogWalk struct{}
+
+//pets:state id=ID created=Date
+//pets:output ID string id Generated identifier of the walk
+//pets:output Date string date When the walk was recorded
+//pets:output Calories int calories Estimated calories burned: met × 3.5 × weightKg × duration / 200
+//pets:output WeightKg float64 weightKg The dog's weight the calories were estimated for
+//pets:output PaceMph float64 paceMph Average pace of the walk in miles per hour
+//pets:output Met float64 met Metabolic equivalent of walking at paceMph
+//pets:output Enjoyment string enjoyment How much the dog enjoyed it: low, medium or high
+//pets:output EnjoymentScore int enjoymentScore Enjoyment out of 100 from the dog's breed and age, the weather and the pace
+//pets:output BehaviorNote BehaviorNote behaviorNote The owner's observation from the walk; getBehaviorTimeline collects these
+type DogWalkArgs struct {
+	DogID       string   `pulumi:"dogId" validate:"required"`
+	Duration    int      `pulumi:"duration" validate:"gt=0,max=600"` // Length of the walk in minutes
+	Distance    float64  `pulumi:"distance" validate:"min=0,max=50"` // Distance covered in miles
+	Route       *string  `pulumi:"route,optional"`
+	Weather     *Weather `pulumi:"weather,optional" validate:"enum"`
+	Temperature *float64 `pulumi:"temperature,optional" validate:"min=-40,max=130"` // Air temperature in degrees Fahrenheit
+	Notes       *string  `pulumi:"notes,optional"`
+	TreatsGiven *int     `pulumi:"treatsGiven,optional" validate:"min=0"`
+}
+
+var walks = crudResource[DogWalkArgs, DogWalkState, *DogWalkState]{
+	kind:     stored[DogWalkState]("walk"),
+	prefix:   "walk",
+	slug:     func(input DogWalkArgs) string { return input.DogID },
+	newState: newDogWalkState,
+	populate: func(ctx context.Context, state *DogWalkState, input DogWalkArgs) error {
+		dog, err := walkedDog(ctx, input.DogID)
+		if err != nil {
+			return err
+		}
+		state.WeightKg = dogWeightKg(dog)
+		state.PaceMph = walkPace(input)
+		state.Met = walkingMET(state.PaceMph)
+		state.Calories = walkCalories(state.Met, state.WeightKg, input.Duration)
+		state.EnjoymentScore, state.Enjoyment = walkEnjoyment(input, dog, registry.Enjoyment(ctx))
+		if warning := advisoryWarning(input, dog); warning != "" {
+			p.GetLogger(ctx).Warning(warning)
+		}
+		state.BehaviorNote = walkNote(input, state.Enjoyment, registry.Now(ctx))
+		return nil
+	},
+	announce: func(ctx context.Context, state DogWalkState) {
+		dispatch(ctx, walkEvent(dogName(ctx, state.DogID), state))
+	},
+}
+
+func (DogWalk) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (DogWalkArgs, []p.CheckFailure, error) {
+	return walks.check(newInputs)
+}
+
+func (DogWalk) Create(ctx context.Context, name string, input DogWalkArgs, preview bool) (string, DogWalkState, error) {
+	return walks.create(ctx, name, input, preview)
+}
+
+func (DogWalk) Delete(ctx context.Context, id string, state DogWalkState) error {
+	return walks.delete(ctx, id, state)
+}
+
+// walkedDog loads the dog a walk or weigh-in is for, which must be in the
+// registry, with its age brought up to date
+func walkedDog(ctx context.Context, dogID string) (DogState, error) {
+	var dog DogState
+	_, err := registry.Load(ctx, "dog", dogID, &dog)
+	if errors.Is(err, backend.ErrNotFound) {
+		return dog, fmt.Errorf("dogId %q is not a registered dog: %w", dogID, err)
+	}
+	refreshAge(&dog, registry.Now(ctx))
+	return dog, err
+}
+
+// dogWeightKg is the dog's weight in kilograms. The Dog resource tracks
+// pounds: its latest weigh-in, else the weight input, else the breed's
+// typical weight.
+func dogWeightKg(dog DogState) float64 {
+	pounds := EstimateWeightByBreed(dog.Breed)
+	if dog.CurrentWeight > 0 {
+		pounds = dog.CurrentWeight
+	} else if dog.Weight != nil {
+		pounds = *dog.Weight
+	}
+	return round2(pounds * kgPerPound)
+}
+
+// walkPace is a walk's average speed in miles per hour
+func walkPace(input DogWalkArgs) float64 {
+	if input.Duration <= 0 {
+		return 0
+	}
+	return round2(input.Distance / (float64(input.Duration) / 60))
+}
+
+// walkingMETs are the Compendium of Physical Activities values for walking
+// and running on level ground, by the slowest pace each applies to
+var walkingMETs = []struct{ mph, met float64 }{
+	{0, 2.0},
+	{2.0, 2.8},
+	{2.5, 3.0},
+	{3.0, 3.5},
+	{3.5, 4.3},
+	{4.0, 5.0},
+	{4.5, 7.0},
+	{5.0, 8.3},
+	{6.0, 9.8},
+	{7.0, 11.0},
+}
+
+// walkingMET is the metabolic equivalent of moving at mph
+func walkingMET(mph float64) float64 {
+	met := walkingMETs[0].met
+	for _, band := range walkingMETs {
+		if mph >= band.mph {
+			met = band.met
+		}
+	}
+	return met
+}
+
+// walkCalories applies the standard MET formula, kcal per minute =
+// MET × 3.5 × kg / 200, to the dog's weight over the walk's duration
+func walkCalories(met, weightKg float64, minutes int) int {
+	return int(math.Round(met * 3.5 * weightKg / 200 * float64(minutes)))
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// walkEnjoyment scores a walk from 0 to 100 as the weighted sum of how well
+// it suited the dog's breed, the weather, its pace and the dog's age, and
+// labels the score. The exercise and pace a breed needs are scaled to the
+// intensity the dog prefers its walks at. Walking under a heat or cold
+// advisory halves the score. A breed missing from the catalog counts as
+// average energy, and a dog of unknown age as a two-year-old.
+func walkEnjoyment(input DogWalkArgs, dog DogState, weights registry.EnjoymentWeights) (int, string) {
+	energy, age := 3, dog.years()
+	if info, ok := BreedCatalog[dog.Breed]; ok {
+		energy = info.EnergyLevel
+	}
+	minutes, intensity := float64(input.Duration), dog.walkIntensity()
+
+	score := weights.BreedEnergy*exerciseFit(minutes, float64(15*energy)*intensity) +
+		weights.Weather*weatherFit(input.Weather, input.Temperature) +
+		weights.Pace*paceFit(walkPace(input), (1.5+0.5*float64(energy))*intensity) +
+		weights.Age*ageFit(minutes, age)
+	if walkAdvisory(input) != "" {
+		score /= 2
+	}
+	percent := clampScore(100 * score)
+
+	switch {
+	case percent >= 70:
+		return percent, "high"
+	case percent >= 40:
+		return percent, "medium"
+	default:
+		return percent, "low"
+	}
+}
+
+// exerciseFit rates a walk's length against the exercise the breed needs:
+// falling short is a partial walk, and more than twice the need overtires.
+func exerciseFit(minutes, need float64) float64 {
+	if minutes > 2*need {
+		return unit(1 - (minutes-2*need)/need)
+	}
+	return unit(minutes / need)
+}
+
+// How much dogs like each kind of weather
+var weatherComfort = map[Weather]float64{
+	Sunny:        1,
+	Cloudy:       0.8,
+	Snow:         0.6,
+	Rain:         0.4,
+	HeatAdvisory: 0,
+}
+
+// weatherFit averages the comfort of the conditions and the temperature,
+// whichever are known. Walks in 45-75°F are comfortable, and comfort falls
+// away to nothing at 20°F and 95°F.
+func weatherFit(weather *Weather, temperature *float64) float64 {
+	var sum float64
+	var known int
+	if weather != nil {
+		if comfort, ok := weatherComfort[*weather]; ok {
+			sum, known = sum+comfort, known+1
+		}
+	}
+	if temperature != nil {
+		t := *temperature
+		switch {
+		case t < 45:
+			sum += unit((t - 20) / 25)
+		case t > 75:
+			sum += unit((95 - t) / 20)
+		default:
+			sum++
+		}
+		known++
+	}
+	if known == 0 {
+		return 0.75
+	}
+	return sum / float64(known)
+}
+
+// walkAdvisory is "heat" or "cold" when a walk is in weather dogs shouldn't
+// be out in for long: a heat advisory or 90°F and up, or 20°F and below.
+func walkAdvisory(input DogWalkArgs) string {
+	switch {
+	case input.Weather != nil && *input.Weather == HeatAdvisory,
+		input.Temperature != nil && *input.Temperature >= 90:
+		return "heat"
+	case input.Temperature != nil && *input.Temperature <= 20:
+		return "cold"
+	}
+	return ""
+}
+
+// advisoryWarning is the safety warning for walking a short-nosed breed
+// under an advisory, or "" when there is nothing to warn about
+func advisoryWarning(input DogWalkArgs, dog DogState) string {
+	if !BreedCatalog[dog.Breed].ShortNosed {
+		return ""
+	}
+	switch walkAdvisory(input) {
+	case "heat":
+		return fmt.Sprintf("%s is a short-nosed breed and overheats quickly: keep walks in a heat advisory short, shaded and with water", dog.Name)
+	case "cold":
+		return fmt.Sprintf("%s is a short-nosed breed and finds very cold air hard to breathe: keep walks in a cold advisory short", dog.Name)
+	}
+	return ""
+}
+
+// paceFit rates the walk's speed against the breed's ideal pace in mph
+func paceFit(mph, ideal float64) float64 {
+	return unit(1 - math.Abs(mph-ideal)/ideal)
+}
+
+// ageFit rates the walk's length against what a dog of age is comfortable
+// with: puppies and seniors tire sooner than adults.
+func ageFit(minutes float64, age int) float64 {
+	comfortable := 60.0
+	switch {
+	case age < 1:
+		comfortable = 20
+	case age >= 10:
+		comfortable = 30
+	}
+	if minutes <= comfortable {
+		return 1
+	}
+	return unit(1 - (minutes-comfortable)/comfortable)
+}
+
+// unit clamps v to [0, 1]
+func unit(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// walkNote records the walk in the dog's behavior timeline. The walker's
+// own notes are the observation when there are any; a walk the dog didn't
+// enjoy is worth a closer look.
+func walkNote(input DogWalkArgs, enjoyment string, now time.Time) BehaviorNote {
+	severity := Info
+	if enjoyment == "low" {
+		severity = Concern
+	}
+	note := fmt.Sprintf("Went on a %d-minute walk and showed %s enjoyment", input.Duration, enjoyment)
+	if input.Notes != nil && strings.TrimSpace(*input.Notes) != "" {
+		note = strings.TrimSpace(*input.Notes)
+	}
+	return observed(now, Owner, severity, note)
+}
@@ -0,0 +1,53 @@
+// Code generated by genstate from dog_walk.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// DogWalkOutputs are computed by the provider; Check rejects them as inputs
+type DogWalkOutputs struct {
+	ID             string       `pulumi:"id"`
+	Date           string       `pulumi:"date"`
+	Calories       int          `pulumi:"calories"`
+	WeightKg       float64      `pulumi:"weightKg"`
+	PaceMph        float64      `pulumi:"paceMph"`
+	Met            float64      `pulumi:"met"`
+	Enjoyment      string       `pulumi:"enjoyment"`
+	EnjoymentScore int          `pulumi:"enjoymentScore"`
+	BehaviorNote   BehaviorNote `pulumi:"behaviorNote"`
+	Version        int64        `pulumi:"version"`
+}
+
+// DogWalkState echoes the inputs next to the computed outputs
+type DogWalkState struct {
+	DogWalkArgs
+	DogWalkOutputs
+}
+
+// newDogWalkState copies the inputs into an otherwise empty state
+func newDogWalkState(input DogWalkArgs) DogWalkState { return DogWalkState{DogWalkArgs: input} }
+
+func (s *DogWalkState) stamp(id, created string)   { s.ID, s.Date = id, created }
+func (s *DogWalkState) identity() (string, string) { return s.ID, s.Date }
+func (s *DogWalkState) setVersion(version int64)   { s.Version = version }
+func (s *DogWalkState) storedVersion() int64       { return s.Version }
+
+func (args *DogWalkArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Duration, "Length of the walk in minutes")
+	a.Describe(&args.Distance, "Distance covered in miles")
+	a.Describe(&args.Temperature, "Air temperature in degrees Fahrenheit")
+}
+
+func (state *DogWalkState) Annotate(a infer.Annotator) {
+	state.DogWalkArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the walk")
+	a.Describe(&state.Date, "When the walk was recorded")
+	a.Describe(&state.Calories, "Estimated calories burned: met × 3.5 × weightKg × duration / 200")
+	a.Describe(&state.WeightKg, "The dog's weight the calories were estimated for")
+	a.Describe(&state.PaceMph, "Average pace of the walk in miles per hour")
+	a.Describe(&state.Met, "Metabolic equivalent of walking at paceMph")
+	a.Describe(&state.Enjoyment, "How much the dog enjoyed it: low, medium or high")
+	a.Describe(&state.EnjoymentScore, "Enjoyment out of 100 from the dog's breed and age, the weather and the pace")
+	a.Describe(&state.BehaviorNote, "The owner's observation from the walk; getBehaviorTimeline collects these")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
@@ -0,0 +1,202 @@
+package resources
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+func weatherPtr(w Weather) *Weather { return &w }
+
+func TestWalkCalories(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration int
+		distance float64
+		weightKg float64
+		wantPace float64
+		wantMET  float64
+		want     int
+	}{
+		{name: "half hour at three mph", duration: 30, distance: 1.5, weightKg: 30, wantPace: 3, wantMET: 3.5, want: 55},
+		{name: "small dog's slow hour", duration: 60, distance: 2, weightKg: 10, wantPace: 2, wantMET: 2.8, want: 29},
+		{name: "running", duration: 30, distance: 3, weightKg: 30, wantPace: 6, wantMET: 9.8, want: 154},
+		{name: "sniffing about", duration: 20, distance: 0.25, weightKg: 30, wantPace: 0.75, wantMET: 2, want: 21},
+		{name: "no time at all", duration: 0, distance: 0, weightKg: 30, wantPace: 0, wantMET: 2, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pace := walkPace(DogWalkArgs{Duration: tt.duration, Distance: tt.distance})
+			met := walkingMET(pace)
+			if pace != tt.wantPace || met != tt.wantMET {
+				t.Errorf("pace/MET = %g/%g, want %g/%g", pace, met, tt.wantPace, tt.wantMET)
+			}
+			if got := walkCalories(met, tt.weightKg, tt.duration); got != tt.want {
+				t.Errorf("walkCalories = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDogWeightKg(t *testing.T) {
+	tests := []struct {
+		name string
+		dog  DogState
+		want float64
+	}{
+		{name: "recorded weight", dog: DogState{DogArgs: DogArgs{Breed: Beagle, Weight: floatPtr(22)}}, want: 9.98},
+		{name: "breed estimate", dog: DogState{DogArgs: DogArgs{Breed: GoldenRetriever}}, want: 29.48},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dogWeightKg(tt.dog); got != tt.want {
+				t.Errorf("dogWeightKg = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalkEnjoyment(t *testing.T) {
+	weights := registry.EnjoymentWeights{BreedEnergy: 0.35, Weather: 0.25, Pace: 0.2, Age: 0.2}
+	dog := func(breed DogBreed, age int) DogState {
+		return DogState{DogArgs: DogArgs{Breed: breed, Age: &age}}
+	}
+	keen := func(dog DogState, activity Activity, intensity ActivityIntensity) DogState {
+		dog.Preferences = &Preferences{ActivityPreferences: []ActivityPreference{{Activity: activity, Intensity: intensity}}}
+		return dog
+	}
+	tests := []struct {
+		name      string
+		input     DogWalkArgs
+		dog       DogState
+		weights   registry.EnjoymentWeights
+		wantScore int
+		wantLabel string
+	}{
+		{
+			name:      "retriever's hour in the sun",
+			input:     DogWalkArgs{Duration: 60, Distance: 3, Weather: weatherPtr(Sunny), Temperature: floatPtr(70)},
+			dog:       dog(GoldenRetriever, 3),
+			wantScore: 97,
+			wantLabel: "high",
+		},
+		{
+			name:      "bulldog marched too far",
+			input:     DogWalkArgs{Duration: 90, Distance: 1.5},
+			dog:       dog(Bulldog, 3),
+			wantScore: 39,
+			wantLabel: "low",
+		},
+		{
+			name:      "husky on an easy stroll",
+			input:     DogWalkArgs{Duration: 45, Distance: 1.5},
+			dog:       dog(Husky, 3),
+			wantScore: 70,
+			wantLabel: "high",
+		},
+		{
+			name:      "husky that likes its walks easy",
+			input:     DogWalkArgs{Duration: 45, Distance: 1.5},
+			dog:       keen(dog(Husky, 3), ActivityWalking, IntensityLow),
+			wantScore: 80,
+			wantLabel: "high",
+		},
+		{
+			name:      "senior husky in cold rain",
+			input:     DogWalkArgs{Duration: 30, Distance: 1.5, Weather: weatherPtr(Rain), Temperature: floatPtr(40)},
+			dog:       dog(Husky, 12),
+			wantScore: 64,
+			wantLabel: "medium",
+		},
+		{
+			name:      "heat spoils a short walk",
+			input:     DogWalkArgs{Duration: 20, Distance: 1, Temperature: floatPtr(100)},
+			dog:       dog(GoldenRetriever, 3),
+			wantScore: 24,
+			wantLabel: "low",
+		},
+		{
+			name:      "heat advisory halves it",
+			input:     DogWalkArgs{Duration: 60, Distance: 3, Weather: weatherPtr(HeatAdvisory)},
+			dog:       dog(GoldenRetriever, 3),
+			wantScore: 36,
+			wantLabel: "low",
+		},
+		{
+			name:      "cold advisory halves it",
+			input:     DogWalkArgs{Duration: 60, Distance: 3, Weather: weatherPtr(Snow), Temperature: floatPtr(10)},
+			dog:       dog(GoldenRetriever, 3),
+			wantScore: 40,
+			wantLabel: "medium",
+		},
+		{
+			name:      "unknown breed and age",
+			input:     DogWalkArgs{Duration: 30, Distance: 1.5},
+			wantScore: 82,
+			wantLabel: "high",
+		},
+		{
+			name:      "only the weather counts",
+			input:     DogWalkArgs{Duration: 5, Distance: 0, Weather: weatherPtr(Cloudy)},
+			dog:       dog(Beagle, 2),
+			weights:   registry.EnjoymentWeights{Weather: 1},
+			wantScore: 80,
+			wantLabel: "high",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := weights
+			if tt.weights != (registry.EnjoymentWeights{}) {
+				w = tt.weights
+			}
+			score, label := walkEnjoyment(tt.input, tt.dog, w)
+			if score != tt.wantScore || label != tt.wantLabel {
+				t.Errorf("walkEnjoyment = %d/%q, want %d/%q", score, label, tt.wantScore, tt.wantLabel)
+			}
+		})
+	}
+}
+
+func TestDogWalkCreatePreview(t *testing.T) {
+	input := DogWalkArgs{DogID: "dog-rex-1", Duration: 30, Distance: 2}
+	id, state, err := DogWalk{}.Create(context.Background(), "morning-walk", input, true)
+	if err != nil {
+		t.Fatalf("preview create: %v", err)
+	}
+	if id != "morning-walk" {
+		t.Errorf("id = %q, want the logical name", id)
+	}
+	if state.Calories != 0 || state.Enjoyment != "" || state.EnjoymentScore != 0 {
+		t.Errorf("preview computed outputs %d/%q/%d, want them left unknown", state.Calories, state.Enjoyment, state.EnjoymentScore)
+	}
+}
+
+func TestAdvisoryWarning(t *testing.T) {
+	bulldog := DogState{DogArgs: DogArgs{Name: "Tank", Breed: Bulldog}}
+	husky := DogState{DogArgs: DogArgs{Name: "Nanook", Breed: Husky}}
+	tests := []struct {
+		name  string
+		input DogWalkArgs
+		dog   DogState
+		want  string // substring of the warning, "" for none
+	}{
+		{name: "heat advisory", input: DogWalkArgs{Weather: weatherPtr(HeatAdvisory)}, dog: bulldog, want: "overheats"},
+		{name: "hot day", input: DogWalkArgs{Weather: weatherPtr(Sunny), Temperature: floatPtr(92)}, dog: bulldog, want: "overheats"},
+		{name: "bitter cold", input: DogWalkArgs{Temperature: floatPtr(15)}, dog: bulldog, want: "hard to breathe"},
+		{name: "mild day", input: DogWalkArgs{Weather: weatherPtr(Cloudy), Temperature: floatPtr(60)}, dog: bulldog},
+		{name: "long-nosed breed", input: DogWalkArgs{Weather: weatherPtr(HeatAdvisory)}, dog: husky},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := advisoryWarning(tt.input, tt.dog)
+			if (tt.want == "") != (got == "") || !strings.Contains(got, tt.want) {
+				t.Errorf("advisoryWarning = %q, want it to mention %q", got, tt.want)
+			}
+		})
+	}
+}
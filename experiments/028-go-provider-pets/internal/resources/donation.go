@@ -0,0 +1,126 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+)
+
+// Donation Resource - a gift of money to a shelter, optionally credited to
+// a fundraising campaign. Each donation gets a receipt number running per
+// shelter and year, and a summary of what the donor can claim; getCampaignTotals
+// adds them up by campaign. A donation can't be edited, only replaced.
+type Donation struct{}
+
+//pets:state id=ID created=DonatedAt
+//pets:output ID string id Generated identifier of the donation
+//pets:output DonatedAt string donatedAt When the donation was recorded
+//pets:output ReceiptNumber string receiptNumber Receipt number, running per shelter and year
+//pets:output TaxSummary DonationTaxSummary taxSummary What the donor can claim for the donation
+type DonationArgs struct {
+	ShelterID    string  `pulumi:"shelterId" validate:"required"`
+	Amount       float64 `pulumi:"amount" validate:"gt=0"` // Dollars
+	DonorName    *string `pulumi:"donorName,optional"`     // Printed on the receipt; anonymous when unset
+	DonorContact string  `pulumi:"donorContact" provider:"secret" validate:"required"`
+	Campaign     *string `pulumi:"campaign,optional"` // The fundraising campaign the donation is credited to
+}
+
+// DonationTaxSummary is the part of a receipt a donor files their taxes
+// with
+type DonationTaxSummary struct {
+	TaxYear               int     `pulumi:"taxYear" json:"taxYear"`
+	DeductibleAmount      float64 `pulumi:"deductibleAmount" json:"deductibleAmount"`
+	Acknowledgement       string  `pulumi:"acknowledgement" json:"acknowledgement"`
+	AcknowledgementNeeded bool    `pulumi:"acknowledgementNeeded" json:"acknowledgementNeeded"` // Whether the amount is large enough that a deduction needs the written acknowledgement
+}
+
+// acknowledgementThreshold is the single gift, in dollars, from which a
+// deduction needs the charity's written acknowledgement
+const acknowledgementThreshold = 250
+
+// GeneralCampaign is what donations not credited to a campaign count
+// towards
+const GeneralCampaign = "general"
+
+var donations = crudResource[DonationArgs, DonationState, *DonationState]{
+	kind:     stored[DonationState]("donation"),
+	prefix:   "gift",
+	slug:     func(input DonationArgs) string { return input.ShelterID },
+	newState: newDonationState,
+	populate: func(ctx context.Context, state *DonationState, input DonationArgs) error {
+		if err := requireShelter(ctx, input.ShelterID); err != nil {
+			return err
+		}
+		donated, err := time.Parse(time.RFC3339, state.DonatedAt)
+		if err != nil {
+			return err
+		}
+		earlier, err := listRecords[DonationState](ctx, backend.Query{
+			Kind:  "donation",
+			Where: []backend.Condition{{Field: "ShelterID", Op: "eq", Value: input.ShelterID}},
+		})
+		if err != nil {
+			return err
+		}
+		state.ReceiptNumber = receiptNumber(input.ShelterID, donated.Year(), earlier)
+		state.TaxSummary = donationTaxSummary(state.Amount, donated.Year(), input.ShelterID)
+		return nil
+	},
+}
+
+func (Donation) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (DonationArgs, []p.CheckFailure, error) {
+	args, failures, err := donations.check(newInputs)
+	if args.Amount != round2(args.Amount) {
+		failures = append(failures, p.CheckFailure{Property: "amount", Reason: "amount must be in whole cents"})
+	}
+	return args, failures, err
+}
+
+func (Donation) Create(ctx context.Context, name string, input DonationArgs, preview bool) (string, DonationState, error) {
+	return donations.create(ctx, name, input, preview)
+}
+
+func (Donation) Read(ctx context.Context, id string, inputs DonationArgs, state DonationState) (string, DonationArgs, DonationState, error) {
+	return donations.read(ctx, id, inputs, state)
+}
+
+func (Donation) Delete(ctx context.Context, id string, state DonationState) error {
+	return donations.delete(ctx, id, state)
+}
+
+// CreditedCampaign is the campaign the donation counts towards
+func (args DonationArgs) CreditedCampaign() string {
+	if args.Campaign == nil || strings.TrimSpace(*args.Campaign) == "" {
+		return GeneralCampaign
+	}
+	return strings.TrimSpace(*args.Campaign)
+}
+
+// receiptNumber follows the highest receipt the shelter issued in year
+func receiptNumber(shelterID string, year int, earlier []DonationState) string {
+	prefix := fmt.Sprintf("%s-%d-", strings.ToUpper(shelterID), year)
+	last := 0
+	for _, donation := range earlier {
+		seq, ok := strings.CutPrefix(donation.ReceiptNumber, prefix)
+		if n, err := strconv.Atoi(seq); ok && err == nil && n > last {
+			last = n
+		}
+	}
+	return fmt.Sprintf("%s%05d", prefix, last+1)
+}
+
+func donationTaxSummary(amount float64, year int, shelterID string) DonationTaxSummary {
+	return DonationTaxSummary{
+		TaxYear:               year,
+		DeductibleAmount:      round2(amount),
+		Acknowledgement:       fmt.Sprintf("%s received a cash contribution of $%.2f. No goods or services were provided in exchange for it.", shelterID, amount),
+		AcknowledgementNeeded: amount >= acknowledgementThreshold,
+	}
+}
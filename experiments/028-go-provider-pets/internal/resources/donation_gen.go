@@ -0,0 +1,43 @@
+// Code generated by genstate from donation.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// DonationOutputs are computed by the provider; Check rejects them as inputs
+type DonationOutputs struct {
+	ID            string             `pulumi:"id"`
+	DonatedAt     string             `pulumi:"donatedAt"`
+	ReceiptNumber string             `pulumi:"receiptNumber"`
+	TaxSummary    DonationTaxSummary `pulumi:"taxSummary"`
+	Version       int64              `pulumi:"version"`
+}
+
+// DonationState echoes the inputs next to the computed outputs
+type DonationState struct {
+	DonationArgs
+	DonationOutputs
+}
+
+// newDonationState copies the inputs into an otherwise empty state
+func newDonationState(input DonationArgs) DonationState { return DonationState{DonationArgs: input} }
+
+func (s *DonationState) stamp(id, created string)   { s.ID, s.DonatedAt = id, created }
+func (s *DonationState) identity() (string, string) { return s.ID, s.DonatedAt }
+func (s *DonationState) setVersion(version int64)   { s.Version = version }
+func (s *DonationState) storedVersion() int64       { return s.Version }
+
+func (args *DonationArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Amount, "Dollars")
+	a.Describe(&args.DonorName, "Printed on the receipt; anonymous when unset")
+	a.Describe(&args.Campaign, "The fundraising campaign the donation is credited to")
+}
+
+func (state *DonationState) Annotate(a infer.Annotator) {
+	state.DonationArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the donation")
+	a.Describe(&state.DonatedAt, "When the donation was recorded")
+	a.Describe(&state.ReceiptNumber, "Receipt number, running per shelter and year")
+	a.Describe(&state.TaxSummary, "What the donor can claim for the donation")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
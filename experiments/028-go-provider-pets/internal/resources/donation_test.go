@@ -0,0 +1,57 @@
+package resources
+
+import "testing"
+
+func TestReceiptNumber(t *testing.T) {
+	receipt := func(number string) DonationState {
+		var d DonationState
+		d.ReceiptNumber = number
+		return d
+	}
+	tests := []struct {
+		name    string
+		earlier []DonationState
+		want    string
+	}{
+		{name: "first of the year", want: "HAPPY-TAILS-2026-00001"},
+		{name: "follows the highest", earlier: []DonationState{receipt("HAPPY-TAILS-2026-00002"), receipt("HAPPY-TAILS-2026-00007")}, want: "HAPPY-TAILS-2026-00008"},
+		{name: "last year's don't count", earlier: []DonationState{receipt("HAPPY-TAILS-2025-00031")}, want: "HAPPY-TAILS-2026-00001"},
+		{name: "unreadable receipts are skipped", earlier: []DonationState{receipt("HAPPY-TAILS-2026-x"), receipt("")}, want: "HAPPY-TAILS-2026-00001"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := receiptNumber("happy-tails", 2026, tt.earlier); got != tt.want {
+				t.Errorf("receiptNumber = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDonationTaxSummary(t *testing.T) {
+	tests := []struct {
+		amount float64
+		needed bool
+	}{
+		{amount: 25},
+		{amount: 249.99},
+		{amount: 250, needed: true},
+		{amount: 1000, needed: true},
+	}
+	for _, tt := range tests {
+		summary := donationTaxSummary(tt.amount, 2026, "happy-tails")
+		if summary.DeductibleAmount != tt.amount || summary.TaxYear != 2026 || summary.AcknowledgementNeeded != tt.needed {
+			t.Errorf("donationTaxSummary(%v) = %+v", tt.amount, summary)
+		}
+	}
+}
+
+func TestCreditedCampaign(t *testing.T) {
+	for campaign, want := range map[string]string{"": GeneralCampaign, "  ": GeneralCampaign, " Winter Coats ": "Winter Coats"} {
+		if got := (DonationArgs{Campaign: stringPtr(campaign)}).CreditedCampaign(); got != want {
+			t.Errorf("CreditedCampaign(%q) = %q, want %q", campaign, got, want)
+		}
+	}
+	if got := (DonationArgs{}).CreditedCampaign(); got != GeneralCampaign {
+		t.Errorf("CreditedCampaign() = %q, want %q", got, GeneralCampaign)
+	}
+}
@@ -0,0 +1,147 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	p "github.com/pulumi/pulumi-go-provider"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// event is something that happened to a dog, to be posted to the
+// NotificationChannels subscribed to it
+type event struct {
+	Kind    EventKind
+	DogID   string
+	Title   string
+	Details []string
+}
+
+// dispatch posts e to every channel that wants it. A channel that can't be
+// reached only costs a warning: the walk or visit has been recorded either
+// way.
+func dispatch(ctx context.Context, e event) {
+	subscribed, err := listRecords[NotificationChannelState](ctx, backend.Query{Kind: "channel"})
+	if err != nil {
+		p.GetLogger(ctx).Warningf("notification channels: %v", err)
+		return
+	}
+	for _, channel := range subscribed {
+		if !channel.wants(e) {
+			continue
+		}
+		if registry.Simulating(ctx) {
+			p.GetLogger(ctx).Infof("simulating: not posting %s event to channel %s", e.Kind, channel.ID)
+			continue
+		}
+		if err := postEvent(ctx, registry.HTTPClient(ctx), channel, e); err != nil {
+			p.GetLogger(ctx).Warningf("notification channel %s: %v", channel.ID, err)
+		}
+	}
+}
+
+// wants reports whether the channel's filters let e through
+func (c NotificationChannelState) wants(e event) bool {
+	if c.DogID != nil && *c.DogID != e.DogID {
+		return false
+	}
+	if len(c.Events) == 0 {
+		return true
+	}
+	for _, kind := range c.Events {
+		if kind == e.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+// webhookPayload formats e in the channel's markup: Slack's mrkdwn takes
+// single asterisks for bold, Discord's markdown double ones.
+func webhookPayload(channelType ChannelType, e event) ([]byte, error) {
+	switch channelType {
+	case ChannelSlack:
+		lines := []string{"*" + e.Title + "*"}
+		for _, detail := range e.Details {
+			lines = append(lines, "• "+detail)
+		}
+		return json.Marshal(map[string]string{"text": strings.Join(lines, "\n")})
+	case ChannelDiscord:
+		lines := []string{"**" + e.Title + "**"}
+		for _, detail := range e.Details {
+			lines = append(lines, "- "+detail)
+		}
+		return json.Marshal(map[string]string{"content": strings.Join(lines, "\n")})
+	}
+	return nil, fmt.Errorf("unknown channel type %q", channelType)
+}
+
+// postEvent sends e to a channel's webhook through client
+func postEvent(ctx context.Context, client *http.Client, channel NotificationChannelState, e event) error {
+	body, err := webhookPayload(channel.Type, e)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		// The error quotes the URL, which is a secret
+		return fmt.Errorf("posting %s event failed", e.Kind)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("posting %s event: unexpected status %s", e.Kind, resp.Status)
+	}
+	return nil
+}
+
+// dogName is what messages call a dog, its ID when it can't be loaded
+func dogName(ctx context.Context, dogID string) string {
+	if dog, err := walkedDog(ctx, dogID); err == nil && dog.Name != "" {
+		return dog.Name
+	}
+	return dogID
+}
+
+// walkEvent announces a finished walk
+func walkEvent(name string, state DogWalkState) event {
+	details := []string{
+		fmt.Sprintf("%g miles at %g mph", state.Distance, state.PaceMph),
+		fmt.Sprintf("%d calories burned", state.Calories),
+		fmt.Sprintf("Enjoyment: %s (%d/100)", state.Enjoyment, state.EnjoymentScore),
+	}
+	if state.Route != nil && *state.Route != "" {
+		details = append(details, "Route: "+*state.Route)
+	}
+	return event{
+		Kind:    EventWalk,
+		DogID:   state.DogID,
+		Title:   fmt.Sprintf("%s finished a %d-minute walk", name, state.Duration),
+		Details: details,
+	}
+}
+
+// visitEvent announces a vet visit
+func visitEvent(name string, state VeterinaryVisitState) event {
+	details := []string{state.Diagnosis}
+	if len(state.Medications) > 0 {
+		details = append(details, "Medications: "+strings.Join(state.Medications, ", "))
+	}
+	details = append(details, "Next visit: "+state.NextVisit)
+	return event{
+		Kind:    EventVisit,
+		DogID:   state.DogID,
+		Title:   fmt.Sprintf("%s had a %s visit with %s at %s", name, state.VisitType, state.VetName, state.ClinicName),
+		Details: details,
+	}
+}
@@ -0,0 +1,120 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestChannelWants(t *testing.T) {
+	walk := event{Kind: EventWalk, DogID: "dog-rex"}
+	tests := []struct {
+		name    string
+		channel NotificationChannelArgs
+		want    bool
+	}{
+		{name: "no filters", want: true},
+		{name: "subscribed to walks", channel: NotificationChannelArgs{Events: []EventKind{EventVisit, EventWalk}}, want: true},
+		{name: "visits only", channel: NotificationChannelArgs{Events: []EventKind{EventVisit}}, want: false},
+		{name: "same dog", channel: NotificationChannelArgs{DogID: stringPtr("dog-rex")}, want: true},
+		{name: "another dog", channel: NotificationChannelArgs{DogID: stringPtr("dog-fido")}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (NotificationChannelState{NotificationChannelArgs: tt.channel}).wants(walk); got != tt.want {
+				t.Errorf("wants = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckChannel(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   NotificationChannelArgs
+		failed []string
+	}{
+		{name: "slack webhook", args: NotificationChannelArgs{WebhookURL: "https://hooks.slack.com/services/T0/B0/x", Events: []EventKind{EventWalk}}},
+		{name: "geofence alerts", args: NotificationChannelArgs{WebhookURL: "https://hooks.slack.com/services/T0/B0/x", Events: []EventKind{EventGeofence}}},
+		{name: "not a URL", args: NotificationChannelArgs{WebhookURL: "hooks.slack.com/services"}, failed: []string{"webhookUrl"}},
+		{name: "unknown event", args: NotificationChannelArgs{WebhookURL: "https://discord.com/api/webhooks/1/x", Events: []EventKind{EventWalk, "adoption"}}, failed: []string{"events[1]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := checkChannel(tt.args)
+			if len(failures) != len(tt.failed) {
+				t.Fatalf("failures = %+v, want %v", failures, tt.failed)
+			}
+			for i, f := range failures {
+				if f.Property != tt.failed[i] {
+					t.Errorf("failure %d on %q, want %q", i, f.Property, tt.failed[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookPayload(t *testing.T) {
+	var walk DogWalkState
+	walk.DogID, walk.Duration, walk.Distance, walk.Route = "dog-rex", 30, 1.5, stringPtr("Riverside")
+	walk.PaceMph, walk.Calories, walk.Enjoyment, walk.EnjoymentScore = 3, 55, "high", 82
+	e := walkEvent("Rex", walk)
+
+	tests := []struct {
+		channel ChannelType
+		key     string
+		want    string
+	}{
+		{channel: ChannelSlack, key: "text", want: "*Rex finished a 30-minute walk*\n• 1.5 miles at 3 mph\n• 55 calories burned\n• Enjoyment: high (82/100)\n• Route: Riverside"},
+		{channel: ChannelDiscord, key: "content", want: "**Rex finished a 30-minute walk**\n- 1.5 miles at 3 mph\n- 55 calories burned\n- Enjoyment: high (82/100)\n- Route: Riverside"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.channel), func(t *testing.T) {
+			body, err := webhookPayload(tt.channel, e)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var payload map[string]string
+			if err := json.Unmarshal(body, &payload); err != nil {
+				t.Fatal(err)
+			}
+			if payload[tt.key] != tt.want {
+				t.Errorf("%s = %q, want %q", tt.key, payload[tt.key], tt.want)
+			}
+		})
+	}
+}
+
+func TestPostEvent(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = string(body)
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	var visit VeterinaryVisitState
+	visit.DogID, visit.VisitType, visit.VetName, visit.ClinicName = "dog-rex", VisitCheckup, "Dr. Smith", "Happy Paws"
+	visit.Diagnosis, visit.NextVisit = "Healthy and happy!", "2027-06-15"
+	e := visitEvent("Rex", visit)
+
+	channel := NotificationChannelState{NotificationChannelArgs: NotificationChannelArgs{Type: ChannelDiscord, WebhookURL: server.URL + "/hook"}}
+	if err := postEvent(context.Background(), server.Client(), channel, e); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "Rex had a checkup visit with Dr. Smith at Happy Paws") {
+		t.Errorf("posted %s", got)
+	}
+
+	channel.WebhookURL = server.URL + "/gone"
+	if err := postEvent(context.Background(), server.Client(), channel, e); err == nil || strings.Contains(err.Error(), server.URL) {
+		t.Errorf("err = %v, want a failure that doesn't quote the webhook URL", err)
+	}
+}
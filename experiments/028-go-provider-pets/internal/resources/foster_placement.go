@@ -0,0 +1,138 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// FosterPlacement Resource - a dog staying with a foster home for a while.
+// A dog is in one foster home at a time: a placement exists from when it
+// is made until the dog comes back, and another one for the same dog can't
+// be made meanwhile. Deleting the placement is the dog's return to the
+// shelter, which goes into the dog's audit log. Moving the dog to another
+// foster home or start date replaces the placement.
+type FosterPlacement struct{}
+
+//pets:state id=ID created=PlacedAt
+//pets:output ID string id Generated identifier of the placement
+//pets:output PlacedAt string placedAt When the placement was made
+//pets:output ExpectedReturn string expectedReturn The day the dog is expected back, YYYY-MM-DD
+//pets:output Status string status scheduled before the start date, active during the placement, overdue after the expected return; recomputed on refresh
+//pets:output DaysInFoster int daysInFoster Days since the start date, 0 before it; recomputed on refresh
+type FosterPlacementArgs struct {
+	DogID                string `pulumi:"dogId" validate:"required"`
+	FosterContact        string `pulumi:"fosterContact" provider:"secret" validate:"required"`
+	StartDate            string `pulumi:"startDate" validate:"required"`                 // YYYY-MM-DD
+	ExpectedDurationDays int    `pulumi:"expectedDurationDays" validate:"min=1,max=365"` // How long the dog is expected to stay
+}
+
+var fosterPlacements = crudResource[FosterPlacementArgs, FosterPlacementState, *FosterPlacementState]{
+	kind:     stored[FosterPlacementState]("foster"),
+	prefix:   "foster",
+	slug:     func(input FosterPlacementArgs) string { return input.DogID },
+	newState: newFosterPlacementState,
+	populate: func(ctx context.Context, state *FosterPlacementState, input FosterPlacementArgs) error {
+		dog, err := walkedDog(ctx, input.DogID)
+		if err != nil {
+			return err
+		}
+		placed, err := listForDog[FosterPlacementState](ctx, "foster", input.DogID)
+		if err != nil {
+			return err
+		}
+		if len(placed) > 0 {
+			return fmt.Errorf("%s is already in foster care since %s (%s); delete that placement to return the dog first", dog.Name, placed[0].StartDate, placed[0].ID)
+		}
+		trackPlacement(state, registry.Now(ctx))
+		return nil
+	},
+	carry: func(ctx context.Context, state *FosterPlacementState, oldState FosterPlacementState, now time.Time) error {
+		trackPlacement(state, now)
+		return nil
+	},
+	refresh: func(ctx context.Context, id string, state *FosterPlacementState) error {
+		trackPlacement(state, registry.Now(ctx))
+		return nil
+	},
+}
+
+func (FosterPlacement) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (FosterPlacementArgs, []p.CheckFailure, error) {
+	args, failures, err := fosterPlacements.check(newInputs)
+	if _, perr := time.Parse(dateLayout, args.StartDate); args.StartDate != "" && perr != nil {
+		failures = append(failures, p.CheckFailure{Property: "startDate", Reason: "startDate must be YYYY-MM-DD"})
+	}
+	return args, failures, err
+}
+
+func (FosterPlacement) Diff(ctx context.Context, id string, olds FosterPlacementState, news FosterPlacementArgs) (p.DiffResponse, error) {
+	// Another dog, home or start date is another placement; the expected
+	// duration can be extended or cut short in place
+	diff := map[string]p.PropertyDiff{}
+	if olds.DogID != news.DogID {
+		diff["dogId"] = p.PropertyDiff{Kind: p.UpdateReplace, InputDiff: true}
+	}
+	if olds.FosterContact != news.FosterContact {
+		diff["fosterContact"] = p.PropertyDiff{Kind: p.UpdateReplace, InputDiff: true}
+	}
+	if olds.StartDate != news.StartDate {
+		diff["startDate"] = p.PropertyDiff{Kind: p.UpdateReplace, InputDiff: true}
+	}
+	if olds.ExpectedDurationDays != news.ExpectedDurationDays {
+		diff["expectedDurationDays"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	return p.DiffResponse{HasChanges: len(diff) > 0, DeleteBeforeReplace: true, DetailedDiff: diff}, nil
+}
+
+func (FosterPlacement) Create(ctx context.Context, name string, input FosterPlacementArgs, preview bool) (string, FosterPlacementState, error) {
+	return fosterPlacements.create(ctx, name, input, preview)
+}
+
+func (FosterPlacement) Read(ctx context.Context, id string, inputs FosterPlacementArgs, state FosterPlacementState) (string, FosterPlacementArgs, FosterPlacementState, error) {
+	return fosterPlacements.read(ctx, id, inputs, state)
+}
+
+func (FosterPlacement) Update(ctx context.Context, id string, oldState FosterPlacementState, input FosterPlacementArgs, preview bool) (FosterPlacementState, error) {
+	return fosterPlacements.update(ctx, id, oldState, input, preview)
+}
+
+func (FosterPlacement) Delete(ctx context.Context, id string, state FosterPlacementState) error {
+	trackPlacement(&state, registry.Now(ctx))
+	err := recordAudit(ctx, state.DogID, AuditEntry{
+		Action:  "foster-return",
+		DogID:   state.DogID,
+		Message: fmt.Sprintf("Returned to the shelter after %d days in foster care (%s)", state.DaysInFoster, id),
+	})
+	if err != nil {
+		return err
+	}
+	return fosterPlacements.delete(ctx, id, state)
+}
+
+// trackPlacement works out when the dog is due back and how the placement
+// stands on now's day
+func trackPlacement(state *FosterPlacementState, now time.Time) {
+	start, err := time.Parse(dateLayout, state.StartDate)
+	if err != nil {
+		return
+	}
+	due := start.AddDate(0, 0, state.ExpectedDurationDays)
+	state.ExpectedReturn = due.Format(dateLayout)
+
+	today := now.UTC().Truncate(24 * time.Hour)
+	state.DaysInFoster = int(math.Max(0, today.Sub(start).Hours()/24))
+	switch {
+	case today.Before(start):
+		state.Status = "scheduled"
+	case today.After(due):
+		state.Status = "overdue"
+	default:
+		state.Status = "active"
+	}
+}
@@ -0,0 +1,46 @@
+// Code generated by genstate from foster_placement.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// FosterPlacementOutputs are computed by the provider; Check rejects them as inputs
+type FosterPlacementOutputs struct {
+	ID             string `pulumi:"id"`
+	PlacedAt       string `pulumi:"placedAt"`
+	ExpectedReturn string `pulumi:"expectedReturn"`
+	Status         string `pulumi:"status"`
+	DaysInFoster   int    `pulumi:"daysInFoster"`
+	Version        int64  `pulumi:"version"`
+}
+
+// FosterPlacementState echoes the inputs next to the computed outputs
+type FosterPlacementState struct {
+	FosterPlacementArgs
+	FosterPlacementOutputs
+}
+
+// newFosterPlacementState copies the inputs into an otherwise empty state
+func newFosterPlacementState(input FosterPlacementArgs) FosterPlacementState {
+	return FosterPlacementState{FosterPlacementArgs: input}
+}
+
+func (s *FosterPlacementState) stamp(id, created string)   { s.ID, s.PlacedAt = id, created }
+func (s *FosterPlacementState) identity() (string, string) { return s.ID, s.PlacedAt }
+func (s *FosterPlacementState) setVersion(version int64)   { s.Version = version }
+func (s *FosterPlacementState) storedVersion() int64       { return s.Version }
+
+func (args *FosterPlacementArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.StartDate, "YYYY-MM-DD")
+	a.Describe(&args.ExpectedDurationDays, "How long the dog is expected to stay")
+}
+
+func (state *FosterPlacementState) Annotate(a infer.Annotator) {
+	state.FosterPlacementArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the placement")
+	a.Describe(&state.PlacedAt, "When the placement was made")
+	a.Describe(&state.ExpectedReturn, "The day the dog is expected back, YYYY-MM-DD")
+	a.Describe(&state.Status, "scheduled before the start date, active during the placement, overdue after the expected return; recomputed on refresh")
+	a.Describe(&state.DaysInFoster, "Days since the start date, 0 before it; recomputed on refresh")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
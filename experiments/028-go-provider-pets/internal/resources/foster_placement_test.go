@@ -0,0 +1,33 @@
+package resources
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrackPlacement(t *testing.T) {
+	tests := []struct {
+		name   string
+		now    time.Time
+		status string
+		days   int
+	}{
+		{name: "before the start", now: time.Date(2026, 9, 30, 18, 0, 0, 0, time.UTC), status: "scheduled"},
+		{name: "first day", now: time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC), status: "active"},
+		{name: "due back today", now: time.Date(2026, 10, 15, 23, 0, 0, 0, time.UTC), status: "active", days: 14},
+		{name: "overdue", now: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC), status: "overdue", days: 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var state FosterPlacementState
+			state.StartDate, state.ExpectedDurationDays = "2026-10-01", 14
+			trackPlacement(&state, tt.now)
+			if state.ExpectedReturn != "2026-10-15" {
+				t.Errorf("expectedReturn = %s, want 2026-10-15", state.ExpectedReturn)
+			}
+			if state.Status != tt.status || state.DaysInFoster != tt.days {
+				t.Errorf("status %s after %d days, want %s after %d", state.Status, state.DaysInFoster, tt.status, tt.days)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+)
+
+// FoundPet is what someone who found a dog can tell about it; anything
+// they can't is left unset
+type FoundPet struct {
+	Breed      *DogBreed `pulumi:"breed,optional"`
+	Size       *PetSize  `pulumi:"size,optional"`
+	Color      *string   `pulumi:"color,optional"`
+	ChipNumber *string   `pulumi:"chipNumber,optional"` // The 15 digits, when a scanner read a chip
+	Location   LatLng    `pulumi:"location"`            // Where the dog was found
+}
+
+// FoundPetMatch is a lost dog the found one may be, with what points to it
+type FoundPetMatch struct {
+	ReportID      string   `pulumi:"reportId"`
+	DogID         string   `pulumi:"dogId"`
+	DogName       string   `pulumi:"dogName"`
+	Score         int      `pulumi:"score"` // Out of 100; a microchip match scores 100
+	DistanceMiles float64  `pulumi:"distanceMiles"`
+	Reasons       []string `pulumi:"reasons"`
+}
+
+// What each attribute adds to a match's score. A dog found well outside a
+// report's radius still gets some credit, since dogs keep moving.
+const (
+	breedMatchPoints   = 35
+	sizeMatchPoints    = 15
+	colorMatchPoints   = 20
+	nearbyPoints       = 30
+	outsideRadiusReach = 3
+)
+
+// MatchFoundPet scores the dogs of the open and sighted LostPetReports
+// against a found one, best match first. Reports nothing points to are
+// left out, and so are dogs chipped with another number than the one
+// scanned.
+func MatchFoundPet(ctx context.Context, found FoundPet) ([]FoundPetMatch, error) {
+	if reason := checkLatLng(found.Location); reason != "" {
+		return nil, fmt.Errorf("location: %s", reason)
+	}
+	if found.ChipNumber != nil && !validChipNumber(*found.ChipNumber) {
+		return nil, fmt.Errorf("chipNumber must be 15 digits")
+	}
+	reports, err := listRecords[LostPetReportState](ctx, backend.Query{Kind: "lost-pet"})
+	if err != nil {
+		return nil, err
+	}
+	chips, err := listRecords[MicrochipState](ctx, backend.Query{Kind: "microchip"})
+	if err != nil {
+		return nil, err
+	}
+	chipOf := map[string]string{}
+	for _, chip := range chips {
+		chipOf[chip.DogID] = chip.ChipNumber
+	}
+
+	matches := []FoundPetMatch{}
+	for _, report := range reports {
+		if report.Status != nil && *report.Status != LostOpen && *report.Status != LostSighted {
+			continue
+		}
+		dog, err := walkedDog(ctx, report.DogID)
+		if err != nil {
+			continue // the dog has left the registry since
+		}
+		if match, ok := scoreFoundPet(found, report, dog, chipOf[dog.ID]); ok {
+			matches = append(matches, match)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].DistanceMiles < matches[j].DistanceMiles
+	})
+	return matches, nil
+}
+
+// scoreFoundPet weighs up whether the found dog is the reported one. chip
+// is the reported dog's microchip number, "" when it has none on record.
+func scoreFoundPet(found FoundPet, report LostPetReportState, dog DogState, chip string) (FoundPetMatch, bool) {
+	miles := round2(distanceMeters(found.Location, report.LastSeenLocation) / metersPerMile)
+	match := FoundPetMatch{ReportID: report.ID, DogID: dog.ID, DogName: dog.Name, DistanceMiles: miles}
+
+	if found.ChipNumber != nil && chip != "" {
+		if *found.ChipNumber != chip {
+			return match, false
+		}
+		match.Score, match.Reasons = 100, []string{fmt.Sprintf("microchip %s is registered to %s", chip, dog.Name)}
+		return match, true
+	}
+
+	if found.Breed != nil && *found.Breed == dog.Breed {
+		match.Score += breedMatchPoints
+		match.Reasons = append(match.Reasons, "same breed: "+BreedCatalog[dog.Breed].Name)
+	}
+	size := determineSizeByBreed(dog.Breed)
+	if dog.Size != nil {
+		size = *dog.Size
+	}
+	if found.Size != nil && *found.Size == size {
+		match.Score += sizeMatchPoints
+		match.Reasons = append(match.Reasons, "same size: "+string(size))
+	}
+	if found.Color != nil && dog.Color != nil {
+		switch shared := colorOverlap(*found.Color, *dog.Color); {
+		case shared == 1:
+			match.Score += colorMatchPoints
+			match.Reasons = append(match.Reasons, "coat color matches: "+*dog.Color)
+		case shared > 0:
+			match.Score += colorMatchPoints / 2
+			match.Reasons = append(match.Reasons, "coat color partly matches: "+*dog.Color)
+		}
+	}
+	switch {
+	case miles <= report.RadiusMiles:
+		match.Score += nearbyPoints
+		match.Reasons = append(match.Reasons, fmt.Sprintf("found %g miles from where it was last seen, inside the search radius", miles))
+	case miles <= outsideRadiusReach*report.RadiusMiles:
+		match.Score += nearbyPoints / 2
+		match.Reasons = append(match.Reasons, fmt.Sprintf("found %g miles from where it was last seen, beyond the search radius", miles))
+	}
+	return match, match.Score > 0
+}
+
+// colorOverlap is the share of the words describing the found dog's coat
+// that also describe the lost dog's, ignoring filler like "and"
+func colorOverlap(found, lost string) float64 {
+	words := func(color string) []string {
+		var out []string
+		for _, word := range strings.FieldsFunc(strings.ToLower(color), func(r rune) bool {
+			return r == ' ' || r == ',' || r == '/' || r == '-' || r == '&'
+		}) {
+			if word != "and" && word != "with" {
+				out = append(out, word)
+			}
+		}
+		return out
+	}
+	lostWords := map[string]bool{}
+	for _, word := range words(lost) {
+		lostWords[word] = true
+	}
+	foundWords := words(found)
+	if len(foundWords) == 0 {
+		return 0
+	}
+	shared := 0
+	for _, word := range foundWords {
+		if lostWords[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(foundWords))
+}
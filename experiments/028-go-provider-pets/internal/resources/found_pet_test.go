@@ -0,0 +1,53 @@
+package resources
+
+import "testing"
+
+func TestColorOverlap(t *testing.T) {
+	tests := []struct {
+		found, lost string
+		want        float64
+	}{
+		{found: "Black and tan", lost: "black/tan", want: 1},
+		{found: "black", lost: "black & white", want: 1},
+		{found: "brown, white", lost: "white", want: 0.5},
+		{found: "golden", lost: "black", want: 0},
+		{found: "and", lost: "black", want: 0},
+	}
+	for _, tt := range tests {
+		if got := colorOverlap(tt.found, tt.lost); got != tt.want {
+			t.Errorf("colorOverlap(%q, %q) = %v, want %v", tt.found, tt.lost, got, tt.want)
+		}
+	}
+}
+
+func TestScoreFoundPet(t *testing.T) {
+	var report LostPetReportState
+	report.ID, report.LastSeenLocation, report.RadiusMiles = "lost-1", LatLng{Lat: 40.7, Lng: -74.0}, 2
+	dog := DogState{DogArgs: DogArgs{Name: "Rex", Breed: Beagle, Color: stringPtr("tricolor")}}
+	dog.ID = "dog-rex"
+	beagle, small := Beagle, Small
+	here, further, away := LatLng{Lat: 40.71, Lng: -74.0}, LatLng{Lat: 40.77, Lng: -74.0}, LatLng{Lat: 41.5, Lng: -74.0}
+
+	tests := []struct {
+		name    string
+		found   FoundPet
+		chip    string
+		score   int
+		matched bool
+	}{
+		{name: "chip matches", found: FoundPet{ChipNumber: stringPtr("985112345678901"), Location: away}, chip: "985112345678901", score: 100, matched: true},
+		{name: "chipped as another dog", found: FoundPet{Breed: &beagle, ChipNumber: stringPtr("985112345678901"), Location: here}, chip: "985100000000000"},
+		{name: "everything but the chip", found: FoundPet{Breed: &beagle, Color: stringPtr("Tricolor"), Location: here}, score: 85, matched: true},
+		{name: "chip scanned, none on record", found: FoundPet{Breed: &beagle, ChipNumber: stringPtr("985112345678901"), Location: here}, score: 65, matched: true},
+		{name: "beyond the radius", found: FoundPet{Size: &small, Location: further}, score: 15, matched: true},
+		{name: "nothing in common", found: FoundPet{Size: &small, Location: away}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, ok := scoreFoundPet(tt.found, report, dog, tt.chip)
+			if ok != tt.matched || (ok && match.Score != tt.score) {
+				t.Errorf("score %d (matched %v), want %d (matched %v); reasons %v", match.Score, ok, tt.score, tt.matched, match.Reasons)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/validate"
+)
+
+// FuzzCheck feeds arbitrary JSON objects to every resource's Check, and
+// whatever Check accepts to a preview Create, which must never panic.
+//
+//	make fuzz FUZZTIME=5m
+func FuzzCheck(f *testing.F) {
+	for _, seed := range []string{
+		`{}`,
+		`{"name": "Rex", "breed": "beagle", "ownerName": "Sam"}`,
+		`{"name": "Rex", "breed": "beagle", "ownerName": "Sam", "age": -4, "size": "huge", "weight": null}`,
+		`{"name": "Rex", "breed": "wolf", "ownerName": "Sam", "trainingLevel": "", "tags": [null, 3]}`,
+		`{"dogId": "dog-rex-1", "duration": 0, "distance": 1e308, "weather": "sunny"}`,
+		`{"dogId": "dog-rex-1", "visitType": "surgery", "vetName": "Vet", "clinicName": "Clinic", "cost": -1}`,
+		`{"dogId": "dog-rex-1", "adopterName": "Sam", "adopterContact": "sam@example.com", "reviewSeconds": -5}`,
+		`{"shelterId": "shelter", "dogs": [{"name": "Rex"}, {}, null, "Rex"]}`,
+		`{"label": "nightly", "trigger": 7}`,
+		`{"id": "dog-rex-1", "version": 3, "registrationDate": "yesterday"}`,
+	} {
+		f.Add(seed)
+	}
+
+	ctx := context.Background()
+	f.Fuzz(func(t *testing.T, data string) {
+		var raw map[string]any
+		if json.Unmarshal([]byte(data), &raw) != nil {
+			return
+		}
+		inputs := resource.NewPropertyMapFromMap(raw)
+
+		if args, failures, err := (Dog{}).Check(ctx, "rex", nil, inputs); err == nil && len(failures) == 0 {
+			_, _, _ = Dog{}.Create(ctx, "rex", args, true)
+		}
+		if args, failures, err := (DogWalk{}).Check(ctx, "walk", nil, inputs); err == nil && len(failures) == 0 {
+			_, _, _ = DogWalk{}.Create(ctx, "walk", args, true)
+		}
+		if args, failures, err := (VeterinaryVisit{}).Check(ctx, "visit", nil, inputs); err == nil && len(failures) == 0 {
+			_, _, _ = VeterinaryVisit{}.Create(ctx, "visit", args, true)
+		}
+		if args, failures, err := (Adoption{}).Check(ctx, "adoption", nil, inputs); err == nil && len(failures) == 0 {
+			_, _, _ = Adoption{}.Create(ctx, "adoption", args, true)
+		}
+		if args, failures, err := (BulkDogIntake{}).Check(ctx, "intake", nil, inputs); err == nil && len(failures) == 0 {
+			_, _, _ = BulkDogIntake{}.Create(ctx, "intake", args, true)
+		}
+		if args, failures, err := (RegistrySnapshot{}).Check(ctx, "snapshot", nil, inputs); err == nil && len(failures) == 0 {
+			_, _, _ = RegistrySnapshot{}.Create(ctx, "snapshot", args, true)
+		}
+	})
+}
+
+// FuzzRegisterDog builds DogArgs from arbitrary values, with each optional
+// field set or not by a bit of mask, and runs whatever passes validation
+// through registration and an update.
+func FuzzRegisterDog(f *testing.F) {
+	f.Add("Rex", "beagle", "Sam", 4, 12.5, "large", "advanced", "not a timestamp", uint8(0))
+	f.Add("Good Boy", "unknown-breed", "Sam", 0, 0.0, "", "", "", uint8(0xff))
+	f.Add(" ", "", "", -1, -3.0, "tiny", "expert", "2024-13-45T99:99:99Z", uint8(0x0f))
+
+	now := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	f.Fuzz(func(t *testing.T, name, breed, owner string, age int, weight float64, size, training, lastFed string, mask uint8) {
+		args := DogArgs{Name: name, Breed: DogBreed(breed), OwnerName: owner}
+		if mask&1 != 0 {
+			args.Age = &age
+		}
+		if mask&2 != 0 {
+			args.Weight = &weight
+		}
+		if mask&4 != 0 {
+			s := PetSize(size)
+			args.Size = &s
+		}
+		if mask&8 != 0 {
+			level := TrainingLevel(training)
+			args.TrainingLevel = &level
+		}
+		if len(validate.Struct(&args)) > 0 {
+			return
+		}
+
+		state := registeredDog(args, now, "1")
+		if !strings.HasPrefix(state.ID, "dog-") {
+			t.Errorf("ID %q lacks the dog- prefix", state.ID)
+		}
+		if state.Age == nil || state.Size == nil || state.Weight == nil || state.IsGoodBoy == nil ||
+			state.Microchipped == nil || state.TrainingLevel == nil {
+			t.Errorf("registered dog has unset optional inputs: %+v", state.DogArgs)
+		}
+
+		state.LastFed = lastFed
+		updated := newDogState(args)
+		carryDogState(&updated, state, now)
+		if updated.LastFed != lastFed {
+			t.Errorf("LastFed = %q, want it carried over as %q", updated.LastFed, lastFed)
+		}
+	})
+}
+
+// FuzzWalk checks that every walk passing validation gets a sensible
+// calorie estimate and enjoyment rating.
+func FuzzWalk(f *testing.F) {
+	f.Add(30, 1.5, "sunny", true)
+	f.Add(600, 50.0, "", false)
+	f.Add(-1, -0.5, "stormy", true)
+
+	f.Fuzz(func(t *testing.T, duration int, distance float64, weather string, hasWeather bool) {
+		args := DogWalkArgs{DogID: "dog-rex-1", Duration: duration, Distance: distance}
+		if hasWeather {
+			w := Weather(weather)
+			args.Weather = &w
+		}
+		if len(validate.Struct(&args)) > 0 {
+			return
+		}
+
+		if calories := walkCalories(walkingMET(walkPace(args)), 30, duration); calories < 0 {
+			t.Errorf("walkCalories(%d min, %g mi) = %d, want at least 0", duration, distance, calories)
+		}
+		dog := DogState{DogArgs: DogArgs{Breed: Husky, Age: intPtr(duration % 20)}}
+		weights := registry.EnjoymentWeights{BreedEnergy: 0.35, Weather: 0.25, Pace: 0.2, Age: 0.2}
+		score, enjoyment := walkEnjoyment(args, dog, weights)
+		if score < 0 || score > 100 {
+			t.Errorf("enjoyment score = %d, want 0 to 100", score)
+		}
+		switch enjoyment {
+		case "low", "medium", "high":
+		default:
+			t.Errorf("walkEnjoyment = %q", enjoyment)
+		}
+	})
+}
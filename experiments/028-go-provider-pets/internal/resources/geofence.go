@@ -0,0 +1,182 @@
+package resources
+
+import (
+	"fmt"
+	"math"
+
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+// LatLng is a WGS84 position in decimal degrees
+type LatLng struct {
+	Lat float64 `pulumi:"lat" json:"lat"`
+	Lng float64 `pulumi:"lng" json:"lng"`
+}
+
+// Geofence is an area a collared dog is expected to stay in: either a circle
+// (center and radiusMeters) or a polygon of at least three vertices.
+type Geofence struct {
+	Name         string   `pulumi:"name" json:"name"`
+	Center       *LatLng  `pulumi:"center,optional" json:"center,omitempty"`
+	RadiusMeters *float64 `pulumi:"radiusMeters,optional" json:"radiusMeters,omitempty"`
+	Polygon      []LatLng `pulumi:"polygon,optional" json:"polygon,omitempty"`
+}
+
+const (
+	// earthRadiusMeters is the mean radius haversine distances use
+	earthRadiusMeters = 6371000
+	// maxFenceRadiusMeters keeps circles to something a dog could roam
+	maxFenceRadiusMeters = 50000
+)
+
+// checkGeofences reports fences with missing, mixed or degenerate geometry
+func checkGeofences(fences []Geofence) []p.CheckFailure {
+	var failures []p.CheckFailure
+	fail := func(property, reason string) {
+		failures = append(failures, p.CheckFailure{Property: property, Reason: reason})
+	}
+	seen := map[string]bool{}
+	for i, fence := range fences {
+		at := fmt.Sprintf("geofences[%d]", i)
+		switch {
+		case fence.Name == "":
+			fail(at+".name", "geofences need a name")
+		case seen[fence.Name]:
+			fail(at+".name", fmt.Sprintf("geofence %q is already defined", fence.Name))
+		}
+		seen[fence.Name] = true
+
+		circle := fence.Center != nil || fence.RadiusMeters != nil
+		switch {
+		case circle && len(fence.Polygon) > 0:
+			fail(at, "a geofence is either a circle or a polygon, not both")
+		case circle:
+			if fence.Center == nil {
+				fail(at+".center", "a circular geofence needs a center")
+			} else if reason := checkLatLng(*fence.Center); reason != "" {
+				fail(at+".center", reason)
+			}
+			if fence.RadiusMeters == nil {
+				fail(at+".radiusMeters", "a circular geofence needs a radius")
+			} else if r := *fence.RadiusMeters; r <= 0 || r > maxFenceRadiusMeters {
+				fail(at+".radiusMeters", fmt.Sprintf("radiusMeters must be greater than 0 and at most %d", maxFenceRadiusMeters))
+			}
+		case len(fence.Polygon) > 0:
+			for _, reason := range checkPolygon(fence.Polygon) {
+				fail(at+".polygon", reason)
+			}
+		default:
+			fail(at, "a geofence needs a center and radiusMeters or a polygon")
+		}
+	}
+	return failures
+}
+
+func checkLatLng(point LatLng) string {
+	if point.Lat < -90 || point.Lat > 90 || point.Lng < -180 || point.Lng > 180 {
+		return fmt.Sprintf("%g,%g is not a valid latitude and longitude", point.Lat, point.Lng)
+	}
+	return ""
+}
+
+// checkPolygon reports vertices out of range, too few vertices and polygons
+// that enclose nothing or cross themselves
+func checkPolygon(polygon []LatLng) []string {
+	var reasons []string
+	for _, vertex := range polygon {
+		if reason := checkLatLng(vertex); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+	if len(reasons) > 0 {
+		return reasons
+	}
+	if len(polygon) < 3 {
+		return []string{"a polygon needs at least three vertices"}
+	}
+	if polygonArea(polygon) == 0 {
+		return []string{"the polygon encloses no area"}
+	}
+	n := len(polygon)
+	for i := 0; i < n; i++ {
+		// Edges sharing a vertex always touch, so only compare the rest
+		for j := i + 2; j < n; j++ {
+			if i == 0 && j == n-1 {
+				continue
+			}
+			if segmentsCross(polygon[i], polygon[(i+1)%n], polygon[j], polygon[(j+1)%n]) {
+				return []string{fmt.Sprintf("the polygon crosses itself between edges %d and %d", i, j)}
+			}
+		}
+	}
+	return nil
+}
+
+// polygonArea is the shoelace area in square degrees; only its sign and
+// whether it is zero mean anything
+func polygonArea(polygon []LatLng) float64 {
+	var area float64
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		area += a.Lng*b.Lat - b.Lng*a.Lat
+	}
+	return area / 2
+}
+
+// segmentsCross reports whether segments ab and cd intersect, touching
+// included
+func segmentsCross(a, b, c, d LatLng) bool {
+	orient := func(p, q, r LatLng) float64 {
+		return (q.Lng-p.Lng)*(r.Lat-p.Lat) - (q.Lat-p.Lat)*(r.Lng-p.Lng)
+	}
+	onSegment := func(p, q, r LatLng) bool {
+		return math.Min(p.Lng, r.Lng) <= q.Lng && q.Lng <= math.Max(p.Lng, r.Lng) &&
+			math.Min(p.Lat, r.Lat) <= q.Lat && q.Lat <= math.Max(p.Lat, r.Lat)
+	}
+	d1, d2 := orient(c, d, a), orient(c, d, b)
+	d3, d4 := orient(a, b, c), orient(a, b, d)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return (d1 == 0 && onSegment(c, a, d)) || (d2 == 0 && onSegment(c, b, d)) ||
+		(d3 == 0 && onSegment(a, c, b)) || (d4 == 0 && onSegment(a, d, b))
+}
+
+// distanceMeters is the great-circle distance between two positions
+func distanceMeters(a, b LatLng) float64 {
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat, dLng := rad(b.Lat-a.Lat), rad(b.Lng-a.Lng)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(rad(a.Lat))*math.Cos(rad(b.Lat))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(h))
+}
+
+// contains reports whether point is inside the fence. Polygons are treated
+// as flat, which is fine at the scale of a yard or a park.
+func (fence Geofence) contains(point LatLng) bool {
+	if fence.Center != nil && fence.RadiusMeters != nil {
+		return distanceMeters(*fence.Center, point) <= *fence.RadiusMeters
+	}
+	inside := false
+	n := len(fence.Polygon)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		a, b := fence.Polygon[i], fence.Polygon[j]
+		if (a.Lat > point.Lat) != (b.Lat > point.Lat) &&
+			point.Lng < (b.Lng-a.Lng)*(point.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// anchor is a point in the middle of the fence
+func (fence Geofence) anchor() LatLng {
+	if fence.Center != nil {
+		return *fence.Center
+	}
+	var mid LatLng
+	for _, vertex := range fence.Polygon {
+		mid.Lat += vertex.Lat / float64(len(fence.Polygon))
+		mid.Lng += vertex.Lng / float64(len(fence.Polygon))
+	}
+	return mid
+}
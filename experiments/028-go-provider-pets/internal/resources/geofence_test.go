@@ -0,0 +1,118 @@
+package resources
+
+import (
+	"math"
+	"testing"
+)
+
+// park is a square of roughly 110 m a side
+var park = []LatLng{{Lat: 47.6000, Lng: -122.3300}, {Lat: 47.6000, Lng: -122.3285}, {Lat: 47.6010, Lng: -122.3285}, {Lat: 47.6010, Lng: -122.3300}}
+
+func TestCheckGeofences(t *testing.T) {
+	home := &LatLng{Lat: 47.61, Lng: -122.33}
+	tests := []struct {
+		name   string
+		fences []Geofence
+		failed []string
+	}{
+		{
+			name:   "circle and polygon",
+			fences: []Geofence{{Name: "home", Center: home, RadiusMeters: floatPtr(50)}, {Name: "park", Polygon: park}},
+		},
+		{
+			name:   "duplicate and missing names",
+			fences: []Geofence{{Name: "home", Center: home, RadiusMeters: floatPtr(50)}, {Name: "home", Polygon: park}, {Polygon: park}},
+			failed: []string{"geofences[1].name", "geofences[2].name"},
+		},
+		{
+			name:   "no geometry",
+			fences: []Geofence{{Name: "home"}},
+			failed: []string{"geofences[0]"},
+		},
+		{
+			name:   "both shapes",
+			fences: []Geofence{{Name: "home", Center: home, RadiusMeters: floatPtr(50), Polygon: park}},
+			failed: []string{"geofences[0]"},
+		},
+		{
+			name:   "circle without radius",
+			fences: []Geofence{{Name: "home", Center: home}},
+			failed: []string{"geofences[0].radiusMeters"},
+		},
+		{
+			name:   "radius out of range",
+			fences: []Geofence{{Name: "home", Center: home, RadiusMeters: floatPtr(0)}, {Name: "county", Center: home, RadiusMeters: floatPtr(80000)}},
+			failed: []string{"geofences[0].radiusMeters", "geofences[1].radiusMeters"},
+		},
+		{
+			name:   "center off the globe",
+			fences: []Geofence{{Name: "home", Center: &LatLng{Lat: 91, Lng: 0}, RadiusMeters: floatPtr(50)}},
+			failed: []string{"geofences[0].center"},
+		},
+		{
+			name:   "two vertices",
+			fences: []Geofence{{Name: "path", Polygon: park[:2]}},
+			failed: []string{"geofences[0].polygon"},
+		},
+		{
+			name:   "collinear",
+			fences: []Geofence{{Name: "line", Polygon: []LatLng{{Lat: 0, Lng: 0}, {Lat: 1, Lng: 1}, {Lat: 2, Lng: 2}}}},
+			failed: []string{"geofences[0].polygon"},
+		},
+		{
+			name:   "bow tie",
+			fences: []Geofence{{Name: "bow", Polygon: []LatLng{park[0], park[2], park[1], park[3]}}},
+			failed: []string{"geofences[0].polygon"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := checkGeofences(tt.fences)
+			if len(failures) != len(tt.failed) {
+				t.Fatalf("failures = %+v, want %v", failures, tt.failed)
+			}
+			for i, f := range failures {
+				if f.Property != tt.failed[i] {
+					t.Errorf("failure %d on %q, want %q", i, f.Property, tt.failed[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGeofenceContains(t *testing.T) {
+	home := Geofence{Name: "home", Center: &LatLng{Lat: 47.61, Lng: -122.33}, RadiusMeters: floatPtr(100)}
+	square := Geofence{Name: "park", Polygon: park}
+	// An L shape whose notch is outside
+	ell := Geofence{Name: "yard", Polygon: []LatLng{{Lat: 0, Lng: 0}, {Lat: 0, Lng: 2}, {Lat: 1, Lng: 2}, {Lat: 1, Lng: 1}, {Lat: 2, Lng: 1}, {Lat: 2, Lng: 0}}}
+
+	tests := []struct {
+		name  string
+		fence Geofence
+		point LatLng
+		want  bool
+	}{
+		{name: "circle center", fence: home, point: *home.Center, want: true},
+		{name: "inside circle", fence: home, point: LatLng{Lat: 47.6105, Lng: -122.33}, want: true},
+		{name: "outside circle", fence: home, point: LatLng{Lat: 47.612, Lng: -122.33}},
+		{name: "inside square", fence: square, point: LatLng{Lat: 47.6005, Lng: -122.3290}, want: true},
+		{name: "outside square", fence: square, point: LatLng{Lat: 47.6015, Lng: -122.3290}},
+		{name: "inside L", fence: ell, point: LatLng{Lat: 0.5, Lng: 1.5}, want: true},
+		{name: "in the notch", fence: ell, point: LatLng{Lat: 1.5, Lng: 1.5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fence.contains(tt.point); got != tt.want {
+				t.Errorf("contains(%+v) = %v, want %v", tt.point, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistanceMeters(t *testing.T) {
+	// A degree of latitude is about 111 km
+	got := distanceMeters(LatLng{Lat: 0, Lng: 0}, LatLng{Lat: 1, Lng: 0})
+	if math.Abs(got-111195) > 10 {
+		t.Errorf("distance = %.0f m, want about 111195", got)
+	}
+}
@@ -0,0 +1,279 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// GpsCollar Resource - a tracking collar and the geofences its dog should
+// stay within. The fences are kept in the registry beside the collar;
+// battery and location come from the trackingApi, or a simulator, on Read.
+// Each Read also checks the location against the fences: a dog outside all
+// of them is in breach, and leaving or coming back goes in the collar's
+// audit log and out to the NotificationChannels subscribed to geofence
+// events.
+type GpsCollar struct{}
+
+//pets:state id=ID created=CreatedAt
+//pets:output ID string id Generated identifier of the collar
+//pets:output CreatedAt string createdAt When the collar was registered
+//pets:output BatteryLevel int batteryLevel Battery charge the collar last reported, in percent
+//pets:output LastLocation *LatLng lastLocation Where the collar last reported being
+//pets:output LastSeenAt string lastSeenAt When the collar last reported in
+//pets:output Online bool online Whether the collar answered the last Read
+//pets:output CurrentFence string currentFence Name of the fence the dog was last inside; empty when outside them all
+//pets:output Breached bool breached Whether the dog was outside every fence at the last check
+type GpsCollarArgs struct {
+	DogID     string     `pulumi:"dogId" validate:"required"`
+	DeviceID  string     `pulumi:"deviceId" validate:"required"`
+	Geofences []Geofence `pulumi:"geofences,optional"` // Areas the dog should stay within
+}
+
+// geofenceSet is the registry record holding a collar's fences and whether
+// the dog was last seen outside them
+type geofenceSet struct {
+	CollarID   string     `json:"collarId"`
+	DogID      string     `json:"dogId"`
+	Fences     []Geofence `json:"fences"`
+	Breached   bool       `json:"breached"`
+	BreachedAt string     `json:"breachedAt,omitempty"`
+}
+
+// collarReport is a collar's position as the tracking API returns it
+type collarReport struct {
+	BatteryLevel int     `json:"batteryLevel"`
+	Location     *LatLng `json:"location"`
+	ReportedAt   string  `json:"reportedAt"`
+}
+
+const (
+	// simulatedRoamMeters is how far a simulated dog wanders from the
+	// middle of its first fence
+	simulatedRoamMeters = 30
+	// simulatedDrainPerHour is the battery a simulated collar uses an hour;
+	// it is put back on the charger at 20%
+	simulatedDrainPerHour = 2
+)
+
+var collars = crudResource[GpsCollarArgs, GpsCollarState, *GpsCollarState]{
+	kind:     stored[GpsCollarState]("collar"),
+	prefix:   "collar",
+	slug:     func(input GpsCollarArgs) string { return input.DogID },
+	newState: newGpsCollarState,
+	populate: func(ctx context.Context, state *GpsCollarState, input GpsCollarArgs) error {
+		if _, err := walkedDog(ctx, input.DogID); err != nil {
+			return err
+		}
+		if err := saveGeofences(ctx, *state); err != nil {
+			return err
+		}
+		return checkCollar(ctx, state)
+	},
+	carry: func(ctx context.Context, state *GpsCollarState, oldState GpsCollarState, now time.Time) error {
+		if err := saveGeofences(ctx, *state); err != nil {
+			return err
+		}
+		return checkCollar(ctx, state)
+	},
+	refresh: func(ctx context.Context, id string, state *GpsCollarState) error {
+		return checkCollar(ctx, state)
+	},
+	related: []string{"geofence"},
+}
+
+func (GpsCollar) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (GpsCollarArgs, []p.CheckFailure, error) {
+	args, failures, err := collars.check(newInputs)
+	return args, append(failures, checkGeofences(args.Geofences)...), err
+}
+
+func (GpsCollar) Create(ctx context.Context, name string, input GpsCollarArgs, preview bool) (string, GpsCollarState, error) {
+	return collars.create(ctx, name, input, preview)
+}
+
+func (GpsCollar) Read(ctx context.Context, id string, inputs GpsCollarArgs, state GpsCollarState) (string, GpsCollarArgs, GpsCollarState, error) {
+	return collars.read(ctx, id, inputs, state)
+}
+
+func (GpsCollar) Update(ctx context.Context, id string, oldState GpsCollarState, input GpsCollarArgs, preview bool) (GpsCollarState, error) {
+	return collars.update(ctx, id, oldState, input, preview)
+}
+
+func (GpsCollar) Delete(ctx context.Context, id string, state GpsCollarState) error {
+	return collars.delete(ctx, id, state)
+}
+
+// CheckGeofence locates a registered collar and checks it against its
+// fences, as a Read would. Breaches are recorded and announced, but the
+// collar's own state is left for the next refresh to update.
+func CheckGeofence(ctx context.Context, collarID string) (GpsCollarState, error) {
+	var state GpsCollarState
+	if _, err := registry.Load(ctx, "collar", collarID, &state); err != nil {
+		if errors.Is(err, backend.ErrNotFound) {
+			return state, fmt.Errorf("collar %q is not registered", collarID)
+		}
+		return state, err
+	}
+	return state, checkCollar(ctx, &state)
+}
+
+// saveGeofences stores the collar's fences under its ID, keeping track of
+// a breach in progress
+func saveGeofences(ctx context.Context, state GpsCollarState) error {
+	var fences geofenceSet
+	version, err := registry.Load(ctx, "geofence", state.ID, &fences)
+	if errors.Is(err, backend.ErrNotFound) {
+		version = 0
+	} else if err != nil {
+		return err
+	}
+	fences.CollarID, fences.DogID, fences.Fences = state.ID, state.DogID, state.Geofences
+	_, err = registry.Save(ctx, "geofence", state.ID, version, fences)
+	return err
+}
+
+// checkCollar locates the collar and evaluates its fences
+func checkCollar(ctx context.Context, state *GpsCollarState) error {
+	if err := trackCollar(ctx, state); err != nil {
+		return err
+	}
+	return evaluateGeofences(ctx, state)
+}
+
+// evaluateGeofences sets where the dog is relative to its fences. When it
+// has just left them all, or come back, the change is recorded in the
+// audit log and posted to the subscribed channels; a dog that stays out
+// is only reported once.
+func evaluateGeofences(ctx context.Context, state *GpsCollarState) error {
+	if state.LastLocation == nil || len(state.Geofences) == 0 {
+		state.CurrentFence, state.Breached = "", false
+		return nil
+	}
+	state.CurrentFence = fenceAt(state.Geofences, *state.LastLocation)
+	state.Breached = state.CurrentFence == ""
+
+	var fences geofenceSet
+	version, err := registry.Load(ctx, "geofence", state.ID, &fences)
+	if err != nil {
+		return err
+	}
+	if fences.Breached == state.Breached {
+		return nil
+	}
+	fences.Breached = state.Breached
+	if state.Breached {
+		fences.BreachedAt = state.LastSeenAt
+	}
+	if _, err := registry.Save(ctx, "geofence", state.ID, version, fences); err != nil {
+		return err
+	}
+
+	e := geofenceEvent(dogName(ctx, state.DogID), *state, fences.BreachedAt)
+	action := "geofence-breach"
+	if !state.Breached {
+		action = "geofence-return"
+	}
+	if err := recordAudit(ctx, state.ID, AuditEntry{Action: action, DogID: state.DogID, Message: e.Title}); err != nil {
+		return err
+	}
+	dispatch(ctx, e)
+	return nil
+}
+
+// fenceAt is the name of the first fence containing point, or empty
+func fenceAt(fences []Geofence, point LatLng) string {
+	for _, fence := range fences {
+		if fence.contains(point) {
+			return fence.Name
+		}
+	}
+	return ""
+}
+
+// geofenceEvent announces a dog leaving its fences or coming back inside
+func geofenceEvent(name string, state GpsCollarState, breachedAt string) event {
+	details := []string{
+		fmt.Sprintf("Last seen at %.5f, %.5f (%s)", state.LastLocation.Lat, state.LastLocation.Lng, state.LastSeenAt),
+		fmt.Sprintf("Collar battery: %d%%", state.BatteryLevel),
+	}
+	title := fmt.Sprintf("%s has left every geofence", name)
+	if !state.Breached {
+		title = fmt.Sprintf("%s is back inside %s", name, state.CurrentFence)
+		if breachedAt != "" {
+			details = append(details, "Outside since "+breachedAt)
+		}
+	}
+	return event{Kind: EventGeofence, DogID: state.DogID, Title: title, Details: details}
+}
+
+// trackCollar fills in battery and location from the tracking API, or the
+// simulator when none is configured. An unreachable collar keeps its last
+// known location and is marked offline.
+func trackCollar(ctx context.Context, state *GpsCollarState) error {
+	api := registry.TrackingAPI(ctx)
+	if api == nil {
+		applyCollarReport(state, simulatedCollar(*state, registry.Now(ctx)))
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, collarURL(api, state.DeviceID), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := deviceCall(ctx, api, req)
+	if err != nil {
+		p.GetLogger(ctx).Warningf("collar %s is offline: %v", state.DeviceID, err)
+		state.Online = false
+		return nil
+	}
+	defer resp.Body.Close()
+	var report collarReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return fmt.Errorf("collar %s report: %w", state.DeviceID, err)
+	}
+	applyCollarReport(state, report)
+	return nil
+}
+
+func applyCollarReport(state *GpsCollarState, report collarReport) {
+	state.BatteryLevel = report.BatteryLevel
+	if report.Location != nil {
+		state.LastLocation = report.Location
+		state.LastSeenAt = report.ReportedAt
+	}
+	state.Online = true
+}
+
+// simulatedCollar circles the middle of the first fence once an hour and
+// drains its battery steadily from the time it was registered
+func simulatedCollar(state GpsCollarState, now time.Time) collarReport {
+	report := collarReport{BatteryLevel: 100, ReportedAt: now.Format("2006-01-02T15:04:05Z")}
+	if created, err := time.Parse("2006-01-02T15:04:05Z", state.CreatedAt); err == nil && now.After(created) {
+		hours := int(now.Sub(created).Hours())
+		report.BatteryLevel = 100 - hours*simulatedDrainPerHour%80
+	}
+	if len(state.Geofences) == 0 {
+		return report
+	}
+	anchor := state.Geofences[0].anchor()
+	angle := 2 * math.Pi * float64(now.Minute()*60+now.Second()) / 3600
+	dLat := simulatedRoamMeters * math.Cos(angle) / earthRadiusMeters * 180 / math.Pi
+	dLng := simulatedRoamMeters * math.Sin(angle) / (earthRadiusMeters * math.Cos(anchor.Lat*math.Pi/180)) * 180 / math.Pi
+	report.Location = &LatLng{Lat: anchor.Lat + dLat, Lng: anchor.Lng + dLng}
+	return report
+}
+
+func collarURL(api *registry.DeviceAPIConfig, deviceID string) string {
+	return strings.TrimSuffix(api.BaseURL, "/") + "/collars/" + url.PathEscape(deviceID)
+}
@@ -0,0 +1,51 @@
+// Code generated by genstate from gps_collar.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// GpsCollarOutputs are computed by the provider; Check rejects them as inputs
+type GpsCollarOutputs struct {
+	ID           string  `pulumi:"id"`
+	CreatedAt    string  `pulumi:"createdAt"`
+	BatteryLevel int     `pulumi:"batteryLevel"`
+	LastLocation *LatLng `pulumi:"lastLocation"`
+	LastSeenAt   string  `pulumi:"lastSeenAt"`
+	Online       bool    `pulumi:"online"`
+	CurrentFence string  `pulumi:"currentFence"`
+	Breached     bool    `pulumi:"breached"`
+	Version      int64   `pulumi:"version"`
+}
+
+// GpsCollarState echoes the inputs next to the computed outputs
+type GpsCollarState struct {
+	GpsCollarArgs
+	GpsCollarOutputs
+}
+
+// newGpsCollarState copies the inputs into an otherwise empty state
+func newGpsCollarState(input GpsCollarArgs) GpsCollarState {
+	return GpsCollarState{GpsCollarArgs: input}
+}
+
+func (s *GpsCollarState) stamp(id, created string)   { s.ID, s.CreatedAt = id, created }
+func (s *GpsCollarState) identity() (string, string) { return s.ID, s.CreatedAt }
+func (s *GpsCollarState) setVersion(version int64)   { s.Version = version }
+func (s *GpsCollarState) storedVersion() int64       { return s.Version }
+
+func (args *GpsCollarArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Geofences, "Areas the dog should stay within")
+}
+
+func (state *GpsCollarState) Annotate(a infer.Annotator) {
+	state.GpsCollarArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the collar")
+	a.Describe(&state.CreatedAt, "When the collar was registered")
+	a.Describe(&state.BatteryLevel, "Battery charge the collar last reported, in percent")
+	a.Describe(&state.LastLocation, "Where the collar last reported being")
+	a.Describe(&state.LastSeenAt, "When the collar last reported in")
+	a.Describe(&state.Online, "Whether the collar answered the last Read")
+	a.Describe(&state.CurrentFence, "Name of the fence the dog was last inside; empty when outside them all")
+	a.Describe(&state.Breached, "Whether the dog was outside every fence at the last check")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
@@ -0,0 +1,97 @@
+package resources
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSimulatedCollar(t *testing.T) {
+	var state GpsCollarState
+	state.CreatedAt = "2026-06-15T08:00:00Z"
+	state.Geofences = []Geofence{{Name: "home", Center: &LatLng{Lat: 47.61, Lng: -122.33}, RadiusMeters: floatPtr(50)}}
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		battery int
+	}{
+		{name: "just registered", now: time.Date(2026, 6, 15, 8, 0, 0, 0, time.UTC), battery: 100},
+		{name: "ten hours on", now: time.Date(2026, 6, 15, 18, 15, 0, 0, time.UTC), battery: 80},
+		{name: "recharged", now: time.Date(2026, 6, 17, 0, 0, 0, 0, time.UTC), battery: 100 - 40*simulatedDrainPerHour%80},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := simulatedCollar(state, tt.now)
+			if report.BatteryLevel != tt.battery {
+				t.Errorf("battery = %d, want %d", report.BatteryLevel, tt.battery)
+			}
+			if report.Location == nil {
+				t.Fatal("no location reported")
+			}
+			// The simulated dog roams inside a 50 m fence
+			if d := distanceMeters(*state.Geofences[0].Center, *report.Location); d < simulatedRoamMeters-1 || d > simulatedRoamMeters+1 {
+				t.Errorf("dog is %.1f m from home, want %d", d, simulatedRoamMeters)
+			}
+			if !state.Geofences[0].contains(*report.Location) {
+				t.Errorf("dog at %+v left the fence", *report.Location)
+			}
+		})
+	}
+
+	state.Geofences = nil
+	if report := simulatedCollar(state, time.Date(2026, 6, 15, 9, 0, 0, 0, time.UTC)); report.Location != nil {
+		t.Errorf("location = %+v without fences to roam", *report.Location)
+	}
+}
+
+func TestFenceAt(t *testing.T) {
+	fences := []Geofence{
+		{Name: "home", Center: &LatLng{Lat: 47.61, Lng: -122.33}, RadiusMeters: floatPtr(100)},
+		{Name: "park", Polygon: park},
+	}
+	tests := []struct {
+		name  string
+		point LatLng
+		want  string
+	}{
+		{name: "at home", point: LatLng{Lat: 47.6101, Lng: -122.33}, want: "home"},
+		{name: "at the park", point: LatLng{Lat: 47.6005, Lng: -122.3290}, want: "park"},
+		{name: "on the way", point: LatLng{Lat: 47.605, Lng: -122.33}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fenceAt(fences, tt.point); got != tt.want {
+				t.Errorf("fenceAt = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeofenceEvent(t *testing.T) {
+	var state GpsCollarState
+	state.DogID, state.BatteryLevel, state.LastSeenAt = "dog-rex", 64, "2026-06-15T18:05:00Z"
+	state.LastLocation = &LatLng{Lat: 47.605, Lng: -122.33}
+
+	tests := []struct {
+		name     string
+		fence    string
+		breached bool
+		title    string
+		details  int
+	}{
+		{name: "left", breached: true, title: "Rex has left every geofence", details: 2},
+		{name: "back", fence: "home", title: "Rex is back inside home", details: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state.CurrentFence, state.Breached = tt.fence, tt.breached
+			e := geofenceEvent("Rex", state, "2026-06-15T17:40:00Z")
+			if e.Kind != EventGeofence || e.DogID != "dog-rex" || e.Title != tt.title {
+				t.Errorf("event = %+v, want %q", e, tt.title)
+			}
+			if len(e.Details) != tt.details {
+				t.Errorf("details = %q, want %d lines", e.Details, tt.details)
+			}
+		})
+	}
+}
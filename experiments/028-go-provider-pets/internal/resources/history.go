@@ -0,0 +1,69 @@
+package resources
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// historyWindow is how many of the most recent entries of each history a
+// dog's state carries. The rest stays in the registry, out of every Read
+// and Update, and is available through the getFullHistory function.
+const historyWindow = 10
+
+// DogHistory is the registry record holding a dog's complete histories,
+// stored under the dog's ID.
+type DogHistory struct {
+	BehaviorNotes  []BehaviorNote `json:"behaviorNotes"`
+	MedicalHistory []string       `json:"medicalHistory"`
+}
+
+// recent is the tail of entries the state carries
+func recent[T any](entries []T) []T {
+	if len(entries) <= historyWindow {
+		return entries
+	}
+	return append([]T(nil), entries[len(entries)-historyWindow:]...)
+}
+
+// showHistory points the state's windows and counts at history
+func showHistory(state *DogState, history DogHistory) {
+	state.BehaviorNotes = recent(history.BehaviorNotes)
+	state.MedicalHistory = recent(history.MedicalHistory)
+	state.BehaviorNoteCount = len(history.BehaviorNotes)
+	state.MedicalHistoryCount = len(history.MedicalHistory)
+}
+
+// startHistory files the histories a new dog starts with, as initDogState
+// left them on the state.
+func startHistory(ctx context.Context, state *DogState) error {
+	history := DogHistory{BehaviorNotes: state.BehaviorNotes, MedicalHistory: state.MedicalHistory}
+	if _, err := registry.Save(ctx, "dog-history", state.ID, backend.AnyVersion, history); err != nil {
+		return err
+	}
+	showHistory(state, history)
+	return nil
+}
+
+// extendHistory appends entries to a dog's histories. Dogs registered
+// before histories were split out have no record yet; theirs starts from
+// the full lists in their old state.
+func extendHistory(ctx context.Context, state *DogState, oldState DogState, behavior []BehaviorNote, medical []string) error {
+	var history DogHistory
+	version, err := registry.Load(ctx, "dog-history", state.ID, &history)
+	if errors.Is(err, backend.ErrNotFound) {
+		history = DogHistory{BehaviorNotes: oldState.BehaviorNotes, MedicalHistory: oldState.MedicalHistory}
+	} else if err != nil {
+		return err
+	}
+
+	history.BehaviorNotes = append(history.BehaviorNotes, behavior...)
+	history.MedicalHistory = append(history.MedicalHistory, medical...)
+	if _, err := registry.Save(ctx, "dog-history", state.ID, version, history); err != nil {
+		return err
+	}
+	showHistory(state, history)
+	return nil
+}
@@ -0,0 +1,75 @@
+package resources
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func notes(n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = fmt.Sprintf("note %d", i+1)
+	}
+	return out
+}
+
+func behaviorNotes(n int) []BehaviorNote {
+	out := make([]BehaviorNote, n)
+	for i, note := range notes(n) {
+		out[i] = BehaviorNote{Author: Owner, Severity: Info, Note: note}
+	}
+	return out
+}
+
+func TestRecent(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    []string
+	}{
+		{name: "empty", entries: nil, want: nil},
+		{name: "within the window", entries: notes(3), want: notes(3)},
+		{name: "exactly the window", entries: notes(historyWindow), want: notes(historyWindow)},
+		{name: "longer keeps the tail", entries: notes(historyWindow + 5), want: notes(historyWindow + 5)[5:]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recent(tt.entries); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("recent = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShowHistory(t *testing.T) {
+	var state DogState
+	showHistory(&state, DogHistory{BehaviorNotes: behaviorNotes(25), MedicalHistory: notes(2)})
+
+	if len(state.BehaviorNotes) != historyWindow || state.BehaviorNotes[historyWindow-1].Note != "note 25" {
+		t.Errorf("behavior window = %+v, want the last %d notes", state.BehaviorNotes, historyWindow)
+	}
+	if state.BehaviorNoteCount != 25 || state.MedicalHistoryCount != 2 {
+		t.Errorf("counts = %d/%d, want 25/2", state.BehaviorNoteCount, state.MedicalHistoryCount)
+	}
+}
+
+func TestCarryDogStateStaysInWindow(t *testing.T) {
+	now := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	old := DogState{DogOutputs: DogOutputs{BehaviorNotes: behaviorNotes(historyWindow), BehaviorNoteCount: 40}}
+
+	var state DogState
+	carryDogState(&state, old, now)
+
+	if len(state.BehaviorNotes) != historyWindow {
+		t.Fatalf("carried %d notes, want the window of %d", len(state.BehaviorNotes), historyWindow)
+	}
+	if last := state.BehaviorNotes[historyWindow-1]; last != updateNote(now) {
+		t.Errorf("last note = %+v, want the update note", last)
+	}
+	if state.BehaviorNoteCount != 40 {
+		t.Errorf("count = %d, want it carried as 40", state.BehaviorNoteCount)
+	}
+}
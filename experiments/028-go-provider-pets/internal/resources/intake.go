@@ -0,0 +1,231 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/validate"
+)
+
+// BulkDogIntake Resource - registers a whole batch of shelter dogs at once,
+// at a shelter registered as a PetShelter
+type BulkDogIntake struct{}
+
+type BulkDogIntakeArgs struct {
+	ShelterID string    `pulumi:"shelterId" validate:"required"`
+	Dogs      []DogArgs `pulumi:"dogs" validate:"required"`
+}
+
+type BulkIntakeFailure struct {
+	Index  int    `pulumi:"index"`
+	Name   string `pulumi:"name"`
+	Reason string `pulumi:"reason"`
+}
+
+// BulkDogIntakeOutputs are computed by the provider; Check rejects them as inputs
+type BulkDogIntakeOutputs struct {
+	ShelterName string              `pulumi:"shelterName"` // the shelter's name, as its PetShelter gives it
+	IntakeDate  string              `pulumi:"intakeDate"`
+	CreatedIDs  []string            `pulumi:"createdIds"`
+	Failures    []BulkIntakeFailure `pulumi:"failures"`
+	Versions    map[string]int64    `pulumi:"versions"`
+}
+
+// BulkDogIntakeState echoes the inputs next to the computed outputs
+type BulkDogIntakeState struct {
+	BulkDogIntakeArgs
+	BulkDogIntakeOutputs
+}
+
+func (BulkDogIntake) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (BulkDogIntakeArgs, []p.CheckFailure, error) {
+	return checkInputs[BulkDogIntakeArgs, BulkDogIntakeState](newInputs)
+}
+
+func (BulkDogIntake) Create(ctx context.Context, name string, input BulkDogIntakeArgs, preview bool) (string, BulkDogIntakeState, error) {
+	state := BulkDogIntakeState{BulkDogIntakeArgs: input}
+
+	if preview {
+		return name, state, nil
+	}
+
+	if err := registry.BeginOperation(); err != nil {
+		return "", state, err
+	}
+	defer registry.EndOperation()
+
+	key := registry.IdempotencyKey(ctx, "bulk-intake", name)
+	if id, _, err := registry.CreatedBefore(ctx, "bulk-intake", key, input, &state); err != nil {
+		return "", state, err
+	} else if id != "" {
+		return id, state, nil
+	}
+
+	shelter, err := loadShelter(ctx, input.ShelterID)
+	if err != nil {
+		return "", state, err
+	}
+	state.ShelterName = shelter.Name
+
+	now := registry.Now(ctx)
+	id := fmt.Sprintf("intake-%s-%s", input.ShelterID, registry.IDSuffix(ctx, name, now.Unix()))
+	state.IntakeDate = now.Format("2006-01-02T15:04:05Z")
+
+	// Bad entries are reported individually instead of failing the whole batch
+	var dogs []DogState
+	var records []backend.Record
+	var at []int // where each dog's record is in records
+	seen := map[string]bool{}
+	for i, spec := range input.Dogs {
+		if reason := intakeProblem(spec, seen); reason != "" {
+			state.Failures = append(state.Failures, BulkIntakeFailure{Index: i, Name: spec.Name, Reason: reason})
+			continue
+		}
+		seen[nameClaimID(spec.OwnerName, spec.Name)] = true
+
+		// Different owners can bring in dogs of the same name together, so
+		// the dog's place in the batch keeps its ID apart
+		suffix := fmt.Sprintf("%s-%d", registry.IDSuffix(ctx, id+"/"+spec.Name, now.Unix()), i)
+		dog := registeredDog(spec, now, suffix)
+		var claim []backend.Record
+		if registry.UniqueDogNames(ctx) {
+			rec, err := nameClaimRecord(ctx, dog)
+			if errors.Is(err, ErrNameConflict) {
+				state.Failures = append(state.Failures, BulkIntakeFailure{Index: i, Name: spec.Name, Reason: err.Error()})
+				continue
+			}
+			if err != nil {
+				return "", state, err
+			}
+			claim = append(claim, rec)
+		}
+		dog.ApprovalStatus = "active"
+		dog.BehaviorNotes = append(dog.BehaviorNotes, observed(now, Owner, Info, fmt.Sprintf("Arrived at %s in a bulk intake", shelter.Name)))
+		history := DogHistory{BehaviorNotes: dog.BehaviorNotes, MedicalHistory: dog.MedicalHistory}
+		showHistory(&dog, history)
+		payload, err := json.Marshal(dog)
+		if err != nil {
+			return "", state, err
+		}
+		historyPayload, err := json.Marshal(history)
+		if err != nil {
+			return "", state, err
+		}
+		dogs = append(dogs, dog)
+		at = append(at, len(records))
+		records = append(records,
+			backend.Record{Kind: "dog", ID: dog.ID, Payload: payload},
+			backend.Record{Kind: "dog-history", ID: dog.ID, Payload: historyPayload})
+		records = append(records, claim...)
+	}
+
+	store, err := registry.Store(ctx)
+	if err != nil {
+		return "", state, err
+	}
+	// The name claims go in the same transaction, so a dog registered
+	// meanwhile under one of the names fails the batch instead of sharing it
+	versions, err := store.PutAll(records)
+	if errors.Is(err, backend.ErrConflict) {
+		return "", state, fmt.Errorf("registering intake batch: %w: a name was taken while the batch was registered", ErrNameConflict)
+	}
+	if err != nil {
+		return "", state, fmt.Errorf("registering intake batch: %w", err)
+	}
+	state.Versions = map[string]int64{}
+	for i, dog := range dogs {
+		state.CreatedIDs = append(state.CreatedIDs, dog.ID)
+		state.Versions[dog.ID] = versions[at[i]]
+	}
+
+	if _, err := registry.Save(ctx, "bulk-intake", id, 0, state); err != nil {
+		return "", state, err
+	}
+	if err := registry.RememberCreate(ctx, key, "bulk-intake", id, input); err != nil {
+		return "", state, err
+	}
+
+	return id, state, nil
+}
+
+func (BulkDogIntake) Delete(ctx context.Context, id string, state BulkDogIntakeState) error {
+	if err := registry.BeginOperation(); err != nil {
+		return err
+	}
+	defer registry.EndOperation()
+
+	for _, dogID := range state.CreatedIDs {
+		// The record has the name to release; a dog that's gone has none
+		dog := DogState{}
+		if _, err := registry.Load(ctx, "dog", dogID, &dog); err != nil && !errors.Is(err, backend.ErrNotFound) {
+			return err
+		}
+		for _, kind := range []string{"dog-history", "ownership"} {
+			if err := registry.Delete(ctx, kind, dogID, backend.AnyVersion); err != nil {
+				return err
+			}
+		}
+		if err := registry.Delete(ctx, "dog", dogID, state.Versions[dogID]); err != nil {
+			return err
+		}
+		if dog.ID != "" {
+			if err := releaseName(ctx, dog); err != nil {
+				return err
+			}
+		}
+	}
+	if err := registry.Delete(ctx, "bulk-intake", id, backend.AnyVersion); err != nil {
+		return err
+	}
+	return registry.ForgetCreate(ctx, "bulk-intake", id)
+}
+
+// Shelter is the ID of the shelter the dogs arrived at. Intakes recorded
+// before shelters were registered only kept the shelter's name.
+func (s BulkDogIntakeState) Shelter() string {
+	if s.ShelterID == "" {
+		return ShelterID(s.ShelterName)
+	}
+	return s.ShelterID
+}
+
+// dogShelter is the shelter whose intake registered the dog, or "" for a
+// dog that didn't come from a shelter
+func dogShelter(ctx context.Context, dogID string) (string, error) {
+	intakes, err := listRecords[BulkDogIntakeState](ctx, backend.Query{
+		Kind:  "bulk-intake",
+		Where: []backend.Condition{{Field: "CreatedIDs", Op: "contains", Value: dogID}},
+	})
+	if err != nil || len(intakes) == 0 {
+		return "", err
+	}
+	return intakes[0].Shelter(), nil
+}
+
+// ShelterID is how a shelter is known across intakes: its name, lowercased
+// with dashes for spaces
+func ShelterID(shelterName string) string {
+	return strings.ToLower(strings.Join(strings.Fields(shelterName), "-"))
+}
+
+// intakeProblem explains why a dog spec can't be registered, or returns "".
+// seen holds the name claims of the batch's earlier dogs.
+func intakeProblem(spec DogArgs, seen map[string]bool) string {
+	if failures := validate.Struct(&spec); len(failures) > 0 {
+		return failures[0].Reason
+	}
+	switch {
+	case seen[nameClaimID(spec.OwnerName, spec.Name)]:
+		return "duplicate name in this intake batch"
+	case spec.RequiresApproval != nil && *spec.RequiresApproval:
+		return "requiresApproval is not supported in bulk intakes"
+	}
+	return ""
+}
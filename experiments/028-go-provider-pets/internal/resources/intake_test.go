@@ -0,0 +1,34 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+func TestBulkIntakeNamesPerOwner(t *testing.T) {
+	deterministic := true
+	ctx := configured(t, registry.Config{Deterministic: &deterministic})
+	shelter := PetShelterState{PetShelterArgs: PetShelterArgs{Name: "Happy Tails", Address: "1 Main St", Capacity: 10}}
+	if _, err := registry.Save(ctx, "shelter", "happy-tails", 0, shelter); err != nil {
+		t.Fatal(err)
+	}
+
+	_, state, err := BulkDogIntake{}.Create(ctx, "spring", BulkDogIntakeArgs{
+		ShelterID: "happy-tails",
+		Dogs: []DogArgs{
+			{Name: "Rex", Breed: Beagle, OwnerName: "Ann"},
+			{Name: "Rex", Breed: Beagle, OwnerName: "Bob"},
+			{Name: "rex", Breed: Beagle, OwnerName: "ann"},
+		},
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.CreatedIDs) != 2 || state.CreatedIDs[0] == state.CreatedIDs[1] {
+		t.Errorf("created %q, want two dogs named Rex", state.CreatedIDs)
+	}
+	if len(state.Failures) != 1 || state.Failures[0].Index != 2 {
+		t.Errorf("failures = %+v, want Ann's second Rex refused", state.Failures)
+	}
+}
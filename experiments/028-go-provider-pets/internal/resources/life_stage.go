@@ -0,0 +1,124 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// A dog's first year counts as 15 human years and its second as 9 more.
+// After that bigger dogs age faster: each year counts for the years of
+// humanYearsPerYear for the dog's size.
+const (
+	firstYearHumanYears  = 15
+	secondYearHumanYears = 9
+	// seniorHumanYears is the human-equivalent age a dog is senior from
+	seniorHumanYears = 56
+)
+
+var humanYearsPerYear = map[PetSize]float64{Small: 4, Medium: 5, Large: 6, ExtraLarge: 7}
+
+// adultAge is the age in years a dog of each size has grown up by; the
+// bigger the dog, the longer it takes
+var adultAge = map[PetSize]float64{Small: 1, Medium: 1, Large: 1.5, ExtraLarge: 2}
+
+// DogAge is a dog's age, given by the ID of a registered dog or by its
+// breed and age
+type DogAge struct {
+	DogID *string   `pulumi:"dogId,optional"`
+	Breed *DogBreed `pulumi:"breed,optional"` // With age, instead of dogId
+	Size  *PetSize  `pulumi:"size,optional"`  // Worked out from the breed when unset
+	Age   *float64  `pulumi:"age,optional"`   // In years; fractions count, e.g. 1.5
+}
+
+// HumanAge is a dog's age in human-equivalent years
+type HumanAge struct {
+	DogAge     float64   `pulumi:"dogAge"`     // In years
+	Size       PetSize   `pulumi:"size"`       // The size the curve was chosen for
+	HumanYears float64   `pulumi:"humanYears"` // To one decimal
+	LifeStage  LifeStage `pulumi:"lifeStage"`  // puppy, adult or senior
+}
+
+// DogAgeInHumanYears converts a dog's age to human-equivalent years on a
+// curve for its size, and says which stage of life it is at
+func DogAgeInHumanYears(ctx context.Context, args DogAge) (HumanAge, error) {
+	switch {
+	case args.DogID != nil && (args.Breed != nil || args.Age != nil):
+		return HumanAge{}, errors.New("give either dogId or breed and age, not both")
+	case args.DogID != nil:
+		dog, err := walkedDog(ctx, *args.DogID)
+		if err != nil {
+			return HumanAge{}, err
+		}
+		return humanAge(dogSize(dog), dogAgeYears(dog)), nil
+	case args.Breed == nil || args.Age == nil:
+		return HumanAge{}, errors.New("dogId, or breed and age, are required")
+	case BreedCatalog[*args.Breed].Name == "":
+		return HumanAge{}, fmt.Errorf("%q is not a known breed", *args.Breed)
+	case args.Size != nil && sizeRank[*args.Size] == 0:
+		return HumanAge{}, errors.New("size must be one of small, medium, large or extra-large")
+	case *args.Age < 0 || *args.Age > 30:
+		return HumanAge{}, errors.New("age must be between 0 and 30")
+	}
+	size := determineSizeByBreed(*args.Breed)
+	if args.Size != nil {
+		size = *args.Size
+	}
+	return humanAge(size, *args.Age), nil
+}
+
+// humanAge is a dog of size at age years in human terms
+func humanAge(size PetSize, age float64) HumanAge {
+	human := humanYears(size, age)
+	return HumanAge{DogAge: round2(age), Size: size, HumanYears: math.Round(human*10) / 10, LifeStage: lifeStage(size, age)}
+}
+
+// humanYears is the human-equivalent of age years for a dog of size
+func humanYears(size PetSize, age float64) float64 {
+	perYear, ok := humanYearsPerYear[size]
+	if !ok {
+		perYear = humanYearsPerYear[Medium]
+	}
+	switch {
+	case age <= 1:
+		return firstYearHumanYears * age
+	case age <= 2:
+		return firstYearHumanYears + secondYearHumanYears*(age-1)
+	default:
+		return firstYearHumanYears + secondYearHumanYears + perYear*(age-2)
+	}
+}
+
+// lifeStage is the stage of life a dog of size is at at age years
+func lifeStage(size PetSize, age float64) LifeStage {
+	grown, ok := adultAge[size]
+	if !ok {
+		grown = 1
+	}
+	switch {
+	case age < grown:
+		return LifeStagePuppy
+	case humanYears(size, age) >= seniorHumanYears:
+		return LifeStageSenior
+	default:
+		return LifeStageAdult
+	}
+}
+
+// dogSize is the dog's size, else its breed's
+func dogSize(dog DogState) PetSize {
+	if dog.Size != nil {
+		return *dog.Size
+	}
+	return determineSizeByBreed(dog.Breed)
+}
+
+// dogAgeYears is the dog's age in years, with the months past its birthday
+// as a fraction when it has a birthDate
+func dogAgeYears(dog DogState) float64 {
+	if dog.BirthDate != nil {
+		return float64(dog.AgeYears) + float64(dog.AgeMonths)/12
+	}
+	return float64(dog.years())
+}
@@ -0,0 +1,65 @@
+package resources
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHumanAge(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  PetSize
+		age   float64
+		human float64
+		stage LifeStage
+	}{
+		{name: "newborn", size: Small, age: 0, human: 0, stage: LifeStagePuppy},
+		{name: "six months", size: Medium, age: 0.5, human: 7.5, stage: LifeStagePuppy},
+		{name: "first birthday", size: Medium, age: 1, human: 15, stage: LifeStageAdult},
+		{name: "large dog still growing", size: Large, age: 1.25, human: 17.3, stage: LifeStagePuppy},
+		{name: "giant still growing", size: ExtraLarge, age: 1.5, human: 19.5, stage: LifeStagePuppy},
+		{name: "two of any size", size: ExtraLarge, age: 2, human: 24, stage: LifeStageAdult},
+		{name: "small adult", size: Small, age: 9, human: 52, stage: LifeStageAdult},
+		{name: "small senior", size: Small, age: 10, human: 56, stage: LifeStageSenior},
+		{name: "large senior", size: Large, age: 8, human: 60, stage: LifeStageSenior},
+		{name: "giant senior", size: ExtraLarge, age: 7, human: 59, stage: LifeStageSenior},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := humanAge(tt.size, tt.age)
+			if got.HumanYears != tt.human || got.LifeStage != tt.stage {
+				t.Errorf("humanAge(%s, %v) = %v years, %s; want %v, %s", tt.size, tt.age, got.HumanYears, got.LifeStage, tt.human, tt.stage)
+			}
+		})
+	}
+}
+
+func TestDogAgeInHumanYearsArgs(t *testing.T) {
+	golden, wolf := GoldenRetriever, DogBreed("wolf")
+	small := Small
+	tests := []struct {
+		name  string
+		args  DogAge
+		size  PetSize
+		human float64
+		err   bool
+	}{
+		{name: "by breed", args: DogAge{Breed: &golden, Age: floatPtr(3)}, size: Large, human: 30},
+		{name: "sized by hand", args: DogAge{Breed: &golden, Size: &small, Age: floatPtr(3)}, size: Small, human: 28},
+		{name: "no age", args: DogAge{Breed: &golden}, err: true},
+		{name: "both ways", args: DogAge{DogID: stringPtr("dog-rex"), Breed: &golden, Age: floatPtr(3)}, err: true},
+		{name: "not a breed", args: DogAge{Breed: &wolf, Age: floatPtr(3)}, err: true},
+		{name: "too old", args: DogAge{Breed: &golden, Age: floatPtr(31)}, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DogAgeInHumanYears(context.Background(), tt.args)
+			if (err != nil) != tt.err {
+				t.Fatalf("err = %v, want error %v", err, tt.err)
+			}
+			if err == nil && (got.Size != tt.size || got.HumanYears != tt.human) {
+				t.Errorf("DogAgeInHumanYears = %+v, want a %s dog of %v human years", got, tt.size, tt.human)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// Listing Resource - a shelter dog shown at a booth and time slot of an
+// adoption event. AdoptionEvent creates one for each dog still waiting for
+// a home; a dog adopted in the meantime can't be listed, and an approved
+// Adoption closes the dog's listings. A listing moved to another booth or
+// slot is replaced.
+type Listing struct{}
+
+//pets:state id=ListingID created=ListedAt
+//pets:output ListingID string listingId Generated identifier of the listing
+//pets:output ListedAt string listedAt When the dog was listed
+//pets:output DogName string dogName The listed dog's name
+//pets:output Breed DogBreed breed The listed dog's breed
+//pets:output Status string status open, or closed once the dog is adopted; recomputed on refresh
+//pets:output AdoptionID string adoptionId The Adoption that closed the listing
+type ListingArgs struct {
+	DogID     string `pulumi:"dogId" validate:"required"`
+	ShelterID string `pulumi:"shelterId" validate:"required"`
+	EventDate string `pulumi:"eventDate" validate:"required"` // The day of the event, YYYY-MM-DD
+	Booth     int    `pulumi:"booth" validate:"min=1"`
+	Slot      string `pulumi:"slot" validate:"required"` // Time of day the dog is shown from, HH:MM
+}
+
+// listingClosure is stored under a listing's ID once an Adoption of its
+// dog closes it
+type listingClosure struct {
+	AdoptionID string `json:"adoptionId"`
+}
+
+// AdoptableDog is a shelter dog no approved Adoption has taken home yet
+type AdoptableDog struct {
+	DogID      string   `pulumi:"dogId" json:"dogId"`
+	Name       string   `pulumi:"name" json:"name"`
+	Breed      DogBreed `pulumi:"breed" json:"breed"`
+	Age        int      `pulumi:"age" json:"age"`
+	IntakeDate string   `pulumi:"intakeDate" json:"intakeDate"`
+}
+
+var listings = crudResource[ListingArgs, ListingState, *ListingState]{
+	kind:     stored[ListingState]("listing"),
+	prefix:   "listing",
+	slug:     func(input ListingArgs) string { return input.DogID },
+	newState: newListingState,
+	populate: func(ctx context.Context, state *ListingState, input ListingArgs) error {
+		if err := requireShelter(ctx, input.ShelterID); err != nil {
+			return err
+		}
+		dogs, err := AdoptableDogs(ctx, input.ShelterID)
+		if err != nil {
+			return err
+		}
+		for _, dog := range dogs {
+			if dog.DogID == input.DogID {
+				state.DogName, state.Breed, state.Status = dog.Name, dog.Breed, "open"
+				return nil
+			}
+		}
+		return fmt.Errorf("dog %s is not waiting for adoption at shelter %s", input.DogID, input.ShelterID)
+	},
+	refresh: func(ctx context.Context, id string, state *ListingState) error {
+		var closure listingClosure
+		_, err := registry.Load(ctx, "listing-closure", id, &closure)
+		switch {
+		case err == nil:
+			state.Status, state.AdoptionID = "closed", closure.AdoptionID
+		case errors.Is(err, backend.ErrNotFound):
+			state.Status, state.AdoptionID = "open", ""
+		default:
+			return err
+		}
+		return nil
+	},
+	related: []string{"listing-closure"},
+}
+
+func (Listing) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (ListingArgs, []p.CheckFailure, error) {
+	args, failures, err := listings.check(newInputs)
+	if _, perr := time.Parse(dateLayout, args.EventDate); args.EventDate != "" && perr != nil {
+		failures = append(failures, p.CheckFailure{Property: "eventDate", Reason: "eventDate must be a date like 2026-10-17"})
+	}
+	if _, perr := time.Parse("15:04", args.Slot); args.Slot != "" && perr != nil {
+		failures = append(failures, p.CheckFailure{Property: "slot", Reason: "slot must be a time of day like 10:30"})
+	}
+	return args, failures, err
+}
+
+func (Listing) Create(ctx context.Context, name string, input ListingArgs, preview bool) (string, ListingState, error) {
+	return listings.create(ctx, name, input, preview)
+}
+
+func (Listing) Read(ctx context.Context, id string, inputs ListingArgs, state ListingState) (string, ListingArgs, ListingState, error) {
+	return listings.read(ctx, id, inputs, state)
+}
+
+func (Listing) Delete(ctx context.Context, id string, state ListingState) error {
+	return listings.delete(ctx, id, state)
+}
+
+// closeListings closes the dog's open listings for the adoption, and
+// returns how many it closed
+func closeListings(ctx context.Context, dogID, adoptionID string) (int, error) {
+	listings, err := listForDog[ListingState](ctx, "listing", dogID)
+	if err != nil {
+		return 0, err
+	}
+	closed := 0
+	for _, listing := range listings {
+		_, err := registry.Load(ctx, "listing-closure", listing.ListingID, &listingClosure{})
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, backend.ErrNotFound) {
+			return closed, err
+		}
+		if _, err := registry.Save(ctx, "listing-closure", listing.ListingID, 0, listingClosure{AdoptionID: adoptionID}); err != nil {
+			return closed, err
+		}
+		closed++
+	}
+	return closed, nil
+}
+
+// reopenListings reopens the dog's listings the adoption closed
+func reopenListings(ctx context.Context, dogID, adoptionID string) error {
+	listings, err := listForDog[ListingState](ctx, "listing", dogID)
+	if err != nil {
+		return err
+	}
+	for _, listing := range listings {
+		var closure listingClosure
+		_, err := registry.Load(ctx, "listing-closure", listing.ListingID, &closure)
+		if errors.Is(err, backend.ErrNotFound) || (err == nil && closure.AdoptionID != adoptionID) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := registry.Delete(ctx, "listing-closure", listing.ListingID, backend.AnyVersion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AdoptableDogs lists the shelter's dogs without an approved Adoption, the
+// longest waiting first
+func AdoptableDogs(ctx context.Context, shelterID string) ([]AdoptableDog, error) {
+	intakes, err := listRecords[BulkDogIntakeState](ctx, backend.Query{Kind: "bulk-intake"})
+	if err != nil {
+		return nil, err
+	}
+	adoptions, err := listRecords[AdoptionState](ctx, backend.Query{Kind: "adoption"})
+	if err != nil {
+		return nil, err
+	}
+	adopted := map[string]bool{}
+	for _, adoption := range adoptions {
+		if adoption.Status == "approved" {
+			adopted[adoption.DogID] = true
+		}
+	}
+	var adoptable []AdoptableDog
+	for _, intake := range intakes {
+		if intake.Shelter() != shelterID {
+			continue
+		}
+		for _, id := range intake.CreatedIDs {
+			if adopted[id] {
+				continue
+			}
+			dog, err := walkedDog(ctx, id)
+			if errors.Is(err, backend.ErrNotFound) {
+				continue // deleted since the intake
+			}
+			if err != nil {
+				return nil, err
+			}
+			adoptable = append(adoptable, AdoptableDog{DogID: id, Name: dog.Name, Breed: dog.Breed, Age: dog.years(), IntakeDate: intake.IntakeDate})
+		}
+	}
+	sort.Slice(adoptable, func(i, j int) bool {
+		if adoptable[i].IntakeDate != adoptable[j].IntakeDate {
+			return adoptable[i].IntakeDate < adoptable[j].IntakeDate
+		}
+		return adoptable[i].DogID < adoptable[j].DogID
+	})
+	return adoptable, nil
+}
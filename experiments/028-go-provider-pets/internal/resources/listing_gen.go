@@ -0,0 +1,46 @@
+// Code generated by genstate from listing.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// ListingOutputs are computed by the provider; Check rejects them as inputs
+type ListingOutputs struct {
+	ListingID  string   `pulumi:"listingId"`
+	ListedAt   string   `pulumi:"listedAt"`
+	DogName    string   `pulumi:"dogName"`
+	Breed      DogBreed `pulumi:"breed"`
+	Status     string   `pulumi:"status"`
+	AdoptionID string   `pulumi:"adoptionId"`
+	Version    int64    `pulumi:"version"`
+}
+
+// ListingState echoes the inputs next to the computed outputs
+type ListingState struct {
+	ListingArgs
+	ListingOutputs
+}
+
+// newListingState copies the inputs into an otherwise empty state
+func newListingState(input ListingArgs) ListingState { return ListingState{ListingArgs: input} }
+
+func (s *ListingState) stamp(id, created string)   { s.ListingID, s.ListedAt = id, created }
+func (s *ListingState) identity() (string, string) { return s.ListingID, s.ListedAt }
+func (s *ListingState) setVersion(version int64)   { s.Version = version }
+func (s *ListingState) storedVersion() int64       { return s.Version }
+
+func (args *ListingArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.EventDate, "The day of the event, YYYY-MM-DD")
+	a.Describe(&args.Slot, "Time of day the dog is shown from, HH:MM")
+}
+
+func (state *ListingState) Annotate(a infer.Annotator) {
+	state.ListingArgs.Annotate(a)
+	a.Describe(&state.ListingID, "Generated identifier of the listing")
+	a.Describe(&state.ListedAt, "When the dog was listed")
+	a.Describe(&state.DogName, "The listed dog's name")
+	a.Describe(&state.Breed, "The listed dog's breed")
+	a.Describe(&state.Status, "open, or closed once the dog is adopted; recomputed on refresh")
+	a.Describe(&state.AdoptionID, "The Adoption that closed the listing")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
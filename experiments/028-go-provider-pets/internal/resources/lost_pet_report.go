@@ -0,0 +1,195 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+)
+
+// LostPetReport Resource - a dog gone missing. Filing the report posts a
+// lost-pet event to the NotificationChannels subscribed to the dog, and
+// lists the PetShelters with a location within the search radius of where
+// the dog was last seen, nearest first, for people to call. The status
+// moves on by updating the report, from open to sighted to found or
+// closed; a report that is found or closed stays that way.
+type LostPetReport struct{}
+
+//pets:state id=ID created=ReportedAt
+//pets:output ID string id Generated identifier of the report
+//pets:output ReportedAt string reportedAt When the report was filed
+//pets:output StatusChangedAt string statusChangedAt When the report last changed status
+//pets:output NearbyShelters []NearbyShelter nearbyShelters Shelters within the search radius, nearest first; recomputed on refresh
+type LostPetReportArgs struct {
+	DogID            string         `pulumi:"dogId" validate:"required"`
+	LastSeenLocation LatLng         `pulumi:"lastSeenLocation"`
+	Date             string         `pulumi:"date" validate:"required"` // The day the dog went missing, YYYY-MM-DD
+	RadiusMiles      float64        `pulumi:"radiusMiles" validate:"gt=0,max=100"`
+	Status           *LostPetStatus `pulumi:"status,optional" default:"open" validate:"oneof=open|sighted|found|closed"` // One of open, sighted, found or closed
+}
+
+// NearbyShelter is a shelter a lost dog may be taken to
+type NearbyShelter struct {
+	ShelterID     string  `pulumi:"shelterId" json:"shelterId"`
+	Name          string  `pulumi:"name" json:"name"`
+	Address       string  `pulumi:"address" json:"address"`
+	DistanceMiles float64 `pulumi:"distanceMiles" json:"distanceMiles"`
+}
+
+// metersPerMile converts search radiuses for distanceMeters
+const metersPerMile = 1609.344
+
+// lostTransitions are the statuses a report can move to from each status
+var lostTransitions = map[LostPetStatus][]LostPetStatus{
+	LostOpen:    {LostSighted, LostFound, LostClosed},
+	LostSighted: {LostFound, LostClosed},
+}
+
+var lostPetReports = crudResource[LostPetReportArgs, LostPetReportState, *LostPetReportState]{
+	kind:     stored[LostPetReportState]("lost-pet"),
+	prefix:   "lost",
+	slug:     func(input LostPetReportArgs) string { return input.DogID },
+	newState: newLostPetReportState,
+	populate: func(ctx context.Context, state *LostPetReportState, input LostPetReportArgs) error {
+		if _, err := walkedDog(ctx, input.DogID); err != nil {
+			return err
+		}
+		if status := *input.Status; status != LostOpen && status != LostSighted {
+			return fmt.Errorf("a new report is open or sighted, not %s", status)
+		}
+		state.StatusChangedAt = state.ReportedAt
+		return findShelters(ctx, state)
+	},
+	keep: func(state *LostPetReportState, oldState LostPetReportState) {
+		state.StatusChangedAt = oldState.StatusChangedAt
+	},
+	carry: func(ctx context.Context, state *LostPetReportState, oldState LostPetReportState, now time.Time) error {
+		if from, to := *oldState.Status, *state.Status; from != to {
+			if !lostTransition(from, to) {
+				return fmt.Errorf("a %s report can't become %s", from, to)
+			}
+			state.StatusChangedAt = now.Format("2006-01-02T15:04:05Z")
+		}
+		return findShelters(ctx, state)
+	},
+	announce: func(ctx context.Context, state LostPetReportState) {
+		dispatch(ctx, lostPetEvent(dogName(ctx, state.DogID), state))
+	},
+	refresh: func(ctx context.Context, id string, state *LostPetReportState) error {
+		return findShelters(ctx, state)
+	},
+}
+
+func (LostPetReport) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (LostPetReportArgs, []p.CheckFailure, error) {
+	args, failures, err := lostPetReports.check(newInputs)
+	if reason := checkLatLng(args.LastSeenLocation); reason != "" {
+		failures = append(failures, p.CheckFailure{Property: "lastSeenLocation", Reason: reason})
+	}
+	if _, perr := time.Parse(dateLayout, args.Date); args.Date != "" && perr != nil {
+		failures = append(failures, p.CheckFailure{Property: "date", Reason: "date must be YYYY-MM-DD"})
+	}
+	return args, failures, err
+}
+
+func (LostPetReport) Diff(ctx context.Context, id string, olds LostPetReportState, news LostPetReportArgs) (p.DiffResponse, error) {
+	// Another dog or day is another report; sightings move the rest along
+	diff := map[string]p.PropertyDiff{}
+	if olds.DogID != news.DogID {
+		diff["dogId"] = p.PropertyDiff{Kind: p.UpdateReplace, InputDiff: true}
+	}
+	if olds.Date != news.Date {
+		diff["date"] = p.PropertyDiff{Kind: p.UpdateReplace, InputDiff: true}
+	}
+	if !reflect.DeepEqual(olds.LastSeenLocation, news.LastSeenLocation) {
+		diff["lastSeenLocation"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	if olds.RadiusMiles != news.RadiusMiles {
+		diff["radiusMiles"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	if !reflect.DeepEqual(olds.Status, news.Status) {
+		diff["status"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	return p.DiffResponse{HasChanges: len(diff) > 0, DetailedDiff: diff}, nil
+}
+
+func (LostPetReport) Create(ctx context.Context, name string, input LostPetReportArgs, preview bool) (string, LostPetReportState, error) {
+	return lostPetReports.create(ctx, name, input, preview)
+}
+
+func (LostPetReport) Read(ctx context.Context, id string, inputs LostPetReportArgs, state LostPetReportState) (string, LostPetReportArgs, LostPetReportState, error) {
+	return lostPetReports.read(ctx, id, inputs, state)
+}
+
+func (LostPetReport) Update(ctx context.Context, id string, oldState LostPetReportState, input LostPetReportArgs, preview bool) (LostPetReportState, error) {
+	return lostPetReports.update(ctx, id, oldState, input, preview)
+}
+
+func (LostPetReport) Delete(ctx context.Context, id string, state LostPetReportState) error {
+	return lostPetReports.delete(ctx, id, state)
+}
+
+// lostTransition reports whether a report can move from one status to
+// another
+func lostTransition(from, to LostPetStatus) bool {
+	for _, next := range lostTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// findShelters lists the registered shelters within the report's radius
+func findShelters(ctx context.Context, state *LostPetReportState) error {
+	shelters, err := listRecords[PetShelterState](ctx, backend.Query{Kind: "shelter"})
+	if err != nil {
+		return err
+	}
+	state.NearbyShelters = nearbyShelters(shelters, state.LastSeenLocation, state.RadiusMiles)
+	return nil
+}
+
+// nearbyShelters are the shelters with a location within radiusMiles of
+// point, nearest first
+func nearbyShelters(shelters []PetShelterState, point LatLng, radiusMiles float64) []NearbyShelter {
+	nearby := []NearbyShelter{}
+	for _, shelter := range shelters {
+		if shelter.Location == nil {
+			continue
+		}
+		miles := distanceMeters(point, *shelter.Location) / metersPerMile
+		if miles <= radiusMiles {
+			nearby = append(nearby, NearbyShelter{ShelterID: shelter.ShelterID, Name: shelter.Name, Address: shelter.Address, DistanceMiles: round2(miles)})
+		}
+	}
+	sort.SliceStable(nearby, func(i, j int) bool { return nearby[i].DistanceMiles < nearby[j].DistanceMiles })
+	return nearby
+}
+
+// lostPetEvent announces a lost dog
+func lostPetEvent(name string, state LostPetReportState) event {
+	details := []string{
+		fmt.Sprintf("Last seen at %g,%g on %s", state.LastSeenLocation.Lat, state.LastSeenLocation.Lng, state.Date),
+		fmt.Sprintf("Searching within %g miles", state.RadiusMiles),
+	}
+	if len(state.NearbyShelters) > 0 {
+		var names []string
+		for _, shelter := range state.NearbyShelters {
+			names = append(names, fmt.Sprintf("%s (%g mi)", shelter.Name, shelter.DistanceMiles))
+		}
+		details = append(details, "Nearby shelters: "+strings.Join(names, ", "))
+	}
+	return event{
+		Kind:    EventLostPet,
+		DogID:   state.DogID,
+		Title:   fmt.Sprintf("%s is missing", name),
+		Details: details,
+	}
+}
@@ -0,0 +1,53 @@
+// Code generated by genstate from lost_pet_report.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// LostPetReportOutputs are computed by the provider; Check rejects them as inputs
+type LostPetReportOutputs struct {
+	ID              string          `pulumi:"id"`
+	ReportedAt      string          `pulumi:"reportedAt"`
+	StatusChangedAt string          `pulumi:"statusChangedAt"`
+	NearbyShelters  []NearbyShelter `pulumi:"nearbyShelters"`
+	Version         int64           `pulumi:"version"`
+}
+
+// LostPetReportState echoes the inputs next to the computed outputs
+type LostPetReportState struct {
+	LostPetReportArgs
+	LostPetReportOutputs
+}
+
+// newLostPetReportState copies the inputs into an otherwise empty state
+func newLostPetReportState(input LostPetReportArgs) LostPetReportState {
+	return LostPetReportState{LostPetReportArgs: input}
+}
+
+func (s *LostPetReportState) stamp(id, created string)   { s.ID, s.ReportedAt = id, created }
+func (s *LostPetReportState) identity() (string, string) { return s.ID, s.ReportedAt }
+func (s *LostPetReportState) setVersion(version int64)   { s.Version = version }
+func (s *LostPetReportState) storedVersion() int64       { return s.Version }
+
+func (args *LostPetReportArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Date, "The day the dog went missing, YYYY-MM-DD")
+	a.Describe(&args.Status, "One of open, sighted, found or closed")
+	a.SetDefault(&args.Status, LostPetStatus("open"))
+}
+
+// applyDefaults fills unset optional inputs with their schema defaults
+func (args *LostPetReportArgs) applyDefaults() {
+	if args.Status == nil {
+		v := LostPetStatus("open")
+		args.Status = &v
+	}
+}
+
+func (state *LostPetReportState) Annotate(a infer.Annotator) {
+	state.LostPetReportArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the report")
+	a.Describe(&state.ReportedAt, "When the report was filed")
+	a.Describe(&state.StatusChangedAt, "When the report last changed status")
+	a.Describe(&state.NearbyShelters, "Shelters within the search radius, nearest first; recomputed on refresh")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
@@ -0,0 +1,60 @@
+package resources
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLostTransition(t *testing.T) {
+	tests := []struct {
+		from, to LostPetStatus
+		want     bool
+	}{
+		{from: LostOpen, to: LostSighted, want: true},
+		{from: LostOpen, to: LostFound, want: true},
+		{from: LostSighted, to: LostClosed, want: true},
+		{from: LostSighted, to: LostOpen},
+		{from: LostFound, to: LostSighted},
+		{from: LostClosed, to: LostOpen},
+	}
+	for _, tt := range tests {
+		if got := lostTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("lostTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestNearbyShelters(t *testing.T) {
+	shelter := func(id string, at *LatLng) PetShelterState {
+		var s PetShelterState
+		s.ShelterID, s.Name, s.Location = id, id, at
+		return s
+	}
+	shelters := []PetShelterState{
+		shelter("far", &LatLng{Lat: 40.0, Lng: -74.0}),
+		shelter("nowhere", nil),
+		shelter("near", &LatLng{Lat: 40.71, Lng: -74.0}),
+		shelter("closer", &LatLng{Lat: 40.705, Lng: -74.0}),
+	}
+	point := LatLng{Lat: 40.7, Lng: -74.0}
+	tests := []struct {
+		name   string
+		radius float64
+		want   []string
+	}{
+		{name: "a mile", radius: 1, want: []string{"closer", "near"}},
+		{name: "next door", radius: 0.1},
+		{name: "the region", radius: 60, want: []string{"closer", "near", "far"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, s := range nearbyShelters(shelters, point, tt.radius) {
+				got = append(got, s.ShelterID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nearby = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
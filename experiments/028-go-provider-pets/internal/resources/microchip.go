@@ -0,0 +1,81 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+)
+
+// Microchip Resource - an ISO 11784/11785 identification chip implanted in
+// a dog. A chip number is registered to one dog at a time; PetDoors list
+// chips to decide who gets through.
+type Microchip struct{}
+
+//pets:state id=ID created=RegisteredAt
+//pets:output ID string id Generated identifier of the chip registration
+//pets:output RegisteredAt string registeredAt When the chip was registered
+type MicrochipArgs struct {
+	DogID        string  `pulumi:"dogId" validate:"required"`
+	ChipNumber   string  `pulumi:"chipNumber" validate:"required"` // The 15 digits a scanner reads
+	Manufacturer *string `pulumi:"manufacturer,optional"`
+}
+
+var microchips = crudResource[MicrochipArgs, MicrochipState, *MicrochipState]{
+	kind:     stored[MicrochipState]("microchip"),
+	prefix:   "chip",
+	slug:     func(input MicrochipArgs) string { return input.DogID },
+	newState: newMicrochipState,
+	populate: func(ctx context.Context, state *MicrochipState, input MicrochipArgs) error {
+		if _, err := walkedDog(ctx, input.DogID); err != nil {
+			return err
+		}
+		registered, err := listRecords[MicrochipState](ctx, backend.Query{
+			Kind:  "microchip",
+			Where: []backend.Condition{{Field: "ChipNumber", Op: "eq", Value: input.ChipNumber}},
+		})
+		if err != nil {
+			return err
+		}
+		if len(registered) > 0 {
+			return fmt.Errorf("chip %s is already registered to %s", input.ChipNumber, registered[0].DogID)
+		}
+		return nil
+	},
+}
+
+func (Microchip) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (MicrochipArgs, []p.CheckFailure, error) {
+	args, failures, err := microchips.check(newInputs)
+	if args.ChipNumber != "" && !validChipNumber(args.ChipNumber) {
+		failures = append(failures, p.CheckFailure{Property: "chipNumber", Reason: "chipNumber must be 15 digits"})
+	}
+	return args, failures, err
+}
+
+func (Microchip) Create(ctx context.Context, name string, input MicrochipArgs, preview bool) (string, MicrochipState, error) {
+	return microchips.create(ctx, name, input, preview)
+}
+
+func (Microchip) Read(ctx context.Context, id string, inputs MicrochipArgs, state MicrochipState) (string, MicrochipArgs, MicrochipState, error) {
+	return microchips.read(ctx, id, inputs, state)
+}
+
+func (Microchip) Delete(ctx context.Context, id string, state MicrochipState) error {
+	return microchips.delete(ctx, id, state)
+}
+
+// validChipNumber reports whether number is an ISO 11784 chip number
+func validChipNumber(number string) bool {
+	if len(number) != 15 {
+		return false
+	}
+	for _, c := range number {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
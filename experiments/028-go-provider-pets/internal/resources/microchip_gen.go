@@ -0,0 +1,39 @@
+// Code generated by genstate from microchip.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// MicrochipOutputs are computed by the provider; Check rejects them as inputs
+type MicrochipOutputs struct {
+	ID           string `pulumi:"id"`
+	RegisteredAt string `pulumi:"registeredAt"`
+	Version      int64  `pulumi:"version"`
+}
+
+// MicrochipState echoes the inputs next to the computed outputs
+type MicrochipState struct {
+	MicrochipArgs
+	MicrochipOutputs
+}
+
+// newMicrochipState copies the inputs into an otherwise empty state
+func newMicrochipState(input MicrochipArgs) MicrochipState {
+	return MicrochipState{MicrochipArgs: input}
+}
+
+func (s *MicrochipState) stamp(id, created string)   { s.ID, s.RegisteredAt = id, created }
+func (s *MicrochipState) identity() (string, string) { return s.ID, s.RegisteredAt }
+func (s *MicrochipState) setVersion(version int64)   { s.Version = version }
+func (s *MicrochipState) storedVersion() int64       { return s.Version }
+
+func (args *MicrochipArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.ChipNumber, "The 15 digits a scanner reads")
+}
+
+func (state *MicrochipState) Annotate(a infer.Annotator) {
+	state.MicrochipArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the chip registration")
+	a.Describe(&state.RegisteredAt, "When the chip was registered")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
@@ -0,0 +1,23 @@
+package resources
+
+import "testing"
+
+func TestValidChipNumber(t *testing.T) {
+	tests := []struct {
+		number string
+		want   bool
+	}{
+		{number: "985112345678901", want: true},
+		{number: "98511234567890"},
+		{number: "9851123456789012"},
+		{number: "98511234567890A"},
+		{number: "985-112-345-678"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			if got := validChipNumber(tt.number); got != tt.want {
+				t.Errorf("validChipNumber(%q) = %v, want %v", tt.number, got, tt.want)
+			}
+		})
+	}
+}
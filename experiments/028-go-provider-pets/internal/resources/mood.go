@@ -0,0 +1,158 @@
+package resources
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// refreshMood derives when a dog was last walked and fed from its DogWalk
+// records, what its SmartFeeders last reported dispensing and the care
+// logged through feedDog and recordWalk, and recomputes its happiness and
+// energy from them, so a refresh shows how the dog has been doing since the
+// last deployment. DogParkVisits tire the dog like walks and make up its
+// socialization. The walk and treat totals are recounted too.
+func refreshMood(ctx context.Context, state *DogState) error {
+	care, _, err := loadCare(ctx, state.ID)
+	if err != nil {
+		return err
+	}
+	return moodWithCare(ctx, state, care)
+}
+
+// moodWithCare is refreshMood with the dog's care record as given
+func moodWithCare(ctx context.Context, state *DogState, care dogCare) error {
+	walks, err := listForDog[DogWalkState](ctx, "walk", state.ID)
+	if err != nil {
+		return err
+	}
+	played, err := listForDog[DogParkVisitState](ctx, "park-visit", state.ID)
+	if err != nil {
+		return err
+	}
+	feeders, err := listForDog[SmartFeederState](ctx, "feeder", state.ID)
+	if err != nil {
+		return err
+	}
+	state.TotalWalks, state.TotalTreats = len(walks)+care.WalkCount, care.Treats
+	walked, fed := care.asRecords()
+	walks, feeders = append(append(walks, walked...), parkPlayAsWalks(played)...), append(feeders, fed...)
+
+	now := registry.Now(ctx)
+	state.Socialization = socialization(played, now)
+	state.LastWalk, state.LastFed = lastActivity(walks, feeders)
+	state.Happiness, state.Energy = moodAt(registry.Mood(ctx), now, *state, walks, feeders)
+	return nil
+}
+
+// HappinessTrend is the dog's happiness at this time of day on each of the
+// last days it was registered on, oldest first, from the walks and feeder
+// reports as they stood then
+func HappinessTrend(curve registry.MoodCurve, dog DogState, walks []DogWalkState, feeders []SmartFeederState, now time.Time, days int) []int {
+	registered, _ := parseStamp(dog.RegistrationDate)
+	var trend []int
+	for i := days - 1; i >= 0; i-- {
+		at := now.AddDate(0, 0, -i)
+		if at.Before(registered) {
+			continue
+		}
+		happiness, _ := moodAt(curve, at, dog, walks, feeders)
+		trend = append(trend, happiness)
+	}
+	return trend
+}
+
+// moodAt is the dog's mood at a moment, going by the walks and feeder
+// reports up to it. Until there is a record to go by, lastWalk and lastFed
+// are empty and the mood counts from the dog's registration.
+func moodAt(curve registry.MoodCurve, at time.Time, dog DogState, walks []DogWalkState, feeders []SmartFeederState) (happiness, energy int) {
+	var walked []DogWalkState
+	for _, walk := range walks {
+		if t, ok := parseStamp(walk.Date); ok && !t.After(at) {
+			walked = append(walked, walk)
+		}
+	}
+	var fed []SmartFeederState
+	for _, feeder := range feeders {
+		if t, ok := parseStamp(feeder.LastDispense); ok && !t.After(at) {
+			fed = append(fed, feeder)
+		}
+	}
+	lastWalk, lastFed := lastActivity(walked, fed)
+	if lastWalk == "" {
+		lastWalk = dog.RegistrationDate
+	}
+	if lastFed == "" {
+		lastFed = dog.RegistrationDate
+	}
+	return mood(curve, at, lastWalk, lastFed, walked)
+}
+
+// lastActivity is the newest walk and the newest dispense of any feeder,
+// empty when there is none; timestamps that don't parse are ignored.
+func lastActivity(walks []DogWalkState, feeders []SmartFeederState) (lastWalk, lastFed string) {
+	var walked, fed time.Time
+	for _, walk := range walks {
+		if at, ok := parseStamp(walk.Date); ok && at.After(walked) {
+			walked, lastWalk = at, walk.Date
+		}
+	}
+	for _, feeder := range feeders {
+		if at, ok := parseStamp(feeder.LastDispense); ok && at.After(fed) {
+			fed, lastFed = at, feeder.LastDispense
+		}
+	}
+	return lastWalk, lastFed
+}
+
+// mood evaluates the curve at now. The last walk is the later of lastWalk
+// and the newest walk record; timestamps that don't parse are ignored.
+func mood(curve registry.MoodCurve, now time.Time, lastWalk, lastFed string, walks []DogWalkState) (happiness, energy int) {
+	walked, hasWalked := parseStamp(lastWalk)
+	fatigue := 0.0
+	for _, walk := range walks {
+		at, ok := parseStamp(walk.Date)
+		if !ok {
+			continue
+		}
+		if !hasWalked || at.After(walked) {
+			walked, hasWalked = at, true
+		}
+		if since := now.Sub(at).Hours(); since >= 0 && since <= curve.ActivityWindowHours {
+			fatigue += float64(walk.Duration) * curve.EnergyPerWalkMinute * halve(since, curve.EnergyRecoveryHalfLifeHours)
+		}
+	}
+
+	h := 100.0
+	if hasWalked {
+		floor := float64(curve.HappinessFloor)
+		h = floor + (100-floor)*halve(hoursSince(now, walked), curve.HappinessHalfLifeHours)
+	}
+	if fed, ok := parseStamp(lastFed); ok {
+		if overdue := hoursSince(now, fed) - curve.FeedingIntervalHours; overdue > 0 {
+			h -= overdue * curve.HungerPenaltyPerHour
+		}
+	}
+	return clampScore(h), clampScore(100 - fatigue)
+}
+
+// halve is what's left of a quantity after hours with the given half-life
+func halve(hours, halfLife float64) float64 {
+	return math.Pow(0.5, hours/halfLife)
+}
+
+func hoursSince(now, then time.Time) float64 {
+	return math.Max(0, now.Sub(then).Hours())
+}
+
+func parseStamp(stamp string) (time.Time, bool) {
+	t, err := time.Parse("2006-01-02T15:04:05Z", stamp)
+	return t, err == nil
+}
+
+// clampScore rounds a score onto the 0-100 scale
+func clampScore(v float64) int {
+	return int(math.Round(math.Max(0, math.Min(100, v))))
+}
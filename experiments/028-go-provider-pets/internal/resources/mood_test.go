@@ -0,0 +1,114 @@
+package resources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+func TestMood(t *testing.T) {
+	curve := registry.MoodCurve{
+		HappinessHalfLifeHours:      24,
+		HappinessFloor:              20,
+		FeedingIntervalHours:        12,
+		HungerPenaltyPerHour:        5,
+		EnergyPerWalkMinute:         1,
+		EnergyRecoveryHalfLifeHours: 3,
+		ActivityWindowHours:         24,
+	}
+	now := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	stamp := func(hoursAgo float64) string {
+		return now.Add(-time.Duration(hoursAgo * float64(time.Hour))).Format("2006-01-02T15:04:05Z")
+	}
+	walk := func(hoursAgo float64, minutes int) DogWalkState {
+		return DogWalkState{DogWalkArgs: DogWalkArgs{Duration: minutes}, DogWalkOutputs: DogWalkOutputs{Date: stamp(hoursAgo)}}
+	}
+
+	tests := []struct {
+		name          string
+		lastWalk      string
+		lastFed       string
+		walks         []DogWalkState
+		wantHappiness int
+		wantEnergy    int
+	}{
+		{name: "just walked and fed", lastWalk: stamp(0), lastFed: stamp(0), wantHappiness: 100, wantEnergy: 100},
+		{name: "a day since the last walk", lastWalk: stamp(24), lastFed: stamp(1), wantHappiness: 60, wantEnergy: 100},
+		{name: "two days since the last walk", lastWalk: stamp(48), lastFed: stamp(1), wantHappiness: 40, wantEnergy: 100},
+		{name: "meal four hours overdue", lastWalk: stamp(0), lastFed: stamp(16), wantHappiness: 80, wantEnergy: 100},
+		{name: "starving bottoms out", lastWalk: stamp(0), lastFed: stamp(72), wantHappiness: 0, wantEnergy: 100},
+		{
+			name:          "walk record newer than lastWalk",
+			lastWalk:      stamp(48),
+			lastFed:       stamp(1),
+			walks:         []DogWalkState{walk(0, 30)},
+			wantHappiness: 100,
+			wantEnergy:    70,
+		},
+		{
+			name:          "fatigue recovers",
+			lastWalk:      stamp(3),
+			lastFed:       stamp(1),
+			walks:         []DogWalkState{walk(3, 60)},
+			wantHappiness: 93,
+			wantEnergy:    70,
+		},
+		{
+			name:          "walks outside the window don't tire",
+			lastWalk:      stamp(30),
+			lastFed:       stamp(1),
+			walks:         []DogWalkState{walk(30, 120)},
+			wantHappiness: 54,
+			wantEnergy:    100,
+		},
+		{name: "unknown times leave the dog content", lastWalk: "yesterday", lastFed: "", wantHappiness: 100, wantEnergy: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			happiness, energy := mood(curve, now, tt.lastWalk, tt.lastFed, tt.walks)
+			if happiness != tt.wantHappiness || energy != tt.wantEnergy {
+				t.Errorf("mood = %d/%d, want %d/%d", happiness, energy, tt.wantHappiness, tt.wantEnergy)
+			}
+		})
+	}
+}
+
+func TestLastActivity(t *testing.T) {
+	walk := func(date string) DogWalkState {
+		return DogWalkState{DogWalkOutputs: DogWalkOutputs{Date: date}}
+	}
+	feeder := func(dispensed string) SmartFeederState {
+		return SmartFeederState{SmartFeederOutputs: SmartFeederOutputs{LastDispense: dispensed}}
+	}
+	tests := []struct {
+		name         string
+		walks        []DogWalkState
+		feeders      []SmartFeederState
+		wantLastWalk string
+		wantLastFed  string
+	}{
+		{name: "no records"},
+		{
+			name:         "newest of each",
+			walks:        []DogWalkState{walk("2024-03-05T08:00:00Z"), walk("2024-03-05T18:30:00Z"), walk("2024-03-04T07:00:00Z")},
+			feeders:      []SmartFeederState{feeder("2024-03-05T07:00:00Z"), feeder("2024-03-05T17:00:00Z")},
+			wantLastWalk: "2024-03-05T18:30:00Z",
+			wantLastFed:  "2024-03-05T17:00:00Z",
+		},
+		{
+			name:        "offline feeder and bad stamps ignored",
+			walks:       []DogWalkState{walk("yesterday")},
+			feeders:     []SmartFeederState{feeder(""), feeder("2024-03-05T07:00:00Z")},
+			wantLastFed: "2024-03-05T07:00:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lastWalk, lastFed := lastActivity(tt.walks, tt.feeders)
+			if lastWalk != tt.wantLastWalk || lastFed != tt.wantLastFed {
+				t.Errorf("lastActivity = %q, %q; want %q, %q", lastWalk, lastFed, tt.wantLastWalk, tt.wantLastFed)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// NotificationChannel Resource - a Slack or Discord incoming webhook that
+// walks and vet visits are posted to as they are recorded. The webhook URL
+// is a secret; the registry stores it encrypted when an encryptionKey is
+// configured.
+type NotificationChannel struct{}
+
+//pets:state id=ID created=CreatedAt
+//pets:output ID string id Generated identifier of the channel
+//pets:output CreatedAt string createdAt When the channel was registered
+type NotificationChannelArgs struct {
+	Name       string      `pulumi:"name" validate:"required,max=64"`
+	Type       ChannelType `pulumi:"type" validate:"required,oneof=slack|discord"` // One of slack or discord
+	WebhookURL string      `pulumi:"webhookUrl" provider:"secret" validate:"required"`
+	Events     []EventKind `pulumi:"events,optional"` // Events to post: walk, visit, geofence or lost-pet; all events when unset
+	DogID      *string     `pulumi:"dogId,optional"`  // Only post events about this dog
+}
+
+var channels = crudResource[NotificationChannelArgs, NotificationChannelState, *NotificationChannelState]{
+	kind:     stored[NotificationChannelState]("channel"),
+	prefix:   "channel",
+	slug:     func(input NotificationChannelArgs) string { return slugify(input.Name) },
+	newState: newNotificationChannelState,
+}
+
+func (NotificationChannel) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (NotificationChannelArgs, []p.CheckFailure, error) {
+	args, failures, err := channels.check(newInputs)
+	return args, append(failures, checkChannel(args)...), err
+}
+
+func (NotificationChannel) Create(ctx context.Context, name string, input NotificationChannelArgs, preview bool) (string, NotificationChannelState, error) {
+	return channels.create(ctx, name, input, preview)
+}
+
+func (NotificationChannel) Read(ctx context.Context, id string, inputs NotificationChannelArgs, state NotificationChannelState) (string, NotificationChannelArgs, NotificationChannelState, error) {
+	return channels.read(ctx, id, inputs, state)
+}
+
+func (NotificationChannel) Update(ctx context.Context, id string, oldState NotificationChannelState, input NotificationChannelArgs, preview bool) (NotificationChannelState, error) {
+	return channels.update(ctx, id, oldState, input, preview)
+}
+
+func (NotificationChannel) Delete(ctx context.Context, id string, state NotificationChannelState) error {
+	return channels.delete(ctx, id, state)
+}
+
+// checkChannel reports a webhook URL that can't be posted to and unknown
+// event filters
+func checkChannel(args NotificationChannelArgs) []p.CheckFailure {
+	var failures []p.CheckFailure
+	if args.WebhookURL != "" {
+		u, err := url.Parse(args.WebhookURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			failures = append(failures, p.CheckFailure{Property: "webhookUrl", Reason: "webhookUrl must be an http or https URL"})
+		}
+	}
+	for i, kind := range args.Events {
+		if kind != EventWalk && kind != EventVisit && kind != EventGeofence && kind != EventLostPet {
+			failures = append(failures, p.CheckFailure{
+				Property: fmt.Sprintf("events[%d]", i),
+				Reason:   "events must be walk, visit, geofence or lost-pet",
+			})
+		}
+	}
+	return failures
+}
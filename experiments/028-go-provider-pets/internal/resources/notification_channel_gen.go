@@ -0,0 +1,41 @@
+// Code generated by genstate from notification_channel.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// NotificationChannelOutputs are computed by the provider; Check rejects them as inputs
+type NotificationChannelOutputs struct {
+	ID        string `pulumi:"id"`
+	CreatedAt string `pulumi:"createdAt"`
+	Version   int64  `pulumi:"version"`
+}
+
+// NotificationChannelState echoes the inputs next to the computed outputs
+type NotificationChannelState struct {
+	NotificationChannelArgs
+	NotificationChannelOutputs
+}
+
+// newNotificationChannelState copies the inputs into an otherwise empty state
+func newNotificationChannelState(input NotificationChannelArgs) NotificationChannelState {
+	return NotificationChannelState{NotificationChannelArgs: input}
+}
+
+func (s *NotificationChannelState) stamp(id, created string)   { s.ID, s.CreatedAt = id, created }
+func (s *NotificationChannelState) identity() (string, string) { return s.ID, s.CreatedAt }
+func (s *NotificationChannelState) setVersion(version int64)   { s.Version = version }
+func (s *NotificationChannelState) storedVersion() int64       { return s.Version }
+
+func (args *NotificationChannelArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Type, "One of slack or discord")
+	a.Describe(&args.Events, "Events to post: walk, visit, geofence or lost-pet; all events when unset")
+	a.Describe(&args.DogID, "Only post events about this dog")
+}
+
+func (state *NotificationChannelState) Annotate(a infer.Annotator) {
+	state.NotificationChannelArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the channel")
+	a.Describe(&state.CreatedAt, "When the channel was registered")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
@@ -0,0 +1,87 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// OwnershipTransfer is a dog changing hands: an Update that gave it another
+// ownerName
+type OwnershipTransfer struct {
+	PreviousOwner string `pulumi:"previousOwner" json:"previousOwner"`
+	NewOwner      string `pulumi:"newOwner" json:"newOwner"`
+	Date          string `pulumi:"date" json:"date"`
+}
+
+// ownershipRecord is the registry record holding a dog's transfers, oldest
+// first, stored under the dog's ID
+type ownershipRecord struct {
+	Transfers []OwnershipTransfer `json:"transfers"`
+}
+
+// OwnershipTransfers reads a dog's transfers, oldest first. A dog that
+// never changed hands has none.
+func OwnershipTransfers(ctx context.Context, dogID string) ([]OwnershipTransfer, error) {
+	var record ownershipRecord
+	_, err := registry.Load(ctx, "ownership", dogID, &record)
+	if errors.Is(err, backend.ErrNotFound) {
+		return nil, nil
+	}
+	return record.Transfers, err
+}
+
+// transferOwnership records the transfer when an update changed the dog's
+// owner, and tells the deployment about it
+func transferOwnership(ctx context.Context, state *DogState, oldState DogState, now time.Time) error {
+	transfers, err := recordTransfer(ctx, *state, oldState.OwnerName, now)
+	state.OwnershipTransfers = transfers
+	return err
+}
+
+// recordTransfer adds the dog going from previous to its ownerName to its
+// transfers, when that is a change, and returns them
+func recordTransfer(ctx context.Context, dog DogState, previous string, now time.Time) ([]OwnershipTransfer, error) {
+	var record ownershipRecord
+	version, err := registry.Load(ctx, "ownership", dog.ID, &record)
+	if errors.Is(err, backend.ErrNotFound) {
+		version = 0
+	} else if err != nil {
+		return nil, err
+	}
+	transfers, changed := transferred(record.Transfers, previous, dog.OwnerName, now)
+	if !changed {
+		return transfers, nil
+	}
+	record.Transfers = transfers
+	if _, err := registry.Save(ctx, "ownership", dog.ID, version, record); err != nil {
+		return nil, err
+	}
+	last := transfers[len(transfers)-1]
+	p.GetLogger(ctx).Infof("%s (%s) transferred from %s to %s", dog.Name, dog.ID, last.PreviousOwner, last.NewOwner)
+	return transfers, nil
+}
+
+// transferred adds a transfer from previous to owner, unless they are the
+// same owner spelled or spaced differently. When the dog changed hands
+// before, previous is whoever it last went to: an Adoption can transfer a
+// dog without its ownerName changing.
+func transferred(transfers []OwnershipTransfer, previous, owner string, now time.Time) ([]OwnershipTransfer, bool) {
+	if len(transfers) > 0 {
+		previous = transfers[len(transfers)-1].NewOwner
+	}
+	if householdKey(previous) == householdKey(owner) {
+		return transfers, false
+	}
+	return append(transfers, OwnershipTransfer{
+		PreviousOwner: strings.TrimSpace(previous),
+		NewOwner:      strings.TrimSpace(owner),
+		Date:          now.Format("2006-01-02T15:04:05Z"),
+	}), true
+}
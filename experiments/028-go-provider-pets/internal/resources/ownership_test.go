@@ -0,0 +1,41 @@
+package resources
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTransferred(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	earlier := []OwnershipTransfer{{PreviousOwner: "Sam Lee", NewOwner: "Alice Smith", Date: "2025-03-01T12:00:00Z"}}
+	tests := []struct {
+		name     string
+		previous string
+		owner    string
+		changed  bool
+	}{
+		{name: "same owner", previous: "Alice Smith", owner: "Alice Smith"},
+		{name: "respelled", previous: "Alice Smith", owner: " alice  SMITH"},
+		{name: "new owner", previous: "Alice Smith", owner: "Bob Jones ", changed: true},
+		{name: "from whoever it last went to", previous: "Sam Lee", owner: "Bob Jones", changed: true},
+		{name: "already transferred", previous: "Sam Lee", owner: "Alice Smith"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfers, changed := transferred(earlier, tt.previous, tt.owner, now)
+			if changed != tt.changed {
+				t.Fatalf("changed = %v, want %v", changed, tt.changed)
+			}
+			if !changed {
+				if len(transfers) != 1 {
+					t.Errorf("transfers = %+v, want only the earlier one", transfers)
+				}
+				return
+			}
+			want := OwnershipTransfer{PreviousOwner: "Alice Smith", NewOwner: "Bob Jones", Date: "2026-10-16T09:00:00Z"}
+			if len(transfers) != 2 || transfers[0] != earlier[0] || transfers[1] != want {
+				t.Errorf("transfers = %+v, want the earlier one then %+v", transfers, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// PetCamera Resource - a camera watching the pets, provisioned with the
+// cameraApi or a simulator. Changing how long footage is kept is applied to
+// the running camera; moving it or changing its resolution means setting
+// up a new one.
+type PetCamera struct{}
+
+//pets:state id=ID created=CreatedAt
+//pets:output ID string id Generated identifier of the camera
+//pets:output CreatedAt string createdAt When the camera was provisioned
+//pets:output CameraID string cameraId Identifier the camera service gave the camera
+//pets:output StorageGb float64 storageGb Estimated storage the retained footage takes, in gigabytes
+//pets:embed CameraStream
+type PetCameraArgs struct {
+	Location      string           `pulumi:"location" validate:"required"`                                // Where the camera is, e.g. "living room"
+	Resolution    CameraResolution `pulumi:"resolution" validate:"oneof=720p|1080p|1440p|4k"`             // Recording resolution
+	RetentionDays *int             `pulumi:"retentionDays,optional" default:"7" validate:"min=1,max=365"` // How many days of footage are kept
+}
+
+// CameraStream is where a camera can be watched live. The URL carries its
+// access token, so it is a secret.
+type CameraStream struct {
+	StreamURL string `pulumi:"streamUrl" provider:"secret"`
+}
+
+// cameraBitrateMbps is the typical bitrate a camera records at
+var cameraBitrateMbps = map[CameraResolution]float64{
+	Resolution720p:  2,
+	Resolution1080p: 4,
+	Resolution1440p: 6,
+	Resolution4K:    16,
+}
+
+// cameraRecord is a camera as the camera API describes it
+type cameraRecord struct {
+	ID            string           `json:"id,omitempty"`
+	Location      string           `json:"location,omitempty"`
+	Resolution    CameraResolution `json:"resolution,omitempty"`
+	RetentionDays int              `json:"retentionDays"`
+	StreamURL     string           `json:"streamUrl,omitempty"`
+}
+
+var cameras = crudResource[PetCameraArgs, PetCameraState, *PetCameraState]{
+	kind:     stored[PetCameraState]("camera"),
+	prefix:   "camera",
+	slug:     func(input PetCameraArgs) string { return slugify(input.Location) },
+	newState: newPetCameraState,
+	populate: func(ctx context.Context, state *PetCameraState, input PetCameraArgs) error {
+		if err := provisionCamera(ctx, state); err != nil {
+			return err
+		}
+		state.StorageGb = cameraStorageGb(input.Resolution, *input.RetentionDays)
+		return nil
+	},
+	keep: func(state *PetCameraState, oldState PetCameraState) {
+		state.CameraID = oldState.CameraID
+		state.CameraStream = oldState.CameraStream
+	},
+	carry: func(ctx context.Context, state *PetCameraState, oldState PetCameraState, now time.Time) error {
+		if *state.RetentionDays != *oldState.RetentionDays {
+			if err := setCameraRetention(ctx, state.CameraID, *state.RetentionDays); err != nil {
+				return err
+			}
+		}
+		state.StorageGb = cameraStorageGb(state.Resolution, *state.RetentionDays)
+		return nil
+	},
+}
+
+func (PetCamera) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (PetCameraArgs, []p.CheckFailure, error) {
+	return cameras.check(newInputs)
+}
+
+// Diff updates retention in place; anything else is a different camera
+func (PetCamera) Diff(ctx context.Context, id string, olds PetCameraState, news PetCameraArgs) (p.DiffResponse, error) {
+	diff := map[string]p.PropertyDiff{}
+	if news.Location != olds.Location {
+		diff["location"] = p.PropertyDiff{Kind: p.UpdateReplace, InputDiff: true}
+	}
+	if news.Resolution != olds.Resolution {
+		diff["resolution"] = p.PropertyDiff{Kind: p.UpdateReplace, InputDiff: true}
+	}
+	if news.RetentionDays != nil && olds.RetentionDays != nil && *news.RetentionDays != *olds.RetentionDays {
+		diff["retentionDays"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	return p.DiffResponse{HasChanges: len(diff) > 0, DetailedDiff: diff}, nil
+}
+
+func (PetCamera) Create(ctx context.Context, name string, input PetCameraArgs, preview bool) (string, PetCameraState, error) {
+	return cameras.create(ctx, name, input, preview)
+}
+
+func (PetCamera) Read(ctx context.Context, id string, inputs PetCameraArgs, state PetCameraState) (string, PetCameraArgs, PetCameraState, error) {
+	return cameras.read(ctx, id, inputs, state)
+}
+
+func (PetCamera) Update(ctx context.Context, id string, oldState PetCameraState, input PetCameraArgs, preview bool) (PetCameraState, error) {
+	return cameras.update(ctx, id, oldState, input, preview)
+}
+
+func (PetCamera) Delete(ctx context.Context, id string, state PetCameraState) error {
+	if err := removeCamera(ctx, state.CameraID); err != nil {
+		return err
+	}
+	return cameras.delete(ctx, id, state)
+}
+
+// cameraStorageGb estimates the footage a camera recording around the
+// clock keeps
+func cameraStorageGb(resolution CameraResolution, retentionDays int) float64 {
+	gbPerDay := cameraBitrateMbps[resolution] * 86400 / 8 / 1000
+	return round2(gbPerDay * float64(retentionDays))
+}
+
+// provisionCamera sets the camera up with the camera API, or has the
+// simulator hand out a stream URL with a fresh token
+func provisionCamera(ctx context.Context, state *PetCameraState) error {
+	api := registry.CameraAPI(ctx)
+	if api == nil {
+		token := make([]byte, 16)
+		if _, err := io.ReadFull(registry.Random(ctx, "camera/"+state.ID), token); err != nil {
+			return err
+		}
+		state.CameraID = state.ID
+		state.StreamURL = fmt.Sprintf("rtsp://cameras.pets.local/%s/live?token=%s", state.ID, hex.EncodeToString(token))
+		return nil
+	}
+
+	camera, err := cameraCall(ctx, api, http.MethodPost, "", cameraRecord{
+		Location:      state.Location,
+		Resolution:    state.Resolution,
+		RetentionDays: *state.RetentionDays,
+	})
+	if err != nil {
+		return fmt.Errorf("provisioning camera: %w", err)
+	}
+	if camera.ID == "" || camera.StreamURL == "" {
+		return fmt.Errorf("provisioning camera: the camera service returned no id or streamUrl")
+	}
+	state.CameraID, state.StreamURL = camera.ID, camera.StreamURL
+	return nil
+}
+
+func setCameraRetention(ctx context.Context, cameraID string, days int) error {
+	api := registry.CameraAPI(ctx)
+	if api == nil {
+		return nil
+	}
+	if _, err := cameraCall(ctx, api, http.MethodPatch, cameraID, cameraRecord{RetentionDays: days}); err != nil {
+		return fmt.Errorf("updating camera %s: %w", cameraID, err)
+	}
+	return nil
+}
+
+// removeCamera deprovisions a camera; one the service no longer knows is
+// already gone
+func removeCamera(ctx context.Context, cameraID string) error {
+	api := registry.CameraAPI(ctx)
+	if api == nil || cameraID == "" {
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, camerasURL(api, cameraID), nil)
+	if err != nil {
+		return err
+	}
+	if api.APIKey != nil {
+		req.Header.Set("Authorization", "Bearer "+*api.APIKey)
+	}
+	resp, err := registry.HTTPClient(ctx).Do(req)
+	if err != nil {
+		return fmt.Errorf("removing camera %s: %w", cameraID, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("removing camera %s: unexpected status %s", cameraID, resp.Status)
+	}
+	return nil
+}
+
+// cameraCall sends body to a camera, or to the collection when cameraID is
+// empty, and decodes the camera the service answers with
+func cameraCall(ctx context.Context, api *registry.DeviceAPIConfig, method, cameraID string, body cameraRecord) (cameraRecord, error) {
+	var camera cameraRecord
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return camera, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, camerasURL(api, cameraID), bytes.NewReader(payload))
+	if err != nil {
+		return camera, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := deviceCall(ctx, api, req)
+	if err != nil {
+		return camera, err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(&camera); err != nil && err != io.EOF {
+		return camera, err
+	}
+	return camera, nil
+}
+
+func camerasURL(api *registry.DeviceAPIConfig, cameraID string) string {
+	u := strings.TrimSuffix(api.BaseURL, "/") + "/cameras"
+	if cameraID != "" {
+		u += "/" + url.PathEscape(cameraID)
+	}
+	return u
+}
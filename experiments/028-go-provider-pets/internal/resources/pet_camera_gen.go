@@ -0,0 +1,55 @@
+// Code generated by genstate from pet_camera.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// PetCameraOutputs are computed by the provider; Check rejects them as inputs
+type PetCameraOutputs struct {
+	ID        string  `pulumi:"id"`
+	CreatedAt string  `pulumi:"createdAt"`
+	CameraID  string  `pulumi:"cameraId"`
+	StorageGb float64 `pulumi:"storageGb"`
+	Version   int64   `pulumi:"version"`
+	CameraStream
+}
+
+// PetCameraState echoes the inputs next to the computed outputs
+type PetCameraState struct {
+	PetCameraArgs
+	PetCameraOutputs
+}
+
+// newPetCameraState copies the inputs into an otherwise empty state
+func newPetCameraState(input PetCameraArgs) PetCameraState {
+	return PetCameraState{PetCameraArgs: input}
+}
+
+func (s *PetCameraState) stamp(id, created string)   { s.ID, s.CreatedAt = id, created }
+func (s *PetCameraState) identity() (string, string) { return s.ID, s.CreatedAt }
+func (s *PetCameraState) setVersion(version int64)   { s.Version = version }
+func (s *PetCameraState) storedVersion() int64       { return s.Version }
+
+func (args *PetCameraArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Location, "Where the camera is, e.g. \"living room\"")
+	a.Describe(&args.Resolution, "Recording resolution")
+	a.Describe(&args.RetentionDays, "How many days of footage are kept")
+	a.SetDefault(&args.RetentionDays, 7)
+}
+
+// applyDefaults fills unset optional inputs with their schema defaults
+func (args *PetCameraArgs) applyDefaults() {
+	if args.RetentionDays == nil {
+		v := 7
+		args.RetentionDays = &v
+	}
+}
+
+func (state *PetCameraState) Annotate(a infer.Annotator) {
+	state.PetCameraArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the camera")
+	a.Describe(&state.CreatedAt, "When the camera was provisioned")
+	a.Describe(&state.CameraID, "Identifier the camera service gave the camera")
+	a.Describe(&state.StorageGb, "Estimated storage the retained footage takes, in gigabytes")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
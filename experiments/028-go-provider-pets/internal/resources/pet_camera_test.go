@@ -0,0 +1,72 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+func TestCameraStorageGb(t *testing.T) {
+	tests := []struct {
+		resolution CameraResolution
+		days       int
+		want       float64
+	}{
+		{resolution: Resolution720p, days: 1, want: 21.6},
+		{resolution: Resolution1080p, days: 7, want: 302.4},
+		{resolution: Resolution4K, days: 30, want: 5184},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.resolution), func(t *testing.T) {
+			if got := cameraStorageGb(tt.resolution, tt.days); got != tt.want {
+				t.Errorf("storage = %g GB, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPetCameraDiff(t *testing.T) {
+	var olds PetCameraState
+	olds.PetCameraArgs = PetCameraArgs{Location: "living room", Resolution: Resolution1080p, RetentionDays: intPtr(7)}
+	olds.StreamURL, olds.StorageGb = "rtsp://cameras.pets.local/camera-1/live?token=abc", 302.4
+
+	tests := []struct {
+		name  string
+		news  PetCameraArgs
+		diffs map[string]p.DiffKind
+	}{
+		{name: "unchanged", news: olds.PetCameraArgs, diffs: map[string]p.DiffKind{}},
+		{
+			name:  "retention",
+			news:  PetCameraArgs{Location: "living room", Resolution: Resolution1080p, RetentionDays: intPtr(30)},
+			diffs: map[string]p.DiffKind{"retentionDays": p.Update},
+		},
+		{
+			name:  "resolution",
+			news:  PetCameraArgs{Location: "living room", Resolution: Resolution4K, RetentionDays: intPtr(7)},
+			diffs: map[string]p.DiffKind{"resolution": p.UpdateReplace},
+		},
+		{
+			name:  "moved and retention",
+			news:  PetCameraArgs{Location: "kitchen", Resolution: Resolution1080p, RetentionDays: intPtr(14)},
+			diffs: map[string]p.DiffKind{"location": p.UpdateReplace, "retentionDays": p.Update},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := PetCamera{}.Diff(context.Background(), "camera-1", olds, tt.news)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.HasChanges != (len(tt.diffs) > 0) || len(resp.DetailedDiff) != len(tt.diffs) {
+				t.Fatalf("diff = %+v, want %v", resp.DetailedDiff, tt.diffs)
+			}
+			for key, kind := range tt.diffs {
+				if resp.DetailedDiff[key].Kind != kind {
+					t.Errorf("%s: kind %v, want %v", key, resp.DetailedDiff[key].Kind, kind)
+				}
+			}
+		})
+	}
+}
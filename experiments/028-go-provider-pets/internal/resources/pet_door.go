@@ -0,0 +1,180 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// PetDoor Resource - an RFID pet door that only opens for the Microchips on
+// its allowlist. A household is the dogs sharing an ownerName, and only
+// their chips may be listed. Read simulates the comings and goings of each
+// listed dog, busier for more energetic breeds.
+type PetDoor struct{}
+
+//pets:state id=ID created=InstalledAt
+//pets:output ID string id Generated identifier of the door
+//pets:output InstalledAt string installedAt When the door was installed
+//pets:output Activity []DoorActivity activity Entries and exits of each listed dog since the door was installed; recomputed on refresh
+type PetDoorArgs struct {
+	OwnerName string   `pulumi:"ownerName" validate:"required"` // The household: dogs registered with this ownerName
+	Location  *string  `pulumi:"location,optional"`             // Where the door is fitted, e.g. "back door"
+	Allowlist []string `pulumi:"allowlist"`                     // IDs of the Microchip resources the door opens for
+}
+
+// DoorActivity is how often one dog has used a door
+type DoorActivity struct {
+	MicrochipID string `pulumi:"microchipId" json:"microchipId"`
+	DogID       string `pulumi:"dogId" json:"dogId"`
+	Entries     int    `pulumi:"entries" json:"entries"`
+	Exits       int    `pulumi:"exits" json:"exits"`
+	Outside     bool   `pulumi:"outside" json:"outside"`
+}
+
+// doorPet is a listed chip and the dog it is implanted in
+type doorPet struct {
+	chip MicrochipState
+	dog  DogState
+}
+
+// tripsPerEnergy is the trips outside a day per point of breed energy
+const tripsPerEnergy = 2
+
+var doors = crudResource[PetDoorArgs, PetDoorState, *PetDoorState]{
+	kind:     stored[PetDoorState]("door"),
+	prefix:   "door",
+	slug:     func(input PetDoorArgs) string { return slugify(input.OwnerName) },
+	newState: newPetDoorState,
+	populate: func(ctx context.Context, state *PetDoorState, input PetDoorArgs) error {
+		return countPassages(ctx, state, registry.Now(ctx), true)
+	},
+	carry: func(ctx context.Context, state *PetDoorState, oldState PetDoorState, now time.Time) error {
+		return countPassages(ctx, state, now, true)
+	},
+	refresh: func(ctx context.Context, id string, state *PetDoorState) error {
+		return countPassages(ctx, state, registry.Now(ctx), false)
+	},
+}
+
+func (PetDoor) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (PetDoorArgs, []p.CheckFailure, error) {
+	args, failures, err := doors.check(newInputs)
+	// An unreachable registry leaves the chips to Create to verify
+	_, allowlistFailures, _ := householdPets(ctx, args.OwnerName, args.Allowlist)
+	return args, append(failures, allowlistFailures...), err
+}
+
+func (PetDoor) Create(ctx context.Context, name string, input PetDoorArgs, preview bool) (string, PetDoorState, error) {
+	return doors.create(ctx, name, input, preview)
+}
+
+func (PetDoor) Read(ctx context.Context, id string, inputs PetDoorArgs, state PetDoorState) (string, PetDoorArgs, PetDoorState, error) {
+	return doors.read(ctx, id, inputs, state)
+}
+
+func (PetDoor) Update(ctx context.Context, id string, oldState PetDoorState, input PetDoorArgs, preview bool) (PetDoorState, error) {
+	return doors.update(ctx, id, oldState, input, preview)
+}
+
+func (PetDoor) Delete(ctx context.Context, id string, state PetDoorState) error {
+	return doors.delete(ctx, id, state)
+}
+
+// householdPets loads the chips on an allowlist and their dogs, reporting
+// chips that are listed twice, not registered, or implanted in a dog from
+// another household. IDs not known yet, as in a preview, are skipped.
+func householdPets(ctx context.Context, ownerName string, allowlist []string) ([]doorPet, []p.CheckFailure, error) {
+	var pets []doorPet
+	var failures []p.CheckFailure
+	seen := map[string]bool{}
+	for i, chipID := range allowlist {
+		property := fmt.Sprintf("allowlist[%d]", i)
+		if chipID == "" {
+			continue
+		}
+		if seen[chipID] {
+			failures = append(failures, p.CheckFailure{Property: property, Reason: fmt.Sprintf("microchip %s is already on the allowlist", chipID)})
+			continue
+		}
+		seen[chipID] = true
+
+		var pet doorPet
+		_, err := registry.Load(ctx, "microchip", chipID, &pet.chip)
+		if errors.Is(err, backend.ErrNotFound) {
+			failures = append(failures, p.CheckFailure{Property: property, Reason: fmt.Sprintf("microchip %s is not registered", chipID)})
+			continue
+		}
+		if err != nil {
+			return pets, failures, err
+		}
+		if pet.dog, err = walkedDog(ctx, pet.chip.DogID); err != nil {
+			failures = append(failures, p.CheckFailure{Property: property, Reason: fmt.Sprintf("microchip %s: %v", chipID, err)})
+			continue
+		}
+		if !sameHousehold(pet.dog.OwnerName, ownerName) {
+			failures = append(failures, p.CheckFailure{
+				Property: property,
+				Reason:   fmt.Sprintf("microchip %s is implanted in %s, who belongs to %s's household, not %s's", chipID, pet.dog.Name, pet.dog.OwnerName, ownerName),
+			})
+			continue
+		}
+		pets = append(pets, pet)
+	}
+	return pets, failures, nil
+}
+
+func sameHousehold(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// countPassages recomputes the activity of the listed dogs. Create and
+// Update refuse an allowlist that doesn't check out; a refresh just leaves
+// out chips that have gone since.
+func countPassages(ctx context.Context, state *PetDoorState, now time.Time, strict bool) error {
+	pets, failures, err := householdPets(ctx, state.OwnerName, state.Allowlist)
+	if err != nil {
+		return err
+	}
+	if strict && len(failures) > 0 {
+		return fmt.Errorf("%s: %s", failures[0].Property, failures[0].Reason)
+	}
+	installed, err := time.Parse("2006-01-02T15:04:05Z", state.InstalledAt)
+	if err != nil {
+		return fmt.Errorf("door %s: %w", state.ID, err)
+	}
+	state.Activity = doorActivity(pets, installed, now)
+	return nil
+}
+
+// doorActivity simulates each dog going out a few times a day, more often
+// the more energetic its breed, and coming back in half a trip later
+func doorActivity(pets []doorPet, installed, now time.Time) []DoorActivity {
+	activity := []DoorActivity{}
+	days := now.Sub(installed).Hours() / 24
+	if days < 0 {
+		days = 0
+	}
+	for _, pet := range pets {
+		energy := BreedCatalog[pet.dog.Breed].EnergyLevel
+		if energy == 0 {
+			energy = 3
+		}
+		trips := days * float64(energy*tripsPerEnergy)
+		exits, entries := int(trips+0.5), int(trips)
+		activity = append(activity, DoorActivity{
+			MicrochipID: pet.chip.ID,
+			DogID:       pet.chip.DogID,
+			Entries:     entries,
+			Exits:       exits,
+			Outside:     exits > entries,
+		})
+	}
+	return activity
+}
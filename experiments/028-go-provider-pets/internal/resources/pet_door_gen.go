@@ -0,0 +1,41 @@
+// Code generated by genstate from pet_door.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// PetDoorOutputs are computed by the provider; Check rejects them as inputs
+type PetDoorOutputs struct {
+	ID          string         `pulumi:"id"`
+	InstalledAt string         `pulumi:"installedAt"`
+	Activity    []DoorActivity `pulumi:"activity"`
+	Version     int64          `pulumi:"version"`
+}
+
+// PetDoorState echoes the inputs next to the computed outputs
+type PetDoorState struct {
+	PetDoorArgs
+	PetDoorOutputs
+}
+
+// newPetDoorState copies the inputs into an otherwise empty state
+func newPetDoorState(input PetDoorArgs) PetDoorState { return PetDoorState{PetDoorArgs: input} }
+
+func (s *PetDoorState) stamp(id, created string)   { s.ID, s.InstalledAt = id, created }
+func (s *PetDoorState) identity() (string, string) { return s.ID, s.InstalledAt }
+func (s *PetDoorState) setVersion(version int64)   { s.Version = version }
+func (s *PetDoorState) storedVersion() int64       { return s.Version }
+
+func (args *PetDoorArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.OwnerName, "The household: dogs registered with this ownerName")
+	a.Describe(&args.Location, "Where the door is fitted, e.g. \"back door\"")
+	a.Describe(&args.Allowlist, "IDs of the Microchip resources the door opens for")
+}
+
+func (state *PetDoorState) Annotate(a infer.Annotator) {
+	state.PetDoorArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the door")
+	a.Describe(&state.InstalledAt, "When the door was installed")
+	a.Describe(&state.Activity, "Entries and exits of each listed dog since the door was installed; recomputed on refresh")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
@@ -0,0 +1,69 @@
+package resources
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDoorActivity(t *testing.T) {
+	installed := time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)
+	pet := func(chipID string, breed DogBreed) doorPet {
+		var pet doorPet
+		pet.chip.ID, pet.chip.DogID = chipID, "dog-"+chipID
+		pet.dog.Breed = breed
+		return pet
+	}
+	husky, bulldog := pet("chip-a", Husky), pet("chip-b", Bulldog)
+	huskyTrips := BreedCatalog[Husky].EnergyLevel * tripsPerEnergy
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		entries int
+		exits   int
+	}{
+		{name: "just installed", now: installed},
+		{name: "before installation", now: installed.Add(-time.Hour)},
+		{name: "one day", now: installed.AddDate(0, 0, 1), entries: huskyTrips, exits: huskyTrips},
+		{
+			name:    "out on a trip",
+			now:     installed.Add(time.Duration(36/float64(huskyTrips)*float64(time.Hour)) + time.Hour),
+			entries: 1,
+			exits:   2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activity := doorActivity([]doorPet{husky, bulldog}, installed, tt.now)
+			if len(activity) != 2 {
+				t.Fatalf("activity = %+v, want both dogs", activity)
+			}
+			got := activity[0]
+			if got.MicrochipID != "chip-a" || got.DogID != "dog-chip-a" {
+				t.Errorf("activity for %s/%s, want chip-a/dog-chip-a", got.MicrochipID, got.DogID)
+			}
+			if got.Entries != tt.entries || got.Exits != tt.exits || got.Outside != (tt.exits > tt.entries) {
+				t.Errorf("entries %d exits %d outside %v, want %d and %d", got.Entries, got.Exits, got.Outside, tt.entries, tt.exits)
+			}
+			if activity[1].Exits > got.Exits {
+				t.Errorf("bulldog went out %d times, more than the husky's %d", activity[1].Exits, got.Exits)
+			}
+		})
+	}
+}
+
+func TestSameHousehold(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "Jane Doe", b: "Jane Doe", want: true},
+		{a: "jane doe ", b: "Jane Doe", want: true},
+		{a: "Jane Doe", b: "John Doe"},
+	}
+	for _, tt := range tests {
+		if got := sameHousehold(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameHousehold(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
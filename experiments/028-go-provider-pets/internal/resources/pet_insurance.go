@@ -0,0 +1,128 @@
+package resources
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// PetInsurance Resource - a policy on one dog at the premium its insurer
+// quoted, for instance through comparePolicies. A household insuring more
+// than one dog, by the dogs' ownerName, gets the provider's multi-pet
+// discount on every policy; Read re-applies it as other policies come and
+// go.
+type PetInsurance struct{}
+
+//pets:state id=PolicyNumber created=StartDate
+//pets:output PolicyNumber string policyNumber Generated policy number
+//pets:output StartDate string startDate When the policy started
+//pets:output Household string household The owner whose dogs count towards the multi-pet discount
+//pets:output SiblingPolicies int siblingPolicies Other dogs in the household with a policy
+//pets:output DiscountPercent float64 discountPercent Multi-pet discount applied to the premium
+//pets:output DiscountedPremium float64 discountedPremium Monthly premium after the discount
+//pets:output AnnualCost float64 annualCost Twelve discounted premiums
+//pets:embed CostDisplay
+type PetInsuranceArgs struct {
+	DogID          string       `pulumi:"dogId" validate:"required"`
+	Insurer        string       `pulumi:"insurer" validate:"required"`
+	CoverageType   CoverageType `pulumi:"coverageType" validate:"oneof=accident-only|accident-illness|comprehensive"`
+	Deductible     int          `pulumi:"deductible" validate:"min=0"`            // Dollars a year
+	Reimbursement  int          `pulumi:"reimbursement" validate:"min=1,max=100"` // Percent of the vet bill paid back
+	AnnualLimit    int          `pulumi:"annualLimit" validate:"min=0"`           // Dollars a year, 0 for unlimited
+	MonthlyPremium float64      `pulumi:"monthlyPremium" validate:"gt=0"`         // The insurer's quote before discounts
+}
+
+var policies = crudResource[PetInsuranceArgs, PetInsuranceState, *PetInsuranceState]{
+	kind:     stored[PetInsuranceState]("insurance"),
+	prefix:   "policy",
+	slug:     func(input PetInsuranceArgs) string { return input.DogID },
+	newState: newPetInsuranceState,
+	populate: func(ctx context.Context, state *PetInsuranceState, input PetInsuranceArgs) error {
+		return priceHouseholdPolicy(ctx, state)
+	},
+	carry: func(ctx context.Context, state *PetInsuranceState, oldState PetInsuranceState, now time.Time) error {
+		return priceHouseholdPolicy(ctx, state)
+	},
+	refresh: func(ctx context.Context, id string, state *PetInsuranceState) error {
+		return priceHouseholdPolicy(ctx, state)
+	},
+}
+
+func (PetInsurance) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (PetInsuranceArgs, []p.CheckFailure, error) {
+	return policies.check(newInputs)
+}
+
+func (PetInsurance) Create(ctx context.Context, name string, input PetInsuranceArgs, preview bool) (string, PetInsuranceState, error) {
+	return policies.create(ctx, name, input, preview)
+}
+
+func (PetInsurance) Read(ctx context.Context, id string, inputs PetInsuranceArgs, state PetInsuranceState) (string, PetInsuranceArgs, PetInsuranceState, error) {
+	return policies.read(ctx, id, inputs, state)
+}
+
+func (PetInsurance) Update(ctx context.Context, id string, oldState PetInsuranceState, input PetInsuranceArgs, preview bool) (PetInsuranceState, error) {
+	return policies.update(ctx, id, oldState, input, preview)
+}
+
+func (PetInsurance) Delete(ctx context.Context, id string, state PetInsuranceState) error {
+	return policies.delete(ctx, id, state)
+}
+
+// priceHouseholdPolicy looks up the other policies in the dog's household
+// and discounts the premium when there are any
+func priceHouseholdPolicy(ctx context.Context, state *PetInsuranceState) error {
+	dog, err := walkedDog(ctx, state.DogID)
+	if err != nil {
+		return err
+	}
+	state.Household = householdKey(dog.OwnerName)
+	household, err := listRecords[PetInsuranceState](ctx, backend.Query{
+		Kind:  "insurance",
+		Where: []backend.Condition{{Field: "Household", Op: "eq", Value: state.Household}},
+	})
+	if err != nil {
+		return err
+	}
+	state.SiblingPolicies = siblingDogs(household, *state)
+	discount := 0.0
+	if state.SiblingPolicies > 0 {
+		discount = registry.MultiPetDiscount(ctx)
+	}
+	applyDiscount(state, discount)
+	state.CostDisplay = showCosts(ctx, state.costs())
+	return nil
+}
+
+func (s PetInsuranceState) costs() map[string]float64 {
+	return map[string]float64{"monthlyPremium": s.MonthlyPremium, "discountedPremium": s.DiscountedPremium, "annualCost": s.AnnualCost}
+}
+
+// householdKey is how policies record a household, so spelling and spacing
+// of the owner's name don't split one
+func householdKey(ownerName string) string {
+	return strings.ToLower(strings.Join(strings.Fields(ownerName), " "))
+}
+
+// siblingDogs counts the other dogs insured in a household. A dog with two
+// policies counts once, and the policy's own dog not at all.
+func siblingDogs(household []PetInsuranceState, policy PetInsuranceState) int {
+	dogs := map[string]bool{}
+	for _, other := range household {
+		if other.PolicyNumber != policy.PolicyNumber && other.DogID != policy.DogID {
+			dogs[other.DogID] = true
+		}
+	}
+	return len(dogs)
+}
+
+func applyDiscount(state *PetInsuranceState, percent float64) {
+	state.DiscountPercent = percent
+	state.DiscountedPremium = round2(state.MonthlyPremium * (1 - percent/100))
+	state.AnnualCost = round2(state.DiscountedPremium * 12)
+}
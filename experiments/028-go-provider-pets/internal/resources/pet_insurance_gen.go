@@ -0,0 +1,53 @@
+// Code generated by genstate from pet_insurance.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// PetInsuranceOutputs are computed by the provider; Check rejects them as inputs
+type PetInsuranceOutputs struct {
+	PolicyNumber      string  `pulumi:"policyNumber"`
+	StartDate         string  `pulumi:"startDate"`
+	Household         string  `pulumi:"household"`
+	SiblingPolicies   int     `pulumi:"siblingPolicies"`
+	DiscountPercent   float64 `pulumi:"discountPercent"`
+	DiscountedPremium float64 `pulumi:"discountedPremium"`
+	AnnualCost        float64 `pulumi:"annualCost"`
+	Version           int64   `pulumi:"version"`
+	CostDisplay
+}
+
+// PetInsuranceState echoes the inputs next to the computed outputs
+type PetInsuranceState struct {
+	PetInsuranceArgs
+	PetInsuranceOutputs
+}
+
+// newPetInsuranceState copies the inputs into an otherwise empty state
+func newPetInsuranceState(input PetInsuranceArgs) PetInsuranceState {
+	return PetInsuranceState{PetInsuranceArgs: input}
+}
+
+func (s *PetInsuranceState) stamp(id, created string)   { s.PolicyNumber, s.StartDate = id, created }
+func (s *PetInsuranceState) identity() (string, string) { return s.PolicyNumber, s.StartDate }
+func (s *PetInsuranceState) setVersion(version int64)   { s.Version = version }
+func (s *PetInsuranceState) storedVersion() int64       { return s.Version }
+
+func (args *PetInsuranceArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Deductible, "Dollars a year")
+	a.Describe(&args.Reimbursement, "Percent of the vet bill paid back")
+	a.Describe(&args.AnnualLimit, "Dollars a year, 0 for unlimited")
+	a.Describe(&args.MonthlyPremium, "The insurer's quote before discounts")
+}
+
+func (state *PetInsuranceState) Annotate(a infer.Annotator) {
+	state.PetInsuranceArgs.Annotate(a)
+	a.Describe(&state.PolicyNumber, "Generated policy number")
+	a.Describe(&state.StartDate, "When the policy started")
+	a.Describe(&state.Household, "The owner whose dogs count towards the multi-pet discount")
+	a.Describe(&state.SiblingPolicies, "Other dogs in the household with a policy")
+	a.Describe(&state.DiscountPercent, "Multi-pet discount applied to the premium")
+	a.Describe(&state.DiscountedPremium, "Monthly premium after the discount")
+	a.Describe(&state.AnnualCost, "Twelve discounted premiums")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
@@ -0,0 +1,68 @@
+package resources
+
+import "testing"
+
+func TestHouseholdKey(t *testing.T) {
+	for _, name := range []string{"Jane Doe", "jane doe", "  Jane   Doe "} {
+		if got := householdKey(name); got != "jane doe" {
+			t.Errorf("householdKey(%q) = %q, want %q", name, got, "jane doe")
+		}
+	}
+}
+
+func TestSiblingDogs(t *testing.T) {
+	policy := func(number, dogID string) PetInsuranceState {
+		var state PetInsuranceState
+		state.PolicyNumber, state.DogID = number, dogID
+		return state
+	}
+	rex := policy("policy-rex-1", "dog-rex")
+	tests := []struct {
+		name      string
+		household []PetInsuranceState
+		want      int
+	}{
+		{name: "only policy", household: []PetInsuranceState{rex}},
+		{name: "not saved yet"},
+		{name: "two dogs", household: []PetInsuranceState{rex, policy("policy-bella-1", "dog-bella")}, want: 1},
+		{
+			name:      "a sibling insured twice",
+			household: []PetInsuranceState{rex, policy("policy-bella-1", "dog-bella"), policy("policy-bella-2", "dog-bella")},
+			want:      1,
+		},
+		{name: "a second policy on the same dog", household: []PetInsuranceState{rex, policy("policy-rex-2", "dog-rex")}},
+		{
+			name:      "three dogs",
+			household: []PetInsuranceState{rex, policy("policy-bella-1", "dog-bella"), policy("policy-max-1", "dog-max")},
+			want:      2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := siblingDogs(tt.household, rex); got != tt.want {
+				t.Errorf("siblingDogs = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyDiscount(t *testing.T) {
+	tests := []struct {
+		premium    float64
+		percent    float64
+		discounted float64
+		annual     float64
+	}{
+		{premium: 48, percent: 0, discounted: 48, annual: 576},
+		{premium: 48, percent: 10, discounted: 43.2, annual: 518.4},
+		{premium: 30.62, percent: 15, discounted: 26.03, annual: 312.36},
+	}
+	for _, tt := range tests {
+		var state PetInsuranceState
+		state.MonthlyPremium = tt.premium
+		applyDiscount(&state, tt.percent)
+		if state.DiscountPercent != tt.percent || state.DiscountedPremium != tt.discounted || state.AnnualCost != tt.annual {
+			t.Errorf("%g at %g%% = %g/month, %g/year; want %g and %g", tt.premium, tt.percent, state.DiscountedPremium, state.AnnualCost, tt.discounted, tt.annual)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// PetShelter Resource - a shelter that intakes, adoption waitlists,
+// donations, volunteer shifts and listings refer to by its shelterId. The
+// ID is the shelter's name, lowercased with dashes for spaces, so there is
+// only ever one shelter of a name; renaming one replaces it. Its capacity
+// is what getShelterStatistics measures occupancy against, and a shelter
+// with a location is suggested to LostPetReports around it.
+type PetShelter struct{}
+
+//pets:state id=ShelterID created=RegisteredAt
+//pets:output ShelterID string shelterId The shelter's name, lowercased with dashes for spaces
+//pets:output RegisteredAt string registeredAt When the shelter was registered
+//pets:output WeeklyOpenHours float64 weeklyOpenHours Hours a week the shelter is open to the public
+type PetShelterArgs struct {
+	Name           string         `pulumi:"name" validate:"required"`
+	Address        string         `pulumi:"address" validate:"required"`
+	Capacity       int            `pulumi:"capacity" validate:"min=1"` // Kennels, the dogs the shelter has room for
+	OperatingHours []OpeningHours `pulumi:"operatingHours,optional"`   // When the shelter is open to the public; days left out are closed
+	Location       *LatLng        `pulumi:"location,optional"`         // Where the shelter is, for finding it near a lost dog
+}
+
+// OpeningHours is when a shelter opens and closes on a day of the week
+type OpeningHours struct {
+	Day    string `pulumi:"day" json:"day"`       // Day of the week, e.g. monday
+	Opens  string `pulumi:"opens" json:"opens"`   // HH:MM
+	Closes string `pulumi:"closes" json:"closes"` // HH:MM, after opens
+}
+
+var shelters = crudResource[PetShelterArgs, PetShelterState, *PetShelterState]{
+	kind:      stored[PetShelterState]("shelter"),
+	prefix:    "shelter",
+	slug:      func(input PetShelterArgs) string { return ShelterID(input.Name) },
+	naturalID: func(input PetShelterArgs) string { return ShelterID(input.Name) },
+	newState:  newPetShelterState,
+	populate: func(ctx context.Context, state *PetShelterState, input PetShelterArgs) error {
+		state.WeeklyOpenHours = weeklyOpenHours(input.OperatingHours)
+		return nil
+	},
+	carry: func(ctx context.Context, state *PetShelterState, oldState PetShelterState, now time.Time) error {
+		state.WeeklyOpenHours = weeklyOpenHours(state.OperatingHours)
+		return nil
+	},
+}
+
+func (PetShelter) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (PetShelterArgs, []p.CheckFailure, error) {
+	args, failures, err := shelters.check(newInputs)
+	if args.Name != "" && ShelterID(args.Name) == "" {
+		failures = append(failures, p.CheckFailure{Property: "name", Reason: "name must not be blank"})
+	}
+	if args.Location != nil {
+		if reason := checkLatLng(*args.Location); reason != "" {
+			failures = append(failures, p.CheckFailure{Property: "location", Reason: reason})
+		}
+	}
+	return args, append(failures, checkOpeningHours(args.OperatingHours)...), err
+}
+
+func (PetShelter) Diff(ctx context.Context, id string, olds PetShelterState, news PetShelterArgs) (p.DiffResponse, error) {
+	// The name is the shelter's ID, so a new name is a new shelter
+	diff := map[string]p.PropertyDiff{}
+	if olds.Name != news.Name {
+		diff["name"] = p.PropertyDiff{Kind: p.UpdateReplace, InputDiff: true}
+	}
+	if olds.Address != news.Address {
+		diff["address"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	if olds.Capacity != news.Capacity {
+		diff["capacity"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	if !reflect.DeepEqual(olds.OperatingHours, news.OperatingHours) {
+		diff["operatingHours"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	if !reflect.DeepEqual(olds.Location, news.Location) {
+		diff["location"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	return p.DiffResponse{HasChanges: len(diff) > 0, DetailedDiff: diff}, nil
+}
+
+func (PetShelter) Create(ctx context.Context, name string, input PetShelterArgs, preview bool) (string, PetShelterState, error) {
+	return shelters.create(ctx, name, input, preview)
+}
+
+func (PetShelter) Read(ctx context.Context, id string, inputs PetShelterArgs, state PetShelterState) (string, PetShelterArgs, PetShelterState, error) {
+	return shelters.read(ctx, id, inputs, state)
+}
+
+func (PetShelter) Update(ctx context.Context, id string, oldState PetShelterState, input PetShelterArgs, preview bool) (PetShelterState, error) {
+	return shelters.update(ctx, id, oldState, input, preview)
+}
+
+func (PetShelter) Delete(ctx context.Context, id string, state PetShelterState) error {
+	return shelters.delete(ctx, id, state)
+}
+
+func checkOpeningHours(hours []OpeningHours) []p.CheckFailure {
+	var failures []p.CheckFailure
+	seen := map[string]bool{}
+	for i, day := range hours {
+		property := fmt.Sprintf("operatingHours[%d]", i)
+		weekday := strings.ToLower(day.Day)
+		if _, ok := weekdays[weekday]; !ok {
+			failures = append(failures, p.CheckFailure{Property: property + ".day", Reason: "day must be a day of the week, e.g. monday"})
+		} else if seen[weekday] {
+			failures = append(failures, p.CheckFailure{Property: property + ".day", Reason: fmt.Sprintf("%s is listed more than once", weekday)})
+		}
+		seen[weekday] = true
+
+		opens, oerr := time.Parse("15:04", day.Opens)
+		if oerr != nil {
+			failures = append(failures, p.CheckFailure{Property: property + ".opens", Reason: "opens must be a time of day like 09:00"})
+		}
+		closes, cerr := time.Parse("15:04", day.Closes)
+		if cerr != nil {
+			failures = append(failures, p.CheckFailure{Property: property + ".closes", Reason: "closes must be a time of day like 17:30"})
+		}
+		if oerr == nil && cerr == nil && !closes.After(opens) {
+			failures = append(failures, p.CheckFailure{Property: property + ".closes", Reason: "closes must be after opens"})
+		}
+	}
+	return failures
+}
+
+// weeklyOpenHours adds up the opening hours, which Check has validated
+func weeklyOpenHours(hours []OpeningHours) float64 {
+	total := 0.0
+	for _, day := range hours {
+		opens, _ := time.Parse("15:04", day.Opens)
+		closes, _ := time.Parse("15:04", day.Closes)
+		total += closes.Sub(opens).Hours()
+	}
+	return round2(total)
+}
+
+// loadShelter is the registered PetShelter with the ID
+func loadShelter(ctx context.Context, shelterID string) (PetShelterState, error) {
+	var shelter PetShelterState
+	_, err := registry.Load(ctx, "shelter", shelterID, &shelter)
+	if errors.Is(err, backend.ErrNotFound) {
+		return shelter, fmt.Errorf("no shelter %s is registered; create a PetShelter for it first", shelterID)
+	}
+	return shelter, err
+}
+
+// requireShelter fails unless a PetShelter with the ID is registered
+func requireShelter(ctx context.Context, shelterID string) error {
+	_, err := loadShelter(ctx, shelterID)
+	return err
+}
+
+// ShelterCapacity is how many dogs the shelter has room for: its
+// PetShelter's capacity, else the provider's shelterCapacity config, else 0
+func ShelterCapacity(ctx context.Context, shelterID string) (int, error) {
+	var shelter PetShelterState
+	_, err := registry.Load(ctx, "shelter", shelterID, &shelter)
+	if errors.Is(err, backend.ErrNotFound) {
+		return registry.ShelterCapacity(ctx, shelterID), nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return shelter.Capacity, nil
+}
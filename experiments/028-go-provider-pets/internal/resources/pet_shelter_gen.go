@@ -0,0 +1,43 @@
+// Code generated by genstate from pet_shelter.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// PetShelterOutputs are computed by the provider; Check rejects them as inputs
+type PetShelterOutputs struct {
+	ShelterID       string  `pulumi:"shelterId"`
+	RegisteredAt    string  `pulumi:"registeredAt"`
+	WeeklyOpenHours float64 `pulumi:"weeklyOpenHours"`
+	Version         int64   `pulumi:"version"`
+}
+
+// PetShelterState echoes the inputs next to the computed outputs
+type PetShelterState struct {
+	PetShelterArgs
+	PetShelterOutputs
+}
+
+// newPetShelterState copies the inputs into an otherwise empty state
+func newPetShelterState(input PetShelterArgs) PetShelterState {
+	return PetShelterState{PetShelterArgs: input}
+}
+
+func (s *PetShelterState) stamp(id, created string)   { s.ShelterID, s.RegisteredAt = id, created }
+func (s *PetShelterState) identity() (string, string) { return s.ShelterID, s.RegisteredAt }
+func (s *PetShelterState) setVersion(version int64)   { s.Version = version }
+func (s *PetShelterState) storedVersion() int64       { return s.Version }
+
+func (args *PetShelterArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Capacity, "Kennels, the dogs the shelter has room for")
+	a.Describe(&args.OperatingHours, "When the shelter is open to the public; days left out are closed")
+	a.Describe(&args.Location, "Where the shelter is, for finding it near a lost dog")
+}
+
+func (state *PetShelterState) Annotate(a infer.Annotator) {
+	state.PetShelterArgs.Annotate(a)
+	a.Describe(&state.ShelterID, "The shelter's name, lowercased with dashes for spaces")
+	a.Describe(&state.RegisteredAt, "When the shelter was registered")
+	a.Describe(&state.WeeklyOpenHours, "Hours a week the shelter is open to the public")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
@@ -0,0 +1,67 @@
+package resources
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckOpeningHours(t *testing.T) {
+	tests := []struct {
+		name   string
+		hours  []OpeningHours
+		failed []string
+	}{
+		{name: "closed all week"},
+		{name: "weekdays", hours: []OpeningHours{{Day: "monday", Opens: "09:00", Closes: "17:00"}, {Day: "Friday", Opens: "10:00", Closes: "16:30"}}},
+		{name: "not a day", hours: []OpeningHours{{Day: "funday", Opens: "09:00", Closes: "17:00"}}, failed: []string{"operatingHours[0].day"}},
+		{
+			name:   "twice on a day",
+			hours:  []OpeningHours{{Day: "monday", Opens: "09:00", Closes: "12:00"}, {Day: "Monday", Opens: "13:00", Closes: "17:00"}},
+			failed: []string{"operatingHours[1].day"},
+		},
+		{name: "closes first", hours: []OpeningHours{{Day: "sunday", Opens: "17:00", Closes: "09:00"}}, failed: []string{"operatingHours[0].closes"}},
+		{name: "not times", hours: []OpeningHours{{Day: "sunday", Opens: "9am", Closes: "5pm"}}, failed: []string{"operatingHours[0].opens", "operatingHours[0].closes"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, f := range checkOpeningHours(tt.hours) {
+				got = append(got, f.Property)
+			}
+			if !reflect.DeepEqual(got, tt.failed) {
+				t.Errorf("failures on %v, want %v", got, tt.failed)
+			}
+		})
+	}
+}
+
+func TestWeeklyOpenHours(t *testing.T) {
+	hours := []OpeningHours{
+		{Day: "monday", Opens: "09:00", Closes: "17:00"},
+		{Day: "saturday", Opens: "10:00", Closes: "14:30"},
+	}
+	if got := weeklyOpenHours(hours); got != 12.5 {
+		t.Errorf("weeklyOpenHours = %v, want 12.5", got)
+	}
+	if got := weeklyOpenHours(nil); got != 0 {
+		t.Errorf("weeklyOpenHours(nil) = %v, want 0", got)
+	}
+}
+
+func TestBulkDogIntakeShelter(t *testing.T) {
+	tests := []struct {
+		name   string
+		intake BulkDogIntakeState
+		want   string
+	}{
+		{name: "by ID", intake: BulkDogIntakeState{BulkDogIntakeArgs: BulkDogIntakeArgs{ShelterID: "happy-tails"}}, want: "happy-tails"},
+		{name: "recorded by name", intake: BulkDogIntakeState{BulkDogIntakeOutputs: BulkDogIntakeOutputs{ShelterName: "Happy  Tails"}}, want: "happy-tails"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.intake.Shelter(); got != tt.want {
+				t.Errorf("Shelter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
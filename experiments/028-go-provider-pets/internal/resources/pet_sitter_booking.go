@@ -0,0 +1,262 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+)
+
+// PetSitterBooking Resource - a sitter looking after one or more dogs at
+// home, dropping in a number of times a day. A sitter can't be booked for
+// two stays that overlap. The booking is priced per visit, with a
+// surcharge for each dog after the first, and comes with care
+// instructions drawn from what the registry knows about each dog: its
+// feeders, diet changes during the stay, allergies, latest medications
+// and preferences. Changing the times or dogs rechecks the sitter's
+// calendar and redoes both.
+type PetSitterBooking struct{}
+
+//pets:state id=ID created=BookedAt
+//pets:output ID string id Generated identifier of the booking
+//pets:output BookedAt string bookedAt When the booking was made
+//pets:output Days int days Days the stay spans, counting a part day as a whole one
+//pets:output Visits int visits Visits the sitter makes over the stay
+//pets:output Cost float64 cost What the stay costs, in dollars
+//pets:output CareInstructions string careInstructions What the sitter needs to know about each dog, as plain text
+//pets:embed CostDisplay
+type PetSitterBookingArgs struct {
+	SitterContact string   `pulumi:"sitterContact" provider:"secret" validate:"required"` // Identifies the sitter across bookings
+	DogIDs        []string `pulumi:"dogIds" validate:"required"`
+	Start         string   `pulumi:"start" validate:"required"`           // RFC 3339 timestamp
+	End           string   `pulumi:"end" validate:"required"`             // RFC 3339 timestamp
+	VisitsPerDay  int      `pulumi:"visitsPerDay" validate:"min=1,max=6"` // Drop-ins a day
+}
+
+// Sitter rates, in dollars
+const (
+	sitterVisitFee    = 25.0 // a visit to the first dog
+	sitterExtraDogFee = 10.0 // a visit, for each dog after the first
+	maxSitterDays     = 60
+)
+
+// lifeStageCare is what a sitter should keep in mind for a young or old dog
+var lifeStageCare = map[LifeStage]string{
+	LifeStagePuppy:  "Puppy: short play sessions, frequent toilet breaks, and keep chewables out of reach",
+	LifeStageSenior: "Senior: gentle walks, easy access to water and bed, and watch for stiffness",
+}
+
+// careSheet is what the registry knows about looking after one dog
+type careSheet struct {
+	dog         DogState
+	feeders     []SmartFeederState
+	transitions []DietTransitionState
+	allergies   []knownAllergy
+	lastVisit   *VeterinaryVisitState
+}
+
+var sitterBookings = crudResource[PetSitterBookingArgs, PetSitterBookingState, *PetSitterBookingState]{
+	kind:     stored[PetSitterBookingState]("sitter-booking"),
+	prefix:   "sit",
+	slug:     func(input PetSitterBookingArgs) string { return input.DogIDs[0] },
+	newState: newPetSitterBookingState,
+	populate: func(ctx context.Context, state *PetSitterBookingState, input PetSitterBookingArgs) error {
+		return bookSitter(ctx, state)
+	},
+	carry: func(ctx context.Context, state *PetSitterBookingState, oldState PetSitterBookingState, now time.Time) error {
+		return bookSitter(ctx, state)
+	},
+}
+
+func (PetSitterBooking) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (PetSitterBookingArgs, []p.CheckFailure, error) {
+	args, failures, err := sitterBookings.check(newInputs)
+	return args, append(failures, checkSitterBooking(args)...), err
+}
+
+func (PetSitterBooking) Create(ctx context.Context, name string, input PetSitterBookingArgs, preview bool) (string, PetSitterBookingState, error) {
+	return sitterBookings.create(ctx, name, input, preview)
+}
+
+func (PetSitterBooking) Read(ctx context.Context, id string, inputs PetSitterBookingArgs, state PetSitterBookingState) (string, PetSitterBookingArgs, PetSitterBookingState, error) {
+	return sitterBookings.read(ctx, id, inputs, state)
+}
+
+func (PetSitterBooking) Update(ctx context.Context, id string, oldState PetSitterBookingState, input PetSitterBookingArgs, preview bool) (PetSitterBookingState, error) {
+	return sitterBookings.update(ctx, id, oldState, input, preview)
+}
+
+func (PetSitterBooking) Delete(ctx context.Context, id string, state PetSitterBookingState) error {
+	return sitterBookings.delete(ctx, id, state)
+}
+
+func checkSitterBooking(args PetSitterBookingArgs) []p.CheckFailure {
+	var failures []p.CheckFailure
+	seen := map[string]bool{}
+	for i, dogID := range args.DogIDs {
+		if seen[dogID] {
+			failures = append(failures, p.CheckFailure{Property: fmt.Sprintf("dogIds[%d]", i), Reason: fmt.Sprintf("%s is listed more than once", dogID)})
+		}
+		seen[dogID] = true
+	}
+	start, serr := time.Parse(time.RFC3339, args.Start)
+	if args.Start != "" && serr != nil {
+		failures = append(failures, p.CheckFailure{Property: "start", Reason: "start must be an RFC 3339 timestamp"})
+	}
+	end, eerr := time.Parse(time.RFC3339, args.End)
+	if args.End != "" && eerr != nil {
+		failures = append(failures, p.CheckFailure{Property: "end", Reason: "end must be an RFC 3339 timestamp"})
+	}
+	if serr == nil && eerr == nil {
+		switch {
+		case !end.After(start):
+			failures = append(failures, p.CheckFailure{Property: "end", Reason: "end must be after start"})
+		case end.Sub(start) > maxSitterDays*24*time.Hour:
+			failures = append(failures, p.CheckFailure{Property: "end", Reason: fmt.Sprintf("stays last at most %d days", maxSitterDays)})
+		}
+	}
+	return failures
+}
+
+// span is the stay's start and end
+func (s PetSitterBookingArgs) span() (time.Time, time.Time) {
+	start, _ := time.Parse(time.RFC3339, s.Start)
+	end, _ := time.Parse(time.RFC3339, s.End)
+	return start, end
+}
+
+// bookSitter refuses a stay that overlaps another of the sitter's, then
+// prices it and writes up the care instructions
+func bookSitter(ctx context.Context, state *PetSitterBookingState) error {
+	booked, err := listRecords[PetSitterBookingState](ctx, backend.Query{
+		Kind:  "sitter-booking",
+		Where: []backend.Condition{{Field: "SitterContact", Op: "eq", Value: state.SitterContact}},
+	})
+	if err != nil {
+		return err
+	}
+	start, end := state.span()
+	for _, other := range booked {
+		otherStart, otherEnd := other.span()
+		if other.ID != state.ID && start.Before(otherEnd) && otherStart.Before(end) {
+			return fmt.Errorf("the sitter is already booked from %s to %s (%s)", other.Start, other.End, other.ID)
+		}
+	}
+
+	var sheets []careSheet
+	for _, dogID := range state.DogIDs {
+		sheet, err := loadCareSheet(ctx, dogID)
+		if err != nil {
+			return err
+		}
+		sheets = append(sheets, sheet)
+	}
+	state.Days, state.Visits, state.Cost = sitterCost(start, end, state.VisitsPerDay, len(state.DogIDs))
+	state.CareInstructions = careInstructions(sheets, start, end, state.VisitsPerDay)
+	state.CostDisplay = showCosts(ctx, map[string]float64{"cost": state.Cost})
+	return nil
+}
+
+// sitterCost prices a stay: every visit costs the base fee plus the extra
+// dog fee for each dog after the first
+func sitterCost(start, end time.Time, visitsPerDay, dogs int) (days, visits int, cost float64) {
+	days = int(math.Ceil(end.Sub(start).Hours() / 24))
+	visits = days * visitsPerDay
+	return days, visits, round2(float64(visits) * (sitterVisitFee + sitterExtraDogFee*float64(dogs-1)))
+}
+
+// loadCareSheet gathers the dog's records a sitter needs
+func loadCareSheet(ctx context.Context, dogID string) (careSheet, error) {
+	dog, err := walkedDog(ctx, dogID)
+	if err != nil {
+		return careSheet{}, err
+	}
+	sheet := careSheet{dog: dog}
+	if sheet.feeders, err = listForDog[SmartFeederState](ctx, "feeder", dogID); err != nil {
+		return sheet, err
+	}
+	if sheet.transitions, err = listForDog[DietTransitionState](ctx, "diet-transition", dogID); err != nil {
+		return sheet, err
+	}
+	if sheet.allergies, err = dogAllergies(ctx, dog); err != nil {
+		return sheet, err
+	}
+	visits, err := listForDog[VeterinaryVisitState](ctx, "visit", dogID)
+	if err != nil {
+		return sheet, err
+	}
+	for i := range visits {
+		if sheet.lastVisit == nil || visits[i].Date > sheet.lastVisit.Date {
+			sheet.lastVisit = &visits[i]
+		}
+	}
+	return sheet, nil
+}
+
+// careInstructions writes up the sheets for the stay from start to end
+func careInstructions(sheets []careSheet, start, end time.Time, visitsPerDay int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Care instructions for %s to %s, %d visit(s) a day\n", start.Format(dateLayout), end.Format(dateLayout), visitsPerDay)
+	for _, sheet := range sheets {
+		dog := sheet.dog
+		fmt.Fprintf(&b, "\n%s (%s, %d years, %s)\n", dog.Name, BreedCatalog[dog.Breed].Name, dog.years(), lifeStage(dogSize(dog), dogAgeYears(dog)))
+		for _, line := range sheet.lines(start, end) {
+			b.WriteString("- " + line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// lines are the instructions for one dog over the stay
+func (sheet careSheet) lines(start, end time.Time) []string {
+	var lines []string
+	for _, feeder := range sheet.feeders {
+		lines = append(lines, fmt.Sprintf("Feeding: feeder %s gives %d g at %s; feed by hand if it is offline", feeder.DeviceID, feeder.PortionGrams, strings.Join(feeder.Schedule, ", ")))
+	}
+	for _, transition := range sheet.transitions {
+		for _, day := range transition.Plan {
+			at, err := time.Parse(dateLayout, day.Date)
+			if err != nil || at.Before(start.Truncate(24*time.Hour)) || at.After(end) {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("Diet change on %s: %d%% %s, %d%% %s", day.Date, day.FromPercent, transition.FromFood, day.ToPercent, transition.ToFood))
+		}
+	}
+	for _, allergy := range sheet.allergies {
+		lines = append(lines, fmt.Sprintf("Allergic to %s (%s); keep it out of food and treats", allergy.Allergen, allergy.Source))
+	}
+	if sheet.lastVisit != nil && len(sheet.lastVisit.Medications) > 0 {
+		lines = append(lines, fmt.Sprintf("Medications from the %s visit on %s: %s", sheet.lastVisit.VisitType, strings.SplitN(sheet.lastVisit.Date, "T", 2)[0], strings.Join(sheet.lastVisit.Medications, ", ")))
+	}
+	if prefs := sheet.dog.Preferences; prefs != nil {
+		if len(prefs.FavoriteFoods) > 0 {
+			lines = append(lines, "Favorite foods: "+strings.Join(prefs.FavoriteFoods, ", "))
+		}
+		if len(prefs.ToyTypes) > 0 {
+			toys := make([]string, len(prefs.ToyTypes))
+			for i, toy := range prefs.ToyTypes {
+				toys[i] = string(toy)
+			}
+			lines = append(lines, "Favorite toys: "+strings.Join(toys, ", "))
+		}
+		if len(prefs.ActivityPreferences) > 0 {
+			activities := make([]string, len(prefs.ActivityPreferences))
+			for i, pref := range prefs.ActivityPreferences {
+				activities[i] = fmt.Sprintf("%s (%s)", pref.Activity, pref.Intensity)
+			}
+			lines = append(lines, "Likes: "+strings.Join(activities, ", "))
+		}
+	}
+	if advice := lifeStageCare[lifeStage(dogSize(sheet.dog), dogAgeYears(sheet.dog))]; advice != "" {
+		lines = append(lines, advice)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "Nothing special on record")
+	}
+	return lines
+}
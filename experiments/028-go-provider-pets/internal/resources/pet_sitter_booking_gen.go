@@ -0,0 +1,51 @@
+// Code generated by genstate from pet_sitter_booking.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// PetSitterBookingOutputs are computed by the provider; Check rejects them as inputs
+type PetSitterBookingOutputs struct {
+	ID               string  `pulumi:"id"`
+	BookedAt         string  `pulumi:"bookedAt"`
+	Days             int     `pulumi:"days"`
+	Visits           int     `pulumi:"visits"`
+	Cost             float64 `pulumi:"cost"`
+	CareInstructions string  `pulumi:"careInstructions"`
+	Version          int64   `pulumi:"version"`
+	CostDisplay
+}
+
+// PetSitterBookingState echoes the inputs next to the computed outputs
+type PetSitterBookingState struct {
+	PetSitterBookingArgs
+	PetSitterBookingOutputs
+}
+
+// newPetSitterBookingState copies the inputs into an otherwise empty state
+func newPetSitterBookingState(input PetSitterBookingArgs) PetSitterBookingState {
+	return PetSitterBookingState{PetSitterBookingArgs: input}
+}
+
+func (s *PetSitterBookingState) stamp(id, created string)   { s.ID, s.BookedAt = id, created }
+func (s *PetSitterBookingState) identity() (string, string) { return s.ID, s.BookedAt }
+func (s *PetSitterBookingState) setVersion(version int64)   { s.Version = version }
+func (s *PetSitterBookingState) storedVersion() int64       { return s.Version }
+
+func (args *PetSitterBookingArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.SitterContact, "Identifies the sitter across bookings")
+	a.Describe(&args.Start, "RFC 3339 timestamp")
+	a.Describe(&args.End, "RFC 3339 timestamp")
+	a.Describe(&args.VisitsPerDay, "Drop-ins a day")
+}
+
+func (state *PetSitterBookingState) Annotate(a infer.Annotator) {
+	state.PetSitterBookingArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the booking")
+	a.Describe(&state.BookedAt, "When the booking was made")
+	a.Describe(&state.Days, "Days the stay spans, counting a part day as a whole one")
+	a.Describe(&state.Visits, "Visits the sitter makes over the stay")
+	a.Describe(&state.Cost, "What the stay costs, in dollars")
+	a.Describe(&state.CareInstructions, "What the sitter needs to know about each dog, as plain text")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
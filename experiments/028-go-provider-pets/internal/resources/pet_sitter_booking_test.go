@@ -0,0 +1,117 @@
+package resources
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSitterCost(t *testing.T) {
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		hours        int
+		visitsPerDay int
+		dogs         int
+		days, visits int
+		cost         float64
+	}{
+		{name: "one day, one dog", hours: 24, visitsPerDay: 2, dogs: 1, days: 1, visits: 2, cost: 50},
+		{name: "part day counts", hours: 30, visitsPerDay: 2, dogs: 1, days: 2, visits: 4, cost: 100},
+		{name: "extra dogs", hours: 72, visitsPerDay: 1, dogs: 3, days: 3, visits: 3, cost: 135},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days, visits, cost := sitterCost(start, start.Add(time.Duration(tt.hours)*time.Hour), tt.visitsPerDay, tt.dogs)
+			if days != tt.days || visits != tt.visits || cost != tt.cost {
+				t.Errorf("sitterCost = %d days, %d visits, $%v; want %d, %d, $%v", days, visits, cost, tt.days, tt.visits, tt.cost)
+			}
+		})
+	}
+}
+
+func TestCheckSitterBooking(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   PetSitterBookingArgs
+		failed []string
+	}{
+		{name: "a weekend", args: PetSitterBookingArgs{DogIDs: []string{"dog-a"}, Start: "2026-10-03T08:00:00Z", End: "2026-10-05T08:00:00Z"}},
+		{name: "same dog twice", args: PetSitterBookingArgs{DogIDs: []string{"dog-a", "dog-a"}, Start: "2026-10-03T08:00:00Z", End: "2026-10-05T08:00:00Z"}, failed: []string{"dogIds[1]"}},
+		{name: "ends first", args: PetSitterBookingArgs{DogIDs: []string{"dog-a"}, Start: "2026-10-05T08:00:00Z", End: "2026-10-03T08:00:00Z"}, failed: []string{"end"}},
+		{name: "too long", args: PetSitterBookingArgs{DogIDs: []string{"dog-a"}, Start: "2026-10-01T08:00:00Z", End: "2027-01-01T08:00:00Z"}, failed: []string{"end"}},
+		{name: "dates only", args: PetSitterBookingArgs{DogIDs: []string{"dog-a"}, Start: "2026-10-03", End: "2026-10-05"}, failed: []string{"start", "end"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, f := range checkSitterBooking(tt.args) {
+				got = append(got, f.Property)
+			}
+			if !reflect.DeepEqual(got, tt.failed) {
+				t.Errorf("failures on %v, want %v", got, tt.failed)
+			}
+		})
+	}
+}
+
+func TestCareInstructions(t *testing.T) {
+	start := time.Date(2026, 10, 3, 8, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 10, 5, 8, 0, 0, 0, time.UTC)
+
+	var rex DogState
+	rex.Name, rex.Breed = "Rex", GoldenRetriever
+	rex.Preferences = &Preferences{
+		FavoriteFoods:       []string{"salmon"},
+		ToyTypes:            []ToyType{ToyBall},
+		ActivityPreferences: []ActivityPreference{{Activity: ActivityHiking, Intensity: IntensityHigh}},
+	}
+	var feeder SmartFeederState
+	feeder.DeviceID, feeder.PortionGrams, feeder.Schedule = "feeder-1", 120, []string{"07:00", "18:00"}
+	var diet DietTransitionState
+	diet.FromFood, diet.ToFood = "kibble", "salmon"
+	diet.Plan = []MixingDay{
+		{Day: 1, Date: "2026-10-02", FromPercent: 75, ToPercent: 25},
+		{Day: 2, Date: "2026-10-03", FromPercent: 50, ToPercent: 50},
+		{Day: 3, Date: "2026-10-06", FromPercent: 25, ToPercent: 75},
+	}
+	var visit VeterinaryVisitState
+	visit.VisitType, visit.Date, visit.Medications = "checkup", "2026-09-20T10:00:00Z", []string{"carprofen"}
+
+	var pip DogState
+	pip.Name, pip.Breed = "Pip", LabradorRetriever
+
+	got := careInstructions([]careSheet{
+		{
+			dog:         rex,
+			feeders:     []SmartFeederState{feeder},
+			transitions: []DietTransitionState{diet},
+			allergies:   []knownAllergy{{Allergen: "chicken", Source: "listed on the dog"}},
+			lastVisit:   &visit,
+		},
+		{dog: pip},
+	}, start, end, 2)
+
+	for _, want := range []string{
+		"Care instructions for 2026-10-03 to 2026-10-05, 2 visit(s) a day",
+		"- Feeding: feeder feeder-1 gives 120 g at 07:00, 18:00",
+		"- Diet change on 2026-10-03: 50% kibble, 50% salmon",
+		"- Allergic to chicken (listed on the dog)",
+		"- Medications from the checkup visit on 2026-09-20: carprofen",
+		"- Favorite foods: salmon",
+		"- Favorite toys: ball",
+		"- Likes: hiking (high)",
+		"Pip (",
+		"- Nothing special on record",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("care instructions lack %q:\n%s", want, got)
+		}
+	}
+	for _, outside := range []string{"2026-10-02", "2026-10-06"} {
+		if strings.Contains(got, outside) {
+			t.Errorf("care instructions mention %s, outside the stay:\n%s", outside, got)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// PhotoAlbum Resource - extracts an archive of photos into the registry.
+// Each photo is indexed in the backend and kept under the data directory;
+// a new archive updates the album in place, and the preview lists the
+// photos it adds, removes or changes.
+type PhotoAlbum struct{}
+
+type PhotoAlbumArgs struct {
+	Title  string           `pulumi:"title" validate:"required,max=128"`
+	DogID  *string          `pulumi:"dogId,optional"`
+	Photos resource.Archive `pulumi:"photos"`
+}
+
+type Photo struct {
+	Path        string `pulumi:"path" json:"path"`
+	SHA256      string `pulumi:"sha256" json:"sha256"`
+	Size        int    `pulumi:"size" json:"size"`
+	ContentType string `pulumi:"contentType" json:"contentType"`
+}
+
+// PhotoAlbumOutputs are computed by the provider; Check rejects them as inputs
+type PhotoAlbumOutputs struct {
+	AlbumID      string            `pulumi:"albumId"`
+	CreatedAt    string            `pulumi:"createdAt"`
+	ArchiveHash  string            `pulumi:"archiveHash"`
+	Manifest     []Photo           `pulumi:"manifest"`
+	ManifestHash string            `pulumi:"manifestHash"`
+	PhotoHashes  map[string]string `pulumi:"photoHashes"`
+	Added        []string          `pulumi:"added"`   // photos the last change added
+	Removed      []string          `pulumi:"removed"` // photos the last change removed
+}
+
+// PhotoAlbumState echoes the inputs next to the computed outputs
+type PhotoAlbumState struct {
+	PhotoAlbumArgs
+	PhotoAlbumOutputs
+}
+
+// maxPhotoSize bounds a single photo, in bytes
+const maxPhotoSize = 20 << 20
+
+func (PhotoAlbum) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (PhotoAlbumArgs, []p.CheckFailure, error) {
+	return checkInputs[PhotoAlbumArgs, PhotoAlbumState](newInputs)
+}
+
+// Diff updates in place for any change. When the archive changed and its
+// contents are available, each added, removed or changed photo is listed.
+func (PhotoAlbum) Diff(ctx context.Context, id string, olds PhotoAlbumState, news PhotoAlbumArgs) (p.DiffResponse, error) {
+	diff := map[string]p.PropertyDiff{}
+	if news.Title != olds.Title {
+		diff["title"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	if !equalStringPtr(news.DogID, olds.DogID) {
+		diff["dogId"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	if news.Photos.Hash != olds.ArchiveHash {
+		diff["photos"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+		// Best effort: the details are only a preview aid, and an archive
+		// the engine hasn't read arrives as a bare hash
+		if news.Photos.HasContents() {
+			if files, err := readPhotoArchive(&news.Photos); err == nil {
+				for name, kind := range photoChanges(olds.PhotoHashes, indexPhotos(files).hashes()) {
+					diff[fmt.Sprintf("photoHashes[%q]", name)] = p.PropertyDiff{Kind: kind}
+				}
+			}
+		}
+	}
+	return p.DiffResponse{HasChanges: len(diff) > 0, DetailedDiff: diff}, nil
+}
+
+func (PhotoAlbum) Create(ctx context.Context, name string, input PhotoAlbumArgs, preview bool) (string, PhotoAlbumState, error) {
+	state := PhotoAlbumState{PhotoAlbumArgs: input}
+
+	if preview {
+		return name, state, nil
+	}
+
+	if err := registry.BeginOperation(); err != nil {
+		return "", state, err
+	}
+	defer registry.EndOperation()
+
+	key := registry.IdempotencyKey(ctx, "album", name)
+	if id, _, err := registry.CreatedBefore(ctx, "album", key, input, &state); err != nil {
+		return "", state, err
+	} else if id != "" {
+		return id, state, nil
+	}
+
+	now := registry.Now(ctx)
+	state.AlbumID = fmt.Sprintf("album-%s-%s", strings.ToLower(strings.ReplaceAll(input.Title, " ", "-")), registry.IDSuffix(ctx, name, now.Unix()))
+	state.CreatedAt = now.Format("2006-01-02T15:04:05Z")
+	if err := storeAlbum(ctx, &state, nil); err != nil {
+		return "", state, err
+	}
+	if err := registry.RememberCreate(ctx, key, "album", state.AlbumID, input); err != nil {
+		return "", state, err
+	}
+	return state.AlbumID, state, nil
+}
+
+func (PhotoAlbum) Update(ctx context.Context, id string, oldState PhotoAlbumState, input PhotoAlbumArgs, preview bool) (PhotoAlbumState, error) {
+	state := PhotoAlbumState{PhotoAlbumArgs: input, PhotoAlbumOutputs: oldState.PhotoAlbumOutputs}
+
+	if preview {
+		return state, nil
+	}
+
+	if err := registry.BeginOperation(); err != nil {
+		return oldState, err
+	}
+	defer registry.EndOperation()
+
+	if err := storeAlbum(ctx, &state, oldState.PhotoHashes); err != nil {
+		return oldState, err
+	}
+	return state, nil
+}
+
+func (PhotoAlbum) Delete(ctx context.Context, id string, state PhotoAlbumState) error {
+	if err := registry.BeginOperation(); err != nil {
+		return err
+	}
+	defer registry.EndOperation()
+
+	for _, photo := range state.Manifest {
+		if err := registry.Delete(ctx, "photo", id+"/"+photo.Path, backend.AnyVersion); err != nil {
+			return err
+		}
+	}
+	if err := registry.RemoveAlbum(ctx, id); err != nil {
+		return err
+	}
+	if err := registry.Delete(ctx, "album", id, backend.AnyVersion); err != nil {
+		return err
+	}
+	return registry.ForgetCreate(ctx, "album", id)
+}
+
+// storeAlbum extracts the archive, indexes every photo in the backend and
+// fills in the outputs. previous holds the photo hashes before an update.
+func storeAlbum(ctx context.Context, state *PhotoAlbumState, previous map[string]string) error {
+	files, err := readPhotoArchive(&state.Photos)
+	if err != nil {
+		return err
+	}
+	index := indexPhotos(files)
+	for _, photo := range index {
+		if !strings.HasPrefix(photo.ContentType, "image/") {
+			return fmt.Errorf("photos: %s is not an image (%s)", photo.Path, photo.ContentType)
+		}
+	}
+
+	manifest, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(manifest)
+	state.ArchiveHash = state.Photos.Hash
+	state.Manifest = index
+	state.ManifestHash = hex.EncodeToString(sum[:])
+	state.PhotoHashes = index.hashes()
+	state.Added, state.Removed = nil, nil
+	for key, kind := range photoChanges(previous, state.PhotoHashes) {
+		switch kind {
+		case p.Add:
+			state.Added = append(state.Added, key)
+		case p.Delete:
+			state.Removed = append(state.Removed, key)
+		}
+	}
+	sort.Strings(state.Added)
+	sort.Strings(state.Removed)
+
+	if err := registry.WriteAlbum(ctx, state.AlbumID, files); err != nil {
+		return fmt.Errorf("extracting photos: %w", err)
+	}
+	records := make([]backend.Record, 0, len(index))
+	for _, photo := range index {
+		payload, err := json.Marshal(photo)
+		if err != nil {
+			return err
+		}
+		records = append(records, backend.Record{Kind: "photo", ID: state.AlbumID + "/" + photo.Path, Payload: payload})
+	}
+	store, err := registry.Store(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := store.PutAll(records); err != nil {
+		return fmt.Errorf("indexing photos: %w", err)
+	}
+	for _, name := range state.Removed {
+		if err := registry.Delete(ctx, "photo", state.AlbumID+"/"+name, backend.AnyVersion); err != nil {
+			return err
+		}
+	}
+	_, err = registry.Save(ctx, "album", state.AlbumID, backend.AnyVersion, state)
+	return err
+}
+
+// readPhotoArchive reads every file of the archive into memory, skipping
+// hidden files such as .DS_Store. Opening an archive that is only a hash
+// panics, so that is an error here.
+func readPhotoArchive(archive *resource.Archive) (map[string][]byte, error) {
+	if !archive.HasContents() {
+		return nil, errors.New("photos archive has no contents")
+	}
+	reader, err := archive.Open()
+	if err != nil {
+		return nil, fmt.Errorf("opening photos archive: %w", err)
+	}
+	defer reader.Close()
+
+	files := map[string][]byte{}
+	for {
+		name, blob, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading photos archive: %w", err)
+		}
+		data, err := io.ReadAll(io.LimitReader(blob, maxPhotoSize+1))
+		blob.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+		if len(data) > maxPhotoSize {
+			return nil, fmt.Errorf("photos: %s is larger than %d MiB", name, maxPhotoSize>>20)
+		}
+		if strings.HasPrefix(path.Base(name), ".") {
+			continue
+		}
+		files[path.Clean(name)] = data
+	}
+}
+
+type photoIndex []Photo
+
+// indexPhotos describes each file, in path order
+func indexPhotos(files map[string][]byte) photoIndex {
+	index := make(photoIndex, 0, len(files))
+	for name, data := range files {
+		sum := sha256.Sum256(data)
+		index = append(index, Photo{
+			Path:        name,
+			SHA256:      hex.EncodeToString(sum[:]),
+			Size:        len(data),
+			ContentType: http.DetectContentType(data),
+		})
+	}
+	sort.Slice(index, func(i, j int) bool { return index[i].Path < index[j].Path })
+	return index
+}
+
+func (index photoIndex) hashes() map[string]string {
+	hashes := make(map[string]string, len(index))
+	for _, photo := range index {
+		hashes[photo.Path] = photo.SHA256
+	}
+	return hashes
+}
+
+// photoChanges compares photo hashes before and after a change
+func photoChanges(before, after map[string]string) map[string]p.DiffKind {
+	changes := map[string]p.DiffKind{}
+	for name, hash := range after {
+		if old, ok := before[name]; !ok {
+			changes[name] = p.Add
+		} else if old != hash {
+			changes[name] = p.Update
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			changes[name] = p.Delete
+		}
+	}
+	return changes
+}
+
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
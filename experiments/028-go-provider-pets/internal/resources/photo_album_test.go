@@ -0,0 +1,105 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// Minimal file headers that content sniffing recognizes
+var (
+	pngPhoto  = []byte("\x89PNG\r\n\x1a\n rex in the park")
+	jpegPhoto = []byte("\xff\xd8\xff rex at the beach")
+)
+
+func TestIndexPhotos(t *testing.T) {
+	index := indexPhotos(map[string][]byte{
+		"summer/beach.jpg": jpegPhoto,
+		"park.png":         pngPhoto,
+		"notes.txt":        []byte("not a photo"),
+	})
+
+	want := []struct{ path, contentType string }{
+		{"notes.txt", "text/plain; charset=utf-8"},
+		{"park.png", "image/png"},
+		{"summer/beach.jpg", "image/jpeg"},
+	}
+	if len(index) != len(want) {
+		t.Fatalf("indexed %d photos, want %d", len(index), len(want))
+	}
+	for i, w := range want {
+		if index[i].Path != w.path || index[i].ContentType != w.contentType {
+			t.Errorf("photo %d = %s (%s), want %s (%s)", i, index[i].Path, index[i].ContentType, w.path, w.contentType)
+		}
+		if len(index[i].SHA256) != 64 || index[i].Size == 0 {
+			t.Errorf("photo %s has hash %q and size %d", index[i].Path, index[i].SHA256, index[i].Size)
+		}
+	}
+}
+
+func TestPhotoChanges(t *testing.T) {
+	before := map[string]string{"park.png": "a", "beach.jpg": "b", "snow.jpg": "c"}
+	after := map[string]string{"park.png": "a", "beach.jpg": "B", "couch.jpg": "d"}
+
+	got := photoChanges(before, after)
+	want := map[string]p.DiffKind{"beach.jpg": p.Update, "couch.jpg": p.Add, "snow.jpg": p.Delete}
+	if len(got) != len(want) {
+		t.Fatalf("changes = %v, want %v", got, want)
+	}
+	for name, kind := range want {
+		if got[name] != kind {
+			t.Errorf("%s = %q, want %q", name, got[name], kind)
+		}
+	}
+}
+
+func TestPhotoAlbumDiff(t *testing.T) {
+	dog := "dog-rex-1"
+	olds := PhotoAlbumState{
+		PhotoAlbumArgs:    PhotoAlbumArgs{Title: "Rex", DogID: &dog},
+		PhotoAlbumOutputs: PhotoAlbumOutputs{ArchiveHash: "h1"},
+	}
+	tests := []struct {
+		name     string
+		edit     func(*PhotoAlbumArgs)
+		wantKeys []string
+	}{
+		{name: "unchanged", edit: func(a *PhotoAlbumArgs) {}},
+		{name: "title", edit: func(a *PhotoAlbumArgs) { a.Title = "Rex and friends" }, wantKeys: []string{"title"}},
+		{name: "dog removed", edit: func(a *PhotoAlbumArgs) { a.DogID = nil }, wantKeys: []string{"dogId"}},
+		{name: "new archive", edit: func(a *PhotoAlbumArgs) { a.Photos.Hash = "h2" }, wantKeys: []string{"photos"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			news := olds.PhotoAlbumArgs
+			news.Photos.Hash = "h1"
+			tt.edit(&news)
+
+			diff, err := PhotoAlbum{}.Diff(context.Background(), "album-rex-1", olds, news)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff.HasChanges != (len(tt.wantKeys) > 0) {
+				t.Errorf("has changes = %v, want %v", diff.HasChanges, len(tt.wantKeys) > 0)
+			}
+			for _, key := range tt.wantKeys {
+				if diff.DetailedDiff[key].Kind != p.Update {
+					t.Errorf("%s = %+v, want an update", key, diff.DetailedDiff[key])
+				}
+			}
+			for key, change := range diff.DetailedDiff {
+				if change.Kind == p.UpdateReplace || change.Kind == p.AddReplace || change.Kind == p.DeleteReplace {
+					t.Errorf("%s replaces the album, want an update in place", key)
+				}
+			}
+		})
+	}
+}
+
+func TestReadPhotoArchiveWithoutContents(t *testing.T) {
+	if _, err := readPhotoArchive(&resource.Archive{Hash: "h1"}); err == nil {
+		t.Error("reading an archive with only a hash succeeded")
+	}
+}
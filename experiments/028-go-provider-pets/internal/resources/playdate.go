@@ -0,0 +1,183 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+)
+
+// Playdate Resource - two dogs meeting to play. Check runs the
+// compatibility model compatibilityScore exposes on the pair, weighing
+// the gap in their sizes and energy levels and their behavior incidents
+// over the last year. A
+// moderate-risk pairing goes ahead with a warning; a high-risk one is
+// refused unless acceptRisk is set, which turns the refusal into a
+// warning. Either way the playdate says how closely to supervise it, and
+// the assessment is redone on refresh as the dogs' records change.
+type Playdate struct{}
+
+//pets:state id=ID created=CreatedAt
+//pets:output ID string id Generated identifier of the playdate
+//pets:output CreatedAt string createdAt When the playdate was arranged
+//pets:output CompatibilityScore int compatibilityScore How well the dogs suit each other, out of 100; recomputed on refresh
+//pets:output Risk string risk How risky the pairing is: low, moderate or high
+//pets:output Concerns []string concerns What counted against the pairing
+//pets:output Factors []CompatibilityFactor factors The compatibility score factor by factor, as compatibilityScore returns it
+//pets:output Supervision string supervision How closely to supervise the dogs
+type PlaydateArgs struct {
+	DogAID     string  `pulumi:"dogAId" validate:"required"`
+	DogBID     string  `pulumi:"dogBId" validate:"required"`
+	Date       string  `pulumi:"date" validate:"required"` // YYYY-MM-DD
+	Location   *string `pulumi:"location,optional"`
+	AcceptRisk *bool   `pulumi:"acceptRisk,optional" default:"false"` // Go ahead with a high-risk pairing, with a warning instead of a refusal
+}
+
+var playdates = crudResource[PlaydateArgs, PlaydateState, *PlaydateState]{
+	kind:     stored[PlaydateState]("playdate"),
+	prefix:   "playdate",
+	slug:     func(input PlaydateArgs) string { return input.DogAID },
+	newState: newPlaydateState,
+	populate: func(ctx context.Context, state *PlaydateState, input PlaydateArgs) error {
+		return assessPlaydate(ctx, state, true)
+	},
+	carry: func(ctx context.Context, state *PlaydateState, oldState PlaydateState, now time.Time) error {
+		return assessPlaydate(ctx, state, true)
+	},
+	// Incidents get recorded and dogs grow up, so Read reassesses the pair;
+	// once a dog has left the registry the last assessment stands
+	refresh: func(ctx context.Context, id string, state *PlaydateState) error {
+		if err := assessPlaydate(ctx, state, false); err != nil && !errors.Is(err, backend.ErrNotFound) {
+			return err
+		}
+		return nil
+	},
+}
+
+func (Playdate) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (PlaydateArgs, []p.CheckFailure, error) {
+	args, failures, err := playdates.check(newInputs)
+	if args.DogAID != "" && args.DogAID == args.DogBID {
+		failures = append(failures, p.CheckFailure{Property: "dogBId", Reason: "a dog can't have a playdate with itself"})
+	}
+	if _, perr := time.Parse(dateLayout, args.Date); args.Date != "" && perr != nil {
+		failures = append(failures, p.CheckFailure{Property: "date", Reason: "date must be YYYY-MM-DD"})
+	}
+	if len(failures) > 0 || err != nil {
+		return args, failures, err
+	}
+	return args, checkPairing(ctx, args), nil
+}
+
+func (Playdate) Diff(ctx context.Context, id string, olds PlaydateState, news PlaydateArgs) (p.DiffResponse, error) {
+	// Other dogs are another playdate; it can move to another day or place
+	diff := map[string]p.PropertyDiff{}
+	if olds.DogAID != news.DogAID {
+		diff["dogAId"] = p.PropertyDiff{Kind: p.UpdateReplace, InputDiff: true}
+	}
+	if olds.DogBID != news.DogBID {
+		diff["dogBId"] = p.PropertyDiff{Kind: p.UpdateReplace, InputDiff: true}
+	}
+	if olds.Date != news.Date {
+		diff["date"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	if !reflect.DeepEqual(olds.Location, news.Location) {
+		diff["location"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	if !reflect.DeepEqual(olds.AcceptRisk, news.AcceptRisk) {
+		diff["acceptRisk"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	return p.DiffResponse{HasChanges: len(diff) > 0, DetailedDiff: diff}, nil
+}
+
+func (Playdate) Create(ctx context.Context, name string, input PlaydateArgs, preview bool) (string, PlaydateState, error) {
+	return playdates.create(ctx, name, input, preview)
+}
+
+func (Playdate) Read(ctx context.Context, id string, inputs PlaydateArgs, state PlaydateState) (string, PlaydateArgs, PlaydateState, error) {
+	return playdates.read(ctx, id, inputs, state)
+}
+
+func (Playdate) Update(ctx context.Context, id string, oldState PlaydateState, input PlaydateArgs, preview bool) (PlaydateState, error) {
+	return playdates.update(ctx, id, oldState, input, preview)
+}
+
+func (Playdate) Delete(ctx context.Context, id string, state PlaydateState) error {
+	return playdates.delete(ctx, id, state)
+}
+
+// checkPairing warns of a risky pairing, or fails dogBId when it is too
+// risky and the risk isn't accepted. Check can run before the dogs or the
+// registry exist, so pairings it can't assess are left for Create.
+func checkPairing(ctx context.Context, args PlaydateArgs) []p.CheckFailure {
+	a, err := loadProfile(ctx, args.DogAID)
+	if err != nil {
+		return nil
+	}
+	b, err := loadProfile(ctx, args.DogBID)
+	if err != nil {
+		return nil
+	}
+	score, factors := compatibility(a, b)
+	if reason := pairingVerdict(a, b, score, factors, args.AcceptRisk != nil && *args.AcceptRisk); reason != "" {
+		return []p.CheckFailure{{Property: "dogBId", Reason: reason}}
+	}
+	if risk := pairingRisk(score, factors); risk != RiskLow {
+		p.GetLogger(ctx).Warning(fmt.Sprintf("%s and %s are a %s-risk pairing (%d/100): %s", a.Name, b.Name, risk, score, strings.Join(concerns(factors), "; ")))
+	}
+	return nil
+}
+
+// assessPlaydate scores the pair. On create and update a high-risk pairing
+// whose risk isn't accepted is an error; a refresh only reports it.
+func assessPlaydate(ctx context.Context, state *PlaydateState, enforce bool) error {
+	a, err := loadProfile(ctx, state.DogAID)
+	if err != nil {
+		return err
+	}
+	b, err := loadProfile(ctx, state.DogBID)
+	if err != nil {
+		return err
+	}
+	verdict := assess(a, b)
+	if reason := pairingVerdict(a, b, verdict.Score, verdict.Factors, *state.AcceptRisk); enforce && reason != "" {
+		return errors.New(reason)
+	}
+	state.CompatibilityScore, state.Risk, state.Supervision = verdict.Score, verdict.Risk, verdict.Supervision
+	state.Factors = verdict.Factors
+	state.Concerns = concerns(verdict.Factors)
+	return nil
+}
+
+// pairingVerdict is why a pairing is refused, or empty when it can go ahead
+func pairingVerdict(a, b dogProfile, score int, factors []CompatibilityFactor, acceptRisk bool) string {
+	if acceptRisk || pairingRisk(score, factors) != RiskHigh {
+		return ""
+	}
+	return fmt.Sprintf("%s and %s are a high-risk pairing (%d/100): %s; set acceptRisk to arrange the playdate anyway",
+		a.Name, b.Name, score, strings.Join(concerns(factors), "; "))
+}
+
+// concerns are the details of the factors that scored below full marks,
+// worst first
+func concerns(factors []CompatibilityFactor) []string {
+	var worst []CompatibilityFactor
+	for _, f := range factors {
+		if f.Score < 100 {
+			worst = append(worst, f)
+		}
+	}
+	sort.SliceStable(worst, func(i, j int) bool { return worst[i].Score < worst[j].Score })
+	out := []string{}
+	for _, f := range worst {
+		out = append(out, f.Detail)
+	}
+	return out
+}
@@ -0,0 +1,57 @@
+// Code generated by genstate from playdate.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// PlaydateOutputs are computed by the provider; Check rejects them as inputs
+type PlaydateOutputs struct {
+	ID                 string                `pulumi:"id"`
+	CreatedAt          string                `pulumi:"createdAt"`
+	CompatibilityScore int                   `pulumi:"compatibilityScore"`
+	Risk               string                `pulumi:"risk"`
+	Concerns           []string              `pulumi:"concerns"`
+	Factors            []CompatibilityFactor `pulumi:"factors"`
+	Supervision        string                `pulumi:"supervision"`
+	Version            int64                 `pulumi:"version"`
+}
+
+// PlaydateState echoes the inputs next to the computed outputs
+type PlaydateState struct {
+	PlaydateArgs
+	PlaydateOutputs
+}
+
+// newPlaydateState copies the inputs into an otherwise empty state
+func newPlaydateState(input PlaydateArgs) PlaydateState { return PlaydateState{PlaydateArgs: input} }
+
+func (s *PlaydateState) stamp(id, created string)   { s.ID, s.CreatedAt = id, created }
+func (s *PlaydateState) identity() (string, string) { return s.ID, s.CreatedAt }
+func (s *PlaydateState) setVersion(version int64)   { s.Version = version }
+func (s *PlaydateState) storedVersion() int64       { return s.Version }
+
+func (args *PlaydateArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Date, "YYYY-MM-DD")
+	a.Describe(&args.AcceptRisk, "Go ahead with a high-risk pairing, with a warning instead of a refusal")
+	a.SetDefault(&args.AcceptRisk, false)
+}
+
+// applyDefaults fills unset optional inputs with their schema defaults
+func (args *PlaydateArgs) applyDefaults() {
+	if args.AcceptRisk == nil {
+		v := false
+		args.AcceptRisk = &v
+	}
+}
+
+func (state *PlaydateState) Annotate(a infer.Annotator) {
+	state.PlaydateArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the playdate")
+	a.Describe(&state.CreatedAt, "When the playdate was arranged")
+	a.Describe(&state.CompatibilityScore, "How well the dogs suit each other, out of 100; recomputed on refresh")
+	a.Describe(&state.Risk, "How risky the pairing is: low, moderate or high")
+	a.Describe(&state.Concerns, "What counted against the pairing")
+	a.Describe(&state.Factors, "The compatibility score factor by factor, as compatibilityScore returns it")
+	a.Describe(&state.Supervision, "How closely to supervise the dogs")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
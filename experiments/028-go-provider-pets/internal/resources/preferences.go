@@ -0,0 +1,127 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+// Preferences is what a dog likes. Diet transitions note when they move a
+// dog off a favorite food, toys of a favorite type are played with more and
+// wear sooner, and walks are scored against the intensity the dog likes
+// its walking, running or hiking at.
+type Preferences struct {
+	FavoriteFoods       []string             `pulumi:"favoriteFoods,optional" json:"favoriteFoods,omitempty"`
+	ToyTypes            []ToyType            `pulumi:"toyTypes,optional" json:"toyTypes,omitempty"`
+	ActivityPreferences []ActivityPreference `pulumi:"activityPreferences,optional" json:"activityPreferences,omitempty"`
+}
+
+// ActivityPreference is an activity a dog enjoys and how hard it likes it
+type ActivityPreference struct {
+	Activity  Activity          `pulumi:"activity" json:"activity"`
+	Intensity ActivityIntensity `pulumi:"intensity" json:"intensity"`
+}
+
+// intensityScale stretches the exercise and pace a walk is scored against
+var intensityScale = map[ActivityIntensity]float64{
+	IntensityLow:      0.75,
+	IntensityModerate: 1,
+	IntensityHigh:     1.25,
+}
+
+// walkActivities are the activities a DogWalk stands for
+var walkActivities = map[Activity]bool{
+	ActivityWalking: true,
+	ActivityRunning: true,
+	ActivityHiking:  true,
+}
+
+var activities = map[Activity]bool{
+	ActivityWalking: true, ActivityRunning: true, ActivityHiking: true, ActivityFetch: true,
+	ActivitySwimming: true, ActivityAgility: true, ActivityTug: true,
+}
+
+// favoriteToyPlay is how much more a dog plays with a toy of a favorite type
+const favoriteToyPlay = 1.5
+
+// checkPreferences reports malformed entries of a preferences input
+func checkPreferences(property string, prefs *Preferences) []p.CheckFailure {
+	if prefs == nil {
+		return nil
+	}
+	var failures []p.CheckFailure
+	fail := func(field, reason string) {
+		failures = append(failures, p.CheckFailure{Property: property + "." + field, Reason: reason})
+	}
+	for i, food := range prefs.FavoriteFoods {
+		if strings.TrimSpace(food) == "" {
+			fail(fmt.Sprintf("favoriteFoods[%d]", i), "favorite foods must not be empty")
+		}
+	}
+	for i, toy := range prefs.ToyTypes {
+		if _, ok := wearPerHour[toy]; !ok {
+			fail(fmt.Sprintf("toyTypes[%d]", i), "toy types must be one of ball, rope, chew, plush, squeaky or puzzle")
+		}
+	}
+	seen := map[Activity]bool{}
+	for i, pref := range prefs.ActivityPreferences {
+		switch {
+		case !activities[pref.Activity]:
+			fail(fmt.Sprintf("activityPreferences[%d].activity", i), "activity must be one of walking, running, hiking, fetch, swimming, agility or tug")
+		case seen[pref.Activity]:
+			fail(fmt.Sprintf("activityPreferences[%d].activity", i), fmt.Sprintf("%s is listed more than once", pref.Activity))
+		}
+		seen[pref.Activity] = true
+		if _, ok := intensityScale[pref.Intensity]; !ok {
+			fail(fmt.Sprintf("activityPreferences[%d].intensity", i), "intensity must be one of low, moderate or high")
+		}
+	}
+	return failures
+}
+
+// favoriteFood reports whether food is among the dog's favorites, ignoring
+// case and spacing
+func (dog DogState) favoriteFood(food string) bool {
+	if dog.Preferences == nil {
+		return false
+	}
+	for _, favorite := range dog.Preferences.FavoriteFoods {
+		if householdKey(favorite) == householdKey(food) {
+			return true
+		}
+	}
+	return false
+}
+
+// favoriteToy reports whether toys of the type are among the dog's
+// favorites
+func (dog DogState) favoriteToy(toy ToyType) bool {
+	if dog.Preferences == nil {
+		return false
+	}
+	for _, favorite := range dog.Preferences.ToyTypes {
+		if favorite == toy {
+			return true
+		}
+	}
+	return false
+}
+
+// walkIntensity scales the exercise a walk should give the dog: by the
+// highest intensity it likes walking, running or hiking at, and 1 when it
+// has no such preference
+func (dog DogState) walkIntensity() float64 {
+	scale := 0.0
+	if dog.Preferences != nil {
+		for _, pref := range dog.Preferences.ActivityPreferences {
+			if walkActivities[pref.Activity] {
+				scale = max(scale, intensityScale[pref.Intensity])
+			}
+		}
+	}
+	if scale == 0 {
+		return 1
+	}
+	return scale
+}
@@ -0,0 +1,72 @@
+package resources
+
+import "testing"
+
+func TestCheckPreferences(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefs  *Preferences
+		failed []string
+	}{
+		{name: "unset"},
+		{
+			name: "valid",
+			prefs: &Preferences{
+				FavoriteFoods:       []string{"salmon kibble"},
+				ToyTypes:            []ToyType{ToyBall, ToyRope},
+				ActivityPreferences: []ActivityPreference{{Activity: ActivityFetch, Intensity: IntensityHigh}, {Activity: ActivityWalking, Intensity: IntensityLow}},
+			},
+		},
+		{
+			name:   "blank food and unknown toy",
+			prefs:  &Preferences{FavoriteFoods: []string{" "}, ToyTypes: []ToyType{"stick"}},
+			failed: []string{"preferences.favoriteFoods[0]", "preferences.toyTypes[0]"},
+		},
+		{
+			name: "bad activities",
+			prefs: &Preferences{ActivityPreferences: []ActivityPreference{
+				{Activity: "napping", Intensity: IntensityLow},
+				{Activity: ActivityTug, Intensity: "extreme"},
+				{Activity: ActivityTug, Intensity: IntensityLow},
+			}},
+			failed: []string{"preferences.activityPreferences[0].activity", "preferences.activityPreferences[1].intensity", "preferences.activityPreferences[2].activity"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := checkPreferences("preferences", tt.prefs)
+			if len(failures) != len(tt.failed) {
+				t.Fatalf("failures = %+v, want %v", failures, tt.failed)
+			}
+			for i, f := range failures {
+				if f.Property != tt.failed[i] {
+					t.Errorf("failure %d on %q, want %q", i, f.Property, tt.failed[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPreferenceLookups(t *testing.T) {
+	var dog DogState
+	if dog.favoriteFood("salmon") || dog.favoriteToy(ToyBall) || dog.walkIntensity() != 1 {
+		t.Fatal("a dog without preferences has no favorites and walks at moderate intensity")
+	}
+	dog.Preferences = &Preferences{
+		FavoriteFoods: []string{"Salmon  Kibble"},
+		ToyTypes:      []ToyType{ToyBall},
+		ActivityPreferences: []ActivityPreference{
+			{Activity: ActivitySwimming, Intensity: IntensityHigh},
+			{Activity: ActivityWalking, Intensity: IntensityLow},
+		},
+	}
+	if !dog.favoriteFood("salmon kibble") || dog.favoriteFood("lamb") {
+		t.Error("favoriteFood should match regardless of case and spacing")
+	}
+	if !dog.favoriteToy(ToyBall) || dog.favoriteToy(ToyChew) {
+		t.Error("favoriteToy should match the listed types only")
+	}
+	if got := dog.walkIntensity(); got != 0.75 {
+		t.Errorf("walkIntensity = %v, want 0.75 from walking, not swimming", got)
+	}
+}
@@ -0,0 +1,21 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+// reportProgress streams the steps of a multi-step operation to the Pulumi
+// diagnostic stream so long creates aren't silent until they finish.
+func reportProgress(ctx context.Context, subject string, steps []string) error {
+	logger := p.GetLogger(ctx)
+	for i, step := range steps {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("%s cancelled after step %d/%d: %w", subject, i, len(steps), err)
+		}
+		logger.InfoStatusf("%s step %d/%d: %s", subject, i+1, len(steps), step)
+	}
+	return nil
+}
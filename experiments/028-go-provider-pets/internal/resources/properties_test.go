@@ -0,0 +1,146 @@
+package resources
+
+import (
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/validate"
+)
+
+// The properties below hold for every input that passes validation, so the
+// generators map arbitrary integers into the validated ranges.
+
+// walkDuration maps n onto the 1–600 minutes DogWalkArgs accepts
+func walkDuration(n uint16) int { return 1 + int(n)%600 }
+
+// walkDistance maps n onto 0–50 miles in hundredths
+func walkDistance(n uint16) float64 { return float64(n%5001) / 100 }
+
+func TestWalkCaloriesProperties(t *testing.T) {
+	// walkWeight maps n onto 1–160 kg, the range Dog's weight allows
+	walkWeight := func(n uint16) float64 { return 1 + float64(n%1600)/10 }
+	burn := func(args DogWalkArgs, kg float64) int {
+		return walkCalories(walkingMET(walkPace(args)), kg, args.Duration)
+	}
+
+	properties := map[string]any{
+		"never negative": func(duration, distance, weight uint16) bool {
+			return burn(DogWalkArgs{Duration: walkDuration(duration), Distance: walkDistance(distance)}, walkWeight(weight)) >= 0
+		},
+		"heavier dogs never burn fewer calories": func(duration, distance, a, b uint16) bool {
+			light, heavy := walkWeight(a), walkWeight(b)
+			if light > heavy {
+				light, heavy = heavy, light
+			}
+			args := DogWalkArgs{Duration: walkDuration(duration), Distance: walkDistance(distance)}
+			return burn(args, light) <= burn(args, heavy)
+		},
+		"farther walks never burn fewer calories": func(duration, a, b, weight uint16) bool {
+			near, far := walkDistance(a), walkDistance(b)
+			if near > far {
+				near, far = far, near
+			}
+			minutes, kg := walkDuration(duration), walkWeight(weight)
+			return burn(DogWalkArgs{Duration: minutes, Distance: near}, kg) <=
+				burn(DogWalkArgs{Duration: minutes, Distance: far}, kg)
+		},
+	}
+
+	for name, property := range properties {
+		t.Run(name, func(t *testing.T) {
+			if err := quick.Check(property, nil); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestWalkEnjoymentProperties(t *testing.T) {
+	weights := registry.EnjoymentWeights{BreedEnergy: 0.35, Weather: 0.25, Pace: 0.2, Age: 0.2}
+	walk := func(duration, distance uint16, breed, age uint8) (DogWalkArgs, DogState) {
+		years := int(age) % 31
+		dog := DogState{DogArgs: DogArgs{Breed: breeds[int(breed)%len(breeds)], Age: &years}}
+		return DogWalkArgs{Duration: walkDuration(duration), Distance: walkDistance(distance)}, dog
+	}
+
+	properties := map[string]any{
+		"score is within 0–100 and matches its label": func(duration, distance uint16, breed, age, weather uint8) bool {
+			args, dog := walk(duration, distance, breed, age)
+			conditions := []Weather{Sunny, Cloudy, Rain, Snow, HeatAdvisory}
+			args.Weather = &conditions[int(weather)%len(conditions)]
+			score, label := walkEnjoyment(args, dog, weights)
+			switch {
+			case score < 0 || score > 100:
+				return false
+			case score >= 70:
+				return label == "high"
+			case score >= 40:
+				return label == "medium"
+			default:
+				return label == "low"
+			}
+		},
+		"sunshine is never enjoyed less than rain": func(duration, distance uint16, breed, age uint8) bool {
+			args, dog := walk(duration, distance, breed, age)
+			sunny, rain := Sunny, Rain
+			args.Weather = &sunny
+			inSun, _ := walkEnjoyment(args, dog, weights)
+			args.Weather = &rain
+			inRain, _ := walkEnjoyment(args, dog, weights)
+			return inSun >= inRain
+		},
+		"a mild day is never enjoyed less than a hot one": func(duration, distance uint16, breed, age uint8, heat uint8) bool {
+			args, dog := walk(duration, distance, breed, age)
+			mild, hot := 60.0, 76+float64(heat%54)
+			args.Temperature = &mild
+			onMild, _ := walkEnjoyment(args, dog, weights)
+			args.Temperature = &hot
+			onHot, _ := walkEnjoyment(args, dog, weights)
+			return onMild >= onHot
+		},
+	}
+
+	for name, property := range properties {
+		t.Run(name, func(t *testing.T) {
+			if err := quick.Check(property, nil); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+var breeds = []DogBreed{
+	GoldenRetriever, LabradorRetriever, GermanShepherd, Bulldog,
+	Poodle, Beagle, Rottweiler, Husky, "mixed",
+}
+
+func TestRegisteredDogProperties(t *testing.T) {
+	now := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	register := func(breed uint8, age uint8) DogState {
+		years := int(age) % 31
+		args := DogArgs{Name: "Rex", Breed: breeds[int(breed)%len(breeds)], OwnerName: "Sam", Age: &years}
+		return registeredDog(args, now, "1")
+	}
+
+	properties := map[string]any{
+		"scores stay within 0–100": func(breed, age uint8) bool {
+			state := register(breed, age)
+			carryDogState(&state, state, now)
+			return state.Happiness >= 0 && state.Happiness <= 100 && state.Energy >= 0 && state.Energy <= 100
+		},
+		"breed defaults pass validation": func(breed, age uint8) bool {
+			state := register(breed, age)
+			return len(validate.Struct(&state.DogArgs)) == 0
+		},
+	}
+
+	for name, property := range properties {
+		t.Run(name, func(t *testing.T) {
+			if err := quick.Check(property, nil); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
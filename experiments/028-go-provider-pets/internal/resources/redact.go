@@ -0,0 +1,91 @@
+package resources
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// storedStates maps a record kind to the state type its payloads encode
+var storedStates = map[string]reflect.Type{}
+
+// stored notes that records of kind hold S payloads and returns kind, so the
+// resource descriptors declare both together
+func stored[S any](kind string) string {
+	storedStates[kind] = reflect.TypeOf((*S)(nil)).Elem()
+	return kind
+}
+
+// Redact returns payload, a record of kind, without the properties its state
+// marks provider:"secret", for serving the registry outside Pulumi. Records
+// of kinds without a resource state have no secrets and are returned as is.
+func Redact(kind string, payload []byte) ([]byte, error) {
+	t, ok := storedStates[kind]
+	if !ok {
+		return payload, nil
+	}
+	var data any
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+	redact(t, data)
+	return json.Marshal(data)
+}
+
+// redact drops the secret fields of t from data, its decoded JSON
+func redact(t reflect.Type, data any) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		fields, _ := data.(map[string]any)
+		if fields == nil {
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if field.Anonymous && name == "" {
+				// Embedded structs are flattened into their parent
+				redact(field.Type, fields)
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			if secret(field) {
+				delete(fields, name)
+				continue
+			}
+			if value, ok := fields[name]; ok {
+				redact(field.Type, value)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		items, _ := data.([]any)
+		for _, item := range items {
+			redact(t.Elem(), item)
+		}
+	case reflect.Map:
+		values, _ := data.(map[string]any)
+		for _, value := range values {
+			redact(t.Elem(), value)
+		}
+	}
+}
+
+func secret(field reflect.StructField) bool {
+	for _, option := range strings.Split(field.Tag.Get("provider"), ",") {
+		if option == "secret" {
+			return true
+		}
+	}
+	return false
+}
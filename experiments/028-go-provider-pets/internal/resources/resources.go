@@ -0,0 +1,308 @@
+// Package resources implements the custom resources of the pets provider.
+package resources
+
+//go:generate go run ../tools/genstate
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// Pet breeds and types
+type DogBreed string
+
+const (
+	GoldenRetriever   DogBreed = "golden-retriever"
+	LabradorRetriever DogBreed = "labrador-retriever"
+	GermanShepherd    DogBreed = "german-shepherd"
+	Bulldog           DogBreed = "bulldog"
+	Poodle            DogBreed = "poodle"
+	Beagle            DogBreed = "beagle"
+	Rottweiler        DogBreed = "rottweiler"
+	Husky             DogBreed = "husky"
+)
+
+type PetSize string
+
+const (
+	Small      PetSize = "small"
+	Medium     PetSize = "medium"
+	Large      PetSize = "large"
+	ExtraLarge PetSize = "extra-large"
+)
+
+type TrainingLevel string
+
+const (
+	Untrained    TrainingLevel = "untrained"
+	Basic        TrainingLevel = "basic"
+	Intermediate TrainingLevel = "intermediate"
+	Advanced     TrainingLevel = "advanced"
+	Professional TrainingLevel = "professional"
+)
+
+// What a training session worked on
+type TrainingFocus string
+
+const (
+	FocusObedience     TrainingFocus = "obedience"
+	FocusTask          TrainingFocus = "task" // the work an assistance dog does for its handler
+	FocusAgility       TrainingFocus = "agility"
+	FocusSocialization TrainingFocus = "socialization"
+)
+
+// Reasons for a veterinary visit
+type VisitType string
+
+const (
+	VisitCheckup     VisitType = "checkup"
+	VisitVaccination VisitType = "vaccination"
+	VisitEmergency   VisitType = "emergency"
+	VisitSurgery     VisitType = "surgery"
+	VisitDental      VisitType = "dental"
+	VisitFollowUp    VisitType = "followup"
+)
+
+// Values makes VisitType an enum in the schema, and is what validation
+// checks a visit's type against
+func (VisitType) Values() []infer.EnumValue[VisitType] {
+	return []infer.EnumValue[VisitType]{
+		{Name: "Checkup", Value: VisitCheckup},
+		{Name: "Vaccination", Value: VisitVaccination},
+		{Name: "Emergency", Value: VisitEmergency},
+		{Name: "Surgery", Value: VisitSurgery},
+		{Name: "Dental", Value: VisitDental},
+		{Name: "FollowUp", Value: VisitFollowUp},
+	}
+}
+
+// Weather conditions for a walk
+type Weather string
+
+const (
+	Sunny        Weather = "sunny"
+	Cloudy       Weather = "cloudy"
+	Rain         Weather = "rain"
+	Snow         Weather = "snow"
+	HeatAdvisory Weather = "heat-advisory"
+)
+
+// Values makes Weather an enum in the schema, and is what validation
+// checks a walk's weather against
+func (Weather) Values() []infer.EnumValue[Weather] {
+	return []infer.EnumValue[Weather]{
+		{Name: "Sunny", Value: Sunny},
+		{Name: "Cloudy", Value: Cloudy},
+		{Name: "Rain", Value: Rain},
+		{Name: "Snow", Value: Snow},
+		{Name: "HeatAdvisory", Value: HeatAdvisory},
+	}
+}
+
+// How badly a dog reacts to an allergen
+type AllergySeverity string
+
+const (
+	AllergyMild     AllergySeverity = "mild"
+	AllergyModerate AllergySeverity = "moderate"
+	AllergySevere   AllergySeverity = "severe"
+)
+
+// Kinds of toy in a ToyInventory
+type ToyType string
+
+const (
+	ToyBall    ToyType = "ball"
+	ToyRope    ToyType = "rope"
+	ToyChew    ToyType = "chew"
+	ToyPlush   ToyType = "plush"
+	ToySqueaky ToyType = "squeaky"
+	ToyPuzzle  ToyType = "puzzle"
+)
+
+// How well a toy stands up to play
+type ToyDurability string
+
+const (
+	DurabilityLow    ToyDurability = "low"
+	DurabilityMedium ToyDurability = "medium"
+	DurabilityHigh   ToyDurability = "high"
+)
+
+// Subscription box tiers, from the fewest items to the most
+type BoxTier string
+
+const (
+	TierBasic   BoxTier = "basic"
+	TierPremium BoxTier = "premium"
+	TierDeluxe  BoxTier = "deluxe"
+)
+
+// How often a subscription box is delivered
+type Cadence string
+
+const (
+	CadenceWeekly   Cadence = "weekly"
+	CadenceBiweekly Cadence = "biweekly"
+	CadenceMonthly  Cadence = "monthly"
+)
+
+// Chat services a NotificationChannel posts to
+type ChannelType string
+
+const (
+	ChannelSlack   ChannelType = "slack"
+	ChannelDiscord ChannelType = "discord"
+)
+
+// Events a NotificationChannel can subscribe to
+type EventKind string
+
+const (
+	EventWalk     EventKind = "walk"
+	EventVisit    EventKind = "visit"
+	EventGeofence EventKind = "geofence"
+	EventLostPet  EventKind = "lost-pet"
+)
+
+// Where a LostPetReport stands. A report goes from open to sighted as
+// people spot the dog, and ends found or closed.
+type LostPetStatus string
+
+const (
+	LostOpen    LostPetStatus = "open"
+	LostSighted LostPetStatus = "sighted"
+	LostFound   LostPetStatus = "found"
+	LostClosed  LostPetStatus = "closed"
+)
+
+// Recording resolutions a PetCamera supports
+type CameraResolution string
+
+const (
+	Resolution720p  CameraResolution = "720p"
+	Resolution1080p CameraResolution = "1080p"
+	Resolution1440p CameraResolution = "1440p"
+	Resolution4K    CameraResolution = "4k"
+)
+
+// What a PetInsurance policy pays out for, from the least to the most
+type CoverageType string
+
+const (
+	CoverageAccidentOnly    CoverageType = "accident-only"
+	CoverageAccidentIllness CoverageType = "accident-illness"
+	CoverageComprehensive   CoverageType = "comprehensive"
+)
+
+// WellnessPlan tiers, from the fewest included visits to the most
+type WellnessTier string
+
+const (
+	WellnessBasic   WellnessTier = "basic"
+	WellnessPlus    WellnessTier = "plus"
+	WellnessPremium WellnessTier = "premium"
+)
+
+// Jobs a WorkingDog is assigned to
+type WorkingRole string
+
+const (
+	RolePolice       WorkingRole = "police"
+	RoleSearchRescue WorkingRole = "search-rescue"
+	RoleDetection    WorkingRole = "detection"
+)
+
+// Jobs a volunteer does at a shelter
+type VolunteerRole string
+
+const (
+	RoleKennel            VolunteerRole = "kennel"
+	RoleDogWalker         VolunteerRole = "dog-walker"
+	RoleAdoptionCounselor VolunteerRole = "adoption-counselor"
+	RoleFrontDesk         VolunteerRole = "front-desk"
+)
+
+// What a ServiceDogCertification certifies a dog for
+type CertificationPurpose string
+
+const (
+	PurposeService CertificationPurpose = "service" // working for a handler
+	PurposeTherapy CertificationPurpose = "therapy" // visiting facilities to comfort people
+)
+
+// Activities a dog can be keen on, in its preferences
+type Activity string
+
+const (
+	ActivityWalking  Activity = "walking"
+	ActivityRunning  Activity = "running"
+	ActivityHiking   Activity = "hiking"
+	ActivityFetch    Activity = "fetch"
+	ActivitySwimming Activity = "swimming"
+	ActivityAgility  Activity = "agility"
+	ActivityTug      Activity = "tug"
+)
+
+// How hard a dog likes an activity to be
+type ActivityIntensity string
+
+const (
+	IntensityLow      ActivityIntensity = "low"
+	IntensityModerate ActivityIntensity = "moderate"
+	IntensityHigh     ActivityIntensity = "high"
+)
+
+// Stages of a dog's life, which care recommendations go by
+type LifeStage string
+
+const (
+	LifeStagePuppy  LifeStage = "puppy"
+	LifeStageAdult  LifeStage = "adult"
+	LifeStageSenior LifeStage = "senior"
+)
+
+// Kinds of home, from the least room for a dog to the most
+type HomeSize string
+
+const (
+	HomeApartment     HomeSize = "apartment"
+	HomeHouse         HomeSize = "house"
+	HomeHouseWithYard HomeSize = "house-with-yard"
+)
+
+// Helper functions
+func determineSizeByBreed(breed DogBreed) PetSize {
+	switch breed {
+	case Beagle, Poodle:
+		return Medium
+	case GoldenRetriever, LabradorRetriever, GermanShepherd, Rottweiler, Husky:
+		return Large
+	case Bulldog:
+		return Medium
+	default:
+		return Medium
+	}
+}
+
+// EstimateWeightByBreed is a typical adult weight for the breed in pounds;
+// it is also the norm weight trends are assessed against.
+func EstimateWeightByBreed(breed DogBreed) float64 {
+	switch breed {
+	case Beagle:
+		return 25.0
+	case Poodle:
+		return 45.0
+	case GoldenRetriever:
+		return 65.0
+	case LabradorRetriever:
+		return 70.0
+	case GermanShepherd:
+		return 75.0
+	case Bulldog:
+		return 50.0
+	case Rottweiler:
+		return 95.0
+	case Husky:
+		return 55.0
+	default:
+		return 50.0
+	}
+}
@@ -0,0 +1,176 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// ServiceDogCertification Resource - certifies a dog to work for its
+// handler, or as a therapy dog. Create only succeeds once the dog is
+// trained far enough for the purpose, has had enough training sessions -
+// task sessions on every task a service dog performs, socialization
+// sessions for a therapy dog - and has had a checkup in the last year;
+// otherwise it fails listing what is still missing.
+type ServiceDogCertification struct{}
+
+//pets:state id=ID created=CertifiedAt
+//pets:output ID string id Generated certificate number
+//pets:output CertifiedAt string certifiedAt When the dog was certified
+//pets:output ExpiresOn string expiresOn Last day the certificate is valid
+//pets:output TrainingLevel TrainingLevel trainingLevel The dog's training level when it was certified
+//pets:output HealthCheckDate string healthCheckDate Day of the checkup the certification relied on
+//pets:output TaskSessions map[string]int taskSessions Task training sessions on each certified task
+type ServiceDogCertificationArgs struct {
+	DogID       string                `pulumi:"dogId" validate:"required"`
+	HandlerName string                `pulumi:"handlerName" validate:"required"`
+	Purpose     *CertificationPurpose `pulumi:"purpose,optional" default:"service" validate:"oneof=service|therapy"` // One of service or therapy
+	Tasks       []string              `pulumi:"tasks,optional"`                                                      // What a service dog does for its handler, named like the skill of its DogTraining sessions
+}
+
+// serviceTaskSessions is how many task sessions each certified task needs
+const serviceTaskSessions = 5
+
+// therapySessions is how many socialization sessions a therapy dog needs
+const therapySessions = 5
+
+// certificationMonths is how long a certificate, and a checkup, stays good
+const certificationMonths = 12
+
+var certifications = crudResource[ServiceDogCertificationArgs, ServiceDogCertificationState, *ServiceDogCertificationState]{
+	kind:     stored[ServiceDogCertificationState]("certification"),
+	prefix:   "cert",
+	slug:     func(input ServiceDogCertificationArgs) string { return input.DogID },
+	newState: newServiceDogCertificationState,
+	populate: func(ctx context.Context, state *ServiceDogCertificationState, input ServiceDogCertificationArgs) error {
+		now, _ := time.Parse(time.RFC3339, state.CertifiedAt)
+		state.ExpiresOn = now.AddDate(0, certificationMonths, -1).Format(dateLayout)
+		return certify(ctx, state, now)
+	},
+	keep: func(state *ServiceDogCertificationState, oldState ServiceDogCertificationState) {
+		state.ExpiresOn = oldState.ExpiresOn
+	},
+	carry: func(ctx context.Context, state *ServiceDogCertificationState, oldState ServiceDogCertificationState, now time.Time) error {
+		return certify(ctx, state, now)
+	},
+}
+
+func (ServiceDogCertification) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (ServiceDogCertificationArgs, []p.CheckFailure, error) {
+	args, failures, err := certifications.check(newInputs)
+	if args.purpose() == PurposeService && len(args.Tasks) == 0 {
+		failures = append(failures, p.CheckFailure{Property: "tasks", Reason: "a service certification needs at least one task"})
+	}
+	seen := map[string]bool{}
+	for i, task := range args.Tasks {
+		property := fmt.Sprintf("tasks[%d]", i)
+		switch {
+		case strings.TrimSpace(task) == "":
+			failures = append(failures, p.CheckFailure{Property: property, Reason: "tasks must not be blank"})
+		case seen[task]:
+			failures = append(failures, p.CheckFailure{Property: property, Reason: fmt.Sprintf("task %q is listed twice", task)})
+		}
+		seen[task] = true
+	}
+	return args, failures, err
+}
+
+func (ServiceDogCertification) Create(ctx context.Context, name string, input ServiceDogCertificationArgs, preview bool) (string, ServiceDogCertificationState, error) {
+	return certifications.create(ctx, name, input, preview)
+}
+
+func (ServiceDogCertification) Read(ctx context.Context, id string, inputs ServiceDogCertificationArgs, state ServiceDogCertificationState) (string, ServiceDogCertificationArgs, ServiceDogCertificationState, error) {
+	return certifications.read(ctx, id, inputs, state)
+}
+
+func (ServiceDogCertification) Update(ctx context.Context, id string, oldState ServiceDogCertificationState, input ServiceDogCertificationArgs, preview bool) (ServiceDogCertificationState, error) {
+	return certifications.update(ctx, id, oldState, input, preview)
+}
+
+func (ServiceDogCertification) Delete(ctx context.Context, id string, state ServiceDogCertificationState) error {
+	return certifications.delete(ctx, id, state)
+}
+
+func (args ServiceDogCertificationArgs) purpose() CertificationPurpose {
+	if args.Purpose == nil {
+		return PurposeService
+	}
+	return *args.Purpose
+}
+
+// certify checks the dog's prerequisites against the registry and records
+// what the certification relied on, or fails listing everything missing
+func certify(ctx context.Context, state *ServiceDogCertificationState, now time.Time) error {
+	dog, err := walkedDog(ctx, state.DogID)
+	if err != nil {
+		return err
+	}
+	sessions, err := listForDog[DogTrainingState](ctx, "training", state.DogID)
+	if err != nil {
+		return err
+	}
+	visits, err := listForDog[VeterinaryVisitState](ctx, "visit", state.DogID)
+	if err != nil {
+		return err
+	}
+	missing := certificationPrerequisites(state, dog, sessions, visits, now)
+	if len(missing) > 0 {
+		return fmt.Errorf("%s can't be certified as a %s dog yet:\n  - %s", dog.Name, state.purpose(), strings.Join(missing, "\n  - "))
+	}
+	return nil
+}
+
+// certificationPrerequisites fills in the certification's outputs and
+// returns what the dog still lacks, each with what to do about it
+func certificationPrerequisites(state *ServiceDogCertificationState, dog DogState, sessions []DogTrainingState, visits []VeterinaryVisitState, now time.Time) []string {
+	var missing []string
+
+	state.TrainingLevel = Basic
+	if dog.TrainingLevel != nil {
+		state.TrainingLevel = *dog.TrainingLevel
+	}
+	therapy := state.purpose() == PurposeTherapy
+	switch {
+	case therapy && (state.TrainingLevel == Untrained || state.TrainingLevel == Basic):
+		missing = append(missing, fmt.Sprintf("trainingLevel is %s; set the Dog's trainingLevel to intermediate or above once it gets there", state.TrainingLevel))
+	case !therapy && state.TrainingLevel != Advanced && state.TrainingLevel != Professional:
+		missing = append(missing, fmt.Sprintf("trainingLevel is %s; set the Dog's trainingLevel to advanced or professional once it gets there", state.TrainingLevel))
+	}
+
+	state.TaskSessions = map[string]int{}
+	if therapy {
+		socialized := 0
+		for _, n := range countSessions(sessions, FocusSocialization) {
+			socialized += n
+		}
+		if short := therapySessions - socialized; short > 0 {
+			missing = append(missing, fmt.Sprintf("%d of %d socialization sessions; record %d more DogTraining with focus socialization", socialized, therapySessions, short))
+		}
+	}
+	counts := countSessions(sessions, FocusTask)
+	for _, task := range state.Tasks {
+		state.TaskSessions[task] = counts[task]
+		if short := serviceTaskSessions - counts[task]; short > 0 && !therapy {
+			missing = append(missing, fmt.Sprintf("task %q has %d of %d task training sessions; record %d more DogTraining with skill %q and focus task", task, counts[task], serviceTaskSessions, short, task))
+		}
+	}
+
+	state.HealthCheckDate = ""
+	since := now.AddDate(0, -certificationMonths, 0)
+	for _, visit := range visits {
+		at, err := time.Parse(time.RFC3339, visit.Date)
+		if err != nil || visit.VisitType != VisitCheckup || at.Before(since) || at.After(now) {
+			continue
+		}
+		if day := at.Format(dateLayout); day > state.HealthCheckDate {
+			state.HealthCheckDate = day
+		}
+	}
+	if state.HealthCheckDate == "" {
+		missing = append(missing, fmt.Sprintf("no checkup since %s; record a VeterinaryVisit with visitType checkup", since.Format(dateLayout)))
+	}
+	return missing
+}
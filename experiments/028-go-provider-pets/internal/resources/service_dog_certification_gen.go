@@ -0,0 +1,57 @@
+// Code generated by genstate from service_dog_certification.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// ServiceDogCertificationOutputs are computed by the provider; Check rejects them as inputs
+type ServiceDogCertificationOutputs struct {
+	ID              string         `pulumi:"id"`
+	CertifiedAt     string         `pulumi:"certifiedAt"`
+	ExpiresOn       string         `pulumi:"expiresOn"`
+	TrainingLevel   TrainingLevel  `pulumi:"trainingLevel"`
+	HealthCheckDate string         `pulumi:"healthCheckDate"`
+	TaskSessions    map[string]int `pulumi:"taskSessions"`
+	Version         int64          `pulumi:"version"`
+}
+
+// ServiceDogCertificationState echoes the inputs next to the computed outputs
+type ServiceDogCertificationState struct {
+	ServiceDogCertificationArgs
+	ServiceDogCertificationOutputs
+}
+
+// newServiceDogCertificationState copies the inputs into an otherwise empty state
+func newServiceDogCertificationState(input ServiceDogCertificationArgs) ServiceDogCertificationState {
+	return ServiceDogCertificationState{ServiceDogCertificationArgs: input}
+}
+
+func (s *ServiceDogCertificationState) stamp(id, created string)   { s.ID, s.CertifiedAt = id, created }
+func (s *ServiceDogCertificationState) identity() (string, string) { return s.ID, s.CertifiedAt }
+func (s *ServiceDogCertificationState) setVersion(version int64)   { s.Version = version }
+func (s *ServiceDogCertificationState) storedVersion() int64       { return s.Version }
+
+func (args *ServiceDogCertificationArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Purpose, "One of service or therapy")
+	a.SetDefault(&args.Purpose, CertificationPurpose("service"))
+	a.Describe(&args.Tasks, "What a service dog does for its handler, named like the skill of its DogTraining sessions")
+}
+
+// applyDefaults fills unset optional inputs with their schema defaults
+func (args *ServiceDogCertificationArgs) applyDefaults() {
+	if args.Purpose == nil {
+		v := CertificationPurpose("service")
+		args.Purpose = &v
+	}
+}
+
+func (state *ServiceDogCertificationState) Annotate(a infer.Annotator) {
+	state.ServiceDogCertificationArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated certificate number")
+	a.Describe(&state.CertifiedAt, "When the dog was certified")
+	a.Describe(&state.ExpiresOn, "Last day the certificate is valid")
+	a.Describe(&state.TrainingLevel, "The dog's training level when it was certified")
+	a.Describe(&state.HealthCheckDate, "Day of the checkup the certification relied on")
+	a.Describe(&state.TaskSessions, "Task training sessions on each certified task")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
@@ -0,0 +1,112 @@
+package resources
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCertificationPrerequisites(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tasks := func(counts map[string]int) []DogTrainingState {
+		var sessions []DogTrainingState
+		for skill, n := range counts {
+			for i := 0; i < n; i++ {
+				var s DogTrainingState
+				s.Skill, s.Focus = skill, FocusTask
+				sessions = append(sessions, s)
+			}
+		}
+		return sessions
+	}
+	checkup := func(date string, visitType VisitType) VeterinaryVisitState {
+		var v VeterinaryVisitState
+		v.Date, v.VisitType = date, visitType
+		return v
+	}
+	social := func(n int) []DogTrainingState {
+		sessions := make([]DogTrainingState, n)
+		for i := range sessions {
+			sessions[i].Skill, sessions[i].Focus = "meet-strangers", FocusSocialization
+		}
+		return sessions
+	}
+	level := func(l TrainingLevel) *TrainingLevel { return &l }
+
+	tests := []struct {
+		name        string
+		purpose     CertificationPurpose
+		level       *TrainingLevel
+		sessions    []DogTrainingState
+		visits      []VeterinaryVisitState
+		missing     []string
+		healthCheck string
+	}{
+		{
+			name:        "ready",
+			level:       level(Advanced),
+			sessions:    tasks(map[string]int{"open-door": 5, "alert": 6}),
+			visits:      []VeterinaryVisitState{checkup("2026-03-01T10:00:00Z", VisitCheckup), checkup("2026-06-01T10:00:00Z", VisitCheckup)},
+			healthCheck: "2026-06-01",
+		},
+		{
+			name:     "missing everything",
+			sessions: tasks(map[string]int{"open-door": 2}),
+			visits:   []VeterinaryVisitState{checkup("2025-09-01T10:00:00Z", VisitCheckup), checkup("2026-09-01T10:00:00Z", VisitDental)},
+			missing: []string{
+				"trainingLevel is basic",
+				`task "open-door" has 2 of 5 task training sessions; record 3 more`,
+				`task "alert" has 0 of 5`,
+				"no checkup since 2025-10-16",
+			},
+		},
+		{
+			name:        "professional but one task short",
+			level:       level(Professional),
+			sessions:    tasks(map[string]int{"open-door": 5, "alert": 4}),
+			visits:      []VeterinaryVisitState{checkup("2025-11-01T10:00:00Z", VisitCheckup)},
+			missing:     []string{`task "alert" has 4 of 5`},
+			healthCheck: "2025-11-01",
+		},
+		{
+			name:        "therapy dog",
+			purpose:     PurposeTherapy,
+			level:       level(Intermediate),
+			sessions:    append(tasks(map[string]int{"open-door": 1}), social(5)...),
+			visits:      []VeterinaryVisitState{checkup("2026-10-01T10:00:00Z", VisitCheckup)},
+			healthCheck: "2026-10-01",
+		},
+		{
+			name:        "therapy dog still shy",
+			purpose:     PurposeTherapy,
+			sessions:    social(3),
+			visits:      []VeterinaryVisitState{checkup("2026-10-01T10:00:00Z", VisitCheckup)},
+			missing:     []string{"trainingLevel is basic; set the Dog's trainingLevel to intermediate", "3 of 5 socialization sessions; record 2 more"},
+			healthCheck: "2026-10-01",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dog DogState
+			dog.TrainingLevel = tt.level
+			var state ServiceDogCertificationState
+			state.Tasks = []string{"open-door", "alert"}
+			if tt.purpose != "" {
+				state.Purpose = &tt.purpose
+			}
+
+			missing := certificationPrerequisites(&state, dog, tt.sessions, tt.visits, now)
+			if len(missing) != len(tt.missing) {
+				t.Fatalf("missing = %q, want %d entries", missing, len(tt.missing))
+			}
+			for i, want := range tt.missing {
+				if !strings.HasPrefix(missing[i], want) {
+					t.Errorf("missing[%d] = %q, want it to start %q", i, missing[i], want)
+				}
+			}
+			if state.HealthCheckDate != tt.healthCheck {
+				t.Errorf("healthCheckDate = %q, want %q", state.HealthCheckDate, tt.healthCheck)
+			}
+		})
+	}
+}
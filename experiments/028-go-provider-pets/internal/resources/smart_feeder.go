@@ -0,0 +1,266 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// SmartFeeder Resource - the twin of an automatic feeder. The inputs are
+// the desired state, pushed to the device on Create and Update; the
+// outputs are what the device last reported, fetched by Read from the
+// deviceApi or a simulator. Diff only compares desired state, so a device
+// that hasn't caught up yet doesn't show as a change; inSync says whether
+// it has.
+type SmartFeeder struct{}
+
+//pets:state id=ID created=CreatedAt
+//pets:output ID string id Generated identifier of the feeder
+//pets:output CreatedAt string createdAt When the feeder was registered
+//pets:output Firmware string firmware Firmware version the device reported
+//pets:output LastDispense string lastDispense When the device last dispensed food
+//pets:output ReportedSchedule []string reportedSchedule Feeding times the device is running
+//pets:output ReportedPortionGrams int reportedPortionGrams Portion the device is dispensing, in grams
+//pets:output Online bool online Whether the device answered the last Read
+//pets:output InSync bool inSync Whether the device is running the desired schedule and portion
+type SmartFeederArgs struct {
+	DogID        string   `pulumi:"dogId" validate:"required"`
+	DeviceID     string   `pulumi:"deviceId" validate:"required"`
+	Schedule     []string `pulumi:"schedule"`                              // Feeding times as HH:MM in the device's local time
+	PortionGrams int      `pulumi:"portionGrams" validate:"gt=0,max=1000"` // Food per feeding, in grams
+}
+
+// feederReport is a feeder's reported state as the device API returns it
+type feederReport struct {
+	Firmware     string   `json:"firmware"`
+	LastDispense string   `json:"lastDispense"`
+	Schedule     []string `json:"schedule"`
+	PortionGrams int      `json:"portionGrams"`
+}
+
+// feederDesired is what the device API is asked to run
+type feederDesired struct {
+	Schedule     []string `json:"schedule"`
+	PortionGrams int      `json:"portionGrams"`
+}
+
+// simulatedFirmware is what simulated feeders report running
+const simulatedFirmware = "1.0.0-sim"
+
+var feeders = crudResource[SmartFeederArgs, SmartFeederState, *SmartFeederState]{
+	kind:     stored[SmartFeederState]("feeder"),
+	prefix:   "feeder",
+	slug:     func(input SmartFeederArgs) string { return input.DogID },
+	newState: newSmartFeederState,
+	populate: func(ctx context.Context, state *SmartFeederState, input SmartFeederArgs) error {
+		if _, err := walkedDog(ctx, input.DogID); err != nil {
+			return err
+		}
+		if err := pushFeeder(ctx, *state); err != nil {
+			return err
+		}
+		return reportFeeder(ctx, state)
+	},
+	carry: func(ctx context.Context, state *SmartFeederState, oldState SmartFeederState, now time.Time) error {
+		if err := pushFeeder(ctx, *state); err != nil {
+			return err
+		}
+		return reportFeeder(ctx, state)
+	},
+	refresh: func(ctx context.Context, id string, state *SmartFeederState) error {
+		return reportFeeder(ctx, state)
+	},
+}
+
+func (SmartFeeder) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (SmartFeederArgs, []p.CheckFailure, error) {
+	args, failures, err := feeders.check(newInputs)
+	return args, append(failures, checkFeedingTimes(args.Schedule)...), err
+}
+
+// Diff compares desired state only: the reported outputs are the device's
+// business. Moving the twin to another device replaces it.
+func (SmartFeeder) Diff(ctx context.Context, id string, olds SmartFeederState, news SmartFeederArgs) (p.DiffResponse, error) {
+	diff := map[string]p.PropertyDiff{}
+	if news.DeviceID != olds.DeviceID {
+		diff["deviceId"] = p.PropertyDiff{Kind: p.UpdateReplace, InputDiff: true}
+	}
+	if news.DogID != olds.DogID {
+		diff["dogId"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	if !slices.Equal(news.Schedule, olds.Schedule) {
+		diff["schedule"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	if news.PortionGrams != olds.PortionGrams {
+		diff["portionGrams"] = p.PropertyDiff{Kind: p.Update, InputDiff: true}
+	}
+	return p.DiffResponse{
+		HasChanges:          len(diff) > 0,
+		DeleteBeforeReplace: true,
+		DetailedDiff:        diff,
+	}, nil
+}
+
+func (SmartFeeder) Create(ctx context.Context, name string, input SmartFeederArgs, preview bool) (string, SmartFeederState, error) {
+	return feeders.create(ctx, name, input, preview)
+}
+
+func (SmartFeeder) Read(ctx context.Context, id string, inputs SmartFeederArgs, state SmartFeederState) (string, SmartFeederArgs, SmartFeederState, error) {
+	return feeders.read(ctx, id, inputs, state)
+}
+
+func (SmartFeeder) Update(ctx context.Context, id string, oldState SmartFeederState, input SmartFeederArgs, preview bool) (SmartFeederState, error) {
+	return feeders.update(ctx, id, oldState, input, preview)
+}
+
+func (SmartFeeder) Delete(ctx context.Context, id string, state SmartFeederState) error {
+	return feeders.delete(ctx, id, state)
+}
+
+// checkFeedingTimes reports schedule entries that aren't HH:MM or repeat
+func checkFeedingTimes(schedule []string) []p.CheckFailure {
+	var failures []p.CheckFailure
+	seen := map[string]bool{}
+	for i, at := range schedule {
+		property := fmt.Sprintf("schedule[%d]", i)
+		if _, err := time.Parse("15:04", at); err != nil || len(at) != len("15:04") {
+			failures = append(failures, p.CheckFailure{Property: property, Reason: "feeding times must be HH:MM"})
+			continue
+		}
+		if seen[at] {
+			failures = append(failures, p.CheckFailure{Property: property, Reason: at + " is already in the schedule"})
+		}
+		seen[at] = true
+	}
+	return failures
+}
+
+// pushFeeder asks the device to run the desired schedule and portion. The
+// simulator needs no telling.
+func pushFeeder(ctx context.Context, state SmartFeederState) error {
+	api := registry.DeviceAPI(ctx)
+	if api == nil {
+		return nil
+	}
+	body, err := json.Marshal(feederDesired{Schedule: state.Schedule, PortionGrams: state.PortionGrams})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, feederURL(api, state.DeviceID)+"/desired", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := deviceCall(ctx, api, req)
+	if err != nil {
+		return fmt.Errorf("updating feeder %s: %w", state.DeviceID, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// reportFeeder fills in the outputs from what the device reports. A device
+// that can't be reached is offline rather than an error, so a refresh
+// doesn't fail because a feeder lost its Wi-Fi.
+func reportFeeder(ctx context.Context, state *SmartFeederState) error {
+	api := registry.DeviceAPI(ctx)
+	if api == nil {
+		applyReport(state, simulatedFeeder(state.SmartFeederArgs, registry.Now(ctx)))
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feederURL(api, state.DeviceID), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := deviceCall(ctx, api, req)
+	if err != nil {
+		p.GetLogger(ctx).Warningf("feeder %s is offline: %v", state.DeviceID, err)
+		state.Online, state.InSync = false, false
+		return nil
+	}
+	defer resp.Body.Close()
+	var report feederReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return fmt.Errorf("feeder %s report: %w", state.DeviceID, err)
+	}
+	applyReport(state, report)
+	return nil
+}
+
+// applyReport records what an online device reported
+func applyReport(state *SmartFeederState, report feederReport) {
+	state.Firmware = report.Firmware
+	state.LastDispense = report.LastDispense
+	state.ReportedSchedule = report.Schedule
+	state.ReportedPortionGrams = report.PortionGrams
+	state.Online = true
+	state.InSync = sameFeedingTimes(report.Schedule, state.Schedule) && report.PortionGrams == state.PortionGrams
+}
+
+// sameFeedingTimes compares schedules regardless of order
+func sameFeedingTimes(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	sort.Strings(a)
+	sort.Strings(b)
+	return slices.Equal(a, b)
+}
+
+// simulatedFeeder is a device that applies whatever it is asked to at
+// once and has dispensed at every scheduled time so far
+func simulatedFeeder(desired SmartFeederArgs, now time.Time) feederReport {
+	report := feederReport{
+		Firmware:     simulatedFirmware,
+		Schedule:     desired.Schedule,
+		PortionGrams: desired.PortionGrams,
+	}
+	var last time.Time
+	for _, at := range desired.Schedule {
+		clock, err := time.Parse("15:04", at)
+		if err != nil {
+			continue
+		}
+		today := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, time.UTC)
+		if today.After(now) {
+			today = today.AddDate(0, 0, -1)
+		}
+		if today.After(last) {
+			last = today
+		}
+	}
+	if !last.IsZero() {
+		report.LastDispense = last.Format("2006-01-02T15:04:05Z")
+	}
+	return report
+}
+
+func feederURL(api *registry.DeviceAPIConfig, deviceID string) string {
+	return strings.TrimSuffix(api.BaseURL, "/") + "/feeders/" + url.PathEscape(deviceID)
+}
+
+// deviceCall sends a request to the device API, failing on any status
+// other than success
+func deviceCall(ctx context.Context, api *registry.DeviceAPIConfig, req *http.Request) (*http.Response, error) {
+	if api.APIKey != nil {
+		req.Header.Set("Authorization", "Bearer "+*api.APIKey)
+	}
+	resp, err := registry.HTTPClient(ctx).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp, nil
+}
@@ -0,0 +1,52 @@
+// Code generated by genstate from smart_feeder.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// SmartFeederOutputs are computed by the provider; Check rejects them as inputs
+type SmartFeederOutputs struct {
+	ID                   string   `pulumi:"id"`
+	CreatedAt            string   `pulumi:"createdAt"`
+	Firmware             string   `pulumi:"firmware"`
+	LastDispense         string   `pulumi:"lastDispense"`
+	ReportedSchedule     []string `pulumi:"reportedSchedule"`
+	ReportedPortionGrams int      `pulumi:"reportedPortionGrams"`
+	Online               bool     `pulumi:"online"`
+	InSync               bool     `pulumi:"inSync"`
+	Version              int64    `pulumi:"version"`
+}
+
+// SmartFeederState echoes the inputs next to the computed outputs
+type SmartFeederState struct {
+	SmartFeederArgs
+	SmartFeederOutputs
+}
+
+// newSmartFeederState copies the inputs into an otherwise empty state
+func newSmartFeederState(input SmartFeederArgs) SmartFeederState {
+	return SmartFeederState{SmartFeederArgs: input}
+}
+
+func (s *SmartFeederState) stamp(id, created string)   { s.ID, s.CreatedAt = id, created }
+func (s *SmartFeederState) identity() (string, string) { return s.ID, s.CreatedAt }
+func (s *SmartFeederState) setVersion(version int64)   { s.Version = version }
+func (s *SmartFeederState) storedVersion() int64       { return s.Version }
+
+func (args *SmartFeederArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Schedule, "Feeding times as HH:MM in the device's local time")
+	a.Describe(&args.PortionGrams, "Food per feeding, in grams")
+}
+
+func (state *SmartFeederState) Annotate(a infer.Annotator) {
+	state.SmartFeederArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the feeder")
+	a.Describe(&state.CreatedAt, "When the feeder was registered")
+	a.Describe(&state.Firmware, "Firmware version the device reported")
+	a.Describe(&state.LastDispense, "When the device last dispensed food")
+	a.Describe(&state.ReportedSchedule, "Feeding times the device is running")
+	a.Describe(&state.ReportedPortionGrams, "Portion the device is dispensing, in grams")
+	a.Describe(&state.Online, "Whether the device answered the last Read")
+	a.Describe(&state.InSync, "Whether the device is running the desired schedule and portion")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
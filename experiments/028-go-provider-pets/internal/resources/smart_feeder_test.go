@@ -0,0 +1,154 @@
+package resources
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+func TestCheckFeedingTimes(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule []string
+		failed   []string
+	}{
+		{name: "valid", schedule: []string{"07:30", "18:00"}},
+		{name: "empty"},
+		{name: "not a time", schedule: []string{"07:30", "dinner"}, failed: []string{"schedule[1]"}},
+		{name: "out of range", schedule: []string{"24:00"}, failed: []string{"schedule[0]"}},
+		{name: "missing leading zero", schedule: []string{"7:30"}, failed: []string{"schedule[0]"}},
+		{name: "repeated", schedule: []string{"07:30", "18:00", "07:30"}, failed: []string{"schedule[2]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := checkFeedingTimes(tt.schedule)
+			if len(failures) != len(tt.failed) {
+				t.Fatalf("failures = %+v, want %v", failures, tt.failed)
+			}
+			for i, f := range failures {
+				if f.Property != tt.failed[i] {
+					t.Errorf("failure %d on %q, want %q", i, f.Property, tt.failed[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSimulatedFeeder(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule []string
+		now      time.Time
+		want     string
+	}{
+		{
+			name:     "earlier today",
+			schedule: []string{"07:30", "18:00"},
+			now:      time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC),
+			want:     "2026-06-15T07:30:00Z",
+		},
+		{
+			name:     "exactly on time",
+			schedule: []string{"07:30", "18:00"},
+			now:      time.Date(2026, 6, 15, 18, 0, 0, 0, time.UTC),
+			want:     "2026-06-15T18:00:00Z",
+		},
+		{
+			name:     "before the first feeding",
+			schedule: []string{"07:30", "18:00"},
+			now:      time.Date(2026, 6, 15, 6, 0, 0, 0, time.UTC),
+			want:     "2026-06-14T18:00:00Z",
+		},
+		{
+			name: "no schedule",
+			now:  time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := simulatedFeeder(SmartFeederArgs{Schedule: tt.schedule, PortionGrams: 120}, tt.now)
+			if report.LastDispense != tt.want {
+				t.Errorf("lastDispense = %q, want %q", report.LastDispense, tt.want)
+			}
+			if report.Firmware != simulatedFirmware || report.PortionGrams != 120 {
+				t.Errorf("report = %+v, want the desired state on simulated firmware", report)
+			}
+		})
+	}
+}
+
+func TestApplyReport(t *testing.T) {
+	var state SmartFeederState
+	state.Schedule, state.PortionGrams = []string{"07:30", "18:00"}, 120
+
+	tests := []struct {
+		name   string
+		report feederReport
+		inSync bool
+	}{
+		{name: "caught up", report: feederReport{Schedule: []string{"07:30", "18:00"}, PortionGrams: 120}, inSync: true},
+		{name: "reordered", report: feederReport{Schedule: []string{"18:00", "07:30"}, PortionGrams: 120}, inSync: true},
+		{name: "old schedule", report: feederReport{Schedule: []string{"08:00"}, PortionGrams: 120}},
+		{name: "old portion", report: feederReport{Schedule: []string{"07:30", "18:00"}, PortionGrams: 100}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := state
+			applyReport(&got, tt.report)
+			if !got.Online || got.InSync != tt.inSync {
+				t.Errorf("online = %v, inSync = %v, want true, %v", got.Online, got.InSync, tt.inSync)
+			}
+		})
+	}
+}
+
+func TestSmartFeederDiff(t *testing.T) {
+	var olds SmartFeederState
+	olds.SmartFeederArgs = SmartFeederArgs{DogID: "dog-1", DeviceID: "feeder-a", Schedule: []string{"07:30"}, PortionGrams: 120}
+	// The device is lagging behind, which is not a change to the twin
+	olds.ReportedSchedule, olds.ReportedPortionGrams, olds.Firmware = []string{"06:00"}, 90, "1.0.0"
+
+	tests := []struct {
+		name  string
+		edit  func(*SmartFeederArgs)
+		diffs map[string]p.DiffKind
+	}{
+		{name: "unchanged", edit: func(*SmartFeederArgs) {}, diffs: map[string]p.DiffKind{}},
+		{
+			name:  "schedule",
+			edit:  func(a *SmartFeederArgs) { a.Schedule = []string{"07:30", "18:00"} },
+			diffs: map[string]p.DiffKind{"schedule": p.Update},
+		},
+		{
+			name:  "portion",
+			edit:  func(a *SmartFeederArgs) { a.PortionGrams = 150 },
+			diffs: map[string]p.DiffKind{"portionGrams": p.Update},
+		},
+		{
+			name:  "another device",
+			edit:  func(a *SmartFeederArgs) { a.DeviceID = "feeder-b" },
+			diffs: map[string]p.DiffKind{"deviceId": p.UpdateReplace},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			news := olds.SmartFeederArgs
+			news.Schedule = append([]string(nil), olds.Schedule...)
+			tt.edit(&news)
+			resp, err := SmartFeeder{}.Diff(context.Background(), "feeder-dog-1", olds, news)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.HasChanges != (len(tt.diffs) > 0) || len(resp.DetailedDiff) != len(tt.diffs) {
+				t.Fatalf("diff = %+v, want %v", resp.DetailedDiff, tt.diffs)
+			}
+			for key, kind := range tt.diffs {
+				if resp.DetailedDiff[key].Kind != kind {
+					t.Errorf("%s: kind %v, want %v", key, resp.DetailedDiff[key].Kind, kind)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// SmsReminder Resource - a text message about a dog, sent through Twilio
+// once sendAt comes round. A reminder that isn't due yet at Create is sent
+// by the first Read after it is, and Reads then follow its delivery until
+// Twilio reports a final status. Changing a reminder replaces it.
+type SmsReminder struct{}
+
+//pets:state id=ID created=CreatedAt
+//pets:output ID string id Generated identifier of the reminder
+//pets:output CreatedAt string createdAt When the reminder was scheduled
+//pets:output Body string body The message as sent, with the template filled in
+//pets:output Status string status scheduled, dry-run, or Twilio's delivery status: queued, sent, delivered, undelivered or failed
+//pets:output MessageSID string messageSid Twilio's identifier for the message
+//pets:output SentAt string sentAt When the message was handed to Twilio
+//pets:output ErrorMessage string errorMessage Why Twilio couldn't deliver the message
+type SmsReminderArgs struct {
+	DogID   string  `pulumi:"dogId" validate:"required"`
+	Phone   string  `pulumi:"phone" provider:"secret" validate:"required"` // Recipient in E.164 form, such as +15551234567
+	Message string  `pulumi:"message" validate:"required,max=1600"`        // Go template; {{.DogName}}, {{.OwnerName}} and {{.Breed}} are filled in
+	SendAt  *string `pulumi:"sendAt,optional"`                             // When to send, as an RFC 3339 timestamp; right away when unset
+	DryRun  *bool   `pulumi:"dryRun,optional" default:"false"`             // Render the message without sending it
+}
+
+// smsFields are what a reminder's template can refer to
+type smsFields struct {
+	DogName   string
+	OwnerName string
+	Breed     DogBreed
+}
+
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// finalSmsStatuses are the statuses a reminder stays in
+var finalSmsStatuses = map[string]bool{
+	"dry-run":     true,
+	"delivered":   true,
+	"undelivered": true,
+	"failed":      true,
+	"canceled":    true,
+}
+
+var reminders = crudResource[SmsReminderArgs, SmsReminderState, *SmsReminderState]{
+	kind:     stored[SmsReminderState]("sms"),
+	prefix:   "sms",
+	slug:     func(input SmsReminderArgs) string { return input.DogID },
+	newState: newSmsReminderState,
+	populate: func(ctx context.Context, state *SmsReminderState, input SmsReminderArgs) error {
+		dog, err := walkedDog(ctx, input.DogID)
+		if err != nil {
+			return err
+		}
+		if state.Body, err = renderSms(input.Message, dog); err != nil {
+			return err
+		}
+		state.Status = "scheduled"
+		_, err = deliverSms(ctx, state, registry.Now(ctx))
+		return err
+	},
+	refresh: func(ctx context.Context, id string, state *SmsReminderState) error {
+		changed, err := deliverSms(ctx, state, registry.Now(ctx))
+		if err != nil || !changed {
+			return err
+		}
+		// Sending is not repeatable, so the registry has to know at once
+		version, err := registry.Save(ctx, "sms", id, state.Version, *state)
+		if err != nil {
+			return err
+		}
+		state.Version = version
+		return nil
+	},
+}
+
+func (SmsReminder) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (SmsReminderArgs, []p.CheckFailure, error) {
+	args, failures, err := reminders.check(newInputs)
+	return args, append(failures, checkSms(args)...), err
+}
+
+func (SmsReminder) Create(ctx context.Context, name string, input SmsReminderArgs, preview bool) (string, SmsReminderState, error) {
+	return reminders.create(ctx, name, input, preview)
+}
+
+func (SmsReminder) Read(ctx context.Context, id string, inputs SmsReminderArgs, state SmsReminderState) (string, SmsReminderArgs, SmsReminderState, error) {
+	return reminders.read(ctx, id, inputs, state)
+}
+
+// Delete forgets the reminder; a message already sent can't be recalled
+func (SmsReminder) Delete(ctx context.Context, id string, state SmsReminderState) error {
+	return reminders.delete(ctx, id, state)
+}
+
+// checkSms reports a malformed phone number, template or send time
+func checkSms(args SmsReminderArgs) []p.CheckFailure {
+	var failures []p.CheckFailure
+	if args.Phone != "" && !e164.MatchString(args.Phone) {
+		failures = append(failures, p.CheckFailure{Property: "phone", Reason: "phone must be in E.164 form, such as +15551234567"})
+	}
+	if args.Message != "" {
+		if _, err := renderSms(args.Message, DogState{}); err != nil {
+			failures = append(failures, p.CheckFailure{Property: "message", Reason: err.Error()})
+		}
+	}
+	if args.SendAt != nil {
+		if _, err := time.Parse(time.RFC3339, *args.SendAt); err != nil {
+			failures = append(failures, p.CheckFailure{Property: "sendAt", Reason: "sendAt must be an RFC 3339 timestamp"})
+		}
+	}
+	return failures
+}
+
+// renderSms fills in a reminder's template for a dog
+func renderSms(message string, dog DogState) (string, error) {
+	tmpl, err := template.New("message").Option("missingkey=error").Parse(message)
+	if err != nil {
+		return "", fmt.Errorf("invalid message template: %w", err)
+	}
+	var body strings.Builder
+	fields := smsFields{DogName: dog.Name, OwnerName: dog.OwnerName, Breed: dog.Breed}
+	if err := tmpl.Execute(&body, fields); err != nil {
+		return "", fmt.Errorf("invalid message template: %w", err)
+	}
+	return body.String(), nil
+}
+
+// smsDue reports whether a reminder should go out at now
+func smsDue(state SmsReminderState, now time.Time) bool {
+	if state.SendAt == nil {
+		return true
+	}
+	at, err := time.Parse(time.RFC3339, *state.SendAt)
+	return err == nil && !now.Before(at)
+}
+
+// deliverSms moves a reminder along: it sends a scheduled one that is due
+// at now and asks Twilio how a sent one is getting on. It reports whether
+// the state changed.
+func deliverSms(ctx context.Context, state *SmsReminderState, now time.Time) (bool, error) {
+	if finalSmsStatuses[state.Status] {
+		return false, nil
+	}
+	if state.Status == "scheduled" && !smsDue(*state, now) {
+		return false, nil
+	}
+
+	config := registry.Twilio(ctx)
+	if state.Status == "scheduled" {
+		if (state.DryRun != nil && *state.DryRun) || (config != nil && config.DryRun != nil && *config.DryRun) || registry.Simulating(ctx) {
+			p.GetLogger(ctx).Infof("dry run: not texting reminder %s: %s", state.ID, state.Body)
+			state.Status, state.SentAt = "dry-run", now.Format("2006-01-02T15:04:05Z")
+			return true, nil
+		}
+		if config == nil {
+			return false, errors.New("twilio is not configured: set twilio in the provider config, or dryRun on the reminder")
+		}
+		msg, err := sendSms(ctx, registry.HTTPClient(ctx), config, state.Phone, state.Body)
+		if err != nil {
+			return false, err
+		}
+		state.MessageSID, state.SentAt = msg.SID, now.Format("2006-01-02T15:04:05Z")
+		msg.apply(state)
+		return true, nil
+	}
+
+	if state.MessageSID == "" || config == nil {
+		return false, nil
+	}
+	msg, err := smsStatus(ctx, registry.HTTPClient(ctx), config, state.MessageSID)
+	if err != nil {
+		return false, err
+	}
+	before := state.Status
+	msg.apply(state)
+	return state.Status != before, nil
+}
+
+// twilioMessage is the part of Twilio's message resource reminders use
+type twilioMessage struct {
+	SID          string  `json:"sid"`
+	Status       string  `json:"status"`
+	ErrorMessage *string `json:"error_message"`
+}
+
+func (m twilioMessage) apply(state *SmsReminderState) {
+	state.Status = m.Status
+	if m.ErrorMessage != nil {
+		state.ErrorMessage = *m.ErrorMessage
+	}
+}
+
+// sendSms creates a message through Twilio's Messages API
+func sendSms(ctx context.Context, client *http.Client, config *registry.TwilioConfig, to, body string) (twilioMessage, error) {
+	form := url.Values{"To": {to}, "From": {config.FromNumber}, "Body": {body}}
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", config.URL(), url.PathEscape(config.AccountSID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return twilioMessage{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return twilioCall(client, config, req, "sending SMS")
+}
+
+// smsStatus fetches a message's current delivery status
+func smsStatus(ctx context.Context, client *http.Client, config *registry.TwilioConfig, sid string) (twilioMessage, error) {
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages/%s.json", config.URL(), url.PathEscape(config.AccountSID), url.PathEscape(sid))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return twilioMessage{}, err
+	}
+	return twilioCall(client, config, req, "checking SMS status")
+}
+
+func twilioCall(client *http.Client, config *registry.TwilioConfig, req *http.Request, what string) (twilioMessage, error) {
+	req.SetBasicAuth(config.AccountSID, config.AuthToken)
+	resp, err := client.Do(req)
+	if err != nil {
+		return twilioMessage{}, fmt.Errorf("%s: %w", what, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		var failure struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&failure) == nil && failure.Message != "" {
+			return twilioMessage{}, fmt.Errorf("%s: %s (%s)", what, failure.Message, resp.Status)
+		}
+		return twilioMessage{}, fmt.Errorf("%s: unexpected status %s", what, resp.Status)
+	}
+	var msg twilioMessage
+	if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+		return twilioMessage{}, fmt.Errorf("%s: %w", what, err)
+	}
+	return msg, nil
+}
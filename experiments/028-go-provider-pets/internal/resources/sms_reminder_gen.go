@@ -0,0 +1,61 @@
+// Code generated by genstate from sms_reminder.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// SmsReminderOutputs are computed by the provider; Check rejects them as inputs
+type SmsReminderOutputs struct {
+	ID           string `pulumi:"id"`
+	CreatedAt    string `pulumi:"createdAt"`
+	Body         string `pulumi:"body"`
+	Status       string `pulumi:"status"`
+	MessageSID   string `pulumi:"messageSid"`
+	SentAt       string `pulumi:"sentAt"`
+	ErrorMessage string `pulumi:"errorMessage"`
+	Version      int64  `pulumi:"version"`
+}
+
+// SmsReminderState echoes the inputs next to the computed outputs
+type SmsReminderState struct {
+	SmsReminderArgs
+	SmsReminderOutputs
+}
+
+// newSmsReminderState copies the inputs into an otherwise empty state
+func newSmsReminderState(input SmsReminderArgs) SmsReminderState {
+	return SmsReminderState{SmsReminderArgs: input}
+}
+
+func (s *SmsReminderState) stamp(id, created string)   { s.ID, s.CreatedAt = id, created }
+func (s *SmsReminderState) identity() (string, string) { return s.ID, s.CreatedAt }
+func (s *SmsReminderState) setVersion(version int64)   { s.Version = version }
+func (s *SmsReminderState) storedVersion() int64       { return s.Version }
+
+func (args *SmsReminderArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Phone, "Recipient in E.164 form, such as +15551234567")
+	a.Describe(&args.Message, "Go template; {{.DogName}}, {{.OwnerName}} and {{.Breed}} are filled in")
+	a.Describe(&args.SendAt, "When to send, as an RFC 3339 timestamp; right away when unset")
+	a.Describe(&args.DryRun, "Render the message without sending it")
+	a.SetDefault(&args.DryRun, false)
+}
+
+// applyDefaults fills unset optional inputs with their schema defaults
+func (args *SmsReminderArgs) applyDefaults() {
+	if args.DryRun == nil {
+		v := false
+		args.DryRun = &v
+	}
+}
+
+func (state *SmsReminderState) Annotate(a infer.Annotator) {
+	state.SmsReminderArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the reminder")
+	a.Describe(&state.CreatedAt, "When the reminder was scheduled")
+	a.Describe(&state.Body, "The message as sent, with the template filled in")
+	a.Describe(&state.Status, "scheduled, dry-run, or Twilio's delivery status: queued, sent, delivered, undelivered or failed")
+	a.Describe(&state.MessageSID, "Twilio's identifier for the message")
+	a.Describe(&state.SentAt, "When the message was handed to Twilio")
+	a.Describe(&state.ErrorMessage, "Why Twilio couldn't deliver the message")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
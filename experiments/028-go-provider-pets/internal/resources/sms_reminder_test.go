@@ -0,0 +1,137 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+func TestCheckSms(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   SmsReminderArgs
+		failed []string
+	}{
+		{name: "valid", args: SmsReminderArgs{Phone: "+15551234567", Message: "{{.DogName}} is due a walk", SendAt: stringPtr("2026-07-01T09:00:00Z")}},
+		{name: "local number", args: SmsReminderArgs{Phone: "555-1234", Message: "Walk time"}, failed: []string{"phone"}},
+		{name: "broken template", args: SmsReminderArgs{Phone: "+15551234567", Message: "{{.DogName"}, failed: []string{"message"}},
+		{name: "unknown field", args: SmsReminderArgs{Phone: "+15551234567", Message: "{{.Nickname}}"}, failed: []string{"message"}},
+		{name: "malformed sendAt", args: SmsReminderArgs{Phone: "+15551234567", Message: "Walk time", SendAt: stringPtr("tomorrow")}, failed: []string{"sendAt"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := checkSms(tt.args)
+			if len(failures) != len(tt.failed) {
+				t.Fatalf("failures = %+v, want %v", failures, tt.failed)
+			}
+			for i, f := range failures {
+				if f.Property != tt.failed[i] {
+					t.Errorf("failure %d on %q, want %q", i, f.Property, tt.failed[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRenderSms(t *testing.T) {
+	dog := DogState{DogArgs: DogArgs{Name: "Rex", OwnerName: "Sam", Breed: Beagle}}
+	got, err := renderSms("Hi {{.OwnerName}}, {{.DogName}} the {{.Breed}} has a checkup tomorrow", dog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Hi Sam, Rex the beagle has a checkup tomorrow"; got != want {
+		t.Errorf("renderSms = %q, want %q", got, want)
+	}
+}
+
+func TestSmsDue(t *testing.T) {
+	now := time.Date(2026, 6, 15, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		sendAt *string
+		want   bool
+	}{
+		{sendAt: nil, want: true},
+		{sendAt: stringPtr("2026-06-15T09:00:00Z"), want: true},
+		{sendAt: stringPtr("2026-06-15T10:00:00+01:00"), want: true},
+		{sendAt: stringPtr("2026-06-15T09:00:01Z"), want: false},
+	}
+	for _, tt := range tests {
+		name := "unset"
+		if tt.sendAt != nil {
+			name = *tt.sendAt
+		}
+		t.Run(name, func(t *testing.T) {
+			state := SmsReminderState{SmsReminderArgs: SmsReminderArgs{SendAt: tt.sendAt}}
+			if got := smsDue(state, now); got != tt.want {
+				t.Errorf("smsDue = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeliverSmsDryRun(t *testing.T) {
+	yes := true
+	state := SmsReminderState{SmsReminderArgs: SmsReminderArgs{Phone: "+15551234567", DryRun: &yes}}
+	state.Status, state.Body = "scheduled", "Walk time"
+	now := time.Date(2026, 6, 15, 9, 0, 0, 0, time.UTC)
+	changed, err := deliverSms(context.Background(), &state, now)
+	if err != nil || !changed {
+		t.Fatalf("deliverSms = %v, %v", changed, err)
+	}
+	if state.Status != "dry-run" || state.MessageSID != "" || state.SentAt != "2026-06-15T09:00:00Z" {
+		t.Errorf("state = %+v, want a dry run at 09:00 with no message", state.SmsReminderOutputs)
+	}
+	if changed, _ := deliverSms(context.Background(), &state, now.Add(time.Hour)); changed {
+		t.Errorf("a dry run moved on")
+	}
+}
+
+func TestTwilioMessages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "AC1" || pass != "t0ken" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"code": 20003, "message": "Authenticate"}`)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/2010-04-01/Accounts/AC1/Messages.json":
+			r.ParseForm()
+			if r.PostForm.Get("To") != "+15551234567" || r.PostForm.Get("From") != "+15550000000" || r.PostForm.Get("Body") != "Walk time" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"sid": "SM1", "status": "queued", "error_message": null}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/2010-04-01/Accounts/AC1/Messages/SM1.json":
+			fmt.Fprint(w, `{"sid": "SM1", "status": "undelivered", "error_message": "Unreachable destination handset"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	config := &registry.TwilioConfig{AccountSID: "AC1", AuthToken: "t0ken", FromNumber: "+15550000000", BaseURL: &server.URL}
+
+	msg, err := sendSms(context.Background(), server.Client(), config, "+15551234567", "Walk time")
+	if err != nil || msg.SID != "SM1" || msg.Status != "queued" {
+		t.Fatalf("sendSms = %+v, %v", msg, err)
+	}
+
+	var state SmsReminderState
+	msg, err = smsStatus(context.Background(), server.Client(), config, "SM1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg.apply(&state)
+	if state.Status != "undelivered" || state.ErrorMessage != "Unreachable destination handset" {
+		t.Errorf("state = %+v", state.SmsReminderOutputs)
+	}
+
+	config.AuthToken = "wrong"
+	if _, err := sendSms(context.Background(), server.Client(), config, "+15551234567", "Walk time"); err == nil || err.Error() != "sending SMS: Authenticate (401 Unauthorized)" {
+		t.Errorf("err = %v, want Twilio's message", err)
+	}
+}
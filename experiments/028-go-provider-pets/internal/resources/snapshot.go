@@ -0,0 +1,100 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// RegistrySnapshot records a labeled point-in-time copy of the registry.
+// Declare it with dependsOn on the rest of the stack so it is taken once a
+// deployment has succeeded; change trigger to take a fresh snapshot on every
+// update. Snapshots outlive the resource so they stay available for rollback.
+type RegistrySnapshot struct{}
+
+type RegistrySnapshotArgs struct {
+	Label   string  `pulumi:"label" validate:"required"`
+	Trigger *string `pulumi:"trigger,optional"`
+}
+
+// RegistrySnapshotOutputs are computed by the provider; Check rejects them as inputs
+type RegistrySnapshotOutputs struct {
+	SnapshotID string `pulumi:"snapshotId"`
+	TakenAt    string `pulumi:"takenAt"`
+	Records    int    `pulumi:"records"`
+	Checksum   string `pulumi:"checksum"`
+}
+
+// RegistrySnapshotState echoes the inputs next to the computed outputs
+type RegistrySnapshotState struct {
+	RegistrySnapshotArgs
+	RegistrySnapshotOutputs
+}
+
+func (RegistrySnapshot) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (RegistrySnapshotArgs, []p.CheckFailure, error) {
+	return checkInputs[RegistrySnapshotArgs, RegistrySnapshotState](newInputs)
+}
+
+func (RegistrySnapshot) Create(ctx context.Context, name string, input RegistrySnapshotArgs, preview bool) (string, RegistrySnapshotState, error) {
+	state := RegistrySnapshotState{RegistrySnapshotArgs: input}
+
+	if preview {
+		return name, state, nil
+	}
+
+	if err := registry.BeginOperation(); err != nil {
+		return "", state, err
+	}
+	defer registry.EndOperation()
+
+	if err := takeSnapshot(ctx, &state); err != nil {
+		return "", state, err
+	}
+	return state.SnapshotID, state, nil
+}
+
+func (RegistrySnapshot) Update(ctx context.Context, id string, oldState RegistrySnapshotState, input RegistrySnapshotArgs, preview bool) (RegistrySnapshotState, error) {
+	state := RegistrySnapshotState{RegistrySnapshotArgs: input}
+
+	if preview {
+		return state, nil
+	}
+
+	if err := registry.BeginOperation(); err != nil {
+		return state, err
+	}
+	defer registry.EndOperation()
+
+	if err := takeSnapshot(ctx, &state); err != nil {
+		return state, err
+	}
+	return state, nil
+}
+
+func takeSnapshot(ctx context.Context, state *RegistrySnapshotState) error {
+	store, err := registry.Store(ctx)
+	if err != nil {
+		return err
+	}
+	records, err := store.List("")
+	if err != nil {
+		return err
+	}
+
+	now := registry.Now(ctx)
+	trigger := ""
+	if state.Trigger != nil {
+		trigger = *state.Trigger
+	}
+	suffix := registry.IDSuffix(ctx, state.Label+"\x00"+trigger, now.UnixNano())
+	state.SnapshotID = fmt.Sprintf("snap-%s-%s", strings.ToLower(strings.ReplaceAll(state.Label, " ", "-")), suffix)
+	state.TakenAt = now.Format("2006-01-02T15:04:05Z")
+	state.Records = len(records)
+	state.Checksum, err = registry.WriteArchive(ctx, registry.SnapshotPath(ctx, state.SnapshotID), state.Label, records)
+	return err
+}
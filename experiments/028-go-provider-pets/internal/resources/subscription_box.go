@@ -0,0 +1,231 @@
+package resources
+
+import (
+	"context"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// SubscriptionBox Resource - a recurring delivery of treats and toys sized
+// to the dog. Changing tier takes effect from the next delivery, and the
+// price difference for the rest of the current period is charged or
+// credited pro rata.
+type SubscriptionBox struct{}
+
+//pets:state id=ID created=CreatedAt
+//pets:output ID string id Generated identifier of the subscription
+//pets:output CreatedAt string createdAt When the subscription was created
+//pets:output NextDeliveries []string nextDeliveries The upcoming delivery dates; recomputed on refresh
+//pets:output Contents []BoxItem contents What each box holds, sized to the dog
+//pets:output PricePerBox float64 pricePerBox Price of one box in dollars
+//pets:output MonthlyCost float64 monthlyCost Average cost per month at the cadence
+//pets:output TierChangeCharge float64 tierChangeCharge Prorated difference billed for the last tier change; negative is a credit
+//pets:embed CostDisplay
+type SubscriptionBoxArgs struct {
+	DogID      string  `pulumi:"dogId" validate:"required"`
+	Tier       BoxTier `pulumi:"tier" validate:"required,oneof=basic|premium|deluxe"`       // One of basic, premium or deluxe
+	Cadence    Cadence `pulumi:"cadence" validate:"required,oneof=weekly|biweekly|monthly"` // One of weekly, biweekly or monthly
+	StartDate  *string `pulumi:"startDate,optional"`                                        // First delivery as YYYY-MM-DD; defaults to the day the subscription is created
+	Deliveries *int    `pulumi:"deliveries,optional" default:"4" validate:"min=1,max=52"`   // How many upcoming deliveries to list
+}
+
+// BoxItem is one line of a subscription box
+type BoxItem struct {
+	Item     string  `pulumi:"item" json:"item"`
+	Size     PetSize `pulumi:"size" json:"size"`
+	Quantity int     `pulumi:"quantity" json:"quantity"`
+}
+
+// boxPrices are the price of a box for a medium dog
+var boxPrices = map[BoxTier]float64{
+	TierBasic:   25,
+	TierPremium: 40,
+	TierDeluxe:  60,
+}
+
+// sizeSurcharges cover the bigger toys and extra treats of larger dogs
+var sizeSurcharges = map[PetSize]float64{
+	Large:      5,
+	ExtraLarge: 10,
+}
+
+// boxItems lists each tier's contents for a medium dog; treat quantities
+// scale with the dog's size
+var boxItems = map[BoxTier][]BoxItem{
+	TierBasic: {
+		{Item: "training treats", Quantity: 2},
+		{Item: "toy", Quantity: 1},
+	},
+	TierPremium: {
+		{Item: "training treats", Quantity: 2},
+		{Item: "dental chew", Quantity: 4},
+		{Item: "toy", Quantity: 2},
+	},
+	TierDeluxe: {
+		{Item: "training treats", Quantity: 3},
+		{Item: "dental chew", Quantity: 6},
+		{Item: "toy", Quantity: 3},
+		{Item: "grooming kit", Quantity: 1},
+	},
+}
+
+// boxesPerMonth averages each cadence over a year
+var boxesPerMonth = map[Cadence]float64{
+	CadenceWeekly:   52.0 / 12,
+	CadenceBiweekly: 26.0 / 12,
+	CadenceMonthly:  1,
+}
+
+var boxes = crudResource[SubscriptionBoxArgs, SubscriptionBoxState, *SubscriptionBoxState]{
+	kind:     stored[SubscriptionBoxState]("subscription"),
+	prefix:   "box",
+	slug:     func(input SubscriptionBoxArgs) string { return input.DogID },
+	newState: newSubscriptionBoxState,
+	populate: func(ctx context.Context, state *SubscriptionBoxState, input SubscriptionBoxArgs) error {
+		if err := priceBox(ctx, state); err != nil {
+			return err
+		}
+		state.NextDeliveries = deliveryDates(boxAnchor(*state), state.Cadence, *state.Deliveries, registry.Now(ctx))
+		state.CostDisplay = showCosts(ctx, state.costs())
+		return nil
+	},
+	carry: func(ctx context.Context, state *SubscriptionBoxState, oldState SubscriptionBoxState, now time.Time) error {
+		if err := priceBox(ctx, state); err != nil {
+			return err
+		}
+		state.NextDeliveries = deliveryDates(boxAnchor(*state), state.Cadence, *state.Deliveries, now)
+		state.TierChangeCharge = oldState.TierChangeCharge
+		if state.Tier != oldState.Tier {
+			state.TierChangeCharge = proratedChange(oldState, state.PricePerBox, now)
+		}
+		state.CostDisplay = showCosts(ctx, state.costs())
+		return nil
+	},
+	refresh: func(ctx context.Context, id string, state *SubscriptionBoxState) error {
+		state.NextDeliveries = deliveryDates(boxAnchor(*state), state.Cadence, *state.Deliveries, registry.Now(ctx))
+		state.CostDisplay = showCosts(ctx, state.costs())
+		return nil
+	},
+}
+
+func (s SubscriptionBoxState) costs() map[string]float64 {
+	return map[string]float64{"pricePerBox": s.PricePerBox, "monthlyCost": s.MonthlyCost, "tierChangeCharge": s.TierChangeCharge}
+}
+
+func (SubscriptionBox) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (SubscriptionBoxArgs, []p.CheckFailure, error) {
+	args, failures, err := boxes.check(newInputs)
+	if args.StartDate != nil {
+		if _, perr := time.Parse(dateLayout, *args.StartDate); perr != nil {
+			failures = append(failures, p.CheckFailure{Property: "startDate", Reason: "startDate must be a YYYY-MM-DD date"})
+		}
+	}
+	return args, failures, err
+}
+
+func (SubscriptionBox) Create(ctx context.Context, name string, input SubscriptionBoxArgs, preview bool) (string, SubscriptionBoxState, error) {
+	return boxes.create(ctx, name, input, preview)
+}
+
+func (SubscriptionBox) Read(ctx context.Context, id string, inputs SubscriptionBoxArgs, state SubscriptionBoxState) (string, SubscriptionBoxArgs, SubscriptionBoxState, error) {
+	return boxes.read(ctx, id, inputs, state)
+}
+
+func (SubscriptionBox) Update(ctx context.Context, id string, oldState SubscriptionBoxState, input SubscriptionBoxArgs, preview bool) (SubscriptionBoxState, error) {
+	return boxes.update(ctx, id, oldState, input, preview)
+}
+
+func (SubscriptionBox) Delete(ctx context.Context, id string, state SubscriptionBoxState) error {
+	return boxes.delete(ctx, id, state)
+}
+
+// priceBox fills in the contents and prices for the subscribed dog's size
+func priceBox(ctx context.Context, state *SubscriptionBoxState) error {
+	dog, err := walkedDog(ctx, state.DogID)
+	if err != nil {
+		return err
+	}
+	size := determineSizeByBreed(dog.Breed)
+	if dog.Size != nil {
+		size = *dog.Size
+	}
+	state.Contents = boxContents(state.Tier, size)
+	state.PricePerBox = boxPrices[state.Tier] + sizeSurcharges[size]
+	state.MonthlyCost = round2(state.PricePerBox * boxesPerMonth[state.Cadence])
+	return nil
+}
+
+// boxContents sizes a tier's items for a dog
+func boxContents(tier BoxTier, size PetSize) []BoxItem {
+	items := make([]BoxItem, len(boxItems[tier]))
+	for i, item := range boxItems[tier] {
+		item.Size = size
+		if item.Item == "training treats" {
+			switch size {
+			case Small:
+				item.Quantity = (item.Quantity + 1) / 2
+			case Large, ExtraLarge:
+				item.Quantity *= 2
+			}
+		}
+		items[i] = item
+	}
+	return items
+}
+
+// boxAnchor is the date deliveries are counted from
+func boxAnchor(state SubscriptionBoxState) time.Time {
+	if state.StartDate != nil {
+		if start, err := time.Parse(dateLayout, *state.StartDate); err == nil {
+			return start
+		}
+	}
+	created, _ := time.Parse("2006-01-02T15:04:05Z", state.CreatedAt)
+	return created.Truncate(24 * time.Hour)
+}
+
+// delivery is the date of the nth delivery counted from anchor, the first
+// being number 0
+func delivery(anchor time.Time, cadence Cadence, n int) time.Time {
+	switch cadence {
+	case CadenceWeekly:
+		return anchor.AddDate(0, 0, 7*n)
+	case CadenceBiweekly:
+		return anchor.AddDate(0, 0, 14*n)
+	default:
+		return anchor.AddDate(0, n, 0)
+	}
+}
+
+// deliveryDates lists count deliveries from the day of now onwards
+func deliveryDates(anchor time.Time, cadence Cadence, count int, now time.Time) []string {
+	today := now.Format(dateLayout)
+	var dates []string
+	for n := 0; len(dates) < count; n++ {
+		if day := delivery(anchor, cadence, n).Format(dateLayout); day >= today {
+			dates = append(dates, day)
+		}
+	}
+	return dates
+}
+
+// proratedChange is what a tier change to newPrice costs. The current
+// period's box went out at the old price; the part of the period still to
+// run is billed at the new one, whose contents ship from the next delivery.
+func proratedChange(oldState SubscriptionBoxState, newPrice float64, now time.Time) float64 {
+	anchor := boxAnchor(oldState)
+	if now.Before(anchor) {
+		// Nothing has shipped yet, so the first box goes out at the new price
+		return 0
+	}
+	n := 0
+	for !delivery(anchor, oldState.Cadence, n+1).After(now) {
+		n++
+	}
+	start, next := delivery(anchor, oldState.Cadence, n), delivery(anchor, oldState.Cadence, n+1)
+	left := next.Sub(now).Hours() / next.Sub(start).Hours()
+	return round2((newPrice - oldState.PricePerBox) * left)
+}
@@ -0,0 +1,62 @@
+// Code generated by genstate from subscription_box.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// SubscriptionBoxOutputs are computed by the provider; Check rejects them as inputs
+type SubscriptionBoxOutputs struct {
+	ID               string    `pulumi:"id"`
+	CreatedAt        string    `pulumi:"createdAt"`
+	NextDeliveries   []string  `pulumi:"nextDeliveries"`
+	Contents         []BoxItem `pulumi:"contents"`
+	PricePerBox      float64   `pulumi:"pricePerBox"`
+	MonthlyCost      float64   `pulumi:"monthlyCost"`
+	TierChangeCharge float64   `pulumi:"tierChangeCharge"`
+	Version          int64     `pulumi:"version"`
+	CostDisplay
+}
+
+// SubscriptionBoxState echoes the inputs next to the computed outputs
+type SubscriptionBoxState struct {
+	SubscriptionBoxArgs
+	SubscriptionBoxOutputs
+}
+
+// newSubscriptionBoxState copies the inputs into an otherwise empty state
+func newSubscriptionBoxState(input SubscriptionBoxArgs) SubscriptionBoxState {
+	return SubscriptionBoxState{SubscriptionBoxArgs: input}
+}
+
+func (s *SubscriptionBoxState) stamp(id, created string)   { s.ID, s.CreatedAt = id, created }
+func (s *SubscriptionBoxState) identity() (string, string) { return s.ID, s.CreatedAt }
+func (s *SubscriptionBoxState) setVersion(version int64)   { s.Version = version }
+func (s *SubscriptionBoxState) storedVersion() int64       { return s.Version }
+
+func (args *SubscriptionBoxArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Tier, "One of basic, premium or deluxe")
+	a.Describe(&args.Cadence, "One of weekly, biweekly or monthly")
+	a.Describe(&args.StartDate, "First delivery as YYYY-MM-DD; defaults to the day the subscription is created")
+	a.Describe(&args.Deliveries, "How many upcoming deliveries to list")
+	a.SetDefault(&args.Deliveries, 4)
+}
+
+// applyDefaults fills unset optional inputs with their schema defaults
+func (args *SubscriptionBoxArgs) applyDefaults() {
+	if args.Deliveries == nil {
+		v := 4
+		args.Deliveries = &v
+	}
+}
+
+func (state *SubscriptionBoxState) Annotate(a infer.Annotator) {
+	state.SubscriptionBoxArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the subscription")
+	a.Describe(&state.CreatedAt, "When the subscription was created")
+	a.Describe(&state.NextDeliveries, "The upcoming delivery dates; recomputed on refresh")
+	a.Describe(&state.Contents, "What each box holds, sized to the dog")
+	a.Describe(&state.PricePerBox, "Price of one box in dollars")
+	a.Describe(&state.MonthlyCost, "Average cost per month at the cadence")
+	a.Describe(&state.TierChangeCharge, "Prorated difference billed for the last tier change; negative is a credit")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
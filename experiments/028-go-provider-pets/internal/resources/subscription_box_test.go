@@ -0,0 +1,84 @@
+package resources
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeliveryDates(t *testing.T) {
+	anchor := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		cadence Cadence
+		want    []string
+	}{
+		{cadence: CadenceWeekly, want: []string{"2026-03-14", "2026-03-21", "2026-03-28"}},
+		{cadence: CadenceBiweekly, want: []string{"2026-03-14", "2026-03-28", "2026-04-11"}},
+		// Counted from the anchor, so a short month doesn't shift later ones
+		{cadence: CadenceMonthly, want: []string{"2026-03-31", "2026-05-01", "2026-05-31"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.cadence), func(t *testing.T) {
+			got := deliveryDates(anchor, tt.cadence, 3, now)
+			if len(got) != len(tt.want) {
+				t.Fatalf("deliveryDates = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("delivery %d = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBoxContents(t *testing.T) {
+	tests := []struct {
+		name       string
+		tier       BoxTier
+		size       PetSize
+		wantItems  int
+		wantTreats int
+	}{
+		{name: "basic small", tier: TierBasic, size: Small, wantItems: 2, wantTreats: 1},
+		{name: "premium medium", tier: TierPremium, size: Medium, wantItems: 3, wantTreats: 2},
+		{name: "deluxe extra-large", tier: TierDeluxe, size: ExtraLarge, wantItems: 4, wantTreats: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := boxContents(tt.tier, tt.size)
+			if len(items) != tt.wantItems {
+				t.Fatalf("got %d items, want %d", len(items), tt.wantItems)
+			}
+			if items[0].Quantity != tt.wantTreats || items[0].Size != tt.size {
+				t.Errorf("treats = %+v, want %d for %s", items[0], tt.wantTreats, tt.size)
+			}
+		})
+	}
+	// The catalog itself is never scaled
+	if boxItems[TierDeluxe][0].Quantity != 3 {
+		t.Errorf("boxContents modified the catalog")
+	}
+}
+
+func TestProratedChange(t *testing.T) {
+	old := SubscriptionBoxState{SubscriptionBoxArgs: SubscriptionBoxArgs{Cadence: CadenceWeekly, StartDate: stringPtr("2026-06-01")}}
+	old.PricePerBox = 25
+	tests := []struct {
+		name     string
+		now      time.Time
+		newPrice float64
+		want     float64
+	}{
+		{name: "upgrade halfway through a week", now: time.Date(2026, 6, 11, 12, 0, 0, 0, time.UTC), newPrice: 39, want: 7},
+		{name: "downgrade on a delivery day", now: time.Date(2026, 6, 8, 0, 0, 0, 0, time.UTC), newPrice: 11, want: -14},
+		{name: "before the first delivery", now: time.Date(2026, 5, 20, 0, 0, 0, 0, time.UTC), newPrice: 60, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := proratedChange(old, tt.newPrice, tt.now); got != tt.want {
+				t.Errorf("proratedChange = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
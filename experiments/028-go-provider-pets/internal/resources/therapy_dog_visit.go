@@ -0,0 +1,151 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// TherapyDogVisit Resource - a certified therapy dog's visit to a hospital,
+// care home or school. Comforting strangers is tiring, so a dog gets at
+// most a few visits in any Monday-to-Sunday week; the limit is enforced
+// against the visits already in the registry.
+type TherapyDogVisit struct{}
+
+//pets:state id=ID created=RecordedAt
+//pets:output ID string id Generated identifier of the visit
+//pets:output RecordedAt string recordedAt When the visit was recorded
+//pets:output CertificationID string certificationId The therapy certification the visit was made under
+//pets:output WeekVisits int weekVisits Visits the dog made in the visit's week, this one included
+//pets:output TotalVisits int totalVisits Therapy visits the dog has made up to and including this one
+//pets:output TotalMinutes int totalMinutes Minutes the dog has spent on therapy visits up to and including this one
+//pets:output Facilities int facilities Distinct facilities the dog has visited up to and including this one
+type TherapyDogVisitArgs struct {
+	DogID           string `pulumi:"dogId" validate:"required"`
+	Facility        string `pulumi:"facility" validate:"required"`
+	Date            string `pulumi:"date" validate:"required"` // YYYY-MM-DD
+	DurationMinutes int    `pulumi:"durationMinutes" validate:"min=10,max=120"`
+}
+
+// therapyVisitsPerWeek is how many therapy visits a dog makes in a week at
+// most
+const therapyVisitsPerWeek = 3
+
+var therapyVisits = crudResource[TherapyDogVisitArgs, TherapyDogVisitState, *TherapyDogVisitState]{
+	kind:     stored[TherapyDogVisitState]("therapy-visit"),
+	prefix:   "therapy",
+	slug:     func(input TherapyDogVisitArgs) string { return input.DogID },
+	newState: newTherapyDogVisitState,
+	populate: func(ctx context.Context, state *TherapyDogVisitState, input TherapyDogVisitArgs) error {
+		dog, err := walkedDog(ctx, input.DogID)
+		if err != nil {
+			return err
+		}
+		day, err := time.Parse(dateLayout, input.Date)
+		if err != nil {
+			return fmt.Errorf("date must be YYYY-MM-DD: %w", err)
+		}
+		certs, err := listForDog[ServiceDogCertificationState](ctx, "certification", input.DogID)
+		if err != nil {
+			return err
+		}
+		if state.CertificationID = therapyCertification(certs, input.Date); state.CertificationID == "" {
+			return fmt.Errorf("%s has no therapy certification valid on %s; create a ServiceDogCertification with purpose therapy first", dog.Name, input.Date)
+		}
+		history, err := listForDog[TherapyDogVisitState](ctx, "therapy-visit", input.DogID)
+		if err != nil {
+			return err
+		}
+		if booked := visitsInWeek(history, day); booked >= therapyVisitsPerWeek {
+			monday := weekStart(day)
+			return fmt.Errorf("%s already has %d therapy visits in the week of %s, the most a dog makes in a week; pick a date from %s on",
+				dog.Name, booked, monday.Format(dateLayout), monday.AddDate(0, 0, 7).Format(dateLayout))
+		}
+		therapyStats(state, append(history, *state))
+		return nil
+	},
+	refresh: func(ctx context.Context, id string, state *TherapyDogVisitState) error {
+		history, err := listForDog[TherapyDogVisitState](ctx, "therapy-visit", state.DogID)
+		if err != nil {
+			return err
+		}
+		therapyStats(state, history)
+		return nil
+	},
+}
+
+func (TherapyDogVisit) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (TherapyDogVisitArgs, []p.CheckFailure, error) {
+	args, failures, err := therapyVisits.check(newInputs)
+	if args.Date != "" {
+		if _, perr := time.Parse(dateLayout, args.Date); perr != nil {
+			failures = append(failures, p.CheckFailure{Property: "date", Reason: "date must be YYYY-MM-DD"})
+		}
+	}
+	return args, failures, err
+}
+
+func (TherapyDogVisit) Create(ctx context.Context, name string, input TherapyDogVisitArgs, preview bool) (string, TherapyDogVisitState, error) {
+	return therapyVisits.create(ctx, name, input, preview)
+}
+
+func (TherapyDogVisit) Read(ctx context.Context, id string, inputs TherapyDogVisitArgs, state TherapyDogVisitState) (string, TherapyDogVisitArgs, TherapyDogVisitState, error) {
+	return therapyVisits.read(ctx, id, inputs, state)
+}
+
+func (TherapyDogVisit) Delete(ctx context.Context, id string, state TherapyDogVisitState) error {
+	return therapyVisits.delete(ctx, id, state)
+}
+
+// therapyCertification is the ID of a therapy certification valid on day,
+// or empty when there is none
+func therapyCertification(certs []ServiceDogCertificationState, day string) string {
+	for _, cert := range certs {
+		certified, err := time.Parse(time.RFC3339, cert.CertifiedAt)
+		if err != nil || cert.purpose() != PurposeTherapy {
+			continue
+		}
+		if certified.Format(dateLayout) <= day && day <= cert.ExpiresOn {
+			return cert.ID
+		}
+	}
+	return ""
+}
+
+// weekStart is the Monday of day's week
+func weekStart(day time.Time) time.Time {
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// visitsInWeek counts the visits in the Monday-to-Sunday week of day
+func visitsInWeek(visits []TherapyDogVisitState, day time.Time) int {
+	monday := weekStart(day).Format(dateLayout)
+	sunday := weekStart(day).AddDate(0, 0, 6).Format(dateLayout)
+	n := 0
+	for _, visit := range visits {
+		if monday <= visit.Date && visit.Date <= sunday {
+			n++
+		}
+	}
+	return n
+}
+
+// therapyStats totals the visits up to and including state's date
+func therapyStats(state *TherapyDogVisitState, visits []TherapyDogVisitState) {
+	day, _ := time.Parse(dateLayout, state.Date)
+	state.WeekVisits = visitsInWeek(visits, day)
+	state.TotalVisits, state.TotalMinutes = 0, 0
+	facilities := map[string]bool{}
+	for _, visit := range visits {
+		if visit.Date > state.Date {
+			continue
+		}
+		state.TotalVisits++
+		state.TotalMinutes += visit.DurationMinutes
+		facilities[strings.ToLower(strings.TrimSpace(visit.Facility))] = true
+	}
+	state.Facilities = len(facilities)
+}
@@ -0,0 +1,49 @@
+// Code generated by genstate from therapy_dog_visit.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// TherapyDogVisitOutputs are computed by the provider; Check rejects them as inputs
+type TherapyDogVisitOutputs struct {
+	ID              string `pulumi:"id"`
+	RecordedAt      string `pulumi:"recordedAt"`
+	CertificationID string `pulumi:"certificationId"`
+	WeekVisits      int    `pulumi:"weekVisits"`
+	TotalVisits     int    `pulumi:"totalVisits"`
+	TotalMinutes    int    `pulumi:"totalMinutes"`
+	Facilities      int    `pulumi:"facilities"`
+	Version         int64  `pulumi:"version"`
+}
+
+// TherapyDogVisitState echoes the inputs next to the computed outputs
+type TherapyDogVisitState struct {
+	TherapyDogVisitArgs
+	TherapyDogVisitOutputs
+}
+
+// newTherapyDogVisitState copies the inputs into an otherwise empty state
+func newTherapyDogVisitState(input TherapyDogVisitArgs) TherapyDogVisitState {
+	return TherapyDogVisitState{TherapyDogVisitArgs: input}
+}
+
+func (s *TherapyDogVisitState) stamp(id, created string)   { s.ID, s.RecordedAt = id, created }
+func (s *TherapyDogVisitState) identity() (string, string) { return s.ID, s.RecordedAt }
+func (s *TherapyDogVisitState) setVersion(version int64)   { s.Version = version }
+func (s *TherapyDogVisitState) storedVersion() int64       { return s.Version }
+
+func (args *TherapyDogVisitArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Date, "YYYY-MM-DD")
+}
+
+func (state *TherapyDogVisitState) Annotate(a infer.Annotator) {
+	state.TherapyDogVisitArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the visit")
+	a.Describe(&state.RecordedAt, "When the visit was recorded")
+	a.Describe(&state.CertificationID, "The therapy certification the visit was made under")
+	a.Describe(&state.WeekVisits, "Visits the dog made in the visit's week, this one included")
+	a.Describe(&state.TotalVisits, "Therapy visits the dog has made up to and including this one")
+	a.Describe(&state.TotalMinutes, "Minutes the dog has spent on therapy visits up to and including this one")
+	a.Describe(&state.Facilities, "Distinct facilities the dog has visited up to and including this one")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
@@ -0,0 +1,82 @@
+package resources
+
+import (
+	"testing"
+	"time"
+)
+
+func therapyVisit(date, facility string, minutes int) TherapyDogVisitState {
+	var v TherapyDogVisitState
+	v.Date, v.Facility, v.DurationMinutes = date, facility, minutes
+	return v
+}
+
+func TestVisitsInWeek(t *testing.T) {
+	visits := []TherapyDogVisitState{
+		therapyVisit("2026-10-11", "Oak Ridge", 30), // Sunday of the week before
+		therapyVisit("2026-10-12", "Oak Ridge", 30), // Monday
+		therapyVisit("2026-10-14", "St. Mary's", 45),
+		therapyVisit("2026-10-18", "Oak Ridge", 30), // Sunday
+		therapyVisit("2026-10-19", "Oak Ridge", 30), // the next Monday
+	}
+	tests := []struct {
+		day  string
+		want int
+	}{
+		{day: "2026-10-12", want: 3},
+		{day: "2026-10-15", want: 3},
+		{day: "2026-10-18", want: 3},
+		{day: "2026-10-11", want: 1},
+		{day: "2026-10-25", want: 1},
+		{day: "2026-11-02", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.day, func(t *testing.T) {
+			day, _ := time.Parse(dateLayout, tt.day)
+			if got := visitsInWeek(visits, day); got != tt.want {
+				t.Errorf("visitsInWeek(%s) = %d, want %d", tt.day, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTherapyStats(t *testing.T) {
+	visits := []TherapyDogVisitState{
+		therapyVisit("2026-10-05", "Oak Ridge", 30),
+		therapyVisit("2026-10-07", "St. Mary's Hospital", 45),
+		therapyVisit("2026-10-13", "oak ridge ", 60),
+		therapyVisit("2026-10-20", "Lincoln Elementary", 30),
+	}
+	state := therapyVisit("2026-10-13", "oak ridge ", 60)
+	therapyStats(&state, visits)
+	if state.WeekVisits != 1 || state.TotalVisits != 3 || state.TotalMinutes != 135 || state.Facilities != 2 {
+		t.Errorf("stats = week %d, total %d, minutes %d, facilities %d; want 1, 3, 135, 2",
+			state.WeekVisits, state.TotalVisits, state.TotalMinutes, state.Facilities)
+	}
+}
+
+func TestTherapyCertification(t *testing.T) {
+	cert := func(id string, purpose CertificationPurpose, certified, expires string) ServiceDogCertificationState {
+		var c ServiceDogCertificationState
+		c.ID, c.Purpose, c.CertifiedAt, c.ExpiresOn = id, &purpose, certified, expires
+		return c
+	}
+	certs := []ServiceDogCertificationState{
+		cert("cert-service", PurposeService, "2026-01-10T09:00:00Z", "2027-01-09"),
+		cert("cert-therapy", PurposeTherapy, "2026-03-01T09:00:00Z", "2027-02-28"),
+	}
+	tests := []struct {
+		day  string
+		want string
+	}{
+		{day: "2026-06-01", want: "cert-therapy"},
+		{day: "2026-03-01", want: "cert-therapy"},
+		{day: "2026-02-01"},
+		{day: "2027-03-01"},
+	}
+	for _, tt := range tests {
+		if got := therapyCertification(certs, tt.day); got != tt.want {
+			t.Errorf("therapyCertification(%s) = %q, want %q", tt.day, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// ToyInventory Resource - the toys a dog owns. Toys wear out with play and,
+// for the ones that go along, walks; Read recomputes the wear and lists
+// what needs replacing.
+type ToyInventory struct{}
+
+//pets:state id=ID created=CreatedAt
+//pets:output ID string id Generated identifier of the inventory
+//pets:output CreatedAt string createdAt When the inventory was created
+//pets:output Wear []ToyWear wear How worn each toy is; recomputed on refresh
+//pets:output NeedsReplacement []string needsReplacement Names of the toys worn past replacing
+//pets:output ReorderList []ReorderItem reorderList Replacements to order, grouped by type and durability
+type ToyInventoryArgs struct {
+	DogID string `pulumi:"dogId" validate:"required"`
+	Toys  []Toy  `pulumi:"toys"`
+}
+
+// Toy is one toy in an inventory. PurchaseDate is YYYY-MM-DD.
+type Toy struct {
+	Name         string         `pulumi:"name" json:"name"`
+	Type         ToyType        `pulumi:"type" json:"type"`
+	Durability   *ToyDurability `pulumi:"durability,optional" json:"durability,omitempty"`
+	PurchaseDate string         `pulumi:"purchaseDate" json:"purchaseDate"`
+}
+
+// ToyWear is how worn a toy is
+type ToyWear struct {
+	Name             string  `pulumi:"name" json:"name"`
+	Type             ToyType `pulumi:"type" json:"type"`
+	WearPercent      int     `pulumi:"wearPercent" json:"wearPercent"`
+	NeedsReplacement bool    `pulumi:"needsReplacement" json:"needsReplacement"`
+}
+
+// ReorderItem is a line of the reorder list
+type ReorderItem struct {
+	Type       ToyType       `pulumi:"type" json:"type"`
+	Durability ToyDurability `pulumi:"durability" json:"durability"`
+	Quantity   int           `pulumi:"quantity" json:"quantity"`
+}
+
+const (
+	// replaceAtWear is the wear percentage a toy is replaced at
+	replaceAtWear = 80
+	// playMinutesPerEnergy is the daily play per point of breed energy
+	playMinutesPerEnergy = 10
+)
+
+// wearPerHour is the wear percentage an hour of use costs a toy of medium
+// durability
+var wearPerHour = map[ToyType]float64{
+	ToyBall:    0.5,
+	ToyRope:    0.8,
+	ToyChew:    1.2,
+	ToyPlush:   2,
+	ToySqueaky: 1.5,
+	ToyPuzzle:  0.3,
+}
+
+// walkUse is how much of each walk a toy of the type is played with
+var walkUse = map[ToyType]float64{
+	ToyBall: 1,
+	ToyRope: 0.5,
+}
+
+var durabilityFactor = map[ToyDurability]float64{
+	DurabilityLow:    2,
+	DurabilityMedium: 1,
+	DurabilityHigh:   0.5,
+}
+
+var toys = crudResource[ToyInventoryArgs, ToyInventoryState, *ToyInventoryState]{
+	kind:     stored[ToyInventoryState]("toys"),
+	prefix:   "toys",
+	slug:     func(input ToyInventoryArgs) string { return input.DogID },
+	newState: newToyInventoryState,
+	populate: func(ctx context.Context, state *ToyInventoryState, input ToyInventoryArgs) error {
+		return refreshToys(ctx, state)
+	},
+	carry: func(ctx context.Context, state *ToyInventoryState, oldState ToyInventoryState, now time.Time) error {
+		return refreshToys(ctx, state)
+	},
+	refresh: func(ctx context.Context, id string, state *ToyInventoryState) error {
+		return refreshToys(ctx, state)
+	},
+}
+
+func (ToyInventory) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (ToyInventoryArgs, []p.CheckFailure, error) {
+	args, failures, err := toys.check(newInputs)
+	return args, append(failures, checkToys(args.Toys)...), err
+}
+
+func (ToyInventory) Create(ctx context.Context, name string, input ToyInventoryArgs, preview bool) (string, ToyInventoryState, error) {
+	return toys.create(ctx, name, input, preview)
+}
+
+func (ToyInventory) Read(ctx context.Context, id string, inputs ToyInventoryArgs, state ToyInventoryState) (string, ToyInventoryArgs, ToyInventoryState, error) {
+	return toys.read(ctx, id, inputs, state)
+}
+
+func (ToyInventory) Update(ctx context.Context, id string, oldState ToyInventoryState, input ToyInventoryArgs, preview bool) (ToyInventoryState, error) {
+	return toys.update(ctx, id, oldState, input, preview)
+}
+
+func (ToyInventory) Delete(ctx context.Context, id string, state ToyInventoryState) error {
+	return toys.delete(ctx, id, state)
+}
+
+// checkToys reports malformed entries of the toys input
+func checkToys(list []Toy) []p.CheckFailure {
+	var failures []p.CheckFailure
+	fail := func(i int, field, reason string) {
+		failures = append(failures, p.CheckFailure{
+			Property: fmt.Sprintf("toys[%d].%s", i, field),
+			Reason:   reason,
+		})
+	}
+	names := map[string]bool{}
+	for i, toy := range list {
+		switch {
+		case strings.TrimSpace(toy.Name) == "":
+			fail(i, "name", "name is required")
+		case names[toy.Name]:
+			fail(i, "name", fmt.Sprintf("another toy is already named %q", toy.Name))
+		}
+		names[toy.Name] = true
+		if _, ok := wearPerHour[toy.Type]; !ok {
+			fail(i, "type", "type must be one of ball, rope, chew, plush, squeaky or puzzle")
+		}
+		if toy.Durability != nil {
+			if _, ok := durabilityFactor[*toy.Durability]; !ok {
+				fail(i, "durability", "durability must be one of low, medium or high")
+			}
+		}
+		if _, err := time.Parse(dateLayout, toy.PurchaseDate); err != nil {
+			fail(i, "purchaseDate", "purchaseDate must be a YYYY-MM-DD date")
+		}
+	}
+	return failures
+}
+
+// refreshToys recomputes the wear of every toy from the dog's walks and
+// its breed's appetite for play
+func refreshToys(ctx context.Context, state *ToyInventoryState) error {
+	dog, err := walkedDog(ctx, state.DogID)
+	if err != nil {
+		return err
+	}
+	walks, err := listForDog[DogWalkState](ctx, "walk", state.DogID)
+	if err != nil {
+		return err
+	}
+	state.Wear, state.NeedsReplacement, state.ReorderList = inventoryWear(state.Toys, walks, dog, registry.Now(ctx))
+	return nil
+}
+
+// inventoryWear grades every toy and lists the replacements to order. Toys
+// of the dog's favorite types are played with more than the rest.
+func inventoryWear(list []Toy, walks []DogWalkState, dog DogState, now time.Time) ([]ToyWear, []string, []ReorderItem) {
+	wear := make([]ToyWear, len(list))
+	var worn []string
+	orders := map[ReorderItem]int{}
+	for i, toy := range list {
+		play := float64(BreedCatalog[dog.Breed].EnergyLevel * playMinutesPerEnergy)
+		if dog.favoriteToy(toy.Type) {
+			play *= favoriteToyPlay
+		}
+		wear[i] = ToyWear{Name: toy.Name, Type: toy.Type, WearPercent: toyWear(toy, walks, play, now)}
+		if wear[i].WearPercent >= replaceAtWear {
+			wear[i].NeedsReplacement = true
+			worn = append(worn, toy.Name)
+			orders[ReorderItem{Type: toy.Type, Durability: toyDurability(toy)}]++
+		}
+	}
+
+	var reorder []ReorderItem
+	for item, quantity := range orders {
+		item.Quantity = quantity
+		reorder = append(reorder, item)
+	}
+	sort.Slice(reorder, func(i, j int) bool {
+		if reorder[i].Type != reorder[j].Type {
+			return reorder[i].Type < reorder[j].Type
+		}
+		return reorder[i].Durability < reorder[j].Durability
+	})
+	return wear, worn, reorder
+}
+
+// toyWear is a toy's wear percentage: the hours it has been played with
+// since it was bought, at play minutes a day and on walks, at its type's
+// wear rate scaled by its durability
+func toyWear(toy Toy, walks []DogWalkState, play float64, now time.Time) int {
+	bought, err := time.Parse(dateLayout, toy.PurchaseDate)
+	if err != nil || !now.After(bought) {
+		return 0
+	}
+	minutes := now.Sub(bought).Hours() / 24 * play
+	since := toy.PurchaseDate
+	for _, walk := range walks {
+		// Walk dates are timestamps, which order after their own day
+		if walk.Date >= since {
+			minutes += float64(walk.Duration) * walkUse[toy.Type]
+		}
+	}
+	percent := minutes / 60 * wearPerHour[toy.Type] * durabilityFactor[toyDurability(toy)]
+	return int(math.Min(100, math.Round(percent)))
+}
+
+func toyDurability(toy Toy) ToyDurability {
+	if toy.Durability == nil {
+		return DurabilityMedium
+	}
+	return *toy.Durability
+}
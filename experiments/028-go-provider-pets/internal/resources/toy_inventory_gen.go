@@ -0,0 +1,40 @@
+// Code generated by genstate from toy_inventory.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// ToyInventoryOutputs are computed by the provider; Check rejects them as inputs
+type ToyInventoryOutputs struct {
+	ID               string        `pulumi:"id"`
+	CreatedAt        string        `pulumi:"createdAt"`
+	Wear             []ToyWear     `pulumi:"wear"`
+	NeedsReplacement []string      `pulumi:"needsReplacement"`
+	ReorderList      []ReorderItem `pulumi:"reorderList"`
+	Version          int64         `pulumi:"version"`
+}
+
+// ToyInventoryState echoes the inputs next to the computed outputs
+type ToyInventoryState struct {
+	ToyInventoryArgs
+	ToyInventoryOutputs
+}
+
+// newToyInventoryState copies the inputs into an otherwise empty state
+func newToyInventoryState(input ToyInventoryArgs) ToyInventoryState {
+	return ToyInventoryState{ToyInventoryArgs: input}
+}
+
+func (s *ToyInventoryState) stamp(id, created string)   { s.ID, s.CreatedAt = id, created }
+func (s *ToyInventoryState) identity() (string, string) { return s.ID, s.CreatedAt }
+func (s *ToyInventoryState) setVersion(version int64)   { s.Version = version }
+func (s *ToyInventoryState) storedVersion() int64       { return s.Version }
+
+func (state *ToyInventoryState) Annotate(a infer.Annotator) {
+	a.Describe(&state.ID, "Generated identifier of the inventory")
+	a.Describe(&state.CreatedAt, "When the inventory was created")
+	a.Describe(&state.Wear, "How worn each toy is; recomputed on refresh")
+	a.Describe(&state.NeedsReplacement, "Names of the toys worn past replacing")
+	a.Describe(&state.ReorderList, "Replacements to order, grouped by type and durability")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
@@ -0,0 +1,121 @@
+package resources
+
+import (
+	"testing"
+	"time"
+)
+
+func durabilityPtr(d ToyDurability) *ToyDurability { return &d }
+
+func TestCheckToys(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []Toy
+		failed []string
+	}{
+		{
+			name:  "valid",
+			input: []Toy{{Name: "Red ball", Type: ToyBall, PurchaseDate: "2026-05-01"}, {Name: "Bunny", Type: ToyPlush, Durability: durabilityPtr(DurabilityLow), PurchaseDate: "2026-05-01"}},
+		},
+		{
+			name:   "duplicate name",
+			input:  []Toy{{Name: "Ball", Type: ToyBall, PurchaseDate: "2026-05-01"}, {Name: "Ball", Type: ToyBall, PurchaseDate: "2026-05-02"}},
+			failed: []string{"toys[1].name"},
+		},
+		{
+			name:   "unknown type and durability",
+			input:  []Toy{{Name: "Stick", Type: "stick", Durability: durabilityPtr("titanium"), PurchaseDate: "2026-05-01"}},
+			failed: []string{"toys[0].type", "toys[0].durability"},
+		},
+		{
+			name:   "malformed purchaseDate",
+			input:  []Toy{{Name: "Rope", Type: ToyRope, PurchaseDate: "last spring"}},
+			failed: []string{"toys[0].purchaseDate"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := checkToys(tt.input)
+			if len(failures) != len(tt.failed) {
+				t.Fatalf("failures = %+v, want %v", failures, tt.failed)
+			}
+			for i, f := range failures {
+				if f.Property != tt.failed[i] {
+					t.Errorf("failure %d on %q, want %q", i, f.Property, tt.failed[i])
+				}
+			}
+		})
+	}
+}
+
+func TestToyWear(t *testing.T) {
+	now := time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)
+	walk := func(date string, minutes int) DogWalkState {
+		var w DogWalkState
+		w.Date, w.Duration = date, minutes
+		return w
+	}
+	walks := []DogWalkState{walk("2026-06-01T09:00:00Z", 120), walk("2026-06-10T09:00:00Z", 30), walk("2026-06-12T18:00:00Z", 30)}
+
+	// A beagle (energy 4) plays 40 minutes a day
+	tests := []struct {
+		name string
+		toy  Toy
+		want int
+	}{
+		{name: "plush for a month", toy: Toy{Type: ToyPlush, PurchaseDate: "2026-05-16"}, want: 40},
+		{name: "plush for three months", toy: Toy{Type: ToyPlush, PurchaseDate: "2026-03-17"}, want: 100},
+		{name: "flimsy chew", toy: Toy{Type: ToyChew, Durability: durabilityPtr(DurabilityLow), PurchaseDate: "2026-04-16"}, want: 96},
+		{name: "sturdy ball goes on walks", toy: Toy{Type: ToyBall, Durability: durabilityPtr(DurabilityHigh), PurchaseDate: "2026-06-05"}, want: 2},
+		{name: "rope comes along half the time", toy: Toy{Type: ToyRope, PurchaseDate: "2026-06-01"}, want: 9},
+		{name: "not bought yet", toy: Toy{Type: ToyPuzzle, PurchaseDate: "2026-07-01"}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toyWear(tt.toy, walks, 40, now); got != tt.want {
+				t.Errorf("toyWear = %d%%, want %d%%", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInventoryWearReorders(t *testing.T) {
+	now := time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)
+	list := []Toy{
+		{Name: "Bunny", Type: ToyPlush, PurchaseDate: "2026-03-01"},
+		{Name: "Duck", Type: ToyPlush, PurchaseDate: "2026-03-10"},
+		{Name: "Bone", Type: ToyChew, Durability: durabilityPtr(DurabilityLow), PurchaseDate: "2026-03-01"},
+		{Name: "Ball", Type: ToyBall, PurchaseDate: "2026-06-01"},
+	}
+	var beagle DogState
+	beagle.Breed = Beagle
+	wear, worn, reorder := inventoryWear(list, nil, beagle, now)
+	if len(wear) != len(list) || wear[3].NeedsReplacement {
+		t.Fatalf("wear = %+v", wear)
+	}
+	if len(worn) != 3 || worn[0] != "Bunny" || worn[1] != "Duck" || worn[2] != "Bone" {
+		t.Errorf("needsReplacement = %v, want Bunny, Duck and Bone", worn)
+	}
+	want := []ReorderItem{
+		{Type: ToyChew, Durability: DurabilityLow, Quantity: 1},
+		{Type: ToyPlush, Durability: DurabilityMedium, Quantity: 2},
+	}
+	if len(reorder) != len(want) || reorder[0] != want[0] || reorder[1] != want[1] {
+		t.Errorf("reorderList = %+v, want %+v", reorder, want)
+	}
+}
+
+func TestInventoryWearFavorites(t *testing.T) {
+	now := time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)
+	list := []Toy{{Name: "Bunny", Type: ToyPlush, PurchaseDate: "2026-05-16"}, {Name: "Knot", Type: ToyRope, PurchaseDate: "2026-05-16"}}
+	var beagle DogState
+	beagle.Breed = Beagle
+	beagle.Preferences = &Preferences{ToyTypes: []ToyType{ToyPlush}}
+
+	// A month of 40 minutes a day wears a plush 40%, and half as much again
+	// as a favorite
+	wear, _, _ := inventoryWear(list, nil, beagle, now)
+	if wear[0].WearPercent != 60 || wear[1].WearPercent != 16 {
+		t.Errorf("wear = %+v, want the favorite plush at 60%% and the rope at 16%%", wear)
+	}
+}
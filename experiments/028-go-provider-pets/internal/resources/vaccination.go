@@ -0,0 +1,116 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// dateLayout is how vaccination and visit dates are written
+const dateLayout = "2006-01-02"
+
+// Vaccination is one dose of a vaccine, recorded on a Dog or given at a
+// VeterinaryVisit. Dates are YYYY-MM-DD; a dose without expiresOn never
+// lapses.
+type Vaccination struct {
+	Vaccine   string  `pulumi:"vaccine" json:"vaccine"`
+	DateGiven string  `pulumi:"dateGiven" json:"dateGiven"`
+	ExpiresOn *string `pulumi:"expiresOn,optional" json:"expiresOn,omitempty"`
+	LotNumber *string `pulumi:"lotNumber,optional" json:"lotNumber,omitempty"`
+}
+
+// checkVaccinations reports malformed entries of a vaccinations input
+func checkVaccinations(property string, vaccinations []Vaccination) []p.CheckFailure {
+	var failures []p.CheckFailure
+	fail := func(i int, field, reason string) {
+		failures = append(failures, p.CheckFailure{
+			Property: fmt.Sprintf("%s[%d].%s", property, i, field),
+			Reason:   reason,
+		})
+	}
+	for i, v := range vaccinations {
+		if strings.TrimSpace(v.Vaccine) == "" {
+			fail(i, "vaccine", "vaccine is required")
+		}
+		given, err := time.Parse(dateLayout, v.DateGiven)
+		if err != nil {
+			fail(i, "dateGiven", "dateGiven must be a YYYY-MM-DD date")
+			continue
+		}
+		if v.ExpiresOn == nil {
+			continue
+		}
+		expires, err := time.Parse(dateLayout, *v.ExpiresOn)
+		switch {
+		case err != nil:
+			fail(i, "expiresOn", "expiresOn must be a YYYY-MM-DD date")
+		case !expires.After(given):
+			fail(i, "expiresOn", "expiresOn must be after dateGiven")
+		}
+	}
+	return failures
+}
+
+// vaccinationCurrent reports whether every vaccine's latest dose is still
+// valid on the day of now. A dog with no recorded doses is not current.
+func vaccinationCurrent(vaccinations []Vaccination, now time.Time) bool {
+	latest := map[string]Vaccination{}
+	for _, v := range vaccinations {
+		key := strings.ToLower(strings.TrimSpace(v.Vaccine))
+		// Dates are YYYY-MM-DD, so they order as strings
+		if prev, ok := latest[key]; !ok || v.DateGiven > prev.DateGiven {
+			latest[key] = v
+		}
+	}
+	today := now.Format(dateLayout)
+	for _, v := range latest {
+		if v.ExpiresOn != nil && *v.ExpiresOn < today {
+			return false
+		}
+	}
+	return len(latest) > 0
+}
+
+// DogVaccinations is every dose recorded for a dog: those on the dog itself
+// and those given at its visits.
+func DogVaccinations(ctx context.Context, dogID string) ([]Vaccination, error) {
+	var dog DogState
+	if _, err := registry.Load(ctx, "dog", dogID, &dog); err != nil {
+		return nil, fmt.Errorf("dog %s: %w", dogID, err)
+	}
+	given, err := visitVaccinations(ctx, dogID)
+	if err != nil {
+		return nil, err
+	}
+	return append(dog.Vaccinations, given...), nil
+}
+
+// refreshVaccination recomputes a dog's vaccinationCurrent from its own
+// records and the vaccinations given at its visits.
+func refreshVaccination(ctx context.Context, state *DogState) error {
+	given, err := visitVaccinations(ctx, state.ID)
+	if err != nil {
+		return err
+	}
+	all := append(append([]Vaccination{}, state.Vaccinations...), given...)
+	state.VaccinationCurrent = vaccinationCurrent(all, registry.Now(ctx))
+	return nil
+}
+
+// visitVaccinations collects the vaccinations given at a dog's visits
+func visitVaccinations(ctx context.Context, dogID string) ([]Vaccination, error) {
+	visits, err := listForDog[VeterinaryVisitState](ctx, "visit", dogID)
+	if err != nil {
+		return nil, err
+	}
+	var given []Vaccination
+	for _, visit := range visits {
+		given = append(given, visit.Vaccinations...)
+	}
+	return given, nil
+}
@@ -0,0 +1,106 @@
+package resources
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckVaccinations(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []Vaccination
+		failed []string
+	}{
+		{
+			name:  "valid with and without expiry",
+			input: []Vaccination{{Vaccine: "rabies", DateGiven: "2025-03-01", ExpiresOn: stringPtr("2028-03-01")}, {Vaccine: "lepto", DateGiven: "2025-03-01"}},
+		},
+		{
+			name:   "missing vaccine",
+			input:  []Vaccination{{Vaccine: " ", DateGiven: "2025-03-01"}},
+			failed: []string{"vaccinations[0].vaccine"},
+		},
+		{
+			name:   "malformed dateGiven",
+			input:  []Vaccination{{Vaccine: "rabies", DateGiven: "03/01/2025", ExpiresOn: stringPtr("2028-03-01")}},
+			failed: []string{"vaccinations[0].dateGiven"},
+		},
+		{
+			name:   "malformed expiresOn",
+			input:  []Vaccination{{Vaccine: "rabies", DateGiven: "2025-03-01"}, {Vaccine: "dhpp", DateGiven: "2025-03-01", ExpiresOn: stringPtr("soon")}},
+			failed: []string{"vaccinations[1].expiresOn"},
+		},
+		{
+			name:   "expires before given",
+			input:  []Vaccination{{Vaccine: "rabies", DateGiven: "2025-03-01", ExpiresOn: stringPtr("2025-03-01")}},
+			failed: []string{"vaccinations[0].expiresOn"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := checkVaccinations("vaccinations", tt.input)
+			if len(failures) != len(tt.failed) {
+				t.Fatalf("failures = %+v, want %v", failures, tt.failed)
+			}
+			for i, f := range failures {
+				if f.Property != tt.failed[i] {
+					t.Errorf("failure %d on %q, want %q", i, f.Property, tt.failed[i])
+				}
+			}
+		})
+	}
+}
+
+func TestVaccinationCurrent(t *testing.T) {
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		input []Vaccination
+		want  bool
+	}{
+		{name: "no records", want: false},
+		{
+			name:  "unexpired",
+			input: []Vaccination{{Vaccine: "rabies", DateGiven: "2025-06-01", ExpiresOn: stringPtr("2028-06-01")}},
+			want:  true,
+		},
+		{
+			name:  "expires today",
+			input: []Vaccination{{Vaccine: "rabies", DateGiven: "2023-06-15", ExpiresOn: stringPtr("2026-06-15")}},
+			want:  true,
+		},
+		{
+			name:  "expired",
+			input: []Vaccination{{Vaccine: "rabies", DateGiven: "2023-06-01", ExpiresOn: stringPtr("2026-06-01")}},
+			want:  false,
+		},
+		{
+			name: "expired dose superseded by a booster",
+			input: []Vaccination{
+				{Vaccine: "Rabies", DateGiven: "2026-05-01", ExpiresOn: stringPtr("2029-05-01")},
+				{Vaccine: "rabies", DateGiven: "2023-05-01", ExpiresOn: stringPtr("2026-05-01")},
+			},
+			want: true,
+		},
+		{
+			name: "one of several lapsed",
+			input: []Vaccination{
+				{Vaccine: "rabies", DateGiven: "2025-06-01", ExpiresOn: stringPtr("2028-06-01")},
+				{Vaccine: "bordetella", DateGiven: "2025-01-01", ExpiresOn: stringPtr("2026-01-01")},
+			},
+			want: false,
+		},
+		{
+			name:  "no expiry never lapses",
+			input: []Vaccination{{Vaccine: "lyme", DateGiven: "2010-01-01"}},
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := vaccinationCurrent(tt.input, now); got != tt.want {
+				t.Errorf("vaccinationCurrent = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
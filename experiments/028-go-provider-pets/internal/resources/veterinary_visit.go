@@ -0,0 +1,215 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/gcal"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// VeterinaryVisit Resource
+type VeterinaryVisit struct{}
+
+//pets:state id=ID created=Date
+//pets:output ID string id Generated identifier of the visit
+//pets:output Date string date When the visit took place
+//pets:output Diagnosis string diagnosis The vet's findings
+//pets:output Medications []string medications Medications prescribed at the visit
+//pets:output NextVisit string nextVisit Date the next visit is due
+//pets:output BehaviorNote BehaviorNote behaviorNote The vet's observation from the visit; getBehaviorTimeline collects these
+//pets:output CalendarEventID string calendarEventId Google Calendar event for nextVisit, when the googleCalendar integration is configured
+//pets:output WellnessPlanID string wellnessPlanId The WellnessPlan whose allowance covered the visit, if any
+//pets:embed ApprovalState
+//pets:embed CostDisplay
+type VeterinaryVisitArgs struct {
+	DogID      string    `pulumi:"dogId" validate:"required"`
+	VisitType  VisitType `pulumi:"visitType" validate:"required,enum"` // Why the dog saw the vet
+	Symptoms   *string   `pulumi:"symptoms,optional"`
+	Treatment  *string   `pulumi:"treatment,optional"`
+	Cost       *float64  `pulumi:"cost,optional" validate:"min=0"`
+	VetName    string    `pulumi:"vetName" validate:"required"`
+	ClinicName string    `pulumi:"clinicName" validate:"required"`
+	FollowUp   *bool     `pulumi:"followUp,optional"`
+	// Doses given at the visit; they count towards the dog's vaccinationCurrent
+	Vaccinations []Vaccination `pulumi:"vaccinations,optional"`
+	ApprovalArgs
+}
+
+var visits = crudResource[VeterinaryVisitArgs, VeterinaryVisitState, *VeterinaryVisitState]{
+	kind:     stored[VeterinaryVisitState]("visit"),
+	prefix:   "vet",
+	slug:     func(input VeterinaryVisitArgs) string { return input.DogID },
+	newState: newVeterinaryVisitState,
+	populate: func(ctx context.Context, state *VeterinaryVisitState, input VeterinaryVisitArgs) error {
+		now := registry.Now(ctx)
+		var err error
+		state.Diagnosis, state.Medications, state.NextVisit, err = diagnoseVisit(input.VisitType, now)
+		if err != nil {
+			return err
+		}
+		state.BehaviorNote = visitNote(input, now)
+		if state.CalendarEventID, err = scheduleNextVisit(ctx, *state); err != nil {
+			return err
+		}
+		if state.WellnessPlanID, err = claimWellnessVisit(ctx, *state, now); err != nil {
+			return err
+		}
+		state.CostDisplay = showCosts(ctx, state.costs())
+
+		if err := reportProgress(ctx, state.ID, visitSteps[input.VisitType]); err != nil {
+			return err
+		}
+
+		state.ApprovalState, err = requestApproval(ctx, "visit", state.ID, input.ApprovalArgs)
+		return err
+	},
+	announce: func(ctx context.Context, state VeterinaryVisitState) {
+		dispatch(ctx, visitEvent(dogName(ctx, state.DogID), state))
+	},
+	refresh: func(ctx context.Context, id string, state *VeterinaryVisitState) error {
+		state.CostDisplay = showCosts(ctx, state.costs())
+		return refreshApproval(ctx, id, &state.ApprovalState)
+	},
+	related: []string{"approval"},
+}
+
+func (VeterinaryVisit) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (VeterinaryVisitArgs, []p.CheckFailure, error) {
+	args, failures, err := visits.check(newInputs)
+	return args, append(failures, checkVaccinations("vaccinations", args.Vaccinations)...), err
+}
+
+func (VeterinaryVisit) Create(ctx context.Context, name string, input VeterinaryVisitArgs, preview bool) (string, VeterinaryVisitState, error) {
+	return visits.create(ctx, name, input, preview)
+}
+
+func (VeterinaryVisit) Read(ctx context.Context, id string, inputs VeterinaryVisitArgs, state VeterinaryVisitState) (string, VeterinaryVisitArgs, VeterinaryVisitState, error) {
+	return visits.read(ctx, id, inputs, state)
+}
+
+// Delete takes the next visit off the calendar and gives back any wellness
+// allowance it used, along with the record
+func (VeterinaryVisit) Delete(ctx context.Context, id string, state VeterinaryVisitState) error {
+	if err := releaseWellnessVisit(ctx, state); err != nil {
+		return err
+	}
+	if state.CalendarEventID != "" {
+		if calendar := registry.Calendar(ctx); calendar != nil {
+			if err := calendar.Delete(ctx, state.CalendarEventID); err != nil {
+				return err
+			}
+		} else {
+			p.GetLogger(ctx).Warningf("googleCalendar is not configured, leaving calendar event %s in place", state.CalendarEventID)
+		}
+	}
+	return visits.delete(ctx, id, state)
+}
+
+// Procedures that take several steps report each one as it completes
+var visitSteps = map[VisitType][]string{
+	VisitEmergency: {
+		"triage completed",
+		"patient stabilized",
+		"treatment administered",
+		"observation period finished",
+	},
+	VisitSurgery: {
+		"pre-operative exam completed",
+		"anesthesia administered",
+		"procedure performed",
+		"recovery monitored",
+		"discharge instructions prepared",
+	},
+}
+
+// diagnoseVisit generates the outcome of a visit from its type. Check
+// rejects other types, so an unknown one here is a bug worth failing on.
+func diagnoseVisit(visitType VisitType, now time.Time) (diagnosis string, medications []string, nextVisit string, err error) {
+	var next time.Time
+	switch visitType {
+	case VisitCheckup:
+		diagnosis = "Healthy and happy! No concerns noted."
+		next = now.AddDate(1, 0, 0)
+	case VisitVaccination:
+		diagnosis = "Vaccination administered successfully."
+		medications = []string{"Annual vaccination booster"}
+		next = now.AddDate(1, 0, 0)
+	case VisitEmergency:
+		diagnosis = "Emergency condition treated and stabilized."
+		next = now.AddDate(0, 0, 7)
+	case VisitSurgery:
+		diagnosis = "Surgical procedure completed successfully."
+		medications = []string{"Pain medication", "Antibiotics"}
+		next = now.AddDate(0, 0, 14)
+	case VisitDental:
+		diagnosis = "Teeth cleaned and gums checked."
+		medications = []string{"Chlorhexidine oral rinse"}
+		next = now.AddDate(1, 0, 0)
+	case VisitFollowUp:
+		diagnosis = "Recovering as expected from the previous visit."
+		next = now.AddDate(0, 3, 0)
+	default:
+		return "", nil, "", fmt.Errorf("unknown visitType %q", visitType)
+	}
+	return diagnosis, medications, next.Format("2006-01-02"), nil
+}
+
+// scheduleNextVisit puts a visit's nextVisit on the Google Calendar and
+// returns the event's ID, or "" when the integration isn't configured
+func scheduleNextVisit(ctx context.Context, state VeterinaryVisitState) (string, error) {
+	calendar := registry.Calendar(ctx)
+	if calendar == nil {
+		return "", nil
+	}
+	event, err := nextVisitEvent(dogName(ctx, state.DogID), state)
+	if err != nil {
+		return "", err
+	}
+	return calendar.Insert(ctx, event)
+}
+
+// nextVisitEvent is the all-day calendar entry for a visit's nextVisit
+func nextVisitEvent(name string, state VeterinaryVisitState) (gcal.Event, error) {
+	day, err := time.Parse(dateLayout, state.NextVisit)
+	if err != nil {
+		return gcal.Event{}, fmt.Errorf("nextVisit: %w", err)
+	}
+	visited, _, _ := strings.Cut(state.Date, "T")
+	return gcal.Event{
+		Summary: fmt.Sprintf("%s: vet visit at %s", name, state.ClinicName),
+		Description: fmt.Sprintf("Follows the %s visit with %s on %s. %s",
+			state.VisitType, state.VetName, visited, state.Diagnosis),
+		Start: day,
+		End:   day,
+	}, nil
+}
+
+// visitNote records the visit in the dog's behavior timeline, flagged by
+// how serious the reason for it was
+func visitNote(input VeterinaryVisitArgs, now time.Time) BehaviorNote {
+	severity := Info
+	switch input.VisitType {
+	case VisitEmergency:
+		severity = Alert
+	case VisitSurgery:
+		severity = Concern
+	}
+	note := fmt.Sprintf("Visit with %s (%s)", input.VetName, input.VisitType)
+	if input.Symptoms != nil && *input.Symptoms != "" {
+		note += ": " + *input.Symptoms
+	}
+	return observed(now, Vet, severity, note)
+}
+
+// costs is the visit's cost, when it was given
+func (s VeterinaryVisitState) costs() map[string]float64 {
+	if s.Cost == nil {
+		return map[string]float64{}
+	}
+	return map[string]float64{"cost": *s.Cost}
+}
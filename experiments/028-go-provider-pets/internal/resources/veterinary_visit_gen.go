@@ -0,0 +1,54 @@
+// Code generated by genstate from veterinary_visit.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// VeterinaryVisitOutputs are computed by the provider; Check rejects them as inputs
+type VeterinaryVisitOutputs struct {
+	ID              string       `pulumi:"id"`
+	Date            string       `pulumi:"date"`
+	Diagnosis       string       `pulumi:"diagnosis"`
+	Medications     []string     `pulumi:"medications"`
+	NextVisit       string       `pulumi:"nextVisit"`
+	BehaviorNote    BehaviorNote `pulumi:"behaviorNote"`
+	CalendarEventID string       `pulumi:"calendarEventId"`
+	WellnessPlanID  string       `pulumi:"wellnessPlanId"`
+	Version         int64        `pulumi:"version"`
+	ApprovalState
+	CostDisplay
+}
+
+// VeterinaryVisitState echoes the inputs next to the computed outputs
+type VeterinaryVisitState struct {
+	VeterinaryVisitArgs
+	VeterinaryVisitOutputs
+}
+
+// newVeterinaryVisitState copies the inputs into an otherwise empty state
+func newVeterinaryVisitState(input VeterinaryVisitArgs) VeterinaryVisitState {
+	return VeterinaryVisitState{VeterinaryVisitArgs: input}
+}
+
+func (s *VeterinaryVisitState) stamp(id, created string)   { s.ID, s.Date = id, created }
+func (s *VeterinaryVisitState) identity() (string, string) { return s.ID, s.Date }
+func (s *VeterinaryVisitState) setVersion(version int64)   { s.Version = version }
+func (s *VeterinaryVisitState) storedVersion() int64       { return s.Version }
+
+func (args *VeterinaryVisitArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.VisitType, "Why the dog saw the vet")
+	a.Describe(&args.Vaccinations, "Doses given at the visit; they count towards the dog's vaccinationCurrent")
+}
+
+func (state *VeterinaryVisitState) Annotate(a infer.Annotator) {
+	state.VeterinaryVisitArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the visit")
+	a.Describe(&state.Date, "When the visit took place")
+	a.Describe(&state.Diagnosis, "The vet's findings")
+	a.Describe(&state.Medications, "Medications prescribed at the visit")
+	a.Describe(&state.NextVisit, "Date the next visit is due")
+	a.Describe(&state.BehaviorNote, "The vet's observation from the visit; getBehaviorTimeline collects these")
+	a.Describe(&state.CalendarEventID, "Google Calendar event for nextVisit, when the googleCalendar integration is configured")
+	a.Describe(&state.WellnessPlanID, "The WellnessPlan whose allowance covered the visit, if any")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
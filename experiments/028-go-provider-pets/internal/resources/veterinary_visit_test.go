@@ -0,0 +1,125 @@
+package resources
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiagnoseVisit(t *testing.T) {
+	now := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		visitType       VisitType
+		wantDiagnosis   string
+		wantMedications []string
+		wantNextVisit   string
+	}{
+		{
+			visitType:     "checkup",
+			wantDiagnosis: "Healthy and happy! No concerns noted.",
+			wantNextVisit: "2025-03-05",
+		},
+		{
+			visitType:       "vaccination",
+			wantDiagnosis:   "Vaccination administered successfully.",
+			wantMedications: []string{"Annual vaccination booster"},
+			wantNextVisit:   "2025-03-05",
+		},
+		{
+			visitType:     "emergency",
+			wantDiagnosis: "Emergency condition treated and stabilized.",
+			wantNextVisit: "2024-03-12",
+		},
+		{
+			visitType:       "surgery",
+			wantDiagnosis:   "Surgical procedure completed successfully.",
+			wantMedications: []string{"Pain medication", "Antibiotics"},
+			wantNextVisit:   "2024-03-19",
+		},
+		{
+			visitType:       "dental",
+			wantDiagnosis:   "Teeth cleaned and gums checked.",
+			wantMedications: []string{"Chlorhexidine oral rinse"},
+			wantNextVisit:   "2025-03-05",
+		},
+		{
+			visitType:     "followup",
+			wantDiagnosis: "Recovering as expected from the previous visit.",
+			wantNextVisit: "2024-06-05",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.visitType), func(t *testing.T) {
+			diagnosis, medications, nextVisit, err := diagnoseVisit(tt.visitType, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diagnosis != tt.wantDiagnosis {
+				t.Errorf("diagnosis = %q, want %q", diagnosis, tt.wantDiagnosis)
+			}
+			if strings.Join(medications, "|") != strings.Join(tt.wantMedications, "|") {
+				t.Errorf("medications = %q, want %q", medications, tt.wantMedications)
+			}
+			if nextVisit != tt.wantNextVisit {
+				t.Errorf("next visit = %q, want %q", nextVisit, tt.wantNextVisit)
+			}
+		})
+	}
+}
+
+func TestDiagnoseVisitRejectsUnknownType(t *testing.T) {
+	if _, _, _, err := diagnoseVisit("grooming", time.Now()); err == nil {
+		t.Error("diagnoseVisit accepted an unknown visit type")
+	}
+}
+
+func TestVisitStepsOnlyForMultiStepProcedures(t *testing.T) {
+	for _, visitType := range []VisitType{VisitCheckup, VisitVaccination, VisitDental, VisitFollowUp} {
+		if steps := visitSteps[visitType]; len(steps) != 0 {
+			t.Errorf("%s reports %d steps, want none", visitType, len(steps))
+		}
+	}
+	for _, visitType := range []VisitType{VisitEmergency, VisitSurgery} {
+		if steps := visitSteps[visitType]; len(steps) == 0 {
+			t.Errorf("%s reports no steps", visitType)
+		}
+	}
+}
+
+func TestVeterinaryVisitCreatePreview(t *testing.T) {
+	input := VeterinaryVisitArgs{DogID: "dog-rex-1", VisitType: "surgery", VetName: "Dr. Paws", ClinicName: "Happy Tails"}
+	id, state, err := VeterinaryVisit{}.Create(context.Background(), "spay", input, true)
+	if err != nil {
+		t.Fatalf("preview create: %v", err)
+	}
+	if id != "spay" {
+		t.Errorf("id = %q, want the logical name", id)
+	}
+	if state.Diagnosis != "" {
+		t.Errorf("preview diagnosed %q, want it left unknown", state.Diagnosis)
+	}
+}
+
+func TestNextVisitEvent(t *testing.T) {
+	var visit VeterinaryVisitState
+	visit.VisitType, visit.VetName, visit.ClinicName = VisitSurgery, "Dr. Smith", "Happy Paws"
+	visit.Date, visit.NextVisit, visit.Diagnosis = "2026-06-01T10:00:00Z", "2026-06-15", "Surgical procedure completed successfully."
+
+	event, err := nextVisitEvent("Rex", visit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Summary != "Rex: vet visit at Happy Paws" || !event.Start.Equal(event.End) || event.Start.Format(dateLayout) != "2026-06-15" {
+		t.Errorf("event = %+v", event)
+	}
+	if want := "Follows the surgery visit with Dr. Smith on 2026-06-01. Surgical procedure completed successfully."; event.Description != want {
+		t.Errorf("description = %q, want %q", event.Description, want)
+	}
+
+	visit.NextVisit = ""
+	if _, err := nextVisitEvent("Rex", visit); err == nil {
+		t.Errorf("a visit without nextVisit made an event")
+	}
+}
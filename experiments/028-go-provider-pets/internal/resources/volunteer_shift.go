@@ -0,0 +1,266 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// VolunteerShift Resource - a volunteer's stint at a shelter in one role.
+// A volunteer can't be booked on two shifts at once, at any shelter.
+// Whenever anyone is on shift at a shelter, every role needs its minimum
+// number of volunteers, from the provider's volunteerMinimums config;
+// each shift shows whether its role falls short of that at some point
+// during it, and exportVolunteerSchedule lists the gaps for a week.
+type VolunteerShift struct{}
+
+//pets:state id=ID created=BookedAt
+//pets:output ID string id Generated identifier of the shift
+//pets:output BookedAt string bookedAt When the shift was booked
+//pets:output Hours float64 hours Length of the shift
+//pets:output Understaffed bool understaffed Whether the shift's role is below its minimum at some point during the shift; recomputed on refresh
+type VolunteerShiftArgs struct {
+	ShelterID        string        `pulumi:"shelterId" validate:"required"`
+	VolunteerContact string        `pulumi:"volunteerContact" provider:"secret" validate:"required"`                // Identifies the volunteer across shifts
+	Start            string        `pulumi:"start" validate:"required"`                                             // RFC 3339 timestamp
+	End              string        `pulumi:"end" validate:"required"`                                               // RFC 3339 timestamp
+	Role             VolunteerRole `pulumi:"role" validate:"oneof=kennel|dog-walker|adoption-counselor|front-desk"` // One of kennel, dog-walker, adoption-counselor or front-desk
+}
+
+// StaffingGap is a stretch of time a role has fewer volunteers than it
+// needs
+type StaffingGap struct {
+	Role      VolunteerRole `pulumi:"role"`
+	Start     string        `pulumi:"start"`
+	End       string        `pulumi:"end"`
+	Scheduled int           `pulumi:"scheduled"`
+	Minimum   int           `pulumi:"minimum"`
+}
+
+// maxShiftHours is the longest shift a volunteer can be booked on
+const maxShiftHours = 12
+
+var defaultVolunteerMinimums = map[VolunteerRole]int{
+	RoleKennel:            2,
+	RoleDogWalker:         1,
+	RoleAdoptionCounselor: 1,
+	RoleFrontDesk:         1,
+}
+
+var volunteerShifts = crudResource[VolunteerShiftArgs, VolunteerShiftState, *VolunteerShiftState]{
+	kind:     stored[VolunteerShiftState]("volunteer-shift"),
+	prefix:   "shift",
+	slug:     func(input VolunteerShiftArgs) string { return input.ShelterID },
+	newState: newVolunteerShiftState,
+	populate: func(ctx context.Context, state *VolunteerShiftState, input VolunteerShiftArgs) error {
+		if err := requireShelter(ctx, input.ShelterID); err != nil {
+			return err
+		}
+		return bookShift(ctx, state)
+	},
+	carry: func(ctx context.Context, state *VolunteerShiftState, oldState VolunteerShiftState, now time.Time) error {
+		return bookShift(ctx, state)
+	},
+	refresh: func(ctx context.Context, id string, state *VolunteerShiftState) error {
+		return staffShift(ctx, state)
+	},
+}
+
+func (VolunteerShift) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (VolunteerShiftArgs, []p.CheckFailure, error) {
+	args, failures, err := volunteerShifts.check(newInputs)
+	return args, append(failures, checkShift(args)...), err
+}
+
+func (VolunteerShift) Create(ctx context.Context, name string, input VolunteerShiftArgs, preview bool) (string, VolunteerShiftState, error) {
+	return volunteerShifts.create(ctx, name, input, preview)
+}
+
+func (VolunteerShift) Read(ctx context.Context, id string, inputs VolunteerShiftArgs, state VolunteerShiftState) (string, VolunteerShiftArgs, VolunteerShiftState, error) {
+	return volunteerShifts.read(ctx, id, inputs, state)
+}
+
+func (VolunteerShift) Update(ctx context.Context, id string, oldState VolunteerShiftState, input VolunteerShiftArgs, preview bool) (VolunteerShiftState, error) {
+	return volunteerShifts.update(ctx, id, oldState, input, preview)
+}
+
+func (VolunteerShift) Delete(ctx context.Context, id string, state VolunteerShiftState) error {
+	return volunteerShifts.delete(ctx, id, state)
+}
+
+func checkShift(args VolunteerShiftArgs) []p.CheckFailure {
+	var failures []p.CheckFailure
+	start, serr := time.Parse(time.RFC3339, args.Start)
+	if args.Start != "" && serr != nil {
+		failures = append(failures, p.CheckFailure{Property: "start", Reason: "start must be an RFC 3339 timestamp"})
+	}
+	end, eerr := time.Parse(time.RFC3339, args.End)
+	if args.End != "" && eerr != nil {
+		failures = append(failures, p.CheckFailure{Property: "end", Reason: "end must be an RFC 3339 timestamp"})
+	}
+	if serr == nil && eerr == nil {
+		switch {
+		case !end.After(start):
+			failures = append(failures, p.CheckFailure{Property: "end", Reason: "end must be after start"})
+		case end.Sub(start) > maxShiftHours*time.Hour:
+			failures = append(failures, p.CheckFailure{Property: "end", Reason: fmt.Sprintf("shifts last at most %d hours", maxShiftHours)})
+		}
+	}
+	return failures
+}
+
+// span is the shift's start and end
+func (s VolunteerShiftArgs) span() (time.Time, time.Time) {
+	start, _ := time.Parse(time.RFC3339, s.Start)
+	end, _ := time.Parse(time.RFC3339, s.End)
+	return start, end
+}
+
+// bookShift refuses a shift that overlaps another of the volunteer's, then
+// works out its staffing
+func bookShift(ctx context.Context, state *VolunteerShiftState) error {
+	booked, err := listRecords[VolunteerShiftState](ctx, backend.Query{
+		Kind:  "volunteer-shift",
+		Where: []backend.Condition{{Field: "VolunteerContact", Op: "eq", Value: state.VolunteerContact}},
+	})
+	if err != nil {
+		return err
+	}
+	if clash := overlappingShift(booked, *state); clash != nil {
+		return fmt.Errorf("the volunteer is already on a %s shift at %s from %s to %s (%s)", clash.Role, clash.ShelterID, clash.Start, clash.End, clash.ID)
+	}
+	start, end := state.span()
+	state.Hours = round2(end.Sub(start).Hours())
+	return staffShift(ctx, state)
+}
+
+// overlappingShift is one of the booked shifts that overlaps shift, other
+// than shift itself
+func overlappingShift(booked []VolunteerShiftState, shift VolunteerShiftState) *VolunteerShiftState {
+	start, end := shift.span()
+	for i, other := range booked {
+		otherStart, otherEnd := other.span()
+		if other.ID != shift.ID && start.Before(otherEnd) && otherStart.Before(end) {
+			return &booked[i]
+		}
+	}
+	return nil
+}
+
+// staffShift works out whether the shift's role is short during it
+func staffShift(ctx context.Context, state *VolunteerShiftState) error {
+	shifts, err := ShelterShifts(ctx, state.ShelterID)
+	if err != nil {
+		return err
+	}
+	// The shift being booked isn't saved yet
+	shifts = append(removeShift(shifts, state.ID), *state)
+	start, end := state.span()
+	minimums := VolunteerMinimums(ctx)
+	state.Understaffed = false
+	for _, gap := range StaffingGaps(shifts, minimums, start, end) {
+		if gap.Role == state.Role {
+			state.Understaffed = true
+		}
+	}
+	return nil
+}
+
+func removeShift(shifts []VolunteerShiftState, id string) []VolunteerShiftState {
+	kept := shifts[:0]
+	for _, shift := range shifts {
+		if shift.ID != id {
+			kept = append(kept, shift)
+		}
+	}
+	return kept
+}
+
+// ShelterShifts loads every shift booked at the shelter
+func ShelterShifts(ctx context.Context, shelterID string) ([]VolunteerShiftState, error) {
+	return listRecords[VolunteerShiftState](ctx, backend.Query{
+		Kind:  "volunteer-shift",
+		Where: []backend.Condition{{Field: "ShelterID", Op: "eq", Value: shelterID}},
+	})
+}
+
+// VolunteerMinimums is how many volunteers each role needs, with the
+// provider's volunteerMinimums config over the defaults
+func VolunteerMinimums(ctx context.Context) map[VolunteerRole]int {
+	minimums := map[VolunteerRole]int{}
+	for role, minimum := range defaultVolunteerMinimums {
+		minimums[role] = minimum
+	}
+	for role, minimum := range registry.VolunteerMinimums(ctx) {
+		minimums[VolunteerRole(role)] = minimum
+	}
+	return minimums
+}
+
+// StaffingGaps finds when, between from and to, a role has fewer
+// volunteers than its minimum while anyone is on shift at all. Stretches
+// with the same shortfall are merged.
+func StaffingGaps(shifts []VolunteerShiftState, minimums map[VolunteerRole]int, from, to time.Time) []StaffingGap {
+	bounds := []time.Time{from, to}
+	for _, shift := range shifts {
+		start, end := shift.span()
+		for _, t := range []time.Time{start, end} {
+			if t.After(from) && t.Before(to) {
+				bounds = append(bounds, t)
+			}
+		}
+	}
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i].Before(bounds[j]) })
+
+	roles := make([]VolunteerRole, 0, len(minimums))
+	for role := range minimums {
+		roles = append(roles, role)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i] < roles[j] })
+
+	var gaps []StaffingGap
+	open := map[VolunteerRole]*StaffingGap{}
+	for i := 0; i+1 < len(bounds); i++ {
+		at, next := bounds[i], bounds[i+1]
+		if !at.Before(next) {
+			continue
+		}
+		staffed := map[VolunteerRole]int{}
+		anyone := false
+		for _, shift := range shifts {
+			start, end := shift.span()
+			if !at.Before(start) && at.Before(end) {
+				staffed[shift.Role]++
+				anyone = true
+			}
+		}
+		for _, role := range roles {
+			short := anyone && staffed[role] < minimums[role]
+			gap := open[role]
+			switch {
+			case short && gap != nil && gap.Scheduled == staffed[role] && gap.End == at.UTC().Format(time.RFC3339):
+				gap.End = next.UTC().Format(time.RFC3339)
+				continue
+			case gap != nil:
+				gaps = append(gaps, *gap)
+				delete(open, role)
+			}
+			if short {
+				open[role] = &StaffingGap{Role: role, Start: at.UTC().Format(time.RFC3339), End: next.UTC().Format(time.RFC3339), Scheduled: staffed[role], Minimum: minimums[role]}
+			}
+		}
+	}
+	for _, role := range roles {
+		if gap := open[role]; gap != nil {
+			gaps = append(gaps, *gap)
+		}
+	}
+	sort.SliceStable(gaps, func(i, j int) bool { return gaps[i].Start < gaps[j].Start })
+	return gaps
+}
@@ -0,0 +1,46 @@
+// Code generated by genstate from volunteer_shift.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// VolunteerShiftOutputs are computed by the provider; Check rejects them as inputs
+type VolunteerShiftOutputs struct {
+	ID           string  `pulumi:"id"`
+	BookedAt     string  `pulumi:"bookedAt"`
+	Hours        float64 `pulumi:"hours"`
+	Understaffed bool    `pulumi:"understaffed"`
+	Version      int64   `pulumi:"version"`
+}
+
+// VolunteerShiftState echoes the inputs next to the computed outputs
+type VolunteerShiftState struct {
+	VolunteerShiftArgs
+	VolunteerShiftOutputs
+}
+
+// newVolunteerShiftState copies the inputs into an otherwise empty state
+func newVolunteerShiftState(input VolunteerShiftArgs) VolunteerShiftState {
+	return VolunteerShiftState{VolunteerShiftArgs: input}
+}
+
+func (s *VolunteerShiftState) stamp(id, created string)   { s.ID, s.BookedAt = id, created }
+func (s *VolunteerShiftState) identity() (string, string) { return s.ID, s.BookedAt }
+func (s *VolunteerShiftState) setVersion(version int64)   { s.Version = version }
+func (s *VolunteerShiftState) storedVersion() int64       { return s.Version }
+
+func (args *VolunteerShiftArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.VolunteerContact, "Identifies the volunteer across shifts")
+	a.Describe(&args.Start, "RFC 3339 timestamp")
+	a.Describe(&args.End, "RFC 3339 timestamp")
+	a.Describe(&args.Role, "One of kennel, dog-walker, adoption-counselor or front-desk")
+}
+
+func (state *VolunteerShiftState) Annotate(a infer.Annotator) {
+	state.VolunteerShiftArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the shift")
+	a.Describe(&state.BookedAt, "When the shift was booked")
+	a.Describe(&state.Hours, "Length of the shift")
+	a.Describe(&state.Understaffed, "Whether the shift's role is below its minimum at some point during the shift; recomputed on refresh")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
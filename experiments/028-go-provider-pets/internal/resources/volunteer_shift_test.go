@@ -0,0 +1,101 @@
+package resources
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func volunteerShift(id string, role VolunteerRole, start, end string) VolunteerShiftState {
+	var s VolunteerShiftState
+	s.ID, s.Role, s.Start, s.End = id, role, start, end
+	return s
+}
+
+func TestCheckShift(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end string
+		failed     []string
+	}{
+		{name: "morning", start: "2026-10-17T08:00:00Z", end: "2026-10-17T12:00:00Z"},
+		{name: "with an offset", start: "2026-10-17T08:00:00-05:00", end: "2026-10-17T14:00:00Z"},
+		{name: "backwards", start: "2026-10-17T12:00:00Z", end: "2026-10-17T08:00:00Z", failed: []string{"end"}},
+		{name: "too long", start: "2026-10-17T06:00:00Z", end: "2026-10-17T19:00:00Z", failed: []string{"end"}},
+		{name: "not timestamps", start: "8am", end: "noon", failed: []string{"start", "end"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := checkShift(VolunteerShiftArgs{Start: tt.start, End: tt.end})
+			var got []string
+			for _, f := range failures {
+				got = append(got, f.Property)
+			}
+			if !reflect.DeepEqual(got, tt.failed) {
+				t.Errorf("failures on %v, want %v", got, tt.failed)
+			}
+		})
+	}
+}
+
+func TestOverlappingShift(t *testing.T) {
+	booked := []VolunteerShiftState{
+		volunteerShift("shift-a", RoleKennel, "2026-10-17T08:00:00Z", "2026-10-17T12:00:00Z"),
+		volunteerShift("shift-b", RoleFrontDesk, "2026-10-17T14:00:00Z", "2026-10-17T16:00:00Z"),
+	}
+	tests := []struct {
+		name       string
+		shift      VolunteerShiftState
+		overlapped string
+	}{
+		{name: "straight after", shift: volunteerShift("new", RoleKennel, "2026-10-17T12:00:00Z", "2026-10-17T14:00:00Z")},
+		{name: "overlaps the morning", shift: volunteerShift("new", RoleKennel, "2026-10-17T11:00:00Z", "2026-10-17T13:00:00Z"), overlapped: "shift-a"},
+		{name: "inside the afternoon", shift: volunteerShift("new", RoleKennel, "2026-10-17T14:30:00Z", "2026-10-17T15:00:00Z"), overlapped: "shift-b"},
+		{name: "moving itself", shift: volunteerShift("shift-a", RoleKennel, "2026-10-17T09:00:00Z", "2026-10-17T13:00:00Z")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if clash := overlappingShift(booked, tt.shift); clash != nil {
+				got = clash.ID
+			}
+			if got != tt.overlapped {
+				t.Errorf("overlaps %q, want %q", got, tt.overlapped)
+			}
+		})
+	}
+}
+
+func TestStaffingGaps(t *testing.T) {
+	at := func(s string) time.Time {
+		t, _ := time.Parse(time.RFC3339, s)
+		return t
+	}
+	shifts := []VolunteerShiftState{
+		volunteerShift("k1", RoleKennel, "2026-10-17T08:00:00Z", "2026-10-17T16:00:00Z"),
+		volunteerShift("k2", RoleKennel, "2026-10-17T10:00:00Z", "2026-10-17T14:00:00Z"),
+		volunteerShift("f1", RoleFrontDesk, "2026-10-17T08:00:00Z", "2026-10-17T12:00:00Z"),
+		volunteerShift("f2", RoleFrontDesk, "2026-10-17T12:00:00Z", "2026-10-17T16:00:00Z"),
+	}
+	minimums := map[VolunteerRole]int{RoleKennel: 2, RoleFrontDesk: 1}
+
+	got := StaffingGaps(shifts, minimums, at("2026-10-17T00:00:00Z"), at("2026-10-18T00:00:00Z"))
+	want := []StaffingGap{
+		{Role: RoleKennel, Start: "2026-10-17T08:00:00Z", End: "2026-10-17T10:00:00Z", Scheduled: 1, Minimum: 2},
+		{Role: RoleKennel, Start: "2026-10-17T14:00:00Z", End: "2026-10-17T16:00:00Z", Scheduled: 1, Minimum: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("gaps = %+v, want %+v", got, want)
+	}
+
+	// Only the part of the day asked about
+	got = StaffingGaps(shifts, minimums, at("2026-10-17T09:00:00Z"), at("2026-10-17T13:00:00Z"))
+	if len(got) != 1 || got[0].Start != "2026-10-17T09:00:00Z" || got[0].End != "2026-10-17T10:00:00Z" {
+		t.Errorf("gaps in the window = %+v", got)
+	}
+
+	// An empty shelter isn't short of anyone
+	if got := StaffingGaps(nil, minimums, at("2026-10-17T00:00:00Z"), at("2026-10-18T00:00:00Z")); len(got) != 0 {
+		t.Errorf("gaps with nobody booked = %+v", got)
+	}
+}
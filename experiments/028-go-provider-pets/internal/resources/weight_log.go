@@ -0,0 +1,126 @@
+package resources
+
+import (
+	"context"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// kgPerPound converts the Dog resource's pounds to kilograms
+const kgPerPound = 0.45359237
+
+// WeightLog Resource - one weigh-in of a dog. The entries form the dog's
+// weight history, and the latest one is its currentWeight.
+type WeightLog struct{}
+
+//pets:state id=ID created=RecordedAt
+//pets:output ID string id Generated identifier of the weigh-in
+//pets:output RecordedAt string recordedAt When the weigh-in was recorded
+//pets:output MeasuredOn string measuredOn Day the dog was weighed: date, or the day it was recorded
+//pets:output Kilograms float64 kilograms The weight in kilograms
+//pets:output Pounds float64 pounds The weight in pounds
+type WeightLogArgs struct {
+	DogID     string   `pulumi:"dogId" validate:"required"`
+	Date      *string  `pulumi:"date,optional"`                              // Day the dog was weighed as YYYY-MM-DD; defaults to today
+	WeightKg  *float64 `pulumi:"weightKg,optional" validate:"gt=0,max=160"`  // Weight in kilograms; give this or weightLbs
+	WeightLbs *float64 `pulumi:"weightLbs,optional" validate:"gt=0,max=350"` // Weight in pounds; give this or weightKg
+	Notes     *string  `pulumi:"notes,optional"`
+}
+
+var weights = crudResource[WeightLogArgs, WeightLogState, *WeightLogState]{
+	kind:     stored[WeightLogState]("weight"),
+	prefix:   "weight",
+	slug:     func(input WeightLogArgs) string { return input.DogID },
+	newState: newWeightLogState,
+	populate: func(ctx context.Context, state *WeightLogState, input WeightLogArgs) error {
+		if _, err := walkedDog(ctx, input.DogID); err != nil {
+			return err
+		}
+		state.MeasuredOn = registry.Now(ctx).Format(dateLayout)
+		if input.Date != nil {
+			state.MeasuredOn = *input.Date
+		}
+		state.Kilograms, state.Pounds = weighIn(input)
+		return nil
+	},
+}
+
+func (WeightLog) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (WeightLogArgs, []p.CheckFailure, error) {
+	args, failures, err := weights.check(newInputs)
+	return args, append(failures, checkWeighIn(args, registry.Now(ctx))...), err
+}
+
+func (WeightLog) Create(ctx context.Context, name string, input WeightLogArgs, preview bool) (string, WeightLogState, error) {
+	return weights.create(ctx, name, input, preview)
+}
+
+func (WeightLog) Read(ctx context.Context, id string, inputs WeightLogArgs, state WeightLogState) (string, WeightLogArgs, WeightLogState, error) {
+	return weights.read(ctx, id, inputs, state)
+}
+
+func (WeightLog) Delete(ctx context.Context, id string, state WeightLogState) error {
+	return weights.delete(ctx, id, state)
+}
+
+// checkWeighIn requires exactly one of the weight units and a well-formed
+// date that isn't in the future
+func checkWeighIn(args WeightLogArgs, now time.Time) []p.CheckFailure {
+	var failures []p.CheckFailure
+	if (args.WeightKg == nil) == (args.WeightLbs == nil) {
+		failures = append(failures, p.CheckFailure{Property: "weightKg", Reason: "exactly one of weightKg or weightLbs is required"})
+	}
+	if args.Date != nil {
+		day, err := time.Parse(dateLayout, *args.Date)
+		switch {
+		case err != nil:
+			failures = append(failures, p.CheckFailure{Property: "date", Reason: "date must be a YYYY-MM-DD date"})
+		case day.After(now):
+			failures = append(failures, p.CheckFailure{Property: "date", Reason: "date must not be in the future"})
+		}
+	}
+	return failures
+}
+
+// weighIn is the weight of a log entry in both units
+func weighIn(input WeightLogArgs) (kg, lbs float64) {
+	if input.WeightKg != nil {
+		return round2(*input.WeightKg), round2(*input.WeightKg / kgPerPound)
+	}
+	if input.WeightLbs != nil {
+		return round2(*input.WeightLbs * kgPerPound), round2(*input.WeightLbs)
+	}
+	return 0, 0
+}
+
+// latestWeighIn is the entry measured most recently, with ties going to the
+// one recorded last
+func latestWeighIn(logs []WeightLogState) (WeightLogState, bool) {
+	var latest WeightLogState
+	for i, entry := range logs {
+		// Both are formatted dates and timestamps, so they order as strings
+		if i == 0 || entry.MeasuredOn > latest.MeasuredOn ||
+			(entry.MeasuredOn == latest.MeasuredOn && entry.RecordedAt > latest.RecordedAt) {
+			latest = entry
+		}
+	}
+	return latest, len(logs) > 0
+}
+
+// refreshWeight sets currentWeight from the dog's latest weigh-in, falling
+// back to the weight input for dogs that have never been weighed
+func refreshWeight(ctx context.Context, state *DogState) error {
+	logs, err := listForDog[WeightLogState](ctx, "weight", state.ID)
+	if err != nil {
+		return err
+	}
+	if latest, ok := latestWeighIn(logs); ok {
+		state.CurrentWeight = latest.Pounds
+	} else if state.Weight != nil {
+		state.CurrentWeight = *state.Weight
+	}
+	return nil
+}
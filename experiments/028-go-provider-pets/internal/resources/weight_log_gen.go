@@ -0,0 +1,47 @@
+// Code generated by genstate from weight_log.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// WeightLogOutputs are computed by the provider; Check rejects them as inputs
+type WeightLogOutputs struct {
+	ID         string  `pulumi:"id"`
+	RecordedAt string  `pulumi:"recordedAt"`
+	MeasuredOn string  `pulumi:"measuredOn"`
+	Kilograms  float64 `pulumi:"kilograms"`
+	Pounds     float64 `pulumi:"pounds"`
+	Version    int64   `pulumi:"version"`
+}
+
+// WeightLogState echoes the inputs next to the computed outputs
+type WeightLogState struct {
+	WeightLogArgs
+	WeightLogOutputs
+}
+
+// newWeightLogState copies the inputs into an otherwise empty state
+func newWeightLogState(input WeightLogArgs) WeightLogState {
+	return WeightLogState{WeightLogArgs: input}
+}
+
+func (s *WeightLogState) stamp(id, created string)   { s.ID, s.RecordedAt = id, created }
+func (s *WeightLogState) identity() (string, string) { return s.ID, s.RecordedAt }
+func (s *WeightLogState) setVersion(version int64)   { s.Version = version }
+func (s *WeightLogState) storedVersion() int64       { return s.Version }
+
+func (args *WeightLogArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Date, "Day the dog was weighed as YYYY-MM-DD; defaults to today")
+	a.Describe(&args.WeightKg, "Weight in kilograms; give this or weightLbs")
+	a.Describe(&args.WeightLbs, "Weight in pounds; give this or weightKg")
+}
+
+func (state *WeightLogState) Annotate(a infer.Annotator) {
+	state.WeightLogArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the weigh-in")
+	a.Describe(&state.RecordedAt, "When the weigh-in was recorded")
+	a.Describe(&state.MeasuredOn, "Day the dog was weighed: date, or the day it was recorded")
+	a.Describe(&state.Kilograms, "The weight in kilograms")
+	a.Describe(&state.Pounds, "The weight in pounds")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
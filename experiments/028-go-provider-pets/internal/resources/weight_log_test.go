@@ -0,0 +1,93 @@
+package resources
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckWeighIn(t *testing.T) {
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		input  WeightLogArgs
+		failed []string
+	}{
+		{name: "pounds", input: WeightLogArgs{DogID: "dog-rex", WeightLbs: floatPtr(24)}},
+		{name: "kilograms on a date", input: WeightLogArgs{DogID: "dog-rex", WeightKg: floatPtr(11), Date: stringPtr("2026-06-15")}},
+		{name: "no weight", input: WeightLogArgs{DogID: "dog-rex"}, failed: []string{"weightKg"}},
+		{name: "both units", input: WeightLogArgs{DogID: "dog-rex", WeightKg: floatPtr(11), WeightLbs: floatPtr(24)}, failed: []string{"weightKg"}},
+		{name: "malformed date", input: WeightLogArgs{DogID: "dog-rex", WeightLbs: floatPtr(24), Date: stringPtr("June 1")}, failed: []string{"date"}},
+		{name: "future date", input: WeightLogArgs{DogID: "dog-rex", WeightLbs: floatPtr(24), Date: stringPtr("2026-06-16")}, failed: []string{"date"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := checkWeighIn(tt.input, now)
+			if len(failures) != len(tt.failed) {
+				t.Fatalf("failures = %+v, want %v", failures, tt.failed)
+			}
+			for i, f := range failures {
+				if f.Property != tt.failed[i] {
+					t.Errorf("failure %d on %q, want %q", i, f.Property, tt.failed[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWeighIn(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           WeightLogArgs
+		wantKg, wantLbs float64
+	}{
+		{name: "pounds", input: WeightLogArgs{WeightLbs: floatPtr(25)}, wantKg: 11.34, wantLbs: 25},
+		{name: "kilograms", input: WeightLogArgs{WeightKg: floatPtr(30)}, wantKg: 30, wantLbs: 66.14},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kg, lbs := weighIn(tt.input)
+			if kg != tt.wantKg || lbs != tt.wantLbs {
+				t.Errorf("weighIn = %g kg / %g lbs, want %g / %g", kg, lbs, tt.wantKg, tt.wantLbs)
+			}
+		})
+	}
+}
+
+func TestLatestWeighIn(t *testing.T) {
+	entry := func(id, measured, recorded string, lbs float64) WeightLogState {
+		var s WeightLogState
+		s.ID, s.MeasuredOn, s.RecordedAt, s.Pounds = id, measured, recorded, lbs
+		return s
+	}
+	tests := []struct {
+		name   string
+		logs   []WeightLogState
+		wantID string
+	}{
+		{name: "never weighed"},
+		{
+			name: "latest measurement wins over latest recording",
+			logs: []WeightLogState{
+				entry("weight-a", "2026-06-10", "2026-06-10T09:00:00Z", 24),
+				entry("weight-b", "2026-05-01", "2026-06-12T09:00:00Z", 22),
+			},
+			wantID: "weight-a",
+		},
+		{
+			name: "same day goes to the later recording",
+			logs: []WeightLogState{
+				entry("weight-a", "2026-06-10", "2026-06-10T09:00:00Z", 24),
+				entry("weight-b", "2026-06-10", "2026-06-10T18:00:00Z", 25),
+			},
+			wantID: "weight-b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			latest, ok := latestWeighIn(tt.logs)
+			if ok != (tt.wantID != "") || latest.ID != tt.wantID {
+				t.Errorf("latestWeighIn = %q (%v), want %q", latest.ID, ok, tt.wantID)
+			}
+		})
+	}
+}
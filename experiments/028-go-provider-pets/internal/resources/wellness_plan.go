@@ -0,0 +1,258 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// WellnessPlan Resource - a routine care subscription, separate from
+// insurance: a monthly fee buys a number of visits of each covered type a
+// plan year. Every VeterinaryVisit of a covered type draws on the dog's
+// plan while allowance remains, and Read shows what is left until the
+// plan's next anniversary.
+type WellnessPlan struct{}
+
+//pets:state id=ID created=StartDate
+//pets:output ID string id Generated identifier of the plan
+//pets:output StartDate string startDate When the plan started; plan years run from its anniversaries
+//pets:output MonthlyFee float64 monthlyFee What the tier costs a month
+//pets:output PlanYearStart string planYearStart First day of the current plan year
+//pets:output PlanYearEnd string planYearEnd Last day of the current plan year
+//pets:output Benefits []WellnessBenefit benefits Included visits by type and how many are left this plan year; recomputed on refresh
+//pets:embed CostDisplay
+type WellnessPlanArgs struct {
+	DogID string       `pulumi:"dogId" validate:"required"`
+	Tier  WellnessTier `pulumi:"tier" validate:"oneof=basic|plus|premium"` // basic, plus or premium
+}
+
+// WellnessBenefit is the allowance for one type of visit
+type WellnessBenefit struct {
+	VisitType VisitType `pulumi:"visitType" json:"visitType"`
+	Included  int       `pulumi:"included" json:"included"`
+	Used      int       `pulumi:"used" json:"used"`
+	Remaining int       `pulumi:"remaining" json:"remaining"`
+}
+
+// wellnessUsage is the registry record of what a plan has left in the
+// current plan year, stored under the plan's ID
+type wellnessUsage struct {
+	YearStart string               `json:"yearStart"`
+	Remaining map[VisitType]int    `json:"remaining"`
+	Claims    map[string]VisitType `json:"claims"` // visit ID to the type it used up
+}
+
+// wellnessAllowances are the visits each tier includes a plan year
+var wellnessAllowances = map[WellnessTier]map[VisitType]int{
+	WellnessBasic:   {VisitCheckup: 1, VisitVaccination: 1},
+	WellnessPlus:    {VisitCheckup: 2, VisitVaccination: 2, VisitDental: 1},
+	WellnessPremium: {VisitCheckup: 3, VisitVaccination: 3, VisitDental: 1, VisitFollowUp: 2},
+}
+
+var wellnessFees = map[WellnessTier]float64{
+	WellnessBasic:   25,
+	WellnessPlus:    45,
+	WellnessPremium: 70,
+}
+
+var wellnessPlans = crudResource[WellnessPlanArgs, WellnessPlanState, *WellnessPlanState]{
+	kind:     stored[WellnessPlanState]("wellness"),
+	prefix:   "wellness",
+	slug:     func(input WellnessPlanArgs) string { return input.DogID },
+	newState: newWellnessPlanState,
+	populate: func(ctx context.Context, state *WellnessPlanState, input WellnessPlanArgs) error {
+		if _, err := walkedDog(ctx, input.DogID); err != nil {
+			return err
+		}
+		now := registry.Now(ctx)
+		usage := freshWellnessYear(input.Tier, planYearStart(now, now))
+		if _, err := registry.Save(ctx, "wellness-usage", state.ID, 0, usage); err != nil {
+			return err
+		}
+		showBenefits(state, usage, now)
+		state.CostDisplay = showCosts(ctx, map[string]float64{"monthlyFee": state.MonthlyFee})
+		return nil
+	},
+	carry: func(ctx context.Context, state *WellnessPlanState, oldState WellnessPlanState, now time.Time) error {
+		usage, version, err := loadWellnessUsage(ctx, *state, now)
+		if err != nil {
+			return err
+		}
+		if state.Tier != oldState.Tier {
+			changeWellnessTier(&usage, oldState.Tier, state.Tier)
+		}
+		if _, err := registry.Save(ctx, "wellness-usage", state.ID, version, usage); err != nil {
+			return err
+		}
+		showBenefits(state, usage, now)
+		state.CostDisplay = showCosts(ctx, map[string]float64{"monthlyFee": state.MonthlyFee})
+		return nil
+	},
+	refresh: func(ctx context.Context, id string, state *WellnessPlanState) error {
+		now := registry.Now(ctx)
+		usage, _, err := loadWellnessUsage(ctx, *state, now)
+		if err != nil {
+			return err
+		}
+		showBenefits(state, usage, now)
+		state.CostDisplay = showCosts(ctx, map[string]float64{"monthlyFee": state.MonthlyFee})
+		return nil
+	},
+	related: []string{"wellness-usage"},
+}
+
+func (WellnessPlan) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (WellnessPlanArgs, []p.CheckFailure, error) {
+	return wellnessPlans.check(newInputs)
+}
+
+func (WellnessPlan) Create(ctx context.Context, name string, input WellnessPlanArgs, preview bool) (string, WellnessPlanState, error) {
+	return wellnessPlans.create(ctx, name, input, preview)
+}
+
+func (WellnessPlan) Read(ctx context.Context, id string, inputs WellnessPlanArgs, state WellnessPlanState) (string, WellnessPlanArgs, WellnessPlanState, error) {
+	return wellnessPlans.read(ctx, id, inputs, state)
+}
+
+func (WellnessPlan) Update(ctx context.Context, id string, oldState WellnessPlanState, input WellnessPlanArgs, preview bool) (WellnessPlanState, error) {
+	return wellnessPlans.update(ctx, id, oldState, input, preview)
+}
+
+func (WellnessPlan) Delete(ctx context.Context, id string, state WellnessPlanState) error {
+	return wellnessPlans.delete(ctx, id, state)
+}
+
+// planYearStart is the latest anniversary of start on or before now
+func planYearStart(start, now time.Time) time.Time {
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	year := start.AddDate(now.Year()-start.Year(), 0, 0)
+	if year.After(now) {
+		year = year.AddDate(-1, 0, 0)
+	}
+	if year.Before(start) {
+		return start
+	}
+	return year
+}
+
+func freshWellnessYear(tier WellnessTier, yearStart time.Time) wellnessUsage {
+	usage := wellnessUsage{
+		YearStart: yearStart.Format(dateLayout),
+		Remaining: map[VisitType]int{},
+		Claims:    map[string]VisitType{},
+	}
+	for visitType, included := range wellnessAllowances[tier] {
+		usage.Remaining[visitType] = included
+	}
+	return usage
+}
+
+// loadWellnessUsage is the plan's usage for the plan year now falls in;
+// on an anniversary the allowances start over
+func loadWellnessUsage(ctx context.Context, state WellnessPlanState, now time.Time) (wellnessUsage, int64, error) {
+	var usage wellnessUsage
+	version, err := registry.Load(ctx, "wellness-usage", state.ID, &usage)
+	if err != nil && !errors.Is(err, backend.ErrNotFound) {
+		return usage, version, err
+	}
+	start, perr := time.Parse("2006-01-02T15:04:05Z", state.StartDate)
+	if perr != nil {
+		return usage, version, fmt.Errorf("wellness plan %s: %w", state.ID, perr)
+	}
+	yearStart := planYearStart(start, now)
+	if err != nil || usage.YearStart != yearStart.Format(dateLayout) {
+		usage = freshWellnessYear(state.Tier, yearStart)
+	}
+	return usage, version, nil
+}
+
+// changeWellnessTier moves the remaining allowances to a new tier, keeping
+// what has been used this plan year
+func changeWellnessTier(usage *wellnessUsage, from, to WellnessTier) {
+	remaining := map[VisitType]int{}
+	for visitType, included := range wellnessAllowances[to] {
+		used := wellnessAllowances[from][visitType] - usage.Remaining[visitType]
+		remaining[visitType] = max(included-used, 0)
+	}
+	usage.Remaining = remaining
+}
+
+// showBenefits sets the plan's outputs from its usage
+func showBenefits(state *WellnessPlanState, usage wellnessUsage, now time.Time) {
+	state.MonthlyFee = wellnessFees[state.Tier]
+	state.PlanYearStart = usage.YearStart
+	if yearStart, err := time.Parse(dateLayout, usage.YearStart); err == nil {
+		state.PlanYearEnd = yearStart.AddDate(1, 0, -1).Format(dateLayout)
+	}
+	state.Benefits = []WellnessBenefit{}
+	for visitType, included := range wellnessAllowances[state.Tier] {
+		remaining := usage.Remaining[visitType]
+		state.Benefits = append(state.Benefits, WellnessBenefit{
+			VisitType: visitType,
+			Included:  included,
+			Used:      included - remaining,
+			Remaining: remaining,
+		})
+	}
+	sort.Slice(state.Benefits, func(i, j int) bool { return state.Benefits[i].VisitType < state.Benefits[j].VisitType })
+}
+
+// claimWellnessVisit draws a visit from the first of the dog's plans with
+// allowance left for its type and returns that plan's ID, or "" when no
+// plan covers it
+func claimWellnessVisit(ctx context.Context, visit VeterinaryVisitState, now time.Time) (string, error) {
+	plans, err := listForDog[WellnessPlanState](ctx, "wellness", visit.DogID)
+	if err != nil {
+		return "", err
+	}
+	sort.Slice(plans, func(i, j int) bool { return plans[i].ID < plans[j].ID })
+	for _, plan := range plans {
+		usage, version, err := loadWellnessUsage(ctx, plan, now)
+		if err != nil {
+			return "", err
+		}
+		if usage.Remaining[visit.VisitType] == 0 {
+			continue
+		}
+		usage.Remaining[visit.VisitType]--
+		usage.Claims[visit.ID] = visit.VisitType
+		if _, err := registry.Save(ctx, "wellness-usage", plan.ID, version, usage); err != nil {
+			return "", err
+		}
+		return plan.ID, nil
+	}
+	return "", nil
+}
+
+// releaseWellnessVisit gives a deleted visit's allowance back to its plan,
+// as long as the plan year it was drawn from is still running
+func releaseWellnessVisit(ctx context.Context, visit VeterinaryVisitState) error {
+	if visit.WellnessPlanID == "" {
+		return nil
+	}
+	var plan WellnessPlanState
+	if _, err := registry.Load(ctx, "wellness", visit.WellnessPlanID, &plan); errors.Is(err, backend.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	usage, version, err := loadWellnessUsage(ctx, plan, registry.Now(ctx))
+	if err != nil {
+		return err
+	}
+	visitType, ok := usage.Claims[visit.ID]
+	if !ok {
+		return nil
+	}
+	delete(usage.Claims, visit.ID)
+	usage.Remaining[visitType]++
+	_, err = registry.Save(ctx, "wellness-usage", plan.ID, version, usage)
+	return err
+}
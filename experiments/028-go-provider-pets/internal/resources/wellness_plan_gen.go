@@ -0,0 +1,48 @@
+// Code generated by genstate from wellness_plan.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// WellnessPlanOutputs are computed by the provider; Check rejects them as inputs
+type WellnessPlanOutputs struct {
+	ID            string            `pulumi:"id"`
+	StartDate     string            `pulumi:"startDate"`
+	MonthlyFee    float64           `pulumi:"monthlyFee"`
+	PlanYearStart string            `pulumi:"planYearStart"`
+	PlanYearEnd   string            `pulumi:"planYearEnd"`
+	Benefits      []WellnessBenefit `pulumi:"benefits"`
+	Version       int64             `pulumi:"version"`
+	CostDisplay
+}
+
+// WellnessPlanState echoes the inputs next to the computed outputs
+type WellnessPlanState struct {
+	WellnessPlanArgs
+	WellnessPlanOutputs
+}
+
+// newWellnessPlanState copies the inputs into an otherwise empty state
+func newWellnessPlanState(input WellnessPlanArgs) WellnessPlanState {
+	return WellnessPlanState{WellnessPlanArgs: input}
+}
+
+func (s *WellnessPlanState) stamp(id, created string)   { s.ID, s.StartDate = id, created }
+func (s *WellnessPlanState) identity() (string, string) { return s.ID, s.StartDate }
+func (s *WellnessPlanState) setVersion(version int64)   { s.Version = version }
+func (s *WellnessPlanState) storedVersion() int64       { return s.Version }
+
+func (args *WellnessPlanArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Tier, "basic, plus or premium")
+}
+
+func (state *WellnessPlanState) Annotate(a infer.Annotator) {
+	state.WellnessPlanArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the plan")
+	a.Describe(&state.StartDate, "When the plan started; plan years run from its anniversaries")
+	a.Describe(&state.MonthlyFee, "What the tier costs a month")
+	a.Describe(&state.PlanYearStart, "First day of the current plan year")
+	a.Describe(&state.PlanYearEnd, "Last day of the current plan year")
+	a.Describe(&state.Benefits, "Included visits by type and how many are left this plan year; recomputed on refresh")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
@@ -0,0 +1,84 @@
+package resources
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPlanYearStart(t *testing.T) {
+	start := time.Date(2025, 3, 10, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{name: "first day", now: start, want: "2025-03-10"},
+		{name: "first year", now: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), want: "2025-03-10"},
+		{name: "day before the anniversary", now: time.Date(2026, 3, 9, 23, 0, 0, 0, time.UTC), want: "2025-03-10"},
+		{name: "anniversary", now: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), want: "2026-03-10"},
+		{name: "third year", now: time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC), want: "2027-03-10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := planYearStart(start, tt.now).Format(dateLayout); got != tt.want {
+				t.Errorf("planYearStart = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangeWellnessTier(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to WellnessTier
+		used     map[VisitType]int
+		want     map[VisitType]int
+	}{
+		{
+			name: "upgrade keeps what was used",
+			from: WellnessBasic, to: WellnessPlus,
+			used: map[VisitType]int{VisitCheckup: 1},
+			want: map[VisitType]int{VisitCheckup: 1, VisitVaccination: 2, VisitDental: 1},
+		},
+		{
+			name: "downgrade past what was used",
+			from: WellnessPremium, to: WellnessBasic,
+			used: map[VisitType]int{VisitCheckup: 2, VisitFollowUp: 1},
+			want: map[VisitType]int{VisitCheckup: 0, VisitVaccination: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage := freshWellnessYear(tt.from, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+			for visitType, n := range tt.used {
+				usage.Remaining[visitType] -= n
+			}
+			changeWellnessTier(&usage, tt.from, tt.to)
+			if !reflect.DeepEqual(usage.Remaining, tt.want) {
+				t.Errorf("remaining = %v, want %v", usage.Remaining, tt.want)
+			}
+		})
+	}
+}
+
+func TestShowBenefits(t *testing.T) {
+	var state WellnessPlanState
+	state.Tier = WellnessPlus
+	usage := freshWellnessYear(WellnessPlus, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC))
+	usage.Remaining[VisitCheckup]--
+	usage.Remaining[VisitDental]--
+
+	showBenefits(&state, usage, time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC))
+	if state.MonthlyFee != 45 || state.PlanYearStart != "2026-03-10" || state.PlanYearEnd != "2027-03-09" {
+		t.Errorf("fee %g, plan year %s to %s", state.MonthlyFee, state.PlanYearStart, state.PlanYearEnd)
+	}
+	want := []WellnessBenefit{
+		{VisitType: VisitCheckup, Included: 2, Used: 1, Remaining: 1},
+		{VisitType: VisitDental, Included: 1, Used: 1, Remaining: 0},
+		{VisitType: VisitVaccination, Included: 2, Used: 0, Remaining: 2},
+	}
+	if !reflect.DeepEqual(state.Benefits, want) {
+		t.Errorf("benefits = %+v, want %+v", state.Benefits, want)
+	}
+}
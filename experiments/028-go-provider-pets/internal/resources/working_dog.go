@@ -0,0 +1,300 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/registry"
+)
+
+// WorkingDog Resource - puts a registered dog to work as a police, search
+// and rescue or detection dog with a handler. Each role asks more of the
+// dog than the base Dog model does: particular breeds or builds, a training
+// level, and a cap on scheduled duty hours. Duty hours served are accrued
+// from the schedule, in UTC, whenever the resource is read or updated.
+type WorkingDog struct{}
+
+//pets:state id=ID created=AssignedAt
+//pets:output ID string id Generated identifier of the assignment
+//pets:output AssignedAt string assignedAt When the dog was assigned to its handler
+//pets:output WeeklyDutyHours float64 weeklyDutyHours Hours on duty in a week of the schedule
+//pets:output DutyHoursServed float64 dutyHoursServed Scheduled hours served since the assignment
+//pets:output ServedThrough string servedThrough When dutyHoursServed was last brought up to date
+//pets:output OnDuty bool onDuty Whether a shift was under way then
+//pets:output NextShift string nextShift When the next shift starts
+type WorkingDogArgs struct {
+	DogID          string      `pulumi:"dogId" validate:"required"`
+	Role           WorkingRole `pulumi:"role" validate:"oneof=police|search-rescue|detection"` // One of police, search-rescue or detection
+	HandlerName    string      `pulumi:"handlerName" validate:"required"`
+	HandlerContact string      `pulumi:"handlerContact" validate:"required"` // An email address or an E.164 phone number
+	DutySchedule   []DutyShift `pulumi:"dutySchedule" validate:"required"`
+}
+
+// DutyShift is a weekly shift
+type DutyShift struct {
+	Day   string  `pulumi:"day" json:"day"`     // Day of the week, e.g. monday
+	Start string  `pulumi:"start" json:"start"` // HH:MM
+	Hours float64 `pulumi:"hours" json:"hours"` // Up to twelve
+}
+
+// roleRequirements is what a role asks of a dog
+type roleRequirements struct {
+	breeds         []DogBreed // any breed when empty
+	minSize        PetSize
+	minEnergy      int
+	scentWork      bool // short-nosed breeds can't do it
+	training       TrainingLevel
+	maxWeeklyHours float64
+}
+
+var workingRoles = map[WorkingRole]roleRequirements{
+	RolePolice:       {breeds: []DogBreed{GermanShepherd, Rottweiler, LabradorRetriever}, minSize: Large, training: Professional, maxWeeklyHours: 40},
+	RoleSearchRescue: {minSize: Medium, minEnergy: 4, scentWork: true, training: Advanced, maxWeeklyHours: 30},
+	RoleDetection:    {minSize: Small, minEnergy: 3, scentWork: true, training: Advanced, maxWeeklyHours: 30},
+}
+
+var sizeRank = map[PetSize]int{Small: 1, Medium: 2, Large: 3, ExtraLarge: 4}
+
+var trainingRank = map[TrainingLevel]int{Untrained: 0, Basic: 1, Intermediate: 2, Advanced: 3, Professional: 4}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+var workingDogs = crudResource[WorkingDogArgs, WorkingDogState, *WorkingDogState]{
+	kind:     stored[WorkingDogState]("working-dog"),
+	prefix:   "k9",
+	slug:     func(input WorkingDogArgs) string { return input.DogID },
+	newState: newWorkingDogState,
+	populate: func(ctx context.Context, state *WorkingDogState, input WorkingDogArgs) error {
+		if err := qualifyWorkingDog(ctx, state); err != nil {
+			return err
+		}
+		assigned, _ := time.Parse(time.RFC3339, state.AssignedAt)
+		state.ServedThrough = state.AssignedAt
+		trackDuty(state, assigned)
+		return nil
+	},
+	keep: func(state *WorkingDogState, oldState WorkingDogState) {
+		state.DutyHoursServed, state.ServedThrough = oldState.DutyHoursServed, oldState.ServedThrough
+	},
+	carry: func(ctx context.Context, state *WorkingDogState, oldState WorkingDogState, now time.Time) error {
+		if err := qualifyWorkingDog(ctx, state); err != nil {
+			return err
+		}
+		// Hours up to now were served on the old schedule
+		trackDuty(&oldState, now)
+		state.DutyHoursServed, state.ServedThrough = oldState.DutyHoursServed, oldState.ServedThrough
+		trackDuty(state, now)
+		return nil
+	},
+	refresh: func(ctx context.Context, id string, state *WorkingDogState) error {
+		trackDuty(state, registry.Now(ctx))
+		return nil
+	},
+}
+
+func (WorkingDog) Check(ctx context.Context, name string, oldInputs, newInputs resource.PropertyMap) (WorkingDogArgs, []p.CheckFailure, error) {
+	args, failures, err := workingDogs.check(newInputs)
+	return args, append(failures, checkWorkingDog(args)...), err
+}
+
+func (WorkingDog) Create(ctx context.Context, name string, input WorkingDogArgs, preview bool) (string, WorkingDogState, error) {
+	return workingDogs.create(ctx, name, input, preview)
+}
+
+func (WorkingDog) Read(ctx context.Context, id string, inputs WorkingDogArgs, state WorkingDogState) (string, WorkingDogArgs, WorkingDogState, error) {
+	return workingDogs.read(ctx, id, inputs, state)
+}
+
+func (WorkingDog) Update(ctx context.Context, id string, oldState WorkingDogState, input WorkingDogArgs, preview bool) (WorkingDogState, error) {
+	return workingDogs.update(ctx, id, oldState, input, preview)
+}
+
+func (WorkingDog) Delete(ctx context.Context, id string, state WorkingDogState) error {
+	return workingDogs.delete(ctx, id, state)
+}
+
+// checkWorkingDog validates the handler's contact and the duty schedule,
+// which has to fit within the role's weekly hours
+func checkWorkingDog(args WorkingDogArgs) []p.CheckFailure {
+	var failures []p.CheckFailure
+	if contact := args.HandlerContact; contact != "" && !e164.MatchString(contact) {
+		if _, err := mail.ParseAddress(contact); err != nil {
+			failures = append(failures, p.CheckFailure{Property: "handlerContact", Reason: "handlerContact must be an email address or an E.164 phone number, such as +15551234567"})
+		}
+	}
+	for i, shift := range args.DutySchedule {
+		if _, ok := weekdays[strings.ToLower(shift.Day)]; !ok {
+			failures = append(failures, p.CheckFailure{Property: fmt.Sprintf("dutySchedule[%d].day", i), Reason: "day must be a day of the week, such as monday"})
+		}
+		if _, err := time.Parse("15:04", shift.Start); err != nil || len(shift.Start) != len("15:04") {
+			failures = append(failures, p.CheckFailure{Property: fmt.Sprintf("dutySchedule[%d].start", i), Reason: "start must be HH:MM"})
+		}
+		if shift.Hours <= 0 || shift.Hours > 12 {
+			failures = append(failures, p.CheckFailure{Property: fmt.Sprintf("dutySchedule[%d].hours", i), Reason: "shifts run for more than 0 and at most 12 hours"})
+		}
+	}
+	if required, ok := workingRoles[args.Role]; ok {
+		if hours := weeklyHours(args.DutySchedule); hours > required.maxWeeklyHours {
+			failures = append(failures, p.CheckFailure{Property: "dutySchedule", Reason: fmt.Sprintf("%s dogs work at most %g hours a week; the schedule has %g", args.Role, required.maxWeeklyHours, hours)})
+		}
+	}
+	return failures
+}
+
+func weeklyHours(schedule []DutyShift) float64 {
+	hours := 0.0
+	for _, shift := range schedule {
+		hours += shift.Hours
+	}
+	return round2(hours)
+}
+
+// qualifyWorkingDog checks the dog against its role's requirements, and
+// that it isn't already assigned elsewhere
+func qualifyWorkingDog(ctx context.Context, state *WorkingDogState) error {
+	dog, err := walkedDog(ctx, state.DogID)
+	if err != nil {
+		return err
+	}
+	if unmet := roleShortfalls(state.Role, dog); len(unmet) > 0 {
+		return fmt.Errorf("%s doesn't meet the requirements of a %s dog:\n  - %s", dog.Name, state.Role, strings.Join(unmet, "\n  - "))
+	}
+	assignments, err := listForDog[WorkingDogState](ctx, "working-dog", state.DogID)
+	if err != nil {
+		return err
+	}
+	for _, other := range assignments {
+		if other.ID != state.ID {
+			return fmt.Errorf("%s is already a working dog with %s (%s)", dog.Name, other.HandlerName, other.ID)
+		}
+	}
+	state.WeeklyDutyHours = weeklyHours(state.DutySchedule)
+	return nil
+}
+
+// roleShortfalls lists the ways the dog falls short of the role
+func roleShortfalls(role WorkingRole, dog DogState) []string {
+	required, ok := workingRoles[role]
+	if !ok {
+		return []string{fmt.Sprintf("there is no %q role", role)}
+	}
+	breed := BreedCatalog[dog.Breed]
+
+	var unmet []string
+	if len(required.breeds) > 0 && !hasBreed(required.breeds, dog.Breed) {
+		names := make([]string, len(required.breeds))
+		for i, b := range required.breeds {
+			names[i] = BreedCatalog[b].Name
+		}
+		unmet = append(unmet, fmt.Sprintf("%s dogs are %s, not %s", role, strings.Join(names, ", "), breed.Name))
+	}
+	size := determineSizeByBreed(dog.Breed)
+	if dog.Size != nil {
+		size = *dog.Size
+	}
+	if sizeRank[size] < sizeRank[required.minSize] {
+		unmet = append(unmet, fmt.Sprintf("%s dogs are %s or bigger, not %s", role, required.minSize, size))
+	}
+	if breed.EnergyLevel < required.minEnergy {
+		unmet = append(unmet, fmt.Sprintf("%s dogs need an energy level of %d or more; %s is %d", role, required.minEnergy, breed.Name, breed.EnergyLevel))
+	}
+	if required.scentWork && breed.ShortNosed {
+		unmet = append(unmet, fmt.Sprintf("%s is short-nosed and can't do %s scent work", breed.Name, role))
+	}
+	level := Basic
+	if dog.TrainingLevel != nil {
+		level = *dog.TrainingLevel
+	}
+	if trainingRank[level] < trainingRank[required.training] {
+		unmet = append(unmet, fmt.Sprintf("trainingLevel is %s; %s dogs are trained to %s or above", level, role, required.training))
+	}
+	return unmet
+}
+
+func hasBreed(breeds []DogBreed, breed DogBreed) bool {
+	for _, b := range breeds {
+		if b == breed {
+			return true
+		}
+	}
+	return false
+}
+
+// trackDuty adds the scheduled hours served since servedThrough and works
+// out whether the dog is on duty at now and when it next goes on
+func trackDuty(state *WorkingDogState, now time.Time) {
+	now = now.UTC()
+	since, err := time.Parse(time.RFC3339, state.ServedThrough)
+	if err == nil && now.After(since) {
+		state.DutyHoursServed = round2(state.DutyHoursServed + dutyHours(state.DutySchedule, since, now))
+		state.ServedThrough = now.Format(time.RFC3339)
+	}
+	state.OnDuty, state.NextShift = false, ""
+	// Shifts that started yesterday can still be running
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	for i := 0; i < 9 && state.NextShift == ""; i++ {
+		for _, start := range shiftStarts(state.DutySchedule, day.AddDate(0, 0, i)) {
+			end := start.shiftEnd()
+			if !now.Before(start.at) && now.Before(end) {
+				state.OnDuty = true
+			}
+			if start.at.After(now) && (state.NextShift == "" || start.at.Format(time.RFC3339) < state.NextShift) {
+				state.NextShift = start.at.Format(time.RFC3339)
+			}
+		}
+	}
+}
+
+type shiftStart struct {
+	at    time.Time
+	hours float64
+}
+
+func (s shiftStart) shiftEnd() time.Time {
+	return s.at.Add(time.Duration(s.hours * float64(time.Hour)))
+}
+
+// shiftStarts are the shifts in the schedule starting on day
+func shiftStarts(schedule []DutyShift, day time.Time) []shiftStart {
+	var starts []shiftStart
+	for _, shift := range schedule {
+		clock, err := time.Parse("15:04", shift.Start)
+		if err != nil || weekdays[strings.ToLower(shift.Day)] != day.Weekday() {
+			continue
+		}
+		at := day.Add(time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute)
+		starts = append(starts, shiftStart{at: at, hours: shift.Hours})
+	}
+	return starts
+}
+
+// dutyHours is how much of the schedule falls between from and to
+func dutyHours(schedule []DutyShift, from, to time.Time) float64 {
+	from, to = from.UTC(), to.UTC()
+	total := time.Duration(0)
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, start := range shiftStarts(schedule, day) {
+			begin, end := start.at, start.shiftEnd()
+			if begin.Before(from) {
+				begin = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			if end.After(begin) {
+				total += end.Sub(begin)
+			}
+		}
+	}
+	return total.Hours()
+}
@@ -0,0 +1,50 @@
+// Code generated by genstate from working_dog.go; DO NOT EDIT.
+
+package resources
+
+import "github.com/pulumi/pulumi-go-provider/infer"
+
+// WorkingDogOutputs are computed by the provider; Check rejects them as inputs
+type WorkingDogOutputs struct {
+	ID              string  `pulumi:"id"`
+	AssignedAt      string  `pulumi:"assignedAt"`
+	WeeklyDutyHours float64 `pulumi:"weeklyDutyHours"`
+	DutyHoursServed float64 `pulumi:"dutyHoursServed"`
+	ServedThrough   string  `pulumi:"servedThrough"`
+	OnDuty          bool    `pulumi:"onDuty"`
+	NextShift       string  `pulumi:"nextShift"`
+	Version         int64   `pulumi:"version"`
+}
+
+// WorkingDogState echoes the inputs next to the computed outputs
+type WorkingDogState struct {
+	WorkingDogArgs
+	WorkingDogOutputs
+}
+
+// newWorkingDogState copies the inputs into an otherwise empty state
+func newWorkingDogState(input WorkingDogArgs) WorkingDogState {
+	return WorkingDogState{WorkingDogArgs: input}
+}
+
+func (s *WorkingDogState) stamp(id, created string)   { s.ID, s.AssignedAt = id, created }
+func (s *WorkingDogState) identity() (string, string) { return s.ID, s.AssignedAt }
+func (s *WorkingDogState) setVersion(version int64)   { s.Version = version }
+func (s *WorkingDogState) storedVersion() int64       { return s.Version }
+
+func (args *WorkingDogArgs) Annotate(a infer.Annotator) {
+	a.Describe(&args.Role, "One of police, search-rescue or detection")
+	a.Describe(&args.HandlerContact, "An email address or an E.164 phone number")
+}
+
+func (state *WorkingDogState) Annotate(a infer.Annotator) {
+	state.WorkingDogArgs.Annotate(a)
+	a.Describe(&state.ID, "Generated identifier of the assignment")
+	a.Describe(&state.AssignedAt, "When the dog was assigned to its handler")
+	a.Describe(&state.WeeklyDutyHours, "Hours on duty in a week of the schedule")
+	a.Describe(&state.DutyHoursServed, "Scheduled hours served since the assignment")
+	a.Describe(&state.ServedThrough, "When dutyHoursServed was last brought up to date")
+	a.Describe(&state.OnDuty, "Whether a shift was under way then")
+	a.Describe(&state.NextShift, "When the next shift starts")
+	a.Describe(&state.Version, "Version of the stored registry record, used to detect concurrent changes")
+}
@@ -0,0 +1,116 @@
+package resources
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRoleShortfalls(t *testing.T) {
+	dog := func(breed DogBreed, size PetSize, level TrainingLevel) DogState {
+		var d DogState
+		d.Breed, d.Size, d.TrainingLevel = breed, &size, &level
+		return d
+	}
+	tests := []struct {
+		name  string
+		role  WorkingRole
+		dog   DogState
+		unmet int
+	}{
+		{name: "police shepherd", role: RolePolice, dog: dog(GermanShepherd, Large, Professional)},
+		{name: "police beagle", role: RolePolice, dog: dog(Beagle, Small, Advanced), unmet: 3}, // breed, size, training
+		{name: "detection beagle", role: RoleDetection, dog: dog(Beagle, Small, Advanced)},
+		{name: "detection bulldog", role: RoleDetection, dog: dog(Bulldog, Medium, Advanced), unmet: 2}, // energy, short nose
+		{name: "search and rescue husky", role: RoleSearchRescue, dog: dog(Husky, Large, Advanced)},
+		{name: "search and rescue poodle", role: RoleSearchRescue, dog: dog(Poodle, Medium, Intermediate), unmet: 2}, // energy, training
+		{name: "unknown role", role: "therapy", dog: dog(Husky, Large, Advanced), unmet: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if unmet := roleShortfalls(tt.role, tt.dog); len(unmet) != tt.unmet {
+				t.Errorf("shortfalls = %q, want %d", unmet, tt.unmet)
+			}
+		})
+	}
+}
+
+func TestCheckWorkingDog(t *testing.T) {
+	shift := func(day, start string, hours float64) DutyShift {
+		return DutyShift{Day: day, Start: start, Hours: hours}
+	}
+	tests := []struct {
+		name   string
+		args   WorkingDogArgs
+		failed []string
+	}{
+		{name: "email", args: WorkingDogArgs{Role: RolePolice, HandlerContact: "ofc.diaz@example.org", DutySchedule: []DutyShift{shift("monday", "08:00", 8)}}},
+		{name: "phone", args: WorkingDogArgs{Role: RolePolice, HandlerContact: "+15551234567", DutySchedule: []DutyShift{shift("Friday", "22:00", 10)}}},
+		{name: "bad contact", args: WorkingDogArgs{Role: RolePolice, HandlerContact: "call dispatch"}, failed: []string{"handlerContact"}},
+		{
+			name:   "bad shifts",
+			args:   WorkingDogArgs{Role: RoleDetection, HandlerContact: "k9@example.org", DutySchedule: []DutyShift{shift("someday", "8am", 14)}},
+			failed: []string{"dutySchedule[0].day", "dutySchedule[0].start", "dutySchedule[0].hours"},
+		},
+		{
+			name: "too many hours",
+			args: WorkingDogArgs{Role: RoleSearchRescue, HandlerContact: "k9@example.org", DutySchedule: []DutyShift{
+				shift("monday", "06:00", 12), shift("tuesday", "06:00", 12), shift("wednesday", "06:00", 12),
+			}},
+			failed: []string{"dutySchedule"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := checkWorkingDog(tt.args)
+			if len(failures) != len(tt.failed) {
+				t.Fatalf("failures = %+v, want %v", failures, tt.failed)
+			}
+			for i, f := range failures {
+				if f.Property != tt.failed[i] {
+					t.Errorf("failure %d on %q, want %q", i, f.Property, tt.failed[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDutyHours(t *testing.T) {
+	// Weekday days plus an overnight Friday shift
+	schedule := []DutyShift{
+		{Day: "monday", Start: "09:00", Hours: 8},
+		{Day: "wednesday", Start: "09:00", Hours: 8},
+		{Day: "friday", Start: "22:00", Hours: 10},
+	}
+	at := func(s string) time.Time {
+		t, _ := time.Parse(time.RFC3339, s)
+		return t
+	}
+	tests := []struct {
+		name     string
+		from, to string
+		want     float64
+	}{
+		{name: "a whole week", from: "2026-10-12T00:00:00Z", to: "2026-10-19T00:00:00Z", want: 26},
+		{name: "partway through monday", from: "2026-10-12T13:00:00Z", to: "2026-10-12T15:30:00Z", want: 2.5},
+		{name: "into the overnight shift", from: "2026-10-17T00:00:00Z", to: "2026-10-17T06:00:00Z", want: 6},
+		{name: "nothing scheduled", from: "2026-10-13T00:00:00Z", to: "2026-10-14T00:00:00Z", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dutyHours(schedule, at(tt.from), at(tt.to)); got != tt.want {
+				t.Errorf("dutyHours = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	var state WorkingDogState
+	state.DutySchedule, state.ServedThrough = schedule, "2026-10-12T00:00:00Z"
+	trackDuty(&state, at("2026-10-12T10:00:00Z"))
+	trackDuty(&state, at("2026-10-17T01:00:00Z"))
+	if state.DutyHoursServed != 19 || state.ServedThrough != "2026-10-17T01:00:00Z" {
+		t.Errorf("served %v through %s, want 19 through 2026-10-17T01:00:00Z", state.DutyHoursServed, state.ServedThrough)
+	}
+	if !state.OnDuty || state.NextShift != "2026-10-19T09:00:00Z" {
+		t.Errorf("onDuty = %v, nextShift = %s; want on duty until monday's shift", state.OnDuty, state.NextShift)
+	}
+}
@@ -0,0 +1,196 @@
+// Package restapi exposes the pets registry as a small read-only JSON API,
+// so tools outside Pulumi can use the data the provider manages. Every
+// request needs the API token as a bearer token. Properties the provider
+// keeps secret, such as approval tokens and contact details, are left out.
+//
+//	GET /v1/kinds             the kinds of record and how many there are
+//	GET /v1/kinds/{kind}      one page of a kind's records
+//	GET /v1/kinds/{kind}/{id} one record
+//
+// Listing takes the same paging as the provider's list functions: pageSize
+// (50 by default, at most 500) and the pageToken from the previous page's
+// nextPageToken, plus sortBy, a property of the records, and descending.
+package restapi
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// Record is a registry record as the API returns it
+type Record struct {
+	Kind    string          `json:"kind"`
+	ID      string          `json:"id"`
+	Version int64           `json:"version"`
+	Updated string          `json:"updated"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type Kind struct {
+	Kind  string `json:"kind"`
+	Count int    `json:"count"`
+}
+
+type Page struct {
+	Records       []Record `json:"records"`
+	NextPageToken string   `json:"nextPageToken"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// Handler serves the API over store to clients presenting token
+func Handler(store backend.Store, token string) http.Handler {
+	api := &api{store: store, token: token}
+	return http.HandlerFunc(api.serve)
+}
+
+type api struct {
+	store backend.Store
+	token string
+}
+
+func (a *api) serve(w http.ResponseWriter, r *http.Request) {
+	if !a.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="pets"`)
+		writeError(w, http.StatusUnauthorized, errors.New("missing or wrong API token"))
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, errors.New("the API is read-only"))
+		return
+	}
+
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(path) < 2 || path[0] != "v1" || path[1] != "kinds" || len(path) > 4 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no such endpoint %s", r.URL.Path))
+		return
+	}
+	switch len(path) {
+	case 2:
+		a.kinds(w)
+	case 3:
+		a.list(w, r, path[2])
+	case 4:
+		a.get(w, path[2], path[3])
+	}
+}
+
+func (a *api) authorized(r *http.Request) bool {
+	presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && a.token != "" && subtle.ConstantTimeCompare([]byte(presented), []byte(a.token)) == 1
+}
+
+func (a *api) kinds(w http.ResponseWriter) {
+	records, err := a.store.List("")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	counts := map[string]int{}
+	for _, rec := range records {
+		counts[rec.Kind]++
+	}
+	kinds := []Kind{}
+	for kind, count := range counts {
+		kinds = append(kinds, Kind{Kind: kind, Count: count})
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i].Kind < kinds[j].Kind })
+	writeJSON(w, http.StatusOK, map[string][]Kind{"kinds": kinds})
+}
+
+func (a *api) list(w http.ResponseWriter, r *http.Request, kind string) {
+	q, err := query(kind, r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	records, next, err := a.store.ListPage(q)
+	if errors.Is(err, backend.ErrInvalidPageToken) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	page := Page{Records: []Record{}, NextPageToken: next}
+	for _, rec := range records {
+		page.Records = append(page.Records, record(rec))
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (a *api) get(w http.ResponseWriter, kind, id string) {
+	rec, err := a.store.Get(kind, id)
+	if errors.Is(err, backend.ErrNotFound) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%s %s: %w", kind, id, err))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, record(rec))
+}
+
+// query reads the paging and ordering parameters of a listing
+func query(kind string, params map[string][]string) (backend.Query, error) {
+	get := func(name string) string {
+		if values := params[name]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	q := backend.Query{Kind: kind, Limit: defaultPageSize, Cursor: get("pageToken"), SortBy: get("sortBy")}
+	if size := get("pageSize"); size != "" {
+		n, err := strconv.Atoi(size)
+		if err != nil || n < 1 || n > maxPageSize {
+			return q, fmt.Errorf("pageSize must be between 1 and %d", maxPageSize)
+		}
+		q.Limit = n
+	}
+	if descending := get("descending"); descending != "" {
+		d, err := strconv.ParseBool(descending)
+		if err != nil {
+			return q, fmt.Errorf("descending must be true or false")
+		}
+		q.Descending = d
+	}
+	return q, nil
+}
+
+func record(rec backend.Record) Record {
+	data, err := resources.Redact(rec.Kind, rec.Payload)
+	if err != nil || !json.Valid(data) {
+		// Keep the response valid; petsctl check reports such records
+		data = []byte("null")
+	}
+	return Record{Kind: rec.Kind, ID: rec.ID, Version: rec.Version, Updated: rec.Updated, Data: json.RawMessage(data)}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, apiError{Error: err.Error()})
+}
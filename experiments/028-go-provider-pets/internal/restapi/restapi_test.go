@@ -0,0 +1,161 @@
+package restapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aygp-dr/pulumi-pets-provider/internal/backend"
+	"github.com/aygp-dr/pulumi-pets-provider/internal/resources"
+)
+
+func TestHandler(t *testing.T) {
+	store := backend.NewFileStore(filepath.Join(t.TempDir(), "registry.json"))
+	if _, err := store.PutAll([]backend.Record{
+		{Kind: "dog", ID: "dog-ada", Payload: []byte(`{"ID":"dog-ada","Name":"Ada","Age":3}`)},
+		{Kind: "dog", ID: "dog-rex", Payload: []byte(`{"ID":"dog-rex","Name":"Rex","Age":7}`)},
+		{Kind: "dog", ID: "dog-zed", Payload: []byte(`{"ID":"dog-zed","Name":"Zed","Age":5}`)},
+		{Kind: "walk", ID: "walk-1", Payload: []byte(`{"DogID":"dog-rex"}`)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(Handler(store, "s3cret"))
+	defer server.Close()
+
+	call := func(method, path, token string, out any) int {
+		t.Helper()
+		req, _ := http.NewRequest(method, server.URL+path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if out != nil {
+			if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+				t.Fatalf("%s %s: %v", method, path, err)
+			}
+		}
+		return resp.StatusCode
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{name: "no token", method: "GET", path: "/v1/kinds", want: http.StatusUnauthorized},
+		{name: "wrong token", method: "GET", path: "/v1/kinds", token: "guess", want: http.StatusUnauthorized},
+		{name: "write", method: "DELETE", path: "/v1/kinds/dog/dog-rex", token: "s3cret", want: http.StatusMethodNotAllowed},
+		{name: "unknown endpoint", method: "GET", path: "/v2/dogs", token: "s3cret", want: http.StatusNotFound},
+		{name: "missing record", method: "GET", path: "/v1/kinds/dog/dog-nope", token: "s3cret", want: http.StatusNotFound},
+		{name: "page too large", method: "GET", path: "/v1/kinds/dog?pageSize=501", token: "s3cret", want: http.StatusBadRequest},
+		{name: "forged page token", method: "GET", path: "/v1/kinds/dog?pageToken=%21%21", token: "s3cret", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := call(tt.method, tt.path, tt.token, nil); got != tt.want {
+				t.Errorf("%s %s = %d, want %d", tt.method, tt.path, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("kinds", func(t *testing.T) {
+		var got struct{ Kinds []Kind }
+		call("GET", "/v1/kinds", "s3cret", &got)
+		if len(got.Kinds) != 2 || got.Kinds[0] != (Kind{"dog", 3}) || got.Kinds[1] != (Kind{"walk", 1}) {
+			t.Errorf("kinds = %+v", got.Kinds)
+		}
+	})
+
+	t.Run("list pages", func(t *testing.T) {
+		var ids []string
+		token := ""
+		for pages := 0; pages == 0 || token != ""; pages++ {
+			if pages > 3 {
+				t.Fatal("paging doesn't end")
+			}
+			var page Page
+			call("GET", "/v1/kinds/dog?pageSize=2&sortBy=Age&descending=true&pageToken="+token, "s3cret", &page)
+			for _, rec := range page.Records {
+				ids = append(ids, rec.ID)
+			}
+			token = page.NextPageToken
+		}
+		if len(ids) != 3 || ids[0] != "dog-rex" || ids[1] != "dog-zed" || ids[2] != "dog-ada" {
+			t.Errorf("listed %v, want the dogs oldest first", ids)
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		var got Record
+		if status := call("GET", "/v1/kinds/dog/dog-rex", "s3cret", &got); status != http.StatusOK {
+			t.Fatalf("status = %d", status)
+		}
+		var dog struct{ Name string }
+		json.Unmarshal(got.Data, &dog)
+		if got.Kind != "dog" || got.Version != 1 || dog.Name != "Rex" {
+			t.Errorf("record = %+v", got)
+		}
+	})
+}
+
+func TestHandlerLeavesOutSecrets(t *testing.T) {
+	store := backend.NewFileStore(filepath.Join(t.TempDir(), "registry.json"))
+	dog := resources.DogState{}
+	dog.ID, dog.Name, dog.ApprovalStatus, dog.ApprovalToken = "dog-rex", "Rex", "pending", "0123456789abcdef"
+	sms := resources.SmsReminderState{}
+	sms.Phone, sms.Message = "+15551234567", "Walk Rex"
+	var records []backend.Record
+	for _, rec := range []struct {
+		kind, id string
+		state    any
+	}{{"dog", "dog-rex", dog}, {"sms", "sms-1", sms}} {
+		payload, err := json.Marshal(rec.state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, backend.Record{Kind: rec.kind, ID: rec.id, Payload: payload})
+	}
+	if _, err := store.PutAll(records); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(Handler(store, "s3cret"))
+	defer server.Close()
+
+	tests := []struct {
+		path   string
+		secret string
+		kept   string
+	}{
+		{path: "/v1/kinds/dog/dog-rex", secret: dog.ApprovalToken, kept: `"ApprovalStatus":"pending"`},
+		{path: "/v1/kinds/dog", secret: dog.ApprovalToken, kept: `"Name":"Rex"`},
+		{path: "/v1/kinds/sms/sms-1", secret: sms.Phone, kept: `"Message":"Walk Rex"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", server.URL+tt.path, nil)
+			req.Header.Set("Authorization", "Bearer s3cret")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if strings.Contains(string(body), tt.secret) {
+				t.Errorf("response has the secret %q: %s", tt.secret, body)
+			}
+			if !strings.Contains(string(body), tt.kept) {
+				t.Errorf("response lost %s: %s", tt.kept, body)
+			}
+		})
+	}
+}
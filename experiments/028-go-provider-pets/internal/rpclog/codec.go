@@ -0,0 +1,119 @@
+package rpclog
+
+import (
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// Property values are written as plain JSON, with secrets and unknowns
+// wrapped in single-key objects so they survive the round trip:
+//
+//	{"$secret": "[redacted]"}   a secret, its value dropped unless kept
+//	{"$computed": true}         a value not known until apply
+const (
+	secretKey   = "$secret"
+	computedKey = "$computed"
+	redacted    = "[redacted]"
+)
+
+// codec converts property maps to and from their recorded form.
+type codec struct {
+	keepSecrets bool
+}
+
+func (c codec) encodeMap(m resource.PropertyMap) map[string]any {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[string(k)] = c.encodeValue(v)
+	}
+	return out
+}
+
+func (c codec) encodeValue(v resource.PropertyValue) any {
+	switch {
+	case v.IsSecret():
+		var inner any = redacted
+		if c.keepSecrets {
+			inner = c.encodeValue(v.SecretValue().Element)
+		}
+		return map[string]any{secretKey: inner}
+	case v.IsComputed(), v.IsOutput():
+		// Outputs only reach a provider while their value is still unknown
+		return map[string]any{computedKey: true}
+	case v.IsArray():
+		items := v.ArrayValue()
+		out := make([]any, len(items))
+		for i, item := range items {
+			out[i] = c.encodeValue(item)
+		}
+		return out
+	case v.IsObject():
+		return c.encodeMap(v.ObjectValue())
+	case v.IsNull():
+		return nil
+	default:
+		// Bools, numbers and strings; the provider has no asset properties
+		return v.Mappable()
+	}
+}
+
+func decodeMap(m map[string]any) resource.PropertyMap {
+	if m == nil {
+		return nil
+	}
+	out := make(resource.PropertyMap, len(m))
+	for k, v := range m {
+		out[resource.PropertyKey(k)] = decodeValue(v)
+	}
+	return out
+}
+
+func decodeValue(v any) resource.PropertyValue {
+	switch v := v.(type) {
+	case bool:
+		return resource.NewBoolProperty(v)
+	case float64:
+		return resource.NewNumberProperty(v)
+	case string:
+		return resource.NewStringProperty(v)
+	case []any:
+		items := make([]resource.PropertyValue, len(v))
+		for i, item := range v {
+			items[i] = decodeValue(item)
+		}
+		return resource.NewArrayProperty(items)
+	case map[string]any:
+		if len(v) == 1 {
+			if inner, ok := v[secretKey]; ok {
+				return resource.MakeSecret(decodeValue(inner))
+			}
+			if _, ok := v[computedKey]; ok {
+				return resource.MakeComputed(resource.NewStringProperty(""))
+			}
+		}
+		return resource.NewObjectProperty(decodeMap(v))
+	default:
+		return resource.NewNullProperty()
+	}
+}
+
+// redactJSON drops secret values from decoded JSON, so recordings made with
+// and without PETS_RECORD_SECRETS compare equal.
+func redactJSON(v any) any {
+	switch v := v.(type) {
+	case []any:
+		for i := range v {
+			v[i] = redactJSON(v[i])
+		}
+	case map[string]any:
+		if _, ok := v[secretKey]; ok && len(v) == 1 {
+			return map[string]any{secretKey: redacted}
+		}
+		for k := range v {
+			v[k] = redactJSON(v[k])
+		}
+	}
+	return v
+}
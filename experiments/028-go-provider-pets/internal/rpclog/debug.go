@@ -0,0 +1,58 @@
+package rpclog
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+// Debugger logs a sanitized copy of every RPC, secrets redacted, through
+// the engine's logger; run `pulumi up --debug` to see it. It starts enabled
+// with PETS_DEBUG and switches on when Configure sees pets:debugRpc=true.
+type Debugger struct {
+	enabled atomic.Bool
+	codec   codec
+}
+
+// NewDebugger returns a Debugger, already logging when enabled is set.
+func NewDebugger(enabled bool) *Debugger {
+	d := &Debugger{}
+	d.enabled.Store(enabled)
+	return d
+}
+
+// Wrap returns prov with its RPCs logged while debugging is on.
+func (d *Debugger) Wrap(prov p.Provider) p.Provider {
+	wrapped := intercept(prov, d.codec, d.log)
+	if configure := wrapped.Configure; configure != nil {
+		// Provider config only arrives with Configure, so the flag is
+		// picked up here, before the call itself is logged.
+		wrapped.Configure = func(ctx context.Context, req p.ConfigureRequest) error {
+			if v, ok := req.Args["debugRpc"]; ok && v.IsBool() && v.BoolValue() {
+				d.enabled.Store(true)
+			}
+			return configure(ctx, req)
+		}
+	}
+	return wrapped
+}
+
+func (d *Debugger) log(ctx context.Context, method string, req, resp any, err error) {
+	if !d.enabled.Load() {
+		return
+	}
+	logger := p.GetLogger(ctx)
+	request, _ := json.Marshal(req)
+	if err != nil {
+		logger.Debugf("rpc %s request=%s error=%q", method, request, err.Error())
+		return
+	}
+	if resp == nil {
+		logger.Debugf("rpc %s request=%s", method, request)
+		return
+	}
+	response, _ := json.Marshal(resp)
+	logger.Debugf("rpc %s request=%s response=%s", method, request, response)
+}
@@ -0,0 +1,73 @@
+package rpclog
+
+import (
+	"context"
+
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+// observer sees every call after it returns, with the request and response
+// already in their recorded form. resp is nil for calls without a response.
+type observer func(ctx context.Context, method string, req, resp any, err error)
+
+// intercept returns prov with each implemented RPC reported to observe.
+func intercept(prov p.Provider, c codec, observe observer) p.Provider {
+	wrapped := prov
+	if configure := prov.Configure; configure != nil {
+		wrapped.Configure = func(ctx context.Context, req p.ConfigureRequest) error {
+			err := configure(ctx, req)
+			observe(ctx, "Configure", c.configureRequest(req), nil, err)
+			return err
+		}
+	}
+	if check := prov.Check; check != nil {
+		wrapped.Check = func(ctx context.Context, req p.CheckRequest) (p.CheckResponse, error) {
+			resp, err := check(ctx, req)
+			observe(ctx, "Check", c.checkRequest(req), c.checkResponse(resp), err)
+			return resp, err
+		}
+	}
+	if diff := prov.Diff; diff != nil {
+		wrapped.Diff = func(ctx context.Context, req p.DiffRequest) (p.DiffResponse, error) {
+			resp, err := diff(ctx, req)
+			observe(ctx, "Diff", c.diffRequest(req), c.diffResponse(resp), err)
+			return resp, err
+		}
+	}
+	if create := prov.Create; create != nil {
+		wrapped.Create = func(ctx context.Context, req p.CreateRequest) (p.CreateResponse, error) {
+			resp, err := create(ctx, req)
+			observe(ctx, "Create", c.createRequest(req), c.createResponse(resp), err)
+			return resp, err
+		}
+	}
+	if read := prov.Read; read != nil {
+		wrapped.Read = func(ctx context.Context, req p.ReadRequest) (p.ReadResponse, error) {
+			resp, err := read(ctx, req)
+			observe(ctx, "Read", c.readRequest(req), c.readResponse(resp), err)
+			return resp, err
+		}
+	}
+	if update := prov.Update; update != nil {
+		wrapped.Update = func(ctx context.Context, req p.UpdateRequest) (p.UpdateResponse, error) {
+			resp, err := update(ctx, req)
+			observe(ctx, "Update", c.updateRequest(req), c.updateResponse(resp), err)
+			return resp, err
+		}
+	}
+	if del := prov.Delete; del != nil {
+		wrapped.Delete = func(ctx context.Context, req p.DeleteRequest) error {
+			err := del(ctx, req)
+			observe(ctx, "Delete", c.deleteRequest(req), nil, err)
+			return err
+		}
+	}
+	if invoke := prov.Invoke; invoke != nil {
+		wrapped.Invoke = func(ctx context.Context, req p.InvokeRequest) (p.InvokeResponse, error) {
+			resp, err := invoke(ctx, req)
+			observe(ctx, "Invoke", c.invokeRequest(req), c.invokeResponse(resp), err)
+			return resp, err
+		}
+	}
+	return wrapped
+}
@@ -0,0 +1,72 @@
+// Package rpclog records the provider's resource RPCs to a JSONL file,
+// replays a recording against another build of the provider, and logs
+// sanitized RPC payloads for debugging.
+//
+// Each line is an Entry holding one Configure, Check, Diff, Create, Update or
+// Delete call: the request, and either the response or the error it returned.
+// Secret values are redacted unless the recorder is told to keep them; a
+// replay then runs with the redacted placeholder in their place.
+package rpclog
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+// Entry is one recorded call
+type Entry struct {
+	Time     string          `json:"time"`
+	Method   string          `json:"method"`
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// Recorder appends the calls made through a wrapped provider to a writer.
+type Recorder struct {
+	mu    sync.Mutex
+	w     io.Writer
+	codec codec
+}
+
+// NewRecorder writes entries to w, keeping secret values only when asked to.
+func NewRecorder(w io.Writer, keepSecrets bool) *Recorder {
+	return &Recorder{w: w, codec: codec{keepSecrets: keepSecrets}}
+}
+
+// Wrap returns prov with its resource RPCs recorded. Read and Invoke are
+// left out: replaying them adds nothing, and functions such as
+// backupRegistry write to paths outside the replay's data directory.
+func (r *Recorder) Wrap(prov p.Provider) p.Provider {
+	return intercept(prov, r.codec, func(ctx context.Context, method string, req, resp any, err error) {
+		if method == "Read" || method == "Invoke" {
+			return
+		}
+		r.record(method, req, resp, err)
+	})
+}
+
+// record writes one entry. A recording is a debugging aid, so failing to
+// write it never fails the deployment.
+func (r *Recorder) record(method string, req, resp any, err error) {
+	entry := Entry{Time: time.Now().UTC().Format("2006-01-02T15:04:05Z"), Method: method}
+	entry.Request, _ = json.Marshal(req)
+	if err != nil {
+		entry.Error = err.Error()
+	} else if resp != nil {
+		entry.Response, _ = json.Marshal(resp)
+	}
+	line, marshalErr := json.Marshal(entry)
+	if marshalErr != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, _ = r.w.Write(append(line, '\n'))
+}
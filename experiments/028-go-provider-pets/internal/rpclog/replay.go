@@ -0,0 +1,155 @@
+package rpclog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// Result is the outcome of replaying one entry. Want and Got hold the
+// response as JSON, or the error text prefixed with "error: ".
+type Result struct {
+	Line   int
+	Method string
+	Want   string
+	Got    string
+}
+
+// Matches reports whether the replay reproduced the recorded outcome
+func (r Result) Matches() bool {
+	return r.Want == r.Got
+}
+
+// Replay re-executes every entry of a recording, in order, against prov and
+// reports each outcome. Configure is pointed at dataDir, with any encryption
+// keys dropped, so a replay never touches the registry it was recorded
+// against; http settings are dropped too, since their secrets were redacted. Generated IDs and timestamps only line up when the recording was
+// made with pets:deterministic=true.
+func Replay(ctx context.Context, prov p.Provider, recording io.Reader, dataDir string, report func(Result)) error {
+	replay := codec{}
+	scanner := bufio.NewScanner(recording)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		resp, err := replay.call(ctx, prov, entry, dataDir)
+		var unsupported unsupportedError
+		if errors.As(err, &unsupported) {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		want, got := outcome(entry.Response, entry.Error), ""
+		if err != nil {
+			got = "error: " + err.Error()
+		} else {
+			encoded, marshalErr := json.Marshal(resp)
+			if marshalErr != nil {
+				return fmt.Errorf("line %d: %w", line, marshalErr)
+			}
+			got = outcome(encoded, "")
+		}
+		report(Result{Line: line, Method: entry.Method, Want: want, Got: got})
+	}
+	return scanner.Err()
+}
+
+// unsupportedError marks entries the replay cannot run at all, as opposed
+// to calls that ran and failed.
+type unsupportedError struct{ msg string }
+
+func (e unsupportedError) Error() string { return e.msg }
+
+func (c codec) call(ctx context.Context, prov p.Provider, entry Entry, dataDir string) (any, error) {
+	switch entry.Method {
+	case "Configure":
+		var req configureRequest
+		if err := decodeRequest(entry, &req, prov.Configure != nil); err != nil {
+			return nil, err
+		}
+		decoded := req.decode()
+		if decoded.Args == nil {
+			decoded.Args = resource.PropertyMap{}
+		}
+		decoded.Args["dataDir"] = resource.NewStringProperty(dataDir)
+		delete(decoded.Args, "encryptionKey")
+		delete(decoded.Args, "previousEncryptionKeys")
+		delete(decoded.Args, "http")
+		return nil, prov.Configure(ctx, decoded)
+	case "Check":
+		var req checkRequest
+		if err := decodeRequest(entry, &req, prov.Check != nil); err != nil {
+			return nil, err
+		}
+		resp, err := prov.Check(ctx, req.decode())
+		return c.checkResponse(resp), err
+	case "Diff":
+		var req diffRequest
+		if err := decodeRequest(entry, &req, prov.Diff != nil); err != nil {
+			return nil, err
+		}
+		resp, err := prov.Diff(ctx, req.decode())
+		return c.diffResponse(resp), err
+	case "Create":
+		var req createRequest
+		if err := decodeRequest(entry, &req, prov.Create != nil); err != nil {
+			return nil, err
+		}
+		resp, err := prov.Create(ctx, req.decode())
+		return c.createResponse(resp), err
+	case "Update":
+		var req updateRequest
+		if err := decodeRequest(entry, &req, prov.Update != nil); err != nil {
+			return nil, err
+		}
+		resp, err := prov.Update(ctx, req.decode())
+		return c.updateResponse(resp), err
+	case "Delete":
+		var req deleteRequest
+		if err := decodeRequest(entry, &req, prov.Delete != nil); err != nil {
+			return nil, err
+		}
+		return nil, prov.Delete(ctx, req.decode())
+	default:
+		return nil, unsupportedError{fmt.Sprintf("unknown method %q", entry.Method)}
+	}
+}
+
+func decodeRequest(entry Entry, req any, implemented bool) error {
+	if !implemented {
+		return unsupportedError{fmt.Sprintf("provider does not implement %s", entry.Method)}
+	}
+	if err := json.Unmarshal(entry.Request, req); err != nil {
+		return unsupportedError{fmt.Sprintf("decoding %s request: %v", entry.Method, err)}
+	}
+	return nil
+}
+
+// outcome normalizes a recorded or replayed result for comparison: secrets
+// are redacted and object keys sorted, so only real differences show.
+func outcome(response json.RawMessage, errText string) string {
+	if errText != "" {
+		return "error: " + errText
+	}
+	if len(response) == 0 || string(response) == "null" {
+		return ""
+	}
+	var decoded any
+	if err := json.Unmarshal(response, &decoded); err != nil {
+		return string(response)
+	}
+	normalized, _ := json.Marshal(redactJSON(decoded))
+	return string(normalized)
+}
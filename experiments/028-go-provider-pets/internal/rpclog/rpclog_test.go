@@ -0,0 +1,183 @@
+package rpclog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		value resource.PropertyValue
+	}{
+		{"null", resource.NewNullProperty()},
+		{"bool", resource.NewBoolProperty(true)},
+		{"number", resource.NewNumberProperty(3.5)},
+		{"string", resource.NewStringProperty("Rex")},
+		{"array", resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewStringProperty("friendly"),
+			resource.NewNumberProperty(2),
+		})},
+		{"object", resource.NewObjectProperty(resource.PropertyMap{
+			"name": resource.NewStringProperty("Rex"),
+			"age":  resource.NewNumberProperty(4),
+		})},
+		{"secret", resource.MakeSecret(resource.NewStringProperty("hunter2"))},
+		{"computed", resource.MakeComputed(resource.NewStringProperty(""))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := json.Marshal(codec{keepSecrets: true}.encodeValue(tt.value))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded any
+			if err := json.Unmarshal(raw, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if got := decodeValue(decoded); !reflect.DeepEqual(got, tt.value) {
+				t.Errorf("round trip of %s = %#v, want %#v", raw, got, tt.value)
+			}
+		})
+	}
+}
+
+func TestRequestRoundTrip(t *testing.T) {
+	c := codec{keepSecrets: true}
+	diff := p.DiffRequest{ID: "dog-rex-1", Urn: "urn:pulumi:dev::lab::pets:index:Dog::rex", IgnoreChanges: []resource.PropertyKey{"age", "tags"}}
+	if got := c.diffRequest(diff).decode(); !reflect.DeepEqual(got.IgnoreChanges, diff.IgnoreChanges) {
+		t.Errorf("diff ignoreChanges = %v, want %v", got.IgnoreChanges, diff.IgnoreChanges)
+	}
+	update := p.UpdateRequest{ID: "dog-rex-1", IgnoreChanges: []resource.PropertyKey{"weight"}}
+	if got := c.updateRequest(update).decode(); !reflect.DeepEqual(got.IgnoreChanges, update.IgnoreChanges) {
+		t.Errorf("update ignoreChanges = %v, want %v", got.IgnoreChanges, update.IgnoreChanges)
+	}
+	if got := c.invokeRequest(p.InvokeRequest{Token: "pets:index:getBreedImage"}); got.Token != "pets:index:getBreedImage" {
+		t.Errorf("invoke token = %q", got.Token)
+	}
+}
+
+func TestCodecRedactsSecrets(t *testing.T) {
+	m := resource.PropertyMap{
+		"token": resource.MakeSecret(resource.NewStringProperty("hunter2")),
+		"nested": resource.NewObjectProperty(resource.PropertyMap{
+			"key": resource.MakeSecret(resource.NewStringProperty("s3cret")),
+		}),
+	}
+	raw, err := json.Marshal(codec{}.encodeMap(m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leaked := range []string{"hunter2", "s3cret"} {
+		if strings.Contains(string(raw), leaked) {
+			t.Errorf("recording %s leaks %q", raw, leaked)
+		}
+	}
+	if want := `{"nested":{"key":{"$secret":"[redacted]"}},"token":{"$secret":"[redacted]"}}`; string(raw) != want {
+		t.Errorf("encoded %s, want %s", raw, want)
+	}
+}
+
+// fakeProvider answers Create with an ID derived from its input and fails
+// Delete for one ID, so a replay can be steered into differing.
+func fakeProvider(suffix string) p.Provider {
+	return p.Provider{
+		Create: func(ctx context.Context, req p.CreateRequest) (p.CreateResponse, error) {
+			name := req.Properties["name"].StringValue()
+			return p.CreateResponse{ID: "dog-" + name + suffix, Properties: req.Properties}, nil
+		},
+		Delete: func(ctx context.Context, req p.DeleteRequest) error {
+			if req.ID == "dog-stuck" {
+				return errors.New("dog is still walking")
+			}
+			return nil
+		},
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	var recording bytes.Buffer
+	prov := NewRecorder(&recording, false).Wrap(fakeProvider(""))
+
+	ctx := context.Background()
+	if _, err := prov.Create(ctx, p.CreateRequest{
+		Urn:        "urn:pulumi:dev::pets::pets:index:Dog::rex",
+		Properties: resource.PropertyMap{"name": resource.NewStringProperty("rex")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := prov.Delete(ctx, p.DeleteRequest{ID: "dog-stuck"}); err == nil {
+		t.Fatal("Delete of dog-stuck succeeded")
+	}
+	if prov.Check != nil {
+		t.Error("Wrap implemented Check for a provider without one")
+	}
+
+	tests := []struct {
+		name    string
+		suffix  string
+		matches []bool
+	}{
+		{"same build", "", []bool{true, true}},
+		{"changed create", "-1", []bool{false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var matches []bool
+			err := Replay(ctx, fakeProvider(tt.suffix), bytes.NewReader(recording.Bytes()), t.TempDir(), func(r Result) {
+				matches = append(matches, r.Matches())
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(matches, tt.matches) {
+				t.Errorf("matches = %v, want %v", matches, tt.matches)
+			}
+		})
+	}
+}
+
+func TestReplayRejectsUnimplementedMethods(t *testing.T) {
+	recording := `{"time":"2024-01-01T00:00:00Z","method":"Check","request":{"urn":"x"}}` + "\n"
+	err := Replay(context.Background(), fakeProvider(""), strings.NewReader(recording), t.TempDir(), func(Result) {})
+	if err == nil || !strings.Contains(err.Error(), "does not implement Check") {
+		t.Errorf("Replay error = %v, want an unimplemented Check", err)
+	}
+}
+
+func TestDebuggerEnabledByConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		args resource.PropertyMap
+		want bool
+	}{
+		{"unset", resource.PropertyMap{}, false},
+		{"off", resource.PropertyMap{"debugRpc": resource.NewBoolProperty(false)}, false},
+		{"on", resource.PropertyMap{"debugRpc": resource.NewBoolProperty(true)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDebugger(false)
+			prov := d.Wrap(p.Provider{
+				Configure: func(context.Context, p.ConfigureRequest) error { return nil },
+			})
+			if err := prov.Configure(context.Background(), p.ConfigureRequest{Args: tt.args}); err != nil {
+				t.Fatal(err)
+			}
+			if got := d.enabled.Load(); got != tt.want {
+				t.Errorf("enabled = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package rpclog
+
+import (
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// The recorded form of each request and response. Only Configure's Args are
+// kept: Variables repeats them as strings, without the secret markers.
+
+type configureRequest struct {
+	Args map[string]any `json:"args,omitempty"`
+}
+
+type checkRequest struct {
+	Urn  string         `json:"urn"`
+	Olds map[string]any `json:"olds,omitempty"`
+	News map[string]any `json:"news,omitempty"`
+}
+
+type checkFailure struct {
+	Property string `json:"property"`
+	Reason   string `json:"reason"`
+}
+
+type checkResponse struct {
+	Inputs   map[string]any `json:"inputs,omitempty"`
+	Failures []checkFailure `json:"failures,omitempty"`
+}
+
+type diffRequest struct {
+	ID            string         `json:"id"`
+	Urn           string         `json:"urn"`
+	Olds          map[string]any `json:"olds,omitempty"`
+	News          map[string]any `json:"news,omitempty"`
+	IgnoreChanges []string       `json:"ignoreChanges,omitempty"`
+}
+
+type propertyDiff struct {
+	Kind      string `json:"kind"`
+	InputDiff bool   `json:"inputDiff,omitempty"`
+}
+
+type diffResponse struct {
+	DeleteBeforeReplace bool                    `json:"deleteBeforeReplace,omitempty"`
+	HasChanges          bool                    `json:"hasChanges"`
+	DetailedDiff        map[string]propertyDiff `json:"detailedDiff,omitempty"`
+}
+
+type createRequest struct {
+	Urn        string         `json:"urn"`
+	Properties map[string]any `json:"properties,omitempty"`
+	Timeout    float64        `json:"timeout,omitempty"`
+	Preview    bool           `json:"preview,omitempty"`
+}
+
+type createResponse struct {
+	ID         string         `json:"id"`
+	Properties map[string]any `json:"properties,omitempty"`
+}
+
+type updateRequest struct {
+	ID            string         `json:"id"`
+	Urn           string         `json:"urn"`
+	Olds          map[string]any `json:"olds,omitempty"`
+	News          map[string]any `json:"news,omitempty"`
+	Timeout       float64        `json:"timeout,omitempty"`
+	IgnoreChanges []string       `json:"ignoreChanges,omitempty"`
+	Preview       bool           `json:"preview,omitempty"`
+}
+
+type updateResponse struct {
+	Properties map[string]any `json:"properties,omitempty"`
+}
+
+type deleteRequest struct {
+	ID         string         `json:"id"`
+	Urn        string         `json:"urn"`
+	Properties map[string]any `json:"properties,omitempty"`
+	Timeout    float64        `json:"timeout,omitempty"`
+}
+
+func (c codec) configureRequest(req p.ConfigureRequest) configureRequest {
+	return configureRequest{Args: c.encodeMap(req.Args)}
+}
+
+func (r configureRequest) decode() p.ConfigureRequest {
+	return p.ConfigureRequest{Args: decodeMap(r.Args)}
+}
+
+func (c codec) checkRequest(req p.CheckRequest) checkRequest {
+	return checkRequest{Urn: string(req.Urn), Olds: c.encodeMap(req.Olds), News: c.encodeMap(req.News)}
+}
+
+func (r checkRequest) decode() p.CheckRequest {
+	return p.CheckRequest{Urn: resource.URN(r.Urn), Olds: decodeMap(r.Olds), News: decodeMap(r.News)}
+}
+
+func (c codec) checkResponse(resp p.CheckResponse) checkResponse {
+	out := checkResponse{Inputs: c.encodeMap(resp.Inputs)}
+	for _, f := range resp.Failures {
+		out.Failures = append(out.Failures, checkFailure{Property: f.Property, Reason: f.Reason})
+	}
+	return out
+}
+
+func (c codec) diffRequest(req p.DiffRequest) diffRequest {
+	return diffRequest{
+		ID:            req.ID,
+		Urn:           string(req.Urn),
+		Olds:          c.encodeMap(req.Olds),
+		News:          c.encodeMap(req.News),
+		IgnoreChanges: keyNames(req.IgnoreChanges),
+	}
+}
+
+func (r diffRequest) decode() p.DiffRequest {
+	return p.DiffRequest{
+		ID:            r.ID,
+		Urn:           resource.URN(r.Urn),
+		Olds:          decodeMap(r.Olds),
+		News:          decodeMap(r.News),
+		IgnoreChanges: propertyKeys(r.IgnoreChanges),
+	}
+}
+
+func (c codec) diffResponse(resp p.DiffResponse) diffResponse {
+	out := diffResponse{DeleteBeforeReplace: resp.DeleteBeforeReplace, HasChanges: resp.HasChanges}
+	if len(resp.DetailedDiff) > 0 {
+		out.DetailedDiff = make(map[string]propertyDiff, len(resp.DetailedDiff))
+		for k, d := range resp.DetailedDiff {
+			out.DetailedDiff[k] = propertyDiff{Kind: string(d.Kind), InputDiff: d.InputDiff}
+		}
+	}
+	return out
+}
+
+func (c codec) createRequest(req p.CreateRequest) createRequest {
+	return createRequest{
+		Urn:        string(req.Urn),
+		Properties: c.encodeMap(req.Properties),
+		Timeout:    req.Timeout,
+		Preview:    req.Preview,
+	}
+}
+
+func (r createRequest) decode() p.CreateRequest {
+	return p.CreateRequest{
+		Urn:        resource.URN(r.Urn),
+		Properties: decodeMap(r.Properties),
+		Timeout:    r.Timeout,
+		Preview:    r.Preview,
+	}
+}
+
+func (c codec) createResponse(resp p.CreateResponse) createResponse {
+	return createResponse{ID: resp.ID, Properties: c.encodeMap(resp.Properties)}
+}
+
+func (c codec) updateRequest(req p.UpdateRequest) updateRequest {
+	return updateRequest{
+		ID:            req.ID,
+		Urn:           string(req.Urn),
+		Olds:          c.encodeMap(req.Olds),
+		News:          c.encodeMap(req.News),
+		Timeout:       req.Timeout,
+		IgnoreChanges: keyNames(req.IgnoreChanges),
+		Preview:       req.Preview,
+	}
+}
+
+func (r updateRequest) decode() p.UpdateRequest {
+	return p.UpdateRequest{
+		ID:            r.ID,
+		Urn:           resource.URN(r.Urn),
+		Olds:          decodeMap(r.Olds),
+		News:          decodeMap(r.News),
+		Timeout:       r.Timeout,
+		IgnoreChanges: propertyKeys(r.IgnoreChanges),
+		Preview:       r.Preview,
+	}
+}
+
+func (c codec) updateResponse(resp p.UpdateResponse) updateResponse {
+	return updateResponse{Properties: c.encodeMap(resp.Properties)}
+}
+
+func (c codec) deleteRequest(req p.DeleteRequest) deleteRequest {
+	return deleteRequest{
+		ID:         req.ID,
+		Urn:        string(req.Urn),
+		Properties: c.encodeMap(req.Properties),
+		Timeout:    req.Timeout,
+	}
+}
+
+func (r deleteRequest) decode() p.DeleteRequest {
+	return p.DeleteRequest{
+		ID:         r.ID,
+		Urn:        resource.URN(r.Urn),
+		Properties: decodeMap(r.Properties),
+		Timeout:    r.Timeout,
+	}
+}
+
+type readRequest struct {
+	ID         string         `json:"id"`
+	Urn        string         `json:"urn"`
+	Properties map[string]any `json:"properties,omitempty"`
+	Inputs     map[string]any `json:"inputs,omitempty"`
+}
+
+type readResponse struct {
+	ID         string         `json:"id"`
+	Properties map[string]any `json:"properties,omitempty"`
+	Inputs     map[string]any `json:"inputs,omitempty"`
+}
+
+type invokeRequest struct {
+	Token string         `json:"token"`
+	Args  map[string]any `json:"args,omitempty"`
+}
+
+type invokeResponse struct {
+	Return   map[string]any `json:"return,omitempty"`
+	Failures []checkFailure `json:"failures,omitempty"`
+}
+
+func (c codec) readRequest(req p.ReadRequest) readRequest {
+	return readRequest{
+		ID:         req.ID,
+		Urn:        string(req.Urn),
+		Properties: c.encodeMap(req.Properties),
+		Inputs:     c.encodeMap(req.Inputs),
+	}
+}
+
+func (c codec) readResponse(resp p.ReadResponse) readResponse {
+	return readResponse{ID: resp.ID, Properties: c.encodeMap(resp.Properties), Inputs: c.encodeMap(resp.Inputs)}
+}
+
+func (c codec) invokeRequest(req p.InvokeRequest) invokeRequest {
+	return invokeRequest{Token: string(req.Token), Args: c.encodeMap(req.Args)}
+}
+
+func (c codec) invokeResponse(resp p.InvokeResponse) invokeResponse {
+	out := invokeResponse{Return: c.encodeMap(resp.Return)}
+	for _, f := range resp.Failures {
+		out.Failures = append(out.Failures, checkFailure{Property: f.Property, Reason: f.Reason})
+	}
+	return out
+}
+
+// keyNames records property keys as plain strings
+func keyNames(keys []resource.PropertyKey) []string {
+	if keys == nil {
+		return nil
+	}
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = string(k)
+	}
+	return names
+}
+
+// propertyKeys turns recorded key names back into property keys
+func propertyKeys(names []string) []resource.PropertyKey {
+	if names == nil {
+		return nil
+	}
+	keys := make([]resource.PropertyKey, len(names))
+	for i, name := range names {
+		keys[i] = resource.PropertyKey(name)
+	}
+	return keys
+}
@@ -0,0 +1,315 @@
+// Package selftest checks a provider configuration end to end. It drives
+// each resource through create, read, update and delete over the same RPC
+// interface the engine uses, against whatever backend the configuration
+// opens, and cleans up after itself.
+package selftest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// timeout bounds each Create, Update and Delete, in seconds
+const timeout = 300
+
+// Result is the outcome of one resource's cycle
+type Result struct {
+	Resource string
+	Steps    []string // steps that succeeded, in order
+	Err      error    // why the cycle failed, nil when it passed
+}
+
+// cycle scripts one resource. Steps a resource doesn't implement are left
+// out: without Update a change replaces the resource, and without Read the
+// engine keeps the state it has.
+type cycle struct {
+	resource string
+	// inputs builds the inputs from a fixture: a per-run suffix for Dog,
+	// and the test dog's ID for everything else
+	inputs func(fixture string) resource.PropertyMap
+	read   bool
+	update func(inputs resource.PropertyMap) // edits the inputs for Update
+}
+
+// The Dog cycle runs first and its dog stays registered until every other
+// cycle is done, since they all refer to it.
+var dogCycle = cycle{
+	resource: "Dog",
+	inputs: func(run string) resource.PropertyMap {
+		return resource.PropertyMap{
+			"name":      resource.NewStringProperty("selftest-" + run),
+			"breed":     resource.NewStringProperty("beagle"),
+			"ownerName": resource.NewStringProperty("Self Test"),
+		}
+	},
+	read: true,
+	update: func(inputs resource.PropertyMap) {
+		inputs["age"] = resource.NewNumberProperty(3)
+	},
+}
+
+// The PetShelter cycle runs next and its shelter likewise stays until the
+// end, for the intake to arrive at. It is named after the test dog so runs
+// don't collide.
+var shelterCycle = cycle{
+	resource: "PetShelter",
+	inputs: func(dogID string) resource.PropertyMap {
+		return resource.PropertyMap{
+			"name":     resource.NewStringProperty("Self-Test Shelter " + dogID),
+			"address":  resource.NewStringProperty("1 Self-Test Lane"),
+			"capacity": resource.NewNumberProperty(10),
+		}
+	},
+	read: true,
+	update: func(inputs resource.PropertyMap) {
+		inputs["capacity"] = resource.NewNumberProperty(12)
+	},
+}
+
+// selftestShelter is the ID of the shelter the PetShelter cycle registers
+func selftestShelter(dogID string) string {
+	return strings.ToLower("self-test-shelter-" + dogID)
+}
+
+var dependentCycles = []cycle{
+	{
+		resource: "DogWalk",
+		inputs: func(dogID string) resource.PropertyMap {
+			return resource.PropertyMap{
+				"dogId":    resource.NewStringProperty(dogID),
+				"duration": resource.NewNumberProperty(30),
+				"distance": resource.NewNumberProperty(1.5),
+			}
+		},
+	},
+	{
+		resource: "WeightLog",
+		inputs: func(dogID string) resource.PropertyMap {
+			return resource.PropertyMap{
+				"dogId":     resource.NewStringProperty(dogID),
+				"weightLbs": resource.NewNumberProperty(24),
+			}
+		},
+		read: true,
+	},
+	{
+		resource: "VeterinaryVisit",
+		inputs: func(dogID string) resource.PropertyMap {
+			return resource.PropertyMap{
+				"dogId":      resource.NewStringProperty(dogID),
+				"visitType":  resource.NewStringProperty("checkup"),
+				"vetName":    resource.NewStringProperty("Dr. Self Test"),
+				"clinicName": resource.NewStringProperty("Self-Test Clinic"),
+			}
+		},
+		read: true,
+	},
+	{
+		resource: "Adoption",
+		inputs: func(dogID string) resource.PropertyMap {
+			return resource.PropertyMap{
+				"dogId":          resource.NewStringProperty(dogID),
+				"adopterName":    resource.NewStringProperty("Self Test"),
+				"adopterContact": resource.NewStringProperty("selftest@example.com"),
+				"reviewSeconds":  resource.NewNumberProperty(0),
+				"timeoutSeconds": resource.NewNumberProperty(30),
+			}
+		},
+	},
+	{
+		resource: "BulkDogIntake",
+		inputs: func(dogID string) resource.PropertyMap {
+			return resource.PropertyMap{
+				"shelterId": resource.NewStringProperty(selftestShelter(dogID)),
+				"dogs": resource.NewArrayProperty([]resource.PropertyValue{
+					resource.NewObjectProperty(resource.PropertyMap{
+						"name":      resource.NewStringProperty(dogID + "-intake"),
+						"breed":     resource.NewStringProperty("poodle"),
+						"ownerName": resource.NewStringProperty("Self-Test Shelter"),
+					}),
+				}),
+			}
+		},
+	},
+}
+
+// Run configures prov with config, which falls back to the PETS_*
+// environment like any other deployment, and runs every cycle. An error
+// means the self-test could not start at all; failed cycles are reported
+// in the results.
+func Run(ctx context.Context, prov p.Provider, config resource.PropertyMap) ([]Result, error) {
+	tokens, err := resourceTokens(ctx, prov)
+	if err != nil {
+		return nil, err
+	}
+	if err := prov.Configure(ctx, p.ConfigureRequest{Args: config}); err != nil {
+		return nil, fmt.Errorf("configuring provider: %w", err)
+	}
+
+	t := &tester{prov: prov, tokens: tokens}
+	run := fmt.Sprint(time.Now().Unix())
+
+	dogResult := Result{Resource: dogCycle.resource}
+	dog, err := t.exercise(ctx, dogCycle, run, &dogResult)
+	if err != nil {
+		dogResult.Err = err
+		results := []Result{dogResult}
+		for _, c := range append([]cycle{shelterCycle}, dependentCycles...) {
+			results = append(results, Result{Resource: c.resource, Err: errors.New("skipped: no Dog to refer to")})
+		}
+		return results, nil
+	}
+
+	shelterResult := Result{Resource: shelterCycle.resource}
+	shelter, err := t.exercise(ctx, shelterCycle, dog.id, &shelterResult)
+	shelterResult.Err = err
+
+	var results []Result
+	for _, c := range dependentCycles {
+		result := Result{Resource: c.resource}
+		inst, err := t.exercise(ctx, c, dog.id, &result)
+		if err == nil {
+			err = t.step(&result, "delete", t.delete(ctx, inst))
+		}
+		result.Err = err
+		results = append(results, result)
+	}
+
+	if shelterResult.Err == nil {
+		shelterResult.Err = t.step(&shelterResult, "delete", t.delete(ctx, shelter))
+	}
+	dogResult.Err = t.step(&dogResult, "delete", t.delete(ctx, dog))
+	return append([]Result{dogResult, shelterResult}, results...), nil
+}
+
+// resourceTokens maps resource type names to their schema tokens, so the
+// test builds the same URNs the engine would.
+func resourceTokens(ctx context.Context, prov p.Provider) (map[string]string, error) {
+	resp, err := prov.GetSchema(ctx, p.GetSchemaRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("reading provider schema: %w", err)
+	}
+	var schema struct {
+		Resources map[string]json.RawMessage `json:"resources"`
+	}
+	if err := json.Unmarshal([]byte(resp.Schema), &schema); err != nil {
+		return nil, fmt.Errorf("reading provider schema: %w", err)
+	}
+	tokens := map[string]string{}
+	for token := range schema.Resources {
+		tokens[token[strings.LastIndex(token, ":")+1:]] = token
+	}
+	return tokens, nil
+}
+
+type tester struct {
+	prov   p.Provider
+	tokens map[string]string
+}
+
+// instance is a resource the test created
+type instance struct {
+	urn    resource.URN
+	id     string
+	inputs resource.PropertyMap
+	props  resource.PropertyMap
+}
+
+// exercise creates a resource and reads and updates it where supported. A
+// failure after Create deletes the resource again before returning.
+func (t *tester) exercise(ctx context.Context, c cycle, fixture string, result *Result) (instance, error) {
+	token, ok := t.tokens[c.resource]
+	if !ok {
+		return instance{}, fmt.Errorf("%s is not in the provider schema", c.resource)
+	}
+	inst := instance{urn: resource.URN(fmt.Sprintf("urn:pulumi:selftest::pets::%s::selftest-%s", token, strings.ToLower(c.resource)))}
+
+	if err := t.step(result, "create", t.create(ctx, &inst, c.inputs(fixture))); err != nil {
+		return inst, err
+	}
+
+	if err := t.readAndUpdate(ctx, c, &inst, result); err != nil {
+		// Best effort: a failed cycle shouldn't leave records behind
+		_ = t.delete(ctx, inst)
+		return inst, err
+	}
+	return inst, nil
+}
+
+func (t *tester) readAndUpdate(ctx context.Context, c cycle, inst *instance, result *Result) error {
+	if c.read {
+		read, err := t.prov.Read(ctx, p.ReadRequest{ID: inst.id, Urn: inst.urn, Properties: inst.props, Inputs: inst.inputs})
+		if err == nil && read.ID != inst.id {
+			err = fmt.Errorf("%s was not found after it was created", inst.id)
+		}
+		if err := t.step(result, "read", err); err != nil {
+			return err
+		}
+	}
+
+	if c.update != nil {
+		news := inst.inputs.Copy()
+		c.update(news)
+		if err := t.step(result, "update", t.update(ctx, inst, news)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *tester) create(ctx context.Context, inst *instance, inputs resource.PropertyMap) error {
+	inputs, err := t.check(ctx, inst.urn, inputs)
+	if err != nil {
+		return err
+	}
+	resp, err := t.prov.Create(ctx, p.CreateRequest{Urn: inst.urn, Properties: inputs, Timeout: timeout})
+	if err != nil {
+		return err
+	}
+	inst.id, inst.inputs, inst.props = resp.ID, inputs, resp.Properties
+	return nil
+}
+
+func (t *tester) update(ctx context.Context, inst *instance, news resource.PropertyMap) error {
+	news, err := t.check(ctx, inst.urn, news)
+	if err != nil {
+		return err
+	}
+	resp, err := t.prov.Update(ctx, p.UpdateRequest{ID: inst.id, Urn: inst.urn, Olds: inst.props, News: news, Timeout: timeout})
+	if err != nil {
+		return err
+	}
+	inst.inputs, inst.props = news, resp.Properties
+	return nil
+}
+
+func (t *tester) check(ctx context.Context, urn resource.URN, news resource.PropertyMap) (resource.PropertyMap, error) {
+	resp, err := t.prov.Check(ctx, p.CheckRequest{Urn: urn, News: news})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Failures) > 0 {
+		return nil, fmt.Errorf("inputs rejected: %s", resp.Failures[0].Reason)
+	}
+	return resp.Inputs, nil
+}
+
+func (t *tester) delete(ctx context.Context, inst instance) error {
+	return t.prov.Delete(ctx, p.DeleteRequest{ID: inst.id, Urn: inst.urn, Properties: inst.props, Timeout: timeout})
+}
+
+// step records a successful step, or names the step a failure happened in.
+func (t *tester) step(result *Result, name string, err error) error {
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	result.Steps = append(result.Steps, name)
+	return nil
+}
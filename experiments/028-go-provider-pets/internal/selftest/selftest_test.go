@@ -0,0 +1,143 @@
+package selftest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+const schema = `{"resources": {
+	"pets:index:Dog": {}, "pets:index:DogWalk": {}, "pets:index:WeightLog": {},
+	"pets:index:VeterinaryVisit": {}, "pets:index:PetShelter": {},
+	"pets:index:Adoption": {}, "pets:index:BulkDogIntake": {}
+}}`
+
+// fakeProvider accepts everything except Creates and Deletes of the type
+// named in failCreate and failDelete, and counts live resources.
+func fakeProvider(failCreate, failDelete string, live *int) p.Provider {
+	fails := func(urn resource.URN, typ string) bool {
+		return typ != "" && strings.Contains(string(urn), ":"+typ+"::")
+	}
+	return p.Provider{
+		GetSchema: func(context.Context, p.GetSchemaRequest) (p.GetSchemaResponse, error) {
+			return p.GetSchemaResponse{Schema: schema}, nil
+		},
+		Configure: func(context.Context, p.ConfigureRequest) error { return nil },
+		Check: func(_ context.Context, req p.CheckRequest) (p.CheckResponse, error) {
+			return p.CheckResponse{Inputs: req.News}, nil
+		},
+		Create: func(_ context.Context, req p.CreateRequest) (p.CreateResponse, error) {
+			if fails(req.Urn, failCreate) {
+				return p.CreateResponse{}, errors.New("backend unreachable")
+			}
+			*live++
+			return p.CreateResponse{ID: "id-" + string(req.Urn), Properties: req.Properties}, nil
+		},
+		Read: func(_ context.Context, req p.ReadRequest) (p.ReadResponse, error) {
+			return p.ReadResponse{ID: req.ID, Properties: req.Properties, Inputs: req.Inputs}, nil
+		},
+		Update: func(_ context.Context, req p.UpdateRequest) (p.UpdateResponse, error) {
+			return p.UpdateResponse{Properties: req.News}, nil
+		},
+		Delete: func(_ context.Context, req p.DeleteRequest) error {
+			if fails(req.Urn, failDelete) {
+				return errors.New("record is locked")
+			}
+			*live--
+			return nil
+		},
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		failCreate string
+		failDelete string
+		wantFailed map[string]string // resource -> error substring
+		wantLive   int
+	}{
+		{
+			name:       "all pass",
+			wantFailed: map[string]string{},
+		},
+		{
+			name:       "dependent create fails",
+			failCreate: "DogWalk",
+			wantFailed: map[string]string{"DogWalk": "create: backend unreachable"},
+		},
+		{
+			name:       "dog create fails",
+			failCreate: "Dog",
+			wantFailed: map[string]string{
+				"Dog":             "create: backend unreachable",
+				"PetShelter":      "skipped",
+				"DogWalk":         "skipped",
+				"WeightLog":       "skipped",
+				"VeterinaryVisit": "skipped",
+				"Adoption":        "skipped",
+				"BulkDogIntake":   "skipped",
+			},
+		},
+		{
+			name:       "shelter create fails",
+			failCreate: "PetShelter",
+			wantFailed: map[string]string{"PetShelter": "create: backend unreachable"},
+		},
+		{
+			name:       "delete fails",
+			failDelete: "Adoption",
+			wantFailed: map[string]string{"Adoption": "delete: record is locked"},
+			wantLive:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			live := 0
+			results, err := Run(context.Background(), fakeProvider(tt.failCreate, tt.failDelete, &live), resource.PropertyMap{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 2+len(dependentCycles) {
+				t.Fatalf("got %d results, want one per resource", len(results))
+			}
+			for _, r := range results {
+				want, shouldFail := tt.wantFailed[r.Resource]
+				switch {
+				case shouldFail && (r.Err == nil || !strings.Contains(r.Err.Error(), want)):
+					t.Errorf("%s: err = %v, want %q", r.Resource, r.Err, want)
+				case !shouldFail && r.Err != nil:
+					t.Errorf("%s: unexpected failure %v", r.Resource, r.Err)
+				}
+			}
+			if live != tt.wantLive {
+				t.Errorf("%d resources left behind, want %d", live, tt.wantLive)
+			}
+		})
+	}
+}
+
+func TestRunReportsSteps(t *testing.T) {
+	live := 0
+	results, err := Run(context.Background(), fakeProvider("", "", &live), resource.PropertyMap{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"Dog":             "create, read, update, delete",
+		"PetShelter":      "create, read, update, delete",
+		"DogWalk":         "create, delete",
+		"WeightLog":       "create, read, delete",
+		"VeterinaryVisit": "create, read, delete",
+	}
+	for _, r := range results {
+		if steps, ok := want[r.Resource]; ok && strings.Join(r.Steps, ", ") != steps {
+			t.Errorf("%s steps = %v, want %s", r.Resource, r.Steps, steps)
+		}
+	}
+}
@@ -0,0 +1,394 @@
+// Command genstate derives resource State structs from annotated Args
+// definitions. It is run through go:generate in internal/resources:
+//
+//	//go:generate go run ../tools/genstate
+//
+// An Args struct opts in with directives in its doc comment:
+//
+//	//pets:state id=ID created=Date
+//	//pets:output ID string id Generated identifier of the walk
+//	//pets:output Calories int calories Estimated calories burned
+//	//pets:embed ApprovalState
+//	type DogWalkArgs struct { ... }
+//
+// For every such struct in foo.go, foo_gen.go gets an XOutputs struct (the
+// outputs in order, a Version field, then any embeds), the State struct
+// embedding the Args next to the Outputs,
+// a newXState constructor copying the Args in, the crudState methods keyed
+// by the id and created outputs, and Annotate methods describing the Args
+// fields from their comments and the outputs from their directives.
+//
+// Optional Args fields may declare a schema default with a `default` tag,
+// for example `default:"2"`. It becomes a SetDefault in Annotate and an
+// applyDefaults method that fills the same values in on the Go side. The
+// schema has no way to deprecate an input, so a `deprecated` tag's message
+// is added to the field's description instead, and a deprecatedInputs
+// method lists the deprecated inputs for Check to warn about.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const directive = "//pets:"
+
+type output struct {
+	Name, Type, Tag, Description string
+}
+
+type field struct {
+	Name, Description string
+	Property          string // the input's name in the schema
+	Default           string // Go expression for the `default` tag, if any
+	Deprecated        string // the `deprecated` tag, if any
+}
+
+type spec struct {
+	Base    string // DogWalk for DogWalkArgs
+	ID      string
+	Created string
+	Outputs []output
+	Embeds  []string
+	Fields  []field // Args fields that carry a comment, a default or a deprecation
+}
+
+func (s spec) hasDeprecations() bool {
+	for _, f := range s.Fields {
+		if f.Deprecated != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (s spec) hasDefaults() bool {
+	for _, f := range s.Fields {
+		if f.Default != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("genstate: ")
+
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		log.Fatal(err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") || strings.HasSuffix(file, "_gen.go") {
+			continue
+		}
+		if err := generate(file); err != nil {
+			log.Fatalf("%s: %v", file, err)
+		}
+	}
+}
+
+// generate writes the _gen.go companion of file, or removes a stale one
+// when file no longer declares any annotated Args.
+func generate(file string) error {
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	specs, err := collect(parsed)
+	if err != nil {
+		return err
+	}
+
+	target := strings.TrimSuffix(file, ".go") + "_gen.go"
+	if len(specs) == 0 {
+		if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	src, err := render(parsed.Name.Name, file, specs)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(target, src, 0o644)
+}
+
+func collect(file *ast.File) ([]spec, error) {
+	var specs []spec
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE || gen.Doc == nil || len(gen.Specs) != 1 {
+			continue
+		}
+		typeSpec := gen.Specs[0].(*ast.TypeSpec)
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			continue
+		}
+
+		s, found, err := parseDirectives(typeSpec.Name.Name, gen.Doc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", typeSpec.Name.Name, err)
+		}
+		if !found {
+			continue
+		}
+		if s.Fields, err = annotatedFields(structType); err != nil {
+			return nil, fmt.Errorf("%s: %w", typeSpec.Name.Name, err)
+		}
+		specs = append(specs, s)
+	}
+	return specs, nil
+}
+
+func parseDirectives(name string, doc *ast.CommentGroup) (spec, bool, error) {
+	s := spec{Base: strings.TrimSuffix(name, "Args")}
+	found := false
+
+	for _, c := range doc.List {
+		if !strings.HasPrefix(c.Text, directive) {
+			continue
+		}
+		verb, rest, _ := strings.Cut(strings.TrimPrefix(c.Text, directive), " ")
+		switch verb {
+		case "state":
+			if s.Base == name {
+				return s, false, fmt.Errorf("pets:state needs a type named <Resource>Args")
+			}
+			found = true
+			for _, opt := range strings.Fields(rest) {
+				key, value, _ := strings.Cut(opt, "=")
+				switch key {
+				case "id":
+					s.ID = value
+				case "created":
+					s.Created = value
+				default:
+					return s, false, fmt.Errorf("unknown pets:state option %q", key)
+				}
+			}
+		case "output":
+			parts := strings.Fields(rest)
+			if len(parts) < 3 {
+				return s, false, fmt.Errorf("pets:output wants <Name> <Type> <tag> [description], got %q", rest)
+			}
+			s.Outputs = append(s.Outputs, output{
+				Name:        parts[0],
+				Type:        parts[1],
+				Tag:         parts[2],
+				Description: strings.Join(parts[3:], " "),
+			})
+		case "embed":
+			s.Embeds = append(s.Embeds, strings.TrimSpace(rest))
+		default:
+			return s, false, fmt.Errorf("unknown directive pets:%s", verb)
+		}
+	}
+
+	if !found {
+		return s, false, nil
+	}
+	if s.ID == "" || s.Created == "" {
+		return s, false, fmt.Errorf("pets:state needs both id= and created=")
+	}
+	for _, want := range []string{s.ID, s.Created} {
+		if !hasOutput(s.Outputs, want) {
+			return s, false, fmt.Errorf("pets:state names %s, which is not a pets:output", want)
+		}
+	}
+	return s, true, nil
+}
+
+func hasOutput(outputs []output, name string) bool {
+	for _, o := range outputs {
+		if o.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// annotatedFields picks the Args fields whose doc or line comment can serve
+// as a schema description, or that declare a `default` or `deprecated`
+// tag; embedded structs annotate their own fields.
+func annotatedFields(structType *ast.StructType) ([]field, error) {
+	var fields []field
+	for _, f := range structType.Fields.List {
+		if len(f.Names) == 0 {
+			continue
+		}
+		description := strings.TrimSpace(f.Doc.Text())
+		if description == "" {
+			description = strings.TrimSpace(f.Comment.Text())
+		}
+		description = strings.Join(strings.Fields(description), " ")
+
+		var def, deprecated, property string
+		if f.Tag != nil {
+			tag, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			if value, ok := reflect.StructTag(tag).Lookup("default"); ok {
+				if def, err = defaultExpr(f.Type, value); err != nil {
+					return nil, fmt.Errorf("%s: %w", f.Names[0].Name, err)
+				}
+			}
+			deprecated = reflect.StructTag(tag).Get("deprecated")
+			property, _, _ = strings.Cut(reflect.StructTag(tag).Get("pulumi"), ",")
+		}
+		if deprecated != "" {
+			description = strings.TrimSpace(description + " Deprecated: " + deprecated)
+		}
+
+		if description == "" && def == "" && deprecated == "" {
+			continue
+		}
+		for _, n := range f.Names {
+			fields = append(fields, field{Name: n.Name, Description: description, Property: property, Default: def, Deprecated: deprecated})
+		}
+	}
+	return fields, nil
+}
+
+// defaultExpr turns a `default` tag into a Go expression of the pointed-to
+// type. Defaults only make sense on optional, that is pointer, fields.
+func defaultExpr(typ ast.Expr, value string) (string, error) {
+	star, ok := typ.(*ast.StarExpr)
+	if !ok {
+		return "", fmt.Errorf("default needs an optional (pointer) field")
+	}
+	ident, ok := star.X.(*ast.Ident)
+	if !ok {
+		return "", fmt.Errorf("default is not supported on this type")
+	}
+
+	switch ident.Name {
+	case "string":
+		return strconv.Quote(value), nil
+	case "bool":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return "", err
+		}
+		return value, nil
+	case "int":
+		if _, err := strconv.Atoi(value); err != nil {
+			return "", err
+		}
+		return value, nil
+	case "int64":
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return "", err
+		}
+		return "int64(" + value + ")", nil
+	case "float64":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "", err
+		}
+		return "float64(" + value + ")", nil
+	default:
+		// Enum types in this repo are named strings
+		return ident.Name + "(" + strconv.Quote(value) + ")", nil
+	}
+}
+
+func render(pkg, source string, specs []spec) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by genstate from %s; DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import \"github.com/pulumi/pulumi-go-provider/infer\"\n")
+
+	for _, s := range specs {
+		args, state := s.Base+"Args", s.Base+"State"
+
+		outputs := s.Base + "Outputs"
+		fmt.Fprintf(&b, "\n// %s are computed by the provider; Check rejects them as inputs\n", outputs)
+		fmt.Fprintf(&b, "type %s struct {\n", outputs)
+		for _, o := range s.Outputs {
+			fmt.Fprintf(&b, "\t%s %s `pulumi:%q`\n", o.Name, o.Type, o.Tag)
+		}
+		fmt.Fprintf(&b, "\tVersion int64 `pulumi:\"version\"`\n")
+		for _, e := range s.Embeds {
+			fmt.Fprintf(&b, "\t%s\n", e)
+		}
+		fmt.Fprintf(&b, "}\n")
+
+		fmt.Fprintf(&b, "\n// %s echoes the inputs next to the computed outputs\n", state)
+		fmt.Fprintf(&b, "type %s struct {\n\t%s\n\t%s\n}\n", state, args, outputs)
+
+		fmt.Fprintf(&b, "\n// new%s copies the inputs into an otherwise empty state\n", state)
+		fmt.Fprintf(&b, "func new%s(input %s) %s { return %s{%s: input} }\n", state, args, state, state, args)
+
+		fmt.Fprintf(&b, "\nfunc (s *%s) stamp(id, created string) { s.%s, s.%s = id, created }\n", state, s.ID, s.Created)
+		fmt.Fprintf(&b, "func (s *%s) identity() (string, string) { return s.%s, s.%s }\n", state, s.ID, s.Created)
+		fmt.Fprintf(&b, "func (s *%s) setVersion(version int64) { s.Version = version }\n", state)
+		fmt.Fprintf(&b, "func (s *%s) storedVersion() int64 { return s.Version }\n", state)
+
+		if len(s.Fields) > 0 {
+			fmt.Fprintf(&b, "\nfunc (args *%s) Annotate(a infer.Annotator) {\n", args)
+			for _, f := range s.Fields {
+				if f.Description != "" {
+					fmt.Fprintf(&b, "\ta.Describe(&args.%s, %q)\n", f.Name, f.Description)
+				}
+				if f.Default != "" {
+					fmt.Fprintf(&b, "\ta.SetDefault(&args.%s, %s)\n", f.Name, f.Default)
+				}
+			}
+			fmt.Fprintf(&b, "}\n")
+		}
+
+		if s.hasDeprecations() {
+			fmt.Fprintf(&b, "\n// deprecatedInputs maps each deprecated input to what to use instead\n")
+			fmt.Fprintf(&b, "func (*%s) deprecatedInputs() map[string]string {\n\treturn map[string]string{\n", args)
+			for _, f := range s.Fields {
+				if f.Deprecated != "" {
+					fmt.Fprintf(&b, "\t\t%q: %q,\n", f.Property, f.Deprecated)
+				}
+			}
+			fmt.Fprintf(&b, "\t}\n}\n")
+		}
+
+		if s.hasDefaults() {
+			fmt.Fprintf(&b, "\n// applyDefaults fills unset optional inputs with their schema defaults\n")
+			fmt.Fprintf(&b, "func (args *%s) applyDefaults() {\n", args)
+			for _, f := range s.Fields {
+				if f.Default != "" {
+					fmt.Fprintf(&b, "\tif args.%s == nil {\n\t\tv := %s\n\t\targs.%s = &v\n\t}\n", f.Name, f.Default, f.Name)
+				}
+			}
+			fmt.Fprintf(&b, "}\n")
+		}
+
+		fmt.Fprintf(&b, "\nfunc (state *%s) Annotate(a infer.Annotator) {\n", state)
+		if len(s.Fields) > 0 {
+			fmt.Fprintf(&b, "\tstate.%s.Annotate(a)\n", args)
+		}
+		for _, o := range s.Outputs {
+			if o.Description != "" {
+				fmt.Fprintf(&b, "\ta.Describe(&state.%s, %q)\n", o.Name, o.Description)
+			}
+		}
+		fmt.Fprintf(&b, "\ta.Describe(&state.Version, %q)\n", "Version of the stored registry record, used to detect concurrent changes")
+		fmt.Fprintf(&b, "}\n")
+	}
+
+	return format.Source(b.Bytes())
+}
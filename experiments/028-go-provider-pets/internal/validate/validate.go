@@ -0,0 +1,179 @@
+// Package validate checks resource inputs against `validate` struct tags.
+//
+// Rules are comma separated and apply to the field they annotate:
+//
+//	required   strings and slices must not be empty, pointers must be set
+//	min=N      numbers must be at least N, strings and slices that long
+//	max=N      numbers must be at most N, strings and slices no longer
+//	gt=N       numbers must be greater than N
+//	oneof=a|b  the value must be one of the listed options
+//	enum       the value must be one of those its type's Values method
+//	           lists, as infer.Enum types do for the schema
+//
+// Bounded floating point fields must also be finite: NaN would otherwise
+// slip past every comparison. Optional (pointer) fields are only checked
+// when they are set. Embedded structs are checked as part of their parent;
+// slices of structs are not descended into, so batch resources can report
+// per-item problems.
+package validate
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+// Struct checks v, a struct or pointer to one, and reports every violated
+// rule as a CheckFailure against the field's pulumi property name. It
+// panics on a malformed tag, which is a programming error.
+func Struct(v any) []p.CheckFailure {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validate: %T is not a struct", v))
+	}
+	var failures []p.CheckFailure
+	checkStruct(value, &failures)
+	return failures
+}
+
+func checkStruct(value reflect.Value, failures *[]p.CheckFailure) {
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			checkStruct(value.Field(i), failures)
+			continue
+		}
+
+		tag, ok := field.Tag.Lookup("validate")
+		if !ok || tag == "" {
+			continue
+		}
+		property := propertyName(field)
+		for _, rule := range strings.Split(tag, ",") {
+			if reason := checkRule(t.Name()+"."+field.Name, rule, value.Field(i)); reason != "" {
+				*failures = append(*failures, p.CheckFailure{
+					Property: property,
+					Reason:   property + " " + reason,
+				})
+			}
+		}
+	}
+}
+
+func propertyName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("pulumi"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// checkRule returns why value breaks rule, or "" when it holds.
+func checkRule(field, rule string, value reflect.Value) string {
+	name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			if name == "required" {
+				return "is required"
+			}
+			return ""
+		}
+		value = value.Elem()
+	}
+
+	switch name {
+	case "required":
+		switch value.Kind() {
+		case reflect.String:
+			if strings.TrimSpace(value.String()) == "" {
+				return "is required"
+			}
+		case reflect.Slice, reflect.Map:
+			if value.Len() == 0 {
+				return "is required"
+			}
+		}
+		return ""
+	case "min", "max", "gt":
+		bound, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			panic(fmt.Sprintf("validate: %s: bad bound in %q", field, rule))
+		}
+		return checkBound(field, name, bound, value)
+	case "oneof":
+		return oneOf(value, strings.Split(arg, "|"))
+	case "enum":
+		values := value.MethodByName("Values")
+		if !values.IsValid() {
+			panic(fmt.Sprintf("validate: %s: enum needs a type with a Values method", field))
+		}
+		listed := values.Call(nil)[0]
+		options := make([]string, listed.Len())
+		for i := range options {
+			options[i] = fmt.Sprint(listed.Index(i).FieldByName("Value").Interface())
+		}
+		return oneOf(value, options)
+	default:
+		panic(fmt.Sprintf("validate: %s: unknown rule %q", field, rule))
+	}
+}
+
+// oneOf returns why value isn't one of options, or "" when it is
+func oneOf(value reflect.Value, options []string) string {
+	got := fmt.Sprint(value.Interface())
+	for _, option := range options {
+		if got == option {
+			return ""
+		}
+	}
+	return "must be one of " + strings.Join(options, ", ")
+}
+
+func checkBound(field, name string, bound float64, value reflect.Value) string {
+	var n float64
+	measured := "" // strings and slices are bounded by length
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = value.Float()
+		// NaN compares false against every bound, so it would pass them all
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return "must be a finite number"
+		}
+	case reflect.String:
+		n, measured = float64(len([]rune(value.String()))), "characters"
+	case reflect.Slice, reflect.Map:
+		n, measured = float64(value.Len()), "items"
+	default:
+		panic(fmt.Sprintf("validate: %s: %s does not apply to %s", field, name, value.Kind()))
+	}
+
+	limit := strconv.FormatFloat(bound, 'f', -1, 64)
+	if measured != "" {
+		limit += " " + measured
+	}
+	switch {
+	case name == "min" && n < bound:
+		if measured != "" {
+			return "must be at least " + limit + " long"
+		}
+		return "must be at least " + limit
+	case name == "max" && n > bound:
+		if measured != "" {
+			return "must be at most " + limit + " long"
+		}
+		return "must be at most " + limit
+	case name == "gt" && n <= bound:
+		return "must be greater than " + limit
+	}
+	return ""
+}
@@ -0,0 +1,171 @@
+package validate
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+type embedded struct {
+	Token string `pulumi:"token" validate:"required"`
+}
+
+type size string
+
+func (size) Values() []infer.EnumValue[size] {
+	return []infer.EnumValue[size]{{Name: "Small", Value: "small"}, {Name: "Large", Value: "large"}}
+}
+
+type args struct {
+	Name   string   `pulumi:"name" validate:"required,max=5"`
+	Age    *int     `pulumi:"age,optional" validate:"min=0,max=30"`
+	Weight *float64 `pulumi:"weight,optional" validate:"gt=0"`
+	Kind   *string  `pulumi:"kind,optional" validate:"oneof=cat|dog"`
+	Size   *size    `pulumi:"size,optional" validate:"enum"`
+	Tags   []string `pulumi:"tags,optional" validate:"max=2"`
+	Owner  *string  `pulumi:"owner,optional" validate:"required"`
+	Notes  string   `pulumi:"notes"`
+	embedded
+}
+
+func TestStruct(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+	stringPtr := func(v string) *string { return &v }
+	valid := func() args {
+		return args{Name: "Rex", Owner: stringPtr("Sam"), embedded: embedded{Token: "t"}}
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*args)
+		want   []p.CheckFailure
+	}{
+		{name: "valid", mutate: func(*args) {}},
+		{
+			name:   "required string",
+			mutate: func(a *args) { a.Name = "  " },
+			want:   []p.CheckFailure{{Property: "name", Reason: "name is required"}},
+		},
+		{
+			name:   "string too long",
+			mutate: func(a *args) { a.Name = "Barkley" },
+			want:   []p.CheckFailure{{Property: "name", Reason: "name must be at most 5 characters long"}},
+		},
+		{
+			name:   "unset optional is skipped",
+			mutate: func(a *args) { a.Age, a.Weight, a.Kind = nil, nil, nil },
+		},
+		{
+			name:   "below minimum",
+			mutate: func(a *args) { a.Age = intPtr(-1) },
+			want:   []p.CheckFailure{{Property: "age", Reason: "age must be at least 0"}},
+		},
+		{
+			name:   "above maximum",
+			mutate: func(a *args) { a.Age = intPtr(31) },
+			want:   []p.CheckFailure{{Property: "age", Reason: "age must be at most 30"}},
+		},
+		{
+			name:   "bounds are inclusive",
+			mutate: func(a *args) { a.Age = intPtr(30) },
+		},
+		{
+			name:   "greater than is exclusive",
+			mutate: func(a *args) { a.Weight = floatPtr(0) },
+			want:   []p.CheckFailure{{Property: "weight", Reason: "weight must be greater than 0"}},
+		},
+		{
+			name:   "not a number",
+			mutate: func(a *args) { a.Weight = floatPtr(math.NaN()) },
+			want:   []p.CheckFailure{{Property: "weight", Reason: "weight must be a finite number"}},
+		},
+		{
+			name:   "infinite",
+			mutate: func(a *args) { a.Weight = floatPtr(math.Inf(1)) },
+			want:   []p.CheckFailure{{Property: "weight", Reason: "weight must be a finite number"}},
+		},
+		{
+			name:   "enum",
+			mutate: func(a *args) { a.Kind = stringPtr("ferret") },
+			want:   []p.CheckFailure{{Property: "kind", Reason: "kind must be one of cat, dog"}},
+		},
+		{
+			name:   "enum values",
+			mutate: func(a *args) { s := size("medium"); a.Size = &s },
+			want:   []p.CheckFailure{{Property: "size", Reason: "size must be one of small, large"}},
+		},
+		{
+			name:   "enum value listed",
+			mutate: func(a *args) { s := size("large"); a.Size = &s },
+		},
+		{
+			name:   "slice length",
+			mutate: func(a *args) { a.Tags = []string{"a", "b", "c"} },
+			want:   []p.CheckFailure{{Property: "tags", Reason: "tags must be at most 2 items long"}},
+		},
+		{
+			name:   "required pointer",
+			mutate: func(a *args) { a.Owner = nil },
+			want:   []p.CheckFailure{{Property: "owner", Reason: "owner is required"}},
+		},
+		{
+			name:   "embedded struct",
+			mutate: func(a *args) { a.Token = "" },
+			want:   []p.CheckFailure{{Property: "token", Reason: "token is required"}},
+		},
+		{
+			name:   "every failure is reported",
+			mutate: func(a *args) { a.Name = ""; a.Age = intPtr(99) },
+			want: []p.CheckFailure{
+				{Property: "name", Reason: "name is required"},
+				{Property: "age", Reason: "age must be at most 30"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := valid()
+			tt.mutate(&a)
+			if got := Struct(&a); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Struct = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructPanicsOnMalformedTags(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+	}{
+		{name: "unknown rule", v: &struct {
+			A string `validate:"shiny"`
+		}{}},
+		{name: "bad bound", v: &struct {
+			A int `validate:"min=lots"`
+		}{}},
+		{name: "enum without values", v: &struct {
+			A string `validate:"enum"`
+		}{A: "x"}},
+		{name: "bound on bool", v: &struct {
+			A bool `validate:"max=1"`
+		}{}},
+		{name: "not a struct", v: 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Struct did not panic")
+				}
+			}()
+			Struct(tt.v)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+// Command pulumi-resource-pets-household is the plugin for the household
+// component provider. Unlike the pets provider, it manages nothing itself:
+// its components are assembled from pets resources, which the engine
+// creates through the pets plugin as usual.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+
+	"github.com/aygp-dr/pulumi-pets-household/internal/household"
+)
+
+// version is stamped by the Makefile through -ldflags -X
+var version = "0.1.0"
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "--version" {
+		fmt.Printf("pulumi-resource-pets-household %s\n", version)
+		return
+	}
+
+	prov := infer.Provider(infer.Options{
+		Components: []infer.InferredComponent{
+			infer.Component[household.StarterKit, household.StarterKitArgs, *household.StarterKitState](),
+		},
+	})
+	if err := p.RunProvider("pets-household", version, prov); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+}
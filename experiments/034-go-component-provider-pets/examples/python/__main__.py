@@ -0,0 +1,13 @@
+import pulumi
+import pulumi_pets_household as household
+
+kit = household.household.StarterKit(
+    "bella",
+    dog_name="Bella",
+    breed="golden-retriever",
+    owner_name="Alex",
+    vet_name="Dr. Rivera",
+)
+
+pulumi.export("dog_id", kit.dog_id)
+pulumi.export("next_visit", kit.next_visit)
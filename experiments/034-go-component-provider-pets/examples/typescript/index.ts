@@ -0,0 +1,11 @@
+import * as household from "@pulumi/pets-household";
+
+const kit = new household.household.StarterKit("rex", {
+    dogName: "Rex",
+    breed: "beagle",
+    ownerName: "Sam",
+    walkMinutes: 30,
+});
+
+export const dogId = kit.dogId;
+export const nextVisit = kit.nextVisit;
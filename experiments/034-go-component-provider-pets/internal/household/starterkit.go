@@ -0,0 +1,122 @@
+// Package household holds components that bundle pets resources into the
+// setups people actually start with, so a program declares one resource
+// instead of wiring dog IDs between several.
+package household
+
+import (
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// Tokens of the pets provider resources the components are built from
+const (
+	dogToken   = "pets:index:Dog"
+	walkToken  = "pets:index:DogWalk"
+	visitToken = "pets:index:VeterinaryVisit"
+)
+
+// Defaults for optional StarterKit inputs
+const (
+	defaultVetName     = "Dr. Starter"
+	defaultClinicName  = "Neighborhood Vet"
+	defaultWalkMinutes = 20
+	defaultWalkMiles   = 1.0
+)
+
+// StarterKit registers a dog, books its first checkup and logs a first walk.
+type StarterKit struct{}
+
+type StarterKitArgs struct {
+	DogName     pulumi.StringInput `pulumi:"dogName"`
+	Breed       pulumi.StringInput `pulumi:"breed"`
+	OwnerName   pulumi.StringInput `pulumi:"ownerName"`
+	VetName     pulumi.StringInput `pulumi:"vetName,optional"`
+	ClinicName  pulumi.StringInput `pulumi:"clinicName,optional"`
+	WalkMinutes pulumi.IntInput    `pulumi:"walkMinutes,optional"`
+}
+
+// StarterKitState is the component as programs see it
+type StarterKitState struct {
+	pulumi.ResourceState
+
+	DogID     pulumi.StringOutput `pulumi:"dogId"`
+	VisitID   pulumi.StringOutput `pulumi:"visitId"`
+	WalkID    pulumi.StringOutput `pulumi:"walkId"`
+	NextVisit pulumi.StringOutput `pulumi:"nextVisit"`
+}
+
+// petsResource receives the outputs of a pets provider resource
+type petsResource struct {
+	pulumi.CustomResourceState
+
+	NextVisit pulumi.StringOutput `pulumi:"nextVisit"`
+}
+
+func (StarterKit) Construct(ctx *pulumi.Context, name, typ string, args StarterKitArgs, opts pulumi.ResourceOption) (*StarterKitState, error) {
+	state := &StarterKitState{}
+	if err := ctx.RegisterComponentResource(typ, name, state, opts); err != nil {
+		return nil, err
+	}
+	parent := pulumi.Parent(state)
+
+	dog := &petsResource{}
+	err := ctx.RegisterResource(dogToken, name+"-dog", pulumi.Map{
+		"name":      args.DogName,
+		"breed":     args.Breed,
+		"ownerName": args.OwnerName,
+	}, dog, parent)
+	if err != nil {
+		return nil, err
+	}
+	dogID := dog.ID().ToStringOutput()
+
+	visit := &petsResource{}
+	err = ctx.RegisterResource(visitToken, name+"-checkup", pulumi.Map{
+		"dogId":      dogID,
+		"visitType":  pulumi.String("checkup"),
+		"vetName":    orString(args.VetName, defaultVetName),
+		"clinicName": orString(args.ClinicName, defaultClinicName),
+	}, visit, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	walk := &petsResource{}
+	err = ctx.RegisterResource(walkToken, name+"-first-walk", pulumi.Map{
+		"dogId":    dogID,
+		"duration": orInt(args.WalkMinutes, defaultWalkMinutes),
+		"distance": pulumi.Float64(defaultWalkMiles),
+		"notes":    pulumi.String("First walk in the new home"),
+	}, walk, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	state.DogID = dogID
+	state.VisitID = visit.ID().ToStringOutput()
+	state.WalkID = walk.ID().ToStringOutput()
+	state.NextVisit = visit.NextVisit
+	if err := ctx.RegisterResourceOutputs(state, pulumi.Map{
+		"dogId":     state.DogID,
+		"visitId":   state.VisitID,
+		"walkId":    state.WalkID,
+		"nextVisit": state.NextVisit,
+	}); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// orString and orInt stand in a default for an input the program left out
+func orString(in pulumi.StringInput, fallback string) pulumi.StringInput {
+	if in == nil {
+		return pulumi.String(fallback)
+	}
+	return in
+}
+
+func orInt(in pulumi.IntInput, fallback int) pulumi.IntInput {
+	if in == nil {
+		return pulumi.Int(fallback)
+	}
+	return in
+}
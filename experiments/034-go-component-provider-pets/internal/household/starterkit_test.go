@@ -0,0 +1,99 @@
+package household
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// mocks stands in for the engine and the pets provider, recording what the
+// component registers.
+type mocks struct {
+	mu        sync.Mutex
+	resources map[string]pulumi.MockResourceArgs // by type token
+}
+
+func (m *mocks) NewResource(args pulumi.MockResourceArgs) (string, resource.PropertyMap, error) {
+	m.mu.Lock()
+	m.resources[args.TypeToken] = args
+	m.mu.Unlock()
+
+	outputs := args.Inputs.Copy()
+	if args.TypeToken == visitToken {
+		outputs["nextVisit"] = resource.NewStringProperty("2025-03-05")
+	}
+	return args.Name + "-id", outputs, nil
+}
+
+func (m *mocks) Call(args pulumi.MockCallArgs) (resource.PropertyMap, error) {
+	return args.Args, nil
+}
+
+func TestStarterKit(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        StarterKitArgs
+		wantVet     string
+		wantMinutes float64
+	}{
+		{
+			name:        "defaults",
+			args:        StarterKitArgs{DogName: pulumi.String("Rex"), Breed: pulumi.String("beagle"), OwnerName: pulumi.String("Sam")},
+			wantVet:     defaultVetName,
+			wantMinutes: defaultWalkMinutes,
+		},
+		{
+			name: "explicit",
+			args: StarterKitArgs{
+				DogName:     pulumi.String("Rex"),
+				Breed:       pulumi.String("beagle"),
+				OwnerName:   pulumi.String("Sam"),
+				VetName:     pulumi.String("Dr. Who"),
+				WalkMinutes: pulumi.Int(45),
+			},
+			wantVet:     "Dr. Who",
+			wantMinutes: 45,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mocks{resources: map[string]pulumi.MockResourceArgs{}}
+			var dogID, nextVisit string
+			err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+				kit, err := StarterKit{}.Construct(ctx, "home", "pets-household:household:StarterKit", tt.args, nil)
+				if err != nil {
+					return err
+				}
+				pulumi.All(kit.DogID, kit.NextVisit).ApplyT(func(all []any) error {
+					dogID, nextVisit = all[0].(string), all[1].(string)
+					return nil
+				})
+				return nil
+			}, pulumi.WithMocks("household", "test", m))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, token := range []string{dogToken, visitToken, walkToken} {
+				if _, ok := m.resources[token]; !ok {
+					t.Fatalf("%s was not registered", token)
+				}
+			}
+			if dogID != "home-dog-id" || nextVisit != "2025-03-05" {
+				t.Errorf("outputs = %q/%q, want the dog's ID and the visit's next date", dogID, nextVisit)
+			}
+
+			visit := m.resources[visitToken].Inputs
+			if visit["dogId"].StringValue() != "home-dog-id" || visit["vetName"].StringValue() != tt.wantVet {
+				t.Errorf("visit inputs = %v, want the kit's dog and vet %q", visit, tt.wantVet)
+			}
+			walk := m.resources[walkToken].Inputs
+			if walk["duration"].NumberValue() != tt.wantMinutes {
+				t.Errorf("walk duration = %v, want %v", walk["duration"], tt.wantMinutes)
+			}
+		})
+	}
+}